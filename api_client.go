@@ -1,7 +1,9 @@
 package cwe
 
 import (
+	"context"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -15,6 +17,10 @@ const (
 	// DefaultTimeout 是HTTP请求的默认超时时间
 	// 设置为30秒，适用于大多数API调用场景
 	DefaultTimeout = 30 * time.Second
+
+	// DefaultLanguage 是CWE内容的默认语言
+	// 官方API只提供英文内容，镜像未声明语言时也按此值记录
+	DefaultLanguage = "en"
 )
 
 // APIClient 表示CWE REST API客户端
@@ -28,6 +34,28 @@ type APIClient struct {
 	// baseURL 是API的基础URL
 	// 所有的API请求都将基于此URL构建
 	baseURL string
+
+	// negotiation 通过SetBaseURL设置不带版本路径的地址时延迟进行的API版本协商，为nil时直接使用baseURL
+	negotiation *apiVersionNegotiation
+
+	// language 是请求内容时首选的语言标签，如"zh-CN"
+	// 为空时不发送Accept-Language请求头
+	language string
+
+	// droppedFields 解码条目时丢弃的可选字段，零值表示保留所有字段
+	droppedFields FieldMask
+
+	// decodeMode 解码响应时对响应结构的容忍程度
+	decodeMode DecodeMode
+
+	// retainRaw 为true时在条目的RawData中保留原始JSON
+	retainRaw bool
+
+	// cache 响应缓存配置，通过NewCachedAPIClient启用，为nil时不使用缓存
+	cache *responseCacheState
+
+	// ctx 发送请求使用的上下文，为nil时使用context.Background()，见withContext
+	ctx context.Context
 }
 
 // NewAPIClient 创建一个新的API客户端
@@ -36,9 +64,12 @@ type APIClient struct {
 // 使用默认配置创建一个新的CWE API客户端实例。默认配置包括:
 // - 使用BaseURL常量作为API基础URL
 // - 使用30秒超时
-// - 默认使用10秒1个请求的速率限制
+// - 按目标主机使用DefaultRateLimiters中的速率限制器，官方API默认10秒1个请求
 // - 失败时最多重试3次，重试间隔1秒
 //
+// 参数:
+// - options: ...ClientOption - 可选的HTTP客户端配置，在默认配置之后应用，如WithUserAgent、WithBearerToken
+//
 // 返回值:
 // - *APIClient: 配置完成的API客户端实例
 //
@@ -52,13 +83,19 @@ type APIClient struct {
 //	}
 //
 // fmt.Printf("当前CWE版本: %s\n", version)
+//
+// // 通过需要认证的网关访问
+// client = cwe.NewAPIClient(cwe.WithUserAgent("scanner/1.2"), cwe.WithBearerToken(token))
 // ```
-func NewAPIClient() *APIClient {
+func NewAPIClient(options ...ClientOption) *APIClient {
+	options = append([]ClientOption{
+		WithMaxRetries(3),
+		WithRetryInterval(time.Second),
+		WithRateLimiterRegistry(DefaultRateLimiters),
+	}, options...)
+
 	return &APIClient{
-		client: NewHttpClient(
-			WithMaxRetries(3),
-			WithRetryInterval(time.Second),
-		),
+		client:  NewHttpClient(options...),
 		baseURL: BaseURL,
 	}
 }
@@ -72,7 +109,7 @@ func NewAPIClient() *APIClient {
 // 参数:
 // - baseURL: string - 自定义API基础URL。如为空字符串，则使用默认BaseURL
 // - timeout: time.Duration - HTTP请求超时时间。如<=0，则使用默认30秒
-// - rateLimiter: *HTTPRateLimiter - 可选的自定义速率限制器。使用nil将使用默认限制器
+// - rateLimiter: *HTTPRateLimiter - 可选的自定义速率限制器。使用nil时按baseURL的主机使用DefaultRateLimiters中的限制器
 //
 // 返回值:
 // - *APIClient: 根据指定配置创建的API客户端实例
@@ -101,11 +138,13 @@ func NewAPIClientWithOptions(baseURL string, timeout time.Duration, rateLimiter
 		WithRetryInterval(time.Second),
 	}
 
-	// 如果提供了自定义的速率限制器，将其添加到选项中
+	// 如果提供了自定义的速率限制器，将其添加到选项中，否则按主机使用DefaultRateLimiters中的限制器
 	if len(rateLimiter) > 0 && rateLimiter[0] != nil {
 		options = append(options, func(c *HTTPClient) {
 			c.SetRateLimiter(rateLimiter[0])
 		})
+	} else {
+		options = append(options, WithRateLimiterRegistry(DefaultRateLimiters))
 	}
 
 	// 创建自定义http.Client并传递给HTTPClient
@@ -174,6 +213,7 @@ func (c *APIClient) SetHTTPClient(client *HTTPClient) {
 //
 // 方法功能:
 // 提供直接访问API客户端内部使用的速率限制器的方法，便于调整速率限制设置
+// 按主机限速时返回基础URL所在主机的限制器，修改它会影响所有访问该主机的客户端
 //
 // 返回值:
 // - *HTTPRateLimiter: 速率限制器实例
@@ -187,7 +227,7 @@ func (c *APIClient) SetHTTPClient(client *HTTPClient) {
 // limiter.SetInterval(5 * time.Second)
 // ```
 func (c *APIClient) GetRateLimiter() *HTTPRateLimiter {
-	return c.client.GetRateLimiter()
+	return c.client.limiterFor(c.baseURL)
 }
 
 // SetRateLimiter 设置API客户端使用的速率限制器
@@ -221,3 +261,78 @@ func (c *APIClient) SetRateLimiter(limiter *HTTPRateLimiter) {
 func (c *APIClient) GetClient() *HTTPClient {
 	return c.client
 }
+
+// SetLanguage 设置请求CWE内容时首选的语言
+//
+// 方法功能:
+// 为之后的所有API请求设置Accept-Language请求头，以便使用提供本地化内容的镜像。
+// 请求头中总会附带较低权重的英文作为回退，不支持本地化的服务器仍会返回英文内容。
+// 获取到的条目会通过Language字段记录服务器实际返回的语言。
+//
+// 参数:
+// - lang: string - BCP 47语言标签，如"zh-CN"、"ja"；传入空字符串则恢复默认行为
+//
+// 使用示例:
+// ```go
+// client := cwe.NewAPIClientWithOptions("https://cwe-mirror.example.com/api/v1", 0)
+// client.SetLanguage("zh-CN")
+//
+// weakness, _ := client.GetWeakness("79")
+// fmt.Println(weakness.Language) // 镜像支持时输出: zh-CN
+// ```
+func (c *APIClient) SetLanguage(lang string) {
+	c.language = strings.TrimSpace(lang)
+}
+
+// GetLanguage 获取请求CWE内容时首选的语言
+func (c *APIClient) GetLanguage() string {
+	return c.language
+}
+
+// withContext 返回使用ctx发送请求的客户端副本，副本与原客户端共享HTTP客户端、缓存和其他配置
+// ctx被取消后，副本正在进行和之后的请求(包括限速等待和重试)都会立即失败
+func (c *APIClient) withContext(ctx context.Context) *APIClient {
+	bound := *c
+	bound.ctx = ctx
+	return &bound
+}
+
+// get 发送GET请求，附带客户端配置的语言偏好
+func (c *APIClient) get(url string) (*http.Response, error) {
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.language != "" {
+		req.Header.Set("Accept-Language", acceptLanguage(c.language))
+	}
+
+	return c.client.Do(req)
+}
+
+// acceptLanguage 构造带英文回退的Accept-Language请求头
+func acceptLanguage(lang string) string {
+	if strings.EqualFold(lang, DefaultLanguage) {
+		return DefaultLanguage
+	}
+	return lang + ", " + DefaultLanguage + ";q=0.5"
+}
+
+// responseLanguage 从响应的Content-Language头中获取内容语言
+// 服务器未声明语言时返回DefaultLanguage
+func responseLanguage(resp *http.Response) string {
+	lang := resp.Header.Get("Content-Language")
+	if i := strings.Index(lang, ","); i >= 0 {
+		lang = lang[:i]
+	}
+	lang = strings.TrimSpace(lang)
+	if lang == "" {
+		return DefaultLanguage
+	}
+	return lang
+}
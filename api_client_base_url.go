@@ -0,0 +1,220 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// 基础URL预设
+const (
+	// BaseURLMITRE 是MITRE官方CWE REST API的地址，与BaseURL相同
+	BaseURLMITRE = BaseURL
+
+	// BaseURLLocalMirror 是本机镜像的地址，与"cwe serve"的默认监听地址和路径前缀一致
+	BaseURLLocalMirror = "http://localhost:8080/api/v1"
+)
+
+// BaseURLPresets 预设名称到基础URL的映射，WithBaseURL和ResolveBaseURL按名称查找
+var BaseURLPresets = map[string]string{
+	"mitre": BaseURLMITRE,
+	"local": BaseURLLocalMirror,
+}
+
+// SupportedAPIVersions 本库支持的REST API版本路径，按优先级从高到低排列
+// 协商API版本时依次探测这些版本，REST API发布新版本且本库适配后在前面追加
+var SupportedAPIVersions = []string{"v1"}
+
+// ErrAPIVersionNotFound 表示在基础URL下没有找到可用的API版本路径
+var ErrAPIVersionNotFound = errors.New("没有找到可用的API版本")
+
+// apiVersionSegment 匹配基础URL末尾的版本路径，如"/v1"
+var apiVersionSegment = regexp.MustCompile(`/v\d+$`)
+
+// ResolveBaseURL 将预设名称解析为基础URL
+// name不区分大小写，匹配BaseURLPresets中的名称时返回对应的地址，否则去掉末尾的"/"后原样返回；
+// 空字符串返回BaseURL
+func ResolveBaseURL(name string) string {
+	name = strings.TrimSpace(name)
+	if preset, ok := BaseURLPresets[strings.ToLower(name)]; ok {
+		return preset
+	}
+	if name == "" {
+		return BaseURL
+	}
+	return strings.TrimRight(name, "/")
+}
+
+// apiVersionNegotiation 记录一次延迟进行的API版本协商，在第一次请求时执行
+type apiVersionNegotiation struct {
+	once sync.Once
+
+	// baseURL 协商得到的基础URL，协商失败时为配置的地址
+	baseURL string
+}
+
+// SetBaseURL 设置API的基础URL
+//
+// 方法功能:
+// baseURL可以是完整的API地址(如BaseURLMITRE)，也可以是BaseURLPresets中的预设名称。
+// 地址以版本路径(如"/v1")结尾时直接使用；否则在第一次请求时自动协商API版本，
+// 依次探测服务器上各版本的"/api/{版本}"、"/{版本}"路径和baseURL本身，使用第一个能返回CWE版本信息的地址。
+// 所有候选地址都不可用时按配置的地址请求，由具体请求返回错误。
+// 应在发起请求前调用，不能与请求并发调用。
+//
+// 参数:
+// - baseURL: string - 基础URL或预设名称，为空时使用BaseURL
+//
+// 使用示例:
+// ```go
+// client := cwe.NewAPIClient()
+//
+// // 使用本机镜像
+// client.SetBaseURL("local")
+//
+// // 只给出服务器地址，第一次请求时协商版本路径
+// client.SetBaseURL("https://cwe.example.com")
+// ```
+func (c *APIClient) SetBaseURL(baseURL string) {
+	c.baseURL = ResolveBaseURL(baseURL)
+	c.negotiation = nil
+	if apiVersionOf(c.baseURL) == "" {
+		c.negotiation = &apiVersionNegotiation{}
+	}
+}
+
+// GetBaseURL 获取请求使用的基础URL
+// 需要协商API版本时会先完成协商，返回协商得到的地址
+func (c *APIClient) GetBaseURL() string {
+	return c.apiBaseURL()
+}
+
+// NegotiateAPIVersion 探测服务器支持的API版本，并将基础URL切换到对应的地址
+//
+// 方法功能:
+// 在当前基础URL所在的服务器上依次探测候选地址的"/cwe/version"接口，使用第一个返回有效版本信息的地址。
+// 当前地址带有版本路径时优先探测它本身，再探测去掉版本路径后的其他版本。
+// 与SetBaseURL的自动协商不同，探测失败时返回错误，基础URL保持不变，可在服务器升级后重新调用。
+// 每个候选地址都会发送一个请求并受速率限制约束。
+//
+// 参数:
+// - versions: ...string - 要探测的版本路径，如"v2"，按优先级从高到低排列，为空时使用SupportedAPIVersions
+//
+// 返回值:
+// - string: 协商得到的版本路径，如"v1"；服务器不使用版本路径时为空字符串
+// - error: 所有候选地址都不可用时返回包装了ErrAPIVersionNotFound的错误
+//
+// 使用示例:
+// ```go
+// client := cwe.NewAPIClientWithOptions("https://cwe.example.com/api/v1", 0)
+// version, err := client.NegotiateAPIVersion("v2", "v1")
+//
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// fmt.Printf("使用API %s: %s\n", version, client.GetBaseURL())
+// ```
+func (c *APIClient) NegotiateAPIVersion(versions ...string) (string, error) {
+	baseURL, err := c.negotiateBaseURL(c.baseURL, versions)
+	if err != nil {
+		return "", err
+	}
+	c.baseURL = baseURL
+	c.negotiation = nil
+	return apiVersionOf(baseURL), nil
+}
+
+// apiBaseURL 返回构造请求使用的基础URL，需要协商时在第一次调用时完成协商
+func (c *APIClient) apiBaseURL() string {
+	negotiation := c.negotiation
+	if negotiation == nil {
+		return c.baseURL
+	}
+	negotiation.once.Do(func() {
+		negotiation.baseURL = c.baseURL
+		if baseURL, err := c.negotiateBaseURL(c.baseURL, nil); err == nil {
+			negotiation.baseURL = baseURL
+		}
+	})
+	return negotiation.baseURL
+}
+
+// negotiateBaseURL 依次探测候选地址，返回第一个能获取版本信息的地址
+func (c *APIClient) negotiateBaseURL(baseURL string, versions []string) (string, error) {
+	if len(versions) == 0 {
+		versions = SupportedAPIVersions
+	}
+
+	var lastErr error
+	for _, candidate := range apiVersionCandidates(baseURL, versions) {
+		if _, err := c.getVersion(candidate); err != nil {
+			lastErr = err
+			continue
+		}
+		return candidate, nil
+	}
+	return "", fmt.Errorf("%w: %s: %v", ErrAPIVersionNotFound, baseURL, lastErr)
+}
+
+// apiVersionCandidates 返回协商API版本时按顺序探测的地址
+// 带版本路径的地址先探测它本身；然后在去掉版本路径和"/api"的根地址下依次探测每个版本的"/api/{版本}"和"/{版本}"；
+// 不带版本路径的地址最后探测它本身
+func apiVersionCandidates(baseURL string, versions []string) []string {
+	baseURL = strings.TrimRight(baseURL, "/")
+	version := apiVersionOf(baseURL)
+	root := strings.TrimSuffix(strings.TrimSuffix(baseURL, "/"+version), "/api")
+
+	var candidates []string
+	seen := make(map[string]bool)
+	add := func(candidate string) {
+		if !seen[candidate] {
+			seen[candidate] = true
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	if version != "" {
+		add(baseURL)
+	}
+	for _, v := range versions {
+		v = strings.Trim(v, "/")
+		if v == "" {
+			continue
+		}
+		add(root + "/api/" + v)
+		add(root + "/" + v)
+	}
+	if version == "" {
+		add(baseURL)
+	}
+	return candidates
+}
+
+// apiVersionOf 返回基础URL路径末尾的版本，如"https://cwe-api.mitre.org/api/v1"返回"v1"，没有版本路径时返回空字符串
+func apiVersionOf(baseURL string) string {
+	parsed, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(apiVersionSegment.FindString(parsed.Path), "/")
+}
+
+// DataFetcherOption 数据获取器的配置选项，用于NewDataFetcher和NewDataFetcherWithClient
+type DataFetcherOption func(*DataFetcher)
+
+// WithBaseURL 设置数据获取器请求的API基础URL，支持BaseURLPresets中的预设名称，见APIClient.SetBaseURL
+//
+// 使用示例:
+// ```go
+// // 从本机的"cwe serve"获取数据
+// fetcher := cwe.NewDataFetcher(cwe.WithBaseURL("local"))
+// ```
+func WithBaseURL(baseURL string) DataFetcherOption {
+	return func(f *DataFetcher) {
+		f.client.SetBaseURL(baseURL)
+	}
+}
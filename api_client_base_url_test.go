@@ -0,0 +1,126 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newVersionedAPIServer 创建只在prefix下提供版本和弱点接口的测试服务器，并记录请求的路径
+func newVersionedAPIServer(prefix string, paths *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*paths = append(*paths, r.URL.Path)
+		switch r.URL.Path {
+		case prefix + "/cwe/version":
+			fmt.Fprint(w, `{"version": "4.14", "release_date": "2024-02-29"}`)
+		case prefix + "/cwe/weakness/79", prefix + "/cwe/weakness/CWE-79":
+			fmt.Fprint(w, `{"weaknesses": [{"id": "79", "name": "XSS"}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestResolveBaseURL(t *testing.T) {
+	tests := map[string]string{
+		"":                         BaseURL,
+		"mitre":                    BaseURLMITRE,
+		" Local ":                  BaseURLLocalMirror,
+		"https://example.com/api/": "https://example.com/api",
+	}
+	for input, want := range tests {
+		if got := ResolveBaseURL(input); got != want {
+			t.Errorf("ResolveBaseURL(%q) = %q，期望%q", input, got, want)
+		}
+	}
+}
+
+func TestAPIVersionCandidates(t *testing.T) {
+	tests := []struct {
+		baseURL  string
+		versions []string
+		want     []string
+	}{
+		{"https://example.com", []string{"v1"}, []string{"https://example.com/api/v1", "https://example.com/v1", "https://example.com"}},
+		{"https://example.com/api/", []string{"v2", "v1"}, []string{
+			"https://example.com/api/v2", "https://example.com/v2", "https://example.com/api/v1", "https://example.com/v1", "https://example.com/api"}},
+		{BaseURL, []string{"v2", "v1"}, []string{BaseURL, "https://cwe-api.mitre.org/api/v2", "https://cwe-api.mitre.org/v2", "https://cwe-api.mitre.org/v1"}},
+	}
+	for _, tt := range tests {
+		if got := apiVersionCandidates(tt.baseURL, tt.versions); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("apiVersionCandidates(%q, %v) = %v，期望%v", tt.baseURL, tt.versions, got, tt.want)
+		}
+	}
+	if version := apiVersionOf("http://localhost:8080/v12/"); version != "v12" {
+		t.Errorf("apiVersionOf = %q", version)
+	}
+}
+
+func TestWithBaseURLNegotiatesVersion(t *testing.T) {
+	var paths []string
+	server := newVersionedAPIServer("/api/v1", &paths)
+	defer server.Close()
+
+	client := NewAPIClientWithOptions("", DefaultTimeout, NewHTTPRateLimiter(0))
+	fetcher := NewDataFetcherWithClient(client, WithBaseURL(server.URL+"/"))
+
+	entry, err := fetcher.FetchWeakness("CWE-79")
+	if err != nil || entry.Name != "XSS" {
+		t.Fatalf("协商版本后应能获取弱点: %v, %v", entry, err)
+	}
+	if _, err := fetcher.FetchWeakness("CWE-79"); err != nil {
+		t.Fatalf("第二次获取失败: %v", err)
+	}
+	if baseURL := client.GetBaseURL(); baseURL != server.URL+"/api/v1" {
+		t.Errorf("协商得到的基础URL = %q", baseURL)
+	}
+	want := []string{"/api/v1/cwe/version", "/api/v1/cwe/weakness/CWE-79", "/api/v1/cwe/weakness/CWE-79"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("只应协商一次，请求路径为%v", paths)
+	}
+
+	// 带版本路径的地址不协商
+	paths = nil
+	client.SetBaseURL(server.URL + "/api/v1")
+	if _, err := client.GetWeakness("79"); err != nil || len(paths) != 1 {
+		t.Errorf("带版本路径时应直接请求: %v, %v", err, paths)
+	}
+}
+
+func TestNegotiateAPIVersion(t *testing.T) {
+	var paths []string
+	server := newVersionedAPIServer("/v2", &paths)
+	defer server.Close()
+
+	client := NewAPIClientWithOptions(server.URL+"/api/v1", DefaultTimeout, NewHTTPRateLimiter(0))
+	if _, err := client.NegotiateAPIVersion(); !errors.Is(err, ErrAPIVersionNotFound) {
+		t.Fatalf("不支持的版本应返回ErrAPIVersionNotFound，实际: %v", err)
+	}
+	if baseURL := client.GetBaseURL(); baseURL != server.URL+"/api/v1" {
+		t.Errorf("协商失败时不应修改基础URL，实际为%q", baseURL)
+	}
+
+	version, err := client.NegotiateAPIVersion("v2", "v1")
+	if err != nil || version != "v2" {
+		t.Fatalf("NegotiateAPIVersion = %q, %v", version, err)
+	}
+	if baseURL := client.GetBaseURL(); baseURL != server.URL+"/v2" {
+		t.Errorf("协商得到的基础URL = %q", baseURL)
+	}
+
+	// 自动协商失败时按配置的地址请求
+	client.SetBaseURL(server.URL + "/mirror")
+	if _, err := client.GetWeakness("79"); err == nil {
+		t.Error("协商失败时请求应返回错误")
+	}
+	if baseURL := client.GetBaseURL(); baseURL != server.URL+"/mirror" {
+		t.Errorf("自动协商失败时应保留配置的地址，实际为%q", baseURL)
+	}
+	if last := paths[len(paths)-1]; !strings.HasPrefix(last, "/mirror/cwe/weakness/") {
+		t.Errorf("最后一个请求应使用配置的地址，实际为%q", last)
+	}
+}
@@ -0,0 +1,325 @@
+package cwe
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ResponseCache 是APIClient缓存API响应使用的存储后端
+//
+// 键由CWE版本、语言、条目类型和ID组成，如"4.16/en/weakness/CWE-79"，值为不透明的字节数据。
+// 实现必须是并发安全的。Set返回的错误不会影响API调用的结果，只会导致该响应不被缓存。
+type ResponseCache interface {
+	// Get 返回键对应的值，不存在时返回false
+	Get(key string) ([]byte, bool)
+
+	// Set 保存键值对
+	Set(key string, value []byte) error
+}
+
+// 缓存的条目类型
+const (
+	cacheKindWeakness = "weakness"
+	cacheKindCategory = "category"
+	cacheKindView     = "view"
+	cacheKindChildren = "children"
+)
+
+// responseCacheState 保存APIClient的缓存配置
+// 以指针形式保存在APIClient中，使NewCachedAPIClient复制客户端时不会复制锁
+type responseCacheState struct {
+	cache ResponseCache
+
+	mutex   sync.Mutex
+	version string
+}
+
+// cachedResponse 是写入缓存的值，保留响应体和内容语言
+type cachedResponse struct {
+	Language string          `json:"language"`
+	Body     json.RawMessage `json:"body"`
+}
+
+// NewCachedAPIClient 返回使用缓存的API客户端
+//
+// 方法功能:
+// 复制client的配置(HTTP客户端、基础URL、语言和字段掩码)，返回一个在GetWeakness、GetCategory、
+// GetView和GetChildren之前先查询缓存的新客户端，原客户端不受影响。
+// 缓存按CWE版本区分，版本默认在第一次查询缓存时通过GetVersion获取一次，也可以用SetCacheVersion指定。
+// 只有成功解析的响应会被缓存，错误响应每次都会重新请求。
+//
+// 配合DataFetcher使用时，重复调用BuildCWETreeWithView几乎不再产生网络请求。
+//
+// 参数:
+// - client: *APIClient - 要包装的客户端
+// - cache: ResponseCache - 缓存后端，如NewMemoryCache或NewFileCache的返回值
+//
+// 返回值:
+// - *APIClient: 使用缓存的客户端
+//
+// 使用示例:
+// ```go
+// cache, err := cwe.NewFileCache(filepath.Join(os.TempDir(), "cwe-cache"))
+//
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// client := cwe.NewCachedAPIClient(cwe.NewAPIClient(), cache)
+// fetcher := cwe.NewDataFetcherWithClient(client)
+//
+// // 第二次构建直接从缓存读取
+// registry, err := fetcher.BuildCWETreeWithView("1000")
+// ```
+func NewCachedAPIClient(client *APIClient, cache ResponseCache) *APIClient {
+	cached := *client
+	cached.cache = &responseCacheState{cache: cache}
+	return &cached
+}
+
+// SetCacheVersion 指定缓存使用的CWE版本，避免第一次查询缓存时调用GetVersion
+// 离线使用缓存时必须设置；未通过NewCachedAPIClient启用缓存时不做任何操作
+func (c *APIClient) SetCacheVersion(version string) {
+	if c.cache == nil {
+		return
+	}
+	c.cache.mutex.Lock()
+	defer c.cache.mutex.Unlock()
+	c.cache.version = version
+}
+
+// cacheKey 返回条目的缓存键，无法确定CWE版本时返回false
+func (c *APIClient) cacheKey(kind, id string) (string, bool) {
+	if c.cache == nil {
+		return "", false
+	}
+
+	c.cache.mutex.Lock()
+	version := c.cache.version
+	if version == "" {
+		if resp, err := c.GetVersion(); err == nil && resp.Version != "" {
+			version = resp.Version
+			c.cache.version = version
+		}
+	}
+	c.cache.mutex.Unlock()
+	if version == "" {
+		return "", false
+	}
+
+	language := c.language
+	if language == "" {
+		language = DefaultLanguage
+	}
+	return strings.Join([]string{version, language, kind, id}, "/"), true
+}
+
+// fetchEntry 获取单个条目的响应体和内容语言，启用缓存时优先读取缓存
+//
+// 返回值中的bool表示数据是否来自缓存；来自网络的数据需要在解析成功后通过storeEntry写入缓存。
+// failure是网络请求失败时错误信息的前缀，如"获取弱点信息失败"。
+func (c *APIClient) fetchEntry(kind, id, url, failure string) ([]byte, string, bool, error) {
+	if key, ok := c.cacheKey(kind, id); ok {
+		if value, exists := c.cache.cache.Get(key); exists {
+			var cached cachedResponse
+			if err := json.Unmarshal(value, &cached); err == nil {
+				c.observeCache(kind, true)
+				return cached.Body, cached.Language, true, nil
+			}
+		}
+		c.observeCache(kind, false)
+	}
+
+	resp, err := c.get(url)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("%s: %w", failure, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", false, &apiStatusError{statusCode: resp.StatusCode}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("读取响应体失败: %w", err)
+	}
+
+	return body, responseLanguage(resp), false, nil
+}
+
+// apiStatusError 表示API返回了非200状态码，404时可通过errors.Is(err, ErrNotFound)判断
+type apiStatusError struct {
+	statusCode int
+}
+
+func (e *apiStatusError) Error() string {
+	return fmt.Sprintf("API请求失败，状态码: %d", e.statusCode)
+}
+
+func (e *apiStatusError) Is(target error) bool {
+	return target == ErrNotFound && e.statusCode == http.StatusNotFound
+}
+
+// observeCache 向HTTP客户端的指标记录器报告一次缓存查询
+func (c *APIClient) observeCache(kind string, hit bool) {
+	if metrics := c.client.GetMetrics(); metrics != nil {
+		metrics.ObserveCache(kind, hit)
+	}
+}
+
+// storeEntry 将解析成功的响应写入缓存，写入失败时忽略
+func (c *APIClient) storeEntry(kind, id string, body []byte, language string) {
+	key, ok := c.cacheKey(kind, id)
+	if !ok {
+		return
+	}
+	value, err := json.Marshal(cachedResponse{Language: language, Body: body})
+	if err != nil {
+		return
+	}
+	_ = c.cache.cache.Set(key, value)
+}
+
+// MemoryCache 是基于LRU淘汰策略的内存缓存
+// MemoryCache是并发安全的
+type MemoryCache struct {
+	mutex    sync.Mutex
+	capacity int
+	order    *list.List
+	items    map[string]*list.Element
+}
+
+// memoryCacheItem 是MemoryCache链表中的元素
+type memoryCacheItem struct {
+	key   string
+	value []byte
+}
+
+// NewMemoryCache 创建最多保存capacity个条目的内存缓存
+// 超出容量时淘汰最久未使用的条目；capacity<=0表示不限制容量
+func NewMemoryCache(capacity int) *MemoryCache {
+	return &MemoryCache{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get 返回键对应的值，并将其标记为最近使用
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	element, exists := m.items[key]
+	if !exists {
+		return nil, false
+	}
+	m.order.MoveToFront(element)
+	return element.Value.(*memoryCacheItem).value, true
+}
+
+// Set 保存键值对，必要时淘汰最久未使用的条目
+func (m *MemoryCache) Set(key string, value []byte) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if element, exists := m.items[key]; exists {
+		element.Value.(*memoryCacheItem).value = value
+		m.order.MoveToFront(element)
+		return nil
+	}
+
+	m.items[key] = m.order.PushFront(&memoryCacheItem{key: key, value: value})
+	if m.capacity > 0 && m.order.Len() > m.capacity {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.items, oldest.Value.(*memoryCacheItem).key)
+	}
+	return nil
+}
+
+// Len 返回缓存中的条目数量
+func (m *MemoryCache) Len() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.order.Len()
+}
+
+// FileCache 是将每个条目保存为一个JSON文件的磁盘缓存
+//
+// 文件路径与缓存键对应，如键"4.16/en/weakness/CWE-79"保存为<dir>/4.16/en/weakness/CWE-79.json，
+// 可以直接查看或删除某个版本的目录。写入时先写临时文件再重命名，多个进程共享目录也不会读到不完整的文件。
+type FileCache struct {
+	dir string
+}
+
+// NewFileCache 创建使用dir目录的磁盘缓存，目录不存在时自动创建
+func NewFileCache(dir string) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建缓存目录失败: %w", err)
+	}
+	return &FileCache{dir: dir}, nil
+}
+
+// Get 读取键对应的文件，文件不存在或无法读取时返回false
+func (f *FileCache) Get(key string) ([]byte, bool) {
+	data, err := os.ReadFile(f.path(key))
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// Set 将值写入键对应的文件
+func (f *FileCache) Set(key string, value []byte) error {
+	return writeFileAtomic(f.path(key), value)
+}
+
+// writeFileAtomic 先写入同目录下的临时文件再重命名，避免其他进程读到不完整的文件
+// 目录不存在时自动创建
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// path 返回键对应的文件路径，键的每一段中除字母、数字、'.'、'-'和'_'以外的字符都替换为'_'
+func (f *FileCache) path(key string) string {
+	parts := strings.Split(key, "/")
+	for i, part := range parts {
+		part = strings.Map(func(r rune) rune {
+			if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_' {
+				return r
+			}
+			return '_'
+		}, part)
+		if part == "" || part == "." || part == ".." {
+			part = "_" + part
+		}
+		parts[i] = part
+	}
+	return filepath.Join(f.dir, filepath.Join(parts...)) + ".json"
+}
@@ -0,0 +1,205 @@
+package cwe
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// setupCacheTestServer 创建视图1000 -> CWE-79的测试服务器，并统计收到的请求数
+func setupCacheTestServer(requests *int32) *httptest.Server {
+	mux := http.NewServeMux()
+	writeJSON := func(w http.ResponseWriter, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Language", "zh-CN")
+		json.NewEncoder(w).Encode(v)
+	}
+
+	mux.HandleFunc("/cwe/version", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{"version": "4.16"})
+	})
+	mux.HandleFunc("/cwe/view/CWE-1000", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"views": []map[string]interface{}{{"id": "1000", "name": "Research Concepts"}},
+		})
+	})
+	mux.HandleFunc("/cwe/CWE-1000/children", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, []string{"79"})
+	})
+	weakness := func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"weaknesses": []map[string]interface{}{{"id": "79", "name": "Cross-site Scripting"}},
+		})
+	}
+	mux.HandleFunc("/cwe/weakness/79", weakness)
+	mux.HandleFunc("/cwe/weakness/CWE-79", weakness)
+	mux.HandleFunc("/cwe/CWE-79/children", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, []string{})
+	})
+	mux.HandleFunc("/cwe/weakness/500", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		mux.ServeHTTP(w, r)
+	}))
+}
+
+// newCacheTestClient 创建不限速、快速重试的测试客户端
+func newCacheTestClient(url string) *APIClient {
+	client := NewAPIClientWithOptions(url, DefaultTimeout, NewHTTPRateLimiter(0))
+	client.GetHTTPClient().SetRetryDelay(time.Millisecond)
+	return client
+}
+
+// TestCachedAPIClientBuildTree 测试重复构建树时不再发送请求
+func TestCachedAPIClientBuildTree(t *testing.T) {
+	var requests int32
+	server := setupCacheTestServer(&requests)
+	defer server.Close()
+
+	base := newCacheTestClient(server.URL)
+	client := NewCachedAPIClient(base, NewMemoryCache(0))
+	if base.cache != nil {
+		t.Fatal("NewCachedAPIClient不应修改原客户端")
+	}
+	fetcher := NewDataFetcherWithClient(client)
+
+	first, err := fetcher.BuildCWETreeWithView("1000")
+	if err != nil {
+		t.Fatalf("第一次构建失败: %v", err)
+	}
+	firstRequests := atomic.LoadInt32(&requests)
+	if firstRequests == 0 {
+		t.Fatal("第一次构建应发送请求")
+	}
+
+	second, err := fetcher.BuildCWETreeWithView("1000")
+	if err != nil {
+		t.Fatalf("第二次构建失败: %v", err)
+	}
+	if extra := atomic.LoadInt32(&requests) - firstRequests; extra != 0 {
+		t.Errorf("第二次构建应完全使用缓存，但发送了 %d 个请求", extra)
+	}
+
+	if len(second.Entries) != len(first.Entries) || second.Entries["CWE-79"] == nil {
+		t.Errorf("缓存构建的树与原始结果不一致: %d vs %d", len(second.Entries), len(first.Entries))
+	}
+	if second.Entries["CWE-79"].Language != "zh-CN" {
+		t.Errorf("缓存应保留内容语言，但得到 %q", second.Entries["CWE-79"].Language)
+	}
+}
+
+// TestCachedAPIClientFileCache 测试磁盘缓存在客户端之间共享并按版本区分
+func TestCachedAPIClientFileCache(t *testing.T) {
+	var requests int32
+	server := setupCacheTestServer(&requests)
+	defer server.Close()
+
+	dir := t.TempDir()
+	cache, err := NewFileCache(dir)
+	if err != nil {
+		t.Fatalf("NewFileCache失败: %v", err)
+	}
+
+	client := NewCachedAPIClient(newCacheTestClient(server.URL), cache)
+	client.SetCacheVersion("4.16")
+	if _, err := client.GetWeakness("79"); err != nil {
+		t.Fatalf("GetWeakness失败: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "4.16", "en", "weakness", "CWE-79.json")); err != nil {
+		t.Fatalf("缓存文件未写入: %v", err)
+	}
+
+	// 新的客户端和缓存实例读取同一目录
+	reopened, _ := NewFileCache(dir)
+	offline := NewCachedAPIClient(newCacheTestClient("http://127.0.0.1:1"), reopened)
+	offline.SetCacheVersion("4.16")
+	weakness, err := offline.GetWeakness("CWE-79")
+	if err != nil {
+		t.Fatalf("离线读取缓存失败: %v", err)
+	}
+	if weakness.Name != "Cross-site Scripting" {
+		t.Errorf("缓存内容不正确: %s", weakness.Name)
+	}
+
+	// 版本不同时不使用旧缓存
+	before := atomic.LoadInt32(&requests)
+	newer := NewCachedAPIClient(newCacheTestClient(server.URL), cache)
+	newer.SetCacheVersion("4.17")
+	if _, err := newer.GetWeakness("79"); err != nil {
+		t.Fatalf("GetWeakness失败: %v", err)
+	}
+	if atomic.LoadInt32(&requests) == before {
+		t.Error("版本不同时应重新请求")
+	}
+}
+
+// TestCachedAPIClientErrorsNotCached 测试错误响应不会被缓存
+func TestCachedAPIClientErrorsNotCached(t *testing.T) {
+	var requests int32
+	server := setupCacheTestServer(&requests)
+	defer server.Close()
+
+	cache := NewMemoryCache(0)
+	client := NewCachedAPIClient(newCacheTestClient(server.URL), cache)
+	client.SetCacheVersion("4.16")
+
+	if _, err := client.GetWeakness("500"); err == nil {
+		t.Fatal("服务器错误时应返回错误")
+	}
+	first := atomic.LoadInt32(&requests)
+	if _, err := client.GetWeakness("500"); err == nil {
+		t.Fatal("服务器错误时应返回错误")
+	}
+	if got := atomic.LoadInt32(&requests) - first; got != first {
+		t.Errorf("错误响应不应被缓存，期望再发送%d个请求，但得到 %d 个", first, got)
+	}
+	if cache.Len() != 0 {
+		t.Errorf("缓存中不应有条目，但有 %d 个", cache.Len())
+	}
+}
+
+// TestMemoryCacheLRU 测试内存缓存按最近使用顺序淘汰
+func TestMemoryCacheLRU(t *testing.T) {
+	cache := NewMemoryCache(2)
+	cache.Set("a", []byte("1"))
+	cache.Set("b", []byte("2"))
+	cache.Get("a")
+	cache.Set("c", []byte("3"))
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("最久未使用的b应被淘汰")
+	}
+	if value, ok := cache.Get("a"); !ok || string(value) != "1" {
+		t.Error("最近使用的a不应被淘汰")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("期望2个条目，但得到 %d 个", cache.Len())
+	}
+
+	cache.Set("a", []byte("updated"))
+	if value, _ := cache.Get("a"); string(value) != "updated" {
+		t.Errorf("Set应更新已有的值，但得到 %s", value)
+	}
+}
+
+// TestFileCachePath 测试缓存键中的特殊字符不会逃出缓存目录
+func TestFileCachePath(t *testing.T) {
+	cache := &FileCache{dir: "/cache"}
+	cases := map[string]string{
+		"4.16/en/children/CWE-79@CWE-1000": filepath.Join("/cache", "4.16", "en", "children", "CWE-79_CWE-1000.json"),
+		"../../etc/passwd":                 filepath.Join("/cache", "_..", "_..", "etc", "passwd.json"),
+	}
+	for key, expected := range cases {
+		if got := cache.path(key); got != expected {
+			t.Errorf("path(%q) = %s，期望 %s", key, got, expected)
+		}
+	}
+}
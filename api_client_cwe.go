@@ -1,7 +1,6 @@
 package cwe
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -77,9 +76,9 @@ func (c *APIClient) GetCWEs(ids []string) (map[string]*CWEWeakness, error) {
 	}
 
 	idsStr := strings.Join(ids, ",")
-	url := fmt.Sprintf("%s/cwe/%s", c.baseURL, idsStr)
+	url := fmt.Sprintf("%s/cwe/%s", c.apiBaseURL(), idsStr)
 
-	resp, err := c.client.Get(context.Background(), url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, fmt.Errorf("获取CWE信息失败: %w", err)
 	}
@@ -94,8 +93,15 @@ func (c *APIClient) GetCWEs(ids []string) (map[string]*CWEWeakness, error) {
 		return nil, fmt.Errorf("读取响应体失败: %w", err)
 	}
 
+	lang := responseLanguage(resp)
+
 	var cwesResp CWEsResponse
-	if err := json.Unmarshal(body, &cwesResp); err != nil {
+	if err := c.decodeResponse(body, &cwesResp); err != nil {
+		// 严格模式下不猜测响应格式
+		if c.decodeMode == DecodeStrict {
+			return nil, fmt.Errorf("解析JSON响应失败: %w", err)
+		}
+
 		// 如果解析为标准响应格式失败，尝试解析为原始映射
 		var rawResult map[string]interface{}
 		if jsonErr := json.Unmarshal(body, &rawResult); jsonErr != nil {
@@ -107,8 +113,9 @@ func (c *APIClient) GetCWEs(ids []string) (map[string]*CWEWeakness, error) {
 		for id, data := range rawResult {
 			if dataMap, ok := data.(map[string]interface{}); ok {
 				cwe := &CWEWeakness{
-					ID:      id,
-					RawData: dataMap,
+					ID:       id,
+					Language: lang,
+					RawData:  dataMap,
 				}
 
 				// 尝试获取基本字段
@@ -133,6 +140,16 @@ func (c *APIClient) GetCWEs(ids []string) (map[string]*CWEWeakness, error) {
 
 	// 使用标准格式的响应
 	if cwesResp.CWEs != nil {
+		raws := c.rawEntryMap(body, "cwes")
+		for id, cwe := range cwesResp.CWEs {
+			if cwe != nil {
+				cwe.Language = lang
+				if raw, ok := raws[id]; ok {
+					cwe.raw = raw
+					cwe.RawData = decodeRaw(raw)
+				}
+			}
+		}
 		return cwesResp.CWEs, nil
 	}
 
@@ -155,7 +172,7 @@ func (c *APIClient) GetCWEs(ids []string) (map[string]*CWEWeakness, error) {
 //
 // 错误处理:
 // - 网络连接失败: 返回"获取弱点信息失败: <原始错误>"
-// - API返回非200状态码: 返回"API请求失败，状态码: <状态码>"
+// - API返回非200状态码: 返回"API请求失败，状态码: <状态码>"，404时可通过errors.Is(err, ErrNotFound)判断
 // - 响应解析失败: 返回"解析JSON响应失败: <原始错误>"
 // - 响应中缺少ID字段: 返回"响应中缺少ID字段"
 //
@@ -192,25 +209,20 @@ func (c *APIClient) GetCWEs(ids []string) (map[string]*CWEWeakness, error) {
 // - API文档: https://github.com/CWE-CAPEC/REST-API-wg/blob/main/Quick%20Start.md
 // - 相关方法: GetCWEs(), GetCategory(), GetView()
 func (c *APIClient) GetWeakness(id string) (*CWEWeakness, error) {
-	url := fmt.Sprintf("%s/cwe/weakness/%s", c.baseURL, id)
+	url := fmt.Sprintf("%s/cwe/weakness/%s", c.apiBaseURL(), id)
 
-	resp, err := c.client.Get(context.Background(), url)
+	body, language, cached, err := c.fetchEntry(cacheKindWeakness, normalizeEntryID(id), url, "获取弱点信息失败")
 	if err != nil {
-		return nil, fmt.Errorf("获取弱点信息失败: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API请求失败，状态码: %d", resp.StatusCode)
+		return nil, err
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body, err = c.unwrapEntries(body, "weaknesses")
 	if err != nil {
-		return nil, fmt.Errorf("读取响应体失败: %w", err)
+		return nil, fmt.Errorf("解析JSON响应失败: %w", err)
 	}
 
 	var weaknessResp WeaknessResponse
-	if err := json.Unmarshal(body, &weaknessResp); err != nil {
+	if err := c.decodeResponse(body, &weaknessResp); err != nil {
 		return nil, fmt.Errorf("解析JSON响应失败: %w", err)
 	}
 
@@ -227,6 +239,15 @@ func (c *APIClient) GetWeakness(id string) (*CWEWeakness, error) {
 		return nil, fmt.Errorf("响应中缺少ID字段")
 	}
 
+	weakness.Language = language
+	if raws := c.rawEntries(body, "weaknesses"); len(raws) > 0 {
+		weakness.raw = raws[0]
+		weakness.RawData = decodeRaw(raws[0])
+	}
+	if !cached {
+		c.storeEntry(cacheKindWeakness, normalizeEntryID(id), body, language)
+	}
+
 	return weakness, nil
 }
 
@@ -246,7 +267,7 @@ func (c *APIClient) GetWeakness(id string) (*CWEWeakness, error) {
 //
 // 错误处理:
 // - 网络连接失败: 返回"获取类别信息失败: <原始错误>"
-// - API返回非200状态码: 返回"API请求失败，状态码: <状态码>"
+// - API返回非200状态码: 返回"API请求失败，状态码: <状态码>"，404时可通过errors.Is(err, ErrNotFound)判断
 // - 响应解析失败: 返回"解析JSON响应失败: <原始错误>"
 // - 响应中缺少ID字段: 返回"响应中缺少ID字段"
 //
@@ -282,25 +303,20 @@ func (c *APIClient) GetWeakness(id string) (*CWEWeakness, error) {
 // - API文档: https://github.com/CWE-CAPEC/REST-API-wg/blob/main/Quick%20Start.md
 // - 相关方法: GetCWEs(), GetWeakness(), GetView()
 func (c *APIClient) GetCategory(id string) (*CWECategory, error) {
-	url := fmt.Sprintf("%s/cwe/category/%s", c.baseURL, id)
+	url := fmt.Sprintf("%s/cwe/category/%s", c.apiBaseURL(), id)
 
-	resp, err := c.client.Get(context.Background(), url)
+	body, language, cached, err := c.fetchEntry(cacheKindCategory, normalizeEntryID(id), url, "获取类别信息失败")
 	if err != nil {
-		return nil, fmt.Errorf("获取类别信息失败: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API请求失败，状态码: %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
+	body, err = c.unwrapEntries(body, "categories")
 	if err != nil {
-		return nil, fmt.Errorf("读取响应体失败: %w", err)
+		return nil, fmt.Errorf("解析JSON响应失败: %w", err)
 	}
 
 	var categoryResp CategoryResponse
-	if err := json.Unmarshal(body, &categoryResp); err != nil {
+	if err := c.decodeResponse(body, &categoryResp); err != nil {
 		return nil, fmt.Errorf("解析JSON响应失败: %w", err)
 	}
 
@@ -317,6 +333,15 @@ func (c *APIClient) GetCategory(id string) (*CWECategory, error) {
 		return nil, fmt.Errorf("响应中缺少ID字段")
 	}
 
+	category.Language = language
+	if raws := c.rawEntries(body, "categories"); len(raws) > 0 {
+		category.raw = raws[0]
+		category.RawData = decodeRaw(raws[0])
+	}
+	if !cached {
+		c.storeEntry(cacheKindCategory, normalizeEntryID(id), body, language)
+	}
+
 	return category, nil
 }
 
@@ -336,7 +361,7 @@ func (c *APIClient) GetCategory(id string) (*CWECategory, error) {
 //
 // 错误处理:
 // - 网络连接失败: 返回"获取视图信息失败: <原始错误>"
-// - API返回非200状态码: 返回"API请求失败，状态码: <状态码>"
+// - API返回非200状态码: 返回"API请求失败，状态码: <状态码>"，404时可通过errors.Is(err, ErrNotFound)判断
 // - 响应解析失败: 返回"解析JSON响应失败: <原始错误>"
 // - 响应中缺少ID字段: 返回"响应中缺少ID字段"
 //
@@ -372,25 +397,20 @@ func (c *APIClient) GetCategory(id string) (*CWECategory, error) {
 // - API文档: https://github.com/CWE-CAPEC/REST-API-wg/blob/main/Quick%20Start.md
 // - 相关方法: GetCWEs(), GetWeakness(), GetCategory()
 func (c *APIClient) GetView(id string) (*CWEView, error) {
-	url := fmt.Sprintf("%s/cwe/view/%s", c.baseURL, id)
+	url := fmt.Sprintf("%s/cwe/view/%s", c.apiBaseURL(), id)
 
-	resp, err := c.client.Get(context.Background(), url)
+	body, language, cached, err := c.fetchEntry(cacheKindView, normalizeEntryID(id), url, "获取视图信息失败")
 	if err != nil {
-		return nil, fmt.Errorf("获取视图信息失败: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API请求失败，状态码: %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
+	body, err = c.unwrapEntries(body, "views")
 	if err != nil {
-		return nil, fmt.Errorf("读取响应体失败: %w", err)
+		return nil, fmt.Errorf("解析JSON响应失败: %w", err)
 	}
 
 	var viewResp ViewResponse
-	if err := json.Unmarshal(body, &viewResp); err != nil {
+	if err := c.decodeResponse(body, &viewResp); err != nil {
 		return nil, fmt.Errorf("解析JSON响应失败: %w", err)
 	}
 
@@ -407,5 +427,14 @@ func (c *APIClient) GetView(id string) (*CWEView, error) {
 		return nil, fmt.Errorf("响应中缺少ID字段")
 	}
 
+	view.Language = language
+	if raws := c.rawEntries(body, "views"); len(raws) > 0 {
+		view.raw = raws[0]
+		view.RawData = decodeRaw(raws[0])
+	}
+	if !cached {
+		c.storeEntry(cacheKindView, normalizeEntryID(id), body, language)
+	}
+
 	return view, nil
 }
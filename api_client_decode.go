@@ -0,0 +1,209 @@
+package cwe
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DecodeMode 表示解码API响应时对响应结构的容忍程度
+type DecodeMode int
+
+const (
+	// DecodeDefault 忽略模型中没有的字段，字段类型不匹配时返回*DecodeError，这是客户端的默认行为
+	DecodeDefault DecodeMode = iota
+
+	// DecodeStrict 在DecodeDefault的基础上，模型中没有的字段和缺少条目数组(如"weaknesses")都返回*DecodeError，
+	// 适用于在CI中尽早发现API结构变化，避免数据被静默丢弃
+	DecodeStrict
+
+	// DecodeLenient 跳过类型不匹配的字段并保留其余字段，
+	// 响应没有包装在条目数组中而是直接返回条目对象时也可以解码
+	DecodeLenient
+)
+
+// String 返回解码模式的名称
+func (m DecodeMode) String() string {
+	switch m {
+	case DecodeDefault:
+		return "default"
+	case DecodeStrict:
+		return "strict"
+	case DecodeLenient:
+		return "lenient"
+	default:
+		return fmt.Sprintf("DecodeMode(%d)", int(m))
+	}
+}
+
+// DecodeErrorKind 表示解码错误的类型
+type DecodeErrorKind int
+
+const (
+	// DecodeTypeMismatch 字段的JSON类型与模型不一致
+	DecodeTypeMismatch DecodeErrorKind = iota + 1
+
+	// DecodeUnknownField 响应中有模型没有的字段，只在DecodeStrict下出现
+	DecodeUnknownField
+
+	// DecodeMissingField 响应中缺少必需的字段，只在DecodeStrict下出现
+	DecodeMissingField
+)
+
+// DecodeError 描述API响应与模型不一致的具体位置
+type DecodeError struct {
+	// Kind 错误类型
+	Kind DecodeErrorKind
+
+	// Field 出错的字段，类型不匹配时为从响应顶层开始的路径，如"weaknesses.name"
+	Field string
+
+	// Expected 模型中字段的Go类型，只在DecodeTypeMismatch时设置
+	Expected string
+
+	// Actual 响应中值的JSON类型，如"number"，只在DecodeTypeMismatch时设置
+	Actual string
+
+	// Offset 出错位置在响应体中的字节偏移，未知时为0
+	Offset int64
+
+	// Err 底层的解码错误
+	Err error
+}
+
+// Error 实现error接口
+func (e *DecodeError) Error() string {
+	switch e.Kind {
+	case DecodeTypeMismatch:
+		return fmt.Sprintf("字段%s类型不匹配: 期望%s，实际为%s(偏移%d)", e.Field, e.Expected, e.Actual, e.Offset)
+	case DecodeUnknownField:
+		return fmt.Sprintf("响应中有模型没有的字段%s", e.Field)
+	case DecodeMissingField:
+		return fmt.Sprintf("响应中缺少字段%s", e.Field)
+	default:
+		return fmt.Sprintf("解码字段%s失败: %v", e.Field, e.Err)
+	}
+}
+
+// Unwrap 返回底层的解码错误
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// SetDecodeMode 设置解码API响应的模式
+//
+// 方法功能:
+// 控制GetWeakness、GetCategory、GetView和GetCWEs在响应结构与模型不一致时的行为，见DecodeMode。
+// 解码失败时返回的错误包装了*DecodeError，可以通过errors.As取出出错的字段和类型。
+//
+// 参数:
+// - mode: DecodeMode - 解码模式，默认为DecodeDefault
+//
+// 使用示例:
+// ```go
+// client := cwe.NewAPIClient()
+// client.SetDecodeMode(cwe.DecodeStrict)
+//
+// _, err := client.GetWeakness("79")
+// var decodeErr *cwe.DecodeError
+//
+//	if errors.As(err, &decodeErr) {
+//	    log.Fatalf("API结构发生变化: %s", decodeErr.Field)
+//	}
+//
+// ```
+func (c *APIClient) SetDecodeMode(mode DecodeMode) {
+	c.decodeMode = mode
+}
+
+// GetDecodeMode 获取解码API响应的模式
+func (c *APIClient) GetDecodeMode() DecodeMode {
+	return c.decodeMode
+}
+
+// SetDecodeMode 设置解码API响应的模式，等同于在底层API客户端上调用SetDecodeMode
+func (f *DataFetcher) SetDecodeMode(mode DecodeMode) {
+	f.client.SetDecodeMode(mode)
+}
+
+// unwrapEntries 检查响应是否把条目包装在key对应的数组中
+//
+// DecodeStrict下缺少该数组时返回*DecodeError；DecodeLenient下响应直接是带"id"的条目对象时，
+// 返回包装后的响应体；其他情况原样返回body，由调用方按原有逻辑处理。
+func (c *APIClient) unwrapEntries(body []byte, key string) ([]byte, error) {
+	if c.decodeMode == DecodeDefault {
+		return body, nil
+	}
+
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(body, &top); err != nil {
+		// 非对象响应交给后续解码报告错误
+		return body, nil
+	}
+	for name := range top {
+		if strings.EqualFold(name, key) {
+			return body, nil
+		}
+	}
+
+	switch c.decodeMode {
+	case DecodeStrict:
+		return nil, &DecodeError{Kind: DecodeMissingField, Field: key}
+	case DecodeLenient:
+		for name := range top {
+			if strings.EqualFold(name, "id") {
+				return []byte(`{` + strconv.Quote(key) + `:[` + string(body) + `]}`), nil
+			}
+		}
+	}
+	return body, nil
+}
+
+// unmarshalResponse 按解码模式将data解码到v中，并将结构不一致的错误转换为*DecodeError
+func unmarshalResponse(data []byte, v interface{}, mode DecodeMode) error {
+	var err error
+	if mode == DecodeStrict {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(v)
+	} else {
+		err = json.Unmarshal(data, v)
+	}
+	if err == nil {
+		return nil
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		// encoding/json遇到类型不匹配的字段时会继续解码其余字段
+		if mode == DecodeLenient {
+			return nil
+		}
+		field := typeErr.Field
+		if field == "" {
+			field = typeErr.Struct
+		}
+		return &DecodeError{
+			Kind:     DecodeTypeMismatch,
+			Field:    field,
+			Expected: typeErr.Type.String(),
+			Actual:   typeErr.Value,
+			Offset:   typeErr.Offset,
+			Err:      err,
+		}
+	}
+
+	// encoding/json没有为未知字段导出错误类型，只能从错误信息中取出字段名
+	const unknownFieldPrefix = "json: unknown field "
+	if strings.HasPrefix(err.Error(), unknownFieldPrefix) {
+		name := strings.TrimPrefix(err.Error(), unknownFieldPrefix)
+		if unquoted, unquoteErr := strconv.Unquote(name); unquoteErr == nil {
+			name = unquoted
+		}
+		return &DecodeError{Kind: DecodeUnknownField, Field: name, Err: err}
+	}
+	return err
+}
@@ -0,0 +1,87 @@
+package cwe
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecodeModeTypeMismatch(t *testing.T) {
+	body := `{"weaknesses": [{"id": "79", "name": "XSS", "severity": 3}]}`
+
+	client := newFieldMaskTestClient(t, body)
+	if client.GetDecodeMode() != DecodeDefault {
+		t.Fatalf("默认解码模式应为DecodeDefault，实际为%v", client.GetDecodeMode())
+	}
+
+	_, err := client.GetWeakness("79")
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("类型不匹配时应返回DecodeError，实际: %v", err)
+	}
+	if decodeErr.Kind != DecodeTypeMismatch || !strings.HasSuffix(decodeErr.Field, "severity") ||
+		decodeErr.Expected != "string" || decodeErr.Actual != "number" {
+		t.Errorf("DecodeError应指出字段和类型，实际为%+v", decodeErr)
+	}
+
+	// 宽松模式跳过不匹配的字段并保留其余字段
+	client.SetDecodeMode(DecodeLenient)
+	weakness, err := client.GetWeakness("79")
+	if err != nil {
+		t.Fatalf("宽松模式不应失败: %v", err)
+	}
+	if weakness.Name != "XSS" || weakness.Severity != "" {
+		t.Errorf("宽松模式应保留其余字段，实际为%+v", weakness)
+	}
+}
+
+func TestDecodeModeStrict(t *testing.T) {
+	client := newFieldMaskTestClient(t, `{"weaknesses": [{"id": "79", "name": "XSS", "TaxonomyMappings": []}]}`)
+
+	// 默认模式下模型中没有的字段被忽略
+	if _, err := client.GetWeakness("79"); err != nil {
+		t.Fatalf("默认模式不应失败: %v", err)
+	}
+
+	client.SetDecodeMode(DecodeStrict)
+	_, err := client.GetWeakness("79")
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) || decodeErr.Kind != DecodeUnknownField || decodeErr.Field != "TaxonomyMappings" {
+		t.Errorf("严格模式下未知字段应返回DecodeError，实际: %v", err)
+	}
+
+	// 掩码丢弃字段时同样生效
+	client.SetFieldMask(FieldsMinimal)
+	if _, err := client.GetWeakness("79"); !errors.As(err, &decodeErr) {
+		t.Errorf("设置掩码时严格模式仍应生效，实际: %v", err)
+	}
+
+	flat := newFieldMaskTestClient(t, `{"id": "79", "name": "XSS"}`)
+	flat.SetDecodeMode(DecodeStrict)
+	if _, err := flat.GetWeakness("79"); !errors.As(err, &decodeErr) || decodeErr.Kind != DecodeMissingField || decodeErr.Field != "weaknesses" {
+		t.Errorf("严格模式下缺少条目数组应返回DecodeError，实际: %v", err)
+	}
+
+	cwes := newFieldMaskTestClient(t, `{"79": {"name": "XSS"}}`)
+	cwes.SetDecodeMode(DecodeStrict)
+	if _, err := cwes.GetCWEs([]string{"79"}); !errors.As(err, &decodeErr) {
+		t.Errorf("严格模式下GetCWEs不应猜测响应格式，实际: %v", err)
+	}
+}
+
+func TestDecodeModeLenientFlatEntry(t *testing.T) {
+	client := newFieldMaskTestClient(t, `{"id": "1000", "name": "Research Concepts"}`)
+
+	if _, err := client.GetView("1000"); err == nil {
+		t.Fatal("默认模式下没有条目数组时应失败")
+	}
+
+	client.SetDecodeMode(DecodeLenient)
+	view, err := client.GetView("1000")
+	if err != nil {
+		t.Fatalf("宽松模式应接受未包装的条目: %v", err)
+	}
+	if view.ID != "1000" || view.Name != "Research Concepts" {
+		t.Errorf("解码结果不正确: %+v", view)
+	}
+}
@@ -0,0 +1,216 @@
+package cwe
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// FieldMask 指定获取条目时保留的可选字段
+//
+// ID、Name、Description、URL、Severity、Status等基础字段总会保留；
+// 掩码只控制体积较大的可选字段。被排除的字段在解码阶段就会被丢弃，
+// 不会为其分配内存，适用于在内存受限的环境中构建大型注册表。
+type FieldMask uint32
+
+const (
+	// FieldExtendedDescription 扩展描述
+	FieldExtendedDescription FieldMask = 1 << iota
+
+	// FieldRelatedWeaknesses 相关弱点关系
+	FieldRelatedWeaknesses
+
+	// FieldCommonConsequences 常见影响
+	FieldCommonConsequences
+
+	// FieldDetectionMethods 检测方法
+	FieldDetectionMethods
+
+	// FieldMitigations 缓解措施
+	FieldMitigations
+
+	// FieldAlternateTerms 替代术语
+	FieldAlternateTerms
+
+	// FieldApplicablePlatforms 适用平台
+	FieldApplicablePlatforms
+
+	// FieldDemonstrativeExamples 示例代码，通常是弱点条目中体积最大的字段
+	FieldDemonstrativeExamples
+
+	// FieldObservedExamples 已观察到的实例
+	FieldObservedExamples
+
+	// FieldContentHistory 内容历史，对弱点、分类和视图均生效
+	FieldContentHistory
+
+	// FieldRelatedAttackPatterns 相关CAPEC攻击模式，FetchRelatedAttackPatterns需要该字段
+	FieldRelatedAttackPatterns
+)
+
+const (
+	// FieldsAll 保留所有字段，这是客户端的默认行为
+	FieldsAll = FieldExtendedDescription | FieldRelatedWeaknesses | FieldCommonConsequences |
+		FieldDetectionMethods | FieldMitigations | FieldAlternateTerms | FieldApplicablePlatforms |
+		FieldDemonstrativeExamples | FieldObservedExamples | FieldContentHistory | FieldRelatedAttackPatterns
+
+	// FieldsMinimal 只保留DataFetcher转换为CWE结构时用到的字段
+	FieldsMinimal = FieldMitigations | FieldObservedExamples
+)
+
+// fieldKeys 每个掩码位对应的JSON键名
+// 键名经过normalizeFieldKey处理，同时匹配"content_history"和"ContentHistory"两种写法
+var fieldKeys = map[FieldMask]string{
+	FieldExtendedDescription:   "extendeddescription",
+	FieldRelatedWeaknesses:     "relatedweaknesses",
+	FieldCommonConsequences:    "commonconsequences",
+	FieldDetectionMethods:      "detectionmethods",
+	FieldMitigations:           "mitigations",
+	FieldAlternateTerms:        "alternateterms",
+	FieldApplicablePlatforms:   "applicableplatforms",
+	FieldDemonstrativeExamples: "demonstrativeexamples",
+	FieldObservedExamples:      "observedexamples",
+	FieldContentHistory:        "contenthistory",
+	FieldRelatedAttackPatterns: "relatedattackpatterns",
+}
+
+// SetFieldMask 设置获取条目时保留的可选字段
+//
+// 方法功能:
+// 之后的GetWeakness、GetCWEs、GetCategory和GetView调用只解码掩码中包含的可选字段，
+// 其余可选字段在解码时直接丢弃。
+//
+// 参数:
+// - mask: FieldMask - 要保留的字段，FieldsAll恢复默认行为
+//
+// 使用示例:
+// ```go
+// client := cwe.NewAPIClient()
+// // 跳过示例代码和内容历史
+// client.SetFieldMask(cwe.FieldsAll &^ (cwe.FieldDemonstrativeExamples | cwe.FieldContentHistory))
+//
+// fetcher := cwe.NewDataFetcherWithClient(client)
+// registry, err := fetcher.BuildCWETreeWithView("1000")
+// ```
+func (c *APIClient) SetFieldMask(mask FieldMask) {
+	c.droppedFields = FieldsAll &^ mask
+}
+
+// GetFieldMask 获取获取条目时保留的可选字段
+func (c *APIClient) GetFieldMask() FieldMask {
+	return FieldsAll &^ c.droppedFields
+}
+
+// SetFieldMask 设置获取条目时保留的可选字段，等同于在底层API客户端上调用SetFieldMask
+func (f *DataFetcher) SetFieldMask(mask FieldMask) {
+	f.client.SetFieldMask(mask)
+}
+
+// decodeResponse 按客户端的解码模式将响应体解码到v中，并丢弃客户端未保留的字段
+func (c *APIClient) decodeResponse(body []byte, v interface{}) error {
+	return decodeMasked(body, v, c.droppedFields, c.decodeMode)
+}
+
+// decodeMasked 解码响应体，丢弃dropped中指定的字段
+//
+// 响应体的顶层是对象，条目位于其中的数组(如"weaknesses")或对象(如"cwes")内。
+// 条目先以json.RawMessage形式解码，删除被丢弃的键后再解码到目标结构，
+// 因此被丢弃字段的内容不会被解析为Go值。
+func decodeMasked(body []byte, v interface{}, dropped FieldMask, mode DecodeMode) error {
+	if dropped == 0 {
+		return unmarshalResponse(body, v, mode)
+	}
+
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(body, &top); err != nil {
+		// 非对象响应交给目标类型处理，以保留原有的错误信息
+		return unmarshalResponse(body, v, mode)
+	}
+
+	for key, value := range top {
+		stripped, err := stripEntries(value, dropped)
+		if err != nil {
+			return err
+		}
+		top[key] = stripped
+	}
+
+	data, err := json.Marshal(top)
+	if err != nil {
+		return err
+	}
+	return unmarshalResponse(data, v, mode)
+}
+
+// stripEntries 从数组或对象中的每个条目删除被丢弃的字段
+// 其他类型的值原样返回
+func stripEntries(value json.RawMessage, dropped FieldMask) (json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(value)
+	if len(trimmed) == 0 {
+		return value, nil
+	}
+
+	switch trimmed[0] {
+	case '[':
+		var entries []json.RawMessage
+		if err := json.Unmarshal(trimmed, &entries); err != nil {
+			return nil, err
+		}
+		for i, entry := range entries {
+			entries[i] = stripFields(entry, dropped)
+		}
+		return json.Marshal(entries)
+	case '{':
+		var entries map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &entries); err != nil {
+			return nil, err
+		}
+		for key, entry := range entries {
+			entries[key] = stripFields(entry, dropped)
+		}
+		return json.Marshal(entries)
+	default:
+		return value, nil
+	}
+}
+
+// stripFields 删除单个条目对象中被丢弃的字段，非对象值原样返回
+func stripFields(entry json.RawMessage, dropped FieldMask) json.RawMessage {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(entry, &fields); err != nil {
+		return entry
+	}
+
+	removed := false
+	for key := range fields {
+		if isDroppedField(key, dropped) {
+			delete(fields, key)
+			removed = true
+		}
+	}
+	if !removed {
+		return entry
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return entry
+	}
+	return data
+}
+
+// isDroppedField 判断JSON键是否属于被丢弃的字段
+func isDroppedField(key string, dropped FieldMask) bool {
+	normalized := normalizeFieldKey(key)
+	for bit, name := range fieldKeys {
+		if dropped&bit != 0 && normalized == name {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeFieldKey 将JSON键转换为小写并去除下划线
+func normalizeFieldKey(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", ""))
+}
@@ -0,0 +1,119 @@
+package cwe
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const maskedWeaknessJSON = `{
+	"weaknesses": [{
+		"id": "CWE-79",
+		"name": "Cross-site Scripting",
+		"description": "XSS",
+		"extended_description": "long text",
+		"mitigations": [{"description": "Encode output"}],
+		"observed_examples": [{"reference": "CVE-2021-0001", "description": "example"}],
+		"demonstrative_examples": [{"code": "<script>"}],
+		"content_history": [{"type": "Submission"}]
+	}]
+}`
+
+func newFieldMaskTestClient(t *testing.T, body string) *APIClient {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return NewAPIClientWithOptions(server.URL, DefaultTimeout, NewHTTPRateLimiter(0))
+}
+
+func TestFieldMaskDefaultKeepsAll(t *testing.T) {
+	client := newFieldMaskTestClient(t, maskedWeaknessJSON)
+
+	if client.GetFieldMask() != FieldsAll {
+		t.Errorf("默认掩码应为FieldsAll，但得到 %b", client.GetFieldMask())
+	}
+
+	weakness, err := client.GetWeakness("79")
+	if err != nil {
+		t.Fatalf("GetWeakness失败: %v", err)
+	}
+	if len(weakness.DemonstrativeExamples) != 1 || len(weakness.ContentHistory) != 1 {
+		t.Errorf("默认应保留所有字段: %+v", weakness)
+	}
+}
+
+func TestFieldMaskDropsFields(t *testing.T) {
+	client := newFieldMaskTestClient(t, maskedWeaknessJSON)
+	client.SetFieldMask(FieldsAll &^ (FieldDemonstrativeExamples | FieldContentHistory | FieldExtendedDescription))
+
+	weakness, err := client.GetWeakness("79")
+	if err != nil {
+		t.Fatalf("GetWeakness失败: %v", err)
+	}
+
+	if weakness.ID != "CWE-79" || weakness.Name != "Cross-site Scripting" || weakness.Description != "XSS" {
+		t.Errorf("基础字段不应受掩码影响: %+v", weakness)
+	}
+	if weakness.DemonstrativeExamples != nil {
+		t.Errorf("DemonstrativeExamples应被丢弃，但得到 %v", weakness.DemonstrativeExamples)
+	}
+	if weakness.ContentHistory != nil {
+		t.Errorf("ContentHistory应被丢弃，但得到 %v", weakness.ContentHistory)
+	}
+	if weakness.ExtendedDescription != "" {
+		t.Errorf("ExtendedDescription应被丢弃，但得到 %q", weakness.ExtendedDescription)
+	}
+	if len(weakness.Mitigations) != 1 || len(weakness.ObservedExamples) != 1 {
+		t.Errorf("未排除的字段应保留: %+v", weakness)
+	}
+}
+
+func TestFieldMaskMinimalWithFetcher(t *testing.T) {
+	client := newFieldMaskTestClient(t, maskedWeaknessJSON)
+	fetcher := NewDataFetcherWithClient(client)
+	fetcher.SetFieldMask(FieldsMinimal)
+
+	if client.GetFieldMask() != FieldsMinimal {
+		t.Fatalf("DataFetcher.SetFieldMask应设置底层客户端的掩码")
+	}
+
+	cwe, err := fetcher.FetchWeakness("79")
+	if err != nil {
+		t.Fatalf("FetchWeakness失败: %v", err)
+	}
+	if len(cwe.Mitigations) != 1 || len(cwe.Examples) != 1 {
+		t.Errorf("FieldsMinimal应保留转换所需的字段: %+v", cwe)
+	}
+}
+
+func TestFieldMaskCWEsMap(t *testing.T) {
+	client := newFieldMaskTestClient(t, `{"cwes": {"CWE-79": {"id": "CWE-79", "name": "XSS", "content_history": [{"type": "Submission"}]}}}`)
+	client.SetFieldMask(FieldsAll &^ FieldContentHistory)
+
+	cwes, err := client.GetCWEs([]string{"79"})
+	if err != nil {
+		t.Fatalf("GetCWEs失败: %v", err)
+	}
+	if cwes["CWE-79"] == nil || cwes["CWE-79"].Name != "XSS" {
+		t.Fatalf("GetCWEs结果不正确: %v", cwes)
+	}
+	if cwes["CWE-79"].ContentHistory != nil {
+		t.Error("对象形式的条目也应丢弃被排除的字段")
+	}
+}
+
+func TestIsDroppedField(t *testing.T) {
+	dropped := FieldDemonstrativeExamples | FieldContentHistory
+	for _, key := range []string{"demonstrative_examples", "DemonstrativeExamples", "ContentHistory", "content_history"} {
+		if !isDroppedField(key, dropped) {
+			t.Errorf("%s应被识别为丢弃字段", key)
+		}
+	}
+	for _, key := range []string{"id", "Name", "mitigations"} {
+		if isDroppedField(key, dropped) {
+			t.Errorf("%s不应被丢弃", key)
+		}
+	}
+}
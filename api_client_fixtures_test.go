@@ -0,0 +1,96 @@
+package cwe_test
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/scagogogo/cwe"
+	"github.com/scagogogo/cwe/cwetest"
+)
+
+// newFixtureClient 创建一个回放录制的官方API响应的客户端
+func newFixtureClient() *cwe.APIClient {
+	client := cwe.NewAPIClientWithOptions(cwe.BaseURL, cwe.DefaultTimeout, cwe.NewHTTPRateLimiter(0))
+	client.GetHTTPClient().SetClient(&http.Client{Transport: cwetest.NewReplayTransport()})
+	return client
+}
+
+func TestFixtureGetVersion(t *testing.T) {
+	version, err := newFixtureClient().GetVersion()
+	if err != nil {
+		t.Fatalf("GetVersion failed: %v", err)
+	}
+	if version.Version != "4.14" || version.ReleaseDate != "2024-02-29" {
+		t.Errorf("Expected version 4.14 released 2024-02-29, got %+v", version)
+	}
+}
+
+func TestFixtureGetWeakness(t *testing.T) {
+	weakness, err := newFixtureClient().GetWeakness("CWE-79")
+	if err != nil {
+		t.Fatalf("GetWeakness failed: %v", err)
+	}
+
+	if weakness.ID != "79" {
+		t.Errorf("Expected raw ID 79, got %s", weakness.ID)
+	}
+	if !strings.HasPrefix(weakness.Name, "Improper Neutralization of Input") {
+		t.Errorf("Unexpected name: %s", weakness.Name)
+	}
+	if weakness.Abstraction != "Base" || weakness.Status != "Stable" {
+		t.Errorf("Unexpected abstraction/status: %s/%s", weakness.Abstraction, weakness.Status)
+	}
+}
+
+func TestFixtureFetchWeaknessNormalizesID(t *testing.T) {
+	fetcher := cwe.NewDataFetcherWithClient(newFixtureClient())
+
+	entry, err := fetcher.FetchWeakness("79")
+	if err != nil {
+		t.Fatalf("FetchWeakness failed: %v", err)
+	}
+	if entry.ID != "CWE-79" {
+		t.Errorf("Expected normalized ID CWE-79, got %s", entry.ID)
+	}
+	if entry.Status != "Stable" {
+		t.Errorf("Expected status Stable, got %s", entry.Status)
+	}
+
+	view, err := fetcher.FetchView("1003")
+	if err != nil {
+		t.Fatalf("FetchView failed: %v", err)
+	}
+	if view.ID != "CWE-1003" {
+		t.Errorf("Expected normalized view ID CWE-1003, got %s", view.ID)
+	}
+
+	category, err := fetcher.FetchCategory("189")
+	if err != nil {
+		t.Fatalf("FetchCategory failed: %v", err)
+	}
+	if category.ID != "CWE-189" || category.Name != "Numeric Errors" {
+		t.Errorf("Unexpected category: %s %s", category.ID, category.Name)
+	}
+}
+
+func TestFixtureRelations(t *testing.T) {
+	client := newFixtureClient()
+
+	children, err := client.GetChildren("74", "1000")
+	if err != nil {
+		t.Fatalf("GetChildren failed: %v", err)
+	}
+	if !reflect.DeepEqual(children, []string{"75", "77", "79"}) {
+		t.Errorf("Unexpected children: %v", children)
+	}
+
+	parents, err := client.GetParents("CWE-79", "1000")
+	if err != nil {
+		t.Fatalf("GetParents failed: %v", err)
+	}
+	if !reflect.DeepEqual(parents, []string{"74"}) {
+		t.Errorf("Unexpected parents: %v", parents)
+	}
+}
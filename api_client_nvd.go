@@ -0,0 +1,308 @@
+package cwe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// NVDBaseURL 是NVD CVE API 2.0的地址
+	NVDBaseURL = "https://services.nvd.nist.gov/rest/json/cves/2.0"
+
+	// NVDDefaultInterval 没有API密钥时两次请求之间的默认间隔
+	// NVD对匿名请求的限制为每30秒5次
+	NVDDefaultInterval = 6 * time.Second
+)
+
+// ErrCVENotFound 表示NVD中不存在请求的CVE
+var ErrCVENotFound = errors.New("NVD中不存在该CVE")
+
+// cvePattern 匹配CVE ID，如"CVE-2023-1234"
+var cvePattern = regexp.MustCompile(`^CVE-\d{4}-\d{4,}$`)
+
+// NVDClient 是查询NVD CVE API的客户端，用于获取CVE映射的CWE
+type NVDClient struct {
+	// client 发送HTTP请求的客户端，包含重试和限速
+	client *HTTPClient
+
+	// baseURL CVE API地址
+	baseURL string
+
+	// apiKey NVD API密钥，为空时以匿名身份请求
+	apiKey string
+}
+
+// nvdResponse 是NVD CVE API响应中需要的部分
+type nvdResponse struct {
+	Vulnerabilities []struct {
+		CVE nvdCVE `json:"cve"`
+	} `json:"vulnerabilities"`
+}
+
+// nvdCVE 是NVD响应中的单个CVE
+type nvdCVE struct {
+	ID           string           `json:"id"`
+	Published    string           `json:"published"`
+	LastModified string           `json:"lastModified"`
+	Descriptions []nvdDescription `json:"descriptions"`
+	Metrics      struct {
+		CVSSMetricV31 []nvdCVSSMetric `json:"cvssMetricV31"`
+		CVSSMetricV30 []nvdCVSSMetric `json:"cvssMetricV30"`
+	} `json:"metrics"`
+	Weaknesses []struct {
+		Source      string           `json:"source"`
+		Type        string           `json:"type"`
+		Description []nvdDescription `json:"description"`
+	} `json:"weaknesses"`
+}
+
+type nvdDescription struct {
+	Lang  string `json:"lang"`
+	Value string `json:"value"`
+}
+
+type nvdCVSSMetric struct {
+	Type     string `json:"type"`
+	CVSSData struct {
+		BaseScore    float64 `json:"baseScore"`
+		BaseSeverity string  `json:"baseSeverity"`
+		VectorString string  `json:"vectorString"`
+	} `json:"cvssData"`
+}
+
+// nvdTimeLayout NVD返回的时间格式，不带时区，按UTC解释
+const nvdTimeLayout = "2006-01-02T15:04:05.000"
+
+// CVESummary 是NVD中CVE的摘要信息
+type CVESummary struct {
+	// ID CVE ID，如"CVE-2021-44228"
+	ID string `json:"id"`
+
+	// Description 英文描述，NVD没有英文描述时取第一条描述
+	Description string `json:"description,omitempty"`
+
+	// Published 和LastModified 为发布和最后修改时间，NVD未提供时为零值
+	Published    time.Time `json:"published,omitempty"`
+	LastModified time.Time `json:"last_modified,omitempty"`
+
+	// BaseScore 和Severity 为CVSS v3基础分和严重性，优先取NVD自己的评分(type为Primary)，没有CVSS v3评分时为零值
+	BaseScore float64 `json:"base_score,omitempty"`
+	Severity  string  `json:"severity,omitempty"`
+
+	// Vector CVSS v3向量，如"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H"
+	Vector string `json:"vector,omitempty"`
+
+	// URL NVD中CVE详情页的网址，见NVDCVEURL
+	URL string `json:"url"`
+}
+
+// NewNVDClient 创建访问官方NVD API的客户端
+// 使用独立的限速器，请求间隔为NVDDefaultInterval；设置API密钥后可以通过GetHTTPClient调整限速
+func NewNVDClient() *NVDClient {
+	return NewNVDClientWithOptions(NVDBaseURL, DefaultTimeout, NewHTTPRateLimiter(NVDDefaultInterval))
+}
+
+// NewNVDClientWithOptions 使用自定义地址、超时和限速器创建NVD客户端
+//
+// 参数:
+// - baseURL: string - CVE API地址，为空时使用NVDBaseURL，可指向内部镜像
+// - timeout: time.Duration - 请求超时，<=0时使用DefaultTimeout
+// - rateLimiter: ...*HTTPRateLimiter - 可选的限速器，未提供时使用全局默认限速器
+//
+// 返回值:
+// - *NVDClient: NVD客户端
+func NewNVDClientWithOptions(baseURL string, timeout time.Duration, rateLimiter ...*HTTPRateLimiter) *NVDClient {
+	if baseURL == "" {
+		baseURL = NVDBaseURL
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	httpClient := NewHttpClient()
+	httpClient.SetClient(&http.Client{Timeout: timeout})
+	if len(rateLimiter) > 0 && rateLimiter[0] != nil {
+		httpClient.SetRateLimiter(rateLimiter[0])
+	}
+
+	return &NVDClient{
+		client:  httpClient,
+		baseURL: baseURL,
+	}
+}
+
+// SetAPIKey 设置NVD API密钥，请求时通过apiKey请求头发送
+func (c *NVDClient) SetAPIKey(key string) {
+	c.apiKey = strings.TrimSpace(key)
+}
+
+// GetHTTPClient 获取底层HTTP客户端，可用于调整重试和限速
+func (c *NVDClient) GetHTTPClient() *HTTPClient {
+	return c.client
+}
+
+// GetCVEWeaknesses 获取CVE映射的CWE ID
+//
+// 方法功能:
+// 查询NVD中CVE的weaknesses字段，返回规范化为"CWE-数字"格式的ID。
+// NVD自己的分析(type为Primary)排在前面，其余来源(CNA等)的映射随后，重复的ID只保留一次。
+// "NVD-CWE-Other"、"NVD-CWE-noinfo"等不对应具体CWE的值会被忽略。
+//
+// 参数:
+// - cveID: string - CVE ID，如"CVE-2021-44228"，不区分大小写
+//
+// 返回值:
+// - []string: CWE ID列表，CVE没有映射时为空切片
+// - error: CVE ID格式无效、CVE不存在(包装ErrCVENotFound)或请求失败时返回错误
+//
+// 使用示例:
+// ```go
+// nvd := cwe.NewNVDClient()
+// ids, err := nvd.GetCVEWeaknesses("CVE-2021-44228")
+// fmt.Println(ids) // [CWE-917 CWE-502 CWE-400 CWE-20]
+// ```
+func (c *NVDClient) GetCVEWeaknesses(cveID string) ([]string, error) {
+	cve, err := c.getCVE(cveID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0)
+	seen := make(map[string]bool)
+	// 第一轮取Primary映射，第二轮取其余映射
+	for _, primary := range []bool{true, false} {
+		for _, weakness := range cve.Weaknesses {
+			if (weakness.Type == "Primary") != primary {
+				continue
+			}
+			for _, desc := range weakness.Description {
+				id, err := ParseCWEID(desc.Value)
+				if err != nil || seen[id] {
+					continue
+				}
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// GetCVESummary 获取CVE的描述、发布时间和CVSS评分
+//
+// 方法功能:
+// 与GetCVEWeaknesses查询同一个接口，用于在展示CWE的已观察实例时补充CVE的详情。
+// 受NVD的速率限制，没有API密钥时每次请求间隔NVDDefaultInterval。
+//
+// 参数:
+// - cveID: string - CVE ID，如"CVE-2021-44228"，不区分大小写
+//
+// 返回值:
+// - *CVESummary: CVE摘要
+// - error: CVE ID格式无效、CVE不存在(包装ErrCVENotFound)或请求失败时返回错误
+//
+// 使用示例:
+// ```go
+// summary, err := nvd.GetCVESummary("CVE-2021-44228")
+//
+//	if err == nil {
+//	    fmt.Printf("%s %.1f %s\n", summary.ID, summary.BaseScore, summary.Description)
+//	}
+//
+// ```
+func (c *NVDClient) GetCVESummary(cveID string) (*CVESummary, error) {
+	cve, err := c.getCVE(cveID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &CVESummary{ID: cve.ID, URL: NVDCVEURL(cve.ID)}
+	for _, desc := range cve.Descriptions {
+		if summary.Description == "" || desc.Lang == "en" {
+			summary.Description = desc.Value
+		}
+		if desc.Lang == "en" {
+			break
+		}
+	}
+	summary.Published, _ = time.Parse(nvdTimeLayout, cve.Published)
+	summary.LastModified, _ = time.Parse(nvdTimeLayout, cve.LastModified)
+
+	metrics := append(cve.Metrics.CVSSMetricV31, cve.Metrics.CVSSMetricV30...)
+	for i, metric := range metrics {
+		if i == 0 || metric.Type == "Primary" {
+			summary.BaseScore = metric.CVSSData.BaseScore
+			summary.Severity = metric.CVSSData.BaseSeverity
+			summary.Vector = metric.CVSSData.VectorString
+		}
+		if metric.Type == "Primary" {
+			break
+		}
+	}
+	return summary, nil
+}
+
+// getCVE 从NVD查询单个CVE
+func (c *NVDClient) getCVE(cveID string) (*nvdCVE, error) {
+	normalized, err := ParseCVEID(cveID)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", c.baseURL+"?cveId="+url.QueryEscape(normalized), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.apiKey != "" {
+		req.Header.Set("apiKey", c.apiKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("查询NVD失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrCVENotFound, normalized)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("NVD请求失败，状态码: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应体失败: %w", err)
+	}
+
+	var nvdResp nvdResponse
+	if err := json.Unmarshal(body, &nvdResp); err != nil {
+		return nil, fmt.Errorf("解析JSON响应失败: %w", err)
+	}
+	if len(nvdResp.Vulnerabilities) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrCVENotFound, normalized)
+	}
+
+	cve := nvdResp.Vulnerabilities[0].CVE
+	if cve.ID == "" {
+		cve.ID = normalized
+	}
+	return &cve, nil
+}
+
+// ParseCVEID 校验并规范化CVE ID
+// 接受大小写不同和带首尾空白的写法，返回大写形式，如" cve-2023-1234 " -> "CVE-2023-1234"
+func ParseCVEID(id string) (string, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(id))
+	if !cvePattern.MatchString(normalized) {
+		return "", fmt.Errorf("无效的CVE ID: %s", id)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,177 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// nvdLog4ShellResponse 是NVD对CVE-2021-44228返回的响应(截取weaknesses部分)
+const nvdLog4ShellResponse = `{
+  "resultsPerPage": 1,
+  "totalResults": 1,
+  "vulnerabilities": [{
+    "cve": {
+      "id": "CVE-2021-44228",
+      "weaknesses": [
+        {"source": "security@apache.org", "type": "Secondary", "description": [
+          {"lang": "en", "value": "CWE-502"}, {"lang": "en", "value": "CWE-400"}, {"lang": "en", "value": "CWE-20"}
+        ]},
+        {"source": "nvd@nist.gov", "type": "Primary", "description": [
+          {"lang": "en", "value": "CWE-917"}, {"lang": "en", "value": "CWE-20"}
+        ]},
+        {"source": "nvd@nist.gov", "type": "Secondary", "description": [
+          {"lang": "en", "value": "NVD-CWE-noinfo"}
+        ]}
+      ],
+      "published": "2021-12-10T10:15:09.143",
+      "lastModified": "2024-07-24T17:08:24.167",
+      "descriptions": [
+        {"lang": "es", "value": "Apache Log4j2 (descripción)"},
+        {"lang": "en", "value": "Apache Log4j2 JNDI features do not protect against attacker controlled LDAP."}
+      ],
+      "metrics": {
+        "cvssMetricV31": [
+          {"source": "security@apache.org", "type": "Secondary", "cvssData": {"baseScore": 9.0, "baseSeverity": "CRITICAL", "vectorString": "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:C/C:H/I:H/A:H"}},
+          {"source": "nvd@nist.gov", "type": "Primary", "cvssData": {"baseScore": 10.0, "baseSeverity": "CRITICAL", "vectorString": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H"}}
+        ]
+      }
+    }
+  }]
+}`
+
+// setupNVDServer 创建模拟NVD CVE API的测试服务器
+func setupNVDServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("cveId") {
+		case "CVE-2021-44228":
+			fmt.Fprint(w, nvdLog4ShellResponse)
+		case "CVE-2020-0001":
+			fmt.Fprint(w, `{"vulnerabilities": [{"cve": {"id": "CVE-2020-0001", "weaknesses": [
+				{"source": "nvd@nist.gov", "type": "Primary", "description": [{"lang": "en", "value": "NVD-CWE-Other"}]}
+			]}}]}`)
+		case "CVE-2020-0002":
+			if r.Header.Get("apiKey") != "secret" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			fmt.Fprint(w, `{"vulnerabilities": [{"cve": {"id": "CVE-2020-0002", "weaknesses": [
+				{"source": "nvd@nist.gov", "type": "Primary", "description": [{"lang": "en", "value": "CWE-79"}]}
+			]}}]}`)
+		case "CVE-2020-0404":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			fmt.Fprint(w, `{"resultsPerPage": 0, "totalResults": 0, "vulnerabilities": []}`)
+		}
+	}))
+}
+
+func newNVDTestClient(url string) *NVDClient {
+	client := NewNVDClientWithOptions(url, DefaultTimeout, NewHTTPRateLimiter(0))
+	client.GetHTTPClient().SetRetryDelay(time.Millisecond)
+	return client
+}
+
+func TestNVDClientGetCVEWeaknesses(t *testing.T) {
+	server := setupNVDServer()
+	defer server.Close()
+
+	client := newNVDTestClient(server.URL)
+
+	ids, err := client.GetCVEWeaknesses(" cve-2021-44228 ")
+	if err != nil {
+		t.Fatalf("获取CVE映射失败: %v", err)
+	}
+	expected := []string{"CWE-917", "CWE-20", "CWE-502", "CWE-400"}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("期望%v，实际为%v", expected, ids)
+	}
+
+	ids, err = client.GetCVEWeaknesses("CVE-2020-0001")
+	if err != nil {
+		t.Fatalf("获取CVE映射失败: %v", err)
+	}
+	if ids == nil || len(ids) != 0 {
+		t.Errorf("NVD-CWE-Other应被忽略，实际为%v", ids)
+	}
+}
+
+func TestNVDClientErrors(t *testing.T) {
+	server := setupNVDServer()
+	defer server.Close()
+
+	client := newNVDTestClient(server.URL)
+
+	for _, id := range []string{"", "CVE-21-1", "CWE-79", "CVE-2021-123"} {
+		if _, err := client.GetCVEWeaknesses(id); err == nil {
+			t.Errorf("%q应被视为无效的CVE ID", id)
+		}
+	}
+
+	for _, id := range []string{"CVE-2099-99999", "CVE-2020-0404"} {
+		if _, err := client.GetCVEWeaknesses(id); !errors.Is(err, ErrCVENotFound) {
+			t.Errorf("%s应返回ErrCVENotFound，实际为%v", id, err)
+		}
+	}
+
+	if _, err := client.GetCVEWeaknesses("CVE-2020-0002"); err == nil {
+		t.Error("没有API密钥时应返回错误")
+	}
+	client.SetAPIKey("secret")
+	ids, err := client.GetCVEWeaknesses("CVE-2020-0002")
+	if err != nil {
+		t.Fatalf("设置API密钥后应成功: %v", err)
+	}
+	if !reflect.DeepEqual(ids, []string{"CWE-79"}) {
+		t.Errorf("期望[CWE-79]，实际为%v", ids)
+	}
+}
+
+func TestNVDClientGetCVESummary(t *testing.T) {
+	server := setupNVDServer()
+	defer server.Close()
+
+	client := newNVDTestClient(server.URL)
+
+	summary, err := client.GetCVESummary("cve-2021-44228")
+	if err != nil {
+		t.Fatalf("获取CVE摘要失败: %v", err)
+	}
+	want := &CVESummary{
+		ID:           "CVE-2021-44228",
+		Description:  "Apache Log4j2 JNDI features do not protect against attacker controlled LDAP.",
+		Published:    time.Date(2021, 12, 10, 10, 15, 9, 143000000, time.UTC),
+		LastModified: time.Date(2024, 7, 24, 17, 8, 24, 167000000, time.UTC),
+		BaseScore:    10.0,
+		Severity:     "CRITICAL",
+		Vector:       "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H",
+		URL:          "https://nvd.nist.gov/vuln/detail/CVE-2021-44228",
+	}
+	if !reflect.DeepEqual(summary, want) {
+		t.Errorf("CVE摘要不正确:\n%+v\n期望:\n%+v", summary, want)
+	}
+
+	// 没有评分和描述的CVE
+	summary, err = client.GetCVESummary("CVE-2020-0001")
+	if err != nil || summary.BaseScore != 0 || summary.Description != "" || !summary.Published.IsZero() {
+		t.Errorf("缺少字段时应为零值: %+v, %v", summary, err)
+	}
+	if _, err := client.GetCVESummary("CVE-2020-0404"); !errors.Is(err, ErrCVENotFound) {
+		t.Errorf("CVE不存在时应返回ErrCVENotFound，实际: %v", err)
+	}
+}
+
+func TestParseCVEID(t *testing.T) {
+	id, err := ParseCVEID("cve-2023-1234")
+	if err != nil || id != "CVE-2023-1234" {
+		t.Errorf("期望CVE-2023-1234，实际为%q, %v", id, err)
+	}
+	if _, err := ParseCVEID("CVE-2023-12"); err == nil {
+		t.Error("序号少于4位的CVE ID应无效")
+	}
+}
@@ -0,0 +1,90 @@
+package cwe
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// SetRetainRaw 设置获取条目时是否保留原始JSON
+//
+// 方法功能:
+// 启用后GetWeakness、GetCategory、GetView和GetCWEs返回的条目的RawData包含响应中该条目的完整JSON对象，
+// 包括模型中还没有的字段和被字段掩码丢弃的字段。默认关闭，以免为每个条目额外解析一次响应。
+//
+// 参数:
+// - retain: bool - 是否保留原始JSON
+func (c *APIClient) SetRetainRaw(retain bool) {
+	c.retainRaw = retain
+}
+
+// GetRetainRaw 获取是否保留原始JSON
+func (c *APIClient) GetRetainRaw() bool {
+	return c.retainRaw
+}
+
+// SetRetainRaw 设置获取条目时是否保留原始JSON，等同于在底层API客户端上调用SetRetainRaw
+//
+// 启用后FetchWeakness、FetchCategory、FetchView及构建树时获取的条目可以通过CWE.Raw读取原始JSON。
+func (f *DataFetcher) SetRetainRaw(retain bool) {
+	f.client.SetRetainRaw(retain)
+}
+
+// rawEntries 从响应体中取出key对应数组中的每个条目的原始JSON，key不区分大小写
+// 未启用SetRetainRaw或响应格式无法识别时返回nil
+func (c *APIClient) rawEntries(body []byte, key string) []json.RawMessage {
+	if !c.retainRaw {
+		return nil
+	}
+
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(body, &top); err != nil {
+		return nil
+	}
+	for name, value := range top {
+		if !strings.EqualFold(name, key) {
+			continue
+		}
+		var entries []json.RawMessage
+		if err := json.Unmarshal(value, &entries); err != nil {
+			return nil
+		}
+		return entries
+	}
+	return nil
+}
+
+// rawEntryMap 从响应体中取出key对应对象中按ID索引的条目的原始JSON
+// 未启用SetRetainRaw或响应格式无法识别时返回nil
+func (c *APIClient) rawEntryMap(body []byte, key string) map[string]json.RawMessage {
+	if !c.retainRaw {
+		return nil
+	}
+
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(body, &top); err != nil {
+		return nil
+	}
+	for name, value := range top {
+		if !strings.EqualFold(name, key) {
+			continue
+		}
+		var entries map[string]json.RawMessage
+		if err := json.Unmarshal(value, &entries); err != nil {
+			return nil
+		}
+		return entries
+	}
+	return nil
+}
+
+// decodeRaw 将条目的原始JSON解码为RawData使用的映射，raw不是对象时返回nil
+func decodeRaw(raw json.RawMessage) map[string]interface{} {
+	if raw == nil {
+		return nil
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil
+	}
+	return data
+}
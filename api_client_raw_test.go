@@ -0,0 +1,91 @@
+package cwe
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIClientRetainRaw(t *testing.T) {
+	client := newFieldMaskTestClient(t, `{"weaknesses": [{"id": "79", "name": "XSS", "TaxonomyMappings": [{"TaxonomyName": "OWASP"}], "demonstrative_examples": [{"code": "x"}]}]}`)
+	client.SetFieldMask(FieldsMinimal)
+
+	weakness, err := client.GetWeakness("79")
+	if err != nil {
+		t.Fatalf("GetWeakness失败: %v", err)
+	}
+	if weakness.RawData != nil {
+		t.Error("默认不应保留原始数据")
+	}
+
+	client.SetRetainRaw(true)
+	if !client.GetRetainRaw() {
+		t.Fatal("GetRetainRaw应返回true")
+	}
+	weakness, err = client.GetWeakness("79")
+	if err != nil {
+		t.Fatalf("GetWeakness失败: %v", err)
+	}
+	if _, ok := weakness.RawData["TaxonomyMappings"]; !ok {
+		t.Errorf("RawData应包含模型中没有的字段，实际为%v", weakness.RawData)
+	}
+	if _, ok := weakness.RawData["demonstrative_examples"]; !ok || weakness.DemonstrativeExamples != nil {
+		t.Errorf("RawData不受字段掩码影响，解码的字段仍受掩码影响")
+	}
+
+	cwes := newFieldMaskTestClient(t, `{"cwes": {"CWE-79": {"id": "79", "name": "XSS", "Extra": 1}}}`)
+	cwes.SetRetainRaw(true)
+	result, err := cwes.GetCWEs([]string{"79"})
+	if err != nil {
+		t.Fatalf("GetCWEs失败: %v", err)
+	}
+	if result["CWE-79"].RawData["Extra"] != float64(1) {
+		t.Errorf("GetCWEs应保留每个条目的原始数据，实际为%v", result["CWE-79"].RawData)
+	}
+}
+
+func TestDataFetcherRetainRaw(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cwe/weakness/CWE-79", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"weaknesses": [{"id": "79", "name": "XSS", "NewField": "value"}]}`))
+	})
+	mux.HandleFunc("/cwe/view/CWE-1000", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"views": [{"id": "1000", "name": "Research Concepts", "Type": "Graph"}]}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	fetcher := NewDataFetcherWithClient(NewAPIClientWithOptions(server.URL, DefaultTimeout, NewHTTPRateLimiter(0)))
+
+	entry, err := fetcher.FetchWeakness("79")
+	if err != nil {
+		t.Fatalf("FetchWeakness失败: %v", err)
+	}
+	if entry.Raw() != nil {
+		t.Error("默认不应保留原始JSON")
+	}
+
+	fetcher.SetRetainRaw(true)
+	entry, err = fetcher.FetchWeakness("79")
+	if err != nil {
+		t.Fatalf("FetchWeakness失败: %v", err)
+	}
+	var extra struct {
+		NewField string `json:"NewField"`
+	}
+	if err := json.Unmarshal(entry.Raw(), &extra); err != nil || extra.NewField != "value" {
+		t.Errorf("Raw应返回条目的原始JSON，实际为%s", entry.Raw())
+	}
+	if clone := entry.Clone(false); string(clone.Raw()) != string(entry.Raw()) {
+		t.Error("Clone应复制原始JSON")
+	}
+
+	view, err := fetcher.FetchView("1000")
+	if err != nil {
+		t.Fatalf("FetchView失败: %v", err)
+	}
+	if view.Raw() == nil {
+		t.Error("FetchView获取的条目也应保留原始JSON")
+	}
+}
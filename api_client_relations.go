@@ -1,11 +1,11 @@
 package cwe
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 )
 
 // GetParents 获取特定CWE的父节点
@@ -63,12 +63,12 @@ import (
 // - API文档: https://github.com/CWE-CAPEC/REST-API-wg/blob/main/Quick%20Start.md
 // - 相关方法: GetChildren(), GetAncestors(), GetDescendants()
 func (c *APIClient) GetParents(id string, viewID string) ([]string, error) {
-	url := fmt.Sprintf("%s/cwe/%s/parents", c.baseURL, id)
+	url := fmt.Sprintf("%s/cwe/%s/parents", c.apiBaseURL(), id)
 	if viewID != "" {
 		url = fmt.Sprintf("%s?view=%s", url, viewID)
 	}
 
-	resp, err := c.client.Get(context.Background(), url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, fmt.Errorf("获取父节点失败: %w", err)
 	}
@@ -83,8 +83,8 @@ func (c *APIClient) GetParents(id string, viewID string) ([]string, error) {
 		return nil, fmt.Errorf("读取响应体失败: %w", err)
 	}
 
-	var result []string
-	if err := json.Unmarshal(body, &result); err != nil {
+	result, err := parseIDList(body)
+	if err != nil {
 		return nil, fmt.Errorf("解析JSON响应失败: %w", err)
 	}
 
@@ -146,31 +146,26 @@ func (c *APIClient) GetParents(id string, viewID string) ([]string, error) {
 // - API文档: https://github.com/CWE-CAPEC/REST-API-wg/blob/main/Quick%20Start.md
 // - 相关方法: GetParents(), GetAncestors(), GetDescendants()
 func (c *APIClient) GetChildren(id string, viewID string) ([]string, error) {
-	url := fmt.Sprintf("%s/cwe/%s/children", c.baseURL, id)
+	url := fmt.Sprintf("%s/cwe/%s/children", c.apiBaseURL(), id)
+	cacheID := normalizeEntryID(id)
 	if viewID != "" {
 		url = fmt.Sprintf("%s?view=%s", url, viewID)
+		cacheID += "@" + normalizeEntryID(viewID)
 	}
 
-	resp, err := c.client.Get(context.Background(), url)
+	body, _, cached, err := c.fetchEntry(cacheKindChildren, cacheID, url, "获取子节点失败")
 	if err != nil {
-		return nil, fmt.Errorf("获取子节点失败: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API请求失败，状态码: %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
+	result, err := parseIDList(body)
 	if err != nil {
-		return nil, fmt.Errorf("读取响应体失败: %w", err)
-	}
-
-	var result []string
-	if err := json.Unmarshal(body, &result); err != nil {
 		return nil, fmt.Errorf("解析JSON响应失败: %w", err)
 	}
 
+	if !cached {
+		c.storeEntry(cacheKindChildren, cacheID, body, "")
+	}
 	return result, nil
 }
 
@@ -229,12 +224,12 @@ func (c *APIClient) GetChildren(id string, viewID string) ([]string, error) {
 // - API文档: https://github.com/CWE-CAPEC/REST-API-wg/blob/main/Quick%20Start.md
 // - 相关方法: GetParents(), GetChildren(), GetDescendants()
 func (c *APIClient) GetAncestors(id string, viewID string) ([]string, error) {
-	url := fmt.Sprintf("%s/cwe/%s/ancestors", c.baseURL, id)
+	url := fmt.Sprintf("%s/cwe/%s/ancestors", c.apiBaseURL(), id)
 	if viewID != "" {
 		url = fmt.Sprintf("%s?view=%s", url, viewID)
 	}
 
-	resp, err := c.client.Get(context.Background(), url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, fmt.Errorf("获取祖先节点失败: %w", err)
 	}
@@ -314,12 +309,12 @@ func (c *APIClient) GetAncestors(id string, viewID string) ([]string, error) {
 // - API文档: https://github.com/CWE-CAPEC/REST-API-wg/blob/main/Quick%20Start.md
 // - 相关方法: GetParents(), GetChildren(), GetAncestors()
 func (c *APIClient) GetDescendants(id string, viewID string) ([]string, error) {
-	url := fmt.Sprintf("%s/cwe/%s/descendants", c.baseURL, id)
+	url := fmt.Sprintf("%s/cwe/%s/descendants", c.apiBaseURL(), id)
 	if viewID != "" {
 		url = fmt.Sprintf("%s?view=%s", url, viewID)
 	}
 
-	resp, err := c.client.Get(context.Background(), url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, fmt.Errorf("获取后代节点失败: %w", err)
 	}
@@ -341,3 +336,166 @@ func (c *APIClient) GetDescendants(id string, viewID string) ([]string, error) {
 
 	return result, nil
 }
+
+// relationEntry 是官方API返回的关系列表中的单个元素
+type relationEntry struct {
+	ID string `json:"ID"`
+}
+
+// parseIDList 解析关系接口返回的ID列表
+// 同时支持字符串数组(如["CWE-74"])和官方API的对象数组(如[{"Type":"weakness","ID":"74",...}])
+func parseIDList(body []byte) ([]string, error) {
+	var ids []string
+	if err := json.Unmarshal(body, &ids); err == nil {
+		return ids, nil
+	}
+
+	var entries []relationEntry
+	if err := json.Unmarshal(body, &entries); err != nil {
+		return nil, err
+	}
+
+	ids = make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.ID != "" {
+			ids = append(ids, entry.ID)
+		}
+	}
+	return ids, nil
+}
+
+// GetRelations 获取特定CWE与其他CWE的类型化关系
+//
+// 方法功能:
+// 从弱点详情中读取关系列表(related_weaknesses)，返回包含关系性质、目标和视图的CWERelation。
+// 与GetParents/GetChildren只返回ID不同，结果保留了ChildOf、CanPrecede、PeerOf等关系性质，
+// 可以区分层次关系和其他关系。结果不受SetFieldMask影响。
+//
+// 参数:
+// - id: string - 要查询的CWE ID，格式应为"CWE-数字"或纯数字(如"CWE-79"或"79")
+// - viewID: string - 可选的视图ID，只返回该视图中的关系。如不需要，可传入空字符串
+//
+// 返回值:
+// - []CWERelation: 关系列表，CweID和ViewID均规范化为"CWE-数字"格式；条目没有关系时返回空切片
+// - error: 如遇到网络问题、API返回非200状态码或响应解析错误时返回相应错误
+//
+// 错误处理:
+// - 网络连接失败: 返回"获取关系失败: <原始错误>"
+// - API返回非200状态码: 返回"API请求失败，状态码: <状态码>"
+// - 响应解析失败: 返回"解析JSON响应失败: <原始错误>"
+// - 响应中没有弱点: 返回"响应中不包含弱点信息"，类别和视图没有关系列表
+//
+// 使用示例:
+// ```go
+// client := cwe.NewAPIClient()
+// relations, err := client.GetRelations("79", "1000")
+//
+//	if err != nil {
+//	    log.Fatalf("获取关系失败: %v", err)
+//	}
+//
+//	for _, rel := range relations {
+//	    fmt.Printf("%s %s (视图%s)\n", rel.Nature, rel.CweID, rel.ViewID)
+//	}
+//
+// ```
+//
+// 数据样例:
+// - 请求: id = "79", viewID = "1000"
+// - 返回值: [{Nature: "ChildOf", CweID: "CWE-74", ViewID: "CWE-1000", Ordinal: "Primary"}]
+//
+// 相关信息:
+// - 相关方法: GetParents(), GetChildren(), DataFetcher.PopulateRelations()
+func (c *APIClient) GetRelations(id string, viewID string) ([]CWERelation, error) {
+	url := fmt.Sprintf("%s/cwe/weakness/%s", c.apiBaseURL(), id)
+
+	resp, err := c.get(url)
+	if err != nil {
+		return nil, fmt.Errorf("获取关系失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API请求失败，状态码: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应体失败: %w", err)
+	}
+
+	// 只需要关系列表，不使用字段掩码，避免关系字段被丢弃
+	var weaknessResp relationsResponse
+	if err := json.Unmarshal(body, &weaknessResp); err != nil {
+		return nil, fmt.Errorf("解析JSON响应失败: %w", err)
+	}
+	if len(weaknessResp.Weaknesses) == 0 {
+		return nil, fmt.Errorf("响应中不包含弱点信息")
+	}
+
+	weakness := weaknessResp.Weaknesses[0]
+	relations := normalizeRelations(append(weakness.RelatedWeaknesses, weakness.PascalRelatedWeaknesses...))
+	if viewID == "" {
+		return relations, nil
+	}
+
+	view := normalizeEntryID(viewID)
+	filtered := make([]CWERelation, 0, len(relations))
+	for _, rel := range relations {
+		if rel.ViewID == view {
+			filtered = append(filtered, rel)
+		}
+	}
+	return filtered, nil
+}
+
+// relationsResponse 是GetRelations解码弱点响应时使用的结构
+// 同时接受snake_case的"related_weaknesses"和官方API的"RelatedWeaknesses"
+type relationsResponse struct {
+	Weaknesses []struct {
+		RelatedWeaknesses       []CWERelation `json:"related_weaknesses"`
+		PascalRelatedWeaknesses []CWERelation `json:"RelatedWeaknesses"`
+	} `json:"weaknesses"`
+}
+
+// UnmarshalJSON 解码关系，同时支持snake_case键(如"cwe_id")和官方API的PascalCase键(如"CweID")
+func (r *CWERelation) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	targets := map[string]*string{
+		"nature":  &r.Nature,
+		"cweid":   &r.CweID,
+		"viewid":  &r.ViewID,
+		"ordinal": &r.Ordinal,
+	}
+	for key, raw := range fields {
+		target, exists := targets[strings.ToLower(strings.ReplaceAll(key, "_", ""))]
+		if !exists {
+			continue
+		}
+		if err := json.Unmarshal(raw, target); err != nil {
+			return fmt.Errorf("解析关系字段%s失败: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// normalizeRelations 复制关系列表，并将CweID和ViewID规范化为"CWE-数字"格式
+// 没有目标的关系会被跳过
+func normalizeRelations(relations []CWERelation) []CWERelation {
+	normalized := make([]CWERelation, 0, len(relations))
+	for _, rel := range relations {
+		if rel.CweID == "" {
+			continue
+		}
+		rel.CweID = normalizeEntryID(rel.CweID)
+		if rel.ViewID != "" {
+			rel.ViewID = normalizeEntryID(rel.ViewID)
+		}
+		normalized = append(normalized, rel)
+	}
+	return normalized
+}
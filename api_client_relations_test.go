@@ -339,3 +339,116 @@ func TestGetViewRelationsWithErrors(t *testing.T) {
 		t.Error("Expected error for view relations")
 	}
 }
+
+// TestGetRelations 测试GetRelations解析官方API和snake_case两种关系格式
+func TestGetRelations(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cwe/weakness/79", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Weaknesses": [{"ID": "79", "Name": "XSS", "RelatedWeaknesses": [
+			{"Nature": "ChildOf", "CweID": "74", "ViewID": "1000", "Ordinal": "Primary"},
+			{"Nature": "ChildOf", "CweID": "74", "ViewID": "699", "Ordinal": "Primary"},
+			{"Nature": "PeerOf", "CweID": "352", "ViewID": "1000"},
+			{"Nature": "ChildOf", "ViewID": "1000"}
+		]}]}`))
+	})
+	mux.HandleFunc("/cwe/weakness/89", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"weaknesses": [{"id": "CWE-89", "name": "SQLi", "related_weaknesses": [
+			{"nature": "ChildOf", "cwe_id": "CWE-943", "view_id": "CWE-1000"}
+		]}]}`))
+	})
+	mux.HandleFunc("/cwe/weakness/1000", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Weaknesses": []}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewAPIClientWithOptions(server.URL, DefaultTimeout, NewHTTPRateLimiter(0))
+	client.SetFieldMask(FieldsMinimal)
+
+	relations, err := client.GetRelations("79", "")
+	if err != nil {
+		t.Fatalf("GetRelations失败: %v", err)
+	}
+	expected := []CWERelation{
+		{Nature: "ChildOf", CweID: "CWE-74", ViewID: "CWE-1000", Ordinal: "Primary"},
+		{Nature: "ChildOf", CweID: "CWE-74", ViewID: "CWE-699", Ordinal: "Primary"},
+		{Nature: "PeerOf", CweID: "CWE-352", ViewID: "CWE-1000"},
+	}
+	if !reflect.DeepEqual(relations, expected) {
+		t.Errorf("关系不符合预期:\n got: %+v\nwant: %+v", relations, expected)
+	}
+
+	relations, err = client.GetRelations("79", "699")
+	if err != nil {
+		t.Fatalf("GetRelations失败: %v", err)
+	}
+	if len(relations) != 1 || relations[0].ViewID != "CWE-699" {
+		t.Errorf("按视图过滤的结果不正确: %+v", relations)
+	}
+
+	relations, err = client.GetRelations("89", "CWE-1000")
+	if err != nil {
+		t.Fatalf("GetRelations失败: %v", err)
+	}
+	if !reflect.DeepEqual(relations, []CWERelation{{Nature: "ChildOf", CweID: "CWE-943", ViewID: "CWE-1000"}}) {
+		t.Errorf("snake_case关系解析不正确: %+v", relations)
+	}
+
+	if _, err := client.GetRelations("1000", ""); err == nil {
+		t.Error("响应中没有弱点时应返回错误")
+	}
+	if _, err := client.GetRelations("404", ""); err == nil {
+		t.Error("非200状态码应返回错误")
+	}
+}
+
+// TestPopulateRelations 测试获取弱点时填充Relations以及单独补充关系
+func TestPopulateRelations(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cwe/weakness/CWE-79", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"weaknesses": [{"id": "79", "name": "XSS", "related_weaknesses": [
+			{"nature": "ChildOf", "cwe_id": "74", "view_id": "1000"}
+		]}]}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewAPIClientWithOptions(server.URL, DefaultTimeout, NewHTTPRateLimiter(0))
+	fetcher := NewDataFetcherWithClient(client)
+
+	entry, err := fetcher.FetchWeakness("79")
+	if err != nil {
+		t.Fatalf("FetchWeakness失败: %v", err)
+	}
+	expected := []CWERelation{{Nature: "ChildOf", CweID: "CWE-74", ViewID: "CWE-1000"}}
+	if !reflect.DeepEqual(entry.Relations, expected) {
+		t.Errorf("FetchWeakness应填充Relations，实际为%+v", entry.Relations)
+	}
+
+	// 丢弃关系字段后需要单独补充
+	fetcher.SetFieldMask(FieldsMinimal)
+	entry, err = fetcher.FetchWeakness("79")
+	if err != nil {
+		t.Fatalf("FetchWeakness失败: %v", err)
+	}
+	if len(entry.Relations) != 0 {
+		t.Fatalf("丢弃关系字段后Relations应为空，实际为%+v", entry.Relations)
+	}
+	if err := fetcher.PopulateRelations(entry, ""); err != nil {
+		t.Fatalf("PopulateRelations失败: %v", err)
+	}
+	if !reflect.DeepEqual(entry.Relations, expected) {
+		t.Errorf("PopulateRelations后Relations不正确: %+v", entry.Relations)
+	}
+
+	missing := NewCWE("CWE-80", "missing")
+	if err := fetcher.PopulateRelations(missing, ""); err == nil {
+		t.Error("获取失败时应返回错误")
+	}
+	if missing.Relations != nil {
+		t.Error("获取失败时Relations应保持不变")
+	}
+}
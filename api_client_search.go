@@ -0,0 +1,99 @@
+package cwe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ErrSearchUnsupported 表示API服务器不提供关键词搜索接口
+// MITRE的官方API目前没有搜索接口，server包提供的内部镜像支持搜索
+var ErrSearchUnsupported = errors.New("API不支持关键词搜索")
+
+// searchResponse 是/search接口的响应，与server.SearchResponse的格式相同
+type searchResponse struct {
+	Total      int            `json:"total"`
+	Results    []*CWEWeakness `json:"results"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+// Search 通过API的/search接口按关键词搜索条目
+//
+// 方法功能:
+// 请求服务器端搜索并沿next_cursor获取所有分页，返回所有匹配的条目。
+// 服务器对/search返回404、405或501时认为不支持搜索，返回ErrSearchUnsupported，
+// 调用方可以据此改为在本地注册表中搜索；DataFetcher.Search已经实现了这种回退。
+//
+// 参数:
+// - query: string - 关键词
+//
+// 返回值:
+// - []*CWEWeakness: 所有匹配的条目，顺序与服务器返回的相同
+// - error: 服务器不支持搜索时返回ErrSearchUnsupported，网络问题、其他非200状态码或响应解析失败时返回相应错误
+//
+// 使用示例:
+// ```go
+// client := cwe.NewAPIClientWithOptions("http://cwe-mirror.internal:8080", cwe.DefaultTimeout)
+// results, err := client.Search("xss")
+//
+//	if errors.Is(err, cwe.ErrSearchUnsupported) {
+//	    // 改为在本地注册表中搜索
+//	}
+//
+// ```
+func (c *APIClient) Search(query string) ([]*CWEWeakness, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(MaxSearchLimit))
+
+	results := make([]*CWEWeakness, 0)
+	for {
+		page, err := c.searchPage(params)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, page.Results...)
+		if page.NextCursor == "" || len(page.Results) == 0 {
+			return results, nil
+		}
+		params.Set("cursor", page.NextCursor)
+	}
+}
+
+// searchPage 请求一页搜索结果
+func (c *APIClient) searchPage(params url.Values) (*searchResponse, error) {
+	resp, err := c.get(c.apiBaseURL() + "/search?" + params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("搜索CWE失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return nil, fmt.Errorf("%w，状态码: %d", ErrSearchUnsupported, resp.StatusCode)
+	default:
+		return nil, fmt.Errorf("API请求失败，状态码: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应体失败: %w", err)
+	}
+
+	var page searchResponse
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, fmt.Errorf("解析JSON响应失败: %w", err)
+	}
+	language := responseLanguage(resp)
+	for _, weakness := range page.Results {
+		if weakness != nil {
+			weakness.Language = language
+		}
+	}
+	return &page, nil
+}
@@ -638,3 +638,59 @@ func contains(slice []string, s string) bool {
 	}
 	return false
 }
+
+func TestAPIClientLanguage(t *testing.T) {
+	var receivedLanguage string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedLanguage = r.Header.Get("Accept-Language")
+		w.Header().Set("Content-Type", "application/json")
+		if receivedLanguage != "" {
+			w.Header().Set("Content-Language", "zh-CN")
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"weaknesses": []map[string]interface{}{
+				{"id": "CWE-79", "name": "跨站脚本"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewAPIClientWithOptions(server.URL, DefaultTimeout, NewHTTPRateLimiter(0))
+
+	// 未设置语言时不发送请求头，记录为默认语言
+	weakness, err := client.GetWeakness("79")
+	if err != nil {
+		t.Fatalf("GetWeakness failed: %v", err)
+	}
+	if receivedLanguage != "" {
+		t.Errorf("Expected no Accept-Language header, got %q", receivedLanguage)
+	}
+	if weakness.Language != DefaultLanguage {
+		t.Errorf("Expected language %s, got %s", DefaultLanguage, weakness.Language)
+	}
+
+	client.SetLanguage("zh-CN")
+	if client.GetLanguage() != "zh-CN" {
+		t.Errorf("Expected language zh-CN, got %s", client.GetLanguage())
+	}
+
+	weakness, err = client.GetWeakness("79")
+	if err != nil {
+		t.Fatalf("GetWeakness failed: %v", err)
+	}
+	if receivedLanguage != "zh-CN, en;q=0.5" {
+		t.Errorf("Expected Accept-Language with English fallback, got %q", receivedLanguage)
+	}
+	if weakness.Language != "zh-CN" {
+		t.Errorf("Expected language zh-CN, got %s", weakness.Language)
+	}
+
+	fetcher := NewDataFetcherWithClient(client)
+	cwe, err := fetcher.FetchWeakness("79")
+	if err != nil {
+		t.Fatalf("FetchWeakness failed: %v", err)
+	}
+	if cwe.Language != "zh-CN" {
+		t.Errorf("Expected fetched CWE language zh-CN, got %s", cwe.Language)
+	}
+}
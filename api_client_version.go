@@ -1,7 +1,6 @@
 package cwe
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -40,14 +39,20 @@ import (
 //
 // 数据样例:
 // - 成功响应: {"version":"4.12","release_date":"2023-02-28"}
+// - 官方API响应: {"ContentVersion":"4.14","ContentDate":"2024-02-29",...}
 // - 返回值: "4.12"
 //
 // 相关信息:
 // - API文档: https://github.com/CWE-CAPEC/REST-API-wg/blob/main/Quick%20Start.md
 func (c *APIClient) GetVersion() (*VersionResponse, error) {
-	url := fmt.Sprintf("%s/cwe/version", c.baseURL)
+	return c.getVersion(c.apiBaseURL())
+}
+
+// getVersion 从指定的基础URL获取版本信息，协商API版本时用于探测候选地址
+func (c *APIClient) getVersion(baseURL string) (*VersionResponse, error) {
+	url := fmt.Sprintf("%s/cwe/version", baseURL)
 
-	resp, err := c.client.Get(context.Background(), url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, fmt.Errorf("获取CWE版本失败: %w", err)
 	}
@@ -84,6 +89,14 @@ func (c *APIClient) GetVersion() (*VersionResponse, error) {
 		}
 	}
 
+	// 官方API使用ContentVersion/ContentDate字段
+	if versionResp.Version == "" {
+		versionResp.Version = versionResp.ContentVersion
+	}
+	if versionResp.ReleaseDate == "" {
+		versionResp.ReleaseDate = versionResp.ContentDate
+	}
+
 	if versionResp.Version == "" {
 		return nil, fmt.Errorf("响应中没有找到版本信息")
 	}
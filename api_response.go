@@ -1,5 +1,7 @@
 package cwe
 
+import "encoding/json"
+
 // APIResponse 通用API响应结构
 // 这个结构体为所有API响应提供基础字段
 type APIResponse struct {
@@ -48,6 +50,9 @@ type CWEWeakness struct {
 	// RelatedWeaknesses 相关弱点关系列表
 	RelatedWeaknesses []CWERelation `json:"related_weaknesses,omitempty"`
 
+	// RelatedAttackPatterns 相关CAPEC攻击模式的编号列表，如["66", "7"]
+	RelatedAttackPatterns []string `json:"related_attack_patterns,omitempty"`
+
 	// CommonConsequences 常见影响
 	CommonConsequences []CWEConsequence `json:"common_consequences,omitempty"`
 
@@ -69,11 +74,21 @@ type CWEWeakness struct {
 	// ObservedExamples 已观察到的实例
 	ObservedExamples []CWEObservedExample `json:"observed_examples,omitempty"`
 
+	// MappingNotes 映射注释
+	MappingNotes *CWEMappingNotes `json:"mapping_notes,omitempty"`
+
 	// ContentHistory 内容历史
 	ContentHistory []CWEContentHistoryEntry `json:"content_history,omitempty"`
 
-	// 原始数据，保存未明确映射的字段
+	// Language 内容语言，取自响应的Content-Language头，未声明时为"en"
+	Language string `json:"-"`
+
+	// RawData 条目的原始JSON对象，包括模型中没有的字段
+	// 只在启用APIClient.SetRetainRaw或GetCWEs无法识别响应格式时填充，不受字段掩码影响
 	RawData map[string]interface{} `json:"-"`
+
+	// raw 条目的原始JSON，与RawData同时填充，DataFetcher将其保存到CWE中
+	raw json.RawMessage
 }
 
 // CWECategory 表示CWE分类条目的结构体
@@ -102,8 +117,15 @@ type CWECategory struct {
 	// ContentHistory 内容历史
 	ContentHistory []CWEContentHistoryEntry `json:"content_history,omitempty"`
 
-	// 原始数据，保存未明确映射的字段
+	// Language 内容语言，取自响应的Content-Language头，未声明时为"en"
+	Language string `json:"-"`
+
+	// RawData 条目的原始JSON对象，包括模型中没有的字段
+	// 只在启用APIClient.SetRetainRaw或GetCWEs无法识别响应格式时填充，不受字段掩码影响
 	RawData map[string]interface{} `json:"-"`
+
+	// raw 条目的原始JSON，与RawData同时填充，DataFetcher将其保存到CWE中
+	raw json.RawMessage
 }
 
 // CWEView 表示CWE视图条目的结构体
@@ -144,8 +166,15 @@ type CWEView struct {
 	// ContentHistory 内容历史
 	ContentHistory []CWEContentHistoryEntry `json:"content_history,omitempty"`
 
-	// 原始数据，保存未明确映射的字段
+	// Language 内容语言，取自响应的Content-Language头，未声明时为"en"
+	Language string `json:"-"`
+
+	// RawData 条目的原始JSON对象，包括模型中没有的字段
+	// 只在启用APIClient.SetRetainRaw或GetCWEs无法识别响应格式时填充，不受字段掩码影响
 	RawData map[string]interface{} `json:"-"`
+
+	// raw 条目的原始JSON，与RawData同时填充，DataFetcher将其保存到CWE中
+	raw json.RawMessage
 }
 
 // CWERelation 表示CWE间关系的结构体
@@ -293,7 +322,7 @@ type CWEViewMember struct {
 
 // CWEMappingNotes 表示CWE映射注释
 type CWEMappingNotes struct {
-	// Usage 使用情况
+	// Usage 是否允许将漏洞映射到该条目，取值见MappingAllowed等常量
 	Usage string `json:"usage,omitempty"`
 
 	// Rationale 理由
@@ -334,10 +363,15 @@ type ViewResponse struct {
 }
 
 // VersionResponse 表示API返回的版本响应
+// 官方API使用ContentVersion/ContentDate字段，GetVersion会将其回填到Version/ReleaseDate
 type VersionResponse struct {
 	APIResponse
 	Version     string `json:"version,omitempty"`
 	ReleaseDate string `json:"release_date,omitempty"`
+
+	// ContentVersion/ContentDate 官方API返回的版本号和发布日期
+	ContentVersion string `json:"ContentVersion,omitempty"`
+	ContentDate    string `json:"ContentDate,omitempty"`
 }
 
 // CWEsResponse 表示API返回的多个CWE响应
@@ -312,7 +312,7 @@ func TestPopulateTree(t *testing.T) {
 	registry.Register(root)
 
 	// 测试populateTree方法
-	err := fetcher.populateTree(registry, root, "1000")
+	err := fetcher.populateTree(registry, root, "1000", nil, &MultiError{})
 	if err != nil {
 		t.Errorf("populateTree failed: %v", err)
 	}
@@ -329,7 +329,7 @@ func TestPopulateTree(t *testing.T) {
 
 	// 测试错误处理 - 使用错误ID
 	errorNode := NewCWE("CWE-error", "Error Node")
-	err = fetcher.populateTree(registry, errorNode, "1000")
+	err = fetcher.populateTree(registry, errorNode, "1000", nil, &MultiError{})
 	if err == nil {
 		t.Error("populateTree should fail with error node")
 	}
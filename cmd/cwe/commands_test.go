@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scagogogo/cwe"
+	"github.com/scagogogo/cwe/cwetest"
+)
+
+// testSnapshot CWE-1000 -> CWE-20 -> {CWE-79, CWE-89}的快照
+const testSnapshot = `{"root_id": "CWE-1000", "entries": [
+	{"id": "CWE-1000", "name": "Research Concepts", "children": ["CWE-20"]},
+	{"id": "CWE-20", "name": "Improper Input Validation", "children": ["CWE-89", "CWE-79"]},
+	{"id": "CWE-79", "name": "XSS", "severity": "High"},
+	{"id": "CWE-89", "name": "SQL Injection"}
+]}`
+
+// captureStdout 执行命令并返回其标准输出
+func captureStdout(t *testing.T, run func([]string) error, args ...string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	stdout = &buf
+	defer func() { stdout = os.Stdout }()
+
+	if err := run(args); err != nil {
+		t.Fatalf("命令执行失败: %v", err)
+	}
+	return buf.String()
+}
+
+// writeTestFile 在临时目录中写入文件并返回路径
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunTree(t *testing.T) {
+	snapshot := writeTestFile(t, "snapshot.json", testSnapshot)
+
+	expected := "CWE-1000 Research Concepts\n  CWE-20 Improper Input Validation\n    CWE-79 XSS\n    CWE-89 SQL Injection\n"
+	if got := captureStdout(t, runTree, "-i", snapshot); got != expected {
+		t.Errorf("文本树不正确:\n%s", got)
+	}
+	if got := captureStdout(t, runTree, "-i", snapshot, "-depth", "1", "20"); got != "CWE-20 Improper Input Validation\n  CWE-79 XSS\n  CWE-89 SQL Injection\n" {
+		t.Errorf("子树不正确:\n%s", got)
+	}
+	// 带CWE内容版本的快照不应被当作导出文件
+	versioned := writeTestFile(t, "versioned.json", `{"version": "4.16", "root_id": "CWE-20", "entries": [
+		{"id": "CWE-20", "name": "Improper Input Validation", "version": "4.16"}
+	]}`)
+	if got := captureStdout(t, runTree, "-i", versioned); got != "CWE-20 Improper Input Validation\n" {
+		t.Errorf("带版本的快照读取不正确:\n%s", got)
+	}
+	if got := captureStdout(t, runTree, "-i", snapshot, "-format", "mermaid"); !strings.Contains(got, "CWE_20 --> CWE_79") {
+		t.Errorf("Mermaid输出不正确:\n%s", got)
+	}
+	if err := runTree([]string{"-i", snapshot, "CWE-87"}); err == nil || !strings.Contains(err.Error(), "CWE-89") {
+		t.Errorf("条目不存在时应建议相近的ID: %v", err)
+	}
+	if err := runTree([]string{"-i", snapshot, "-format", "png"}); err == nil {
+		t.Error("不支持的格式应返回错误")
+	}
+}
+
+func TestRunTreeFetchesView(t *testing.T) {
+	srv := cwetest.NewServer()
+	defer srv.Close()
+	srv.RegisterView(&cwe.CWEView{ID: "1000", Name: "Research Concepts"})
+	srv.RegisterWeakness(&cwe.CWEWeakness{ID: "20", Name: "Improper Input Validation"})
+	srv.RegisterWeakness(&cwe.CWEWeakness{ID: "79", Name: "XSS"})
+	srv.RegisterChildren("1000", "1000", "20")
+	srv.RegisterChildren("20", "1000", "79")
+
+	// 未指定-i时从API构建视图，默认视图为1000
+	expected := "CWE-1000 Research Concepts\n  CWE-20 Improper Input Validation\n    CWE-79 XSS\n"
+	if got := captureStdout(t, runTree, "-base-url", srv.URL, "-interval", "0"); got != expected {
+		t.Errorf("视图树输出不正确:\n%s", got)
+	}
+	if got := captureStdout(t, runTree, "-base-url", srv.URL, "-interval", "0", "-depth", "1", "CWE-1000"); got != "CWE-1000 Research Concepts\n  CWE-20 Improper Input Validation\n" {
+		t.Errorf("深度限制输出不正确:\n%s", got)
+	}
+	if err := runTree([]string{"-base-url", srv.URL, "-interval", "0", "699"}); err == nil {
+		t.Error("视图不存在时应返回错误")
+	}
+}
+
+func TestRunSearch(t *testing.T) {
+	snapshot := writeTestFile(t, "snapshot.json", testSnapshot)
+
+	if got := captureStdout(t, runSearch, "-i", snapshot, "injection"); got != "CWE-89\tSQL Injection\n" {
+		t.Errorf("搜索结果不正确: %q", got)
+	}
+	// 默认使用内置的离线数据
+	if got := captureStdout(t, runSearch, "Cross-site"); !strings.HasPrefix(got, "CWE-79\t") {
+		t.Errorf("离线数据的搜索结果不正确: %q", got)
+	}
+	// 没有结果时输出拼写相近的条目
+	if got := captureStdout(t, runSearch, "-i", snapshot, "SQL Injecton"); got != "CWE-89\tSQL Injection\n" {
+		t.Errorf("建议不正确: %q", got)
+	}
+	if err := runSearch([]string{"-i", snapshot}); err == nil {
+		t.Error("缺少关键词时应返回错误")
+	}
+}
+
+func TestRunExportAndDiff(t *testing.T) {
+	snapshot := writeTestFile(t, "snapshot.json", testSnapshot)
+	dir := t.TempDir()
+
+	// 导出为各种格式后再读取，内容应与原快照相同
+	for _, format := range []string{"json", "bin", "xml", "csv"} {
+		path := filepath.Join(dir, "export."+format)
+		captureStdout(t, runExport, "-i", snapshot, "-format", format, "-o", path)
+		if got := captureStdout(t, runDiff, snapshot, path); got != "没有差异\n" {
+			t.Errorf("%s导出后应没有差异:\n%s", format, got)
+		}
+	}
+
+	if got := captureStdout(t, runExport, "-i", snapshot, "-format", "dot", "-root", "CWE-20"); !strings.Contains(got, `"CWE-20" -> "CWE-79";`) || strings.Contains(got, "CWE-1000") {
+		t.Errorf("DOT输出不正确:\n%s", got)
+	}
+	if err := runExport([]string{"-i", snapshot, "-format", "yaml"}); err == nil {
+		t.Error("不支持的格式应返回错误")
+	}
+
+	edited := writeTestFile(t, "edited.csv", "ID,Name,Severity,ParentID\n"+
+		"CWE-1000,Research Concepts,,\n"+
+		"CWE-20,Improper Input Validation,,CWE-1000\n"+
+		"CWE-79,XSS,Medium,CWE-1000\n"+
+		"CWE-352,CSRF,,CWE-20\n")
+	expected := "~ CWE-79 XSS: Severity, Parent\n- CWE-89 SQL Injection\n+ CWE-352 CSRF\n"
+	if got := captureStdout(t, runDiff, snapshot, edited); got != expected {
+		t.Errorf("差异不正确:\n%s", got)
+	}
+}
+
+func TestRunFetchEntries(t *testing.T) {
+	srv := cwetest.NewServer()
+	defer srv.Close()
+	srv.RegisterWeakness(&cwe.CWEWeakness{ID: "79", Name: "XSS", Description: "desc"})
+	srv.RegisterCategory(&cwe.CWECategory{ID: "699", Name: "Software Development"})
+
+	got := captureStdout(t, runFetch, "-base-url", srv.URL, "-interval", "0", "79")
+	if !strings.Contains(got, `"id":"CWE-79"`) || !strings.Contains(got, `"description":"desc"`) {
+		t.Errorf("JSON输出不正确: %s", got)
+	}
+
+	got = captureStdout(t, runFetch, "-base-url", srv.URL, "-interval", "0", "-format", "markdown", "79", "CWE-699")
+	if !strings.HasPrefix(got, "## CWE-79: XSS\n") || !strings.Contains(got, "\n## CWE-699: Software Development\n") {
+		t.Errorf("Markdown输出不正确:\n%s", got)
+	}
+
+	srv.RegisterWeakness(&cwe.CWEWeakness{ID: "80", Name: "Basic XSS"})
+	got = captureStdout(t, runFetch, "-base-url", srv.URL, "-interval", "0", "CWE-79..80")
+	if strings.Count(got, "\n") != 2 || !strings.Contains(got, `"id":"CWE-80"`) {
+		t.Errorf("ID范围应展开为每个条目: %s", got)
+	}
+
+	if err := runFetch([]string{"-base-url", srv.URL, "79", "xss"}); err == nil || !strings.Contains(err.Error(), "第2项") {
+		t.Errorf("无效ID应报告位置，实际 %v", err)
+	}
+}
+
+func TestFetchEntriesAcceptsStaleData(t *testing.T) {
+	// API不可用时使用存储中的条目
+	client := cwe.NewAPIClientWithOptions("http://127.0.0.1:1", cwe.DefaultTimeout, cwe.NewHTTPRateLimiter(0))
+	client.GetHTTPClient().SetRetryDelay(time.Millisecond)
+	fetcher := cwe.NewDataFetcherWithClient(client)
+	store := cwe.NewMemoryStore()
+	store.Put(cwe.NewCWE("CWE-79", "Stored XSS"))
+	fetcher.SetStore(store, cwe.StoreWriteOnly)
+
+	if err := fetchEntries(fetcher, []string{"79"}, "json"); err == nil {
+		t.Error("未启用回退时应返回错误")
+	}
+
+	fetcher.SetStaleFallback(true, 0)
+	got := captureStdout(t, func(args []string) error {
+		return fetchEntries(fetcher, args, "json")
+	}, "79")
+	if !strings.Contains(got, `"name":"Stored XSS"`) {
+		t.Errorf("应输出存储中的条目: %s", got)
+	}
+}
+
+func TestRunVersion(t *testing.T) {
+	got := captureStdout(t, runVersion)
+	if !strings.HasPrefix(got, "cwe dev\n内置CWE数据版本: ") {
+		t.Errorf("版本输出不正确: %q", got)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/cwe"
+)
+
+// runDiff 执行diff命令
+//
+// 比较两个注册表文件，输出新增(+)、删除(-)和修改(~)的条目，修改的条目列出发生变化的字段。
+// 两个文件可以是不同的格式，例如比较快照和编辑后的CSV。
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "用法: cwe diff <文件A> <文件B>")
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("需要两个文件")
+	}
+
+	before, err := loadRegistry(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	after, err := loadRegistry(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	if writeDiff(stdout, before, after) == 0 {
+		fmt.Fprintln(stdout, "没有差异")
+	}
+	return nil
+}
+
+// writeDiff 输出两个注册表的差异，返回有差异的条目数
+func writeDiff(w io.Writer, before, after *cwe.Registry) int {
+	ids := make([]string, 0, len(before.Entries)+len(after.Entries))
+	for id := range before.Entries {
+		ids = append(ids, id)
+	}
+	for id := range after.Entries {
+		if _, exists := before.Entries[id]; !exists {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, _ := (&cwe.CWE{ID: ids[i]}).GetNumericID()
+		b, _ := (&cwe.CWE{ID: ids[j]}).GetNumericID()
+		if a != b {
+			return a < b
+		}
+		return ids[i] < ids[j]
+	})
+
+	changes := 0
+	for _, id := range ids {
+		old, inBefore := before.Entries[id]
+		updated, inAfter := after.Entries[id]
+		switch {
+		case !inBefore:
+			fmt.Fprintf(w, "+ %s %s\n", id, updated.Name)
+		case !inAfter:
+			fmt.Fprintf(w, "- %s %s\n", id, old.Name)
+		default:
+			fields := changedFields(old, updated)
+			if len(fields) == 0 {
+				continue
+			}
+			fmt.Fprintf(w, "~ %s %s: %s\n", id, updated.Name, strings.Join(fields, ", "))
+		}
+		changes++
+	}
+	return changes
+}
+
+// changedFields 返回两个条目之间发生变化的字段名
+func changedFields(a, b *cwe.CWE) []string {
+	fields := make([]string, 0)
+	if a.Name != b.Name {
+		fields = append(fields, "Name")
+	}
+	if a.Description != b.Description {
+		fields = append(fields, "Description")
+	}
+	if a.Severity != b.Severity {
+		fields = append(fields, "Severity")
+	}
+	if a.Status != b.Status {
+		fields = append(fields, "Status")
+	}
+	if parentID(a) != parentID(b) {
+		fields = append(fields, "Parent")
+	}
+	if strings.Join(a.Mitigations, "\x00") != strings.Join(b.Mitigations, "\x00") {
+		fields = append(fields, "Mitigations")
+	}
+	return fields
+}
+
+func parentID(entry *cwe.CWE) string {
+	if entry.Parent == nil {
+		return ""
+	}
+	return entry.Parent.ID
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/scagogogo/cwe"
+)
+
+// runExport 执行export命令
+//
+// 将注册表转换为其他格式，可用于在快照、XML和CSV之间转换，或生成图表和HTML报告。
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	input := addInputFlag(fs)
+	format := fs.String("format", "json", "输出格式: json、bin、xml、csv、dot、mermaid或html")
+	output := fs.String("o", "", "输出文件路径，为空时写入标准输出")
+	rootID := fs.String("root", "", "dot、mermaid和html格式只导出以该条目为根的子树")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	registry, err := loadRegistry(*input)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if err := exportRegistry(w, registry, *format, *rootID); err != nil {
+		return err
+	}
+	if *output != "" {
+		fmt.Fprintf(os.Stderr, "已将 %d 个条目写入 %s\n", len(registry.Entries), *output)
+	}
+	return nil
+}
+
+// exportRegistry 按格式将注册表写入w
+func exportRegistry(w io.Writer, registry *cwe.Registry, format, rootID string) error {
+	switch format {
+	case "json":
+		return registry.WriteExportJSON(w)
+	case "bin":
+		return registry.ExportBinary(w)
+	case "xml":
+		data, err := registry.ExportToXML()
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	case "csv":
+		return registry.ExportToCSV(w)
+	case "dot":
+		return registry.ExportToDOT(w, cwe.DiagramOptions{RootID: rootID})
+	case "mermaid":
+		return registry.ExportToMermaid(w, cwe.DiagramOptions{RootID: rootID})
+	case "html":
+		return registry.WriteHTMLReport(w, cwe.HTMLReportOptions{RootID: rootID})
+	default:
+		return fmt.Errorf("不支持的输出格式: %s", format)
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/cwe"
+)
+
+// runFetch 执行fetch命令
+//
+// 构建指定视图的完整CWE树并写入快照文件。构建过程中定期将进度写入状态文件，
+// 中断后使用-resume参数可从状态文件继续构建，而不必重新请求已获取的条目。
+// 构建成功后状态文件会被删除。
+//
+// 指定了条目ID参数时只获取这些条目并输出到标准输出，不构建树。
+func runFetch(args []string) error {
+	fs := flag.NewFlagSet("fetch", flag.ContinueOnError)
+	viewID := fs.String("view", "1000", "要构建的视图ID")
+	output := fs.String("o", "", "快照文件路径，默认为cwe-<视图ID>.json")
+	statePath := fs.String("state", "", "状态文件路径，默认为<快照文件路径>.state")
+	resume := fs.Bool("resume", false, "从状态文件继续之前中断的构建")
+	checkpointEvery := fs.Int("checkpoint-every", cwe.DefaultCheckpointEvery, "每处理多少个节点写入一次状态文件")
+	baseURL := fs.String("base-url", cwe.BaseURL, "CWE API的基础URL或预设名称(mitre、local)")
+	interval := fs.Duration("interval", 10*time.Second, "两次API请求之间的最小间隔")
+	allowEmpty := fs.Bool("allow-empty", false, "视图没有子节点时仍写入快照，默认视为失败")
+	showProgress := fs.Bool("progress", false, "在标准错误输出中显示构建进度")
+	format := fs.String("format", "json", "获取单个条目时的输出格式: json或markdown")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "用法: cwe fetch [参数] [条目ID或ID范围(如CWE-120..CWE-122)...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	if fs.NArg() > 0 {
+		client := cwe.NewAPIClientWithOptions(cwe.ResolveBaseURL(*baseURL), cwe.DefaultTimeout, cwe.NewHTTPRateLimiter(*interval))
+		return fetchEntries(cwe.NewDataFetcherWithClient(client), fs.Args(), *format)
+	}
+
+	normalizedViewID, err := cwe.ParseCWEID(*viewID)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		*output = "cwe-" + strings.TrimPrefix(normalizedViewID, "CWE-") + ".json"
+	}
+	if *statePath == "" {
+		*statePath = *output + ".state"
+	}
+
+	var state *cwe.BuildState
+	if *resume {
+		state, err = loadBuildState(*statePath)
+		if err != nil {
+			return fmt.Errorf("读取状态文件失败: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "从 %s 继续构建: 已获取 %d 个条目，待处理 %d 个\n",
+			*statePath, len(state.Snapshot.Entries), len(state.Pending))
+	}
+
+	client := cwe.NewAPIClientWithOptions(cwe.ResolveBaseURL(*baseURL), cwe.DefaultTimeout, cwe.NewHTTPRateLimiter(*interval))
+	fetcher := cwe.NewDataFetcherWithClient(client)
+	fetcher.SetFailOnEmptyView(!*allowEmpty)
+
+	var progress cwe.ProgressFunc
+	if *showProgress {
+		progress = func(fetched, queued int, currentID string) {
+			fmt.Fprintf(os.Stderr, "\r已获取 %d 个条目，待处理 %d 个，当前 %-10s", fetched, queued, currentID)
+		}
+	}
+
+	registry, err := fetcher.BuildCWETreeResumable(normalizedViewID, state, cwe.ResumableBuildOptions{
+		CheckpointEvery: *checkpointEvery,
+		Checkpoint: func(state *cwe.BuildState) error {
+			if *showProgress {
+				fmt.Fprintln(os.Stderr)
+			}
+			fmt.Fprintf(os.Stderr, "检查点: 已获取 %d 个条目，待处理 %d 个\n",
+				len(state.Snapshot.Entries), len(state.Pending))
+			return writeJSONFile(*statePath, state)
+		},
+		Progress: progress,
+	})
+	if err != nil {
+		if _, statErr := os.Stat(*statePath); statErr == nil {
+			return fmt.Errorf("%w (可使用 -resume 从 %s 继续)", err, *statePath)
+		}
+		return err
+	}
+
+	for _, warning := range registry.Warnings() {
+		fmt.Fprintf(os.Stderr, "警告: %v\n", warning)
+	}
+
+	if err := writeJSONFile(*output, cwe.NewRegistrySnapshot(registry)); err != nil {
+		return fmt.Errorf("写入快照文件失败: %w", err)
+	}
+	if err := os.Remove(*statePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("删除状态文件失败: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "已将视图 %s 的 %d 个条目写入 %s\n", normalizedViewID, len(registry.Entries), *output)
+	return nil
+}
+
+// acceptStale 将*cwe.StaleDataError视为获取成功，在标准错误输出中警告后使用存储中的条目
+func acceptStale(entry *cwe.CWE, err error) (*cwe.CWE, error) {
+	var staleErr *cwe.StaleDataError
+	if entry != nil && errors.As(err, &staleErr) {
+		fmt.Fprintf(os.Stderr, "警告: %v\n", staleErr)
+		return entry, nil
+	}
+	return entry, err
+}
+
+// fetchEntries 依次获取条目并按格式输出，条目可以是弱点、类别或视图
+// json格式每行输出一个条目，markdown格式的条目之间以空行分隔
+// 参数可以是ParseCWEIDs接受的任意ID或ID范围，如"79"、"CWE-120..CWE-122"
+func fetchEntries(fetcher *cwe.DataFetcher, args []string, format string) error {
+	if format != "json" && format != "markdown" {
+		return fmt.Errorf("不支持的输出格式: %s", format)
+	}
+
+	ids, err := cwe.ParseCWEIDs(args)
+	if err != nil {
+		return err
+	}
+
+	for i, id := range ids {
+		entry, err := acceptStale(fetcher.FetchWeakness(id))
+		if err != nil {
+			if entry, err = acceptStale(fetcher.FetchCategory(id)); err != nil {
+				if entry, err = acceptStale(fetcher.FetchView(id)); err != nil {
+					return fmt.Errorf("获取%s失败: %w", id, err)
+				}
+			}
+		}
+
+		if format == "markdown" {
+			if i > 0 {
+				fmt.Fprintln(stdout)
+			}
+			fmt.Fprint(stdout, cwe.RenderMarkdown(entry, cwe.MarkdownOptions{}))
+			continue
+		}
+		data, err := json.Marshal(cwe.NewRegistrySnapshot(registryOf(entry)).Entries[0])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(data))
+	}
+	return nil
+}
+
+// registryOf 创建只包含一个条目的注册表
+func registryOf(entry *cwe.CWE) *cwe.Registry {
+	registry := cwe.NewRegistry()
+	registry.Register(entry)
+	return registry
+}
+
+// loadBuildState 从文件读取构建状态
+func loadBuildState(path string) (*cwe.BuildState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var state cwe.BuildState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// writeJSONFile 以原子方式将值写入JSON文件
+// 先写入同目录下的临时文件再重命名，避免中断时留下损坏的文件
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
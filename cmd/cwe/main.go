@@ -0,0 +1,81 @@
+// Command cwe 是CWE库的命令行工具
+//
+// 用法:
+//
+//	cwe <命令> [参数]
+//
+// 可用命令:
+//
+//	fetch    从CWE API构建指定视图的快照文件，支持断点续传；指定条目ID时只获取这些条目
+//	tree     以文本、DOT或Mermaid格式输出视图或条目的子树
+//	search   按关键词搜索条目
+//	export   将注册表转换为json、xml、csv、dot、mermaid或html格式
+//	diff     比较两个注册表文件
+//	serve    以CWE REST API的接口提供注册表中的数据
+//	version  输出版本信息
+//
+// tree默认从CWE API构建指定的视图；search、export和serve默认使用内置的离线数据。
+// 这些命令都可以通过-i参数读取fetch生成的快照或其他导出文件。
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// command 表示一个子命令
+type command struct {
+	// name 命令名称
+	name string
+
+	// summary 命令的一句话说明
+	summary string
+
+	// run 执行命令，args不包含命令名称本身
+	run func(args []string) error
+}
+
+// commands 所有可用的子命令
+var commands = []command{
+	{name: "fetch", summary: "从CWE API构建指定视图的快照文件，支持断点续传；指定条目ID时只获取这些条目", run: runFetch},
+	{name: "tree", summary: "以文本、DOT或Mermaid格式输出视图或条目的子树", run: runTree},
+	{name: "search", summary: "按关键词搜索条目", run: runSearch},
+	{name: "export", summary: "将注册表转换为json、xml、csv、dot、mermaid或html格式", run: runExport},
+	{name: "diff", summary: "比较两个注册表文件", run: runDiff},
+	{name: "serve", summary: "以CWE REST API的接口提供注册表中的数据", run: runServe},
+	{name: "version", summary: "输出版本信息", run: runVersion},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	for _, cmd := range commands {
+		if cmd.name == name {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "cwe %s: %v\n", name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	if name != "help" && name != "-h" && name != "--help" {
+		fmt.Fprintf(os.Stderr, "未知命令: %s\n\n", name)
+	}
+	usage()
+	os.Exit(2)
+}
+
+// usage 打印命令行帮助信息
+func usage() {
+	fmt.Fprintln(os.Stderr, "用法: cwe <命令> [参数]")
+	fmt.Fprintln(os.Stderr, "\n可用命令:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(os.Stderr, "\n使用 \"cwe <命令> -h\" 查看命令的参数说明")
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/scagogogo/cwe"
+)
+
+// stdout 命令的标准输出，测试中替换为缓冲区
+var stdout io.Writer = os.Stdout
+
+// addInputFlag 为命令添加-i参数，指定读取注册表的文件
+func addInputFlag(fs *flag.FlagSet) *string {
+	return fs.String("i", "", "注册表文件(.json快照或导出文件、.bin、.xml、.csv)，为空时使用内置的离线数据")
+}
+
+// loadRegistry 从文件读取注册表，path为空时使用内置的离线数据
+//
+// JSON文件可以是fetch命令输出的快照，也可以是Registry.ExportToJSONFile的导出文件，
+// 根据是否包含导出文件特有的timestamp或rootId字段区分(快照的version字段是CWE内容版本)；
+// 其余格式按扩展名识别，.bin为Registry.ExportBinary的二进制导出文件。
+func loadRegistry(path string) (*cwe.Registry, error) {
+	if path == "" {
+		return cwe.NewOfflineRegistry()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := cwe.NewRegistry()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		var header struct {
+			Timestamp string `json:"timestamp"`
+			RootID    string `json:"rootId"`
+		}
+		if err := json.Unmarshal(data, &header); err != nil {
+			return nil, fmt.Errorf("解析%s失败: %w", path, err)
+		}
+		if header.Timestamp != "" || header.RootID != "" {
+			if err := registry.ReadExportJSON(bytes.NewReader(data)); err != nil {
+				return nil, err
+			}
+			return registry, nil
+		}
+
+		var snapshot cwe.RegistrySnapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return nil, fmt.Errorf("解析%s失败: %w", path, err)
+		}
+		return snapshot.ToRegistry()
+	case ".bin":
+		if err := registry.ImportBinary(bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+		return registry, nil
+	case ".xml":
+		if _, err := registry.ImportFromXML(data, cwe.XMLImportOptions{}); err != nil {
+			return nil, err
+		}
+		return registry, nil
+	case ".csv":
+		if _, err := registry.ImportFromCSV(bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+		return registry, nil
+	default:
+		return nil, fmt.Errorf("不支持的文件类型: %s", path)
+	}
+}
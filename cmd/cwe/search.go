@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/scagogogo/cwe"
+)
+
+// runSearch 执行search命令
+//
+// 在注册表中按关键词搜索条目，每行输出一个"ID<TAB>名称"，便于在管道中用cut、awk等处理。
+// 没有结果时以相同格式输出拼写相近的条目，见Registry.SuggestIDs。
+func runSearch(args []string) error {
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	input := addInputFlag(fs)
+	limit := fs.Int("limit", cwe.DefaultSearchLimit, "最多输出的结果数")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "用法: cwe search [参数] <关键词>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("缺少关键词")
+	}
+
+	registry, err := loadRegistry(*input)
+	if err != nil {
+		return err
+	}
+
+	keyword := strings.Join(fs.Args(), " ")
+	page, err := registry.Search(cwe.SearchQuery{Keyword: keyword, Limit: *limit})
+	if err != nil {
+		return err
+	}
+	if page.Total == 0 {
+		if suggestions := registry.SuggestIDs(keyword, 0); len(suggestions) > 0 {
+			fmt.Fprintln(fs.Output(), "没有找到结果，您是否要找:")
+			for _, id := range suggestions {
+				fmt.Fprintf(stdout, "%s\t%s\n", id, registry.Entries[id].Name)
+			}
+		}
+		return nil
+	}
+	for _, entry := range page.Results {
+		fmt.Fprintf(stdout, "%s\t%s\n", entry.ID, entry.Name)
+	}
+	if page.Total > len(page.Results) {
+		fmt.Fprintf(fs.Output(), "共 %d 个结果，只显示前 %d 个\n", page.Total, len(page.Results))
+	}
+	return nil
+}
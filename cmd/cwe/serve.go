@@ -0,0 +1,35 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/scagogogo/cwe/server"
+)
+
+// runServe 执行serve命令
+//
+// 以与MITRE CWE REST API相同的接口提供注册表中的数据，用作无法访问外网环境中的内部镜像。
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	input := addInputFlag(fs)
+	addr := fs.String("addr", ":8080", "监听地址")
+	prefix := fs.String("prefix", "/api/v1", "接口的路径前缀，与官方API保持一致时客户端只需替换主机名")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	registry, err := loadRegistry(*input)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "在 %s 提供 %d 个条目，接口前缀 %s\n", *addr, len(registry.Entries), *prefix)
+	return http.ListenAndServe(*addr, server.NewWithOptions(registry, server.Options{Prefix: *prefix}))
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/cwe"
+)
+
+// runTree 执行tree命令
+//
+// 未指定-i时从CWE API构建参数指定的视图(默认为1000)并输出整个视图；
+// 指定-i时输出文件中以参数指定的条目为根的子树，未指定条目时从注册表的根节点开始。
+// 两种情况都支持缩进文本、DOT和Mermaid格式。
+func runTree(args []string) error {
+	fs := flag.NewFlagSet("tree", flag.ContinueOnError)
+	input := fs.String("i", "", "注册表文件(.json快照或导出文件、.bin、.xml、.csv)，为空时从CWE API构建视图")
+	depth := fs.Int("depth", 0, "输出的最大深度，0表示不限制")
+	format := fs.String("format", "text", "输出格式: text、dot或mermaid")
+	baseURL := fs.String("base-url", cwe.BaseURL, "未指定-i时使用的CWE API基础URL或预设名称(mitre、local)")
+	interval := fs.Duration("interval", 10*time.Second, "未指定-i时两次API请求之间的最小间隔")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "用法: cwe tree [参数] [视图ID(默认1000)，指定-i时为条目ID]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	opts := cwe.DiagramOptions{RootID: fs.Arg(0), MaxDepth: *depth}
+	var registry *cwe.Registry
+	var err error
+	if *input == "" {
+		registry, err = fetchViewTree(*baseURL, *interval, opts.RootID)
+		opts.RootID = ""
+	} else {
+		registry, err = loadRegistry(*input)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "text":
+		root := registry.Root
+		if opts.RootID != "" {
+			id, err := cwe.ParseCWEID(opts.RootID)
+			if err != nil {
+				return err
+			}
+			if root, err = registry.GetByID(id); err != nil {
+				if suggestions := registry.SuggestIDs(id, 3); len(suggestions) > 0 {
+					return fmt.Errorf("%w，您是否要找: %s", err, strings.Join(suggestions, ", "))
+				}
+				return err
+			}
+		}
+		if root == nil {
+			return fmt.Errorf("注册表没有根节点，请指定条目ID")
+		}
+		writeTextTree(stdout, root, *depth)
+		return nil
+	case "dot":
+		return registry.ExportToDOT(stdout, opts)
+	case "mermaid":
+		return registry.ExportToMermaid(stdout, opts)
+	default:
+		return fmt.Errorf("不支持的输出格式: %s", *format)
+	}
+}
+
+// fetchViewTree 从CWE API构建视图的树，viewID为空时构建视图1000
+// 部分节点获取失败时在标准错误输出中提示，仍返回已构建的树
+func fetchViewTree(baseURL string, interval time.Duration, viewID string) (*cwe.Registry, error) {
+	if viewID == "" {
+		viewID = "1000"
+	}
+	client := cwe.NewAPIClientWithOptions(cwe.ResolveBaseURL(baseURL), cwe.DefaultTimeout, cwe.NewHTTPRateLimiter(interval))
+	registry, err := cwe.NewDataFetcherWithClient(client).BuildCWETreeWithView(viewID)
+	var failures *cwe.MultiError
+	if errors.As(err, &failures) && registry != nil {
+		fmt.Fprintf(os.Stderr, "警告: %d个节点获取失败，树可能不完整: %v\n", len(failures.Errors), err)
+		return registry, nil
+	}
+	return registry, err
+}
+
+// writeTextTree 以缩进文本输出树，每层缩进两个空格，子节点按CWE编号排序
+// 多父节点的条目在每个父节点下都会出现，但只在第一次出现时展开
+func writeTextTree(w io.Writer, root *cwe.CWE, maxDepth int) {
+	visited := make(map[string]bool)
+	var walk func(node *cwe.CWE, depth int)
+	walk = func(node *cwe.CWE, depth int) {
+		fmt.Fprintf(w, "%s%s %s", strings.Repeat("  ", depth), node.ID, node.Name)
+		if visited[node.ID] && len(node.Children) > 0 {
+			fmt.Fprintln(w, " (见上文)")
+			return
+		}
+		fmt.Fprintln(w)
+		visited[node.ID] = true
+		if maxDepth > 0 && depth >= maxDepth {
+			return
+		}
+
+		children := append([]*cwe.CWE(nil), node.Children...)
+		sort.SliceStable(children, func(i, j int) bool {
+			a, _ := children[i].GetNumericID()
+			b, _ := children[j].GetNumericID()
+			return a < b
+		})
+		for _, child := range children {
+			walk(child, depth+1)
+		}
+	}
+	walk(root, 0)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/scagogogo/cwe"
+)
+
+// version 命令行工具的版本，发布时通过 -ldflags "-X main.version=v1.2.3" 设置
+var version = "dev"
+
+// runVersion 执行version命令
+//
+// 输出工具版本和内置数据对应的CWE版本，指定-remote时还会查询CWE API当前的内容版本。
+func runVersion(args []string) error {
+	fs := flag.NewFlagSet("version", flag.ContinueOnError)
+	remote := fs.Bool("remote", false, "查询CWE API当前的内容版本")
+	baseURL := fs.String("base-url", cwe.BaseURL, "CWE API的基础URL或预设名称(mitre、local)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	fmt.Fprintf(stdout, "cwe %s\n", version)
+	fmt.Fprintf(stdout, "内置CWE数据版本: %s\n", cwe.KnownCWEVersion)
+
+	if *remote {
+		client := cwe.NewAPIClientWithOptions(cwe.ResolveBaseURL(*baseURL), cwe.DefaultTimeout, cwe.NewHTTPRateLimiter(0))
+		info, err := client.GetVersion()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "CWE API内容版本: %s (%s)\n", info.Version, info.ReleaseDate)
+	}
+	return nil
+}
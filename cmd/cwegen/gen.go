@@ -0,0 +1,296 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/scagogogo/cwe"
+)
+
+// tables 是数据表文件的内容
+type tables struct {
+	// Version 生成数据对应的CWE版本
+	Version string `json:"version"`
+
+	// Top25 各年份的Top 25列表，键为年份
+	Top25 map[string][]string `json:"top25"`
+
+	// Views 已知视图的ID
+	Views []string `json:"views"`
+
+	// Deprecated 手工维护的废弃别名，键为废弃ID，值为替代ID，优先于从快照推导的别名
+	Deprecated map[string]string `json:"deprecated"`
+}
+
+// loadSnapshot 按扩展名读取JSON快照或XML导出文件，保留根节点ID
+func loadSnapshot(path string) (*cwe.RegistrySnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		var snapshot cwe.RegistrySnapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return nil, err
+		}
+		return &snapshot, nil
+	case ".xml":
+		registry := cwe.NewRegistry()
+		if _, err := registry.ImportFromXML(data, cwe.XMLImportOptions{}); err != nil {
+			return nil, err
+		}
+		return cwe.NewRegistrySnapshot(registry), nil
+	default:
+		return nil, fmt.Errorf("不支持的文件类型: %s", path)
+	}
+}
+
+// writeBundle 将快照写为cwe.NewOfflineRegistry读取的gzip压缩导出文件
+// 不写入导出时间，输入不变时重新生成的文件内容也不变
+func writeBundle(snapshot *cwe.RegistrySnapshot, path string) error {
+	// 先还原一次，确保子节点和根节点引用都有效
+	if _, err := snapshot.ToRegistry(); err != nil {
+		return err
+	}
+
+	export := cwe.RegistryExport{
+		Version: cwe.RegistryExportVersion,
+		RootID:  snapshot.RootID,
+		Entries: snapshot.Entries,
+	}
+
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(zw)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(export); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// loadTables 读取数据表文件
+func loadTables(path string) (*tables, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var t tables
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// generate 使用包名对应的内置模板生成格式化后的Go源文件，见selectTemplate
+func generate(pkg string, entries []cwe.SnapshotEntry, t *tables) ([]byte, error) {
+	tmpl, err := selectTemplate(pkg, "")
+	if err != nil {
+		return nil, err
+	}
+	return render(tmpl, pkg, entries, t)
+}
+
+// render 使用模板生成格式化后的Go源文件
+func render(tmpl *template.Template, pkg string, entries []cwe.SnapshotEntry, t *tables) ([]byte, error) {
+	data, err := newTemplateData(pkg, entries, t)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("执行模板失败: %w", err)
+	}
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("格式化生成的代码失败: %w", err)
+	}
+	return source, nil
+}
+
+// newTemplateData 根据快照条目和数据表准备模板的输入
+func newTemplateData(pkg string, entries []cwe.SnapshotEntry, t *tables) (*templateData, error) {
+	known := make(map[string]templateEntry, len(entries))
+	for _, entry := range entries {
+		id, err := cwe.ParseCWEID(entry.ID)
+		if err != nil {
+			return nil, fmt.Errorf("快照中的条目: %w", err)
+		}
+		known[id] = templateEntry{
+			ID:          id,
+			Const:       constName(id),
+			Name:        oneLine(entry.Name),
+			Abstraction: entry.Abstraction,
+			Status:      entry.Status,
+		}
+	}
+	// lookup 返回ID对应的模板条目，快照中没有该条目时只有ID和常量名
+	lookup := func(id string) templateEntry {
+		if entry, exists := known[id]; exists {
+			return entry
+		}
+		return templateEntry{ID: id, Const: constName(id)}
+	}
+
+	views, err := normalizeIDs(t.Views)
+	if err != nil {
+		return nil, fmt.Errorf("视图列表: %w", err)
+	}
+
+	years := make([]int, 0, len(t.Top25))
+	top25 := make(map[int][]string, len(t.Top25))
+	for key, list := range t.Top25 {
+		year, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, fmt.Errorf("无效的Top 25年份: %q", key)
+		}
+		ids, err := normalizeIDs(list)
+		if err != nil {
+			return nil, fmt.Errorf("%d年Top 25列表: %w", year, err)
+		}
+		years = append(years, year)
+		top25[year] = ids
+	}
+	sort.Ints(years)
+
+	aliases, err := deprecatedAliases(entries, t.Deprecated)
+	if err != nil {
+		return nil, err
+	}
+
+	// 为快照条目和数据表中引用的所有ID生成常量
+	constIDs := make(map[string]bool, len(known))
+	entryIDs := make(map[string]bool, len(known))
+	for id := range known {
+		constIDs[id] = true
+		entryIDs[id] = true
+	}
+	for _, id := range views {
+		constIDs[id] = true
+	}
+	for _, ids := range top25 {
+		for _, id := range ids {
+			constIDs[id] = true
+		}
+	}
+	aliasIDs := make(map[string]bool, len(aliases))
+	for from, to := range aliases {
+		constIDs[from] = true
+		constIDs[to] = true
+		aliasIDs[from] = true
+	}
+
+	data := &templateData{Package: pkg, Version: t.Version}
+	for _, id := range sortedIDs(constIDs) {
+		data.Constants = append(data.Constants, lookup(id))
+	}
+	for _, id := range sortedIDs(entryIDs) {
+		data.Entries = append(data.Entries, known[id])
+	}
+	for _, id := range views {
+		data.Views = append(data.Views, lookup(id))
+	}
+	for _, year := range years {
+		list := templateTop25{Year: year}
+		for _, id := range top25[year] {
+			list.IDs = append(list.IDs, lookup(id))
+		}
+		data.Top25 = append(data.Top25, list)
+	}
+	for _, from := range sortedIDs(aliasIDs) {
+		data.Aliases = append(data.Aliases, templateAlias{From: lookup(from), To: lookup(aliases[from])})
+	}
+	return data, nil
+}
+
+// deprecatedAliases 计算废弃别名
+// 快照中状态为Deprecated的条目取描述中最后引用的其他CWE作为替代，
+// 描述中没有引用时不生成别名；overrides中的别名优先
+func deprecatedAliases(entries []cwe.SnapshotEntry, overrides map[string]string) (map[string]string, error) {
+	aliases := make(map[string]string)
+	for _, entry := range entries {
+		if entry.Status != cwe.StatusDeprecated {
+			continue
+		}
+		id, err := cwe.ParseCWEID(entry.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range cwe.ExtractIDs(entry.Description) {
+			if ref != id {
+				aliases[id] = ref
+			}
+		}
+	}
+
+	for from, to := range overrides {
+		normalizedFrom, err := cwe.ParseCWEID(from)
+		if err != nil {
+			return nil, fmt.Errorf("废弃别名: %w", err)
+		}
+		normalizedTo, err := cwe.ParseCWEID(to)
+		if err != nil {
+			return nil, fmt.Errorf("废弃别名: %w", err)
+		}
+		aliases[normalizedFrom] = normalizedTo
+	}
+	return aliases, nil
+}
+
+// normalizeIDs 规范化ID列表
+func normalizeIDs(ids []string) ([]string, error) {
+	normalized := make([]string, 0, len(ids))
+	for _, id := range ids {
+		n, err := cwe.ParseCWEID(id)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, n)
+	}
+	return normalized, nil
+}
+
+// sortedIDs 返回按编号排序的ID
+func sortedIDs(set map[string]bool) []string {
+	ids := make([]string, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		ni, _ := strconv.Atoi(strings.TrimPrefix(ids[i], "CWE-"))
+		nj, _ := strconv.Atoi(strings.TrimPrefix(ids[j], "CWE-"))
+		return ni < nj
+	})
+	return ids
+}
+
+// constName 返回ID对应的常量名，如CWE-79对应CWE79
+func constName(id string) string {
+	return strings.Replace(id, "-", "", 1)
+}
+
+// oneLine 将名称压缩为单行，用于注释和字符串字面量
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scagogogo/cwe"
+)
+
+func TestGenerate(t *testing.T) {
+	entries := []cwe.SnapshotEntry{
+		{ID: "CWE-79", Name: "Cross-site\nScripting"},
+		{ID: "CWE-1000", Name: "Research Concepts"},
+		{ID: "CWE-71", Name: "DEPRECATED", Status: cwe.StatusDeprecated, Description: "Please refer to CWE-62."},
+		{ID: "CWE-17", Name: "DEPRECATED: Code", Status: cwe.StatusDeprecated},
+	}
+	tbl := &tables{
+		Version:    "4.16",
+		Top25:      map[string][]string{"2024": {"79", "CWE-89"}},
+		Views:      []string{"1000"},
+		Deprecated: map[string]string{"CWE-18": "CWE-17"},
+	}
+
+	source, err := generate("cwe", entries, tbl)
+	if err != nil {
+		t.Fatalf("生成失败: %v", err)
+	}
+	code := string(source)
+
+	for _, want := range []string{
+		"// Code generated by cwegen; DO NOT EDIT.",
+		`const KnownCWEVersion = "4.16"`,
+		"// CWE79 Cross-site Scripting\n",
+		`CWE89 CWEID = "CWE-89"`,
+		`CWE1000: "Research Concepts",`,
+		"2024: {\n\t\tCWE79,\n\t\tCWE89,\n\t},",
+		"CWE71: CWE62,",
+		"CWE18: CWE17,",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("生成的代码缺少 %q:\n%s", want, code)
+		}
+	}
+	if strings.Contains(code, "CWE17: CWE") {
+		t.Error("描述中没有引用的废弃条目不应生成别名")
+	}
+	// 常量按编号排序
+	if strings.Index(code, "CWE17 ") > strings.Index(code, "CWE79 ") {
+		t.Error("常量应按编号排序")
+	}
+}
+
+func TestGenerateInvalidInput(t *testing.T) {
+	if _, err := generate("cwe", []cwe.SnapshotEntry{{ID: "bad"}}, &tables{}); err == nil {
+		t.Error("无效的条目ID应返回错误")
+	}
+	if _, err := generate("cwe", nil, &tables{Top25: map[string][]string{"latest": {"79"}}}); err == nil {
+		t.Error("无效的年份应返回错误")
+	}
+}
+
+func TestRunFromXML(t *testing.T) {
+	registry := cwe.NewRegistry()
+	if err := registry.Register(&cwe.CWE{ID: "CWE-79", Name: "XSS"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := registry.ExportToXML()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	in := filepath.Join(dir, "snapshot.xml")
+	tablesPath := filepath.Join(dir, "tables.json")
+	out := filepath.Join(dir, "known.go")
+	if err := os.WriteFile(in, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(tablesPath, []byte(`{"version":"4.16"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := run(in, tablesPath, "", out, "known"); err != nil {
+		t.Fatalf("run失败: %v", err)
+	}
+	source, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(source), "package known") || !strings.Contains(string(source), "// CWE79 XSS") {
+		t.Errorf("生成的代码不符合预期:\n%s", source)
+	}
+}
+
+func TestGeneratePackage(t *testing.T) {
+	entries := []cwe.SnapshotEntry{
+		{ID: "CWE-79", Name: "XSS", Abstraction: "Base", Status: "Stable"},
+		{ID: "CWE-89", Name: "SQL Injection"},
+	}
+	source, err := generate("weaknesses", entries, &tables{Version: "4.16"})
+	if err != nil {
+		t.Fatalf("生成失败: %v", err)
+	}
+	code := string(source)
+
+	for _, want := range []string{
+		"package weaknesses",
+		`import "github.com/scagogogo/cwe"`,
+		`const CWEVersion = "4.16"`,
+		`CWE79 cwe.CWEID = "CWE-79"`,
+		`CWE79: {ID: CWE79, Name: "XSS", Abstraction: "Base", Status: "Stable"},`,
+		`CWE89: {ID: CWE89, Name: "SQL Injection"},`,
+		"func Lookup(id string) (cwe.KnownEntry, bool)",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("生成的代码缺少 %q:\n%s", want, code)
+		}
+	}
+	if strings.Contains(code, "KnownCWEVersion") || strings.Contains(code, "top25Lists") {
+		t.Error("其他包不应生成cwe包内部的查找表")
+	}
+}
+
+func TestRunWithTemplate(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "snapshot.json")
+	tmplPath := filepath.Join(dir, "ids.tmpl")
+	out := filepath.Join(dir, "ids.go")
+	snapshot := `{"version": "4.15", "entries": [{"id": "CWE-79", "name": "XSS"}, {"id": "CWE-20", "name": "Input Validation"}]}`
+	if err := os.WriteFile(in, []byte(snapshot), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl := "package {{.Package}}\n\n// {{.Version}}\nvar IDs = []string{ {{- range .Entries}}{{printf \"%q\" .ID}}, {{end -}} }\n"
+	if err := os.WriteFile(tmplPath, []byte(tmpl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// 未指定数据表时使用快照的版本
+	if err := run(in, "", tmplPath, out, "ids"); err != nil {
+		t.Fatalf("run失败: %v", err)
+	}
+	source, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(source), "// 4.15") || !strings.Contains(string(source), `[]string{"CWE-20", "CWE-79"}`) {
+		t.Errorf("生成的代码不符合预期:\n%s", source)
+	}
+
+	if err := os.WriteFile(tmplPath, []byte("{{.Missing"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(in, "", tmplPath, out, "ids"); err == nil {
+		t.Error("无效的模板应返回错误")
+	}
+	if err := run(in, "", filepath.Join(dir, "missing.tmpl"), out, "ids"); err == nil {
+		t.Error("模板文件不存在时应返回错误")
+	}
+}
+
+func TestRunBundle(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "snapshot.json")
+	bundle := filepath.Join(dir, "bundle.json.gz")
+	snapshot := `{"root_id": "CWE-1000", "entries": [
+		{"id": "CWE-1000", "name": "Research Concepts", "children": ["CWE-79"]},
+		{"id": "CWE-79", "name": "XSS"}
+	]}`
+	if err := os.WriteFile(in, []byte(snapshot), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runBundle(in, bundle); err != nil {
+		t.Fatalf("runBundle失败: %v", err)
+	}
+
+	file, err := os.Open(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("数据包应为gzip格式: %v", err)
+	}
+	registry := cwe.NewRegistry()
+	if err := registry.ReadExportJSON(reader); err != nil {
+		t.Fatalf("读取数据包失败: %v", err)
+	}
+	if registry.Root == nil || len(registry.Root.Children) != 1 || registry.Root.Children[0].ID != "CWE-79" {
+		t.Error("数据包应保留根节点和父子关系")
+	}
+
+	// 引用了不存在的子节点时不生成数据包
+	broken := filepath.Join(dir, "broken.json")
+	if err := os.WriteFile(broken, []byte(`{"entries": [{"id": "CWE-1", "name": "a", "children": ["CWE-2"]}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runBundle(broken, filepath.Join(dir, "broken.json.gz")); err == nil {
+		t.Error("无效的快照应返回错误")
+	}
+}
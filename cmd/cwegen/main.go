@@ -0,0 +1,102 @@
+// Command cwegen 根据CWE快照和数据表生成cwe包内置的常量和查找表
+//
+// 用法:
+//
+//	cwegen -in data/cwe-top25-snapshot.json [-tables data/cwe-tables.json] -o cwe_top25_gen.go [-pkg cwe] [-template <模板文件>] [-bundle data/cwe-offline.json.gz]
+//
+// 输入快照可以是Registry快照JSON(cwe fetch命令的输出)或Registry.ExportToXML导出的XML，
+// 按扩展名区分。数据表是JSON文件，包含CWE版本、各年份Top 25列表、视图ID和手工维护的
+// 废弃别名。快照中状态为Deprecated的条目会自动从描述中推导替代ID，数据表中的别名优先。
+//
+// 代码通过text/template模板生成。包名为cwe时使用生成cwe包内置数据的模板；其他包名使用生成
+// cwe.CWEID类型常量、条目元数据表和Lookup函数的模板，供下游项目针对自己固定的CWE快照生成代码。
+// -template可以指定自定义模板文件，模板的输入见templateData。
+// 数据表可以省略，此时CWE版本取自快照，不生成Top 25、视图和手工维护的废弃别名。
+//
+// 指定-bundle时还会将快照写为gzip压缩的Registry导出文件，由cwe.NewOfflineRegistry通过go:embed加载。
+//
+// 通常通过cwe包中的go:generate指令调用:
+//
+//	go generate github.com/scagogogo/cwe
+//
+// 下游项目可以在自己的包中这样调用:
+//
+//	//go:generate go run github.com/scagogogo/cwe/cmd/cwegen -in cwe-1000.json -pkg weaknesses -o cwe_ids_gen.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	in := flag.String("in", "", "CWE快照文件路径(.json或.xml)")
+	tables := flag.String("tables", "", "数据表文件路径")
+	out := flag.String("o", "cwe_known_gen.go", "生成的Go源文件路径")
+	pkg := flag.String("pkg", "cwe", "生成代码的包名")
+	tmpl := flag.String("template", "", "自定义代码模板文件路径，为空时使用内置模板")
+	bundle := flag.String("bundle", "", "离线数据包的输出路径，为空时不生成")
+	flag.Parse()
+
+	if *in == "" {
+		fmt.Fprintln(os.Stderr, "用法: cwegen -in <快照文件> [-tables <数据表文件>] [-o <输出文件>] [-pkg <包名>] [-template <模板文件>] [-bundle <数据包文件>]")
+		os.Exit(2)
+	}
+
+	if err := run(*in, *tables, *tmpl, *out, *pkg); err != nil {
+		fmt.Fprintf(os.Stderr, "cwegen: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *bundle != "" {
+		if err := runBundle(*in, *bundle); err != nil {
+			fmt.Fprintf(os.Stderr, "cwegen: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// run 读取输入文件并写入生成的源文件
+// tablesPath为空时不读取数据表，templatePath为空时使用内置模板
+func run(inPath, tablesPath, templatePath, outPath, pkg string) error {
+	snapshot, err := loadSnapshot(inPath)
+	if err != nil {
+		return fmt.Errorf("读取快照失败: %w", err)
+	}
+
+	t := &tables{}
+	if tablesPath != "" {
+		if t, err = loadTables(tablesPath); err != nil {
+			return fmt.Errorf("读取数据表失败: %w", err)
+		}
+	}
+	if t.Version == "" {
+		t.Version = snapshot.Version
+	}
+
+	tmpl, err := selectTemplate(pkg, templatePath)
+	if err != nil {
+		return err
+	}
+
+	source, err := render(tmpl, pkg, snapshot.Entries, t)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(outPath, source, 0o644)
+}
+
+// runBundle 读取输入快照并写入离线数据包
+func runBundle(inPath, bundlePath string) error {
+	snapshot, err := loadSnapshot(inPath)
+	if err != nil {
+		return fmt.Errorf("读取快照失败: %w", err)
+	}
+
+	if err := writeBundle(snapshot, bundlePath); err != nil {
+		return fmt.Errorf("生成离线数据包失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/template"
+)
+
+// knownTemplate 生成cwe包内置的常量、查找表和元数据表，只能用于cwe包
+const knownTemplate = `// Code generated by cwegen; DO NOT EDIT.
+
+package {{.Package}}
+
+// KnownCWEVersion 内置常量和查找表对应的CWE版本
+const KnownCWEVersion = {{printf "%q" .Version}}
+
+// 内置的CWE ID常量
+const (
+{{- range .Constants}}
+{{- if .Name}}
+	// {{.Const}} {{.Name}}
+{{- end}}
+	{{.Const}} CWEID = {{printf "%q" .ID}}
+{{- end}}
+)
+
+// knownViewNames 已知视图的名称
+var knownViewNames = map[CWEID]string{
+{{- range .Views}}
+	{{.Const}}: {{printf "%q" .Name}},
+{{- end}}
+}
+
+// top25Lists 各年份的Top 25列表，按排名排序
+var top25Lists = map[int][]CWEID{
+{{- range .Top25}}
+	{{.Year}}: {
+{{- range .IDs}}
+		{{.Const}},
+{{- end}}
+	},
+{{- end}}
+}
+
+// deprecatedAliases 废弃条目到替代条目的映射
+var deprecatedAliases = map[CWEID]CWEID{
+{{- range .Aliases}}
+	{{.From.Const}}: {{.To.Const}},
+{{- end}}
+}
+
+// knownEntries 快照中条目的元数据，见LookupKnown
+var knownEntries = map[CWEID]KnownEntry{
+{{- range .Entries}}
+	{{.Const}}: {ID: {{.Const}}, Name: {{printf "%q" .Name}}
+		{{- with .Abstraction}}, Abstraction: {{printf "%q" .}}{{end}}
+		{{- with .Status}}, Status: {{printf "%q" .}}{{end}}},
+{{- end}}
+}
+`
+
+// packageTemplate 为其他包生成引用cwe.CWEID的常量、元数据表和Lookup函数
+const packageTemplate = `// Code generated by cwegen; DO NOT EDIT.
+
+package {{.Package}}
+
+import "github.com/scagogogo/cwe"
+
+// CWEVersion 生成常量使用的快照对应的CWE版本
+const CWEVersion = {{printf "%q" .Version}}
+
+// 快照中的CWE ID常量
+const (
+{{- range .Constants}}
+{{- if .Name}}
+	// {{.Const}} {{.Name}}
+{{- end}}
+	{{.Const}} cwe.CWEID = {{printf "%q" .ID}}
+{{- end}}
+)
+
+// entries 快照中条目的元数据
+var entries = map[cwe.CWEID]cwe.KnownEntry{
+{{- range .Entries}}
+	{{.Const}}: {ID: {{.Const}}, Name: {{printf "%q" .Name}}
+		{{- with .Abstraction}}, Abstraction: {{printf "%q" .}}{{end}}
+		{{- with .Status}}, Status: {{printf "%q" .}}{{end}}},
+{{- end}}
+}
+
+// Lookup 返回快照中条目的元数据，id支持cwe.ParseCWEID接受的所有格式
+func Lookup(id string) (cwe.KnownEntry, bool) {
+	normalized, err := cwe.ParseCWEID(id)
+	if err != nil {
+		return cwe.KnownEntry{}, false
+	}
+	entry, exists := entries[cwe.CWEID(normalized)]
+	return entry, exists
+}
+`
+
+// templateData 是代码模板的输入，-template指定的自定义模板也使用此结构
+type templateData struct {
+	// Package 生成代码的包名
+	Package string
+
+	// Version 数据对应的CWE版本，数据表中没有版本时使用快照的版本
+	Version string
+
+	// Constants 需要生成常量的所有ID，包括快照条目和数据表中引用的ID，按编号排序
+	Constants []templateEntry
+
+	// Entries 快照中的条目，按编号排序
+	Entries []templateEntry
+
+	// Views 数据表中的视图，按数据表中的顺序排列
+	Views []templateEntry
+
+	// Top25 各年份的Top 25列表，按年份排序
+	Top25 []templateTop25
+
+	// Aliases 废弃别名，按废弃ID的编号排序
+	Aliases []templateAlias
+}
+
+// templateEntry 是模板中的一个CWE
+type templateEntry struct {
+	// ID 规范化的ID，如"CWE-79"
+	ID string
+
+	// Const 常量名，如"CWE79"
+	Const string
+
+	// Name 单行的名称，快照中没有该条目时为空
+	Name string
+
+	// Abstraction 和Status 是快照中条目的抽象级别和状态
+	Abstraction string
+	Status      string
+}
+
+// templateTop25 是一个年份的Top 25列表
+type templateTop25 struct {
+	Year int
+	IDs  []templateEntry
+}
+
+// templateAlias 是一个废弃别名
+type templateAlias struct {
+	From templateEntry
+	To   templateEntry
+}
+
+// selectTemplate 返回生成代码使用的模板
+// 指定了path时从文件读取；否则cwe包使用knownTemplate，其他包使用packageTemplate
+func selectTemplate(pkg, path string) (*template.Template, error) {
+	if path != "" {
+		text, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return parseTemplate(path, string(text))
+	}
+	if pkg == "cwe" {
+		return parseTemplate("known", knownTemplate)
+	}
+	return parseTemplate("package", packageTemplate)
+}
+
+// parseTemplate 解析代码模板
+func parseTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("解析模板%s失败: %w", name, err)
+	}
+	return tmpl, nil
+}
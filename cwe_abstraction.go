@@ -0,0 +1,119 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 弱点的抽象级别，即CWE.Abstraction的取值，从抽象到具体依次为Pillar、Class、Base、Variant
+// Compound表示由多个弱点组合而成的复合条目
+const (
+	AbstractionPillar   = "Pillar"
+	AbstractionClass    = "Class"
+	AbstractionBase     = "Base"
+	AbstractionVariant  = "Variant"
+	AbstractionCompound = "Compound"
+)
+
+// ErrNoBaseAncestor 表示条目在注册表的层次结构中没有Base级别的祖先
+var ErrNoBaseAncestor = errors.New("条目没有Base级别的祖先")
+
+// IsPillar 判断条目是否为Pillar级别的弱点，比较时不区分大小写
+func (c *CWE) IsPillar() bool {
+	return strings.EqualFold(c.Abstraction, AbstractionPillar)
+}
+
+// IsClass 判断条目是否为Class级别的弱点，比较时不区分大小写
+func (c *CWE) IsClass() bool {
+	return strings.EqualFold(c.Abstraction, AbstractionClass)
+}
+
+// IsBase 判断条目是否为Base级别的弱点，比较时不区分大小写
+func (c *CWE) IsBase() bool {
+	return strings.EqualFold(c.Abstraction, AbstractionBase)
+}
+
+// IsVariant 判断条目是否为Variant级别的弱点，比较时不区分大小写
+func (c *CWE) IsVariant() bool {
+	return strings.EqualFold(c.Abstraction, AbstractionVariant)
+}
+
+// NearestBaseAncestor 返回条目最近的Base级别祖先
+//
+// 方法功能:
+// MITRE的映射指南建议将漏洞映射到Base级别的条目。扫描结果落在Variant上时，
+// 可以用本方法找到对应的Base条目做汇总。祖先沿Parents向上按距离从近到远查找(见GetAncestors)，
+// 距离相同时Primary父节点优先，不包括条目本身。
+//
+// 参数:
+// - id: string - 条目ID，支持ParseCWEID接受的所有格式
+//
+// 返回值:
+// - *CWE: 最近的Base级别祖先
+// - error: 条目不存在时返回包装了ErrNotFound的错误，没有Base级别的祖先时返回包装了ErrNoBaseAncestor的错误
+//
+// 使用示例:
+// ```go
+// // CWE-564(Variant)的Base祖先是CWE-89
+// base, err := registry.NearestBaseAncestor("CWE-564")
+//
+//	if err == nil {
+//	    fmt.Println(base.ID) // CWE-89
+//	}
+//
+// ```
+func (r *Registry) NearestBaseAncestor(id string) (*CWE, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	entry, exists := r.Entries[normalizeEntryID(id)]
+	if !exists {
+		return nil, &notFoundError{id: id}
+	}
+
+	for _, ancestor := range r.ancestorDistances(entry) {
+		if ancestor.entry != entry && ancestor.entry.IsBase() {
+			return ancestor.entry, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNoBaseAncestor, entry.ID)
+}
+
+// FilterByAbstraction 返回抽象级别为levels之一的条目
+//
+// 参数:
+// - levels: ...string - 抽象级别，如AbstractionBase，不区分大小写；为空时返回空切片
+//
+// 返回值:
+// - []*CWE: 匹配的条目，按CWE编号排序
+//
+// 使用示例:
+// ```go
+// mappable := registry.FilterByAbstraction(cwe.AbstractionBase, cwe.AbstractionVariant)
+// ```
+func (r *Registry) FilterByAbstraction(levels ...string) []*CWE {
+	return r.Query().Abstraction(levels...).All()
+}
+
+// Abstraction 只保留抽象级别为levels之一的条目，比较时不区分大小写
+func (q *RegistryQuery) Abstraction(levels ...string) *RegistryQuery {
+	return q.Where(func(entry *CWE) bool {
+		for _, level := range levels {
+			if strings.EqualFold(entry.Abstraction, strings.TrimSpace(level)) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// NearestBaseAncestor 返回条目最近的Base级别祖先，见Registry.NearestBaseAncestor
+func (s *FrozenRegistry) NearestBaseAncestor(id string) (*CWE, error) {
+	return s.registry.NearestBaseAncestor(id)
+}
+
+// FilterByAbstraction 返回抽象级别为levels之一的条目，见Registry.FilterByAbstraction
+func (s *FrozenRegistry) FilterByAbstraction(levels ...string) []*CWE {
+	return s.registry.FilterByAbstraction(levels...)
+}
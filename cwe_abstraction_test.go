@@ -0,0 +1,63 @@
+package cwe
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func newAbstractionTestRegistry() *Registry {
+	return buildTestRegistry([]testNode{
+		{id: "CWE-707", abstraction: AbstractionPillar},
+		{id: "CWE-74", abstraction: AbstractionClass, parent: "CWE-707"},
+		{id: "CWE-89", abstraction: AbstractionBase, parent: "CWE-74"},
+		{id: "CWE-564", abstraction: AbstractionVariant, parent: "CWE-89"},
+		{id: "CWE-1", abstraction: "variant", parent: "CWE-74"},
+	})
+}
+
+func TestAbstractionHelpers(t *testing.T) {
+	registry := newAbstractionTestRegistry()
+
+	if !registry.Entries["CWE-564"].IsVariant() || !registry.Entries["CWE-1"].IsVariant() || registry.Entries["CWE-89"].IsVariant() {
+		t.Error("IsVariant结果不正确")
+	}
+	if !registry.Entries["CWE-89"].IsBase() || !registry.Entries["CWE-74"].IsClass() || !registry.Entries["CWE-707"].IsPillar() {
+		t.Error("IsBase、IsClass或IsPillar结果不正确")
+	}
+
+	base, err := registry.NearestBaseAncestor("564")
+	if err != nil || base.ID != "CWE-89" {
+		t.Errorf("CWE-564的Base祖先应为CWE-89，实际为%v, %v", base, err)
+	}
+	if _, err := registry.NearestBaseAncestor("CWE-89"); !errors.Is(err, ErrNoBaseAncestor) {
+		t.Errorf("结果不应包括条目本身，实际: %v", err)
+	}
+	if _, err := registry.NearestBaseAncestor("CWE-1"); !errors.Is(err, ErrNoBaseAncestor) {
+		t.Errorf("没有Base祖先时应返回ErrNoBaseAncestor，实际: %v", err)
+	}
+	if _, err := registry.NearestBaseAncestor("CWE-404"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("条目不存在时应返回ErrNotFound，实际: %v", err)
+	}
+
+	if got := cweIDsOf(registry.FilterByAbstraction(AbstractionVariant)); !reflect.DeepEqual(got, []string{"CWE-1", "CWE-564"}) {
+		t.Errorf("FilterByAbstraction(Variant) = %v", got)
+	}
+	if got := cweIDsOf(registry.FilterByAbstraction("base", AbstractionClass)); !reflect.DeepEqual(got, []string{"CWE-74", "CWE-89"}) {
+		t.Errorf("FilterByAbstraction(Base, Class) = %v", got)
+	}
+	if got := registry.FilterByAbstraction(); len(got) != 0 {
+		t.Errorf("没有指定级别时应返回空切片，实际为%v", cweIDsOf(got))
+	}
+	if got := registry.Query().Abstraction(AbstractionBase, AbstractionVariant).UnderAncestor("CWE-89").IDs(); !reflect.DeepEqual(got, []string{"CWE-564"}) {
+		t.Errorf("Query().Abstraction = %v", got)
+	}
+
+	frozen := registry.Freeze()
+	if base, err := frozen.NearestBaseAncestor("CWE-564"); err != nil || base.ID != "CWE-89" {
+		t.Errorf("FrozenRegistry.NearestBaseAncestor结果不正确: %v, %v", base, err)
+	}
+	if got := frozen.FilterByAbstraction(AbstractionPillar); len(got) != 1 {
+		t.Errorf("FrozenRegistry.FilterByAbstraction结果不正确: %v", cweIDsOf(got))
+	}
+}
@@ -0,0 +1,254 @@
+package cwe
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultAccessibleMaxChildren AccessibleText默认逐个朗读的子节点数量
+const DefaultAccessibleMaxChildren = 5
+
+// DefaultAbbreviations AccessibleText默认展开的缩写
+// 键区分大小写并按整词匹配，可通过AccessibleOptions.Abbreviations覆盖或补充
+var DefaultAbbreviations = map[string]string{
+	"API":    "application programming interface",
+	"CSRF":   "cross-site request forgery",
+	"CVE":    "C V E",
+	"CVSS":   "C V S S",
+	"DoS":    "denial of service",
+	"HTML":   "H T M L",
+	"HTTP":   "H T T P",
+	"LDAP":   "L D A P",
+	"OS":     "operating system",
+	"SQL":    "S Q L",
+	"SSRF":   "server-side request forgery",
+	"TOCTOU": "time of check to time of use",
+	"URL":    "U R L",
+	"XML":    "X M L",
+	"XSS":    "cross-site scripting",
+	"XXE":    "X M L external entity",
+}
+
+// AccessibleOptions 控制AccessibleText的输出
+type AccessibleOptions struct {
+	// Language 输出语言，支持"en"(默认)和"zh"
+	Language string
+
+	// Abbreviations 额外的缩写表，与DefaultAbbreviations合并，同名时优先
+	// 将值设为空字符串可以禁止展开某个默认缩写
+	Abbreviations map[string]string
+
+	// MaxChildren 逐个朗读的子节点数量，超出部分只读出数量
+	// 0时使用DefaultAccessibleMaxChildren，<0时不朗读子节点
+	MaxChildren int
+
+	// IncludeMitigations 为true时朗读缓解措施
+	IncludeMitigations bool
+}
+
+// accessiblePhrases 一种语言下AccessibleText使用的句式
+type accessiblePhrases struct {
+	entry       string // ID, 名称
+	severity    string // 严重性
+	status      string // 状态
+	root        string // 无父节点
+	parent      string // ID, 名称
+	ancestor    string // ID, 名称
+	children    string // 数量
+	child       string // ID, 名称
+	childSep    string // 子节点之间的分隔符
+	moreChild   string // 数量
+	leaf        string
+	description string // 描述
+	mitigations string // 数量
+	mitigation  string // 序号, 总数, 内容
+	aka         string // 别名
+	end         string // 句末标点
+	separator   string // 句子之间的分隔符
+}
+
+var accessibleLanguages = map[string]accessiblePhrases{
+	"en": {
+		entry:       "%s: %s.",
+		severity:    "Severity: %s.",
+		status:      "Status: %s.",
+		root:        "This is a top-level entry.",
+		parent:      "It belongs to %s, %s",
+		ancestor:    ", which belongs to %s, %s",
+		children:    "It has %d child entries:",
+		child:       " %s, %s",
+		childSep:    ";",
+		moreChild:   "; and %d more",
+		leaf:        "It has no child entries.",
+		description: "Description: %s",
+		mitigations: "There are %d mitigations.",
+		mitigation:  "Mitigation %d of %d: %s",
+		aka:         ", also known as %s",
+		end:         ".",
+		separator:   " ",
+	},
+	"zh": {
+		entry:       "%s：%s。",
+		severity:    "严重性：%s。",
+		status:      "状态：%s。",
+		root:        "这是顶层条目。",
+		parent:      "它属于%s，%s",
+		ancestor:    "，后者属于%s，%s",
+		children:    "它有%d个子条目：",
+		child:       "%s，%s",
+		childSep:    "；",
+		moreChild:   "；以及其他%d个",
+		leaf:        "它没有子条目。",
+		description: "描述：%s",
+		mitigations: "共有%d条缓解措施。",
+		mitigation:  "第%d条，共%d条：%s",
+		aka:         "，又称%s",
+		end:         "。",
+		separator:   "",
+	},
+}
+
+// akaPattern 匹配CWE名称中的别名，如"... ('SQL Injection')"
+var akaPattern = regexp.MustCompile(`\s*\('([^']+)'\)`)
+
+// spokenIDPattern 匹配文本中的CWE ID
+var spokenIDPattern = regexp.MustCompile(`\bCWE-(\d+)\b`)
+
+// AccessibleText 生成适合屏幕阅读器和语音合成朗读的CWE文本
+//
+// 方法功能:
+// 将条目整理为完整的句子：逐字母读出"CWE"并去掉ID中的连字符，展开常见缩写，
+// 把名称中的"('别名')"改写为"又称"句式，并用语言描述条目在层次结构中的位置
+// (所属的父节点链和子节点)，供内部安全门户满足无障碍要求。
+//
+// 参数:
+// - entry: *CWE - 要朗读的条目
+// - opts: AccessibleOptions - 输出选项
+//
+// 返回值:
+// - string: 朗读文本；entry为nil时返回空字符串
+//
+// 使用示例:
+// ```go
+// text := cwe.AccessibleText(registry.Entries["CWE-89"], cwe.AccessibleOptions{IncludeMitigations: true})
+// fmt.Println(text)
+// // 输出类似: C W E 89: Improper Neutralization of Special Elements used in an S Q L Command,
+// // also known as S Q L Injection. Severity: High. It belongs to C W E 20, ...
+// ```
+func AccessibleText(entry *CWE, opts AccessibleOptions) string {
+	if entry == nil {
+		return ""
+	}
+
+	phrases, exists := accessibleLanguages[opts.Language]
+	if !exists {
+		phrases = accessibleLanguages["en"]
+	}
+	abbreviations := mergeAbbreviations(opts.Abbreviations)
+	speak := func(text string) string {
+		text = akaPattern.ReplaceAllStringFunc(text, func(match string) string {
+			return fmt.Sprintf(phrases.aka, akaPattern.FindStringSubmatch(match)[1])
+		})
+		return ExpandAbbreviations(spokenIDPattern.ReplaceAllString(text, "C W E $1"), abbreviations)
+	}
+
+	sentences := []string{fmt.Sprintf(phrases.entry, speak(entry.ID), speak(entry.Name))}
+	if entry.Severity != "" {
+		sentences = append(sentences, fmt.Sprintf(phrases.severity, entry.Severity))
+	}
+	if entry.Status != "" {
+		sentences = append(sentences, fmt.Sprintf(phrases.status, entry.Status))
+	}
+
+	// 层次结构：从父节点一直读到根节点
+	if entry.Parent == nil {
+		sentences = append(sentences, phrases.root)
+	} else {
+		var hierarchy strings.Builder
+		fmt.Fprintf(&hierarchy, phrases.parent, speak(entry.Parent.ID), speak(entry.Parent.Name))
+		seen := map[*CWE]bool{entry: true, entry.Parent: true}
+		for node := entry.Parent.Parent; node != nil && !seen[node]; node = node.Parent {
+			seen[node] = true
+			fmt.Fprintf(&hierarchy, phrases.ancestor, speak(node.ID), speak(node.Name))
+		}
+		hierarchy.WriteString(phrases.end)
+		sentences = append(sentences, hierarchy.String())
+	}
+
+	maxChildren := opts.MaxChildren
+	if maxChildren == 0 {
+		maxChildren = DefaultAccessibleMaxChildren
+	}
+	if maxChildren > 0 {
+		if len(entry.Children) == 0 {
+			sentences = append(sentences, phrases.leaf)
+		} else {
+			var children strings.Builder
+			fmt.Fprintf(&children, phrases.children, len(entry.Children))
+			for i, child := range entry.Children {
+				if i == maxChildren {
+					fmt.Fprintf(&children, phrases.moreChild, len(entry.Children)-maxChildren)
+					break
+				}
+				if i > 0 {
+					children.WriteString(phrases.childSep)
+				}
+				fmt.Fprintf(&children, phrases.child, speak(child.ID), speak(child.Name))
+			}
+			children.WriteString(phrases.end)
+			sentences = append(sentences, children.String())
+		}
+	}
+
+	if description := strings.Join(strings.Fields(entry.Description), " "); description != "" {
+		sentences = append(sentences, fmt.Sprintf(phrases.description, speak(description)))
+	}
+
+	if opts.IncludeMitigations && len(entry.Mitigations) > 0 {
+		sentences = append(sentences, fmt.Sprintf(phrases.mitigations, len(entry.Mitigations)))
+		for i, mitigation := range entry.Mitigations {
+			sentences = append(sentences, fmt.Sprintf(phrases.mitigation, i+1, len(entry.Mitigations), speak(mitigation)))
+		}
+	}
+
+	return strings.Join(sentences, phrases.separator)
+}
+
+// ExpandAbbreviations 将文本中的缩写替换为完整说法
+// 缩写区分大小写并按整词匹配，较长的缩写优先匹配
+func ExpandAbbreviations(text string, abbreviations map[string]string) string {
+	keys := make([]string, 0, len(abbreviations))
+	for abbr, expansion := range abbreviations {
+		if abbr != "" && expansion != "" {
+			keys = append(keys, regexp.QuoteMeta(abbr))
+		}
+	}
+	if len(keys) == 0 {
+		return text
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+
+	pattern := regexp.MustCompile(`\b(?:` + strings.Join(keys, "|") + `)\b`)
+	return pattern.ReplaceAllStringFunc(text, func(abbr string) string {
+		return abbreviations[abbr]
+	})
+}
+
+// mergeAbbreviations 合并默认缩写表和自定义缩写表
+func mergeAbbreviations(custom map[string]string) map[string]string {
+	merged := make(map[string]string, len(DefaultAbbreviations)+len(custom))
+	for abbr, expansion := range DefaultAbbreviations {
+		merged[abbr] = expansion
+	}
+	for abbr, expansion := range custom {
+		merged[abbr] = expansion
+	}
+	return merged
+}
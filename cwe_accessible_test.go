@@ -0,0 +1,90 @@
+package cwe
+
+import (
+	"strings"
+	"testing"
+)
+
+// newAccessibleTestTree 创建CWE-1000 -> CWE-20 -> CWE-89的测试树
+func newAccessibleTestTree() (*CWE, *CWE, *CWE) {
+	root := NewCWE("CWE-1000", "Research Concepts")
+	input := NewCWE("CWE-20", "Improper Input Validation")
+	sqli := NewCWE("CWE-89", "Improper Neutralization of Special Elements used in an SQL Command ('SQL Injection')")
+	sqli.Severity = "High"
+	sqli.Description = "The product constructs  an SQL command\nusing externally-influenced input."
+	sqli.Mitigations = append(sqli.Mitigations, "Use prepared statements.", "Validate input via an API.")
+	root.AddChild(input)
+	input.AddChild(sqli)
+	return root, input, sqli
+}
+
+func TestAccessibleTextEnglish(t *testing.T) {
+	root, input, sqli := newAccessibleTestTree()
+
+	text := AccessibleText(sqli, AccessibleOptions{IncludeMitigations: true})
+	expected := "C W E 89: Improper Neutralization of Special Elements used in an S Q L Command, also known as S Q L Injection. " +
+		"Severity: High. " +
+		"It belongs to C W E 20, Improper Input Validation, which belongs to C W E 1000, Research Concepts. " +
+		"It has no child entries. " +
+		"Description: The product constructs an S Q L command using externally-influenced input. " +
+		"There are 2 mitigations. " +
+		"Mitigation 1 of 2: Use prepared statements. " +
+		"Mitigation 2 of 2: Validate input via an application programming interface."
+	if text != expected {
+		t.Errorf("朗读文本不符合预期:\n got: %s\nwant: %s", text, expected)
+	}
+
+	text = AccessibleText(root, AccessibleOptions{})
+	if !strings.Contains(text, "This is a top-level entry.") ||
+		!strings.Contains(text, "It has 1 child entries: C W E 20, Improper Input Validation.") {
+		t.Errorf("根节点文本不正确: %s", text)
+	}
+
+	for i := 0; i < 3; i++ {
+		input.AddChild(NewCWE("CWE-"+string(rune('1'+i)), "Child"))
+	}
+	text = AccessibleText(input, AccessibleOptions{MaxChildren: 2})
+	if !strings.Contains(text, "It has 4 child entries: C W E 89,") || !strings.Contains(text, "; and 2 more.") {
+		t.Errorf("超出MaxChildren的子节点应只读出数量: %s", text)
+	}
+	if text := AccessibleText(input, AccessibleOptions{MaxChildren: -1}); strings.Contains(text, "child") {
+		t.Errorf("MaxChildren<0时不应朗读子节点: %s", text)
+	}
+}
+
+func TestAccessibleTextChinese(t *testing.T) {
+	_, input, _ := newAccessibleTestTree()
+
+	text := AccessibleText(input, AccessibleOptions{Language: "zh"})
+	expected := "C W E 20：Improper Input Validation。它属于C W E 1000，Research Concepts。" +
+		"它有1个子条目：C W E 89，Improper Neutralization of Special Elements used in an S Q L Command，又称S Q L Injection。"
+	if text != expected {
+		t.Errorf("朗读文本不符合预期:\n got: %s\nwant: %s", text, expected)
+	}
+}
+
+func TestAccessibleTextCustomAbbreviations(t *testing.T) {
+	entry := NewCWE("CWE-79", "XSS in CMS via SQL")
+	text := AccessibleText(entry, AccessibleOptions{
+		Abbreviations: map[string]string{"CMS": "content management system", "SQL": ""},
+	})
+	if !strings.HasPrefix(text, "C W E 79: cross-site scripting in content management system via SQL.") {
+		t.Errorf("自定义缩写未生效: %s", text)
+	}
+
+	if AccessibleText(nil, AccessibleOptions{}) != "" {
+		t.Error("nil条目应返回空字符串")
+	}
+}
+
+func TestExpandAbbreviations(t *testing.T) {
+	abbreviations := map[string]string{"OS": "operating system", "DoS": "denial of service"}
+	got := ExpandAbbreviations("OS command leads to DoS; COSMOS and DOS unchanged", abbreviations)
+	want := "operating system command leads to denial of service; COSMOS and DOS unchanged"
+	if got != want {
+		t.Errorf("ExpandAbbreviations() = %q, want %q", got, want)
+	}
+	if ExpandAbbreviations("text", nil) != "text" {
+		t.Error("空缩写表应原样返回")
+	}
+}
@@ -0,0 +1,241 @@
+package cwe
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Annotation 是用户附加在条目上的注解，如策略标签、负责人和备注
+//
+// 注解独立于条目保存在注册表中，不会修改来自MITRE的字段。
+// 导出快照和导出文件时注解写入单独的annotations部分，导入时随之恢复。
+type Annotation struct {
+	// Tags 自定义标签，如{"policy": "in-policy", "owner": "team-auth"}
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// Notes 按添加顺序排列的备注
+	Notes []string `json:"notes,omitempty"`
+}
+
+// isEmpty 判断注解是否没有任何标签和备注
+func (a Annotation) isEmpty() bool {
+	return len(a.Tags) == 0 && len(a.Notes) == 0
+}
+
+// clone 深拷贝注解
+func (a Annotation) clone() Annotation {
+	clone := Annotation{Notes: append([]string(nil), a.Notes...)}
+	if len(a.Tags) > 0 {
+		clone.Tags = make(map[string]string, len(a.Tags))
+		for key, value := range a.Tags {
+			clone.Tags[key] = value
+		}
+	}
+	return clone
+}
+
+// SetTag 为条目设置一个自定义标签
+//
+// 方法功能:
+// 在条目的注解上设置键值对，键已存在时覆盖原值。标签不会修改条目本身，
+// 适合标记"in-policy"、"accepted-risk"、负责人等团队内部信息，之后可以通过FindByTag查询。
+//
+// 参数:
+// - id: string - 条目ID，支持ParseCWEID接受的所有格式
+// - key: string - 标签名，不能为空
+// - value: string - 标签值，可以为空
+//
+// 返回值:
+// - error: 条目不存在时返回包装了ErrNotFound的错误，标签名为空时返回错误
+//
+// 使用示例:
+// ```go
+// registry.SetTag("CWE-79", "policy", "in-policy")
+// registry.SetTag("CWE-79", "owner", "team-web")
+//
+//	for _, entry := range registry.FindByTag("policy", "in-policy") {
+//	    fmt.Println(entry.ID)
+//	}
+//
+// ```
+func (r *Registry) SetTag(id, key, value string) error {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return fmt.Errorf("%s的标签名不能为空", id)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	entry, exists := r.Entries[normalizeEntryID(id)]
+	if !exists {
+		return &notFoundError{id: id}
+	}
+
+	annotation := r.annotations[entry.ID]
+	if annotation.Tags == nil {
+		annotation.Tags = make(map[string]string)
+	}
+	annotation.Tags[key] = value
+	r.setAnnotation(entry.ID, annotation)
+	return nil
+}
+
+// RemoveTag 删除条目的一个自定义标签，条目或标签不存在时不做任何操作
+func (r *Registry) RemoveTag(id, key string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	normalized := normalizeEntryID(id)
+	annotation, exists := r.annotations[normalized]
+	if !exists {
+		return
+	}
+	delete(annotation.Tags, strings.TrimSpace(key))
+	r.setAnnotation(normalized, annotation)
+}
+
+// AddNote 为条目追加一条备注
+//
+// 参数:
+// - id: string - 条目ID，支持ParseCWEID接受的所有格式
+// - note: string - 备注内容，不能为空
+//
+// 返回值:
+// - error: 条目不存在时返回包装了ErrNotFound的错误，备注为空时返回错误
+func (r *Registry) AddNote(id, note string) error {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return fmt.Errorf("%s的备注不能为空", id)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	entry, exists := r.Entries[normalizeEntryID(id)]
+	if !exists {
+		return &notFoundError{id: id}
+	}
+
+	annotation := r.annotations[entry.ID]
+	annotation.Notes = append(annotation.Notes, note)
+	r.setAnnotation(entry.ID, annotation)
+	return nil
+}
+
+// ClearAnnotation 删除条目的所有标签和备注
+func (r *Registry) ClearAnnotation(id string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.annotations, normalizeEntryID(id))
+}
+
+// GetAnnotation 返回条目的注解副本，修改返回值不会影响注册表
+// 第二个返回值表示条目是否有任何标签或备注
+func (r *Registry) GetAnnotation(id string) (Annotation, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	annotation, exists := r.annotations[normalizeEntryID(id)]
+	if !exists {
+		return Annotation{}, false
+	}
+	return annotation.clone(), true
+}
+
+// FindByTag 返回带有指定标签的条目
+//
+// 参数:
+// - key: string - 标签名
+// - value: string - 标签值，为空时匹配带有该标签的所有条目
+//
+// 返回值:
+// - []*CWE: 匹配的条目，按CWE编号排序
+func (r *Registry) FindByTag(key, value string) []*CWE {
+	key = strings.TrimSpace(key)
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	results := make([]*CWE, 0)
+	for id, annotation := range r.annotations {
+		tagValue, exists := annotation.Tags[key]
+		if !exists || (value != "" && tagValue != value) {
+			continue
+		}
+		if entry, exists := r.Entries[id]; exists {
+			results = append(results, entry)
+		}
+	}
+	sortByCWEID(results)
+	return results
+}
+
+// AnnotatedIDs 返回所有带有注解的条目ID，按CWE编号排序
+func (r *Registry) AnnotatedIDs() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	ids := make([]string, 0, len(r.annotations))
+	for id := range r.annotations {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return compareCWEIDs(ids[i], ids[j]) < 0
+	})
+	return ids
+}
+
+// setAnnotation 保存条目的注解，注解为空时删除，调用方需持有写锁
+func (r *Registry) setAnnotation(id string, annotation Annotation) {
+	if annotation.isEmpty() {
+		delete(r.annotations, id)
+		return
+	}
+	if r.annotations == nil {
+		r.annotations = make(map[string]Annotation)
+	}
+	r.annotations[id] = annotation
+}
+
+// copyAnnotations 深拷贝注解，keep不为nil时只保留keep返回true的条目
+func copyAnnotations(annotations map[string]Annotation, keep func(id string) bool) map[string]Annotation {
+	var copied map[string]Annotation
+	for id, annotation := range annotations {
+		if keep != nil && !keep(id) {
+			continue
+		}
+		if copied == nil {
+			copied = make(map[string]Annotation, len(annotations))
+		}
+		copied[id] = annotation.clone()
+	}
+	return copied
+}
+
+// GetAnnotation 返回条目的注解副本，见Registry.GetAnnotation
+func (s *FrozenRegistry) GetAnnotation(id string) (Annotation, bool) {
+	return s.registry.GetAnnotation(id)
+}
+
+// FindByTag 返回带有指定标签的条目，见Registry.FindByTag
+func (s *FrozenRegistry) FindByTag(key, value string) []*CWE {
+	return s.registry.FindByTag(key, value)
+}
+
+// restoreAnnotations 将快照或导出文件中的注解恢复到注册表，注解引用了不存在的条目时返回错误
+func (r *Registry) restoreAnnotations(annotations map[string]Annotation) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for id, annotation := range annotations {
+		entry, exists := r.Entries[normalizeEntryID(id)]
+		if !exists {
+			return fmt.Errorf("注解引用的条目%s未注册", id)
+		}
+		r.setAnnotation(entry.ID, annotation.clone())
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package cwe
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRegistryAnnotations(t *testing.T) {
+	registry := newQueryTestRegistry()
+
+	if err := registry.SetTag("79", "policy", "in-policy"); err != nil {
+		t.Fatalf("SetTag失败: %v", err)
+	}
+	registry.SetTag("CWE-89", "policy", "accepted-risk")
+	registry.SetTag("CWE-89", "owner", "team-db")
+	if err := registry.AddNote("CWE-89", "  ORM已参数化，见ADR-12  "); err != nil {
+		t.Fatalf("AddNote失败: %v", err)
+	}
+
+	if err := registry.SetTag("CWE-99999", "policy", "x"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("不存在的条目应返回ErrNotFound，实际: %v", err)
+	}
+	if err := registry.SetTag("CWE-79", " ", "x"); err == nil {
+		t.Error("空标签名应返回错误")
+	}
+	if err := registry.AddNote("CWE-79", ""); err == nil {
+		t.Error("空备注应返回错误")
+	}
+
+	annotation, exists := registry.GetAnnotation("CWE-89")
+	want := Annotation{Tags: map[string]string{"policy": "accepted-risk", "owner": "team-db"}, Notes: []string{"ORM已参数化，见ADR-12"}}
+	if !exists || !reflect.DeepEqual(annotation, want) {
+		t.Errorf("注解不正确: %+v", annotation)
+	}
+	annotation.Tags["policy"] = "modified"
+	if again, _ := registry.GetAnnotation("CWE-89"); again.Tags["policy"] != "accepted-risk" {
+		t.Error("修改返回的注解不应影响注册表")
+	}
+
+	if got := cweIDsOf(registry.FindByTag("policy", "")); !reflect.DeepEqual(got, []string{"CWE-79", "CWE-89"}) {
+		t.Errorf("FindByTag(policy)结果不正确: %v", got)
+	}
+	if got := cweIDsOf(registry.FindByTag("policy", "in-policy")); !reflect.DeepEqual(got, []string{"CWE-79"}) {
+		t.Errorf("FindByTag(policy=in-policy)结果不正确: %v", got)
+	}
+
+	// 删除最后一个标签后条目不再有注解
+	registry.RemoveTag("CWE-79", "policy")
+	if _, exists := registry.GetAnnotation("CWE-79"); exists {
+		t.Error("删除所有标签后不应再有注解")
+	}
+	if got := registry.AnnotatedIDs(); !reflect.DeepEqual(got, []string{"CWE-89"}) {
+		t.Errorf("AnnotatedIDs不正确: %v", got)
+	}
+	registry.ClearAnnotation("CWE-89")
+	if got := registry.AnnotatedIDs(); len(got) != 0 {
+		t.Errorf("ClearAnnotation后不应有注解: %v", got)
+	}
+}
+
+func TestRegistryAnnotationsExportImport(t *testing.T) {
+	registry := newQueryTestRegistry()
+	registry.SetTag("CWE-79", "policy", "in-policy")
+	registry.AddNote("CWE-306", "由网关统一处理")
+
+	var buf bytes.Buffer
+	if err := registry.WriteExportJSON(&buf); err != nil {
+		t.Fatalf("WriteExportJSON失败: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"annotations": {`) {
+		t.Errorf("导出文件缺少annotations部分:\n%s", buf.String())
+	}
+
+	restored := NewRegistry()
+	if err := restored.ReadExportJSON(&buf); err != nil {
+		t.Fatalf("ReadExportJSON失败: %v", err)
+	}
+	if annotation, _ := restored.GetAnnotation("CWE-79"); annotation.Tags["policy"] != "in-policy" {
+		t.Errorf("导入后标签丢失: %+v", annotation)
+	}
+	if annotation, _ := restored.GetAnnotation("CWE-306"); !reflect.DeepEqual(annotation.Notes, []string{"由网关统一处理"}) {
+		t.Errorf("导入后备注丢失: %+v", annotation)
+	}
+
+	// 注解不会写入条目本身
+	if !reflect.DeepEqual(NewRegistrySnapshot(restored).Entries, NewRegistrySnapshot(registry).Entries) {
+		t.Error("注解不应修改条目字段")
+	}
+
+	snapshot := NewRegistrySnapshot(registry)
+	snapshot.Annotations["CWE-12345"] = Annotation{Notes: []string{"x"}}
+	if _, err := snapshot.ToRegistry(); err == nil {
+		t.Error("注解引用不存在的条目时应返回错误")
+	}
+}
+
+func TestRegistryAnnotationsCopies(t *testing.T) {
+	registry := newQueryTestRegistry()
+	registry.SetTag("CWE-79", "owner", "team-web")
+	registry.SetTag("CWE-306", "owner", "team-auth")
+
+	clone := registry.Clone()
+	clone.SetTag("CWE-79", "owner", "changed")
+	if annotation, _ := registry.GetAnnotation("CWE-79"); annotation.Tags["owner"] != "team-web" {
+		t.Error("修改克隆的注解不应影响原注册表")
+	}
+
+	subtree, err := registry.Subtree("CWE-20")
+	if err != nil {
+		t.Fatalf("Subtree失败: %v", err)
+	}
+	if got := subtree.AnnotatedIDs(); !reflect.DeepEqual(got, []string{"CWE-79"}) {
+		t.Errorf("子树应只保留其中条目的注解: %v", got)
+	}
+
+	frozen := registry.Freeze()
+	if got := cweIDsOf(frozen.FindByTag("owner", "team-auth")); !reflect.DeepEqual(got, []string{"CWE-306"}) {
+		t.Errorf("FrozenRegistry.FindByTag结果不正确: %v", got)
+	}
+}
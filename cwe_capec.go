@@ -0,0 +1,398 @@
+package cwe
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CAPECBaseURL 是CAPEC攻击模式详情页的地址前缀，后接编号和".html"
+const CAPECBaseURL = "https://capec.mitre.org/data/definitions/"
+
+// ErrAttackPatternNotFound 表示CAPEC目录中不存在请求的攻击模式
+var ErrAttackPatternNotFound = errors.New("CAPEC目录中不存在该攻击模式")
+
+// capecPattern 匹配CAPEC ID，如"CAPEC-66"或"66"
+var capecPattern = regexp.MustCompile(`^(?:CAPEC-)?(\d+)$`)
+
+// bundledCAPECMapping 是内置的CWE到CAPEC映射，格式见LoadCAPECCatalogJSON
+// 只覆盖内置快照中部分常见弱点，完整数据请加载MITRE发布的CAPEC CSV
+//
+//go:embed data/capec-mapping.json
+var bundledCAPECMapping []byte
+
+var (
+	bundledCAPECOnce    sync.Once
+	bundledCAPECCatalog *CAPECCatalog
+	bundledCAPECErr     error
+)
+
+// AttackPattern 表示一个CAPEC攻击模式
+type AttackPattern struct {
+	// ID 攻击模式ID，格式为"CAPEC-数字"
+	ID string `json:"id"`
+
+	// Name 攻击模式名称
+	Name string `json:"name"`
+
+	// Description 攻击模式描述
+	Description string `json:"description,omitempty"`
+
+	// LikelihoodOfAttack 攻击可能性(High, Medium, Low等)
+	LikelihoodOfAttack string `json:"likelihood_of_attack,omitempty"`
+
+	// TypicalSeverity 典型严重性(Very High, High, Medium等)
+	TypicalSeverity string `json:"typical_severity,omitempty"`
+
+	// URL 攻击模式详情页的网址，为空时使用CAPECBaseURL生成
+	URL string `json:"url,omitempty"`
+
+	// RelatedWeaknesses 该攻击模式利用的CWE ID，格式为"CWE-数字"
+	RelatedWeaknesses []string `json:"related_weaknesses,omitempty"`
+}
+
+// capecCatalogFile 是LoadCAPECCatalogJSON读取的文件格式
+type capecCatalogFile struct {
+	Version        string          `json:"version,omitempty"`
+	AttackPatterns []AttackPattern `json:"attack_patterns"`
+}
+
+// CAPECCatalog 是CAPEC攻击模式目录，按CWE ID索引相关的攻击模式
+// 可以安全地在多个goroutine中并发使用
+type CAPECCatalog struct {
+	// Version 目录对应的CAPEC版本，未知时为空
+	Version string
+
+	// patterns 攻击模式ID到攻击模式的映射
+	patterns map[string]*AttackPattern
+
+	// byWeakness CWE ID到相关攻击模式ID的映射
+	byWeakness map[string][]string
+
+	mutex sync.RWMutex
+}
+
+// NewCAPECCatalog 创建空的CAPEC目录
+func NewCAPECCatalog() *CAPECCatalog {
+	return &CAPECCatalog{
+		patterns:   make(map[string]*AttackPattern),
+		byWeakness: make(map[string][]string),
+	}
+}
+
+// ParseCAPECID 将CAPEC ID规范化为"CAPEC-数字"格式
+// 接受"CAPEC-66"、"capec-66"和"66"等形式
+func ParseCAPECID(id string) (string, error) {
+	match := capecPattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(id)))
+	if match == nil {
+		return "", fmt.Errorf("无效的CAPEC ID: %s", id)
+	}
+	number, err := strconv.Atoi(match[1])
+	if err != nil {
+		return "", fmt.Errorf("无效的CAPEC ID: %s", id)
+	}
+	return fmt.Sprintf("CAPEC-%d", number), nil
+}
+
+// Add 添加攻击模式，ID相同的攻击模式会被替换
+//
+// 参数:
+// - pattern: AttackPattern - 攻击模式，ID和RelatedWeaknesses支持ParseCAPECID和ParseCWEID接受的格式
+//
+// 返回值:
+// - error: ID无效时返回错误
+func (c *CAPECCatalog) Add(pattern AttackPattern) error {
+	id, err := ParseCAPECID(pattern.ID)
+	if err != nil {
+		return err
+	}
+	pattern.ID = id
+
+	weaknesses := make([]string, 0, len(pattern.RelatedWeaknesses))
+	for _, weaknessID := range pattern.RelatedWeaknesses {
+		normalized, err := ParseCWEID(weaknessID)
+		if err != nil {
+			return fmt.Errorf("%s的相关弱点%w", id, err)
+		}
+		if !containsString(weaknesses, normalized) {
+			weaknesses = append(weaknesses, normalized)
+		}
+	}
+	pattern.RelatedWeaknesses = weaknesses
+	if pattern.URL == "" {
+		pattern.URL = capecURL(id)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if old, exists := c.patterns[id]; exists {
+		for _, weaknessID := range old.RelatedWeaknesses {
+			c.byWeakness[weaknessID] = removeString(c.byWeakness[weaknessID], id)
+		}
+	}
+	c.patterns[id] = &pattern
+	for _, weaknessID := range weaknesses {
+		c.byWeakness[weaknessID] = append(c.byWeakness[weaknessID], id)
+	}
+	return nil
+}
+
+// Get 获取攻击模式
+//
+// 返回值:
+// - AttackPattern: 攻击模式的副本
+// - error: ID无效或攻击模式不存在(包装ErrAttackPatternNotFound)时返回错误
+func (c *CAPECCatalog) Get(id string) (AttackPattern, error) {
+	normalized, err := ParseCAPECID(id)
+	if err != nil {
+		return AttackPattern{}, err
+	}
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	pattern, exists := c.patterns[normalized]
+	if !exists {
+		return AttackPattern{}, fmt.Errorf("%w: %s", ErrAttackPatternNotFound, normalized)
+	}
+	return copyAttackPattern(pattern), nil
+}
+
+// Len 返回目录中的攻击模式数量
+func (c *CAPECCatalog) Len() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return len(c.patterns)
+}
+
+// RelatedAttackPatterns 返回利用指定弱点的攻击模式
+//
+// 参数:
+// - cweID: string - CWE ID，支持ParseCWEID接受的所有格式
+//
+// 返回值:
+// - []AttackPattern: 按CAPEC编号排序的攻击模式副本，没有相关攻击模式时为空切片
+// - error: CWE ID无效时返回错误
+func (c *CAPECCatalog) RelatedAttackPatterns(cweID string) ([]AttackPattern, error) {
+	normalized, err := ParseCWEID(cweID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	ids := c.byWeakness[normalized]
+	patterns := make([]AttackPattern, 0, len(ids))
+	for _, id := range ids {
+		patterns = append(patterns, copyAttackPattern(c.patterns[id]))
+	}
+	sortAttackPatterns(patterns)
+	return patterns, nil
+}
+
+// LoadCAPECCatalogJSON 从JSON读取CAPEC目录
+//
+// 方法功能:
+// JSON格式为{"version": "3.9", "attack_patterns": [AttackPattern...]}，与内置映射文件相同，
+// 适合维护团队内部的映射或将LoadCAPECCatalogCSV的结果保存下来。
+//
+// 参数:
+// - r: io.Reader - JSON输入
+//
+// 返回值:
+// - *CAPECCatalog: 读取的目录
+// - error: JSON格式错误或ID无效时返回错误
+func LoadCAPECCatalogJSON(r io.Reader) (*CAPECCatalog, error) {
+	var file capecCatalogFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("解析CAPEC目录失败: %w", err)
+	}
+
+	catalog := NewCAPECCatalog()
+	catalog.Version = file.Version
+	for _, pattern := range file.AttackPatterns {
+		if err := catalog.Add(pattern); err != nil {
+			return nil, err
+		}
+	}
+	return catalog, nil
+}
+
+// LoadCAPECCatalogCSV 从MITRE发布的CAPEC CSV读取目录
+//
+// 方法功能:
+// 读取https://capec.mitre.org/data/downloads.html提供的CSV文件(如"Comprehensive CAPEC Dictionary")，
+// 使用ID、Name、Description、Likelihood Of Attack、Typical Severity和Related Weaknesses列，
+// 列名不区分大小写。Related Weaknesses列的格式为"::89::20::"。
+// 官方文件第一列的表头为"'ID"，读取时会忽略开头的单引号。
+//
+// 参数:
+// - r: io.Reader - CSV输入，可以带UTF-8 BOM
+//
+// 返回值:
+// - *CAPECCatalog: 读取的目录，Version为空
+// - error: 缺少ID列、格式错误或ID无效时返回错误
+//
+// 使用示例:
+// ```go
+// file, _ := os.Open("1000.csv")
+// defer file.Close()
+//
+// catalog, err := cwe.LoadCAPECCatalogCSV(file)
+// patterns, _ := catalog.RelatedAttackPatterns("CWE-89")
+// ```
+func LoadCAPECCatalogCSV(r io.Reader) (*CAPECCatalog, error) {
+	buffered := bufio.NewReader(r)
+	if bom, err := buffered.Peek(3); err == nil && string(bom) == "\ufeff" {
+		buffered.Discard(3)
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("读取CSV表头失败: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "'"))] = i
+	}
+	if _, exists := columns["id"]; !exists {
+		return nil, fmt.Errorf("CSV缺少ID列")
+	}
+
+	catalog := NewCAPECCatalog()
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取CSV失败: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		field := func(name string) string {
+			if i, exists := columns[name]; exists && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		if field("id") == "" {
+			// 跳过空行
+			continue
+		}
+		pattern := AttackPattern{
+			ID:                 field("id"),
+			Name:               field("name"),
+			Description:        field("description"),
+			LikelihoodOfAttack: field("likelihood of attack"),
+			TypicalSeverity:    field("typical severity"),
+		}
+		for _, weaknessID := range strings.Split(field("related weaknesses"), "::") {
+			if weaknessID = strings.TrimSpace(weaknessID); weaknessID != "" {
+				pattern.RelatedWeaknesses = append(pattern.RelatedWeaknesses, weaknessID)
+			}
+		}
+		if err := catalog.Add(pattern); err != nil {
+			return nil, fmt.Errorf("第%d行: %w", line, err)
+		}
+	}
+	return catalog, nil
+}
+
+// BundledCAPECCatalog 从内置映射创建CAPEC目录，不发送任何网络请求
+//
+// 内置映射只覆盖内置快照中部分常见弱点(如CWE-79、CWE-89、CWE-22)的攻击模式，
+// 需要完整数据时请使用LoadCAPECCatalogCSV加载MITRE发布的CSV。
+// 每次调用都返回新的目录，调用方可以自由添加攻击模式而不影响其他调用。
+func BundledCAPECCatalog() (*CAPECCatalog, error) {
+	catalog, err := LoadCAPECCatalogJSON(bytes.NewReader(bundledCAPECMapping))
+	if err != nil {
+		return nil, fmt.Errorf("读取内置CAPEC映射失败: %w", err)
+	}
+	return catalog, nil
+}
+
+// GetRelatedAttackPatterns 返回内置映射中利用指定弱点的CAPEC攻击模式
+//
+// 方法功能:
+// 威胁建模时从弱点转向攻击模式的快捷方式，使用BundledCAPECCatalog的数据，只在第一次调用时解析。
+// 内置映射不完整，没有结果不代表该弱点没有相关的攻击模式；
+// 需要最新数据时使用DataFetcher.FetchRelatedAttackPatterns从CWE API获取。
+//
+// 参数:
+// - id: string - CWE ID，支持ParseCWEID接受的所有格式
+//
+// 返回值:
+// - []AttackPattern: 按CAPEC编号排序的攻击模式，没有相关攻击模式时为空切片
+// - error: CWE ID无效时返回错误
+//
+// 使用示例:
+// ```go
+// patterns, err := cwe.GetRelatedAttackPatterns("CWE-89")
+//
+//	for _, pattern := range patterns {
+//	    fmt.Printf("%s: %s\n", pattern.ID, pattern.Name) // CAPEC-7: Blind SQL Injection ...
+//	}
+//
+// ```
+func GetRelatedAttackPatterns(id string) ([]AttackPattern, error) {
+	bundledCAPECOnce.Do(func() {
+		bundledCAPECCatalog, bundledCAPECErr = BundledCAPECCatalog()
+	})
+	if bundledCAPECErr != nil {
+		return nil, bundledCAPECErr
+	}
+	return bundledCAPECCatalog.RelatedAttackPatterns(id)
+}
+
+// copyAttackPattern 复制攻击模式，使调用方的修改不影响目录
+func copyAttackPattern(pattern *AttackPattern) AttackPattern {
+	copied := *pattern
+	copied.RelatedWeaknesses = append([]string(nil), pattern.RelatedWeaknesses...)
+	return copied
+}
+
+// sortAttackPatterns 按CAPEC编号排序攻击模式
+func sortAttackPatterns(patterns []AttackPattern) {
+	sort.SliceStable(patterns, func(i, j int) bool {
+		return capecNumber(patterns[i].ID) < capecNumber(patterns[j].ID)
+	})
+}
+
+// capecURL 返回规范化CAPEC ID的详情页地址
+func capecURL(id string) string {
+	return CAPECBaseURL + strings.TrimPrefix(id, "CAPEC-") + ".html"
+}
+
+// capecNumber 返回规范化CAPEC ID中的编号
+func capecNumber(id string) int {
+	number, _ := strconv.Atoi(strings.TrimPrefix(id, "CAPEC-"))
+	return number
+}
+
+// removeString 返回删除了所有value的切片
+func removeString(values []string, value string) []string {
+	kept := values[:0]
+	for _, v := range values {
+		if v != value {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
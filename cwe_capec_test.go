@@ -0,0 +1,167 @@
+package cwe
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func attackPatternIDs(patterns []AttackPattern) []string {
+	ids := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		ids = append(ids, pattern.ID)
+	}
+	return ids
+}
+
+func TestParseCAPECID(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"CAPEC-66", "CAPEC-66", false},
+		{"capec-066", "CAPEC-66", false},
+		{" 7 ", "CAPEC-7", false},
+		{"CWE-79", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCAPECID(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseCAPECID(%q) = %q, %v", tt.input, got, err)
+		}
+	}
+}
+
+func TestCAPECCatalog(t *testing.T) {
+	catalog := NewCAPECCatalog()
+	if err := catalog.Add(AttackPattern{ID: "66", Name: "SQL Injection", RelatedWeaknesses: []string{"89", "CWE-20", "20"}}); err != nil {
+		t.Fatalf("Add失败: %v", err)
+	}
+	catalog.Add(AttackPattern{ID: "CAPEC-7", Name: "Blind SQL Injection", RelatedWeaknesses: []string{"CWE-89"}})
+
+	patterns, err := catalog.RelatedAttackPatterns("89")
+	if err != nil {
+		t.Fatalf("RelatedAttackPatterns失败: %v", err)
+	}
+	if got := attackPatternIDs(patterns); !reflect.DeepEqual(got, []string{"CAPEC-7", "CAPEC-66"}) {
+		t.Errorf("CWE-89的攻击模式 = %v", got)
+	}
+	if patterns[1].URL != "https://capec.mitre.org/data/definitions/66.html" {
+		t.Errorf("未设置URL时应自动生成，实际为%q", patterns[1].URL)
+	}
+	if !reflect.DeepEqual(patterns[1].RelatedWeaknesses, []string{"CWE-89", "CWE-20"}) {
+		t.Errorf("相关弱点应规范化并去重，实际为%v", patterns[1].RelatedWeaknesses)
+	}
+
+	// 返回副本，修改不影响目录
+	patterns[1].RelatedWeaknesses[0] = "CWE-1"
+	if pattern, _ := catalog.Get("CAPEC-66"); pattern.RelatedWeaknesses[0] != "CWE-89" {
+		t.Error("修改返回值不应影响目录")
+	}
+
+	// 替换时更新索引
+	catalog.Add(AttackPattern{ID: "CAPEC-66", Name: "SQL Injection", RelatedWeaknesses: []string{"CWE-20"}})
+	patterns, _ = catalog.RelatedAttackPatterns("CWE-89")
+	if got := attackPatternIDs(patterns); !reflect.DeepEqual(got, []string{"CAPEC-7"}) {
+		t.Errorf("替换后CWE-89的攻击模式 = %v", got)
+	}
+	if catalog.Len() != 2 {
+		t.Errorf("Len() = %d", catalog.Len())
+	}
+
+	if _, err := catalog.Get("CAPEC-999"); !errors.Is(err, ErrAttackPatternNotFound) {
+		t.Errorf("不存在的攻击模式应返回ErrAttackPatternNotFound，实际为%v", err)
+	}
+	if err := catalog.Add(AttackPattern{ID: "CAPEC-1", RelatedWeaknesses: []string{"abc"}}); err == nil {
+		t.Error("相关弱点ID无效时应返回错误")
+	}
+	if _, err := catalog.RelatedAttackPatterns("abc"); err == nil {
+		t.Error("CWE ID无效时应返回错误")
+	}
+}
+
+func TestLoadCAPECCatalogCSV(t *testing.T) {
+	input := "\ufeff'ID,Name,Abstraction,Status,Description,Alternate Terms,Likelihood Of Attack,Typical Severity,Related Attack Patterns,Related Weaknesses\n" +
+		"66,SQL Injection,Standard,Draft,\"This attack exploits target software that constructs SQL statements\",,High,High,::NATURE:ChildOf:CAPEC ID:248::,::89::1286::\n" +
+		",,,,,,,,,\n" +
+		"7,Blind SQL Injection,Detailed,Draft,Blind SQL Injection results from an insufficient mitigation,,High,High,,::89::209::\n"
+
+	catalog, err := LoadCAPECCatalogCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("LoadCAPECCatalogCSV失败: %v", err)
+	}
+	if catalog.Len() != 2 {
+		t.Fatalf("应读取2个攻击模式，实际为%d", catalog.Len())
+	}
+
+	pattern, err := catalog.Get("66")
+	if err != nil {
+		t.Fatalf("Get失败: %v", err)
+	}
+	if pattern.Name != "SQL Injection" || pattern.LikelihoodOfAttack != "High" || pattern.TypicalSeverity != "High" {
+		t.Errorf("攻击模式字段不正确: %+v", pattern)
+	}
+	if !reflect.DeepEqual(pattern.RelatedWeaknesses, []string{"CWE-89", "CWE-1286"}) {
+		t.Errorf("相关弱点 = %v", pattern.RelatedWeaknesses)
+	}
+
+	patterns, _ := catalog.RelatedAttackPatterns("CWE-209")
+	if got := attackPatternIDs(patterns); !reflect.DeepEqual(got, []string{"CAPEC-7"}) {
+		t.Errorf("CWE-209的攻击模式 = %v", got)
+	}
+
+	if _, err := LoadCAPECCatalogCSV(strings.NewReader("Name\nfoo\n")); err == nil {
+		t.Error("缺少ID列时应返回错误")
+	}
+	if _, err := LoadCAPECCatalogCSV(strings.NewReader("ID,Name\nabc,foo\n")); err == nil || !strings.Contains(err.Error(), "第2行") {
+		t.Errorf("ID无效时应返回包含行号的错误，实际为%v", err)
+	}
+}
+
+func TestLoadCAPECCatalogJSON(t *testing.T) {
+	input := `{"version": "3.9", "attack_patterns": [{"id": "CAPEC-664", "name": "Server Side Request Forgery", "related_weaknesses": ["CWE-918"]}]}`
+	catalog, err := LoadCAPECCatalogJSON(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("LoadCAPECCatalogJSON失败: %v", err)
+	}
+	if catalog.Version != "3.9" || catalog.Len() != 1 {
+		t.Errorf("目录不正确: version=%q len=%d", catalog.Version, catalog.Len())
+	}
+
+	if _, err := LoadCAPECCatalogJSON(strings.NewReader("{")); err == nil {
+		t.Error("JSON格式错误时应返回错误")
+	}
+}
+
+func TestGetRelatedAttackPatterns(t *testing.T) {
+	patterns, err := GetRelatedAttackPatterns("89")
+	if err != nil {
+		t.Fatalf("GetRelatedAttackPatterns失败: %v", err)
+	}
+	if got := attackPatternIDs(patterns); !reflect.DeepEqual(got, []string{"CAPEC-7", "CAPEC-66", "CAPEC-108", "CAPEC-109", "CAPEC-110", "CAPEC-470"}) {
+		t.Errorf("CWE-89的攻击模式 = %v", got)
+	}
+
+	patterns, err = GetRelatedAttackPatterns("CWE-1000")
+	if err != nil || len(patterns) != 0 {
+		t.Errorf("没有映射的条目应返回空切片，实际为%v, %v", patterns, err)
+	}
+
+	// 内置映射中的每个弱点都应在内置快照中
+	catalog, err := BundledCAPECCatalog()
+	if err != nil {
+		t.Fatalf("BundledCAPECCatalog失败: %v", err)
+	}
+	offline, err := NewOfflineRegistry()
+	if err != nil {
+		t.Fatalf("NewOfflineRegistry失败: %v", err)
+	}
+	for id := range catalog.byWeakness {
+		if _, err := offline.GetByID(id); err != nil {
+			t.Errorf("内置映射引用了内置数据中不存在的%s", id)
+		}
+	}
+}
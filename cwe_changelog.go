@@ -0,0 +1,253 @@
+package cwe
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FieldChange 描述条目的一个字段在两个版本之间的变化
+type FieldChange struct {
+	// Field 字段名，与RegistryEvent.Fields相同，如"Description"、"Mitigations"、"Parent"
+	Field string `json:"field"`
+
+	// Old 和New 是文本字段(包括Parent的ID)变化前后的值
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+
+	// Added 和Removed 是列表字段(Mitigations、Children)中新增和删除的项，
+	// 两者都为空表示只有顺序发生了变化
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// EntryChange 描述单个条目在一次更新中的变化
+type EntryChange struct {
+	// Type 变化类型，取值与RegistryEvent.Type相同
+	Type RegistryEventType `json:"type"`
+
+	// ID 条目ID
+	ID string `json:"id"`
+
+	// Name 条目名称，删除的条目为旧名称，其他为新名称
+	Name string `json:"name"`
+
+	// Fields 修改的条目中每个发生变化的字段，新增和删除的条目为空
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+// ChangeSet 记录一次UpdateFrom中从FromVersion到ToVersion的所有条目变化
+type ChangeSet struct {
+	// FromVersion 更新前注册表的CWE版本，注册表原来没有版本时为空
+	FromVersion string `json:"from_version"`
+
+	// ToVersion 更新后注册表的CWE版本
+	ToVersion string `json:"to_version"`
+
+	// Timestamp 执行更新的时间
+	Timestamp time.Time `json:"timestamp"`
+
+	// Entries 发生变化的条目，按CWE编号排序
+	Entries []EntryChange `json:"entries"`
+}
+
+// UpdateFrom 用新版本的注册表替换当前内容，并在变更记录中保存逐条目、逐字段的差异
+//
+// 方法功能:
+// 按DiffRegistries的规则比较当前注册表和newer，记录每个新增、删除的条目以及修改条目的字段新旧值，
+// 然后用newer的副本替换条目、根节点、父子关系和版本。用户注解、同义词表和严重性覆盖层保留，
+// 已删除条目的注解被丢弃。之前的变更记录保留，新的记录追加在末尾，可以通过ChangesSince查询，
+// 并随快照和导出文件一起保存，供合规团队说明指导意见变化的原因。
+//
+// 参数:
+// - newer: *Registry - 新版本的注册表，之后修改它不会影响当前注册表
+//
+// 返回值:
+// - *ChangeSet: 本次更新的变化，也已追加到变更记录中
+// - error: newer为nil、没有版本或版本与当前注册表相同时返回错误，此时注册表保持不变
+//
+// 使用示例:
+// ```go
+// changes, err := registry.UpdateFrom(latest)
+//
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	for _, entry := range changes.Entries {
+//	    for _, field := range entry.Fields {
+//	        fmt.Printf("%s %s: %q -> %q %v\n", entry.ID, field.Field, field.Old, field.New, field.Added)
+//	    }
+//	}
+//
+// ```
+func (r *Registry) UpdateFrom(newer *Registry) (*ChangeSet, error) {
+	if newer == nil {
+		return nil, fmt.Errorf("新注册表为空")
+	}
+	toVersion := newer.Version()
+	if toVersion == "" {
+		return nil, fmt.Errorf("新注册表没有版本信息，无法记录变更")
+	}
+	fromVersion := r.Version()
+	if fromVersion == toVersion {
+		return nil, fmt.Errorf("新注册表的版本%s与当前版本相同", toVersion)
+	}
+
+	changes := &ChangeSet{
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+		Timestamp:   time.Now(),
+		Entries:     diffEntryChanges(r, newer),
+	}
+	imported := newer.Clone()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.Entries = imported.Entries
+	r.Root = imported.Root
+	r.parents = imported.parents
+	r.viewChildren = imported.viewChildren
+	r.hierarchyMode = imported.hierarchyMode
+	r.warnings = imported.warnings
+	r.version = imported.version
+	r.allowMixedVersions = imported.allowMixedVersions
+	r.annotations = copyAnnotations(r.annotations, func(id string) bool {
+		_, exists := imported.Entries[id]
+		return exists
+	})
+	r.changeLog = append(r.changeLog, *changes)
+	return changes, nil
+}
+
+// ChangesSince 返回从oldVersion之后的每次更新的变更记录，按更新顺序排列
+//
+// 参数:
+// - oldVersion: string - 起始版本，应为某次UpdateFrom之前注册表的版本
+//
+// 返回值:
+// - []ChangeSet: 从FromVersion为oldVersion的那次更新开始的所有记录；oldVersion为当前版本时返回空切片
+// - error: 变更记录中没有oldVersion时返回错误
+func (r *Registry) ChangesSince(oldVersion string) ([]ChangeSet, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if oldVersion == r.version {
+		return []ChangeSet{}, nil
+	}
+	for i, changes := range r.changeLog {
+		if changes.FromVersion == oldVersion {
+			return copyChangeLog(r.changeLog[i:]), nil
+		}
+	}
+	return nil, fmt.Errorf("变更记录中没有版本%s", oldVersion)
+}
+
+// ChangeLog 返回注册表的所有变更记录，按更新顺序排列，没有记录时返回nil
+func (r *Registry) ChangeLog() []ChangeSet {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return copyChangeLog(r.changeLog)
+}
+
+// diffEntryChanges 按CWE编号顺序返回两个注册表之间每个条目的变化
+func diffEntryChanges(before, after *Registry) []EntryChange {
+	beforeEntries, _ := diffEntries(before)
+	afterEntries, _ := diffEntries(after)
+
+	changes := make([]EntryChange, 0)
+	for _, event := range DiffRegistries(before, after) {
+		change := EntryChange{Type: event.Type, ID: event.ID, Name: event.Name}
+		if event.Type == EventEntryModified {
+			change.Fields = entryFieldChanges(beforeEntries[event.ID], afterEntries[event.ID])
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// entryFieldChanges 返回两个条目之间每个发生变化的字段及其新旧值
+// 比较的字段和顺序与changedEntryFields相同
+func entryFieldChanges(a, b *CWE) []FieldChange {
+	changes := make([]FieldChange, 0)
+	text := func(field, old, updated string) {
+		if old != updated {
+			changes = append(changes, FieldChange{Field: field, Old: old, New: updated})
+		}
+	}
+	list := func(field string, old, updated []string, separator string) {
+		if strings.Join(old, separator) != strings.Join(updated, separator) {
+			changes = append(changes, FieldChange{
+				Field:   field,
+				Added:   subtractItems(updated, old),
+				Removed: subtractItems(old, updated),
+			})
+		}
+	}
+
+	text("Name", a.Name, b.Name)
+	text("Description", a.Description, b.Description)
+	text("Severity", a.Severity, b.Severity)
+	text("Status", a.Status, b.Status)
+	text("Abstraction", a.Abstraction, b.Abstraction)
+	text("URL", a.URL, b.URL)
+	list("Mitigations", a.Mitigations, b.Mitigations, "\x00")
+	text("MappingUsage", mappingNotesUsage(a), mappingNotesUsage(b))
+	text("Parent", parentIDOf(a), parentIDOf(b))
+	list("Children", childIDsOf(a), childIDsOf(b), ",")
+	return changes
+}
+
+// subtractItems 返回在items中但不在removed中的项，重复项按出现次数计算
+func subtractItems(items, removed []string) []string {
+	counts := make(map[string]int, len(removed))
+	for _, item := range removed {
+		counts[item]++
+	}
+
+	var result []string
+	for _, item := range items {
+		if counts[item] > 0 {
+			counts[item]--
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// copyChangeLog 深拷贝变更记录，changeLog为空时返回nil
+func copyChangeLog(changeLog []ChangeSet) []ChangeSet {
+	if len(changeLog) == 0 {
+		return nil
+	}
+
+	copied := make([]ChangeSet, len(changeLog))
+	for i, changes := range changeLog {
+		copied[i] = changes
+		copied[i].Entries = make([]EntryChange, len(changes.Entries))
+		for j, entry := range changes.Entries {
+			if entry.Fields != nil {
+				fields := make([]FieldChange, len(entry.Fields))
+				for k, field := range entry.Fields {
+					field.Added = append([]string(nil), field.Added...)
+					field.Removed = append([]string(nil), field.Removed...)
+					fields[k] = field
+				}
+				entry.Fields = fields
+			}
+			copied[i].Entries[j] = entry
+		}
+	}
+	return copied
+}
+
+// ChangesSince 返回从oldVersion之后的每次更新的变更记录，见Registry.ChangesSince
+func (s *FrozenRegistry) ChangesSince(oldVersion string) ([]ChangeSet, error) {
+	return s.registry.ChangesSince(oldVersion)
+}
+
+// ChangeLog 返回注册表的所有变更记录，见Registry.ChangeLog
+func (s *FrozenRegistry) ChangeLog() []ChangeSet {
+	return s.registry.ChangeLog()
+}
@@ -0,0 +1,130 @@
+package cwe
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestRegistryUpdateFrom(t *testing.T) {
+	registry := newQueryTestRegistry()
+	registry.SetVersion("4.14")
+	if err := registry.SetTag("CWE-306", "team", "auth"); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.SetTag("CWE-79", "team", "web"); err != nil {
+		t.Fatal(err)
+	}
+
+	v415 := registry.Clone()
+	v415.SetVersion("4.15")
+	v415.Entries["CWE-79"].Description = "new description"
+	v415.Entries["CWE-79"].Mitigations = append(v415.Entries["CWE-79"].Mitigations, "Use a templating engine")
+	delete(v415.Entries, "CWE-306")
+
+	changes, err := registry.UpdateFrom(v415)
+	if err != nil {
+		t.Fatalf("UpdateFrom失败: %v", err)
+	}
+	if changes.FromVersion != "4.14" || changes.ToVersion != "4.15" || registry.Version() != "4.15" {
+		t.Errorf("版本不正确: %+v, 当前版本%s", changes, registry.Version())
+	}
+
+	var modified *EntryChange
+	for i := range changes.Entries {
+		if changes.Entries[i].ID == "CWE-79" {
+			modified = &changes.Entries[i]
+		}
+	}
+	if modified == nil || modified.Type != EventEntryModified {
+		t.Fatalf("应记录CWE-79的修改: %+v", changes.Entries)
+	}
+	wantFields := []FieldChange{
+		{Field: "Description", Old: newQueryTestRegistry().Entries["CWE-79"].Description, New: "new description"},
+		{Field: "Mitigations", Added: []string{"Use a templating engine"}},
+	}
+	if !reflect.DeepEqual(modified.Fields, wantFields) {
+		t.Errorf("字段变化不正确:\n%+v\n期望:\n%+v", modified.Fields, wantFields)
+	}
+
+	// 注册表内容被替换，已删除条目的注解被丢弃
+	if _, exists := registry.Entries["CWE-306"]; exists {
+		t.Error("CWE-306应已被删除")
+	}
+	if _, ok := registry.GetAnnotation("CWE-306"); ok {
+		t.Error("已删除条目的注解应被丢弃")
+	}
+	if _, ok := registry.GetAnnotation("CWE-79"); !ok {
+		t.Error("保留条目的注解应保留")
+	}
+
+	// 修改传入的注册表不影响当前注册表
+	v415.Entries["CWE-79"].Name = "changed"
+	if registry.Entries["CWE-79"].Name == "changed" {
+		t.Error("UpdateFrom应复制新注册表")
+	}
+
+	v416 := registry.Clone()
+	v416.SetVersion("4.16")
+	v416.Entries["CWE-79"].Severity = "Critical"
+	if _, err := registry.UpdateFrom(v416); err != nil {
+		t.Fatalf("UpdateFrom失败: %v", err)
+	}
+
+	since, err := registry.ChangesSince("4.14")
+	if err != nil || len(since) != 2 || since[1].ToVersion != "4.16" {
+		t.Errorf("ChangesSince(4.14)应返回两次更新: %+v, %v", since, err)
+	}
+	if since, err := registry.ChangesSince("4.15"); err != nil || len(since) != 1 || since[0].Entries[0].Fields[0].Field != "Severity" {
+		t.Errorf("ChangesSince(4.15)应只返回最后一次更新: %+v, %v", since, err)
+	}
+	if since, err := registry.ChangesSince("4.16"); err != nil || len(since) != 0 {
+		t.Errorf("当前版本没有变更: %+v, %v", since, err)
+	}
+	if _, err := registry.ChangesSince("4.0"); err == nil {
+		t.Error("未知版本应返回错误")
+	}
+
+	if _, err := registry.UpdateFrom(v416); err == nil {
+		t.Error("相同版本应返回错误")
+	}
+	if _, err := registry.UpdateFrom(NewRegistry()); err == nil {
+		t.Error("没有版本的注册表应返回错误")
+	}
+}
+
+func TestRegistryChangeLogPersistence(t *testing.T) {
+	registry := newQueryTestRegistry()
+	registry.SetVersion("4.14")
+	newer := registry.Clone()
+	newer.SetVersion("4.15")
+	newer.Entries["CWE-79"].Name = "XSS"
+	if _, err := registry.UpdateFrom(newer); err != nil {
+		t.Fatal(err)
+	}
+	want := registry.ChangeLog()
+
+	restored, err := NewRegistrySnapshot(registry).ToRegistry()
+	if err != nil {
+		t.Fatalf("恢复快照失败: %v", err)
+	}
+	if !reflect.DeepEqual(restored.ChangeLog(), want) {
+		t.Errorf("快照应保存变更记录: %+v", restored.ChangeLog())
+	}
+
+	var buf bytes.Buffer
+	if err := registry.WriteExportJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	imported := NewRegistry()
+	if err := imported.ReadExportJSON(&buf); err != nil {
+		t.Fatalf("导入失败: %v", err)
+	}
+	if got := imported.ChangeLog(); len(got) != 1 || got[0].Entries[0].Fields[0].New != "XSS" || !got[0].Timestamp.Equal(want[0].Timestamp) {
+		t.Errorf("导出文件应保存变更记录: %+v", got)
+	}
+
+	if !reflect.DeepEqual(registry.Clone().ChangeLog(), want) || !reflect.DeepEqual(registry.Freeze().ChangeLog(), want) {
+		t.Error("Clone和Freeze应保留变更记录")
+	}
+}
@@ -0,0 +1,160 @@
+package cwe
+
+import "encoding/json"
+
+// Clone 复制CWE条目
+//
+// 方法功能:
+// deep为false时只复制当前节点：Mitigations、Examples、Relations和Children切片是新的，
+// 但Children中的元素和Parent仍指向原来的节点，适合只修改当前节点字段的场景。
+// deep为true时复制当前节点及其所有后代，副本之间的Parent和Children只指向副本，
+// 副本根节点的Parent为nil；多父节点的后代只复制一次，树中的环也会被正确复制。
+//
+// 参数:
+// - deep: bool - 是否复制整个子树
+//
+// 返回值:
+// - *CWE: 副本，c为nil时返回nil
+//
+// 使用示例:
+// ```go
+// working := registry.Root.Clone(true)
+//
+//	working.Prune(func(entry *cwe.CWE) bool {
+//	    return entry.Status == "Deprecated"
+//	})
+//
+// ```
+func (c *CWE) Clone(deep bool) *CWE {
+	if c == nil {
+		return nil
+	}
+	if deep {
+		return copyTree([]*CWE{c})[c]
+	}
+
+	copied := copyFields(c)
+	copied.Parent = c.Parent
+	copied.Children = append([]*CWE(nil), c.Children...)
+	return copied
+}
+
+// Clone 深拷贝注册表
+//
+// 方法功能:
+// 复制所有条目及其父子关系、Root、层次模式、多父节点记录、视图关系、警告、版本设置、同义词表和严重性覆盖层。
+// 副本与原注册表不共享任何可变状态，可以在共享的缓存注册表上安全地剪枝、标注或修改。
+// 条目的Children中不在Entries里的节点也会被复制，但不会加入副本的Entries。
+//
+// 返回值:
+// - *Registry: 注册表的副本
+//
+// 使用示例:
+// ```go
+// working := cachedRegistry.Clone()
+// working.Root.Prune(func(entry *cwe.CWE) bool { return entry.IsLeaf() })
+// ```
+func (r *Registry) Clone() *Registry {
+	entries := r.Snapshot()
+	starts := entries
+	if r.Root != nil {
+		starts = append([]*CWE{r.Root}, entries...)
+	}
+	copies := copyTree(starts)
+
+	clone := NewRegistry()
+	clone.Root = copies[r.Root]
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	for id, entry := range r.Entries {
+		clone.Entries[id] = copies[entry]
+	}
+	clone.hierarchyMode = r.hierarchyMode
+	clone.parents = copyParents(r.parents)
+	clone.viewChildren = copyViewChildren(r.viewChildren, nil)
+	clone.warnings = append([]error(nil), r.warnings...)
+	clone.version = r.version
+	clone.allowMixedVersions = r.allowMixedVersions
+	clone.synonyms = r.synonyms
+	clone.annotations = copyAnnotations(r.annotations, nil)
+	clone.changeLog = copyChangeLog(r.changeLog)
+	if r.severityOverlay != nil {
+		clone.severityOverlay = r.severityOverlay.Clone()
+	}
+	return clone
+}
+
+// Clone 复制严重性覆盖层
+func (o *SeverityOverlay) Clone() *SeverityOverlay {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	clone := NewSeverityOverlay()
+	for id, severity := range o.overrides {
+		clone.overrides[id] = severity
+	}
+	return clone
+}
+
+// copyTree 复制从starts出发沿Children可达的所有节点，返回原节点到副本的映射
+//
+// 副本的Children按原顺序指向副本。原Parent也被复制时副本的Parent指向其副本，
+// 否则指向第一个到达该节点的父节点的副本；starts中原Parent未被复制的节点Parent为nil。
+func copyTree(starts []*CWE) map[*CWE]*CWE {
+	copies := make(map[*CWE]*CWE)
+	isStart := make(map[*CWE]bool, len(starts))
+	queue := make([]*CWE, 0, len(starts))
+	for _, start := range starts {
+		isStart[start] = true
+		if _, exists := copies[start]; !exists {
+			copies[start] = copyFields(start)
+			queue = append(queue, start)
+		}
+	}
+
+	reachedFrom := make(map[*CWE]*CWE)
+	for len(queue) > 0 {
+		original := queue[0]
+		queue = queue[1:]
+		parentCopy := copies[original]
+		for _, child := range original.Children {
+			childCopy, exists := copies[child]
+			if !exists {
+				childCopy = copyFields(child)
+				copies[child] = childCopy
+				queue = append(queue, child)
+			}
+			if _, exists := reachedFrom[child]; !exists {
+				reachedFrom[child] = original
+			}
+			parentCopy.Children = append(parentCopy.Children, childCopy)
+		}
+	}
+
+	for original, entryCopy := range copies {
+		if parentCopy, exists := copies[original.Parent]; exists && original.Parent != nil {
+			entryCopy.Parent = parentCopy
+		} else if from, exists := reachedFrom[original]; exists && !isStart[original] {
+			entryCopy.Parent = copies[from]
+		}
+	}
+	return copies
+}
+
+// copyFields 复制条目的字段和切片，不复制Parent和Children
+func copyFields(entry *CWE) *CWE {
+	copied := *entry
+	copied.Parent = nil
+	copied.Children = nil
+	copied.Mitigations = append([]string(nil), entry.Mitigations...)
+	copied.MitigationDetails = copyMitigations(entry.MitigationDetails)
+	copied.MappingNotes = copyMappingNotes(entry.MappingNotes)
+	copied.Examples = append([]string(nil), entry.Examples...)
+	copied.ObservedExamples = append([]CWEObservedExample(nil), entry.ObservedExamples...)
+	copied.Relations = append([]CWERelation(nil), entry.Relations...)
+	copied.AlternateTerms = append([]string(nil), entry.AlternateTerms...)
+	copied.Translations = copyTranslations(entry.Translations)
+	copied.raw = append(json.RawMessage(nil), entry.raw...)
+	return &copied
+}
@@ -0,0 +1,104 @@
+package cwe
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCWECloneShallow(t *testing.T) {
+	registry := newQueryTestRegistry()
+	original := registry.Entries["CWE-20"]
+
+	clone := original.Clone(false)
+	if clone == original || clone.ID != original.ID {
+		t.Fatal("Clone(false)应返回新的节点")
+	}
+	if clone.Parent != original.Parent || clone.Children[0] != original.Children[0] {
+		t.Error("浅复制应保留对原Parent和子节点的引用")
+	}
+
+	clone.Children = append(clone.Children[:0], NewCWE("CWE-1", "new"))
+	clone.Name = "changed"
+	if len(original.Children) != 2 || original.Children[0].ID != "CWE-79" || original.Name == "changed" {
+		t.Error("修改浅复制不应影响原节点")
+	}
+
+	var nilEntry *CWE
+	if nilEntry.Clone(true) != nil {
+		t.Error("nil节点的副本应为nil")
+	}
+}
+
+func TestCWECloneDeep(t *testing.T) {
+	registry := newQueryTestRegistry()
+	original := registry.Entries["CWE-20"]
+	// 构造一个环
+	registry.Entries["CWE-89"].Children = append(registry.Entries["CWE-89"].Children, original)
+
+	clone := original.Clone(true)
+	if clone.Parent != nil {
+		t.Error("深复制根节点的Parent应为nil")
+	}
+	xss := clone.Children[0]
+	if xss == registry.Entries["CWE-79"] || xss.Parent != clone {
+		t.Error("深复制的子节点应指向副本")
+	}
+	if sqli := clone.Children[1]; sqli.Children[0] != clone {
+		t.Error("环应指向副本而不是原节点")
+	}
+
+	xss.Mitigations[0] = "changed"
+	if registry.Entries["CWE-79"].Mitigations[0] == "changed" {
+		t.Error("修改副本的切片影响了原节点")
+	}
+}
+
+func TestRegistryClone(t *testing.T) {
+	registry := newQueryTestRegistry()
+	registry.SetHierarchyMode(HierarchyMultiParent)
+	if err := registry.BuildHierarchy(map[string][]string{"CWE-287": {"CWE-89"}}); err != nil {
+		t.Fatalf("BuildHierarchy失败: %v", err)
+	}
+	overlay := NewSeverityOverlay()
+	overlay.Set("CWE-79", "Low")
+	registry.SetSeverityOverlay(overlay)
+	registry.addWarning(errors.New("warning"))
+
+	clone := registry.Clone()
+
+	if len(clone.Entries) != len(registry.Entries) {
+		t.Fatalf("期望%d个条目，实际 %d", len(registry.Entries), len(clone.Entries))
+	}
+	for id, entry := range clone.Entries {
+		if entry == registry.Entries[id] {
+			t.Fatalf("%s没有被复制", id)
+		}
+	}
+	if clone.Root != clone.Entries["CWE-1000"] {
+		t.Error("副本的Root应指向副本中的条目")
+	}
+	if clone.Entries["CWE-79"].Parent != clone.Entries["CWE-20"] {
+		t.Error("副本的Parent应指向副本中的条目")
+	}
+	if parents, _ := clone.Parents("CWE-89"); len(parents) != 2 || parents[1] != clone.Entries["CWE-287"] {
+		t.Errorf("多父节点关系应被复制: %v", parents)
+	}
+	if len(clone.Warnings()) != 1 {
+		t.Error("警告应被复制")
+	}
+
+	// 修改副本不影响原注册表
+	clone.Root.Prune(func(entry *CWE) bool { return entry.ID == "CWE-20" })
+	clone.GetSeverityOverlay().Set("CWE-79", "Critical")
+	clone.SetHierarchyMode(HierarchyOverwrite)
+
+	if len(registry.Root.Children) != 2 {
+		t.Error("剪枝副本影响了原树")
+	}
+	if severity, _ := registry.EffectiveSeverity("CWE-79"); severity != "Low" {
+		t.Errorf("修改副本的覆盖层影响了原注册表: %s", severity)
+	}
+	if severity, _ := clone.EffectiveSeverity("CWE-79"); severity != "Critical" {
+		t.Errorf("副本的覆盖层没有生效: %s", severity)
+	}
+}
@@ -0,0 +1,182 @@
+package cwe
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 内容历史记录的类型，取值与CWE XML中Content_History的子元素名称一致
+const (
+	// HistorySubmission 条目最初提交
+	HistorySubmission = "Submission"
+
+	// HistoryModification 条目内容被修改
+	HistoryModification = "Modification"
+)
+
+// historyDateLayouts 内容历史中可能出现的日期格式，官方API和CWE XML使用"2006-01-02"
+var historyDateLayouts = []string{
+	"2006-01-02",
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+}
+
+// TimelineEvent 是内容历史中一条记录的类型化表示
+type TimelineEvent struct {
+	// Type 记录类型，如HistorySubmission、HistoryModification
+	Type string
+
+	// Date 提交或修改日期
+	Date time.Time
+
+	// Name 提交或修改者名称
+	Name string
+
+	// Organization 提交或修改组织
+	Organization string
+
+	// Comment 修改评论，提交记录为空
+	Comment string
+
+	// Version 提交或修改所在的CWE版本，如"4.15"
+	Version string
+
+	// ReleaseDate 包含该变化的CWE版本的发布日期，未知时为零值
+	ReleaseDate time.Time
+}
+
+// ParseHistoryDate 解析内容历史中的日期
+//
+// 支持"2006-01-02"、RFC 3339以及不带时区的"2006-01-02T15:04:05"和"2006-01-02 15:04:05"格式，
+// 不带时区的日期按UTC解析。
+func ParseHistoryDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range historyDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("无法解析内容历史日期: %q", value)
+}
+
+// Event 将内容历史记录转换为TimelineEvent
+// 修改记录使用Modification*字段，其他记录使用Submission*字段；日期无法解析时返回错误，
+// 发布日期无法解析时保持零值
+func (e CWEContentHistoryEntry) Event() (TimelineEvent, error) {
+	event := TimelineEvent{
+		Type:         e.Type,
+		Name:         e.SubmissionName,
+		Organization: e.SubmissionOrganization,
+		Version:      e.SubmissionVersion,
+	}
+	date, releaseDate := e.SubmissionDate, e.SubmissionReleaseDate
+	if e.Type == HistoryModification || (e.SubmissionDate == "" && e.ModificationDate != "") {
+		event.Name = e.ModificationName
+		event.Organization = e.ModificationOrganization
+		event.Comment = e.ModificationComment
+		event.Version = e.ModificationVersion
+		date, releaseDate = e.ModificationDate, e.ModificationReleaseDate
+	}
+
+	parsed, err := ParseHistoryDate(date)
+	if err != nil {
+		return TimelineEvent{}, err
+	}
+	event.Date = parsed
+	if releaseDate != "" {
+		event.ReleaseDate, _ = ParseHistoryDate(releaseDate)
+	}
+	return event, nil
+}
+
+// BuildTimeline 将内容历史转换为按日期升序排列的时间线
+// 日期无法解析的记录被跳过，日期相同的记录保持原有顺序
+func BuildTimeline(history []CWEContentHistoryEntry) []TimelineEvent {
+	timeline := make([]TimelineEvent, 0, len(history))
+	for _, entry := range history {
+		if event, err := entry.Event(); err == nil {
+			timeline = append(timeline, event)
+		}
+	}
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Date.Before(timeline[j].Date)
+	})
+	return timeline
+}
+
+// lastModified 返回时间线中最晚的日期，时间线为空时返回零值
+// 提交记录也计入，因此从未修改过的条目返回提交日期
+func lastModified(history []CWEContentHistoryEntry) time.Time {
+	var latest time.Time
+	for _, event := range BuildTimeline(history) {
+		if event.Date.After(latest) {
+			latest = event.Date
+		}
+	}
+	return latest
+}
+
+// Timeline 返回弱点按日期升序排列的内容历史，见BuildTimeline
+// 内容历史被字段掩码丢弃时返回空切片
+func (w *CWEWeakness) Timeline() []TimelineEvent {
+	return BuildTimeline(w.ContentHistory)
+}
+
+// LastModified 返回弱点最后一次提交或修改的日期，没有可解析的内容历史时返回零值
+func (w *CWEWeakness) LastModified() time.Time {
+	return lastModified(w.ContentHistory)
+}
+
+// Timeline 返回类别按日期升序排列的内容历史，见BuildTimeline
+func (c *CWECategory) Timeline() []TimelineEvent {
+	return BuildTimeline(c.ContentHistory)
+}
+
+// LastModified 返回类别最后一次提交或修改的日期，没有可解析的内容历史时返回零值
+func (c *CWECategory) LastModified() time.Time {
+	return lastModified(c.ContentHistory)
+}
+
+// Timeline 返回视图按日期升序排列的内容历史，见BuildTimeline
+func (v *CWEView) Timeline() []TimelineEvent {
+	return BuildTimeline(v.ContentHistory)
+}
+
+// LastModified 返回视图最后一次提交或修改的日期，没有可解析的内容历史时返回零值
+func (v *CWEView) LastModified() time.Time {
+	return lastModified(v.ContentHistory)
+}
+
+// ModifiedSince 返回LastModified不早于since的条目，按CWE编号排序
+//
+// 方法功能:
+// 只有从API获取且获取时保留了内容历史的条目才有LastModified，
+// LastModified为零值的条目不会被返回。
+//
+// 参数:
+// - since: time.Time - 起始时间(包含)
+//
+// 返回值:
+// - []*CWE: 在since之后提交或修改过的条目
+//
+// 使用示例:
+// ```go
+// cutoff := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+//
+//	for _, entry := range registry.ModifiedSince(cutoff) {
+//	    fmt.Println(entry.ID, entry.LastModified.Format("2006-01-02"))
+//	}
+//
+// ```
+func (r *Registry) ModifiedSince(since time.Time) []*CWE {
+	result := make([]*CWE, 0)
+	for _, entry := range r.Snapshot() {
+		if !entry.LastModified.IsZero() && !entry.LastModified.Before(since) {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
@@ -0,0 +1,86 @@
+package cwe
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildTimeline(t *testing.T) {
+	weakness := &CWEWeakness{
+		ID: "79",
+		ContentHistory: []CWEContentHistoryEntry{
+			{Type: HistoryModification, ModificationName: "CWE Content Team", ModificationDate: "2023-06-29", ModificationComment: "updated Demonstrative_Examples", ModificationVersion: "4.12"},
+			{Type: HistorySubmission, SubmissionName: "PLOVER", SubmissionDate: "2006-07-19", SubmissionReleaseDate: "2006-07-19", SubmissionVersion: "Draft 3"},
+			{Type: HistoryModification, ModificationDate: "not a date"},
+			{Type: HistoryModification, ModificationName: "Eldar Marcussen", ModificationDate: "2008-07-01T10:00:00Z"},
+		},
+	}
+
+	timeline := weakness.Timeline()
+	if len(timeline) != 3 {
+		t.Fatalf("日期无法解析的记录应被跳过，实际有%d条", len(timeline))
+	}
+	if timeline[0].Type != HistorySubmission || timeline[0].Name != "PLOVER" || timeline[0].ReleaseDate.IsZero() {
+		t.Errorf("第一条应为提交记录: %+v", timeline[0])
+	}
+	if timeline[1].Name != "Eldar Marcussen" || timeline[1].Date.Hour() != 10 {
+		t.Errorf("第二条应为2008年的修改: %+v", timeline[1])
+	}
+	if timeline[2].Comment != "updated Demonstrative_Examples" || timeline[2].Version != "4.12" {
+		t.Errorf("修改记录应使用Modification字段: %+v", timeline[2])
+	}
+
+	if want := time.Date(2023, 6, 29, 0, 0, 0, 0, time.UTC); !weakness.LastModified().Equal(want) {
+		t.Errorf("LastModified = %v，期望%v", weakness.LastModified(), want)
+	}
+	if !(&CWEView{}).LastModified().IsZero() {
+		t.Error("没有内容历史时LastModified应为零值")
+	}
+
+	if _, err := ParseHistoryDate("2024-13-01"); err == nil {
+		t.Error("无效日期应返回错误")
+	}
+}
+
+func TestRegistryModifiedSince(t *testing.T) {
+	registry := NewRegistry()
+	old := NewCWE("CWE-20", "Improper Input Validation")
+	old.LastModified = time.Date(2020, 2, 24, 0, 0, 0, 0, time.UTC)
+	recent := NewCWE("CWE-79", "Cross-site Scripting")
+	recent.LastModified = time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	registry.Register(old)
+	registry.Register(recent)
+	registry.Register(NewCWE("CWE-89", "SQL Injection"))
+
+	modified := registry.ModifiedSince(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if len(modified) != 1 || modified[0].ID != "CWE-79" {
+		t.Errorf("应只返回2024年后修改的条目: %v", modified)
+	}
+	if len(registry.ModifiedSince(recent.LastModified)) != 1 {
+		t.Error("起始时间应包含在内")
+	}
+
+	restored, err := NewRegistrySnapshot(registry).ToRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !restored.Entries["CWE-79"].LastModified.Equal(recent.LastModified) || !restored.Entries["CWE-89"].LastModified.IsZero() {
+		t.Error("快照应保存LastModified")
+	}
+}
+
+func TestFetchWeaknessLastModified(t *testing.T) {
+	client := newFieldMaskTestClient(t, `{"weaknesses": [{"id": "79", "name": "XSS", "content_history": [
+		{"type": "Submission", "submission_date": "2006-07-19"},
+		{"type": "Modification", "modification_date": "2024-02-29"}
+	]}]}`)
+	fetcher := NewDataFetcherWithClient(client)
+
+	entry, err := fetcher.FetchWeakness("79")
+	if err != nil {
+		t.Fatalf("FetchWeakness失败: %v", err)
+	}
+	if want := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC); !entry.LastModified.Equal(want) {
+		t.Errorf("LastModified = %v，期望%v", entry.LastModified, want)
+	}
+}
@@ -0,0 +1,255 @@
+package cwe
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// CoverageOptions 控制覆盖率报告的计算方式
+type CoverageOptions struct {
+	// IncludeDescendants 为true时映射到非叶子条目(如类别或Class级别的弱点)视为覆盖其下所有叶子，
+	// 默认只有直接映射到的叶子算作已覆盖
+	IncludeDescendants bool
+}
+
+// CoverageReport 是一组CWE ID对视图中叶子条目的覆盖率报告，由Registry.CoverageReport生成
+type CoverageReport struct {
+	// RootID 视图根节点ID，注册表没有Root时为空
+	RootID string `json:"root_id,omitempty"`
+
+	// TotalLeaves 所有顶层分支下不重复的叶子条目数
+	TotalLeaves int `json:"total_leaves"`
+
+	// CoveredLeaves 已覆盖的不重复叶子条目数
+	CoveredLeaves int `json:"covered_leaves"`
+
+	// Percentage 总覆盖率，取值范围[0, 100]
+	Percentage float64 `json:"percentage"`
+
+	// Pillars 每个顶层分支的覆盖情况，按CWE编号排序
+	Pillars []PillarCoverage `json:"pillars"`
+
+	// Unknown 输入中无法解析或不在注册表中的ID，已排序
+	Unknown []string `json:"unknown,omitempty"`
+}
+
+// PillarCoverage 是一个顶层分支(根节点的子节点)下叶子条目的覆盖情况
+type PillarCoverage struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+
+	// TotalLeaves 分支下的叶子条目数，分支本身是叶子时为1
+	TotalLeaves int `json:"total_leaves"`
+
+	// CoveredLeaves 已覆盖的叶子条目数
+	CoveredLeaves int `json:"covered_leaves"`
+
+	// Percentage 分支的覆盖率，取值范围[0, 100]
+	Percentage float64 `json:"percentage"`
+
+	// Covered 已覆盖的叶子条目ID，按CWE编号排序
+	Covered []string `json:"covered"`
+
+	// Uncovered 未覆盖的叶子条目ID，按CWE编号排序
+	Uncovered []string `json:"uncovered"`
+}
+
+// CoverageReport 计算一组CWE ID对视图叶子条目的覆盖率
+//
+// 方法功能:
+// 以Root的每个子节点作为顶层分支(研究视图CWE-1000下即为Pillar)，没有Root时以所有没有父节点的条目作为顶层分支，
+// 统计每个分支下的叶子条目有多少出现在ids中，并汇总为总覆盖率。
+// 多父节点的叶子会计入它所属的每个分支，但在总数中只计算一次。
+// 典型的输入是静态分析工具规则元数据中的CWE ID，用于评估扫描器对CWE的覆盖情况。
+//
+// 参数:
+// - ids: []string - 已覆盖的CWE ID，支持ParseCWEID接受的所有格式，重复的ID会被忽略
+// - opts: CoverageOptions - 计算选项
+//
+// 返回值:
+// - *CoverageReport: 覆盖率报告，可以通过WriteJSON或WriteMarkdown输出
+//
+// 使用示例:
+// ```go
+// registry, _ := cwe.NewDataFetcher().BuildCWETreeWithView("1000")
+// report := registry.CoverageReport([]string{"CWE-79", "CWE-89", "CWE-22"}, cwe.CoverageOptions{})
+//
+// fmt.Printf("叶子覆盖率: %.1f%%\n", report.Percentage)
+// report.WriteMarkdown(os.Stdout)
+// ```
+func (r *Registry) CoverageReport(ids []string, opts CoverageOptions) *CoverageReport {
+	roots := r.walkRoots()
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	report := &CoverageReport{Pillars: make([]PillarCoverage, 0)}
+	if r.Root != nil {
+		report.RootID = r.Root.ID
+	}
+
+	mapped := make(map[*CWE]bool)
+	unknown := make(map[string]bool)
+	for _, id := range ids {
+		if strings.TrimSpace(id) == "" {
+			continue
+		}
+		entry, exists := r.Entries[normalizeEntryID(id)]
+		if !exists {
+			unknown[strings.TrimSpace(id)] = true
+			continue
+		}
+		mapped[entry] = true
+	}
+	for id := range unknown {
+		report.Unknown = append(report.Unknown, id)
+	}
+	sort.Slice(report.Unknown, func(i, j int) bool {
+		return compareCWEIDs(report.Unknown[i], report.Unknown[j]) < 0
+	})
+
+	covered := make(map[*CWE]bool)
+	for entry := range mapped {
+		if len(entry.Children) == 0 {
+			covered[entry] = true
+		} else if opts.IncludeDescendants {
+			for _, leaf := range leavesUnder(entry) {
+				covered[leaf] = true
+			}
+		}
+	}
+
+	pillars := roots
+	if r.Root != nil {
+		pillars = append([]*CWE(nil), r.Root.Children...)
+		sortByCWEID(pillars)
+	}
+
+	allLeaves := make(map[*CWE]bool)
+	seenPillars := make(map[*CWE]bool)
+	for _, pillar := range pillars {
+		if seenPillars[pillar] {
+			continue
+		}
+		seenPillars[pillar] = true
+
+		coverage := PillarCoverage{
+			ID:        pillar.ID,
+			Name:      pillar.Name,
+			Covered:   make([]string, 0),
+			Uncovered: make([]string, 0),
+		}
+		leaves := leavesUnder(pillar)
+		sortByCWEID(leaves)
+		for _, leaf := range leaves {
+			allLeaves[leaf] = true
+			if covered[leaf] {
+				coverage.Covered = append(coverage.Covered, leaf.ID)
+			} else {
+				coverage.Uncovered = append(coverage.Uncovered, leaf.ID)
+			}
+		}
+		coverage.TotalLeaves = len(leaves)
+		coverage.CoveredLeaves = len(coverage.Covered)
+		coverage.Percentage = percentage(coverage.CoveredLeaves, coverage.TotalLeaves)
+		report.Pillars = append(report.Pillars, coverage)
+	}
+
+	report.TotalLeaves = len(allLeaves)
+	for leaf := range allLeaves {
+		if covered[leaf] {
+			report.CoveredLeaves++
+		}
+	}
+	report.Percentage = percentage(report.CoveredLeaves, report.TotalLeaves)
+	return report
+}
+
+// CoverageReport 计算规则集映射到的所有CWE对注册表叶子条目的覆盖率，见Registry.CoverageReport
+func (m *RuleMapping) CoverageReport(registry *Registry, opts CoverageOptions) *CoverageReport {
+	ids := make([]string, 0)
+	for id := range m.mappedCWEs() {
+		ids = append(ids, id)
+	}
+	return registry.CoverageReport(ids, opts)
+}
+
+// WriteJSON 将报告以两个空格缩进的JSON写入w
+func (c *CoverageReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(c)
+}
+
+// WriteMarkdown 将报告以Markdown写入w
+//
+// 输出包含总覆盖率、每个顶层分支覆盖率的表格，以及每个分支未覆盖的叶子条目列表，
+// 适合贴到GitHub Issue或扫描器评估文档中。
+func (c *CoverageReport) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("## CWE Coverage\n\n")
+	if c.RootID != "" {
+		fmt.Fprintf(&b, "**View:** %s\n\n", c.RootID)
+	}
+	fmt.Fprintf(&b, "**Overall:** %d/%d leaves covered (%.1f%%)\n", c.CoveredLeaves, c.TotalLeaves, c.Percentage)
+
+	if len(c.Pillars) > 0 {
+		b.WriteString("\n| Pillar | Covered | Total | Coverage |\n|---|---:|---:|---:|\n")
+		for _, pillar := range c.Pillars {
+			fmt.Fprintf(&b, "| %s | %d | %d | %.1f%% |\n",
+				markdownEscape(pillarTitle(pillar)), pillar.CoveredLeaves, pillar.TotalLeaves, pillar.Percentage)
+		}
+	}
+
+	for _, pillar := range c.Pillars {
+		if len(pillar.Uncovered) > 0 {
+			writeMarkdownList(&b, 3, "Uncovered in "+markdownEscape(pillarTitle(pillar)), pillar.Uncovered, 0)
+		}
+	}
+	if len(c.Unknown) > 0 {
+		writeMarkdownList(&b, 3, "Unknown IDs", c.Unknown, 0)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// pillarTitle 返回分支在Markdown中显示的标题，如"CWE-20: Improper Input Validation"
+func pillarTitle(pillar PillarCoverage) string {
+	if pillar.Name == "" {
+		return pillar.ID
+	}
+	return pillar.ID + ": " + pillar.Name
+}
+
+// leavesUnder 返回node子树中所有不重复的叶子条目，node本身是叶子时返回node
+func leavesUnder(node *CWE) []*CWE {
+	leaves := make([]*CWE, 0)
+	visited := make(map[*CWE]bool)
+	stack := []*CWE{node}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		if len(current.Children) == 0 {
+			leaves = append(leaves, current)
+		}
+		stack = append(stack, current.Children...)
+	}
+	return leaves
+}
+
+// percentage 返回part占total的百分比，total为0时返回0
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(total)
+}
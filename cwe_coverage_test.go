@@ -0,0 +1,89 @@
+package cwe
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRegistryCoverageReport(t *testing.T) {
+	registry := newQueryTestRegistry()
+
+	report := registry.CoverageReport([]string{"79", "CWE-79", "CWE-287", "CWE-99999", "abc", ""}, CoverageOptions{})
+	if report.RootID != "CWE-1000" || report.TotalLeaves != 3 || report.CoveredLeaves != 1 {
+		t.Errorf("总覆盖率不正确: %+v", report)
+	}
+	if report.Percentage < 33.3 || report.Percentage > 33.4 {
+		t.Errorf("覆盖率百分比不正确: %v", report.Percentage)
+	}
+	if !reflect.DeepEqual(report.Unknown, []string{"CWE-99999", "abc"}) {
+		t.Errorf("未知ID不正确: %v", report.Unknown)
+	}
+
+	want := []PillarCoverage{
+		{ID: "CWE-20", Name: "Improper Input Validation", TotalLeaves: 2, CoveredLeaves: 1, Percentage: 50,
+			Covered: []string{"CWE-79"}, Uncovered: []string{"CWE-89"}},
+		{ID: "CWE-287", Name: "Improper Authentication", TotalLeaves: 1, CoveredLeaves: 0, Percentage: 0,
+			Covered: []string{}, Uncovered: []string{"CWE-306"}},
+	}
+	if !reflect.DeepEqual(report.Pillars, want) {
+		t.Errorf("分支覆盖率不正确:\n%+v\n期望:\n%+v", report.Pillars, want)
+	}
+
+	// 映射到非叶子条目时覆盖其下所有叶子
+	report = registry.CoverageReport([]string{"CWE-287"}, CoverageOptions{IncludeDescendants: true})
+	if report.CoveredLeaves != 1 || report.Pillars[1].Percentage != 100 {
+		t.Errorf("IncludeDescendants时的覆盖率不正确: %+v", report)
+	}
+}
+
+func TestRuleMappingCoverageReport(t *testing.T) {
+	registry := newQueryTestRegistry()
+	registry.SetHierarchyMode(HierarchyMultiParent)
+	registry.AddChild("CWE-287", "CWE-89")
+
+	mapping := NewRuleMapping()
+	mapping.Map("go/sql-injection", "CWE-89")
+	mapping.Map("go/missing-auth", "CWE-306")
+
+	// 多父节点的叶子计入每个分支，但总数只计算一次
+	report := mapping.CoverageReport(registry, CoverageOptions{})
+	if report.TotalLeaves != 3 || report.CoveredLeaves != 2 {
+		t.Errorf("总覆盖率不正确: %+v", report)
+	}
+	if report.Pillars[1].TotalLeaves != 2 || report.Pillars[1].CoveredLeaves != 2 {
+		t.Errorf("多父节点的分支覆盖率不正确: %+v", report.Pillars[1])
+	}
+}
+
+func TestCoverageReportOutput(t *testing.T) {
+	registry := newQueryTestRegistry()
+	report := registry.CoverageReport([]string{"CWE-79", "CWE-12345"}, CoverageOptions{})
+
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON失败: %v", err)
+	}
+	var decoded CoverageReport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || !reflect.DeepEqual(&decoded, report) {
+		t.Errorf("JSON往返后不一致: %v\n%s", err, buf.String())
+	}
+
+	buf.Reset()
+	if err := report.WriteMarkdown(&buf); err != nil {
+		t.Fatalf("WriteMarkdown失败: %v", err)
+	}
+	markdown := buf.String()
+	for _, want := range []string{
+		"**Overall:** 1/3 leaves covered (33.3%)",
+		"| CWE-20: Improper Input Validation | 1 | 2 | 50.0% |",
+		"### Uncovered in CWE-287: Improper Authentication\n\n- CWE-306\n",
+		"### Unknown IDs\n\n- CWE-12345\n",
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Markdown缺少%q:\n%s", want, markdown)
+		}
+	}
+}
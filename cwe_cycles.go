@@ -0,0 +1,182 @@
+package cwe
+
+import "strings"
+
+// DetectCycles 查找以root为根、沿Children可达的所有环
+//
+// 方法功能:
+// CWE数据本应构成有向无环图，但错误的数据、多次合并或手工编辑都可能引入环，
+// 此时递归遍历(如FindByID)会无限递归。DetectCycles以深度优先方式查找环，
+// 每条回边对应一个环，环以编号最小的节点开始，首尾节点不重复；经过相同节点的相同环只返回一次。
+//
+// 参数:
+// - root: *CWE - 起始节点，为nil时返回空切片
+//
+// 返回值:
+// - [][]*CWE: 发现的环，没有环时返回空切片
+//
+// 使用示例:
+// ```go
+// cycles := cwe.DetectCycles(registry.Root)
+//
+//	for _, cycle := range cycles {
+//	    fmt.Println("发现环:", cycle[0].ID, "共", len(cycle), "个节点")
+//	}
+//
+// ```
+func DetectCycles(root *CWE) [][]*CWE {
+	if root == nil {
+		return [][]*CWE{}
+	}
+	return findCycles([]*CWE{root})
+}
+
+// DetectCycles 查找注册表中所有条目之间沿Children存在的环
+// 从Root和所有条目(按CWE编号顺序)开始查找，因此也能发现Root不可达的环
+func (r *Registry) DetectCycles() [][]*CWE {
+	return findCycles(r.cycleStarts())
+}
+
+// BreakCycles 移除以root为根的树中构成环的父子关系
+//
+// 方法功能:
+// 以深度优先方式遍历，移除每条指向当前路径上祖先节点的子节点引用(回边)，移除后树中不再有环。
+// 被移除关系的子节点如果Parent指向移除它的父节点，Parent会被置为nil。
+// 移除哪条边取决于遍历顺序，应先用DetectCycles检查并尽量修正源数据。
+//
+// 参数:
+// - root: *CWE - 起始节点，为nil时不做任何操作
+//
+// 返回值:
+// - int: 移除的父子关系数
+func BreakCycles(root *CWE) int {
+	if root == nil {
+		return 0
+	}
+	return breakBackEdges([]*CWE{root})
+}
+
+// BreakCycles 移除注册表中构成环的父子关系，起点与Registry.DetectCycles相同
+func (r *Registry) BreakCycles() int {
+	return breakBackEdges(r.cycleStarts())
+}
+
+// cycleStarts 返回在注册表中查找环的起点
+func (r *Registry) cycleStarts() []*CWE {
+	starts := make([]*CWE, 0, len(r.Entries)+1)
+	if r.Root != nil {
+		starts = append(starts, r.Root)
+	}
+	for _, entry := range r.Snapshot() {
+		if entry != nil {
+			starts = append(starts, entry)
+		}
+	}
+	return starts
+}
+
+// findCycles 沿Children查找从starts可达的环，规则见DetectCycles
+func findCycles(starts []*CWE) [][]*CWE {
+	seen := make(map[string]bool)
+	cycles := make([][]*CWE, 0)
+	walkBackEdges(starts, func(path []*CWE, child *CWE) {
+		// 路径中从child到当前节点的部分构成一个环
+		start := len(path) - 1
+		for path[start] != child {
+			start--
+		}
+		cycle := rotateCycle(path[start:])
+		key := cycleKey(cycle)
+		if !seen[key] {
+			seen[key] = true
+			cycles = append(cycles, cycle)
+		}
+	})
+	return cycles
+}
+
+// breakBackEdges 移除从starts可达的所有回边，返回移除数
+func breakBackEdges(starts []*CWE) int {
+	type edge struct{ parent, child *CWE }
+	edges := make([]edge, 0)
+	walkBackEdges(starts, func(path []*CWE, child *CWE) {
+		edges = append(edges, edge{parent: path[len(path)-1], child: child})
+	})
+
+	for _, e := range edges {
+		removeChild(e.parent, e.child)
+		if e.child.Parent == e.parent {
+			e.child.Parent = nil
+		}
+	}
+	return len(edges)
+}
+
+// walkBackEdges 从starts开始深度优先遍历，对每条回边调用onBackEdge
+// path为从起点到回边起点的路径，child为回边指向的祖先节点
+func walkBackEdges(starts []*CWE, onBackEdge func(path []*CWE, child *CWE)) {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+
+	state := make(map[*CWE]int)
+	path := make([]*CWE, 0)
+
+	var visit func(node *CWE)
+	visit = func(node *CWE) {
+		state[node] = visiting
+		path = append(path, node)
+		for _, child := range node.Children {
+			if child == nil {
+				continue
+			}
+			switch state[child] {
+			case unvisited:
+				visit(child)
+			case visiting:
+				onBackEdge(path, child)
+			}
+		}
+		path = path[:len(path)-1]
+		state[node] = done
+	}
+
+	for _, start := range starts {
+		if start != nil && state[start] == unvisited {
+			visit(start)
+		}
+	}
+}
+
+// rotateCycle 复制环并旋转到以编号最小的节点开始
+func rotateCycle(nodes []*CWE) []*CWE {
+	first := 0
+	for i, node := range nodes {
+		if compareCWEIDs(node.ID, nodes[first].ID) < 0 {
+			first = i
+		}
+	}
+	cycle := make([]*CWE, 0, len(nodes))
+	cycle = append(cycle, nodes[first:]...)
+	return append(cycle, nodes[:first]...)
+}
+
+// cycleKey 返回环的去重键
+func cycleKey(cycle []*CWE) string {
+	ids := make([]string, 0, len(cycle))
+	for _, node := range cycle {
+		ids = append(ids, node.ID)
+	}
+	return strings.Join(ids, ">")
+}
+
+// formatCycle 将环格式化为"CWE-1 → CWE-2 → CWE-1"
+func formatCycle(cycle []*CWE) string {
+	if len(cycle) == 0 {
+		return ""
+	}
+	closed := append(append(make([]*CWE, 0, len(cycle)+1), cycle...), cycle[0])
+	return FormatPath(closed, PathFormatOptions{})
+}
@@ -0,0 +1,77 @@
+package cwe
+
+import (
+	"testing"
+)
+
+func cycleIDs(cycles [][]*CWE) []string {
+	result := make([]string, 0, len(cycles))
+	for _, cycle := range cycles {
+		result = append(result, cycleKey(cycle))
+	}
+	return result
+}
+
+func TestDetectCycles(t *testing.T) {
+	registry := newQueryTestRegistry()
+	if cycles := DetectCycles(registry.Root); len(cycles) != 0 {
+		t.Fatalf("无环的树不应报告环: %v", cycleIDs(cycles))
+	}
+
+	// CWE-79 → CWE-1000 和 CWE-306 → CWE-287
+	registry.Entries["CWE-79"].Children = append(registry.Entries["CWE-79"].Children, registry.Root)
+	registry.Entries["CWE-306"].Children = append(registry.Entries["CWE-306"].Children, registry.Entries["CWE-287"])
+
+	got := cycleIDs(DetectCycles(registry.Root))
+	if len(got) != 2 || got[0] != "CWE-20>CWE-79>CWE-1000" || got[1] != "CWE-287>CWE-306" {
+		t.Errorf("环不正确: %v", got)
+	}
+	if formatCycle(DetectCycles(registry.Entries["CWE-287"])[0]) != "CWE-287 → CWE-306 → CWE-287" {
+		t.Error("环格式化不正确")
+	}
+	if len(DetectCycles(nil)) != 0 {
+		t.Error("nil根节点不应报告环")
+	}
+}
+
+func TestRegistryDetectCyclesUnreachable(t *testing.T) {
+	registry := newQueryTestRegistry()
+	a := NewCWE("CWE-1", "A")
+	b := NewCWE("CWE-2", "B")
+	a.AddChild(b)
+	b.AddChild(a)
+	registry.Register(a)
+	registry.Register(b)
+
+	if got := cycleIDs(registry.DetectCycles()); len(got) != 1 || got[0] != "CWE-1>CWE-2" {
+		t.Errorf("应发现Root不可达的环: %v", got)
+	}
+}
+
+func TestBreakCycles(t *testing.T) {
+	registry := newQueryTestRegistry()
+	xss := registry.Entries["CWE-79"]
+	xss.Children = append(xss.Children, registry.Root, registry.Entries["CWE-20"])
+	registry.Entries["CWE-306"].AddChild(registry.Entries["CWE-287"])
+
+	removed := registry.BreakCycles()
+	if removed != 3 {
+		t.Errorf("期望移除3条关系，实际 %d", removed)
+	}
+	if cycles := registry.DetectCycles(); len(cycles) != 0 {
+		t.Errorf("移除后不应再有环: %v", cycleIDs(cycles))
+	}
+	if len(xss.Children) != 0 {
+		t.Errorf("回边应被移除: %v", xss.Children)
+	}
+	// CWE-287的Parent被AddChild改为CWE-306，移除回边后应置为nil
+	if registry.Entries["CWE-287"].Parent != nil {
+		t.Error("被移除关系的Parent应被置为nil")
+	}
+	if len(registry.Root.Children) != 2 || registry.Entries["CWE-20"].Parent != registry.Root {
+		t.Error("不属于环的关系不应被修改")
+	}
+	if BreakCycles(nil) != 0 {
+		t.Error("nil根节点不应移除任何关系")
+	}
+}
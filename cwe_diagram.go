@@ -0,0 +1,393 @@
+package cwe
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// DefaultSeverityColors 是导出图表时严重性级别对应的默认填充色
+// 没有严重性或不在表中的级别使用DefaultNodeColor
+var DefaultSeverityColors = map[string]string{
+	"Critical": "#d73027",
+	"High":     "#fc8d59",
+	"Medium":   "#fee08b",
+	"Low":      "#d9ef8b",
+}
+
+// DefaultNodeColor 是没有匹配严重性颜色的节点的填充色
+const DefaultNodeColor = "#ffffff"
+
+// DiagramOptions 控制DOT和Mermaid导出的选项
+type DiagramOptions struct {
+	// RootID 只导出以该条目为根的子树，为空时从注册表的Root开始；
+	// 注册表没有Root时导出所有条目。对TreeNode和Graph无效
+	RootID string
+
+	// MaxDepth 导出的最大深度，根节点深度为0，<=0表示不限制。对Graph无效
+	MaxDepth int
+
+	// Direction 布局方向，如"TB"(从上到下)、"LR"(从左到右)，为空时使用"TB"
+	Direction string
+
+	// SeverityColors 严重性级别到填充色的映射，为nil时使用DefaultSeverityColors
+	SeverityColors map[string]string
+
+	// HideNames 为true时节点只显示ID，适合节点很多的大图
+	HideNames bool
+
+	// Locale 节点名称使用的语言，如"zh-CN"，没有该语言的翻译时使用原文，见CWE.LocalizedName
+	Locale string
+}
+
+// diagram 是导出前与格式无关的图表示
+type diagram struct {
+	nodes []*CWE
+	edges []GraphEdge
+	seen  map[string]bool
+	links map[[2]string]bool
+
+	// placeholders 只作为边的端点出现、不在图中的条目ID
+	placeholders map[string]bool
+}
+
+func newDiagram() *diagram {
+	return &diagram{
+		seen:         make(map[string]bool),
+		links:        make(map[[2]string]bool),
+		placeholders: make(map[string]bool),
+	}
+}
+
+// addNode 添加节点，返回false表示节点已存在
+func (d *diagram) addNode(entry *CWE) bool {
+	if d.seen[entry.ID] {
+		return false
+	}
+	d.seen[entry.ID] = true
+	d.nodes = append(d.nodes, entry)
+	return true
+}
+
+// addLink 添加父子边，重复的边只保留一条
+func (d *diagram) addLink(parent, child string) {
+	key := [2]string{parent, child}
+	if d.links[key] {
+		return
+	}
+	d.links[key] = true
+	d.edges = append(d.edges, GraphEdge{From: parent, To: child})
+}
+
+// ExportToDOT 将注册表的层次结构导出为Graphviz DOT格式
+//
+// 方法功能:
+// 每个条目是一个节点，标签为"ID\n名称"，按生效的严重性(见EffectiveSeverity)填充颜色；
+// 每条父子关系是一条从父节点指向子节点的边。条目按广度优先顺序输出，子节点按CWE编号排序，
+// 因此同一个注册表的输出是确定的，适合纳入版本控制。
+//
+// 参数:
+// - w: io.Writer - 输出目标
+// - opts: DiagramOptions - 导出选项
+//
+// 返回值:
+// - error: RootID不存在或写入失败时返回错误
+//
+// 使用示例:
+// ```go
+// file, _ := os.Create("input-validation.dot")
+// defer file.Close()
+//
+// // 只导出CWE-20及其下三层
+// err := registry.ExportToDOT(file, cwe.DiagramOptions{RootID: "CWE-20", MaxDepth: 3, Direction: "LR"})
+//
+// // 之后执行: dot -Tsvg input-validation.dot -o input-validation.svg
+// ```
+func (r *Registry) ExportToDOT(w io.Writer, opts DiagramOptions) error {
+	d, err := r.diagram(opts)
+	if err != nil {
+		return err
+	}
+	return writeDOT(w, d, opts, r.diagramSeverity)
+}
+
+// ExportToMermaid 将注册表的层次结构导出为Mermaid流程图
+//
+// 方法功能:
+// 与ExportToDOT输出相同的节点和边，生成的文本可以直接嵌入Markdown的```mermaid代码块中，
+// 在GitHub、GitLab等平台上渲染。Mermaid的节点ID不能包含"-"，因此"CWE-79"的节点ID为"CWE_79"，
+// 标签中仍显示原始ID。
+//
+// 参数:
+// - w: io.Writer - 输出目标
+// - opts: DiagramOptions - 导出选项
+//
+// 返回值:
+// - error: RootID不存在或写入失败时返回错误
+func (r *Registry) ExportToMermaid(w io.Writer, opts DiagramOptions) error {
+	d, err := r.diagram(opts)
+	if err != nil {
+		return err
+	}
+	return writeMermaid(w, d, opts, r.diagramSeverity)
+}
+
+// diagramSeverity 返回条目生效的严重性，覆盖层优先
+func (r *Registry) diagramSeverity(entry *CWE) string {
+	if r.severityOverlay != nil {
+		if severity, exists := r.severityOverlay.Get(entry.ID); exists {
+			return severity
+		}
+	}
+	return entry.Severity
+}
+
+// diagram 按选项收集注册表中要导出的节点和边
+func (r *Registry) diagram(opts DiagramOptions) (*diagram, error) {
+	var roots []*CWE
+	switch {
+	case opts.RootID != "":
+		root, err := r.GetByID(normalizeEntryID(opts.RootID))
+		if err != nil {
+			return nil, err
+		}
+		roots = []*CWE{root}
+	case r.Root != nil:
+		roots = []*CWE{r.Root}
+	default:
+		// 没有根节点时，从所有没有父节点的条目开始，再补上剩余的条目(如环中的条目)
+		for _, entry := range r.Snapshot() {
+			if entry.Parent == nil {
+				roots = append(roots, entry)
+			}
+		}
+	}
+
+	d := newDiagram()
+	type item struct {
+		entry *CWE
+		depth int
+	}
+	queue := make([]item, 0, len(roots))
+	for _, root := range roots {
+		if d.addNode(root) {
+			queue = append(queue, item{root, 0})
+		}
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if opts.MaxDepth > 0 && current.depth >= opts.MaxDepth {
+			continue
+		}
+
+		children := append([]*CWE(nil), current.entry.Children...)
+		sortByCWEID(children)
+		for _, child := range children {
+			d.addLink(current.entry.ID, child.ID)
+			if d.addNode(child) {
+				queue = append(queue, item{child, current.depth + 1})
+			}
+		}
+	}
+
+	if opts.RootID == "" && r.Root == nil {
+		for _, entry := range r.Snapshot() {
+			d.addNode(entry)
+		}
+	}
+	return d, nil
+}
+
+// ExportToDOT 将以当前节点为根的树导出为Graphviz DOT格式
+// 同一个CWE在树中出现多次时只输出一个节点，选项和输出格式与Registry.ExportToDOT相同(RootID除外)
+func (n *TreeNode) ExportToDOT(w io.Writer, opts DiagramOptions) error {
+	return writeDOT(w, n.diagram(opts), opts, upstreamSeverity)
+}
+
+// ExportToMermaid 将以当前节点为根的树导出为Mermaid流程图
+// 选项和输出格式与Registry.ExportToMermaid相同(RootID除外)
+func (n *TreeNode) ExportToMermaid(w io.Writer, opts DiagramOptions) error {
+	return writeMermaid(w, n.diagram(opts), opts, upstreamSeverity)
+}
+
+// upstreamSeverity 返回条目自身的严重性，用于没有覆盖层的TreeNode和Graph
+func upstreamSeverity(entry *CWE) string {
+	return entry.Severity
+}
+
+// diagram 按广度优先顺序收集树中的节点和边
+func (n *TreeNode) diagram(opts DiagramOptions) *diagram {
+	d := newDiagram()
+	type item struct {
+		node  *TreeNode
+		depth int
+	}
+	d.addNode(n.CWE)
+	queue := []item{{n, 0}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if opts.MaxDepth > 0 && current.depth >= opts.MaxDepth {
+			continue
+		}
+
+		children := append([]*TreeNode(nil), current.node.Children...)
+		sort.SliceStable(children, func(i, j int) bool {
+			return compareCWEIDs(children[i].CWE.ID, children[j].CWE.ID) < 0
+		})
+		for _, child := range children {
+			d.addLink(current.node.CWE.ID, child.CWE.ID)
+			if d.addNode(child.CWE) {
+				queue = append(queue, item{child, current.depth + 1})
+			}
+		}
+	}
+	return d
+}
+
+// ExportToDOT 将图导出为Graphviz DOT格式，边的标签为关系性质
+// 被引用但不在图中的条目以虚线框的节点输出。选项中只有Direction、SeverityColors和HideNames有效
+func (g *Graph) ExportToDOT(w io.Writer, opts DiagramOptions) error {
+	return writeDOT(w, g.diagram(), opts, upstreamSeverity)
+}
+
+// ExportToMermaid 将图导出为Mermaid流程图，边的标签为关系性质
+// 选项中只有Direction、SeverityColors和HideNames有效
+func (g *Graph) ExportToMermaid(w io.Writer, opts DiagramOptions) error {
+	return writeMermaid(w, g.diagram(), opts, upstreamSeverity)
+}
+
+// diagram 收集图中的所有节点和边，边的端点不在图中时创建只有ID的占位节点
+func (g *Graph) diagram() *diagram {
+	d := newDiagram()
+	for _, entry := range g.Nodes() {
+		d.addNode(entry)
+	}
+	d.edges = g.Edges()
+	for _, edge := range d.edges {
+		for _, id := range []string{edge.From, edge.To} {
+			if d.addNode(&CWE{ID: id}) {
+				d.placeholders[id] = true
+			}
+		}
+	}
+	return d
+}
+
+// writeDOT 以DOT格式输出图
+func writeDOT(w io.Writer, d *diagram, opts DiagramOptions, severity func(*CWE) string) error {
+	bw := bufio.NewWriter(w)
+	colors := opts.SeverityColors
+	if colors == nil {
+		colors = DefaultSeverityColors
+	}
+
+	fmt.Fprintln(bw, "digraph cwe {")
+	fmt.Fprintf(bw, "  rankdir=%s;\n", diagramDirection(opts))
+	fmt.Fprintln(bw, `  node [shape=box, style="rounded,filled", fontname="Helvetica"];`)
+	for _, entry := range d.nodes {
+		if d.placeholders[entry.ID] {
+			fmt.Fprintf(bw, "  %s [style=\"rounded,dashed\"];\n", dotQuote(entry.ID))
+			continue
+		}
+		label := entry.ID
+		if name := entry.LocalizedName(opts.Locale); !opts.HideNames && name != "" {
+			label += "\n" + name
+		}
+		fmt.Fprintf(bw, "  %s [label=%s, fillcolor=%s];\n",
+			dotQuote(entry.ID), dotQuote(label), dotQuote(severityColor(colors, severity(entry))))
+	}
+	for _, edge := range d.edges {
+		if edge.Nature == "" {
+			fmt.Fprintf(bw, "  %s -> %s;\n", dotQuote(edge.From), dotQuote(edge.To))
+		} else {
+			fmt.Fprintf(bw, "  %s -> %s [label=%s];\n", dotQuote(edge.From), dotQuote(edge.To), dotQuote(edge.Nature))
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// writeMermaid 以Mermaid流程图格式输出图
+func writeMermaid(w io.Writer, d *diagram, opts DiagramOptions, severity func(*CWE) string) error {
+	bw := bufio.NewWriter(w)
+	colors := opts.SeverityColors
+	if colors == nil {
+		colors = DefaultSeverityColors
+	}
+
+	fmt.Fprintf(bw, "flowchart %s\n", diagramDirection(opts))
+	classes := make(map[string][]string)
+	for _, entry := range d.nodes {
+		id := mermaidID(entry.ID)
+		label := mermaidEscape(entry.ID)
+		if name := entry.LocalizedName(opts.Locale); !opts.HideNames && name != "" {
+			label += "<br/>" + mermaidEscape(name)
+		}
+		fmt.Fprintf(bw, "  %s[\"%s\"]\n", id, label)
+		if color, exists := colors[severity(entry)]; exists {
+			classes[color] = append(classes[color], id)
+		}
+	}
+	for _, edge := range d.edges {
+		if edge.Nature == "" {
+			fmt.Fprintf(bw, "  %s --> %s\n", mermaidID(edge.From), mermaidID(edge.To))
+		} else {
+			fmt.Fprintf(bw, "  %s -->|%s| %s\n", mermaidID(edge.From), edge.Nature, mermaidID(edge.To))
+		}
+	}
+
+	// 每种颜色定义一个样式类，按颜色排序保证输出确定
+	palette := make([]string, 0, len(classes))
+	for color := range classes {
+		palette = append(palette, color)
+	}
+	sort.Strings(palette)
+	for i, color := range palette {
+		fmt.Fprintf(bw, "  classDef severity%d fill:%s\n", i, color)
+		fmt.Fprintf(bw, "  class %s severity%d\n", strings.Join(classes[color], ","), i)
+	}
+	return bw.Flush()
+}
+
+// diagramDirection 返回布局方向，默认为"TB"
+func diagramDirection(opts DiagramOptions) string {
+	if opts.Direction == "" {
+		return "TB"
+	}
+	return strings.ToUpper(opts.Direction)
+}
+
+// severityColor 返回严重性对应的颜色，找不到时返回DefaultNodeColor
+func severityColor(colors map[string]string, severity string) string {
+	if color, exists := colors[severity]; exists {
+		return color
+	}
+	return DefaultNodeColor
+}
+
+// dotQuote 将字符串转换为DOT的带引号字符串，换行符转换为DOT的"\n"
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// mermaidID 将条目ID转换为合法的Mermaid节点ID，如"CWE-79" -> "CWE_79"
+func mermaidID(id string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, id)
+}
+
+// mermaidEscape 将Mermaid标签中的引号和尖括号转换为实体编码，换行符转换为空格
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;", "\n", " ").Replace(s)
+}
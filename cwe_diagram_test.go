@@ -0,0 +1,150 @@
+package cwe
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestRegistryExportToDOT 测试注册表导出为DOT格式
+func TestRegistryExportToDOT(t *testing.T) {
+	registry := newExportTestRegistry()
+	overlay := NewSeverityOverlay()
+	overlay.Set("CWE-89", "Critical")
+	registry.SetSeverityOverlay(overlay)
+
+	var buf bytes.Buffer
+	if err := registry.ExportToDOT(&buf, DiagramOptions{Direction: "lr"}); err != nil {
+		t.Fatalf("ExportToDOT失败: %v", err)
+	}
+	expected := `digraph cwe {
+  rankdir=LR;
+  node [shape=box, style="rounded,filled", fontname="Helvetica"];
+  "CWE-1000" [label="CWE-1000\nResearch Concepts", fillcolor="#ffffff"];
+  "CWE-20" [label="CWE-20\nImproper Input Validation", fillcolor="#ffffff"];
+  "CWE-79" [label="CWE-79\nCross-site Scripting", fillcolor="#fc8d59"];
+  "CWE-89" [label="CWE-89\nSQL Injection <script>", fillcolor="#d73027"];
+  "CWE-1000" -> "CWE-20";
+  "CWE-20" -> "CWE-79";
+  "CWE-20" -> "CWE-89";
+}
+`
+	if buf.String() != expected {
+		t.Errorf("DOT输出不正确:\n%s", buf.String())
+	}
+}
+
+// TestRegistryExportDiagramOptions 测试子树、深度和隐藏名称选项
+func TestRegistryExportDiagramOptions(t *testing.T) {
+	registry := newExportTestRegistry()
+
+	var buf bytes.Buffer
+	err := registry.ExportToDOT(&buf, DiagramOptions{RootID: "20", MaxDepth: 1, HideNames: true})
+	if err != nil {
+		t.Fatalf("ExportToDOT失败: %v", err)
+	}
+	output := buf.String()
+	if strings.Contains(output, "CWE-1000") || !strings.Contains(output, `"CWE-79" [label="CWE-79", `) {
+		t.Errorf("应只导出CWE-20的子树且不显示名称:\n%s", output)
+	}
+
+	buf.Reset()
+	registry.ExportToDOT(&buf, DiagramOptions{MaxDepth: 1})
+	if strings.Contains(buf.String(), "CWE-79") {
+		t.Errorf("MaxDepth为1时不应包含第二层节点:\n%s", buf.String())
+	}
+
+	if err := registry.ExportToDOT(&buf, DiagramOptions{RootID: "CWE-99999"}); err == nil {
+		t.Error("RootID不存在时应返回错误")
+	}
+
+	// 没有根节点时导出所有条目
+	registry.Root = nil
+	registry.Register(NewCWE("CWE-125", "Out-of-bounds Read"))
+	buf.Reset()
+	registry.ExportToDOT(&buf, DiagramOptions{})
+	if !strings.Contains(buf.String(), `"CWE-125" [`) || !strings.Contains(buf.String(), `"CWE-20" -> "CWE-89";`) {
+		t.Errorf("没有根节点时应导出所有条目:\n%s", buf.String())
+	}
+}
+
+// TestRegistryExportToMermaid 测试注册表导出为Mermaid流程图
+func TestRegistryExportToMermaid(t *testing.T) {
+	registry := newExportTestRegistry()
+	registry.Entries["CWE-20"].Name = `Improper "Input" Validation`
+
+	var buf bytes.Buffer
+	if err := registry.ExportToMermaid(&buf, DiagramOptions{}); err != nil {
+		t.Fatalf("ExportToMermaid失败: %v", err)
+	}
+	expected := `flowchart TB
+  CWE_1000["CWE-1000<br/>Research Concepts"]
+  CWE_20["CWE-20<br/>Improper #quot;Input#quot; Validation"]
+  CWE_79["CWE-79<br/>Cross-site Scripting"]
+  CWE_89["CWE-89<br/>SQL Injection #lt;script#gt;"]
+  CWE_1000 --> CWE_20
+  CWE_20 --> CWE_79
+  CWE_20 --> CWE_89
+  classDef severity0 fill:#fc8d59
+  class CWE_79 severity0
+`
+	if buf.String() != expected {
+		t.Errorf("Mermaid输出不正确:\n%s", buf.String())
+	}
+}
+
+// TestTreeNodeExportToDOT 测试TreeNode导出时重复出现的CWE只输出一次
+func TestTreeNodeExportToDOT(t *testing.T) {
+	shared := NewCWE("CWE-79", "Cross-site Scripting")
+	root := NewTreeNode(NewCWE("CWE-1000", "Research Concepts"))
+	a := NewTreeNode(NewCWE("CWE-74", "Injection"))
+	b := NewTreeNode(NewCWE("CWE-20", "Improper Input Validation"))
+	a.AddChild(NewTreeNode(shared))
+	b.AddChild(NewTreeNode(shared))
+	root.AddChild(a)
+	root.AddChild(b)
+
+	var buf bytes.Buffer
+	if err := root.ExportToDOT(&buf, DiagramOptions{}); err != nil {
+		t.Fatalf("ExportToDOT失败: %v", err)
+	}
+	output := buf.String()
+	if strings.Count(output, `"CWE-79" [`) != 1 {
+		t.Errorf("CWE-79应只输出一个节点:\n%s", output)
+	}
+	if !strings.Contains(output, `"CWE-20" -> "CWE-79";`) || !strings.Contains(output, `"CWE-74" -> "CWE-79";`) {
+		t.Errorf("应包含两个父节点到CWE-79的边:\n%s", output)
+	}
+	if strings.Index(output, `"CWE-20" [`) > strings.Index(output, `"CWE-74" [`) {
+		t.Errorf("子节点应按CWE编号排序:\n%s", output)
+	}
+
+	buf.Reset()
+	root.ExportToMermaid(&buf, DiagramOptions{MaxDepth: 1})
+	if strings.Contains(buf.String(), "CWE_79") {
+		t.Errorf("MaxDepth为1时不应包含第二层节点:\n%s", buf.String())
+	}
+}
+
+// TestGraphExportDiagram 测试图导出时边带有关系标签
+func TestGraphExportDiagram(t *testing.T) {
+	graph := NewGraphFromRegistry(newGraphTestRegistry())
+
+	var buf bytes.Buffer
+	if err := graph.ExportToDOT(&buf, DiagramOptions{}); err != nil {
+		t.Fatalf("ExportToDOT失败: %v", err)
+	}
+	output := buf.String()
+	if !strings.Contains(output, `"CWE-120" -> "CWE-787" [label="CanPrecede"];`) {
+		t.Errorf("边应带有关系标签:\n%s", output)
+	}
+	if !strings.Contains(output, `"CWE-20" [style="rounded,dashed"];`) {
+		t.Errorf("不在图中的端点应输出为虚线节点:\n%s", output)
+	}
+
+	buf.Reset()
+	graph.ExportToMermaid(&buf, DiagramOptions{})
+	if !strings.Contains(buf.String(), "CWE_787 -->|PeerOf| CWE_125") {
+		t.Errorf("Mermaid边应带有关系标签:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,293 @@
+package cwe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultEmbeddingBatchSize 是EmbedRegistry每批提交给嵌入函数的文本数量
+const DefaultEmbeddingBatchSize = 64
+
+// EmbeddingDocument 是嵌入导出中的一行
+// Text由名称、描述和缓解措施拼接而成，可直接交给嵌入模型；Vector仅在提供了向量存储时填充
+type EmbeddingDocument struct {
+	ID     string    `json:"id"`
+	Name   string    `json:"name"`
+	Text   string    `json:"text"`
+	Vector []float32 `json:"vector,omitempty"`
+}
+
+// EmbeddingText 生成用于嵌入的文本
+//
+// 方法功能:
+// 将CWE的名称、描述和缓解措施按行拼接，空字段会被跳过。
+// 同一条目在不同运行中总是生成相同的文本，便于缓存嵌入结果。
+//
+// 参数:
+// - cwe: *CWE - CWE条目
+//
+// 返回值:
+// - string: 用于嵌入的文本
+func EmbeddingText(cwe *CWE) string {
+	parts := make([]string, 0, 2+len(cwe.Mitigations))
+	if cwe.Name != "" {
+		parts = append(parts, cwe.ID+": "+cwe.Name)
+	}
+	if cwe.Description != "" {
+		parts = append(parts, cwe.Description)
+	}
+	for _, mitigation := range cwe.Mitigations {
+		if mitigation != "" {
+			parts = append(parts, "Mitigation: "+mitigation)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// ExportEmbeddingJSONL 以JSON Lines格式导出每个条目的嵌入文本
+//
+// 方法功能:
+// 按ID排序，每个条目输出一行EmbeddingDocument，可直接用于嵌入流水线的批量输入。
+// 提供向量存储时，已有向量的条目会附带Vector字段。
+//
+// 参数:
+// - w: io.Writer - 输出目标
+// - store: *EmbeddingStore - 可选的向量存储，为nil时不输出向量
+//
+// 返回值:
+// - error: 写入失败时返回错误
+//
+// 使用示例:
+// ```go
+// file, _ := os.Create("cwe-embeddings.jsonl")
+// defer file.Close()
+// registry.ExportEmbeddingJSONL(file, nil)
+// ```
+func (r *Registry) ExportEmbeddingJSONL(w io.Writer, store *EmbeddingStore) error {
+	encoder := json.NewEncoder(w)
+	for _, id := range r.sortedIDs() {
+		entry := r.Entries[id]
+		doc := EmbeddingDocument{
+			ID:   entry.ID,
+			Name: entry.Name,
+			Text: EmbeddingText(entry),
+		}
+		if store != nil {
+			doc.Vector, _ = store.Get(entry.ID)
+		}
+		if err := encoder.Encode(doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortedIDs 返回注册表中所有条目的ID，已排序
+func (r *Registry) sortedIDs() []string {
+	ids := make([]string, 0, len(r.Entries))
+	for id := range r.Entries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Embedder 将文本转换为向量
+// 实现通常调用外部嵌入服务或本地模型，返回的向量数量和顺序必须与输入文本一致
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// EmbedderFunc 将普通函数适配为Embedder
+type EmbedderFunc func(ctx context.Context, texts []string) ([][]float32, error)
+
+// Embed 实现Embedder接口
+func (f EmbedderFunc) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return f(ctx, texts)
+}
+
+// SimilarityResult 表示一次相似度查询的结果
+type SimilarityResult struct {
+	ID    string
+	Score float64
+}
+
+// EmbeddingStore 保存CWE条目的嵌入向量，并发安全
+// 所有向量的维度必须相同，维度由第一个写入的向量决定
+type EmbeddingStore struct {
+	mutex   sync.RWMutex
+	vectors map[string][]float32
+	dims    int
+}
+
+// NewEmbeddingStore 创建空的向量存储
+func NewEmbeddingStore() *EmbeddingStore {
+	return &EmbeddingStore{
+		vectors: make(map[string][]float32),
+	}
+}
+
+// Set 保存条目的向量
+// 向量为空或维度与已有向量不一致时返回错误
+func (s *EmbeddingStore) Set(id string, vector []float32) error {
+	if len(vector) == 0 {
+		return fmt.Errorf("%s的向量为空", id)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.dims != 0 && len(vector) != s.dims {
+		return fmt.Errorf("%s的向量维度为%d，期望%d", id, len(vector), s.dims)
+	}
+	s.dims = len(vector)
+	s.vectors[id] = append([]float32(nil), vector...)
+	return nil
+}
+
+// Get 获取条目的向量，第二个返回值表示是否存在
+func (s *EmbeddingStore) Get(id string) ([]float32, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	vector, exists := s.vectors[id]
+	return vector, exists
+}
+
+// Len 返回已保存的向量数量
+func (s *EmbeddingStore) Len() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return len(s.vectors)
+}
+
+// Dimensions 返回向量维度，存储为空时返回0
+func (s *EmbeddingStore) Dimensions() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.dims
+}
+
+// Similar 按余弦相似度返回与给定向量最相近的k个条目
+// 结果按相似度从高到低排序，相似度相同时按ID排序
+func (s *EmbeddingStore) Similar(vector []float32, k int) []SimilarityResult {
+	s.mutex.RLock()
+	results := make([]SimilarityResult, 0, len(s.vectors))
+	for id, candidate := range s.vectors {
+		if len(candidate) != len(vector) {
+			continue
+		}
+		results = append(results, SimilarityResult{ID: id, Score: cosineSimilarity(vector, candidate)})
+	}
+	s.mutex.RUnlock()
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+
+	if k >= 0 && k < len(results) {
+		results = results[:k]
+	}
+	return results
+}
+
+// cosineSimilarity 计算两个等长向量的余弦相似度，任一向量为零向量时返回0
+func cosineSimilarity(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// EmbedRegistry 为注册表中的所有条目生成嵌入向量
+//
+// 方法功能:
+// 按ID顺序将条目的EmbeddingText分批交给embedder，并把结果写入向量存储。
+// store中已有向量的条目会被跳过，因此可以在中断后用同一个store继续。
+//
+// 参数:
+// - ctx: context.Context - 控制取消，会传递给embedder
+// - registry: *Registry - 要处理的注册表
+// - embedder: Embedder - 嵌入函数
+// - store: *EmbeddingStore - 向量存储，为nil时新建
+// - batchSize: int - 每批的文本数量，<=0时使用DefaultEmbeddingBatchSize
+//
+// 返回值:
+// - *EmbeddingStore: 写入了向量的存储
+// - error: embedder返回错误、返回的向量数量不符或ctx被取消时返回错误，已完成批次的向量仍保留在store中
+//
+// 使用示例:
+// ```go
+//
+//	embedder := cwe.EmbedderFunc(func(ctx context.Context, texts []string) ([][]float32, error) {
+//	    return myModel.Embed(ctx, texts)
+//	})
+//
+// store, err := cwe.EmbedRegistry(ctx, registry, embedder, nil, 32)
+// similar := store.Similar(queryVector, 5)
+// ```
+func EmbedRegistry(ctx context.Context, registry *Registry, embedder Embedder, store *EmbeddingStore, batchSize int) (*EmbeddingStore, error) {
+	if store == nil {
+		store = NewEmbeddingStore()
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultEmbeddingBatchSize
+	}
+
+	pending := make([]string, 0, len(registry.Entries))
+	for _, id := range registry.sortedIDs() {
+		if _, exists := store.Get(id); !exists {
+			pending = append(pending, id)
+		}
+	}
+
+	for start := 0; start < len(pending); start += batchSize {
+		if err := ctx.Err(); err != nil {
+			return store, err
+		}
+
+		end := start + batchSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+		batch := pending[start:end]
+
+		texts := make([]string, len(batch))
+		for i, id := range batch {
+			texts[i] = EmbeddingText(registry.Entries[id])
+		}
+
+		vectors, err := embedder.Embed(ctx, texts)
+		if err != nil {
+			return store, fmt.Errorf("生成嵌入向量失败: %w", err)
+		}
+		if len(vectors) != len(batch) {
+			return store, fmt.Errorf("嵌入函数返回了%d个向量，期望%d个", len(vectors), len(batch))
+		}
+
+		for i, id := range batch {
+			if err := store.Set(id, vectors[i]); err != nil {
+				return store, err
+			}
+		}
+	}
+
+	return store, nil
+}
@@ -0,0 +1,145 @@
+package cwe
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newEmbeddingTestRegistry() *Registry {
+	registry := NewRegistry()
+
+	xss := NewCWE("CWE-79", "Cross-site Scripting")
+	xss.Description = "Improper neutralization of input during web page generation."
+	xss.Mitigations = []string{"Encode output"}
+	registry.Register(xss)
+
+	sqli := NewCWE("CWE-89", "SQL Injection")
+	registry.Register(sqli)
+
+	return registry
+}
+
+// lengthEmbedder 用文本长度和换行数构造二维向量，便于断言
+var lengthEmbedder = EmbedderFunc(func(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float32{float32(len(text)), float32(strings.Count(text, "\n") + 1)}
+	}
+	return vectors, nil
+})
+
+func TestEmbeddingText(t *testing.T) {
+	text := EmbeddingText(newEmbeddingTestRegistry().Entries["CWE-79"])
+	expected := "CWE-79: Cross-site Scripting\nImproper neutralization of input during web page generation.\nMitigation: Encode output"
+	if text != expected {
+		t.Errorf("EmbeddingText = %q, 期望 %q", text, expected)
+	}
+}
+
+func TestExportEmbeddingJSONL(t *testing.T) {
+	registry := newEmbeddingTestRegistry()
+
+	var buf bytes.Buffer
+	if err := registry.ExportEmbeddingJSONL(&buf, nil); err != nil {
+		t.Fatalf("导出失败: %v", err)
+	}
+
+	var docs []EmbeddingDocument
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var doc EmbeddingDocument
+		if err := json.Unmarshal(scanner.Bytes(), &doc); err != nil {
+			t.Fatalf("无效的JSON行: %v", err)
+		}
+		docs = append(docs, doc)
+	}
+
+	if len(docs) != 2 || docs[0].ID != "CWE-79" || docs[1].ID != "CWE-89" {
+		t.Fatalf("导出的文档不正确: %+v", docs)
+	}
+	if docs[0].Vector != nil {
+		t.Error("未提供向量存储时不应输出向量")
+	}
+	if strings.Contains(buf.String(), `"vector"`) {
+		t.Error("空向量不应出现在输出中")
+	}
+}
+
+func TestEmbedRegistry(t *testing.T) {
+	registry := newEmbeddingTestRegistry()
+
+	calls := 0
+	embedder := EmbedderFunc(func(ctx context.Context, texts []string) ([][]float32, error) {
+		calls++
+		return lengthEmbedder(ctx, texts)
+	})
+
+	store, err := EmbedRegistry(context.Background(), registry, embedder, nil, 1)
+	if err != nil {
+		t.Fatalf("EmbedRegistry失败: %v", err)
+	}
+	if store.Len() != 2 || store.Dimensions() != 2 || calls != 2 {
+		t.Errorf("向量存储不正确: len=%d dims=%d calls=%d", store.Len(), store.Dimensions(), calls)
+	}
+
+	// 已有向量的条目应被跳过
+	if _, err := EmbedRegistry(context.Background(), registry, embedder, store, 0); err != nil {
+		t.Fatalf("EmbedRegistry失败: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("已有向量时不应再次调用嵌入函数，调用了 %d 次", calls)
+	}
+
+	var buf bytes.Buffer
+	registry.ExportEmbeddingJSONL(&buf, store)
+	if !strings.Contains(buf.String(), `"vector":[`) {
+		t.Error("提供向量存储时应输出向量")
+	}
+
+	results := store.Similar([]float32{100, 3}, 1)
+	if len(results) != 1 || results[0].ID != "CWE-79" {
+		t.Errorf("相似度查询结果不正确: %+v", results)
+	}
+}
+
+func TestEmbedRegistryErrors(t *testing.T) {
+	registry := newEmbeddingTestRegistry()
+
+	failing := EmbedderFunc(func(ctx context.Context, texts []string) ([][]float32, error) {
+		return nil, errors.New("service unavailable")
+	})
+	if _, err := EmbedRegistry(context.Background(), registry, failing, nil, 0); err == nil {
+		t.Error("嵌入函数失败时应返回错误")
+	}
+
+	short := EmbedderFunc(func(ctx context.Context, texts []string) ([][]float32, error) {
+		return [][]float32{{1}}, nil
+	})
+	if _, err := EmbedRegistry(context.Background(), registry, short, nil, 0); err == nil {
+		t.Error("向量数量不符时应返回错误")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := EmbedRegistry(ctx, registry, lengthEmbedder, nil, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("ctx取消时应返回context.Canceled，但得到 %v", err)
+	}
+}
+
+func TestEmbeddingStoreDimensions(t *testing.T) {
+	store := NewEmbeddingStore()
+	if err := store.Set("CWE-79", []float32{1, 0}); err != nil {
+		t.Fatalf("Set失败: %v", err)
+	}
+	if err := store.Set("CWE-89", []float32{1, 0, 0}); err == nil {
+		t.Error("维度不一致时应返回错误")
+	}
+	if err := store.Set("CWE-89", nil); err == nil {
+		t.Error("空向量应返回错误")
+	}
+}
@@ -0,0 +1,290 @@
+package cwe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// RegistryEventType 是注册表变化事件的类型
+type RegistryEventType string
+
+const (
+	// EventEntryAdded 新注册表中新增了条目
+	EventEntryAdded RegistryEventType = "entry.added"
+
+	// EventEntryRemoved 新注册表中删除了条目
+	EventEntryRemoved RegistryEventType = "entry.removed"
+
+	// EventEntryModified 条目的字段或父子关系发生了变化
+	EventEntryModified RegistryEventType = "entry.modified"
+)
+
+// RegistryEvent 描述两个注册表之间单个条目的变化
+type RegistryEvent struct {
+	// Type 事件类型
+	Type RegistryEventType `json:"type"`
+
+	// ID 条目ID
+	ID string `json:"id"`
+
+	// Name 条目名称，删除事件为旧名称，其他事件为新名称
+	Name string `json:"name"`
+
+	// Fields 修改事件中发生变化的字段名，如"Name"、"Severity"、"Parent"
+	Fields []string `json:"fields,omitempty"`
+
+	// PreviousVersion 旧注册表的CWE版本
+	PreviousVersion string `json:"previous_version,omitempty"`
+
+	// Version 新注册表的CWE版本
+	Version string `json:"version,omitempty"`
+}
+
+// DiffRegistries 比较两个注册表，返回每个新增、删除和修改的条目对应的事件
+//
+// 方法功能:
+// 按条目ID对齐两个注册表，比较名称、描述、严重性、状态、抽象级别、URL、缓解措施以及父节点和子节点的ID，
+// 条目的Version字段随每次发布变化，不参与比较。事件按CWE编号排序，
+// PreviousVersion和Version分别为两个注册表的Version()。
+//
+// 参数:
+// - before: *Registry - 旧注册表，为nil时视为空注册表
+// - after: *Registry - 新注册表，为nil时视为空注册表
+//
+// 返回值:
+// - []RegistryEvent: 变化事件，没有变化时返回空切片
+//
+// 使用示例:
+// ```go
+//
+//	for _, event := range cwe.DiffRegistries(previous, current) {
+//	    fmt.Println(event.Type, event.ID, strings.Join(event.Fields, ", "))
+//	}
+//
+// ```
+func DiffRegistries(before, after *Registry) []RegistryEvent {
+	beforeEntries, previousVersion := diffEntries(before)
+	afterEntries, version := diffEntries(after)
+
+	ids := make([]string, 0, len(beforeEntries)+len(afterEntries))
+	for id := range beforeEntries {
+		ids = append(ids, id)
+	}
+	for id := range afterEntries {
+		if _, exists := beforeEntries[id]; !exists {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return compareCWEIDs(ids[i], ids[j]) < 0
+	})
+
+	events := make([]RegistryEvent, 0)
+	for _, id := range ids {
+		old, inBefore := beforeEntries[id]
+		updated, inAfter := afterEntries[id]
+		event := RegistryEvent{ID: id, PreviousVersion: previousVersion, Version: version}
+		switch {
+		case !inBefore:
+			event.Type, event.Name = EventEntryAdded, updated.Name
+		case !inAfter:
+			event.Type, event.Name = EventEntryRemoved, old.Name
+		default:
+			event.Fields = changedEntryFields(old, updated)
+			if len(event.Fields) == 0 {
+				continue
+			}
+			event.Type, event.Name = EventEntryModified, updated.Name
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// diffEntries 返回注册表以ID为键的条目和版本，registry为nil时返回空映射
+func diffEntries(registry *Registry) (map[string]*CWE, string) {
+	entries := make(map[string]*CWE)
+	if registry == nil {
+		return entries, ""
+	}
+	for _, entry := range registry.Snapshot() {
+		entries[entry.ID] = entry
+	}
+	return entries, registry.Version()
+}
+
+// changedEntryFields 返回两个条目之间发生变化的字段名，见entryFieldChanges
+func changedEntryFields(a, b *CWE) []string {
+	changes := entryFieldChanges(a, b)
+	fields := make([]string, 0, len(changes))
+	for _, change := range changes {
+		fields = append(fields, change.Field)
+	}
+	return fields
+}
+
+// parentIDOf 返回条目父节点的ID，没有父节点时返回空字符串
+func parentIDOf(entry *CWE) string {
+	if entry.Parent == nil {
+		return ""
+	}
+	return entry.Parent.ID
+}
+
+// childIDsOf 返回条目所有子节点的ID，按CWE编号排序
+func childIDsOf(entry *CWE) []string {
+	ids := make([]string, 0, len(entry.Children))
+	for _, child := range entry.Children {
+		ids = append(ids, child.ID)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return compareCWEIDs(ids[i], ids[j]) < 0
+	})
+	return ids
+}
+
+// RegistryEventHandler 处理一批注册表变化事件，返回错误表示处理失败
+type RegistryEventHandler func(ctx context.Context, events []RegistryEvent) error
+
+// EventEmitter 将注册表变化事件分发给注册的处理函数和webhook
+//
+// 下游系统(工单、文档站点等)通过它与MITRE的发布保持同步：
+// 挂载到RegistryManager后，每次发现新版本并替换注册表时，新旧注册表之间的差异会作为一批事件发出。
+// 也可以在比较任意两个注册表后手动调用Emit。
+//
+// EventEmitter是并发安全的，可以在多个goroutine中使用
+type EventEmitter struct {
+	mutex    sync.RWMutex
+	handlers []RegistryEventHandler
+}
+
+// NewEventEmitter 创建一个没有处理函数的事件分发器
+func NewEventEmitter() *EventEmitter {
+	return &EventEmitter{}
+}
+
+// On 注册处理函数，handler为nil时不做任何修改
+// 处理函数在Emit所在的goroutine中按注册顺序同步调用
+func (e *EventEmitter) On(handler RegistryEventHandler) {
+	if handler == nil {
+		return
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.handlers = append(e.handlers, handler)
+}
+
+// AddWebhook 注册一个接收事件的webhook
+//
+// 方法功能:
+// 每次Emit向url发送一个POST请求，请求体为{"events": [...]}形式的JSON，
+// 响应状态码不是2xx时视为失败。请求通过client发送，享有其重试、拦截器等功能；
+// client为nil时使用不限速的新客户端，不占用访问CWE API的速率限制。
+//
+// 参数:
+// - url: string - webhook地址
+// - client: *HTTPClient - 发送请求的客户端，可为nil
+//
+// 使用示例:
+// ```go
+// emitter := cwe.NewEventEmitter()
+// emitter.AddWebhook("https://tickets.example.com/hooks/cwe", nil)
+//
+// manager := cwe.NewRegistryManager(cwe.NewAPIClient(), "1000")
+// manager.SetEventEmitter(emitter)
+// manager.Start(ctx)
+// ```
+func (e *EventEmitter) AddWebhook(url string, client *HTTPClient) {
+	if client == nil {
+		client = NewHttpClient()
+		client.SetRateLimiter(NewHTTPRateLimiter(0))
+	}
+	e.On(webhookHandler(url, client))
+}
+
+// Emit 将一批事件依次交给所有处理函数
+//
+// 方法功能:
+// 某个处理函数失败不会影响其他处理函数，所有处理函数都调用完后返回第一个错误，
+// 错误信息中包含失败的处理函数数量。events为空时不调用任何处理函数。
+//
+// 参数:
+// - ctx: context.Context - 传递给处理函数，用于取消webhook请求
+// - events: []RegistryEvent - 要分发的事件
+//
+// 返回值:
+// - error: 有处理函数失败时返回错误
+func (e *EventEmitter) Emit(ctx context.Context, events []RegistryEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	e.mutex.RLock()
+	handlers := append([]RegistryEventHandler(nil), e.handlers...)
+	e.mutex.RUnlock()
+
+	var first error
+	failed := 0
+	for _, handler := range handlers {
+		if err := handler(ctx, events); err != nil {
+			if first == nil {
+				first = err
+			}
+			failed++
+		}
+	}
+	if failed > 1 {
+		return fmt.Errorf("%d个事件处理函数失败，第一个错误: %w", failed, first)
+	}
+	return first
+}
+
+// webhookHandler 返回将事件POST到url的处理函数
+func webhookHandler(url string, client *HTTPClient) RegistryEventHandler {
+	return func(ctx context.Context, events []RegistryEvent) error {
+		body, err := json.Marshal(struct {
+			Events []RegistryEvent `json:"events"`
+		}{events})
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.Post(ctx, url, body)
+		if err != nil {
+			return fmt.Errorf("发送webhook %s失败: %w", url, err)
+		}
+		defer resp.Body.Close()
+		io.Copy(io.Discard, resp.Body)
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("webhook %s返回状态码%d", url, resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// SetEventEmitter 设置注册表被替换时发出变化事件的分发器，为nil时不发出事件
+//
+// 每次刷新替换注册表后，新旧注册表的差异(见DiffRegistries)作为一批事件交给emitter，
+// 在OnUpdate回调之前发出。第一次构建注册表时没有旧注册表，不发出事件。
+// 分发失败只记录日志，不影响注册表的替换。
+func (m *RegistryManager) SetEventEmitter(emitter *EventEmitter) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.emitter = emitter
+}
+
+// emitChanges 将新旧注册表的差异交给事件分发器
+func (m *RegistryManager) emitChanges(ctx context.Context, emitter *EventEmitter, before, after *Registry) {
+	if emitter == nil || before == nil {
+		return
+	}
+	events := DiffRegistries(before, after)
+	if err := emitter.Emit(ctx, events); err != nil {
+		m.log().Warn("发送注册表变化事件失败", "events", len(events), "error", err)
+	}
+}
@@ -0,0 +1,141 @@
+package cwe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDiffRegistries(t *testing.T) {
+	before := newQueryTestRegistry()
+	before.SetVersion("4.15")
+	after := before.Clone()
+	after.SetVersion("4.16")
+
+	after.Entries["CWE-79"].Severity = "Critical"
+	after.Entries["CWE-79"].Name = "Improper Neutralization of Input During Web Page Generation"
+	delete(after.Entries, "CWE-306")
+	after.Entries["CWE-287"].Children = nil
+	after.Register(NewCWE("CWE-352", "Cross-Site Request Forgery"))
+
+	events := DiffRegistries(before, after)
+	want := []RegistryEvent{
+		{Type: EventEntryModified, ID: "CWE-79", Name: "Improper Neutralization of Input During Web Page Generation", Fields: []string{"Name", "Severity"}, PreviousVersion: "4.15", Version: "4.16"},
+		{Type: EventEntryModified, ID: "CWE-287", Name: "Improper Authentication", Fields: []string{"Children"}, PreviousVersion: "4.15", Version: "4.16"},
+		{Type: EventEntryRemoved, ID: "CWE-306", Name: before.Entries["CWE-306"].Name, PreviousVersion: "4.15", Version: "4.16"},
+		{Type: EventEntryAdded, ID: "CWE-352", Name: "Cross-Site Request Forgery", PreviousVersion: "4.15", Version: "4.16"},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("事件不正确:\n%+v\n期望:\n%+v", events, want)
+	}
+
+	if events := DiffRegistries(before, before.Clone()); len(events) != 0 {
+		t.Errorf("相同的注册表不应有事件: %+v", events)
+	}
+	if events := DiffRegistries(nil, before); len(events) != len(before.Entries) || events[0].Type != EventEntryAdded {
+		t.Errorf("与nil比较时所有条目都应是新增: %+v", events)
+	}
+}
+
+func TestEventEmitter(t *testing.T) {
+	emitter := NewEventEmitter()
+	events := []RegistryEvent{{Type: EventEntryAdded, ID: "CWE-352"}}
+
+	var received [][]RegistryEvent
+	emitter.On(func(ctx context.Context, batch []RegistryEvent) error {
+		received = append(received, batch)
+		return nil
+	})
+	emitter.On(func(ctx context.Context, batch []RegistryEvent) error {
+		return errors.New("工单系统不可用")
+	})
+	calls := 0
+	emitter.On(func(ctx context.Context, batch []RegistryEvent) error {
+		calls++
+		return nil
+	})
+
+	err := emitter.Emit(context.Background(), events)
+	if err == nil || !strings.Contains(err.Error(), "工单系统不可用") {
+		t.Errorf("应返回处理函数的错误，实际: %v", err)
+	}
+	if len(received) != 1 || calls != 1 {
+		t.Errorf("某个处理函数失败时其他处理函数仍应被调用: %d, %d", len(received), calls)
+	}
+
+	if err := emitter.Emit(context.Background(), nil); err != nil || len(received) != 1 {
+		t.Errorf("没有事件时不应调用处理函数: %v", err)
+	}
+}
+
+func TestEventEmitterWebhook(t *testing.T) {
+	var status int32 = http.StatusNoContent
+	var payload struct {
+		Events []RegistryEvent `json:"events"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("webhook请求不正确: %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+	}))
+	defer server.Close()
+
+	emitter := NewEventEmitter()
+	emitter.AddWebhook(server.URL, nil)
+
+	events := []RegistryEvent{{Type: EventEntryModified, ID: "CWE-79", Fields: []string{"Severity"}, Version: "4.16"}}
+	if err := emitter.Emit(context.Background(), events); err != nil {
+		t.Fatalf("Emit失败: %v", err)
+	}
+	if !reflect.DeepEqual(payload.Events, events) {
+		t.Errorf("webhook收到的事件不正确: %+v", payload.Events)
+	}
+
+	atomic.StoreInt32(&status, http.StatusBadRequest)
+	if err := emitter.Emit(context.Background(), events); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("非2xx状态码应返回错误，实际: %v", err)
+	}
+}
+
+func TestRegistryManagerEmitsEvents(t *testing.T) {
+	var version atomic.Value
+	version.Store("4.15")
+	server := setupManagerTestServer(t, &version)
+	defer server.Close()
+
+	var received []RegistryEvent
+	emitter := NewEventEmitter()
+	emitter.On(func(ctx context.Context, events []RegistryEvent) error {
+		received = append(received, events...)
+		return nil
+	})
+
+	manager := NewRegistryManager(newManagerTestClient(server.URL), "1000")
+	manager.SetEventEmitter(emitter)
+
+	if _, err := manager.Refresh(context.Background()); err != nil {
+		t.Fatalf("第一次刷新失败: %v", err)
+	}
+	if len(received) != 0 {
+		t.Errorf("第一次构建不应发出事件: %+v", received)
+	}
+
+	// 模拟上一个版本中CWE-79的名称不同
+	manager.Registry().Entries["CWE-79"].Name = "Old Name"
+	version.Store("4.16")
+	if _, err := manager.Refresh(context.Background()); err != nil {
+		t.Fatalf("第二次刷新失败: %v", err)
+	}
+	if len(received) != 1 || received[0].ID != "CWE-79" || received[0].Type != EventEntryModified ||
+		!reflect.DeepEqual(received[0].Fields, []string{"Name"}) || received[0].PreviousVersion != "4.15" || received[0].Version != "4.16" {
+		t.Errorf("事件不正确: %+v", received)
+	}
+}
@@ -0,0 +1,63 @@
+package cwe
+
+import "fmt"
+
+// testNode 描述内部测试注册表中的一个条目，用于以表格形式定义测试树
+//
+// cwe包的内部测试不能导入cwetest(会形成导入循环)，因此在这里提供与cwetest.Node相同的表格式构建方式，
+// 各测试文件不必再各自编写注册、建立父子关系和设置根节点的循环。
+type testNode struct {
+	// id 条目ID，必须为"CWE-数字"格式
+	id string
+
+	// name 条目名称，为空时使用ID
+	name string
+
+	// parent 父节点ID，为空表示没有父节点；父节点必须出现在子节点之前
+	parent string
+
+	// severity 和abstraction 是条目的严重性和抽象级别，可以为空
+	severity    string
+	abstraction string
+
+	// mitigations 缓解措施描述，可以为nil
+	mitigations []string
+
+	// setup 注册之前设置条目的其他字段，可以为nil
+	setup func(entry *CWE)
+}
+
+// buildTestRegistry 根据节点表构建注册表，第一个没有父节点的条目成为根节点
+// 节点表无效时panic，只在测试中使用
+func buildTestRegistry(nodes []testNode) *Registry {
+	registry := NewRegistry()
+	for _, node := range nodes {
+		name := node.name
+		if name == "" {
+			name = node.id
+		}
+		entry := NewCWE(node.id, name)
+		entry.Severity = node.severity
+		entry.Abstraction = node.abstraction
+		entry.Mitigations = append(entry.Mitigations, node.mitigations...)
+		if node.setup != nil {
+			node.setup(entry)
+		}
+		if err := registry.Register(entry); err != nil {
+			panic(fmt.Sprintf("注册测试条目%s失败: %v", node.id, err))
+		}
+
+		if node.parent == "" {
+			if registry.Root == nil {
+				registry.Root = entry
+			}
+			continue
+		}
+		parent, exists := registry.Entries[node.parent]
+		if !exists {
+			panic(fmt.Sprintf("测试条目%s的父节点%s不存在或出现在子节点之后", node.id, node.parent))
+		}
+		parent.AddChild(entry)
+	}
+	return registry
+}
@@ -0,0 +1,340 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// CWE关系性质，即CWERelation.Nature的取值
+const (
+	RelationChildOf    = "ChildOf"
+	RelationParentOf   = "ParentOf"
+	RelationMemberOf   = "MemberOf"
+	RelationHasMember  = "HasMember"
+	RelationPeerOf     = "PeerOf"
+	RelationCanPrecede = "CanPrecede"
+	RelationCanFollow  = "CanFollow"
+	RelationRequires   = "Requires"
+	RelationRequiredBy = "RequiredBy"
+	RelationCanAlsoBe  = "CanAlsoBe"
+	RelationStartsWith = "StartsWith"
+)
+
+// inverseRelations 关系性质与其反向关系的对应表，如A ChildOf B等价于B ParentOf A
+// PeerOf和CanAlsoBe是对称关系，反向关系是其自身
+var inverseRelations = map[string]string{
+	RelationChildOf:    RelationParentOf,
+	RelationParentOf:   RelationChildOf,
+	RelationMemberOf:   RelationHasMember,
+	RelationHasMember:  RelationMemberOf,
+	RelationCanPrecede: RelationCanFollow,
+	RelationCanFollow:  RelationCanPrecede,
+	RelationRequires:   RelationRequiredBy,
+	RelationRequiredBy: RelationRequires,
+	RelationPeerOf:     RelationPeerOf,
+	RelationCanAlsoBe:  RelationCanAlsoBe,
+}
+
+// ErrNoPath 表示图中两个节点之间不存在满足条件的路径
+var ErrNoPath = errors.New("节点之间没有路径")
+
+// GraphEdge 是图中的一条有向类型化边，表示"From Nature To"，如"CWE-79 ChildOf CWE-74"
+type GraphEdge struct {
+	// From 起点ID
+	From string
+
+	// To 终点ID
+	To string
+
+	// Nature 关系性质，如RelationChildOf
+	Nature string
+
+	// ViewID 关系所属的视图ID，为空表示不限视图
+	ViewID string
+
+	// Ordinal 关系优先级，如"Primary"
+	Ordinal string
+}
+
+// Graph 是CWE条目之间类型化关系的图
+//
+// 与Registry的父子树不同，Graph保留了PeerOf、CanPrecede、Requires等非层次关系，
+// 可以用于分析弱点之间的链式和组合关系。边来自条目的Relations字段(即API返回的related_weaknesses)，
+// 被引用但不在图中的条目ID仍会出现在边的端点上，只是Node对其返回false。
+//
+// Graph不是并发安全的：构建完成后可以在多个goroutine中并发读取，但不能同时修改。
+type Graph struct {
+	nodes map[string]*CWE
+	out   map[string][]GraphEdge
+	in    map[string][]GraphEdge
+}
+
+// NewGraph 创建空图
+func NewGraph() *Graph {
+	return &Graph{
+		nodes: make(map[string]*CWE),
+		out:   make(map[string][]GraphEdge),
+		in:    make(map[string][]GraphEdge),
+	}
+}
+
+// NewGraphFromRegistry 根据注册表中所有条目的Relations构建图
+//
+// 方法功能:
+// 注册表中的每个条目都成为图的节点，每条关系成为一条从该条目出发的边。
+// 注册表中的Children层次不会转换为边；从API获取的弱点已在Relations中包含ChildOf关系。
+//
+// 参数:
+// - registry: *Registry - 注册表
+//
+// 返回值:
+// - *Graph: 构建好的图，与注册表共享CWE对象
+//
+// 使用示例:
+// ```go
+// graph := cwe.NewGraphFromRegistry(registry)
+//
+// // CWE-79的所有父节点
+// parents := graph.Neighbors("CWE-79", cwe.RelationChildOf)
+//
+// // 只沿ChildOf/ParentOf查找CWE-79到CWE-20的路径
+// path, err := graph.ShortestPath("CWE-79", "CWE-20", cwe.RelationChildOf, cwe.RelationParentOf)
+// ```
+func NewGraphFromRegistry(registry *Registry) *Graph {
+	graph := NewGraph()
+	for _, entry := range registry.Snapshot() {
+		graph.AddNode(entry)
+		for _, rel := range normalizeRelations(entry.Relations) {
+			graph.AddEdge(GraphEdge{
+				From:    entry.ID,
+				To:      rel.CweID,
+				Nature:  rel.Nature,
+				ViewID:  rel.ViewID,
+				Ordinal: rel.Ordinal,
+			})
+		}
+	}
+	return graph
+}
+
+// AddNode 添加节点，ID相同的节点会被替换
+func (g *Graph) AddNode(entry *CWE) {
+	g.nodes[entry.ID] = entry
+}
+
+// AddEdge 添加边，与已有的边完全相同时不做任何操作
+func (g *Graph) AddEdge(edge GraphEdge) {
+	for _, existing := range g.out[edge.From] {
+		if existing == edge {
+			return
+		}
+	}
+	g.out[edge.From] = append(g.out[edge.From], edge)
+	g.in[edge.To] = append(g.in[edge.To], edge)
+}
+
+// Node 返回ID对应的节点
+func (g *Graph) Node(id string) (*CWE, bool) {
+	entry, exists := g.nodes[normalizeEntryID(id)]
+	return entry, exists
+}
+
+// Nodes 返回所有节点，按CWE编号排序
+func (g *Graph) Nodes() []*CWE {
+	nodes := make([]*CWE, 0, len(g.nodes))
+	for _, entry := range g.nodes {
+		nodes = append(nodes, entry)
+	}
+	sortByCWEID(nodes)
+	return nodes
+}
+
+// Edges 返回所有边，按起点、终点和关系性质排序
+func (g *Graph) Edges() []GraphEdge {
+	edges := make([]GraphEdge, 0)
+	for _, list := range g.out {
+		edges = append(edges, list...)
+	}
+	sortEdges(edges)
+	return edges
+}
+
+// OutEdges 返回从id出发的边，按终点和关系性质排序
+func (g *Graph) OutEdges(id string) []GraphEdge {
+	edges := append([]GraphEdge(nil), g.out[normalizeEntryID(id)]...)
+	sortEdges(edges)
+	return edges
+}
+
+// InEdges 返回指向id的边，按起点和关系性质排序
+func (g *Graph) InEdges(id string) []GraphEdge {
+	edges := append([]GraphEdge(nil), g.in[normalizeEntryID(id)]...)
+	sortEdges(edges)
+	return edges
+}
+
+// Neighbors 返回与id之间存在指定关系的节点ID
+//
+// 方法功能:
+// 返回所有满足"id relation X"的X，同时考虑反向记录的关系：
+// 例如图中只有"CWE-79 ChildOf CWE-74"一条边时，Neighbors("CWE-74", RelationParentOf)也会返回CWE-79。
+// relation为空时返回通过任意关系(任意方向)相连的节点。
+//
+// 参数:
+// - id: string - 节点ID
+// - relation: string - 关系性质，如RelationCanPrecede，为空表示任意关系
+//
+// 返回值:
+// - []string: 相邻节点ID，按CWE编号排序且不重复；没有相邻节点时返回空切片
+func (g *Graph) Neighbors(id, relation string) []string {
+	id = normalizeEntryID(id)
+	seen := make(map[string]bool)
+	for _, edge := range g.out[id] {
+		if relation == "" || edge.Nature == relation {
+			seen[edge.To] = true
+		}
+	}
+	inverse := inverseRelations[relation]
+	for _, edge := range g.in[id] {
+		if relation == "" || (inverse != "" && edge.Nature == inverse) {
+			seen[edge.From] = true
+		}
+	}
+	delete(seen, id)
+
+	neighbors := make([]string, 0, len(seen))
+	for neighbor := range seen {
+		neighbors = append(neighbors, neighbor)
+	}
+	sort.Slice(neighbors, func(i, j int) bool {
+		return compareCWEIDs(neighbors[i], neighbors[j]) < 0
+	})
+	return neighbors
+}
+
+// ShortestPath 返回从from到to经过节点最少的路径
+//
+// 方法功能:
+// 广度优先搜索，每一步沿Neighbors(当前节点, relation)前进，relation取relations中的任意一个；
+// relations为空时可以沿任意关系、任意方向前进。相邻节点按CWE编号顺序访问，结果是确定的。
+//
+// 参数:
+// - from: string - 起点ID
+// - to: string - 终点ID
+// - relations: ...string - 允许经过的关系性质
+//
+// 返回值:
+// - []string: 包括起点和终点的节点ID序列，from与to相同时只包含一个ID
+// - error: 起点或终点不在图中时返回包装了ErrNotFound的错误，没有路径时返回包装了ErrNoPath的错误
+func (g *Graph) ShortestPath(from, to string, relations ...string) ([]string, error) {
+	from = normalizeEntryID(from)
+	to = normalizeEntryID(to)
+	for _, id := range []string{from, to} {
+		if !g.contains(id) {
+			return nil, &notFoundError{id: id}
+		}
+	}
+	if from == to {
+		return []string{from}, nil
+	}
+
+	if len(relations) == 0 {
+		relations = []string{""}
+	}
+
+	previous := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, next := range g.neighborsAny(current, relations) {
+			if _, visited := previous[next]; visited {
+				continue
+			}
+			previous[next] = current
+			if next == to {
+				path := []string{to}
+				for step := current; step != ""; step = previous[step] {
+					path = append(path, step)
+				}
+				for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+					path[i], path[j] = path[j], path[i]
+				}
+				return path, nil
+			}
+			queue = append(queue, next)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s -> %s", ErrNoPath, from, to)
+}
+
+// Subgraph 返回只包含指定节点及其之间的边的新图
+// 不在图中的ID会被忽略；新图与原图共享CWE对象，但边是独立的
+func (g *Graph) Subgraph(ids []string) *Graph {
+	keep := make(map[string]bool, len(ids))
+	sub := NewGraph()
+	for _, id := range ids {
+		id = normalizeEntryID(id)
+		if entry, exists := g.nodes[id]; exists {
+			keep[id] = true
+			sub.AddNode(entry)
+		}
+	}
+
+	for _, edge := range g.Edges() {
+		if keep[edge.From] && keep[edge.To] {
+			sub.AddEdge(edge)
+		}
+	}
+	return sub
+}
+
+// contains 判断id是否为图中的节点或边的端点
+func (g *Graph) contains(id string) bool {
+	if _, exists := g.nodes[id]; exists {
+		return true
+	}
+	return len(g.out[id]) > 0 || len(g.in[id]) > 0
+}
+
+// neighborsAny 返回沿relations中任意关系相邻的节点，按CWE编号排序且不重复
+func (g *Graph) neighborsAny(id string, relations []string) []string {
+	if len(relations) == 1 {
+		return g.Neighbors(id, relations[0])
+	}
+
+	seen := make(map[string]bool)
+	for _, relation := range relations {
+		for _, neighbor := range g.Neighbors(id, relation) {
+			seen[neighbor] = true
+		}
+	}
+	neighbors := make([]string, 0, len(seen))
+	for neighbor := range seen {
+		neighbors = append(neighbors, neighbor)
+	}
+	sort.Slice(neighbors, func(i, j int) bool {
+		return compareCWEIDs(neighbors[i], neighbors[j]) < 0
+	})
+	return neighbors
+}
+
+// sortEdges 按起点、终点、关系性质和视图排序边
+func sortEdges(edges []GraphEdge) {
+	sort.Slice(edges, func(i, j int) bool {
+		a, b := edges[i], edges[j]
+		if c := compareCWEIDs(a.From, b.From); c != 0 {
+			return c < 0
+		}
+		if c := compareCWEIDs(a.To, b.To); c != 0 {
+			return c < 0
+		}
+		if a.Nature != b.Nature {
+			return a.Nature < b.Nature
+		}
+		return a.ViewID < b.ViewID
+	})
+}
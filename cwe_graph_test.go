@@ -0,0 +1,150 @@
+package cwe
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// newGraphTestRegistry 创建带类型化关系的测试注册表，共7个节点和6条边
+//
+//	CWE-79 ChildOf CWE-74，CWE-89 ChildOf CWE-74，CWE-74 ChildOf CWE-707
+//	CWE-120 CanPrecede CWE-787，CWE-787 PeerOf CWE-125
+//	CWE-79 ChildOf CWE-20(视图CWE-699)
+func newGraphTestRegistry() *Registry {
+	registry := NewRegistry()
+	add := func(id string, relations ...CWERelation) {
+		entry := NewCWE(id, id)
+		entry.Relations = relations
+		registry.Register(entry)
+	}
+	add("CWE-707")
+	add("CWE-74", CWERelation{Nature: RelationChildOf, CweID: "707", ViewID: "1000"})
+	add("CWE-79",
+		CWERelation{Nature: RelationChildOf, CweID: "74", ViewID: "1000", Ordinal: "Primary"},
+		CWERelation{Nature: RelationChildOf, CweID: "CWE-20", ViewID: "699"},
+	)
+	add("CWE-89", CWERelation{Nature: RelationChildOf, CweID: "CWE-74", ViewID: "CWE-1000"})
+	add("CWE-120", CWERelation{Nature: RelationCanPrecede, CweID: "787", ViewID: "1000"})
+	add("CWE-787", CWERelation{Nature: RelationPeerOf, CweID: "125", ViewID: "1000"})
+	add("CWE-125")
+	return registry
+}
+
+func TestNewGraphFromRegistry(t *testing.T) {
+	graph := NewGraphFromRegistry(newGraphTestRegistry())
+
+	if len(graph.Nodes()) != 7 {
+		t.Errorf("应有7个节点，实际为%d", len(graph.Nodes()))
+	}
+	if len(graph.Edges()) != 6 {
+		t.Errorf("应有6条边，实际为%d", len(graph.Edges()))
+	}
+
+	// CWE-20不在注册表中，但仍是边的端点
+	if _, exists := graph.Node("CWE-20"); exists {
+		t.Error("CWE-20不应是图中的节点")
+	}
+	if in := graph.InEdges("20"); len(in) != 1 || in[0].From != "CWE-79" || in[0].ViewID != "CWE-699" {
+		t.Errorf("CWE-20的入边不正确: %v", in)
+	}
+
+	out := graph.OutEdges("CWE-79")
+	expected := []GraphEdge{
+		{From: "CWE-79", To: "CWE-20", Nature: RelationChildOf, ViewID: "CWE-699"},
+		{From: "CWE-79", To: "CWE-74", Nature: RelationChildOf, ViewID: "CWE-1000", Ordinal: "Primary"},
+	}
+	if !reflect.DeepEqual(out, expected) {
+		t.Errorf("出边应为%v，实际为%v", expected, out)
+	}
+
+	// 重复的边只保留一条
+	graph.AddEdge(expected[0])
+	if len(graph.OutEdges("CWE-79")) != 2 {
+		t.Error("重复的边不应被添加")
+	}
+}
+
+func TestGraphNeighbors(t *testing.T) {
+	graph := NewGraphFromRegistry(newGraphTestRegistry())
+
+	tests := []struct {
+		id       string
+		relation string
+		expected []string
+	}{
+		{"CWE-79", RelationChildOf, []string{"CWE-20", "CWE-74"}},
+		// 反向关系
+		{"CWE-74", RelationParentOf, []string{"CWE-79", "CWE-89"}},
+		{"CWE-787", RelationCanFollow, []string{"CWE-120"}},
+		// 对称关系
+		{"CWE-125", RelationPeerOf, []string{"CWE-787"}},
+		{"CWE-787", RelationPeerOf, []string{"CWE-125"}},
+		// 任意关系
+		{"CWE-74", "", []string{"CWE-79", "CWE-89", "CWE-707"}},
+		{"CWE-120", RelationRequires, []string{}},
+	}
+	for _, tt := range tests {
+		if got := graph.Neighbors(tt.id, tt.relation); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Neighbors(%s, %q) = %v，期望%v", tt.id, tt.relation, got, tt.expected)
+		}
+	}
+}
+
+func TestGraphShortestPath(t *testing.T) {
+	graph := NewGraphFromRegistry(newGraphTestRegistry())
+
+	path, err := graph.ShortestPath("79", "CWE-707", RelationChildOf)
+	if err != nil {
+		t.Fatalf("查找路径失败: %v", err)
+	}
+	if !reflect.DeepEqual(path, []string{"CWE-79", "CWE-74", "CWE-707"}) {
+		t.Errorf("路径不正确: %v", path)
+	}
+
+	// 沿ChildOf和ParentOf经过共同的父节点
+	path, err = graph.ShortestPath("CWE-79", "CWE-89", RelationChildOf, RelationParentOf)
+	if err != nil || !reflect.DeepEqual(path, []string{"CWE-79", "CWE-74", "CWE-89"}) {
+		t.Errorf("路径应经过CWE-74，实际为%v, %v", path, err)
+	}
+
+	// 只沿ChildOf无法到达兄弟节点
+	if _, err := graph.ShortestPath("CWE-79", "CWE-89", RelationChildOf); !errors.Is(err, ErrNoPath) {
+		t.Errorf("应返回ErrNoPath，实际为%v", err)
+	}
+
+	// 不限关系时沿任意方向前进
+	path, err = graph.ShortestPath("CWE-125", "CWE-120")
+	if err != nil || !reflect.DeepEqual(path, []string{"CWE-125", "CWE-787", "CWE-120"}) {
+		t.Errorf("路径不正确: %v, %v", path, err)
+	}
+
+	if path, err := graph.ShortestPath("CWE-79", "CWE-79"); err != nil || len(path) != 1 {
+		t.Errorf("起点和终点相同时应只包含一个ID，实际为%v, %v", path, err)
+	}
+	if _, err := graph.ShortestPath("CWE-79", "CWE-99999"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("终点不存在时应返回ErrNotFound，实际为%v", err)
+	}
+	if _, err := graph.ShortestPath("CWE-79", "CWE-125"); !errors.Is(err, ErrNoPath) {
+		t.Errorf("不连通的节点应返回ErrNoPath，实际为%v", err)
+	}
+}
+
+func TestGraphSubgraph(t *testing.T) {
+	graph := NewGraphFromRegistry(newGraphTestRegistry())
+
+	sub := graph.Subgraph([]string{"CWE-79", "74", "CWE-89", "CWE-99999"})
+	if len(sub.Nodes()) != 3 {
+		t.Errorf("子图应有3个节点，实际为%d", len(sub.Nodes()))
+	}
+	edges := sub.Edges()
+	if len(edges) != 2 || edges[0].From != "CWE-79" || edges[1].From != "CWE-89" {
+		t.Errorf("子图只应包含两端都在子图中的边，实际为%v", edges)
+	}
+
+	// 修改子图不影响原图
+	sub.AddEdge(GraphEdge{From: "CWE-79", To: "CWE-89", Nature: RelationPeerOf})
+	if len(graph.Neighbors("CWE-79", RelationPeerOf)) != 0 {
+		t.Error("修改子图不应影响原图")
+	}
+}
@@ -0,0 +1,318 @@
+package cwe
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"hash/fnv"
+	"io"
+	"math"
+	"os"
+)
+
+// DefaultIDIndexFalsePositiveRate 创建ID索引时默认的误判率
+const DefaultIDIndexFalsePositiveRate = 0.001
+
+// idIndexMagic 索引文件的魔数，idIndexFormat 索引文件的格式版本
+const (
+	idIndexMagic  = "CWEI"
+	idIndexFormat = 1
+)
+
+// ErrInvalidIDIndex 表示索引数据已损坏或不是ID索引
+var ErrInvalidIDIndex = errors.New("无效的ID索引数据")
+
+// IDIndex 是基于布隆过滤器的CWE ID成员索引
+//
+// 只需要判断ID是否存在的轻量服务可以加载几KB的索引，而不必加载完整的CWE数据。
+// Contains对索引中的ID总是返回true；对不在索引中的ID以约等于创建时指定的误判率返回true，
+// 因此返回false时可以确定ID不存在，返回true时ID几乎一定存在。
+//
+// IDIndex创建后是只读的，可以在多个goroutine中并发使用。
+type IDIndex struct {
+	version string
+	count   int
+	hashes  uint8
+	size    uint64
+	bits    []uint64
+}
+
+// NewIDIndex 为一组CWE ID创建索引
+//
+// 方法功能:
+// 根据ID数量和目标误判率计算布隆过滤器的大小和哈希函数个数，并将所有ID加入索引。
+// ID会先通过ParseCWEID规范化，重复的ID只计算一次。
+//
+// 参数:
+// - version: string - 索引对应的CWE版本，仅作为元数据保存，可以为空
+// - ids: []string - CWE ID列表
+// - falsePositiveRate: float64 - 目标误判率，必须在(0, 1)之间，<=0时使用DefaultIDIndexFalsePositiveRate
+//
+// 返回值:
+// - *IDIndex: 创建的索引
+// - error: ID无法解析或误判率无效时返回错误
+//
+// 使用示例:
+// ```go
+// index, err := cwe.NewIDIndex("4.16", []string{"CWE-79", "CWE-89", "CWE-787"}, 0.001)
+//
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// err = index.Save("cwe-4.16.idx")
+// ```
+func NewIDIndex(version string, ids []string, falsePositiveRate float64) (*IDIndex, error) {
+	if falsePositiveRate <= 0 {
+		falsePositiveRate = DefaultIDIndexFalsePositiveRate
+	}
+	if falsePositiveRate >= 1 {
+		return nil, fmt.Errorf("误判率必须小于1: %v", falsePositiveRate)
+	}
+
+	normalized := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		n, err := ParseCWEID(id)
+		if err != nil {
+			return nil, err
+		}
+		normalized[n] = true
+	}
+
+	n := float64(len(normalized))
+	if n == 0 {
+		n = 1
+	}
+	size := uint64(math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	if size < 64 {
+		size = 64
+	}
+	hashes := int(math.Round(float64(size) / n * math.Ln2))
+	if hashes < 1 {
+		hashes = 1
+	}
+	if hashes > math.MaxUint8 {
+		hashes = math.MaxUint8
+	}
+
+	index := &IDIndex{
+		version: version,
+		count:   len(normalized),
+		hashes:  uint8(hashes),
+		size:    size,
+		bits:    make([]uint64, (size+63)/64),
+	}
+	for id := range normalized {
+		index.add(id)
+	}
+	return index, nil
+}
+
+// BuildIDIndex 为注册表中的所有条目创建索引
+// 参数含义与NewIDIndex相同，通常在构建完整注册表后调用并用Save保存，供其他服务通过LoadIDIndex加载
+func (r *Registry) BuildIDIndex(version string, falsePositiveRate float64) (*IDIndex, error) {
+	return NewIDIndex(version, r.sortedIDs(), falsePositiveRate)
+}
+
+// Contains 判断ID是否可能在索引中
+// id支持ParseCWEID接受的所有格式，无法解析时返回false
+func (idx *IDIndex) Contains(id string) bool {
+	normalized, err := ParseCWEID(id)
+	if err != nil {
+		return false
+	}
+
+	h1, h2 := idIndexHash(normalized)
+	for i := uint64(0); i < uint64(idx.hashes); i++ {
+		bit := (h1 + i*h2) % idx.size
+		if idx.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Version 返回索引对应的CWE版本
+func (idx *IDIndex) Version() string {
+	return idx.version
+}
+
+// Len 返回创建索引时加入的ID数量
+func (idx *IDIndex) Len() int {
+	return idx.count
+}
+
+// SizeBytes 返回位数组占用的字节数
+func (idx *IDIndex) SizeBytes() int {
+	return len(idx.bits) * 8
+}
+
+// add 将规范化后的ID加入索引
+func (idx *IDIndex) add(id string) {
+	h1, h2 := idIndexHash(id)
+	for i := uint64(0); i < uint64(idx.hashes); i++ {
+		bit := (h1 + i*h2) % idx.size
+		idx.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// idIndexHash 计算双重哈希使用的两个哈希值
+// h2保证为奇数，避免所有探测位置落在同一位置
+func idIndexHash(id string) (uint64, uint64) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(id))
+	sum := h.Sum64()
+	return sum, (sum>>32 | sum<<32) | 1
+}
+
+// WriteTo 将索引序列化写入w
+//
+// 格式为: 魔数"CWEI"、格式版本(1字节)、哈希函数个数(1字节)、ID数量(uint32)、
+// 位数(uint64)、版本字符串长度(uint16)和内容、位数组(uint64小端序)、
+// 以及前面所有内容的CRC32校验和(uint32)，整数均为小端序。
+func (idx *IDIndex) WriteTo(w io.Writer) (int64, error) {
+	checksum := crc32.NewIEEE()
+	counter := &countingWriter{w: io.MultiWriter(w, checksum)}
+
+	if len(idx.version) > math.MaxUint16 {
+		return 0, fmt.Errorf("版本字符串过长: %d字节", len(idx.version))
+	}
+
+	header := []interface{}{
+		[]byte(idIndexMagic),
+		uint8(idIndexFormat),
+		idx.hashes,
+		uint32(idx.count),
+		idx.size,
+		uint16(len(idx.version)),
+		[]byte(idx.version),
+		idx.bits,
+	}
+	for _, field := range header {
+		if err := binary.Write(counter, binary.LittleEndian, field); err != nil {
+			return counter.n, err
+		}
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, checksum.Sum32()); err != nil {
+		return counter.n, err
+	}
+	return counter.n + 4, nil
+}
+
+// Save 将索引写入文件
+func (idx *IDIndex) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+	if _, err := idx.WriteTo(writer); err != nil {
+		file.Close()
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// ReadIDIndex 从r读取WriteTo写入的索引
+//
+// 返回值:
+// - *IDIndex: 读取的索引
+// - error: 数据损坏、格式版本不支持或校验和不匹配时返回包装了ErrInvalidIDIndex的错误
+func ReadIDIndex(r io.Reader) (*IDIndex, error) {
+	checksum := crc32.NewIEEE()
+	reader := io.TeeReader(r, checksum)
+
+	var header struct {
+		Magic      [4]byte
+		Format     uint8
+		Hashes     uint8
+		Count      uint32
+		Size       uint64
+		VersionLen uint16
+	}
+	if err := binary.Read(reader, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDIndex, err)
+	}
+	if string(header.Magic[:]) != idIndexMagic {
+		return nil, fmt.Errorf("%w: 魔数不匹配", ErrInvalidIDIndex)
+	}
+	if header.Format != idIndexFormat {
+		return nil, fmt.Errorf("%w: 不支持的格式版本 %d", ErrInvalidIDIndex, header.Format)
+	}
+	// 位数上限防止损坏的数据导致分配过大的内存
+	if header.Hashes == 0 || header.Size == 0 || header.Size > 1<<32 {
+		return nil, fmt.Errorf("%w: 无效的索引参数", ErrInvalidIDIndex)
+	}
+
+	version := make([]byte, header.VersionLen)
+	if _, err := io.ReadFull(reader, version); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDIndex, err)
+	}
+
+	bits := make([]uint64, (header.Size+63)/64)
+	if err := binary.Read(reader, binary.LittleEndian, bits); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDIndex, err)
+	}
+
+	expected := checksum.Sum32()
+	var actual uint32
+	if err := binary.Read(r, binary.LittleEndian, &actual); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDIndex, err)
+	}
+	if actual != expected {
+		return nil, fmt.Errorf("%w: 校验和不匹配", ErrInvalidIDIndex)
+	}
+
+	return &IDIndex{
+		version: string(version),
+		count:   int(header.Count),
+		hashes:  header.Hashes,
+		size:    header.Size,
+		bits:    bits,
+	}, nil
+}
+
+// LoadIDIndex 从文件加载索引
+//
+// 使用示例:
+// ```go
+// index, err := cwe.LoadIDIndex("cwe-4.16.idx")
+//
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	if !index.Contains(userInput) {
+//	    return fmt.Errorf("未知的CWE: %s", userInput)
+//	}
+//
+// ```
+func LoadIDIndex(path string) (*IDIndex, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadIDIndex(bufio.NewReader(file))
+}
+
+// countingWriter 记录写入的字节数
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,136 @@
+package cwe
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestIDIndexContains(t *testing.T) {
+	ids := make([]string, 0, 1000)
+	for i := 1; i <= 1000; i++ {
+		ids = append(ids, fmt.Sprintf("CWE-%d", i))
+	}
+
+	index, err := NewIDIndex("4.16", ids, 0.01)
+	if err != nil {
+		t.Fatalf("创建索引失败: %v", err)
+	}
+	if index.Len() != 1000 || index.Version() != "4.16" {
+		t.Errorf("Len() = %d, Version() = %q", index.Len(), index.Version())
+	}
+	if index.SizeBytes() > 2048 {
+		t.Errorf("1000个ID、1%%误判率的索引应小于2KB，实际为%d字节", index.SizeBytes())
+	}
+
+	for _, id := range ids {
+		if !index.Contains(id) {
+			t.Fatalf("索引应包含%s", id)
+		}
+	}
+	if !index.Contains("79") || !index.Contains("cwe-079") {
+		t.Error("Contains应规范化ID")
+	}
+	if index.Contains("invalid") {
+		t.Error("无效ID应返回false")
+	}
+
+	falsePositives := 0
+	for i := 100001; i <= 110000; i++ {
+		if index.Contains(fmt.Sprintf("CWE-%d", i)) {
+			falsePositives++
+		}
+	}
+	if rate := float64(falsePositives) / 10000; rate > 0.03 {
+		t.Errorf("误判率过高: %.4f", rate)
+	}
+}
+
+func TestNewIDIndexErrors(t *testing.T) {
+	if _, err := NewIDIndex("", []string{"bad"}, 0); err == nil {
+		t.Error("无效ID应返回错误")
+	}
+	if _, err := NewIDIndex("", []string{"CWE-79"}, 1); err == nil {
+		t.Error("误判率为1应返回错误")
+	}
+
+	empty, err := NewIDIndex("", nil, 0)
+	if err != nil {
+		t.Fatalf("空索引应创建成功: %v", err)
+	}
+	if empty.Contains("CWE-79") {
+		t.Error("空索引不应包含任何ID")
+	}
+}
+
+func TestIDIndexSaveAndLoad(t *testing.T) {
+	registry := NewRegistry()
+	for _, id := range []string{"CWE-20", "CWE-79", "CWE-89"} {
+		if err := registry.Register(NewCWE(id, id)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	index, err := registry.BuildIDIndex("4.16", 0)
+	if err != nil {
+		t.Fatalf("创建索引失败: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "cwe.idx")
+	if err := index.Save(path); err != nil {
+		t.Fatalf("保存索引失败: %v", err)
+	}
+
+	loaded, err := LoadIDIndex(path)
+	if err != nil {
+		t.Fatalf("加载索引失败: %v", err)
+	}
+	if loaded.Version() != "4.16" || loaded.Len() != 3 {
+		t.Errorf("Version() = %q, Len() = %d", loaded.Version(), loaded.Len())
+	}
+	for _, id := range []string{"CWE-20", "CWE-79", "CWE-89"} {
+		if !loaded.Contains(id) {
+			t.Errorf("加载的索引应包含%s", id)
+		}
+	}
+	if loaded.Contains("CWE-787") {
+		t.Error("加载的索引不应包含CWE-787")
+	}
+}
+
+func TestReadIDIndexInvalid(t *testing.T) {
+	index, err := NewIDIndex("4.16", []string{"CWE-79"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	n, err := index.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo返回%d，实际写入%d字节", n, buf.Len())
+	}
+	data := buf.Bytes()
+
+	corrupted := append([]byte(nil), data...)
+	corrupted[len(corrupted)-10] ^= 0xff
+
+	tests := map[string][]byte{
+		"空数据":   nil,
+		"魔数错误":  append([]byte("XXXX"), data[4:]...),
+		"数据截断":  data[:len(data)-6],
+		"校验和错误": corrupted,
+	}
+	for name, input := range tests {
+		if _, err := ReadIDIndex(bytes.NewReader(input)); !errors.Is(err, ErrInvalidIDIndex) {
+			t.Errorf("%s: 应返回ErrInvalidIDIndex，实际为%v", name, err)
+		}
+	}
+
+	if _, err := LoadIDIndex(filepath.Join(t.TempDir(), "missing.idx")); err == nil {
+		t.Error("文件不存在时应返回错误")
+	}
+}
@@ -0,0 +1,169 @@
+package cwe
+
+import "sort"
+
+//go:generate go run ./cmd/cwegen -in data/cwe-top25-snapshot.json -tables data/cwe-tables.json -o cwe_top25_gen.go -bundle data/cwe-offline.json.gz
+
+// CWEID 是生成的CWE ID常量的类型，值为规范化的"CWE-数字"格式
+// 可通过String()或string(id)传给接受字符串ID的方法
+//
+// 本包内置的常量和元数据只来自data/cwe-top25-snapshot.json，即各年份Top 25列表、常用视图
+// 和废弃别名涉及的条目，不是完整的CWE目录。需要其他条目的常量时，可以用cmd/cwegen根据
+// cwe fetch得到的完整快照为自己的包生成。
+type CWEID string
+
+// String 返回ID字符串
+func (id CWEID) String() string {
+	return string(id)
+}
+
+// Name 返回内置数据中条目的名称，内置快照中没有该条目时返回空字符串
+func (id CWEID) Name() string {
+	return knownEntries[id].Name
+}
+
+// KnownEntry 是cmd/cwegen从CWE快照生成的条目元数据
+// 下游项目通过cwegen为其他包生成的元数据表也使用此类型
+type KnownEntry struct {
+	// ID 条目ID
+	ID CWEID
+
+	// Name 条目名称
+	Name string
+
+	// Abstraction 抽象级别，如"Base"，快照中没有时为空
+	Abstraction string
+
+	// Status 条目状态，如"Stable"，快照中没有时为空
+	Status string
+}
+
+// LookupKnown 查找内置快照中条目的元数据
+// 内置快照只包含Top 25子集，不在其中的条目返回false，见CWEID
+//
+// 方法功能:
+// 不需要加载注册表或访问API，即可得到条目的名称、抽象级别和状态，适合在日志和报告中显示CWE名称。
+// 内置数据由cmd/cwegen生成，版本见KnownCWEVersion。
+//
+// 参数:
+// - id: string - CWE ID，支持ParseCWEID接受的所有格式
+//
+// 返回值:
+// - KnownEntry: 条目的元数据
+// - bool: 内置快照中是否有该条目
+//
+// 使用示例:
+// ```go
+//
+//	if entry, ok := cwe.LookupKnown("79"); ok {
+//	    fmt.Printf("%s: %s\n", entry.ID, entry.Name)
+//	}
+//
+// ```
+func LookupKnown(id string) (KnownEntry, bool) {
+	normalized, err := ParseCWEID(id)
+	if err != nil {
+		return KnownEntry{}, false
+	}
+	entry, exists := knownEntries[CWEID(normalized)]
+	return entry, exists
+}
+
+// KnownEntries 返回内置快照中所有条目的元数据，按编号排序
+// 内置快照只包含Top 25子集，见CWEID
+func KnownEntries() []KnownEntry {
+	entries := make([]KnownEntry, 0, len(knownEntries))
+	for _, entry := range knownEntries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return compareCWEIDs(string(entries[i].ID), string(entries[j].ID)) < 0
+	})
+	return entries
+}
+
+// Top25 返回指定年份的CWE Top 25列表，按排名排序
+//
+// 方法功能:
+// 返回内置数据中该年份的"最危险的软件弱点"列表，返回值为副本，可以安全修改。
+// 内置数据由cmd/cwegen生成，版本见KnownCWEVersion。
+//
+// 参数:
+// - year: int - 年份，如2024
+//
+// 返回值:
+// - []CWEID: 按排名排序的CWE ID，没有该年份的数据时返回nil
+func Top25(year int) []CWEID {
+	list, exists := top25Lists[year]
+	if !exists {
+		return nil
+	}
+	return append([]CWEID(nil), list...)
+}
+
+// Top25Years 返回内置数据中包含Top 25列表的年份，升序排列
+func Top25Years() []int {
+	years := make([]int, 0, len(top25Lists))
+	for year := range top25Lists {
+		years = append(years, year)
+	}
+	sort.Ints(years)
+	return years
+}
+
+// IsTop25 判断CWE是否在指定年份的Top 25列表中
+// id支持ParseCWEID接受的所有格式，无法解析时返回false
+func IsTop25(id string, year int) bool {
+	normalized, err := ParseCWEID(id)
+	if err != nil {
+		return false
+	}
+	for _, entry := range top25Lists[year] {
+		if string(entry) == normalized {
+			return true
+		}
+	}
+	return false
+}
+
+// KnownViews 返回内置数据中的视图ID，按编号排序
+func KnownViews() []CWEID {
+	views := make([]CWEID, 0, len(knownViewNames))
+	for id := range knownViewNames {
+		views = append(views, id)
+	}
+	sort.Slice(views, func(i, j int) bool {
+		return compareCWEIDs(string(views[i]), string(views[j])) < 0
+	})
+	return views
+}
+
+// ViewName 返回内置数据中视图的名称，第二个返回值表示该ID是否为已知视图
+func ViewName(id string) (string, bool) {
+	normalized, err := ParseCWEID(id)
+	if err != nil {
+		return "", false
+	}
+	name, exists := knownViewNames[CWEID(normalized)]
+	return name, exists
+}
+
+// ResolveDeprecated 查找已废弃CWE的替代条目
+//
+// 方法功能:
+// 根据内置的废弃别名表，返回已废弃条目推荐使用的替代ID。
+//
+// 参数:
+// - id: string - CWE ID，支持ParseCWEID接受的所有格式
+//
+// 返回值:
+// - string: 替代条目的ID
+// - bool: id是否为已知的废弃条目
+func ResolveDeprecated(id string) (string, bool) {
+	normalized, err := ParseCWEID(id)
+	if err != nil {
+		return "", false
+	}
+	replacement, exists := deprecatedAliases[CWEID(normalized)]
+	return string(replacement), exists
+}
@@ -0,0 +1,105 @@
+package cwe
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTop25(t *testing.T) {
+	list := Top25(2024)
+	if len(list) != 25 {
+		t.Fatalf("2024年Top 25应有25项，实际为%d", len(list))
+	}
+	if list[0] != CWE79 {
+		t.Errorf("2024年排名第一应为CWE-79，实际为%s", list[0])
+	}
+
+	// 返回值是副本
+	list[0] = CWE20
+	if Top25(2024)[0] != CWE79 {
+		t.Error("修改返回值不应影响内置数据")
+	}
+
+	if Top25(1999) != nil {
+		t.Error("没有数据的年份应返回nil")
+	}
+
+	years := Top25Years()
+	if len(years) < 2 || years[0] != 2023 || years[1] != 2024 {
+		t.Errorf("Top25Years() = %v", years)
+	}
+}
+
+func TestIsTop25(t *testing.T) {
+	tests := []struct {
+		id   string
+		year int
+		want bool
+	}{
+		{"CWE-79", 2024, true},
+		{"79", 2024, true},
+		{"cwe-400", 2024, true},
+		{"CWE-400", 2023, false},
+		{"CWE-276", 2023, true},
+		{"CWE-276", 2024, false},
+		{"CWE-79", 1999, false},
+		{"invalid", 2024, false},
+	}
+	for _, tt := range tests {
+		if got := IsTop25(tt.id, tt.year); got != tt.want {
+			t.Errorf("IsTop25(%q, %d) = %v, want %v", tt.id, tt.year, got, tt.want)
+		}
+	}
+}
+
+func TestViewName(t *testing.T) {
+	name, ok := ViewName("1000")
+	if !ok || name != "Research Concepts" {
+		t.Errorf("ViewName(1000) = %q, %v", name, ok)
+	}
+	if _, ok := ViewName("CWE-79"); ok {
+		t.Error("CWE-79不是视图")
+	}
+
+	views := KnownViews()
+	if len(views) == 0 || views[0] != CWE699 {
+		t.Errorf("KnownViews()应按编号排序，实际为%v", views)
+	}
+}
+
+func TestResolveDeprecated(t *testing.T) {
+	replacement, ok := ResolveDeprecated("CWE-71")
+	if !ok || replacement != "CWE-62" {
+		t.Errorf("ResolveDeprecated(CWE-71) = %q, %v", replacement, ok)
+	}
+	if _, ok := ResolveDeprecated("CWE-79"); ok {
+		t.Error("CWE-79不是废弃条目")
+	}
+	if _, ok := ResolveDeprecated(""); ok {
+		t.Error("无效ID应返回false")
+	}
+}
+
+func TestLookupKnown(t *testing.T) {
+	entry, ok := LookupKnown("79")
+	if !ok || entry.ID != CWE79 || entry.Name != CWE79.Name() || !strings.Contains(entry.Name, "Cross-site Scripting") {
+		t.Errorf("LookupKnown(79) = %+v, %v", entry, ok)
+	}
+	if entry, ok := LookupKnown("CWE-71"); !ok || entry.Status != StatusDeprecated {
+		t.Errorf("应保留条目状态: %+v", entry)
+	}
+	if _, ok := LookupKnown("CWE-999999"); ok {
+		t.Error("快照中没有的条目应返回false")
+	}
+	if _, ok := LookupKnown("bad"); ok {
+		t.Error("无效ID应返回false")
+	}
+	if CWEID("CWE-999999").Name() != "" {
+		t.Error("未知条目的名称应为空")
+	}
+
+	entries := KnownEntries()
+	if len(entries) == 0 || entries[0].ID != CWE20 {
+		t.Errorf("KnownEntries()应按编号排序，实际第一个为%v", entries)
+	}
+}
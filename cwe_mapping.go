@@ -0,0 +1,250 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 映射用途，即CWEMappingNotes.Usage的取值，表示MITRE是否建议将漏洞映射到该条目
+const (
+	// MappingAllowed 允许映射，通常是Base和Variant级别的弱点
+	MappingAllowed = "Allowed"
+
+	// MappingAllowedWithReview 允许映射，但需要人工确认没有更合适的条目，通常是Class级别的弱点
+	MappingAllowedWithReview = "Allowed-with-Review"
+
+	// MappingDiscouraged 不建议映射，应选择更具体的条目
+	MappingDiscouraged = "Discouraged"
+
+	// MappingProhibited 禁止映射，如类别、视图和废弃条目
+	MappingProhibited = "Prohibited"
+)
+
+var (
+	// ErrMappingDiscouraged 表示MITRE不建议将漏洞映射到该条目
+	ErrMappingDiscouraged = errors.New("不建议映射到该条目")
+
+	// ErrMappingProhibited 表示MITRE禁止将漏洞映射到该条目
+	ErrMappingProhibited = errors.New("禁止映射到该条目")
+
+	// ErrNoAllowedAlternative 表示条目的祖先和后代中都没有允许映射的条目
+	ErrNoAllowedAlternative = errors.New("没有允许映射的替代条目")
+)
+
+// mappingUsages 规范化后的映射用途，键为去掉空白、连字符并转为小写的写法
+var mappingUsages = map[string]string{
+	"allowed":           MappingAllowed,
+	"allowedwithreview": MappingAllowedWithReview,
+	"discouraged":       MappingDiscouraged,
+	"prohibited":        MappingProhibited,
+}
+
+// NormalizeMappingUsage 将映射用途规范化为MappingAllowed等常量
+// 不区分大小写，忽略空白和连字符，如"allowed with review"返回MappingAllowedWithReview；
+// 无法识别的取值去掉首尾空白后原样返回
+func NormalizeMappingUsage(usage string) string {
+	key := strings.ToLower(strings.Join(strings.FieldsFunc(usage, func(r rune) bool {
+		return r == '-' || r == '_' || r == ' ' || r == '\t'
+	}), ""))
+	if normalized, ok := mappingUsages[key]; ok {
+		return normalized
+	}
+	return strings.TrimSpace(usage)
+}
+
+// MappingUsage 返回条目的映射用途
+// 废弃条目总是返回MappingProhibited；没有映射注释时返回空字符串，表示未知
+func (c *CWE) MappingUsage() string {
+	if c.Status == StatusDeprecated {
+		return MappingProhibited
+	}
+	return NormalizeMappingUsage(mappingNotesUsage(c))
+}
+
+// mappingNotesUsage 返回映射注释中的Usage，不考虑条目状态，没有映射注释时返回空字符串
+func mappingNotesUsage(c *CWE) string {
+	if c.MappingNotes == nil {
+		return ""
+	}
+	return c.MappingNotes.Usage
+}
+
+// MappingError 表示不应将漏洞映射到某个条目，可以用errors.Is与ErrMappingDiscouraged或ErrMappingProhibited比较
+type MappingError struct {
+	// ID 条目ID
+	ID string
+
+	// Usage 条目的映射用途，MappingDiscouraged或MappingProhibited
+	Usage string
+
+	// Rationale MITRE给出的理由，可能为空
+	Rationale string
+}
+
+// Error 实现error接口
+func (e *MappingError) Error() string {
+	message := fmt.Sprintf("%s的映射用途为%s", e.ID, e.Usage)
+	if e.Rationale != "" {
+		message += ": " + e.Rationale
+	}
+	return message
+}
+
+// Unwrap 返回对应的ErrMappingDiscouraged或ErrMappingProhibited
+func (e *MappingError) Unwrap() error {
+	if e.Usage == MappingDiscouraged {
+		return ErrMappingDiscouraged
+	}
+	return ErrMappingProhibited
+}
+
+// checkMapping 检查条目是否允许映射，允许时返回nil
+// 映射用途未知或无法识别时视为允许，以免在缺少映射注释的注册表中拒绝所有条目
+func checkMapping(entry *CWE) error {
+	usage := entry.MappingUsage()
+	if usage != MappingDiscouraged && usage != MappingProhibited {
+		return nil
+	}
+
+	mappingErr := &MappingError{ID: entry.ID, Usage: usage}
+	if entry.MappingNotes != nil {
+		mappingErr.Rationale = entry.MappingNotes.Rationale
+	}
+	if entry.Status == StatusDeprecated && mappingErr.Rationale == "" {
+		mappingErr.Rationale = "条目已废弃"
+	}
+	return mappingErr
+}
+
+// ValidateMapping 检查是否可以将漏洞映射到指定条目
+//
+// 方法功能:
+// 按照条目映射注释中的Usage执行MITRE的映射指南：Allowed和Allowed-with-Review允许映射，
+// Discouraged和Prohibited返回*MappingError，废弃条目总是视为Prohibited。
+// 没有映射注释的条目(如手动构建的条目或旧版快照)视为允许。
+//
+// 参数:
+// - id: string - 条目ID，支持ParseCWEID接受的所有格式
+//
+// 返回值:
+// - error: 允许映射时返回nil；条目不存在时返回包装了ErrNotFound的错误；
+// 不允许映射时返回*MappingError，可用errors.Is判断是ErrMappingDiscouraged还是ErrMappingProhibited
+//
+// 使用示例:
+// ```go
+//
+//	if err := registry.ValidateMapping(finding.CWE); errors.Is(err, cwe.ErrMappingProhibited) {
+//	    alternative, _ := registry.SuggestAllowedAlternative(finding.CWE)
+//	    log.Printf("%v，建议改为%s", err, alternative.ID)
+//	}
+//
+// ```
+func (r *Registry) ValidateMapping(id string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	entry, exists := r.Entries[normalizeEntryID(id)]
+	if !exists {
+		return &notFoundError{id: id}
+	}
+	return checkMapping(entry)
+}
+
+// SuggestAllowedAlternative 为不允许映射的条目推荐距离最近的允许映射的条目
+//
+// 方法功能:
+// 条目本身允许映射时直接返回它。否则分别沿Parents向上和沿Children向下按距离从近到远查找
+// 通过ValidateMapping检查的条目，返回距离最近的一个。距离相同时优先返回更具体的后代，
+// 同一方向上距离相同的条目中，祖先按GetAncestors的顺序(Primary父节点优先)、后代按CWE编号选择。
+// 不会经过共同祖先走到兄弟节点。
+//
+// 参数:
+// - id: string - 条目ID，支持ParseCWEID接受的所有格式
+//
+// 返回值:
+// - *CWE: 推荐的条目
+// - error: 条目不存在时返回包装了ErrNotFound的错误，没有可推荐的条目时返回包装了ErrNoAllowedAlternative的错误
+//
+// 使用示例:
+// ```go
+// // 类别CWE-1019禁止映射，推荐其成员CWE-79
+// alternative, err := registry.SuggestAllowedAlternative("CWE-1019")
+// ```
+func (r *Registry) SuggestAllowedAlternative(id string) (*CWE, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	entry, exists := r.Entries[normalizeEntryID(id)]
+	if !exists {
+		return nil, &notFoundError{id: id}
+	}
+	if checkMapping(entry) == nil {
+		return entry, nil
+	}
+
+	var ancestor *CWE
+	ancestorDistance := -1
+	for _, candidate := range r.ancestorDistances(entry) {
+		if candidate.entry != entry && checkMapping(candidate.entry) == nil {
+			ancestor, ancestorDistance = candidate.entry, candidate.distance
+			break
+		}
+	}
+
+	// 沿Children逐层查找，找到时当前层数就是距离
+	visited := map[*CWE]bool{entry: true}
+	level := []*CWE{entry}
+	for distance := 1; len(level) > 0 && (ancestor == nil || distance <= ancestorDistance); distance++ {
+		var next []*CWE
+		for _, current := range level {
+			for _, child := range current.Children {
+				if !visited[child] {
+					visited[child] = true
+					next = append(next, child)
+				}
+			}
+		}
+		sortByCWEID(next)
+		for _, candidate := range next {
+			if checkMapping(candidate) == nil {
+				return candidate, nil
+			}
+		}
+		level = next
+	}
+
+	if ancestor != nil {
+		return ancestor, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNoAllowedAlternative, entry.ID)
+}
+
+// ValidateMapping 检查是否可以将漏洞映射到指定条目，见Registry.ValidateMapping
+func (s *FrozenRegistry) ValidateMapping(id string) error {
+	return s.registry.ValidateMapping(id)
+}
+
+// SuggestAllowedAlternative 推荐距离最近的允许映射的条目，见Registry.SuggestAllowedAlternative
+func (s *FrozenRegistry) SuggestAllowedAlternative(id string) (*CWE, error) {
+	return s.registry.SuggestAllowedAlternative(id)
+}
+
+// normalizeMappingNotes 复制API返回的映射注释并规范化Usage，notes为nil时返回nil
+func normalizeMappingNotes(notes *CWEMappingNotes) *CWEMappingNotes {
+	copied := copyMappingNotes(notes)
+	if copied != nil {
+		copied.Usage = NormalizeMappingUsage(copied.Usage)
+	}
+	return copied
+}
+
+// copyMappingNotes 深拷贝映射注释，notes为nil时返回nil
+func copyMappingNotes(notes *CWEMappingNotes) *CWEMappingNotes {
+	if notes == nil {
+		return nil
+	}
+	copied := *notes
+	copied.Reasons = append([]string(nil), notes.Reasons...)
+	return &copied
+}
@@ -0,0 +1,149 @@
+package cwe
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func newMappingTestRegistry() *Registry {
+	// usage 设置映射说明，理由为"<ID> rationale"
+	usage := func(usage string) func(*CWE) {
+		return func(entry *CWE) {
+			entry.MappingNotes = &CWEMappingNotes{Usage: usage, Rationale: entry.ID + " rationale"}
+		}
+	}
+	return buildTestRegistry([]testNode{
+		{id: "CWE-1000", setup: usage(MappingProhibited)},
+		{id: "CWE-707", parent: "CWE-1000", setup: usage(MappingDiscouraged)},
+		{id: "CWE-74", parent: "CWE-707", setup: usage("allowed with review")},
+		{id: "CWE-89", parent: "CWE-74", setup: usage(MappingAllowed)},
+		{id: "CWE-600", parent: "CWE-89", setup: usage(MappingDiscouraged)},
+		{id: "CWE-20", parent: "CWE-707", setup: usage(MappingAllowedWithReview)},
+		{id: "CWE-1284", parent: "CWE-20", setup: usage(MappingDiscouraged)},
+		{id: "CWE-1285", parent: "CWE-1284", setup: usage(MappingAllowed)},
+		{id: "CWE-1019", parent: "CWE-1000", setup: usage(MappingProhibited)},
+		{id: "CWE-79", parent: "CWE-1019", setup: usage(MappingAllowed)},
+		{id: "CWE-5", setup: usage(MappingProhibited)},
+		{id: "CWE-71", name: "DEPRECATED", setup: func(entry *CWE) { entry.Status = StatusDeprecated }},
+		{id: "CWE-352", name: "No Notes"},
+	})
+}
+
+func TestNormalizeMappingUsage(t *testing.T) {
+	tests := map[string]string{
+		"Allowed":             MappingAllowed,
+		" allowed ":           MappingAllowed,
+		"Allowed-with-Review": MappingAllowedWithReview,
+		"ALLOWED WITH REVIEW": MappingAllowedWithReview,
+		"discouraged":         MappingDiscouraged,
+		"Prohibited":          MappingProhibited,
+		" Unknown ":           "Unknown",
+		"":                    "",
+	}
+	for input, want := range tests {
+		if got := NormalizeMappingUsage(input); got != want {
+			t.Errorf("NormalizeMappingUsage(%q) = %q，期望%q", input, got, want)
+		}
+	}
+}
+
+func TestRegistryValidateMapping(t *testing.T) {
+	registry := newMappingTestRegistry()
+
+	for _, id := range []string{"CWE-89", "74", "CWE-352"} {
+		if err := registry.ValidateMapping(id); err != nil {
+			t.Errorf("%s应允许映射，实际: %v", id, err)
+		}
+	}
+
+	err := registry.ValidateMapping("CWE-707")
+	var mappingErr *MappingError
+	if !errors.Is(err, ErrMappingDiscouraged) || errors.Is(err, ErrMappingProhibited) || !errors.As(err, &mappingErr) {
+		t.Fatalf("Discouraged条目应返回ErrMappingDiscouraged，实际: %v", err)
+	}
+	if mappingErr.ID != "CWE-707" || mappingErr.Usage != MappingDiscouraged || mappingErr.Rationale != "CWE-707 rationale" {
+		t.Errorf("MappingError不正确: %+v", mappingErr)
+	}
+
+	if err := registry.ValidateMapping("CWE-1019"); !errors.Is(err, ErrMappingProhibited) {
+		t.Errorf("Prohibited条目应返回ErrMappingProhibited，实际: %v", err)
+	}
+	if err := registry.ValidateMapping("CWE-71"); !errors.Is(err, ErrMappingProhibited) || !errors.As(err, &mappingErr) || mappingErr.Rationale == "" {
+		t.Errorf("废弃条目应视为Prohibited，实际: %v", err)
+	}
+	if err := registry.ValidateMapping("CWE-404"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("条目不存在时应返回ErrNotFound，实际: %v", err)
+	}
+	if err := registry.Freeze().ValidateMapping("CWE-1000"); !errors.Is(err, ErrMappingProhibited) {
+		t.Errorf("FrozenRegistry.ValidateMapping结果不正确: %v", err)
+	}
+}
+
+func TestRegistrySuggestAllowedAlternative(t *testing.T) {
+	registry := newMappingTestRegistry()
+
+	tests := map[string]string{
+		"CWE-89":   "CWE-89",   // 本身允许映射
+		"CWE-707":  "CWE-20",   // 向下查找，跳过禁止映射的视图，距离相同时按编号选择
+		"CWE-600":  "CWE-89",   // 没有后代时向上查找
+		"CWE-1284": "CWE-1285", // 距离相同时优先后代
+		"CWE-1019": "CWE-79",   // 类别推荐成员
+	}
+	for id, want := range tests {
+		alternative, err := registry.SuggestAllowedAlternative(id)
+		if err != nil || alternative.ID != want {
+			t.Errorf("SuggestAllowedAlternative(%s) = %v, %v，期望%s", id, alternative, err, want)
+		}
+	}
+
+	if _, err := registry.SuggestAllowedAlternative("CWE-5"); !errors.Is(err, ErrNoAllowedAlternative) {
+		t.Errorf("没有可推荐的条目时应返回ErrNoAllowedAlternative，实际: %v", err)
+	}
+	if _, err := registry.SuggestAllowedAlternative("CWE-404"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("条目不存在时应返回ErrNotFound，实际: %v", err)
+	}
+	if alternative, err := registry.Freeze().SuggestAllowedAlternative("CWE-1000"); err != nil || alternative.ID != "CWE-20" {
+		t.Errorf("FrozenRegistry.SuggestAllowedAlternative结果不正确: %v, %v", alternative, err)
+	}
+}
+
+func TestMappingNotesRoundTrip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"weaknesses": [{"id": "74", "name": "Injection", "mapping_notes": {
+			"usage": "allowed-with-review", "rationale": "Class-level", "reasons": ["Abstraction"]}}]}`)
+	}))
+	defer server.Close()
+
+	entry, err := newResumableTestFetcher(server.URL).FetchWeakness("74")
+	if err != nil {
+		t.Fatalf("FetchWeakness失败: %v", err)
+	}
+	want := &CWEMappingNotes{Usage: MappingAllowedWithReview, Rationale: "Class-level", Reasons: []string{"Abstraction"}}
+	if !reflect.DeepEqual(entry.MappingNotes, want) || entry.MappingUsage() != MappingAllowedWithReview {
+		t.Errorf("映射注释不正确: %+v", entry.MappingNotes)
+	}
+
+	registry := NewRegistry()
+	registry.Register(entry)
+	if clone := registry.Clone(); !reflect.DeepEqual(clone.Entries["CWE-74"].MappingNotes, want) || clone.Entries["CWE-74"].MappingNotes == entry.MappingNotes {
+		t.Errorf("克隆应深拷贝映射注释: %+v", clone.Entries["CWE-74"].MappingNotes)
+	}
+
+	var buf bytes.Buffer
+	if err := registry.WriteExportJSON(&buf); err != nil {
+		t.Fatalf("导出失败: %v", err)
+	}
+	imported := NewRegistry()
+	if err := imported.ReadExportJSON(&buf); err != nil {
+		t.Fatalf("导入失败: %v", err)
+	}
+	if !reflect.DeepEqual(imported.Entries["CWE-74"].MappingNotes, want) {
+		t.Errorf("导出再导入应保留映射注释: %+v", imported.Entries["CWE-74"].MappingNotes)
+	}
+}
@@ -0,0 +1,163 @@
+package cwe
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MarkdownOptions 控制RenderMarkdown的输出
+type MarkdownOptions struct {
+	// HeadingLevel 标题的级别，1-6，默认为2；小节标题比它低一级
+	HeadingLevel int
+
+	// PlainSeverity 为true时严重性以粗体文本显示，而不是shields.io徽章图片
+	// Slack等不渲染Markdown图片的场景应设置为true
+	PlainSeverity bool
+
+	// MaxDescriptionLength 描述的最大字符数，超出部分以"…"截断，<=0表示不截断
+	MaxDescriptionLength int
+
+	// MaxMitigations 最多列出的缓解措施数量，<=0表示全部列出
+	MaxMitigations int
+
+	// MaxExamples 最多列出的示例数量，<=0表示全部列出
+	MaxExamples int
+
+	// HidePath 为true时不输出到根节点的路径
+	HidePath bool
+
+	// Locale 名称和描述使用的语言，如"zh-CN"，没有该语言的翻译时使用原文，见CWE.LocalizedName
+	Locale string
+}
+
+// severityBadgeColors shields.io徽章中严重性对应的颜色
+var severityBadgeColors = map[string]string{
+	"Critical": "critical",
+	"High":     "orange",
+	"Medium":   "yellow",
+	"Low":      "yellowgreen",
+}
+
+// RenderMarkdown 将单个CWE渲染为Markdown片段
+//
+// 方法功能:
+// 生成包含标题(带详情页链接)、严重性徽章、描述、到根节点的路径、缓解措施和示例的Markdown，
+// 便于安全机器人将CWE的上下文贴到GitHub Issue或Slack消息中。
+// 没有内容的部分不会输出。路径沿Parent向上查找，遇到环时停止。
+//
+// 参数:
+// - cwe: *CWE - 要渲染的CWE，为nil时返回空字符串
+// - opts: MarkdownOptions - 渲染选项
+//
+// 返回值:
+// - string: Markdown文本，以换行符结尾
+//
+// 使用示例:
+// ```go
+// entry, _ := registry.GetByID("CWE-79")
+// body := cwe.RenderMarkdown(entry, cwe.MarkdownOptions{MaxMitigations: 3})
+// // ## [CWE-79: Cross-site Scripting](https://cwe.mitre.org/data/definitions/79.html)
+// //
+// // ![Severity: High](https://img.shields.io/badge/severity-High-orange)
+// // ...
+// ```
+func RenderMarkdown(cwe *CWE, opts MarkdownOptions) string {
+	if cwe == nil {
+		return ""
+	}
+
+	level := opts.HeadingLevel
+	if level < 1 || level > 6 {
+		level = 2
+	}
+	subLevel := level + 1
+	if subLevel > 6 {
+		subLevel = 6
+	}
+
+	var b strings.Builder
+
+	title := cwe.ID
+	if name := cwe.LocalizedName(opts.Locale); name != "" {
+		title += ": " + name
+	}
+	title = markdownEscape(title)
+	if cwe.URL != "" {
+		title = fmt.Sprintf("[%s](%s)", title, cwe.URL)
+	}
+	fmt.Fprintf(&b, "%s %s\n", strings.Repeat("#", level), title)
+
+	if cwe.Severity != "" {
+		b.WriteString("\n")
+		if opts.PlainSeverity {
+			fmt.Fprintf(&b, "**Severity:** %s\n", markdownEscape(cwe.Severity))
+		} else {
+			fmt.Fprintf(&b, "![Severity: %s](%s)\n", markdownEscape(cwe.Severity), severityBadgeURL(cwe.Severity))
+		}
+	}
+
+	if description := cwe.LocalizedDescription(opts.Locale); description != "" {
+		fmt.Fprintf(&b, "\n%s\n", truncateRunes(strings.TrimSpace(description), opts.MaxDescriptionLength))
+	}
+
+	if !opts.HidePath && cwe.Parent != nil {
+		ids := make([]string, 0)
+		visited := make(map[*CWE]bool)
+		for current := cwe; current != nil && !visited[current]; current = current.Parent {
+			visited[current] = true
+			ids = append([]string{current.ID}, ids...)
+		}
+		fmt.Fprintf(&b, "\n**Path:** %s\n", strings.Join(ids, " › "))
+	}
+
+	writeMarkdownList(&b, subLevel, "Mitigations", cwe.Mitigations, opts.MaxMitigations)
+	writeMarkdownList(&b, subLevel, "Examples", cwe.Examples, opts.MaxExamples)
+
+	return b.String()
+}
+
+// writeMarkdownList 输出带小节标题的列表，超出limit的条目以"… and N more"概括
+func writeMarkdownList(b *strings.Builder, level int, title string, items []string, limit int) {
+	if len(items) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "\n%s %s\n\n", strings.Repeat("#", level), title)
+	shown := items
+	if limit > 0 && len(items) > limit {
+		shown = items[:limit]
+	}
+	for _, item := range shown {
+		// 多行内容合并为一行，避免破坏列表结构
+		fmt.Fprintf(b, "- %s\n", strings.Join(strings.Fields(item), " "))
+	}
+	if len(shown) < len(items) {
+		fmt.Fprintf(b, "- … and %d more\n", len(items)-len(shown))
+	}
+}
+
+// severityBadgeURL 返回严重性的shields.io徽章地址
+func severityBadgeURL(severity string) string {
+	color, exists := severityBadgeColors[severity]
+	if !exists {
+		color = "lightgrey"
+	}
+	// shields.io中"-"是分隔符，需要写成"--"
+	label := strings.ReplaceAll(severity, "-", "--")
+	label = strings.ReplaceAll(label, " ", "%20")
+	return fmt.Sprintf("https://img.shields.io/badge/severity-%s-%s", label, color)
+}
+
+// markdownEscape 转义会被Markdown解释为链接或强调的字符
+func markdownEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`, "*", `\*`, "_", `\_`, "`", "\\`", "<", "&lt;").Replace(s)
+}
+
+// truncateRunes 将s截断为最多limit个字符，截断时以"…"结尾；limit<=0时不截断
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if limit <= 0 || len(runes) <= limit {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:limit])) + "…"
+}
@@ -0,0 +1,83 @@
+package cwe
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRenderMarkdown 测试完整的Markdown输出
+func TestRenderMarkdown(t *testing.T) {
+	registry := newExportTestRegistry()
+	xss := registry.Entries["CWE-79"]
+	xss.URL = "https://cwe.mitre.org/data/definitions/79.html"
+	xss.Description = "  The product does not neutralize user input.  "
+	xss.Examples = []string{"CVE-2021-0001: 反射型XSS\n出现在搜索页面"}
+
+	expected := `## [CWE-79: Cross-site Scripting](https://cwe.mitre.org/data/definitions/79.html)
+
+![Severity: High](https://img.shields.io/badge/severity-High-orange)
+
+The product does not neutralize user input.
+
+**Path:** CWE-1000 › CWE-20 › CWE-79
+
+### Mitigations
+
+- 对输出进行编码
+
+### Examples
+
+- CVE-2021-0001: 反射型XSS 出现在搜索页面
+`
+	if got := RenderMarkdown(xss, MarkdownOptions{}); got != expected {
+		t.Errorf("Markdown输出不正确:\n%s", got)
+	}
+}
+
+// TestRenderMarkdownOptions 测试渲染选项
+func TestRenderMarkdownOptions(t *testing.T) {
+	entry := NewCWE("CWE-89", "SQL Injection [*]")
+	entry.Severity = "Critical"
+	entry.Description = "这是一段很长的描述"
+	entry.Mitigations = []string{"a", "b", "c"}
+
+	got := RenderMarkdown(entry, MarkdownOptions{
+		HeadingLevel:         3,
+		PlainSeverity:        true,
+		MaxDescriptionLength: 4,
+		MaxMitigations:       2,
+	})
+	for _, expected := range []string{
+		"### CWE-89: SQL Injection \\[\\*\\]\n",
+		"**Severity:** Critical\n",
+		"\n这是一段…\n",
+		"#### Mitigations\n\n- a\n- b\n- … and 1 more\n",
+	} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("输出中缺少%q:\n%s", expected, got)
+		}
+	}
+	if strings.Contains(got, "Path") || strings.Contains(got, "Examples") {
+		t.Errorf("没有父节点和示例时不应输出对应部分:\n%s", got)
+	}
+
+	if got := RenderMarkdown(nil, MarkdownOptions{}); got != "" {
+		t.Errorf("nil应返回空字符串，实际为%q", got)
+	}
+}
+
+// TestRenderMarkdownPathCycle 测试Parent形成环时路径不会无限循环
+func TestRenderMarkdownPathCycle(t *testing.T) {
+	a := NewCWE("CWE-1", "A")
+	b := NewCWE("CWE-2", "B")
+	a.Parent = b
+	b.Parent = a
+
+	got := RenderMarkdown(a, MarkdownOptions{})
+	if !strings.Contains(got, "**Path:** CWE-2 › CWE-1\n") {
+		t.Errorf("路径不正确:\n%s", got)
+	}
+	if strings.Contains(RenderMarkdown(a, MarkdownOptions{HidePath: true}), "Path") {
+		t.Error("HidePath为true时不应输出路径")
+	}
+}
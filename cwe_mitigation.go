@@ -0,0 +1,175 @@
+package cwe
+
+import "strings"
+
+// 缓解措施的常见适用阶段，即CWEMitigation.Phase的取值
+const (
+	PhaseRequirements           = "Requirements"
+	PhaseArchitectureAndDesign  = "Architecture and Design"
+	PhaseImplementation         = "Implementation"
+	PhaseBuildAndCompilation    = "Build and Compilation"
+	PhaseTesting                = "Testing"
+	PhaseInstallation           = "Installation"
+	PhaseSystemConfiguration    = "System Configuration"
+	PhaseOperation              = "Operation"
+	PhasePatchingAndMaintenance = "Patching and Maintenance"
+	PhasePolicy                 = "Policy"
+)
+
+// AppliesToPhase 判断缓解措施是否适用于指定阶段
+// 阶段名称不区分大小写；phase为空时总是返回true，没有标注阶段的缓解措施只在phase为空时适用
+func (m CWEMitigation) AppliesToPhase(phase string) bool {
+	phase = strings.TrimSpace(phase)
+	if phase == "" {
+		return true
+	}
+	for _, p := range m.Phase {
+		if strings.EqualFold(strings.TrimSpace(p), phase) {
+			return true
+		}
+	}
+	return false
+}
+
+// TypedMitigations 返回条目的类型化缓解措施
+//
+// 方法功能:
+// 设置了MitigationDetails时返回它的副本；否则将Mitigations中的每条描述转换为
+// 只有Description的CWEMitigation，便于手动构建的条目和旧版快照使用同一套接口。
+//
+// 返回值:
+// - []CWEMitigation: 缓解措施副本，修改不影响条目；没有缓解措施时返回nil
+//
+// 使用示例:
+// ```go
+//
+//	for _, mitigation := range entry.TypedMitigations() {
+//	    fmt.Println(mitigation.Phase, mitigation.Strategy, mitigation.Description)
+//	}
+//
+// ```
+func (c *CWE) TypedMitigations() []CWEMitigation {
+	if len(c.MitigationDetails) > 0 {
+		return copyMitigations(c.MitigationDetails)
+	}
+	var mitigations []CWEMitigation
+	for _, description := range c.Mitigations {
+		mitigations = append(mitigations, CWEMitigation{Description: description})
+	}
+	return mitigations
+}
+
+// MitigationAdvice 是MitigationsForPhase返回的一条缓解措施及其来源
+type MitigationAdvice struct {
+	// Mitigation 距离最近的来源中的缓解措施
+	Mitigation CWEMitigation `json:"mitigation"`
+
+	// Sources 包含该缓解措施的条目ID，按距离从近到远排列
+	Sources []string `json:"sources"`
+
+	// Distance 最近来源与查询条目的距离，0表示条目本身，1表示父节点，依此类推
+	Distance int `json:"distance"`
+}
+
+// Inherited 判断缓解措施是否只来自祖先节点
+func (a MitigationAdvice) Inherited() bool {
+	return a.Distance > 0
+}
+
+// MitigationsForPhase 返回适用于指定阶段的缓解措施，包括从祖先节点继承的缓解措施
+//
+// 方法功能:
+// 具体的弱点往往只列出针对性的缓解措施，更通用的建议记录在父弱点中。
+// 本方法按距离从近到远遍历条目本身及其所有祖先(见GetAncestors)，收集适用于phase的缓解措施。
+// 多个条目中相同的缓解措施只保留一次：有MitigationID时按ID判断，否则按忽略大小写和空白的描述判断，
+// 保留距离最近的一条并在Sources中记录所有来源。条目没有MitigationDetails时使用Mitigations中的描述，
+// 这些缓解措施没有阶段，只在phase为空时返回。
+//
+// 参数:
+// - id: string - 条目ID，支持ParseCWEID接受的所有格式
+// - phase: string - 阶段名称，如PhaseImplementation，不区分大小写；为空时返回所有阶段的缓解措施
+//
+// 返回值:
+// - []MitigationAdvice: 按距离从近到远、同一条目内按原始顺序排列，没有适用的缓解措施时为空切片
+// - error: 条目不存在时返回包装了ErrNotFound的错误
+//
+// 使用示例:
+// ```go
+// advice, err := registry.MitigationsForPhase("CWE-89", cwe.PhaseImplementation)
+//
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	for _, item := range advice {
+//	    fmt.Printf("[%s] %s (来自%v)\n", item.Mitigation.Strategy, item.Mitigation.Description, item.Sources)
+//	}
+//
+// ```
+func (r *Registry) MitigationsForPhase(id string, phase string) ([]MitigationAdvice, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	entry, exists := r.Entries[normalizeEntryID(id)]
+	if !exists {
+		return nil, &notFoundError{id: id}
+	}
+
+	advice := make([]MitigationAdvice, 0)
+	index := make(map[string]int)
+	for _, ancestor := range r.ancestorDistances(entry) {
+		for _, mitigation := range ancestor.entry.TypedMitigations() {
+			if !mitigation.AppliesToPhase(phase) {
+				continue
+			}
+			key := mitigationKey(mitigation)
+			if key == "" {
+				continue
+			}
+			if i, ok := index[key]; ok {
+				sources := advice[i].Sources
+				if sources[len(sources)-1] != ancestor.entry.ID {
+					advice[i].Sources = append(sources, ancestor.entry.ID)
+				}
+				continue
+			}
+			index[key] = len(advice)
+			advice = append(advice, MitigationAdvice{
+				Mitigation: mitigation,
+				Sources:    []string{ancestor.entry.ID},
+				Distance:   ancestor.distance,
+			})
+		}
+	}
+	return advice, nil
+}
+
+// MitigationsForPhase 返回适用于指定阶段的缓解措施，见Registry.MitigationsForPhase
+func (s *FrozenRegistry) MitigationsForPhase(id string, phase string) ([]MitigationAdvice, error) {
+	return s.registry.MitigationsForPhase(id, phase)
+}
+
+// mitigationKey 返回用于去重的键，有MitigationID时使用ID，否则使用规范化的描述
+func mitigationKey(mitigation CWEMitigation) string {
+	if id := strings.TrimSpace(mitigation.MitigationID); id != "" {
+		return "id:" + strings.ToUpper(id)
+	}
+	description := strings.Join(strings.Fields(strings.ToLower(mitigation.Description)), " ")
+	if description == "" {
+		return ""
+	}
+	return "description:" + description
+}
+
+// copyMitigations 深拷贝缓解措施列表，nil保持为nil
+func copyMitigations(mitigations []CWEMitigation) []CWEMitigation {
+	if mitigations == nil {
+		return nil
+	}
+	copied := make([]CWEMitigation, len(mitigations))
+	for i, mitigation := range mitigations {
+		mitigation.Phase = append([]string(nil), mitigation.Phase...)
+		copied[i] = mitigation
+	}
+	return copied
+}
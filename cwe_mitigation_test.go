@@ -0,0 +1,128 @@
+package cwe
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func newMitigationTestRegistry() *Registry {
+	// details 设置结构化的缓解措施
+	details := func(mitigations ...CWEMitigation) func(*CWE) {
+		return func(entry *CWE) { entry.MitigationDetails = mitigations }
+	}
+	return buildTestRegistry([]testNode{
+		{id: "CWE-707", name: "Improper Neutralization", setup: details(
+			CWEMitigation{Phase: []string{PhaseArchitectureAndDesign}, Strategy: "Input Validation", Description: "Validate all input."},
+		)},
+		{id: "CWE-74", name: "Injection", parent: "CWE-707", setup: details(
+			CWEMitigation{MitigationID: "MIT-5", Phase: []string{PhaseImplementation}, Strategy: "Input Validation", Description: "Use an allowlist."},
+			CWEMitigation{Phase: []string{PhaseImplementation, PhaseOperation}, Description: "Escape   output."},
+		)},
+		{id: "CWE-89", name: "SQL Injection", parent: "CWE-74", setup: details(
+			CWEMitigation{Phase: []string{"implementation"}, Strategy: "Libraries or Frameworks", Description: "Use parameterized queries.", Effectiveness: "High"},
+			CWEMitigation{MitigationID: "mit-5", Phase: []string{PhaseImplementation}, Description: "Use an allowlist of acceptable inputs."},
+			CWEMitigation{Phase: []string{PhaseImplementation}, Description: "escape output."},
+		)},
+		// 手动构建的条目只有描述
+		{id: "CWE-564", name: "SQL Injection: Hibernate", parent: "CWE-89", mitigations: []string{"Use Hibernate named parameters."}},
+	})
+}
+
+func TestRegistryMitigationsForPhase(t *testing.T) {
+	registry := newMitigationTestRegistry()
+
+	advice, err := registry.MitigationsForPhase("89", PhaseImplementation)
+	if err != nil {
+		t.Fatalf("MitigationsForPhase失败: %v", err)
+	}
+	want := []MitigationAdvice{
+		{Mitigation: registry.Entries["CWE-89"].MitigationDetails[0], Sources: []string{"CWE-89"}},
+		{Mitigation: registry.Entries["CWE-89"].MitigationDetails[1], Sources: []string{"CWE-89", "CWE-74"}},
+		{Mitigation: registry.Entries["CWE-89"].MitigationDetails[2], Sources: []string{"CWE-89", "CWE-74"}},
+	}
+	if !reflect.DeepEqual(advice, want) {
+		t.Errorf("缓解措施不正确:\n%+v\n期望:\n%+v", advice, want)
+	}
+
+	advice, _ = registry.MitigationsForPhase("CWE-564", "architecture and design")
+	if len(advice) != 1 || advice[0].Sources[0] != "CWE-707" || advice[0].Distance != 3 || !advice[0].Inherited() {
+		t.Errorf("应继承祖先中适用于该阶段的缓解措施: %+v", advice)
+	}
+
+	// phase为空时返回所有缓解措施，只有描述的缓解措施也包含在内
+	advice, _ = registry.MitigationsForPhase("CWE-564", "")
+	if len(advice) != 5 || advice[0].Mitigation.Description != "Use Hibernate named parameters." || advice[0].Inherited() {
+		t.Errorf("phase为空时应返回所有缓解措施: %+v", advice)
+	}
+
+	if advice, err := registry.MitigationsForPhase("CWE-707", PhaseTesting); err != nil || advice == nil || len(advice) != 0 {
+		t.Errorf("没有适用的缓解措施时应返回空切片，实际为%v, %v", advice, err)
+	}
+	if _, err := registry.MitigationsForPhase("CWE-404", ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("条目不存在时应返回ErrNotFound，实际: %v", err)
+	}
+	if advice, err := registry.Freeze().MitigationsForPhase("CWE-89", PhaseOperation); err != nil || len(advice) != 1 {
+		t.Errorf("FrozenRegistry.MitigationsForPhase结果不正确: %+v, %v", advice, err)
+	}
+}
+
+func TestTypedMitigationsRoundTrip(t *testing.T) {
+	registry := newMitigationTestRegistry()
+
+	typed := registry.Entries["CWE-89"].TypedMitigations()
+	typed[0].Phase[0] = "Testing"
+	if registry.Entries["CWE-89"].MitigationDetails[0].Phase[0] != "implementation" {
+		t.Error("TypedMitigations应返回副本")
+	}
+
+	clone := registry.Clone()
+	if !reflect.DeepEqual(clone.Entries["CWE-74"].MitigationDetails, registry.Entries["CWE-74"].MitigationDetails) {
+		t.Errorf("克隆应保留类型化的缓解措施: %+v", clone.Entries["CWE-74"].MitigationDetails)
+	}
+
+	var buf bytes.Buffer
+	if err := registry.WriteExportJSON(&buf); err != nil {
+		t.Fatalf("导出失败: %v", err)
+	}
+	imported := NewRegistry()
+	if err := imported.ReadExportJSON(&buf); err != nil {
+		t.Fatalf("导入失败: %v", err)
+	}
+	if !reflect.DeepEqual(imported.Entries["CWE-89"].MitigationDetails, registry.Entries["CWE-89"].MitigationDetails) {
+		t.Errorf("导出再导入应保留类型化的缓解措施: %+v", imported.Entries["CWE-89"].MitigationDetails)
+	}
+	if imported.Entries["CWE-564"].MitigationDetails != nil {
+		t.Errorf("只有描述的条目导入后不应有类型化的缓解措施: %+v", imported.Entries["CWE-564"].MitigationDetails)
+	}
+}
+
+func TestFetchWeaknessTypedMitigations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"weaknesses": [{"id": "89", "name": "SQL Injection", "mitigations": [
+			{"mitigation_id": "MIT-4", "phase": ["Architecture and Design"], "strategy": "Libraries or Frameworks",
+			 "description": "Use a vetted library.", "effectiveness": "High", "effectiveness_notes": "Usually"}]}]}`)
+	}))
+	defer server.Close()
+
+	entry, err := newResumableTestFetcher(server.URL).FetchWeakness("89")
+	if err != nil {
+		t.Fatalf("FetchWeakness失败: %v", err)
+	}
+	want := []CWEMitigation{{
+		MitigationID:       "MIT-4",
+		Phase:              []string{PhaseArchitectureAndDesign},
+		Strategy:           "Libraries or Frameworks",
+		Description:        "Use a vetted library.",
+		Effectiveness:      "High",
+		EffectivenessNotes: "Usually",
+	}}
+	if !reflect.DeepEqual(entry.MitigationDetails, want) || !reflect.DeepEqual(entry.Mitigations, []string{"Use a vetted library."}) {
+		t.Errorf("缓解措施不正确: %+v, %q", entry.MitigationDetails, entry.Mitigations)
+	}
+}
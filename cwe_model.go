@@ -7,6 +7,17 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"time"
+)
+
+// CWE条目的状态值
+const (
+	// StatusIncomplete 表示条目信息不完整
+	// 导入时自动创建的占位节点使用此状态标记
+	StatusIncomplete = "Incomplete"
+
+	// StatusDeprecated 表示条目已被MITRE废弃，不应再用于映射
+	StatusDeprecated = "Deprecated"
 )
 
 // CWE 表示一个CWE节点
@@ -43,13 +54,86 @@ type CWE struct {
 	// 表示此类弱点可能造成的安全影响程度
 	Severity string
 
+	// Status CWE条目的状态
+	// 可能的值: "Stable", "Draft", "Incomplete", "Deprecated"等
+	// 导入时自动创建的占位节点会被标记为"Incomplete"
+	Status string
+
+	// Abstraction 弱点的抽象级别，如"Pillar"、"Class"、"Base"、"Variant"
+	// 从API获取弱点或导入CWE字典时填充，类别和视图为空
+	Abstraction string
+
+	// Language Name和Description等文本内容的语言，如"en"、"zh-CN"
+	// 从API获取时记录服务器实际返回的语言，为空表示未知
+	Language string
+
+	// Version 条目所属的CWE内容版本，如"4.16"
+	// 从API获取时由DataFetcher根据PinVersion固定的版本或Version查询到的服务器版本填充，为空表示未知
+	Version string
+
+	// LastModified 条目最后一次提交或修改的日期，见CWEWeakness.LastModified
+	// 从API获取时根据content_history填充，字段掩码不包含FieldContentHistory或内容历史无法解析时为零值
+	LastModified time.Time
+
+	// FetchedAt 条目从API获取的时间，DataFetcher写入存储时记录，用于判断存储中的数据是否过期
+	// 零值表示未知，如手动构建的条目或旧版本写入存储的条目
+	FetchedAt time.Time
+
+	// AlternateTerms 条目的替代术语和常用缩写，如CWE-79的"XSS"
+	// 从API获取弱点时根据alternate_terms填充，关键词搜索时与名称和描述一起匹配
+	AlternateTerms []string
+
+	// Translations Name和Description在其他语言下的翻译，键为规范化的语言标签(见NormalizeLocale)
+	// 通过SetTranslation或Registry.ApplyTranslations设置，为nil表示没有翻译
+	Translations map[string]Translation
+
 	// Mitigations 相关的缓解措施列表
 	// 包含了针对此类弱点的防御和修复建议
 	Mitigations []string
 
+	// MitigationDetails 类型化的缓解措施，包含适用阶段、策略和有效性，见TypedMitigations
+	// 从API获取弱点或导入CWE字典时填充，与Mitigations一一对应；手动构建的条目可以只设置Mitigations
+	MitigationDetails []CWEMitigation
+
 	// Examples 相关的示例列表
 	// 包含了此类弱点的具体实例或攻击场景
 	Examples []string
+
+	// ObservedExamples 已观察到的实例，包含引用的CVE ID和链接，见CVEReferences
+	// 从API获取弱点或导入CWE字典时填充，与Examples一一对应；手动构建的条目可以只设置Examples
+	ObservedExamples []CWEObservedExample
+
+	// MappingNotes MITRE对是否可以将漏洞映射到该条目的说明，见ValidateMapping
+	// 从API获取或导入CWE字典时填充，Usage已通过NormalizeMappingUsage规范化；为nil表示未知
+	MappingNotes *CWEMappingNotes
+
+	// Relations 与其他CWE的类型化关系，如ChildOf、CanPrecede、PeerOf等
+	// 从API获取弱点时根据related_weaknesses填充，也可通过DataFetcher.PopulateRelations获取
+	// 与Children不同，Relations保留了关系性质和所属视图
+	Relations []CWERelation
+
+	// raw 从API获取时条目的原始JSON，见Raw
+	raw json.RawMessage
+}
+
+// Raw 返回从API获取条目时的原始JSON对象
+//
+// 只在DataFetcher启用SetRetainRaw后通过FetchWeakness、FetchCategory或FetchView获取的条目才有原始数据，
+// 从存储、快照或导出文件中读取的条目返回nil。用于在本库为新增的上游字段建模之前读取它们。
+//
+// 使用示例:
+// ```go
+// fetcher.SetRetainRaw(true)
+// entry, _ := fetcher.FetchWeakness("79")
+//
+//	var extra struct {
+//	    TaxonomyMappings []json.RawMessage `json:"TaxonomyMappings"`
+//	}
+//
+// json.Unmarshal(entry.Raw(), &extra)
+// ```
+func (c *CWE) Raw() json.RawMessage {
+	return c.raw
 }
 
 // NewCWE 创建一个新的CWE实例
@@ -343,6 +427,7 @@ func (c *CWE) ToXML() ([]byte, error) {
 		Description string   `xml:"Description,omitempty"`
 		URL         string   `xml:"URL,omitempty"`
 		Severity    string   `xml:"Severity,omitempty"`
+		Status      string   `xml:"Status,omitempty"`
 		Mitigations []string `xml:"Mitigations>Mitigation,omitempty"`
 		Examples    []string `xml:"Examples>Example,omitempty"`
 		// 不包含Parent，避免循环引用
@@ -362,6 +447,7 @@ func (c *CWE) ToXML() ([]byte, error) {
 			Description: cwe.Description,
 			URL:         cwe.URL,
 			Severity:    cwe.Severity,
+			Status:      cwe.Status,
 			Mitigations: cwe.Mitigations,
 			Examples:    cwe.Examples,
 			Children:    make([]*SafeCWE, 0, len(cwe.Children)),
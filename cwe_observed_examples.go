@@ -0,0 +1,194 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// NVDDetailURL 是NVD中CVE详情页的网址前缀
+const NVDDetailURL = "https://nvd.nist.gov/vuln/detail/"
+
+// cveMention 匹配文本中出现的CVE ID
+var cveMention = regexp.MustCompile(`(?i)\bCVE-\d{4}-\d{4,}\b`)
+
+// NVDCVEURL 返回CVE在NVD中的详情页网址，如"https://nvd.nist.gov/vuln/detail/CVE-2021-44228"
+// cveID不区分大小写，无效的CVE ID返回空字符串
+func NVDCVEURL(cveID string) string {
+	normalized, err := ParseCVEID(cveID)
+	if err != nil {
+		return ""
+	}
+	return NVDDetailURL + normalized
+}
+
+// CVEReference 是已观察到的实例中引用的一个CVE
+type CVEReference struct {
+	// CVE 规范化的CVE ID，如"CVE-2021-44228"
+	CVE string `json:"cve"`
+
+	// Description 已观察到的实例的描述，说明该CVE如何体现这个弱点
+	Description string `json:"description,omitempty"`
+
+	// Link 实例中给出的链接，通常指向cve.org
+	Link string `json:"link,omitempty"`
+
+	// NVDURL NVD中CVE详情页的网址
+	NVDURL string `json:"nvd_url"`
+
+	// Summary NVD中的CVE摘要，只在调用DataFetcher.ResolveCVESummaries后填充
+	Summary *CVESummary `json:"summary,omitempty"`
+}
+
+// ExtractCVEReferences 从已观察到的实例中提取CVE引用
+//
+// 方法功能:
+// 优先使用实例的Reference字段；Reference不是CVE ID时(如"BID-12345")，
+// 在Link和Description中查找第一个出现的CVE ID，都没有时跳过该实例。
+// 同一个CVE只保留第一次出现的实例。
+//
+// 参数:
+// - examples: []CWEObservedExample - 已观察到的实例，如CWEWeakness.ObservedExamples
+//
+// 返回值:
+// - []CVEReference: 按实例顺序排列的CVE引用，没有CVE时为空切片
+//
+// 使用示例:
+// ```go
+// _, weakness, _ := fetcher.FetchWeaknessFull("CWE-502")
+//
+//	for _, ref := range cwe.ExtractCVEReferences(weakness.ObservedExamples) {
+//	    fmt.Printf("%s %s\n", ref.CVE, ref.NVDURL)
+//	}
+//
+// ```
+func ExtractCVEReferences(examples []CWEObservedExample) []CVEReference {
+	references := make([]CVEReference, 0, len(examples))
+	seen := make(map[string]bool, len(examples))
+	for _, example := range examples {
+		cveID, err := ParseCVEID(example.Reference)
+		if err != nil {
+			mention := cveMention.FindString(example.Link)
+			if mention == "" {
+				mention = cveMention.FindString(example.Description)
+			}
+			if mention == "" {
+				continue
+			}
+			cveID = strings.ToUpper(mention)
+		}
+		if seen[cveID] {
+			continue
+		}
+		seen[cveID] = true
+
+		references = append(references, CVEReference{
+			CVE:         cveID,
+			Description: example.Description,
+			Link:        example.Link,
+			NVDURL:      NVDDetailURL + cveID,
+		})
+	}
+	return references
+}
+
+// CVEReferences 返回弱点的已观察到的实例中引用的CVE，见ExtractCVEReferences
+func (w *CWEWeakness) CVEReferences() []CVEReference {
+	return ExtractCVEReferences(w.ObservedExamples)
+}
+
+// TypedObservedExamples 返回条目的已观察到的实例
+// 设置了ObservedExamples时返回它的副本；否则将Examples中的每条描述转换为只有Description的CWEObservedExample
+func (c *CWE) TypedObservedExamples() []CWEObservedExample {
+	if len(c.ObservedExamples) > 0 {
+		return append([]CWEObservedExample(nil), c.ObservedExamples...)
+	}
+	var examples []CWEObservedExample
+	for _, description := range c.Examples {
+		examples = append(examples, CWEObservedExample{Description: description})
+	}
+	return examples
+}
+
+// CVEReferences 返回条目的已观察到的实例中引用的CVE，见ExtractCVEReferences
+// 只有Examples描述的条目也会在描述中查找CVE ID
+func (c *CWE) CVEReferences() []CVEReference {
+	return ExtractCVEReferences(c.TypedObservedExamples())
+}
+
+// FetchCVEReferences 获取弱点的已观察到的实例中引用的CVE
+//
+// 方法功能:
+// 等同于FetchWeaknessFull后调用CWEWeakness.CVEReferences，只请求CWE API。
+// 需要CVE的描述和评分时，再调用ResolveCVESummaries。
+// 通过SetFieldMask丢弃了FieldObservedExamples时结果为空。
+//
+// 参数:
+// - id: string - CWE ID，支持ParseCWEID接受的所有格式
+//
+// 返回值:
+// - []CVEReference: CVE引用，没有CVE时为空切片
+// - error: ID无效或获取弱点失败时返回错误
+//
+// 使用示例:
+// ```go
+// refs, err := fetcher.FetchCVEReferences("CWE-502")
+//
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// // 最多补充5个CVE的摘要，避免触发NVD的速率限制
+//
+//	if len(refs) > 5 {
+//	    refs = refs[:5]
+//	}
+//
+// err = fetcher.ResolveCVESummaries(refs)
+// ```
+func (f *DataFetcher) FetchCVEReferences(id string) ([]CVEReference, error) {
+	_, weakness, err := f.FetchWeaknessFull(id)
+	if err != nil {
+		return nil, err
+	}
+	return weakness.CVEReferences(), nil
+}
+
+// ResolveCVESummaries 通过NVD获取每个CVE引用的摘要，填充到Summary字段
+//
+// 方法功能:
+// 依次调用NVDClient.GetCVESummary，使用SetNVDClient设置的客户端，未设置时使用NewNVDClient创建的默认客户端。
+// 没有API密钥时NVD限制每30秒5次请求，引用较多时耗时较长，调用方应自行控制数量。
+// NVD中不存在的CVE保持Summary为nil，不视为错误；已有Summary的引用不会重复请求。
+//
+// 参数:
+// - refs: []CVEReference - 要补充摘要的CVE引用，原地修改
+//
+// 返回值:
+// - error: 有CVE请求失败时返回*MultiError，其余引用仍会尝试获取
+func (f *DataFetcher) ResolveCVESummaries(refs []CVEReference) error {
+	if f.nvd == nil {
+		f.nvd = NewNVDClient()
+	}
+
+	failures := &MultiError{}
+	for i := range refs {
+		if refs[i].Summary != nil {
+			continue
+		}
+		summary, err := f.nvd.GetCVESummary(refs[i].CVE)
+		if errors.Is(err, ErrCVENotFound) {
+			continue
+		}
+		if err != nil {
+			failures.Errors = append(failures.Errors, fmt.Errorf("获取%s的摘要失败: %w", refs[i].CVE, err))
+			continue
+		}
+		refs[i].Summary = summary
+	}
+	if len(failures.Errors) > 0 {
+		return failures
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestExtractCVEReferences(t *testing.T) {
+	examples := []CWEObservedExample{
+		{Reference: "CVE-2021-44228", Description: "Log4Shell", Link: "https://www.cve.org/CVERecord?id=CVE-2021-44228"},
+		{Reference: "BID-12345", Description: "No CVE here"},
+		{Reference: "OSVDB-1", Description: "Also tracked as cve-2020-1234 elsewhere"},
+		{Reference: " cve-2021-44228 ", Description: "Duplicate"},
+		{Reference: "BID-2", Link: "https://www.cve.org/CVERecord?id=CVE-2019-0001", Description: "See CVE-2018-0001"},
+	}
+
+	refs := ExtractCVEReferences(examples)
+	want := []CVEReference{
+		{CVE: "CVE-2021-44228", Description: "Log4Shell", Link: "https://www.cve.org/CVERecord?id=CVE-2021-44228", NVDURL: "https://nvd.nist.gov/vuln/detail/CVE-2021-44228"},
+		{CVE: "CVE-2020-1234", Description: "Also tracked as cve-2020-1234 elsewhere", NVDURL: "https://nvd.nist.gov/vuln/detail/CVE-2020-1234"},
+		{CVE: "CVE-2019-0001", Description: "See CVE-2018-0001", Link: "https://www.cve.org/CVERecord?id=CVE-2019-0001", NVDURL: "https://nvd.nist.gov/vuln/detail/CVE-2019-0001"},
+	}
+	if !reflect.DeepEqual(refs, want) {
+		t.Errorf("CVE引用不正确:\n%+v\n期望:\n%+v", refs, want)
+	}
+
+	if refs := ExtractCVEReferences(nil); refs == nil || len(refs) != 0 {
+		t.Errorf("没有实例时应返回空切片，实际为%v", refs)
+	}
+	if url := NVDCVEURL("cve-2021-44228"); url != "https://nvd.nist.gov/vuln/detail/CVE-2021-44228" {
+		t.Errorf("NVDCVEURL = %q", url)
+	}
+	if url := NVDCVEURL("CWE-79"); url != "" {
+		t.Errorf("无效的CVE ID应返回空字符串，实际为%q", url)
+	}
+
+	// 只有描述的条目在描述中查找CVE
+	entry := NewCWE("CWE-79", "XSS")
+	entry.Examples = []string{"CVE-2022-0001 reflected XSS", "no reference"}
+	if refs := entry.CVEReferences(); len(refs) != 1 || refs[0].CVE != "CVE-2022-0001" {
+		t.Errorf("CWE.CVEReferences结果不正确: %+v", refs)
+	}
+}
+
+func TestFetchCVEReferences(t *testing.T) {
+	cweServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"weaknesses": [{"id": "502", "name": "Deserialization", "observed_examples": [
+			{"reference": "CVE-2021-44228", "description": "Log4Shell", "link": "https://www.cve.org/CVERecord?id=CVE-2021-44228"},
+			{"reference": "CVE-2020-0404", "description": "Rejected"},
+			{"reference": "CVE-2020-0002", "description": "Needs key"}]}]}`)
+	}))
+	defer cweServer.Close()
+	nvdServer := setupNVDServer()
+	defer nvdServer.Close()
+
+	fetcher := newCVETestFetcher(cweServer.URL, nvdServer.URL)
+
+	entry, err := fetcher.FetchWeakness("502")
+	if err != nil {
+		t.Fatalf("FetchWeakness失败: %v", err)
+	}
+	if len(entry.ObservedExamples) != 3 || entry.ObservedExamples[0].Reference != "CVE-2021-44228" || len(entry.Examples) != 3 {
+		t.Errorf("获取的弱点应包含已观察到的实例: %+v", entry.ObservedExamples)
+	}
+
+	refs, err := fetcher.FetchCVEReferences("CWE-502")
+	if err != nil {
+		t.Fatalf("FetchCVEReferences失败: %v", err)
+	}
+	if len(refs) != 3 || refs[0].NVDURL != "https://nvd.nist.gov/vuln/detail/CVE-2021-44228" || refs[0].Summary != nil {
+		t.Fatalf("CVE引用不正确: %+v", refs)
+	}
+
+	// CVE-2020-0002没有API密钥时请求失败，CVE-2020-0404不存在
+	err = fetcher.ResolveCVESummaries(refs)
+	var multi *MultiError
+	if !errors.As(err, &multi) || len(multi.Errors) != 1 {
+		t.Errorf("请求失败的CVE应返回MultiError，实际: %v", err)
+	}
+	if refs[0].Summary == nil || refs[0].Summary.ID != "CVE-2021-44228" || refs[1].Summary != nil || refs[2].Summary != nil {
+		t.Errorf("摘要填充不正确: %+v", refs)
+	}
+
+	fetcher.nvd.SetAPIKey("secret")
+	if err := fetcher.ResolveCVESummaries(refs); err != nil || refs[2].Summary == nil {
+		t.Errorf("设置API密钥后应补充剩余的摘要: %v", err)
+	}
+
+	if _, err := fetcher.FetchCVEReferences("abc"); err == nil {
+		t.Error("ID无效时应返回错误")
+	}
+}
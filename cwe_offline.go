@@ -0,0 +1,55 @@
+package cwe
+
+import (
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"fmt"
+)
+
+// offlineBundle 是内置的gzip压缩CWE数据，格式与Registry.WriteExportJSON相同
+// 由cmd/cwegen根据data/cwe-top25-snapshot.json生成，见cwe_known.go中的go:generate指令
+//
+//go:embed data/cwe-offline.json.gz
+var offlineBundle []byte
+
+// NewOfflineRegistry 从内置数据创建注册表，不发送任何网络请求
+//
+// 方法功能:
+// 解压并加载随包发布的CWE快照，还原条目、父子关系和根节点。
+// 内置数据与生成的常量和查找表来自同一份快照，版本见KnownCWEVersion。
+// 适用于禁止访问外网的CI环境，或只需要名称、URL等基本信息的场景；
+// 需要最新数据时仍应通过DataFetcher从API获取。
+// 每次调用都返回新的注册表，调用方可以自由修改而不影响其他调用。
+//
+// 如需内置完整的CWE数据，可以用cwe fetch命令或LoadFromXMLDictionaryFile得到完整快照，
+// 替换data/cwe-top25-snapshot.json后执行go generate重新生成。
+//
+// 返回值:
+// - *Registry: 包含内置数据的注册表
+// - error: 内置数据损坏时返回错误
+//
+// 使用示例:
+// ```go
+// registry, err := cwe.NewOfflineRegistry()
+//
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// entry, _ := registry.GetByID("CWE-79")
+// fmt.Println(entry.Name, entry.URL)
+// ```
+func NewOfflineRegistry() (*Registry, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(offlineBundle))
+	if err != nil {
+		return nil, fmt.Errorf("读取内置CWE数据失败: %w", err)
+	}
+	defer reader.Close()
+
+	registry := NewRegistry()
+	if err := registry.ReadExportJSON(reader); err != nil {
+		return nil, fmt.Errorf("读取内置CWE数据失败: %w", err)
+	}
+	return registry, nil
+}
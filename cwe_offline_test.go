@@ -0,0 +1,44 @@
+package cwe
+
+import "testing"
+
+func TestNewOfflineRegistry(t *testing.T) {
+	registry, err := NewOfflineRegistry()
+	if err != nil {
+		t.Fatalf("加载内置数据失败: %v", err)
+	}
+
+	if registry.Root == nil || registry.Root.ID != "CWE-1000" {
+		t.Errorf("根节点应为CWE-1000，实际为%v", registry.Root)
+	}
+
+	// 内置数据应包含生成的查找表中的视图和Top 25条目
+	for id, name := range knownViewNames {
+		entry, err := registry.GetByID(string(id))
+		if err != nil {
+			t.Errorf("内置数据缺少视图%s: %v", id, err)
+			continue
+		}
+		if entry.Name != name {
+			t.Errorf("%s的名称应为%q，实际为%q", id, name, entry.Name)
+		}
+	}
+	for _, year := range Top25Years() {
+		for _, id := range Top25(year) {
+			if _, err := registry.GetByID(id.String()); err != nil {
+				t.Errorf("内置数据缺少%d年Top 25条目%s: %v", year, id, err)
+			}
+		}
+	}
+
+	// 每次调用返回独立的注册表
+	entry, _ := registry.GetByID("CWE-79")
+	entry.Name = "modified"
+	other, err := NewOfflineRegistry()
+	if err != nil {
+		t.Fatalf("加载内置数据失败: %v", err)
+	}
+	if again, _ := other.GetByID("CWE-79"); again.Name == "modified" {
+		t.Error("修改一个注册表不应影响其他调用的结果")
+	}
+}
@@ -0,0 +1,319 @@
+package cwe
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PolicyEffect 是策略规则匹配后的效果
+type PolicyEffect string
+
+const (
+	// PolicyDeny 匹配的发现违反策略
+	PolicyDeny PolicyEffect = "deny"
+
+	// PolicyAllow 匹配的发现被明确允许，通常用于在拒绝的类别中豁免个别弱点
+	PolicyAllow PolicyEffect = "allow"
+)
+
+// PolicyRule 是策略中的一条规则
+type PolicyRule struct {
+	// Effect 规则的效果
+	Effect PolicyEffect `json:"effect"`
+
+	// ID 规则针对的CWE ID，已规范化
+	ID string `json:"id"`
+
+	// IncludeDescendants 为true时规则同样作用于ID在评估所用注册表中的所有后代
+	IncludeDescendants bool `json:"include_descendants,omitempty"`
+
+	// Justification 规则的理由，允许规则必须填写
+	Justification string `json:"justification,omitempty"`
+}
+
+// Policy 是一组允许/拒绝规则，用于判断扫描发现的CWE是否符合团队的安全策略
+//
+// 规则只描述CWE ID及是否包含后代，层次结构来自评估时传入的注册表，
+// 因此同一策略可以在不同视图(如研究视图CWE-1000)下评估。
+// 一个发现匹配多条规则时，在层次结构中离发现最近的规则生效，距离相同时拒绝优先。
+//
+// Policy是并发安全的，可以在多个goroutine中使用
+type Policy struct {
+	mutex sync.RWMutex
+
+	// name 策略名称
+	name string
+
+	// rules 按添加顺序排列的规则
+	rules []PolicyRule
+}
+
+// PolicyMatch 是一个发现与生效规则的匹配结果
+type PolicyMatch struct {
+	// Finding 发现的CWE ID，已规范化
+	Finding string `json:"finding"`
+
+	// Rule 生效的规则
+	Rule PolicyRule `json:"rule"`
+
+	// Path 从规则的CWE到发现的路径，规则直接针对发现时只包含发现本身
+	Path []string `json:"path"`
+}
+
+// PolicyReport 是一组发现的策略评估结果
+type PolicyReport struct {
+	// Policy 策略名称
+	Policy string `json:"policy,omitempty"`
+
+	// Violations 被拒绝规则匹配的发现，按CWE编号排序
+	Violations []PolicyMatch `json:"violations"`
+
+	// Allowed 被允许规则匹配的发现，按CWE编号排序
+	Allowed []PolicyMatch `json:"allowed"`
+
+	// Unmatched 没有匹配任何规则的发现，已排序
+	Unmatched []string `json:"unmatched"`
+
+	// Invalid 无法解析的发现ID，保持原样
+	Invalid []string `json:"invalid,omitempty"`
+}
+
+// Passed 判断是否没有任何违规
+func (r *PolicyReport) Passed() bool {
+	return len(r.Violations) == 0
+}
+
+// NewPolicy 创建一个空策略
+func NewPolicy(name string) *Policy {
+	return &Policy{
+		name:  strings.TrimSpace(name),
+		rules: make([]PolicyRule, 0),
+	}
+}
+
+// Name 返回策略名称
+func (p *Policy) Name() string {
+	return p.name
+}
+
+// Rules 返回策略中所有规则的副本，按添加顺序排列
+func (p *Policy) Rules() []PolicyRule {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return append([]PolicyRule(nil), p.rules...)
+}
+
+// Deny 添加一条拒绝规则
+//
+// 参数:
+// - id: string - CWE ID，支持ParseCWEID接受的所有格式
+// - includeDescendants: bool - 是否同时拒绝所有后代
+//
+// 返回值:
+// - error: CWE ID无法解析时返回错误
+//
+// 使用示例:
+// ```go
+// policy := cwe.NewPolicy("web-baseline")
+// policy.Deny("CWE-89", true)
+// policy.Allow("CWE-327", false, "仅用于兼容旧版客户端的校验和，已由安全团队评审")
+//
+// report := policy.Evaluate(registry, []string{"CWE-89", "CWE-564", "CWE-327"})
+//
+//	for _, violation := range report.Violations {
+//	    fmt.Println(violation.Finding, strings.Join(violation.Path, cwe.DefaultPathSeparator))
+//	}
+//
+// ```
+func (p *Policy) Deny(id string, includeDescendants bool) error {
+	return p.AddRule(PolicyRule{Effect: PolicyDeny, ID: id, IncludeDescendants: includeDescendants})
+}
+
+// Allow 添加一条允许规则，justification说明豁免的理由，不能为空
+func (p *Policy) Allow(id string, includeDescendants bool, justification string) error {
+	return p.AddRule(PolicyRule{Effect: PolicyAllow, ID: id, IncludeDescendants: includeDescendants, Justification: justification})
+}
+
+// AddRule 添加一条规则
+//
+// 参数:
+// - rule: PolicyRule - 规则，ID会被规范化
+//
+// 返回值:
+// - error: 效果不是PolicyDeny或PolicyAllow、CWE ID无法解析或允许规则缺少理由时返回错误；出错时不会修改策略
+func (p *Policy) AddRule(rule PolicyRule) error {
+	if rule.Effect != PolicyDeny && rule.Effect != PolicyAllow {
+		return fmt.Errorf("规则%s的效果%q无效，应为deny或allow", rule.ID, rule.Effect)
+	}
+
+	id, err := ParseCWEID(rule.ID)
+	if err != nil {
+		return fmt.Errorf("规则的CWE ID %q无效: %w", rule.ID, err)
+	}
+	rule.ID = id
+
+	rule.Justification = strings.TrimSpace(rule.Justification)
+	if rule.Effect == PolicyAllow && rule.Justification == "" {
+		return fmt.Errorf("允许%s的规则必须填写理由", rule.ID)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.rules = append(p.rules, rule)
+	return nil
+}
+
+// LoadPolicyJSON 从JSON数据创建策略
+//
+// 参数:
+// - data: []byte - JSON数据
+//
+// 返回值:
+// - *Policy: 策略
+// - error: 解析失败或任一规则无效时返回错误
+//
+// 数据样例:
+// ```json
+//
+//	{
+//	  "name": "web-baseline",
+//	  "rules": [
+//	    {"effect": "deny", "id": "CWE-89", "include_descendants": true},
+//	    {"effect": "allow", "id": "CWE-327", "justification": "仅用于校验和"}
+//	  ]
+//	}
+//
+// ```
+func LoadPolicyJSON(data []byte) (*Policy, error) {
+	var raw struct {
+		Name  string       `json:"name"`
+		Rules []PolicyRule `json:"rules"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	policy := NewPolicy(raw.Name)
+	for i, rule := range raw.Rules {
+		if err := policy.AddRule(rule); err != nil {
+			return nil, fmt.Errorf("第%d条规则无效: %w", i+1, err)
+		}
+	}
+	return policy, nil
+}
+
+// MarshalJSON 以LoadPolicyJSON接受的格式序列化策略
+func (p *Policy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Name  string       `json:"name,omitempty"`
+		Rules []PolicyRule `json:"rules"`
+	}{p.name, p.Rules()})
+}
+
+// Evaluate 按策略评估一组发现
+//
+// 方法功能:
+// 对每个不重复的发现查找匹配的规则：规则ID与发现相同时直接匹配，
+// 规则包含后代且规则ID是发现在registry中的祖先时通过层次结构匹配。
+// 多条规则匹配时离发现最近的规则生效，因此可以拒绝整个类别再豁免其中个别弱点；
+// 距离相同时拒绝规则优先，其次是先添加的规则。
+// 每个匹配都附带从规则的CWE到发现的路径，作为违规的证据。
+//
+// 参数:
+// - registry: *Registry - 提供层次结构的注册表，为nil时只有规则ID与发现相同才会匹配
+// - findings: []string - 发现的CWE ID，如扫描结果中的CWE，支持ParseCWEID接受的所有格式
+//
+// 返回值:
+// - *PolicyReport: 评估结果，可以直接序列化为JSON
+func (p *Policy) Evaluate(registry *Registry, findings []string) *PolicyReport {
+	rules := p.Rules()
+
+	report := &PolicyReport{
+		Policy:     p.name,
+		Violations: make([]PolicyMatch, 0),
+		Allowed:    make([]PolicyMatch, 0),
+		Unmatched:  make([]string, 0),
+	}
+
+	ids := make([]string, 0, len(findings))
+	seen := make(map[string]bool, len(findings))
+	for _, finding := range findings {
+		id, err := ParseCWEID(finding)
+		if err != nil {
+			report.Invalid = append(report.Invalid, finding)
+			continue
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return compareCWEIDs(ids[i], ids[j]) < 0
+	})
+
+	for _, id := range ids {
+		match, matched := matchPolicyRules(registry, rules, id)
+		switch {
+		case !matched:
+			report.Unmatched = append(report.Unmatched, id)
+		case match.Rule.Effect == PolicyDeny:
+			report.Violations = append(report.Violations, match)
+		default:
+			report.Allowed = append(report.Allowed, match)
+		}
+	}
+	return report
+}
+
+// matchPolicyRules 返回对发现id生效的规则及从规则到发现的路径
+func matchPolicyRules(registry *Registry, rules []PolicyRule, id string) (PolicyMatch, bool) {
+	// 发现及其所有祖先到发现的最短路径(从发现向上)
+	chains := map[string][]string{id: {id}}
+	if registry != nil {
+		registry.mutex.RLock()
+		if entry, exists := registry.Entries[id]; exists {
+			ancestors := registry.ancestorDistances(entry)
+			for i, ancestor := range ancestors {
+				chain := make([]string, 0, ancestor.distance+1)
+				for _, node := range ancestorChain(ancestors, i) {
+					chain = append(chain, node.ID)
+				}
+				chains[ancestor.entry.ID] = chain
+			}
+		}
+		registry.mutex.RUnlock()
+	}
+
+	best := -1
+	var bestChain []string
+	for i, rule := range rules {
+		chain, exists := chains[rule.ID]
+		if !exists || (len(chain) > 1 && !rule.IncludeDescendants) {
+			continue
+		}
+		if best >= 0 {
+			if len(chain) > len(bestChain) {
+				continue
+			}
+			if len(chain) == len(bestChain) && (rules[best].Effect == PolicyDeny || rule.Effect != PolicyDeny) {
+				continue
+			}
+		}
+		best, bestChain = i, chain
+	}
+	if best < 0 {
+		return PolicyMatch{}, false
+	}
+
+	path := make([]string, len(bestChain))
+	for i, id := range bestChain {
+		path[len(bestChain)-1-i] = id
+	}
+	return PolicyMatch{Finding: id, Rule: rules[best], Path: path}, true
+}
@@ -0,0 +1,126 @@
+package cwe
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPolicyEvaluate(t *testing.T) {
+	registry := newQueryTestRegistry()
+
+	policy := NewPolicy("web-baseline")
+	if err := policy.Deny("CWE-20", true); err != nil {
+		t.Fatalf("Deny失败: %v", err)
+	}
+	if err := policy.Allow("79", false, "模板引擎默认转义"); err != nil {
+		t.Fatalf("Allow失败: %v", err)
+	}
+	policy.Deny("CWE-287", false)
+
+	report := policy.Evaluate(registry, []string{"CWE-89", "CWE-79", "CWE-306", "CWE-287", "CWE-89", "CWE-99999", "xyz"})
+
+	wantViolations := []PolicyMatch{
+		{Finding: "CWE-89", Rule: PolicyRule{Effect: PolicyDeny, ID: "CWE-20", IncludeDescendants: true}, Path: []string{"CWE-20", "CWE-89"}},
+		{Finding: "CWE-287", Rule: PolicyRule{Effect: PolicyDeny, ID: "CWE-287"}, Path: []string{"CWE-287"}},
+	}
+	if !reflect.DeepEqual(report.Violations, wantViolations) {
+		t.Errorf("违规不正确:\n%+v\n期望:\n%+v", report.Violations, wantViolations)
+	}
+
+	wantAllowed := []PolicyMatch{
+		{Finding: "CWE-79", Rule: PolicyRule{Effect: PolicyAllow, ID: "CWE-79", Justification: "模板引擎默认转义"}, Path: []string{"CWE-79"}},
+	}
+	if !reflect.DeepEqual(report.Allowed, wantAllowed) {
+		t.Errorf("允许的发现不正确: %+v", report.Allowed)
+	}
+
+	// CWE-287的规则不包含后代，CWE-306不受影响
+	if !reflect.DeepEqual(report.Unmatched, []string{"CWE-306", "CWE-99999"}) {
+		t.Errorf("未匹配的发现不正确: %v", report.Unmatched)
+	}
+	if !reflect.DeepEqual(report.Invalid, []string{"xyz"}) {
+		t.Errorf("无效的发现不正确: %v", report.Invalid)
+	}
+	if report.Passed() {
+		t.Error("存在违规时Passed应返回false")
+	}
+}
+
+func TestPolicyRulePrecedence(t *testing.T) {
+	registry := newQueryTestRegistry()
+
+	// 距离相同时拒绝优先
+	policy := NewPolicy("")
+	policy.Allow("CWE-89", false, "例外")
+	policy.Deny("CWE-89", false)
+	if report := policy.Evaluate(registry, []string{"CWE-89"}); len(report.Violations) != 1 {
+		t.Errorf("距离相同时应拒绝: %+v", report)
+	}
+
+	// 允许整个分支时更近的拒绝规则仍然生效
+	policy = NewPolicy("")
+	policy.Allow("CWE-1000", true, "默认允许")
+	policy.Deny("CWE-20", true)
+	report := policy.Evaluate(registry, []string{"CWE-79", "CWE-306"})
+	if len(report.Violations) != 1 || report.Violations[0].Finding != "CWE-79" {
+		t.Errorf("更近的规则应生效: %+v", report.Violations)
+	}
+	if len(report.Allowed) != 1 || !reflect.DeepEqual(report.Allowed[0].Path, []string{"CWE-1000", "CWE-287", "CWE-306"}) {
+		t.Errorf("允许的路径不正确: %+v", report.Allowed)
+	}
+
+	// 没有注册表时只有ID相同才匹配
+	report = policy.Evaluate(nil, []string{"CWE-20", "CWE-79"})
+	if len(report.Violations) != 1 || !reflect.DeepEqual(report.Unmatched, []string{"CWE-79"}) {
+		t.Errorf("没有注册表时的结果不正确: %+v", report)
+	}
+}
+
+func TestPolicyRuleValidation(t *testing.T) {
+	policy := NewPolicy("p")
+	if err := policy.Deny("abc", false); err == nil {
+		t.Error("无效的CWE ID应返回错误")
+	}
+	if err := policy.Allow("CWE-327", false, " "); err == nil {
+		t.Error("允许规则缺少理由时应返回错误")
+	}
+	if err := policy.AddRule(PolicyRule{Effect: "ignore", ID: "CWE-79"}); err == nil {
+		t.Error("无效的效果应返回错误")
+	}
+	if len(policy.Rules()) != 0 {
+		t.Errorf("出错时不应添加规则: %v", policy.Rules())
+	}
+}
+
+func TestLoadPolicyJSON(t *testing.T) {
+	policy, err := LoadPolicyJSON([]byte(`{
+		"name": "web-baseline",
+		"rules": [
+			{"effect": "deny", "id": "89", "include_descendants": true},
+			{"effect": "allow", "id": "CWE-327", "justification": "仅用于校验和"}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("LoadPolicyJSON失败: %v", err)
+	}
+	if policy.Name() != "web-baseline" || len(policy.Rules()) != 2 || policy.Rules()[0].ID != "CWE-89" {
+		t.Errorf("加载的策略不正确: %s %+v", policy.Name(), policy.Rules())
+	}
+
+	data, err := json.Marshal(policy)
+	if err != nil {
+		t.Fatalf("序列化失败: %v", err)
+	}
+	restored, err := LoadPolicyJSON(data)
+	if err != nil || !reflect.DeepEqual(restored.Rules(), policy.Rules()) {
+		t.Errorf("JSON往返后不一致: %v\n%s", err, data)
+	}
+
+	if _, err := LoadPolicyJSON([]byte(`{"rules": [{"effect": "allow", "id": "CWE-79"}]}`)); err == nil {
+		t.Error("无效的规则应返回错误")
+	}
+	if _, err := LoadPolicyJSON([]byte(`{`)); err == nil {
+		t.Error("无效的JSON应返回错误")
+	}
+}
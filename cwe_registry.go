@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
 )
 
 // Registry 表示CWE注册表，用于存储和管理CWE条目
@@ -17,6 +18,41 @@ type Registry struct {
 	// Root 表示CWE层次结构的根节点
 	// 在调用BuildHierarchy后会设置此字段
 	Root *CWE // 根节点
+
+	// severityOverlay 组织自定义的严重性覆盖层，可为nil
+	// 通过SetSeverityOverlay设置，EffectiveSeverity会优先使用其中的值
+	severityOverlay *SeverityOverlay
+
+	// warnings 构建注册表时产生的非致命问题，通过Warnings获取
+	warnings []error
+
+	// hierarchyMode 通过AddChild和BuildHierarchy建立父子关系时的模式，通过SetHierarchyMode设置
+	hierarchyMode HierarchyMode
+
+	// parents HierarchyMultiParent模式下记录的子节点ID到所有父节点ID的映射
+	parents map[string][]string
+
+	// viewChildren 按视图记录的父子关系: 视图ID到父节点ID再到子节点ID列表的映射
+	viewChildren map[string]map[string][]string
+
+	// version 注册表中条目所属的CWE内容版本，为空表示尚未确定，见SetVersion
+	version string
+
+	// allowMixedVersions 为true时允许注册不同版本的条目，见AllowMixedVersions
+	allowMixedVersions bool
+
+	// synonyms Search扩展关键词使用的同义词表，为nil时使用DefaultSynonyms
+	synonyms *SynonymTable
+
+	// annotations 条目ID到用户注解的映射，只保存有标签或备注的条目，见SetTag和AddNote
+	annotations map[string]Annotation
+
+	// changeLog UpdateFrom记录的每次更新的条目变化，按更新顺序排列，见ChangesSince
+	changeLog []ChangeSet
+
+	// mutex 保护Register、GetByID和Snapshot对Entries的并发访问
+	// 直接读写Entries字段的代码不受其保护
+	mutex sync.RWMutex
 }
 
 // NewRegistry 创建新的CWE注册表
@@ -50,6 +86,22 @@ func NewRegistry() *Registry {
 	}
 }
 
+// Warnings 返回构建注册表时产生的非致命问题
+//
+// 例如BuildCWETreeWithView在视图没有子节点时会记录一条包装了ErrEmptyView的警告。
+// 返回值是副本，没有警告时返回nil。
+func (r *Registry) Warnings() []error {
+	if len(r.warnings) == 0 {
+		return nil
+	}
+	return append([]error(nil), r.warnings...)
+}
+
+// addWarning 记录一条警告
+func (r *Registry) addWarning(err error) {
+	r.warnings = append(r.warnings, err)
+}
+
 // Register 注册一个CWE到注册表
 //
 // 方法功能:
@@ -66,6 +118,7 @@ func NewRegistry() *Registry {
 // - 如CWE为nil: 返回"无法注册空的CWE"
 // - 如CWE的ID为空: 返回"CWE必须有ID"
 // - 如注册表中已存在相同ID的CWE: 返回"ID为X的CWE已存在"
+// - 如CWE的Version与注册表的版本不同: 返回包装了ErrVersionMismatch的错误，见AllowMixedVersions
 //
 // 使用示例:
 // ```go
@@ -99,11 +152,18 @@ func (r *Registry) Register(cwe *CWE) error {
 		return errors.New("CWE必须有ID")
 	}
 
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
 	// 检查是否已存在
 	if _, exists := r.Entries[cwe.ID]; exists {
 		return fmt.Errorf("ID为%s的CWE已存在", cwe.ID)
 	}
 
+	if err := r.checkVersion(cwe); err != nil {
+		return err
+	}
+
 	r.Entries[cwe.ID] = cwe
 	return nil
 }
@@ -122,7 +182,7 @@ func (r *Registry) Register(cwe *CWE) error {
 // - error: 如未找到匹配的CWE则返回错误，否则返回nil
 //
 // 错误处理:
-// - 如注册表中不存在指定ID的CWE: 返回"未找到ID为X的CWE"
+// - 如注册表中不存在指定ID的CWE: 返回"未找到ID为X的CWE"，可通过errors.Is(err, ErrNotFound)判断
 //
 // 使用示例:
 // ```go
@@ -154,10 +214,30 @@ func (r *Registry) Register(cwe *CWE) error {
 // - Register(): 向注册表添加CWE
 // - BuildHierarchy(): 构建CWE层次结构
 func (r *Registry) GetByID(id string) (*CWE, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
 	if cwe, exists := r.Entries[id]; exists {
 		return cwe, nil
 	}
-	return nil, fmt.Errorf("未找到ID为%s的CWE", id)
+	return nil, &notFoundError{id: id}
+}
+
+// ErrNotFound 表示注册表中不存在请求的CWE
+// GetByID返回的错误可通过errors.Is(err, ErrNotFound)判断
+var ErrNotFound = errors.New("未找到CWE")
+
+// notFoundError 是GetByID在条目不存在时返回的错误
+type notFoundError struct {
+	id string
+}
+
+func (e *notFoundError) Error() string {
+	return fmt.Sprintf("未找到ID为%s的CWE", e.id)
+}
+
+func (e *notFoundError) Unwrap() error {
+	return ErrNotFound
 }
 
 // BuildHierarchy 根据父子关系构建CWE层次结构
@@ -165,6 +245,7 @@ func (r *Registry) GetByID(id string) (*CWE, error) {
 // 方法功能:
 // 根据提供的父子关系映射，构建注册表中CWE的层次结构。
 // 该方法会为每个父节点添加相应的子节点，从而建立完整的CWE层次树。
+// 父节点按CWE编号顺序处理，子节点处理方式由SetHierarchyMode设置的模式决定。
 // 执行此方法前，相关的CWE必须已通过Register方法添加到注册表中。
 //
 // 参数:
@@ -176,6 +257,7 @@ func (r *Registry) GetByID(id string) (*CWE, error) {
 // 错误处理:
 // - 如父节点未注册: 返回"父节点X未注册"
 // - 如子节点未注册: 返回"子节点X未注册"
+// - 严格模式下子节点已有其他父节点: 返回包装了ErrMultipleParents的错误，之前的关系已经建立
 //
 // 使用示例:
 // ```go
@@ -224,6 +306,9 @@ func (r *Registry) GetByID(id string) (*CWE, error) {
 // - Register(): 向注册表添加CWE
 // - GetByID(): 从注册表查询CWE
 func (r *Registry) BuildHierarchy(parentChildMap map[string][]string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
 	// 先确保所有引用的CWE都已注册
 	for parentID, childIDs := range parentChildMap {
 		if _, exists := r.Entries[parentID]; !exists {
@@ -238,12 +323,13 @@ func (r *Registry) BuildHierarchy(parentChildMap map[string][]string) error {
 	}
 
 	// 构建层次结构
-	for parentID, childIDs := range parentChildMap {
+	for _, parentID := range sortedParentIDs(parentChildMap) {
 		parent := r.Entries[parentID]
 
-		for _, childID := range childIDs {
-			child := r.Entries[childID]
-			parent.AddChild(child)
+		for _, childID := range parentChildMap[parentID] {
+			if err := r.link(parent, r.Entries[childID]); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -395,6 +481,7 @@ func (r *Registry) ImportFromJSON(data []byte) error {
 
 	// 清空当前注册表
 	r.Entries = make(map[string]*CWE)
+	r.parents = nil
 
 	// 导入CWE条目
 	for id, cwe := range entriesMap {
@@ -0,0 +1,258 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoCommonAncestor 表示两个条目在注册表的层次结构中没有共同祖先
+var ErrNoCommonAncestor = errors.New("条目没有共同祖先")
+
+// GetAncestors 返回条目在本地注册表中的所有祖先节点
+//
+// 方法功能:
+// 与APIClient.GetAncestors相同的查询，但完全基于内存中的树，不发送任何请求。
+// 从条目出发沿父节点广度优先遍历，HierarchyMultiParent模式下会经过所有父节点(见Parents)，
+// 每个祖先只出现一次，树中的环也不会导致死循环。
+//
+// 参数:
+// - id: string - 条目ID，支持ParseCWEID接受的所有格式
+//
+// 返回值:
+// - []*CWE: 祖先节点，从近到远排列，不包括条目本身；根节点返回空切片
+// - error: 条目不存在时返回包装了ErrNotFound的错误
+//
+// 使用示例:
+// ```go
+// ancestors, err := registry.GetAncestors("CWE-79")
+//
+//	for _, ancestor := range ancestors {
+//	    fmt.Println(ancestor.ID, ancestor.Name) // CWE-20、CWE-1000...
+//	}
+//
+// ```
+func (r *Registry) GetAncestors(id string) ([]*CWE, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	entry, exists := r.Entries[normalizeEntryID(id)]
+	if !exists {
+		return nil, &notFoundError{id: id}
+	}
+
+	ancestors := make([]*CWE, 0)
+	for _, ancestor := range r.ancestorDistances(entry) {
+		if ancestor.entry != entry {
+			ancestors = append(ancestors, ancestor.entry)
+		}
+	}
+	return ancestors, nil
+}
+
+// GetDescendants 返回条目在本地注册表中的后代节点
+//
+// 方法功能:
+// 与APIClient.GetDescendants相同的查询，但完全基于内存中的树。
+// 沿Children广度优先遍历，每个后代只出现一次。
+//
+// 参数:
+// - id: string - 条目ID，支持ParseCWEID接受的所有格式
+// - maxDepth: int - 最大深度，1表示只返回子节点，<=0表示不限制
+//
+// 返回值:
+// - []*CWE: 后代节点，按广度优先顺序排列，不包括条目本身
+// - error: 条目不存在时返回包装了ErrNotFound的错误
+func (r *Registry) GetDescendants(id string, maxDepth int) ([]*CWE, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	entry, exists := r.Entries[normalizeEntryID(id)]
+	if !exists {
+		return nil, &notFoundError{id: id}
+	}
+
+	descendants := make([]*CWE, 0)
+	visited := map[*CWE]bool{entry: true}
+	level := []*CWE{entry}
+	for depth := 1; len(level) > 0 && (maxDepth <= 0 || depth <= maxDepth); depth++ {
+		var next []*CWE
+		for _, node := range level {
+			for _, child := range node.Children {
+				if visited[child] {
+					continue
+				}
+				visited[child] = true
+				descendants = append(descendants, child)
+				next = append(next, child)
+			}
+		}
+		level = next
+	}
+	return descendants, nil
+}
+
+// GetSiblings 返回与条目有共同父节点的其他条目
+//
+// HierarchyMultiParent模式下包括所有父节点的子节点。根节点和没有父节点的条目返回空切片。
+//
+// 参数:
+// - id: string - 条目ID，支持ParseCWEID接受的所有格式
+//
+// 返回值:
+// - []*CWE: 兄弟节点，按CWE编号排序，不包括条目本身
+// - error: 条目不存在时返回包装了ErrNotFound的错误
+func (r *Registry) GetSiblings(id string) ([]*CWE, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	entry, exists := r.Entries[normalizeEntryID(id)]
+	if !exists {
+		return nil, &notFoundError{id: id}
+	}
+
+	siblings := make([]*CWE, 0)
+	seen := map[*CWE]bool{entry: true}
+	for _, parent := range r.parentsOf(entry) {
+		for _, child := range parent.Children {
+			if !seen[child] {
+				seen[child] = true
+				siblings = append(siblings, child)
+			}
+		}
+	}
+	sortByCWEID(siblings)
+	return siblings, nil
+}
+
+// LowestCommonAncestor 返回两个条目最近的共同祖先
+//
+// 方法功能:
+// 一个条目是另一个条目的祖先时返回该条目本身，a与b相同时返回a。
+// 多父节点的层次结构中可能有多个共同祖先，此时返回到两个条目的距离之和最小的一个，
+// 距离相同时返回CWE编号较小的一个。
+//
+// 参数:
+// - a: string - 第一个条目ID，支持ParseCWEID接受的所有格式
+// - b: string - 第二个条目ID
+//
+// 返回值:
+// - *CWE: 最近的共同祖先
+// - error: 条目不存在时返回包装了ErrNotFound的错误，没有共同祖先时返回包装了ErrNoCommonAncestor的错误
+//
+// 使用示例:
+// ```go
+// // CWE-79和CWE-89都属于输入验证
+// ancestor, err := registry.LowestCommonAncestor("CWE-79", "CWE-89")
+//
+//	if err == nil {
+//	    fmt.Println(ancestor.ID) // CWE-20
+//	}
+//
+// ```
+func (r *Registry) LowestCommonAncestor(a, b string) (*CWE, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	first, exists := r.Entries[normalizeEntryID(a)]
+	if !exists {
+		return nil, &notFoundError{id: a}
+	}
+	second, exists := r.Entries[normalizeEntryID(b)]
+	if !exists {
+		return nil, &notFoundError{id: b}
+	}
+
+	upFirst, _ := r.commonAncestorChains(first, second)
+	if upFirst == nil {
+		return nil, fmt.Errorf("%w: %s和%s", ErrNoCommonAncestor, first.ID, second.ID)
+	}
+	return upFirst[len(upFirst)-1], nil
+}
+
+// commonAncestorChains 返回从first和second分别向上到最近共同祖先的节点链，两条链都以共同祖先结尾
+// 共同祖先的选择规则见LowestCommonAncestor，没有共同祖先时返回nil，调用方需持有读锁
+func (r *Registry) commonAncestorChains(first, second *CWE) ([]*CWE, []*CWE) {
+	firstAncestors := r.ancestorDistances(first)
+	indexes := make(map[*CWE]int, len(firstAncestors))
+	for i, ancestor := range firstAncestors {
+		indexes[ancestor.entry] = i
+	}
+
+	secondAncestors := r.ancestorDistances(second)
+	bestFirst, bestSecond := -1, -1
+	for j, ancestor := range secondAncestors {
+		i, common := indexes[ancestor.entry]
+		if !common {
+			continue
+		}
+		if bestFirst < 0 {
+			bestFirst, bestSecond = i, j
+			continue
+		}
+		distance := firstAncestors[i].distance + ancestor.distance
+		bestDistance := firstAncestors[bestFirst].distance + secondAncestors[bestSecond].distance
+		if distance < bestDistance ||
+			(distance == bestDistance && compareCWEIDs(ancestor.entry.ID, secondAncestors[bestSecond].entry.ID) < 0) {
+			bestFirst, bestSecond = i, j
+		}
+	}
+	if bestFirst < 0 {
+		return nil, nil
+	}
+	return ancestorChain(firstAncestors, bestFirst), ancestorChain(secondAncestors, bestSecond)
+}
+
+// ancestorDistance 是条目的一个祖先及其到条目的最短距离
+type ancestorDistance struct {
+	entry    *CWE
+	distance int
+
+	// via 最短路径上的前一个节点(该祖先的子节点)在结果中的下标，条目本身为-1
+	via int
+}
+
+// ancestorDistances 返回条目本身(距离为0)及其所有祖先，按距离从近到远排列，调用方需持有读锁
+func (r *Registry) ancestorDistances(entry *CWE) []ancestorDistance {
+	result := []ancestorDistance{{entry, 0, -1}}
+	visited := map[*CWE]bool{entry: true}
+	for i := 0; i < len(result); i++ {
+		current := result[i]
+		for _, parent := range r.parentsOf(current.entry) {
+			if !visited[parent] {
+				visited[parent] = true
+				result = append(result, ancestorDistance{parent, current.distance + 1, i})
+			}
+		}
+	}
+	return result
+}
+
+// ancestorChain 返回从条目本身沿最短路径到ancestors[index]的节点链
+func ancestorChain(ancestors []ancestorDistance, index int) []*CWE {
+	chain := make([]*CWE, ancestors[index].distance+1)
+	for i := len(chain) - 1; index >= 0; i-- {
+		chain[i] = ancestors[index].entry
+		index = ancestors[index].via
+	}
+	return chain
+}
+
+// GetAncestors 返回条目的所有祖先节点，见Registry.GetAncestors
+func (s *FrozenRegistry) GetAncestors(id string) ([]*CWE, error) {
+	return s.registry.GetAncestors(id)
+}
+
+// GetDescendants 返回条目的后代节点，见Registry.GetDescendants
+func (s *FrozenRegistry) GetDescendants(id string, maxDepth int) ([]*CWE, error) {
+	return s.registry.GetDescendants(id, maxDepth)
+}
+
+// GetSiblings 返回条目的兄弟节点，见Registry.GetSiblings
+func (s *FrozenRegistry) GetSiblings(id string) ([]*CWE, error) {
+	return s.registry.GetSiblings(id)
+}
+
+// LowestCommonAncestor 返回两个条目最近的共同祖先，见Registry.LowestCommonAncestor
+func (s *FrozenRegistry) LowestCommonAncestor(a, b string) (*CWE, error) {
+	return s.registry.LowestCommonAncestor(a, b)
+}
@@ -0,0 +1,107 @@
+package cwe
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRegistryGetAncestors(t *testing.T) {
+	registry := newQueryTestRegistry()
+
+	ancestors, err := registry.GetAncestors("79")
+	if err != nil {
+		t.Fatalf("GetAncestors失败: %v", err)
+	}
+	if got := cweIDsOf(ancestors); !reflect.DeepEqual(got, []string{"CWE-20", "CWE-1000"}) {
+		t.Errorf("祖先节点应从近到远排列，实际为%v", got)
+	}
+	if ancestors, _ := registry.GetAncestors("CWE-1000"); len(ancestors) != 0 {
+		t.Errorf("根节点不应有祖先，实际为%v", cweIDsOf(ancestors))
+	}
+	if _, err := registry.GetAncestors("CWE-99999"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("条目不存在时应返回ErrNotFound，实际为%v", err)
+	}
+}
+
+func TestRegistryGetDescendants(t *testing.T) {
+	registry := newQueryTestRegistry()
+
+	tests := []struct {
+		id       string
+		maxDepth int
+		want     []string
+	}{
+		{"CWE-1000", 0, []string{"CWE-20", "CWE-287", "CWE-79", "CWE-89", "CWE-306"}},
+		{"CWE-1000", 1, []string{"CWE-20", "CWE-287"}},
+		{"CWE-20", -1, []string{"CWE-79", "CWE-89"}},
+		{"CWE-79", 0, []string{}},
+	}
+	for _, tt := range tests {
+		descendants, err := registry.GetDescendants(tt.id, tt.maxDepth)
+		if err != nil {
+			t.Fatalf("GetDescendants失败: %v", err)
+		}
+		if got := cweIDsOf(descendants); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GetDescendants(%s, %d) = %v, 期望 %v", tt.id, tt.maxDepth, got, tt.want)
+		}
+	}
+}
+
+func TestRegistryGetSiblings(t *testing.T) {
+	registry := newQueryTestRegistry()
+
+	if siblings, _ := registry.GetSiblings("CWE-89"); !reflect.DeepEqual(cweIDsOf(siblings), []string{"CWE-79"}) {
+		t.Errorf("兄弟节点不正确: %v", cweIDsOf(siblings))
+	}
+	if siblings, _ := registry.GetSiblings("CWE-1000"); len(siblings) != 0 {
+		t.Errorf("根节点不应有兄弟节点: %v", cweIDsOf(siblings))
+	}
+
+	// 多父节点时包括所有父节点的子节点
+	registry.SetHierarchyMode(HierarchyMultiParent)
+	registry.AddChild("CWE-287", "CWE-89")
+	if siblings, _ := registry.GetSiblings("CWE-89"); !reflect.DeepEqual(cweIDsOf(siblings), []string{"CWE-79", "CWE-306"}) {
+		t.Errorf("多父节点的兄弟节点不正确: %v", cweIDsOf(siblings))
+	}
+}
+
+func TestRegistryLowestCommonAncestor(t *testing.T) {
+	registry := newQueryTestRegistry()
+
+	tests := []struct {
+		a, b, want string
+	}{
+		{"CWE-79", "CWE-89", "CWE-20"},
+		{"CWE-79", "CWE-306", "CWE-1000"},
+		{"CWE-20", "CWE-89", "CWE-20"},
+		{"CWE-79", "79", "CWE-79"},
+	}
+	for _, tt := range tests {
+		ancestor, err := registry.LowestCommonAncestor(tt.a, tt.b)
+		if err != nil {
+			t.Fatalf("LowestCommonAncestor(%s, %s)失败: %v", tt.a, tt.b, err)
+		}
+		if ancestor.ID != tt.want {
+			t.Errorf("LowestCommonAncestor(%s, %s) = %s, 期望 %s", tt.a, tt.b, ancestor.ID, tt.want)
+		}
+	}
+
+	// 多父节点时选择距离之和最小的共同祖先
+	registry.SetHierarchyMode(HierarchyMultiParent)
+	registry.AddChild("CWE-287", "CWE-89")
+	if ancestor, _ := registry.LowestCommonAncestor("CWE-89", "CWE-306"); ancestor.ID != "CWE-287" {
+		t.Errorf("多父节点的共同祖先不正确: %s", ancestor.ID)
+	}
+
+	registry.Register(NewCWE("CWE-1390", "Weak Authentication"))
+	if _, err := registry.LowestCommonAncestor("CWE-79", "CWE-1390"); !errors.Is(err, ErrNoCommonAncestor) {
+		t.Errorf("没有共同祖先时应返回ErrNoCommonAncestor，实际为%v", err)
+	}
+	if _, err := registry.LowestCommonAncestor("CWE-79", "CWE-99999"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("条目不存在时应返回ErrNotFound，实际为%v", err)
+	}
+	if ancestor, err := registry.Freeze().LowestCommonAncestor("CWE-79", "CWE-89"); err != nil || ancestor.ID != "CWE-20" {
+		t.Errorf("FrozenRegistry.LowestCommonAncestor不正确: %v, %v", ancestor, err)
+	}
+}
@@ -0,0 +1,209 @@
+package cwe
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRegistryBinaryRoundTrip 测试二进制导出再导入能还原条目、层次结构和注册表级别的数据
+func TestRegistryBinaryRoundTrip(t *testing.T) {
+	registry := newExportTestRegistry()
+	registry.SetVersion("4.16")
+	xss := registry.Entries["CWE-79"]
+	xss.Version = "4.16"
+	xss.FetchedAt = time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	xss.MappingNotes = &CWEMappingNotes{Usage: MappingAllowed, Reasons: []string{"Acceptable-Use"}}
+	xss.ObservedExamples = []CWEObservedExample{{Reference: "CVE-2021-44228", Description: "Log4Shell"}}
+	if err := registry.AddViewChild("CWE-699", "CWE-20", "CWE-79"); err != nil {
+		t.Fatalf("AddViewChild失败: %v", err)
+	}
+	if err := registry.SetTag("CWE-89", "policy", "in-policy"); err != nil {
+		t.Fatalf("SetTag失败: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := registry.ExportBinary(&buf); err != nil {
+		t.Fatalf("ExportBinary失败: %v", err)
+	}
+
+	restored := NewRegistry()
+	restored.Register(NewCWE("CWE-1", "旧条目"))
+	if err := restored.ImportBinary(&buf); err != nil {
+		t.Fatalf("ImportBinary失败: %v", err)
+	}
+
+	if len(restored.Entries) != 4 || restored.Entries["CWE-1"] != nil {
+		t.Fatalf("导入应替换原有内容，但得到 %d 个条目", len(restored.Entries))
+	}
+	if restored.Root == nil || restored.Root.ID != "CWE-1000" || restored.Version() != "4.16" {
+		t.Fatalf("根节点或版本未还原: %v %s", restored.Root, restored.Version())
+	}
+	got := restored.Entries["CWE-79"]
+	if got.Parent == nil || got.Parent.ID != "CWE-20" || len(restored.Entries["CWE-20"].Children) != 2 {
+		t.Error("父子关系未还原")
+	}
+	if !reflect.DeepEqual(newSnapshotEntry(got), newSnapshotEntry(xss)) {
+		t.Errorf("条目字段未还原:\n%+v\n期望:\n%+v", newSnapshotEntry(got), newSnapshotEntry(xss))
+	}
+	if children, err := restored.ChildrenInView("CWE-20", "CWE-699"); err != nil || len(children) != 1 || children[0].ID != "CWE-79" {
+		t.Errorf("视图内的父子关系未还原: %v, %v", cweIDsOf(children), err)
+	}
+	if annotation, _ := restored.GetAnnotation("CWE-89"); annotation.Tags["policy"] != "in-policy" {
+		t.Errorf("用户注解未还原: %+v", annotation)
+	}
+}
+
+// TestRegistryBinaryRoundTripMultiParent 测试二进制导出再导入能还原层次结构模式和所有父节点
+func TestRegistryBinaryRoundTripMultiParent(t *testing.T) {
+	registry := buildTestRegistry([]testNode{
+		{id: "CWE-1000"},
+		{id: "CWE-20", parent: "CWE-1000"},
+		{id: "CWE-74", parent: "CWE-1000"},
+		{id: "CWE-79", parent: "CWE-20"},
+	})
+	registry.SetHierarchyMode(HierarchyMultiParent)
+	if err := registry.AddChild("CWE-74", "CWE-79"); err != nil {
+		t.Fatalf("AddChild失败: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := registry.ExportBinary(&buf); err != nil {
+		t.Fatalf("ExportBinary失败: %v", err)
+	}
+	restored := NewRegistry()
+	if err := restored.ImportBinary(&buf); err != nil {
+		t.Fatalf("ImportBinary失败: %v", err)
+	}
+
+	if restored.GetHierarchyMode() != HierarchyMultiParent {
+		t.Errorf("层次结构模式未还原: %v", restored.GetHierarchyMode())
+	}
+	parents, err := restored.Parents("CWE-79")
+	if err != nil || strings.Join(cweIDsOf(parents), ",") != "CWE-20,CWE-74" {
+		t.Errorf("所有父节点应按添加顺序还原，实际为%v, %v", cweIDsOf(parents), err)
+	}
+	xss := restored.Entries["CWE-79"]
+	if xss.Parent == nil || xss.Parent.ID != "CWE-20" {
+		t.Errorf("Parent应指向第一个父节点，实际为%v", xss.Parent)
+	}
+	for _, parentID := range []string{"CWE-20", "CWE-74"} {
+		if children := restored.Entries[parentID].Children; len(children) != 1 || children[0] != xss {
+			t.Errorf("%s的子节点未还原: %v", parentID, cweIDsOf(children))
+		}
+	}
+}
+
+// TestRegistryBinaryFileRoundTrip 测试通过文件导出和导入
+func TestRegistryBinaryFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cwe.bin")
+	if err := newExportTestRegistry().ExportToBinaryFile(path); err != nil {
+		t.Fatalf("ExportToBinaryFile失败: %v", err)
+	}
+
+	restored := NewRegistry()
+	if err := restored.ImportFromBinaryFile(path); err != nil {
+		t.Fatalf("ImportFromBinaryFile失败: %v", err)
+	}
+	if restored.Root == nil || restored.Root.ID != "CWE-1000" || restored.Entries["CWE-89"].Name != "SQL Injection <script>" {
+		t.Errorf("文件导入结果不正确: %v", restored.Root)
+	}
+	if err := restored.ImportFromBinaryFile(filepath.Join(t.TempDir(), "missing.bin")); err == nil {
+		t.Error("文件不存在时应返回错误")
+	}
+}
+
+// TestRegistryImportBinaryErrors 测试格式标识、版本检查和数据损坏
+func TestRegistryImportBinaryErrors(t *testing.T) {
+	var valid bytes.Buffer
+	if err := newExportTestRegistry().ExportBinary(&valid); err != nil {
+		t.Fatalf("ExportBinary失败: %v", err)
+	}
+
+	future := bytes.NewBuffer(append([]byte(nil), registryBinaryMagic...))
+	if err := gob.NewEncoder(future).Encode(registryBinaryHeader{Version: RegistryBinaryVersion + 1}); err != nil {
+		t.Fatalf("编码头部失败: %v", err)
+	}
+
+	cases := map[string]struct {
+		data []byte
+		want error
+	}{
+		"JSON数据": {[]byte(`{"version":"1.0","entries":[]}`), ErrNotRegistryBinary},
+		"空数据":    {nil, ErrNotRegistryBinary},
+		"版本过高":   {future.Bytes(), ErrUnsupportedBinaryVersion},
+		"数据被截断":  {valid.Bytes()[:valid.Len()/2], nil},
+		"只有格式标识": {registryBinaryMagic, ErrNotRegistryBinary},
+	}
+	for name, c := range cases {
+		registry := newExportTestRegistry()
+		err := registry.ImportBinary(bytes.NewReader(c.data))
+		if err == nil || (c.want != nil && !errors.Is(err, c.want)) {
+			t.Errorf("%s: 期望返回%v，实际为%v", name, c.want, err)
+		}
+		if len(registry.Entries) != 4 {
+			t.Errorf("%s: 失败时注册表不应被修改", name)
+		}
+	}
+
+	if err := NewRegistry().ImportBinary(strings.NewReader(valid.String())); err != nil {
+		t.Errorf("完整的数据应可以导入: %v", err)
+	}
+}
+
+// newBenchmarkRegistry 创建包含count个条目、每个节点最多10个子节点的注册表
+func newBenchmarkRegistry(count int) *Registry {
+	nodes := make([]testNode, count)
+	for i := range nodes {
+		nodes[i] = testNode{
+			id:          fmt.Sprintf("CWE-%d", i+1),
+			name:        fmt.Sprintf("Weakness %d", i+1),
+			severity:    "Medium",
+			mitigations: []string{"对输入进行校验", "对输出进行编码"},
+		}
+		if i > 0 {
+			nodes[i].parent = fmt.Sprintf("CWE-%d", i/10+1)
+		}
+	}
+	return buildTestRegistry(nodes)
+}
+
+func BenchmarkImportBinary(b *testing.B) {
+	var buf bytes.Buffer
+	if err := newBenchmarkRegistry(1000).ExportBinary(&buf); err != nil {
+		b.Fatalf("ExportBinary失败: %v", err)
+	}
+	data := buf.Bytes()
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := NewRegistry().ImportBinary(bytes.NewReader(data)); err != nil {
+			b.Fatalf("ImportBinary失败: %v", err)
+		}
+	}
+}
+
+func BenchmarkImportJSON(b *testing.B) {
+	var buf bytes.Buffer
+	if err := newBenchmarkRegistry(1000).WriteExportJSON(&buf); err != nil {
+		b.Fatalf("WriteExportJSON失败: %v", err)
+	}
+	data := buf.Bytes()
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := NewRegistry().ReadExportJSON(bytes.NewReader(data)); err != nil {
+			b.Fatalf("ReadExportJSON失败: %v", err)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package cwe
+
+import "unsafe"
+
+// CompactionReport 描述一次Registry.Compact的结果
+type CompactionReport struct {
+	// DuplicateEdges 删除的重复父子关系数量
+	DuplicateEdges int
+
+	// RepointedEdges 指向注册表外副本的子节点引用被替换为注册表中条目的数量
+	RepointedEdges int
+
+	// TrimmedSlices 释放了多余容量的切片数量
+	TrimmedSlices int
+
+	// BytesReclaimed 释放的切片容量估算值(字节)，不包括map重建释放的内存
+	BytesReclaimed int64
+}
+
+// Compact 整理注册表，去除重复的父子关系并释放多余的内存
+//
+// 方法功能:
+// 大量合并操作后，Children中可能出现重复的节点，切片和map也会保留不再需要的容量。
+// Compact会:
+// - 将子节点引用替换为注册表中同ID的条目，使同一ID只对应一个对象
+// - 按ID去除重复的子节点，保留首次出现的顺序
+// - 将Children、Mitigations和Examples切片的容量收缩到实际长度
+// - 重建Entries映射，释放删除条目后残留的桶
+//
+// 返回值:
+// - *CompactionReport: 整理结果，BytesReclaimed是按切片元素大小估算的值
+//
+// 使用示例:
+// ```go
+// // 多次BuildHierarchy合并关系后
+// report := registry.Compact()
+// fmt.Printf("删除了%d条重复边，约释放%d字节\n", report.DuplicateEdges, report.BytesReclaimed)
+// ```
+func (r *Registry) Compact() *CompactionReport {
+	report := &CompactionReport{}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, entry := range r.Entries {
+		r.compactChildren(entry, report)
+
+		var reclaimed int64
+		var trimmed bool
+
+		entry.Mitigations, trimmed, reclaimed = trimStrings(entry.Mitigations)
+		report.addTrim(trimmed, reclaimed)
+
+		entry.Examples, trimmed, reclaimed = trimStrings(entry.Examples)
+		report.addTrim(trimmed, reclaimed)
+	}
+
+	entries := make(map[string]*CWE, len(r.Entries))
+	for id, entry := range r.Entries {
+		entries[id] = entry
+	}
+	r.Entries = entries
+
+	return report
+}
+
+// compactChildren 去重并收缩单个条目的Children切片
+func (r *Registry) compactChildren(entry *CWE, report *CompactionReport) {
+	if len(entry.Children) == 0 && cap(entry.Children) == 0 {
+		return
+	}
+
+	seen := make(map[string]bool, len(entry.Children))
+	children := make([]*CWE, 0, len(entry.Children))
+	for _, child := range entry.Children {
+		if child == nil {
+			report.DuplicateEdges++
+			continue
+		}
+
+		if canonical, exists := r.Entries[child.ID]; exists && canonical != child {
+			child = canonical
+			report.RepointedEdges++
+		}
+
+		if seen[child.ID] {
+			report.DuplicateEdges++
+			continue
+		}
+		seen[child.ID] = true
+
+		children = append(children, child)
+	}
+
+	freed := cap(entry.Children) - len(children)
+	if freed == 0 {
+		// 没有可释放的容量，沿用新切片即可(其中的引用可能已被替换)
+		entry.Children = children
+		return
+	}
+
+	// 复制到大小恰好的新数组，原数组随之可被回收；空切片保持与NewCWE一致的非nil值
+	entry.Children = make([]*CWE, len(children))
+	copy(entry.Children, children)
+	report.addTrim(true, int64(freed)*int64(unsafe.Sizeof((*CWE)(nil))))
+}
+
+// addTrim 记录一次切片收缩
+func (report *CompactionReport) addTrim(trimmed bool, reclaimed int64) {
+	if trimmed {
+		report.TrimmedSlices++
+		report.BytesReclaimed += reclaimed
+	}
+}
+
+// trimStrings 将字符串切片的容量收缩到实际长度
+// 返回收缩后的切片、是否发生收缩以及释放的字节数
+func trimStrings(values []string) ([]string, bool, int64) {
+	freed := cap(values) - len(values)
+	if freed == 0 {
+		return values, false, 0
+	}
+
+	trimmed := make([]string, len(values))
+	copy(trimmed, values)
+	return trimmed, true, int64(freed) * int64(unsafe.Sizeof(""))
+}
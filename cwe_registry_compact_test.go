@@ -0,0 +1,63 @@
+package cwe
+
+import "testing"
+
+func TestRegistryCompact(t *testing.T) {
+	registry := NewRegistry()
+	root := NewCWE("CWE-1000", "Root")
+	xss := NewCWE("CWE-79", "XSS")
+	sqli := NewCWE("CWE-89", "SQLi")
+	registry.Register(root)
+	registry.Register(xss)
+	registry.Register(sqli)
+
+	// 重复合并同一关系，以及指向注册表外副本的引用
+	registry.BuildHierarchy(map[string][]string{"CWE-1000": {"CWE-79", "CWE-89"}})
+	registry.BuildHierarchy(map[string][]string{"CWE-1000": {"CWE-79"}})
+	root.Children = append(root.Children, NewCWE("CWE-89", "SQLi copy"))
+
+	xss.Mitigations = make([]string, 1, 16)
+	xss.Mitigations[0] = "Encode output"
+
+	report := registry.Compact()
+
+	if report.DuplicateEdges != 2 {
+		t.Errorf("期望删除2条重复边，但删除了 %d 条", report.DuplicateEdges)
+	}
+	if report.RepointedEdges != 1 {
+		t.Errorf("期望替换1个引用，但替换了 %d 个", report.RepointedEdges)
+	}
+	if len(root.Children) != 2 || root.Children[0] != xss || root.Children[1] != sqli {
+		t.Errorf("子节点不正确: %v", root.Children)
+	}
+	if cap(root.Children) != 2 || cap(xss.Mitigations) != 1 {
+		t.Errorf("切片容量应被收缩: children=%d mitigations=%d", cap(root.Children), cap(xss.Mitigations))
+	}
+	if report.TrimmedSlices < 2 || report.BytesReclaimed <= 0 {
+		t.Errorf("应报告释放的内存: %+v", report)
+	}
+	if xss.Mitigations[0] != "Encode output" {
+		t.Error("收缩不应改变切片内容")
+	}
+
+	// 再次整理不应有任何变化
+	again := registry.Compact()
+	if *again != (CompactionReport{}) {
+		t.Errorf("第二次整理应无变化，但得到 %+v", again)
+	}
+	if len(registry.Entries) != 3 {
+		t.Errorf("重建映射后条目数量应不变，但为 %d", len(registry.Entries))
+	}
+}
+
+func TestRegistryCompactKeepsEmptyChildren(t *testing.T) {
+	registry := NewRegistry()
+	leaf := NewCWE("CWE-79", "XSS")
+	registry.Register(leaf)
+
+	registry.Compact()
+
+	if leaf.Children == nil {
+		t.Error("空的Children应保持为非nil切片")
+	}
+}
@@ -0,0 +1,420 @@
+package cwe
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CSVListSeparator 是CSV中多个缓解措施之间的分隔符
+// 缓解措施本身包含的"|"和"\"在导出时写为"\|"和"\\"，导入时还原
+const CSVListSeparator = " | "
+
+// csvColumns ExportToCSV输出的列，ImportFromCSV按列名(不区分大小写)识别
+var csvColumns = []string{"ID", "Name", "Description", "Severity", "ParentID", "Mitigations"}
+
+// CSVImportReport 记录ImportFromCSV对注册表的修改
+type CSVImportReport struct {
+	// Added 新创建的条目ID，按表格中的顺序
+	Added []string
+
+	// Updated 内容或父节点发生变化的已有条目ID，按表格中的顺序
+	Updated []string
+
+	// Unchanged 没有变化的已有条目数量
+	Unchanged int
+}
+
+// csvRow 是ImportFromCSV解析出的一行，nil字段表示表格中没有该列
+type csvRow struct {
+	line        int
+	id          string
+	name        *string
+	description *string
+	severity    *string
+	parentID    *string
+	mitigations *[]string
+}
+
+// ExportToCSV 将注册表导出为扁平的CSV表格
+//
+// 方法功能:
+// 每个条目一行，列为ID、Name、Description、Severity、ParentID、Mitigations，
+// 条目按CWE编号排序，多个缓解措施用CSVListSeparator连接，其中的"|"和"\"用反斜杠转义。
+// 分析人员可以用Excel等工具编辑后通过ImportFromCSV写回注册表。
+// 多父节点的条目只输出Parent指向的父节点。
+//
+// 参数:
+// - w: io.Writer - 输出目标
+//
+// 返回值:
+// - error: 写入失败时返回错误
+//
+// 使用示例:
+// ```go
+// file, _ := os.Create("cwe.csv")
+// defer file.Close()
+//
+// // Excel需要BOM才能正确识别UTF-8编码的中文
+// file.WriteString("\ufeff")
+// registry.ExportToCSV(file)
+// ```
+func (r *Registry) ExportToCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvColumns); err != nil {
+		return err
+	}
+
+	for _, entry := range r.Snapshot() {
+		parentID := ""
+		if entry.Parent != nil {
+			parentID = entry.Parent.ID
+		}
+		record := []string{
+			entry.ID,
+			entry.Name,
+			entry.Description,
+			entry.Severity,
+			parentID,
+			joinCSVList(entry.Mitigations),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ImportFromCSV 将CSV表格中的修改写回注册表
+//
+// 方法功能:
+// 按表头识别列，列的顺序不限，除ID外的列都可以省略，省略的列对应的字段保持不变。
+// ID已存在的条目更新名称、描述、严重性和缓解措施，ParentID变化时移动到新的父节点下
+// (ParentID为空表示从原父节点移出)；ID不存在的条目会被创建。
+// 移动条目时同时更新Parents返回的多父节点记录，并移除原父子关系在各视图中的记录；
+// 缓解措施被修改时清空MitigationDetails，TypedMitigations改为使用新的描述。
+// 导入完成后如果注册表没有根节点，且表格中恰好有一个没有父节点的条目，则将其设为根节点。
+// 文件开头的UTF-8 BOM会被忽略。所有行在修改注册表之前都会先校验，出错时注册表保持不变。
+//
+// 参数:
+// - rd: io.Reader - CSV数据，第一行为表头
+//
+// 返回值:
+// - *CSVImportReport: 新增和更新的条目
+// - error: 缺少ID列、ID无效或重复、父节点不存在或修改后会形成环时返回带行号的错误
+//
+// 使用示例:
+// ```go
+// file, err := os.Open("cwe-edited.csv")
+//
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// defer file.Close()
+//
+// report, err := registry.ImportFromCSV(file)
+//
+//	if err != nil {
+//	    log.Fatalf("导入失败: %v", err)
+//	}
+//
+// fmt.Printf("新增%d个，更新%d个\n", len(report.Added), len(report.Updated))
+// ```
+func (r *Registry) ImportFromCSV(rd io.Reader) (*CSVImportReport, error) {
+	rows, err := readCSVRows(rd)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// 计算导入后每个条目的父节点，用于校验父节点存在且不形成环
+	planned := make(map[string]string, len(r.Entries)+len(rows))
+	for id, entry := range r.Entries {
+		if entry.Parent != nil {
+			planned[id] = entry.Parent.ID
+		}
+	}
+	known := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		if known[row.id] {
+			return nil, fmt.Errorf("第%d行: ID %s重复", row.line, row.id)
+		}
+		known[row.id] = true
+		if row.parentID != nil {
+			planned[row.id] = *row.parentID
+		}
+	}
+	for _, row := range rows {
+		parentID := planned[row.id]
+		if parentID == "" {
+			continue
+		}
+		if _, exists := r.Entries[parentID]; !exists && !known[parentID] {
+			return nil, fmt.Errorf("第%d行: 父节点%s不存在", row.line, parentID)
+		}
+		seen := map[string]bool{row.id: true}
+		for ancestor := parentID; ancestor != ""; ancestor = planned[ancestor] {
+			if seen[ancestor] {
+				return nil, fmt.Errorf("第%d行: 将%s移动到%s下会形成环", row.line, row.id, parentID)
+			}
+			seen[ancestor] = true
+		}
+	}
+
+	report := &CSVImportReport{
+		Added:   make([]string, 0),
+		Updated: make([]string, 0),
+	}
+	changed := make(map[string]bool, len(rows))
+	added := make(map[string]bool)
+	for _, row := range rows {
+		entry, exists := r.Entries[row.id]
+		if !exists {
+			entry = NewCWE(row.id, "")
+			r.Entries[row.id] = entry
+			added[row.id] = true
+			report.Added = append(report.Added, row.id)
+		}
+		changed[row.id] = applyCSVRow(entry, row)
+	}
+
+	// 所有条目都存在后再调整父子关系
+	for _, row := range rows {
+		if row.parentID == nil {
+			continue
+		}
+		entry := r.Entries[row.id]
+		current := ""
+		if entry.Parent != nil {
+			current = entry.Parent.ID
+		}
+		if current == *row.parentID {
+			continue
+		}
+
+		r.reparent(entry, current, *row.parentID)
+		changed[row.id] = true
+	}
+
+	for _, row := range rows {
+		if added[row.id] {
+			continue
+		}
+		if changed[row.id] {
+			report.Updated = append(report.Updated, row.id)
+		} else {
+			report.Unchanged++
+		}
+	}
+
+	if r.Root == nil {
+		var roots []*CWE
+		for _, row := range rows {
+			if entry := r.Entries[row.id]; entry.Parent == nil {
+				roots = append(roots, entry)
+			}
+		}
+		if len(roots) == 1 {
+			r.Root = roots[0]
+		}
+	}
+
+	return report, nil
+}
+
+// readCSVRows 读取并校验CSV的所有行
+func readCSVRows(rd io.Reader) ([]csvRow, error) {
+	buffered := bufio.NewReader(rd)
+	if bom, err := buffered.Peek(3); err == nil && string(bom) == "\ufeff" {
+		buffered.Discard(3)
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("读取CSV表头失败: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, exists := columns["id"]; !exists {
+		return nil, fmt.Errorf("CSV缺少ID列")
+	}
+
+	rows := make([]csvRow, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取CSV失败: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		field := func(name string) *string {
+			i, exists := columns[name]
+			if !exists {
+				return nil
+			}
+			value := ""
+			if i < len(record) {
+				value = strings.TrimSpace(record[i])
+			}
+			return &value
+		}
+
+		rawID := *field("id")
+		if rawID == "" {
+			// 跳过空行
+			continue
+		}
+		id, err := ParseCWEID(rawID)
+		if err != nil {
+			return nil, fmt.Errorf("第%d行: %w", line, err)
+		}
+
+		row := csvRow{
+			line:        line,
+			id:          id,
+			name:        field("name"),
+			description: field("description"),
+			severity:    field("severity"),
+			parentID:    field("parentid"),
+		}
+		if row.parentID != nil && *row.parentID != "" {
+			parentID, err := ParseCWEID(*row.parentID)
+			if err != nil {
+				return nil, fmt.Errorf("第%d行: 父节点%w", line, err)
+			}
+			row.parentID = &parentID
+		}
+		if mitigations := field("mitigations"); mitigations != nil {
+			list := splitCSVList(*mitigations)
+			row.mitigations = &list
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// csvListEscaper 转义列表元素中的分隔符和反斜杠
+var csvListEscaper = strings.NewReplacer(`\`, `\\`, "|", `\|`)
+
+// joinCSVList 用CSVListSeparator连接列表，元素中的"|"和"\"用反斜杠转义
+func joinCSVList(items []string) string {
+	escaped := make([]string, len(items))
+	for i, item := range items {
+		escaped[i] = csvListEscaper.Replace(item)
+	}
+	return strings.Join(escaped, CSVListSeparator)
+}
+
+// splitCSVList 按未转义的"|"拆分joinCSVList生成的列表并还原转义，去掉元素两端的空白并跳过空元素
+// 反斜杠后不是"|"或"\"时保持原样，手工编辑的表格中的Windows路径等不受影响
+func splitCSVList(s string) []string {
+	list := make([]string, 0)
+	var item strings.Builder
+	flush := func() {
+		if value := strings.TrimSpace(item.String()); value != "" {
+			list = append(list, value)
+		}
+		item.Reset()
+	}
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && (s[i+1] == '|' || s[i+1] == '\\'):
+			i++
+			item.WriteByte(s[i])
+		case s[i] == '|':
+			flush()
+		default:
+			item.WriteByte(s[i])
+		}
+	}
+	flush()
+	return list
+}
+
+// applyCSVRow 将行中存在的列写入条目，返回条目内容是否发生变化
+func applyCSVRow(entry *CWE, row csvRow) bool {
+	changed := false
+	set := func(target *string, value *string) {
+		if value != nil && *target != *value {
+			*target = *value
+			changed = true
+		}
+	}
+	set(&entry.Name, row.name)
+	set(&entry.Description, row.description)
+	set(&entry.Severity, row.severity)
+
+	if row.mitigations != nil && strings.Join(entry.Mitigations, "\x00") != strings.Join(*row.mitigations, "\x00") {
+		entry.Mitigations = *row.mitigations
+		// 类型化的缓解措施无法从编辑后的文本还原，清空后TypedMitigations改为使用新的描述
+		entry.MitigationDetails = nil
+		changed = true
+	}
+	return changed
+}
+
+// reparent 将条目从父节点oldID移动到newID下，同时更新多父节点记录和视图中的父子关系，调用方需持有写锁
+// newID为空时只从oldID移出；多父节点模式下记录的其他父节点保持不变，newID成为第一个父节点。
+// 移动后的关系不属于任何视图
+func (r *Registry) reparent(entry *CWE, oldID, newID string) {
+	others := make([]string, 0, len(r.parents[entry.ID]))
+	for _, id := range r.parents[entry.ID] {
+		if id != oldID && id != newID {
+			others = append(others, id)
+		}
+	}
+
+	if entry.Parent != nil {
+		removeChild(entry.Parent, entry)
+		entry.Parent = nil
+	}
+	if oldID != "" {
+		r.removeViewChild(oldID, entry.ID)
+	}
+
+	if newID != "" {
+		// 多父节点模式下newID可能已是其中一个父节点，先移除避免重复加入Children
+		parent := r.Entries[newID]
+		removeChild(parent, entry)
+		parent.AddChild(entry)
+		others = append([]string{newID}, others...)
+	} else if len(others) > 0 {
+		// 仍有其他父节点时，Parent指向记录的下一个父节点
+		entry.Parent = r.Entries[others[0]]
+	}
+
+	if len(r.parents[entry.ID]) == 0 {
+		return
+	}
+	if len(others) > 0 {
+		r.parents[entry.ID] = others
+	} else {
+		delete(r.parents, entry.ID)
+	}
+}
+
+// removeChild 从parent.Children中移除child
+func removeChild(parent, child *CWE) {
+	for i, existing := range parent.Children {
+		if existing == child {
+			parent.Children = append(parent.Children[:i], parent.Children[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package cwe
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestRegistryCSVRoundTrip 测试导出的CSV可以原样导入到新的注册表
+func TestRegistryCSVRoundTrip(t *testing.T) {
+	registry := newExportTestRegistry()
+	registry.Entries["CWE-79"].Mitigations = []string{"对输出进行编码", "使用CSP, 限制脚本来源", `过滤"<|>"和C:\temp\|x`}
+	registry.Entries["CWE-20"].Description = "多行\n描述，含\"引号\""
+
+	var buf bytes.Buffer
+	if err := registry.ExportToCSV(&buf); err != nil {
+		t.Fatalf("ExportToCSV失败: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "ID,Name,Description,Severity,ParentID,Mitigations\nCWE-20,") {
+		t.Errorf("表头或排序不正确:\n%s", buf.String())
+	}
+
+	restored := NewRegistry()
+	report, err := restored.ImportFromCSV(strings.NewReader("\ufeff" + buf.String()))
+	if err != nil {
+		t.Fatalf("ImportFromCSV失败: %v", err)
+	}
+	if len(report.Added) != 4 || len(report.Updated) != 0 {
+		t.Errorf("期望新增4个条目，但得到 %+v", report)
+	}
+	if restored.Root == nil || restored.Root.ID != "CWE-1000" {
+		t.Fatalf("唯一没有父节点的条目应成为根节点: %v", restored.Root)
+	}
+
+	xss := restored.Entries["CWE-79"]
+	if xss.Parent == nil || xss.Parent.ID != "CWE-20" || xss.Severity != "High" {
+		t.Errorf("CWE-79未正确还原: %+v", xss)
+	}
+	if len(xss.Mitigations) != 3 || xss.Mitigations[1] != "使用CSP, 限制脚本来源" || xss.Mitigations[2] != `过滤"<|>"和C:\temp\|x` {
+		t.Errorf("缓解措施未正确还原: %q", xss.Mitigations)
+	}
+	if restored.Entries["CWE-20"].Description != "多行\n描述，含\"引号\"" {
+		t.Errorf("描述未正确还原: %q", restored.Entries["CWE-20"].Description)
+	}
+}
+
+// TestSplitCSVList 测试手工编辑的缓解措施列表的拆分
+func TestSplitCSVList(t *testing.T) {
+	cases := map[string][]string{
+		"":              {},
+		"a | b |  | c":  {"a", "b", "c"},
+		`a \| b | c`:    {"a | b", "c"},
+		`C:\temp\x | d`: {`C:\temp\x`, "d"},
+		`end\`:          {`end\`},
+		`double \\| e`:  {`double \`, "e"},
+	}
+	for input, want := range cases {
+		if got := splitCSVList(input); !reflect.DeepEqual(got, want) {
+			t.Errorf("splitCSVList(%q) = %q，期望%q", input, got, want)
+		}
+	}
+}
+
+// TestRegistryImportCSVEdits 测试将编辑后的表格写回已有注册表
+func TestRegistryImportCSVEdits(t *testing.T) {
+	registry := newExportTestRegistry()
+	registry.Entries["CWE-79"].URL = "https://cwe.mitre.org/data/definitions/79.html"
+
+	// 列顺序调整、省略Description列，修改严重性、移动CWE-89并新增CWE-564
+	edited := "Severity,id,ParentID,Name\n" +
+		"Critical,79,CWE-20,Cross-site Scripting\n" +
+		",CWE-89,CWE-1000,SQL Injection <script>\n" +
+		"Medium,CWE-564,CWE-89,Hibernate Injection\n" +
+		",CWE-20,CWE-1000,Improper Input Validation\n" +
+		",,,\n"
+
+	report, err := registry.ImportFromCSV(strings.NewReader(edited))
+	if err != nil {
+		t.Fatalf("ImportFromCSV失败: %v", err)
+	}
+
+	if strings.Join(report.Added, ",") != "CWE-564" || strings.Join(report.Updated, ",") != "CWE-79,CWE-89" || report.Unchanged != 1 {
+		t.Errorf("导入报告不正确: %+v", report)
+	}
+
+	xss := registry.Entries["CWE-79"]
+	if xss.Severity != "Critical" || xss.URL == "" || len(xss.Mitigations) != 1 {
+		t.Errorf("省略的列不应修改已有字段: %+v", xss)
+	}
+	sqli := registry.Entries["CWE-89"]
+	if sqli.Parent != registry.Root || len(registry.Entries["CWE-20"].Children) != 1 {
+		t.Error("CWE-89应从CWE-20移动到CWE-1000下")
+	}
+	if hibernate := registry.Entries["CWE-564"]; hibernate.Parent != sqli || hibernate.Severity != "Medium" {
+		t.Errorf("新条目未正确创建: %+v", hibernate)
+	}
+	if registry.Root.ID != "CWE-1000" {
+		t.Error("已有根节点不应被修改")
+	}
+}
+
+// TestRegistryImportCSVReparentRoundTrip 测试通过CSV移动条目后多父节点记录、视图关系和类型化缓解措施保持一致
+func TestRegistryImportCSVReparentRoundTrip(t *testing.T) {
+	registry := buildTestRegistry([]testNode{
+		{id: "CWE-1000"},
+		{id: "CWE-20", parent: "CWE-1000"},
+		{id: "CWE-74", parent: "CWE-1000"},
+		{id: "CWE-79", parent: "CWE-20", setup: func(entry *CWE) {
+			entry.Mitigations = []string{"对输出进行编码"}
+			entry.MitigationDetails = []CWEMitigation{{Phase: []string{PhaseImplementation}, Description: "对输出进行编码"}}
+		}},
+	})
+	registry.SetHierarchyMode(HierarchyMultiParent)
+	if err := registry.AddChild("CWE-74", "CWE-79"); err != nil {
+		t.Fatalf("AddChild失败: %v", err)
+	}
+	registry.tagViewChild("CWE-1000", "CWE-20", "CWE-79")
+
+	var buf bytes.Buffer
+	if err := registry.ExportToCSV(&buf); err != nil {
+		t.Fatalf("ExportToCSV失败: %v", err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "CWE-79,") {
+			lines[i] = "CWE-79,CWE-79,,,CWE-1000,对输出进行编码|使用CSP"
+		}
+	}
+	if _, err := registry.ImportFromCSV(strings.NewReader(strings.Join(lines, "\n"))); err != nil {
+		t.Fatalf("ImportFromCSV失败: %v", err)
+	}
+
+	parents, err := registry.Parents("CWE-79")
+	if err != nil {
+		t.Fatalf("Parents失败: %v", err)
+	}
+	if ids := cweIDsOf(parents); strings.Join(ids, ",") != "CWE-1000,CWE-74" {
+		t.Errorf("移动后父节点应为CWE-1000和CWE-74，实际为%v", ids)
+	}
+	xss := registry.Entries["CWE-79"]
+	if xss.Parent != registry.Root || len(registry.Entries["CWE-20"].Children) != 0 || len(registry.Entries["CWE-74"].Children) != 1 {
+		t.Error("CWE-79应从CWE-20移动到CWE-1000下，并保留在CWE-74下")
+	}
+	if inView, _ := registry.ParentsInView("CWE-79", "CWE-1000"); len(inView) != 0 {
+		t.Errorf("视图中原来的父子关系应被移除，实际为%v", cweIDsOf(inView))
+	}
+	if typed := xss.TypedMitigations(); len(typed) != 2 || typed[1].Description != "使用CSP" {
+		t.Errorf("修改缓解措施后类型化的缓解措施应与文本一致，实际为%+v", typed)
+	}
+}
+
+// TestRegistryImportCSVErrors 测试校验失败时注册表保持不变
+func TestRegistryImportCSVErrors(t *testing.T) {
+	cases := map[string]string{
+		"缺少ID列":  "Name\nfoo\n",
+		"ID无效":   "ID\nabc\n",
+		"ID重复":   "ID\nCWE-79\n79\n",
+		"父节点不存在": "ID,ParentID\nCWE-79,CWE-2\n",
+		"形成环":    "ID,ParentID\nCWE-20,CWE-79\n",
+		"空数据":    "",
+	}
+
+	for name, data := range cases {
+		registry := newExportTestRegistry()
+		if _, err := registry.ImportFromCSV(strings.NewReader(data)); err == nil {
+			t.Errorf("%s: 期望返回错误", name)
+		}
+		if len(registry.Entries) != 4 || registry.Entries["CWE-20"].Parent != registry.Root {
+			t.Errorf("%s: 失败时注册表不应被修改", name)
+		}
+	}
+
+	_, err := newExportTestRegistry().ImportFromCSV(strings.NewReader("ID,ParentID\nCWE-79,CWE-20\nCWE-89,CWE-2\n"))
+	if err == nil || !strings.Contains(err.Error(), "第3行") {
+		t.Errorf("错误信息应包含行号，但得到 %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+package cwe
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RegistryExportVersion 是ExportToJSONFile写出的导出格式版本
+// 主版本号相同的导出文件可以被ImportFromJSONFile读取
+const RegistryExportVersion = "1.0"
+
+// RegistryExport 是注册表JSON导出文件的外层结构
+// 条目使用与RegistrySnapshot相同的扁平格式，子节点以ID引用保存，导入时自动重建父子关系
+type RegistryExport struct {
+	// Version 导出格式版本，见RegistryExportVersion
+	Version string `json:"version"`
+
+	// Timestamp 导出时间，RFC 3339格式
+	Timestamp string `json:"timestamp,omitempty"`
+
+	// RootID 根节点ID，注册表没有根节点时为空
+	RootID string `json:"rootId,omitempty"`
+
+	// Entries 所有条目，按CWE编号排序
+	Entries []SnapshotEntry `json:"entries"`
+
+	// Annotations 用户注解，以条目ID为键，没有注解时省略，见Registry.SetTag
+	Annotations map[string]Annotation `json:"annotations,omitempty"`
+
+	// ChangeLog UpdateFrom记录的变更，没有记录时省略，见Registry.ChangesSince
+	ChangeLog []ChangeSet `json:"changeLog,omitempty"`
+}
+
+// WriteExportJSON 将注册表以带版本信息的JSON导出格式写入w
+//
+// 方法功能:
+// 输出包含格式版本、导出时间和根节点ID的RegistryExport，条目按CWE编号排序并使用两个空格缩进。
+// 与ExportToJSON不同，子节点以ID引用保存，不会因Parent字段产生循环引用，可以完整还原层次结构。
+//
+// 参数:
+// - w: io.Writer - 输出目标
+//
+// 返回值:
+// - error: 序列化或写入失败时返回错误
+func (r *Registry) WriteExportJSON(w io.Writer) error {
+	snapshot := NewRegistrySnapshot(r)
+	sort.SliceStable(snapshot.Entries, func(i, j int) bool {
+		return compareCWEIDs(snapshot.Entries[i].ID, snapshot.Entries[j].ID) < 0
+	})
+
+	export := RegistryExport{
+		Version:     RegistryExportVersion,
+		Timestamp:   time.Now().Format(time.RFC3339),
+		RootID:      snapshot.RootID,
+		Entries:     snapshot.Entries,
+		Annotations: snapshot.Annotations,
+		ChangeLog:   snapshot.ChangeLog,
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(export)
+}
+
+// ExportToJSONFile 将注册表导出到JSON文件
+//
+// 方法功能:
+// 以WriteExportJSON的格式写入文件，文件已存在时会被覆盖。
+// 导出的文件可以通过ImportFromJSONFile还原为注册表。
+//
+// 参数:
+// - path: string - 输出文件路径
+//
+// 返回值:
+// - error: 创建或写入文件失败时返回错误
+//
+// 使用示例:
+// ```go
+//
+//	if err := registry.ExportToJSONFile("cwe-1000.json"); err != nil {
+//	    log.Fatalf("导出失败: %v", err)
+//	}
+//
+// restored := cwe.NewRegistry()
+//
+//	if err := restored.ImportFromJSONFile("cwe-1000.json"); err != nil {
+//	    log.Fatalf("导入失败: %v", err)
+//	}
+//
+// fmt.Println(restored.Root.ID)
+// ```
+func (r *Registry) ExportToJSONFile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+	if err := r.WriteExportJSON(writer); err != nil {
+		file.Close()
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// ReadExportJSON 从rd读取WriteExportJSON写出的数据，替换注册表的当前内容
+//
+// 方法功能:
+// 检查格式版本后重建所有条目、父子关系、根节点、用户注解和变更记录。
+// 读取失败时注册表保持不变。
+//
+// 参数:
+// - rd: io.Reader - 输入数据
+//
+// 返回值:
+// - error: JSON格式错误、缺少版本、主版本号不受支持、条目ID缺失或重复、
+// 引用了不存在的子节点或根节点、注解引用了不存在的条目时返回错误
+func (r *Registry) ReadExportJSON(rd io.Reader) error {
+	var export RegistryExport
+	if err := json.NewDecoder(rd).Decode(&export); err != nil {
+		return fmt.Errorf("解析导出文件失败: %w", err)
+	}
+
+	if export.Version == "" {
+		return fmt.Errorf("导出文件缺少版本信息")
+	}
+	if major := strings.SplitN(export.Version, ".", 2)[0]; major != strings.SplitN(RegistryExportVersion, ".", 2)[0] {
+		return fmt.Errorf("不支持的导出格式版本: %s", export.Version)
+	}
+
+	snapshot := &RegistrySnapshot{RootID: export.RootID, Entries: export.Entries, Annotations: export.Annotations, ChangeLog: export.ChangeLog}
+	imported, err := snapshot.ToRegistry()
+	if err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.Entries = imported.Entries
+	r.Root = imported.Root
+	r.parents = nil
+	r.severityOverlay = imported.severityOverlay
+	r.annotations = imported.annotations
+	r.changeLog = imported.changeLog
+	return nil
+}
+
+// ImportFromJSONFile 从ExportToJSONFile导出的文件还原注册表，替换注册表的当前内容
+// 错误处理与ReadExportJSON相同，此外文件无法打开时返回错误
+func (r *Registry) ImportFromJSONFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return r.ReadExportJSON(bufio.NewReader(file))
+}
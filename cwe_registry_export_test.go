@@ -0,0 +1,112 @@
+package cwe
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newExportTestRegistry 创建CWE-1000 -> CWE-20 -> {CWE-79, CWE-89}的注册表
+func newExportTestRegistry() *Registry {
+	return buildTestRegistry([]testNode{
+		{id: "CWE-1000", name: "Research Concepts"},
+		{id: "CWE-20", name: "Improper Input Validation", parent: "CWE-1000"},
+		{id: "CWE-79", name: "Cross-site Scripting", severity: "High", parent: "CWE-20", mitigations: []string{"对输出进行编码"}},
+		{id: "CWE-89", name: "SQL Injection <script>", parent: "CWE-20"},
+	})
+}
+
+// TestRegistryJSONFileRoundTrip 测试导出到文件后导入能还原条目和层次结构
+func TestRegistryJSONFileRoundTrip(t *testing.T) {
+	registry := newExportTestRegistry()
+	overlay := NewSeverityOverlay()
+	overlay.Set("CWE-89", "Critical")
+	registry.SetSeverityOverlay(overlay)
+
+	path := filepath.Join(t.TempDir(), "cwe.json")
+	if err := registry.ExportToJSONFile(path); err != nil {
+		t.Fatalf("ExportToJSONFile失败: %v", err)
+	}
+
+	restored := NewRegistry()
+	restored.Register(NewCWE("CWE-1", "旧条目"))
+	if err := restored.ImportFromJSONFile(path); err != nil {
+		t.Fatalf("ImportFromJSONFile失败: %v", err)
+	}
+
+	if len(restored.Entries) != 4 || restored.Entries["CWE-1"] != nil {
+		t.Fatalf("导入应替换原有内容，但得到 %d 个条目", len(restored.Entries))
+	}
+	if restored.Root == nil || restored.Root.ID != "CWE-1000" {
+		t.Fatalf("根节点未还原: %v", restored.Root)
+	}
+	xss := restored.Entries["CWE-79"]
+	if xss.Parent == nil || xss.Parent.ID != "CWE-20" || xss.Parent.Parent != restored.Root {
+		t.Error("父子关系未还原")
+	}
+	if xss.Severity != "High" || len(xss.Mitigations) != 1 {
+		t.Errorf("条目字段未还原: %+v", xss)
+	}
+	if len(restored.Entries["CWE-20"].Children) != 2 {
+		t.Errorf("CWE-20应有2个子节点")
+	}
+	// 严重性覆盖层不会在默认导出中保存
+	if restored.GetSeverityOverlay() != nil {
+		t.Error("默认导出不应包含覆盖层")
+	}
+}
+
+// TestRegistryWriteExportJSON 测试导出格式的外层结构和条目顺序
+func TestRegistryWriteExportJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := newExportTestRegistry().WriteExportJSON(&buf); err != nil {
+		t.Fatalf("WriteExportJSON失败: %v", err)
+	}
+
+	var export RegistryExport
+	if err := json.Unmarshal(buf.Bytes(), &export); err != nil {
+		t.Fatalf("输出不是有效的JSON: %v", err)
+	}
+	if export.Version != RegistryExportVersion || export.Timestamp == "" || export.RootID != "CWE-1000" {
+		t.Errorf("外层字段不正确: %s %s %s", export.Version, export.Timestamp, export.RootID)
+	}
+
+	ids := make([]string, 0, len(export.Entries))
+	for _, entry := range export.Entries {
+		ids = append(ids, entry.ID)
+	}
+	if strings.Join(ids, ",") != "CWE-20,CWE-79,CWE-89,CWE-1000" {
+		t.Errorf("条目应按CWE编号排序，但得到 %v", ids)
+	}
+	if !strings.Contains(buf.String(), `"rootId": "CWE-1000"`) || !strings.Contains(buf.String(), "<script>") {
+		t.Errorf("输出格式不正确:\n%s", buf.String())
+	}
+}
+
+// TestRegistryReadExportJSONErrors 测试版本检查和数据错误
+func TestRegistryReadExportJSONErrors(t *testing.T) {
+	cases := map[string]string{
+		"格式错误":   `{"version":`,
+		"缺少版本":   `{"entries":[{"id":"CWE-1","name":"a"}]}`,
+		"主版本不支持": `{"version":"2.0","entries":[]}`,
+		"子节点不存在": `{"version":"1.0","entries":[{"id":"CWE-1","name":"a","children":["CWE-2"]}]}`,
+		"根节点不存在": `{"version":"1.0","rootId":"CWE-2","entries":[{"id":"CWE-1","name":"a"}]}`,
+	}
+
+	for name, data := range cases {
+		registry := newExportTestRegistry()
+		if err := registry.ReadExportJSON(strings.NewReader(data)); err == nil {
+			t.Errorf("%s: 期望返回错误", name)
+		}
+		if len(registry.Entries) != 4 {
+			t.Errorf("%s: 失败时注册表不应被修改", name)
+		}
+	}
+
+	registry := NewRegistry()
+	if err := registry.ReadExportJSON(strings.NewReader(`{"version":"1.3","entries":[{"id":"CWE-1","name":"a"}]}`)); err != nil {
+		t.Errorf("相同主版本号应可以导入: %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package cwe
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// FrozenRegistry 是注册表在某一时刻的不可变视图
+//
+// 由Registry.Freeze创建，内部持有注册表的深拷贝，之后对原注册表的任何修改都不会反映到视图中。
+// 视图只提供读取方法，多个goroutine可以不加锁地同时读取；需要修改时通过Thaw取得可修改的副本，
+// 修改完成后再Freeze为新的视图(写时复制)，配合AtomicRegistry可以在服务器中安全地热更新CWE数据。
+// 读取方法返回的*CWE与视图共享，调用方不应修改它们。
+type FrozenRegistry struct {
+	registry *Registry
+
+	// entries 按CWE编号排序的所有条目，在Freeze时计算一次
+	entries []*CWE
+}
+
+// Freeze 创建注册表当前状态的不可变视图
+//
+// 方法功能:
+// 复制注册表的所有条目和状态(见Clone)，返回只读的FrozenRegistry。
+// Registry.Snapshot返回的切片仍与注册表共享条目，而Freeze返回的视图与注册表完全独立，
+// 适合在后台刷新修改注册表的同时供并发的请求读取。
+//
+// 返回值:
+// - *FrozenRegistry: 注册表的不可变视图
+//
+// 使用示例:
+// ```go
+// frozen := registry.Freeze()
+//
+// // 修改原注册表不影响已经创建的视图
+// registry.Register(cwe.NewCWE("CWE-1390", "Weak Authentication"))
+// _, err := frozen.GetByID("CWE-1390") // 返回ErrNotFound
+// ```
+func (r *Registry) Freeze() *FrozenRegistry {
+	return newFrozenRegistry(r.Clone())
+}
+
+// newFrozenRegistry 将registry包装为视图，registry之后不能再被修改
+func newFrozenRegistry(registry *Registry) *FrozenRegistry {
+	return &FrozenRegistry{
+		registry: registry,
+		entries:  registry.Snapshot(),
+	}
+}
+
+// GetByID 返回指定ID的条目，条目不存在时返回包装了ErrNotFound的错误
+func (s *FrozenRegistry) GetByID(id string) (*CWE, error) {
+	return s.registry.GetByID(id)
+}
+
+// Entries 返回按CWE编号排序的所有条目，返回的切片是调用方独有的
+func (s *FrozenRegistry) Entries() []*CWE {
+	return append([]*CWE(nil), s.entries...)
+}
+
+// Len 返回条目数量
+func (s *FrozenRegistry) Len() int {
+	return len(s.entries)
+}
+
+// Root 返回根节点，没有根节点时返回nil
+func (s *FrozenRegistry) Root() *CWE {
+	return s.registry.Root
+}
+
+// Version 返回条目所属的CWE内容版本，见Registry.Version
+func (s *FrozenRegistry) Version() string {
+	return s.registry.Version()
+}
+
+// EffectiveSeverity 返回条目考虑严重性覆盖层后的严重性，见Registry.EffectiveSeverity
+func (s *FrozenRegistry) EffectiveSeverity(id string) (string, error) {
+	return s.registry.EffectiveSeverity(id)
+}
+
+// Thaw 返回视图的可修改副本
+// 每次调用都会复制一次，修改副本不影响视图，修改完成后可以通过Freeze创建新的视图
+func (s *FrozenRegistry) Thaw() *Registry {
+	return s.registry.Clone()
+}
+
+// AtomicRegistry 持有可以原子替换的FrozenRegistry，用于在服务器中热更新CWE数据
+//
+// 读取方通过Load取得当前视图并在整个请求中使用，不会看到更新进行到一半的状态；
+// 更新方通过Swap整体替换视图，或者通过Update在副本上修改后替换。
+// 替换后已经取得旧视图的读取方不受影响，旧视图在不再被引用后释放。
+// 此结构体是线程安全的，零值不可用，需要通过NewAtomicRegistry创建。
+type AtomicRegistry struct {
+	value atomic.Value
+
+	// updateMutex 保证Update的读取-修改-替换过程不会与其他Update交错
+	updateMutex sync.Mutex
+}
+
+// NewAtomicRegistry 创建持有registry不可变视图的AtomicRegistry
+// registry为nil时持有一个空注册表的视图
+func NewAtomicRegistry(registry *Registry) *AtomicRegistry {
+	if registry == nil {
+		registry = NewRegistry()
+	}
+	a := &AtomicRegistry{}
+	a.value.Store(registry.Freeze())
+	return a
+}
+
+// Load 返回当前的不可变视图
+func (a *AtomicRegistry) Load() *FrozenRegistry {
+	return a.value.Load().(*FrozenRegistry)
+}
+
+// Swap 用frozen替换当前的视图，返回被替换的视图
+// frozen为nil时不做任何修改，返回当前的视图
+func (a *AtomicRegistry) Swap(frozen *FrozenRegistry) *FrozenRegistry {
+	if frozen == nil {
+		return a.Load()
+	}
+	a.updateMutex.Lock()
+	defer a.updateMutex.Unlock()
+	return a.value.Swap(frozen).(*FrozenRegistry)
+}
+
+// Update 以写时复制的方式修改注册表
+//
+// 方法功能:
+// 复制当前视图得到可修改的注册表，调用update修改后冻结为新的视图并替换当前视图。
+// update返回错误时放弃修改，当前视图保持不变。多个Update依次执行，不会丢失其他Update的修改；
+// 读取方在整个过程中始终可以通过Load取得旧视图。
+//
+// 参数:
+// - update: func(*Registry) error - 修改注册表副本的函数
+//
+// 返回值:
+// - *FrozenRegistry: 替换后的新视图，update返回错误时为当前视图
+// - error: update返回的错误
+//
+// 使用示例:
+// ```go
+// holder := cwe.NewAtomicRegistry(registry)
+//
+// // 请求处理
+// entry, err := holder.Load().GetByID("CWE-79")
+//
+// // 后台更新
+//
+//	_, err = holder.Update(func(working *cwe.Registry) error {
+//	    return working.Register(cwe.NewCWE("CWE-1427", "Prompt Injection"))
+//	})
+//
+// ```
+func (a *AtomicRegistry) Update(update func(*Registry) error) (*FrozenRegistry, error) {
+	if update == nil {
+		return nil, errors.New("update不能为nil")
+	}
+
+	a.updateMutex.Lock()
+	defer a.updateMutex.Unlock()
+
+	current := a.value.Load().(*FrozenRegistry)
+	working := current.Thaw()
+	if err := update(working); err != nil {
+		return current, err
+	}
+
+	// working只在这里使用过，不必再复制一次
+	frozen := newFrozenRegistry(working)
+	a.value.Store(frozen)
+	return frozen, nil
+}
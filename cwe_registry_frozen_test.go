@@ -0,0 +1,121 @@
+package cwe
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestRegistryFreeze(t *testing.T) {
+	registry := newQueryTestRegistry()
+	registry.SetVersion("4.16")
+	frozen := registry.Freeze()
+
+	count := frozen.Len()
+	if count != len(registry.Entries) || frozen.Version() != "4.16" {
+		t.Fatalf("视图应包含所有条目和版本，实际为%d个，版本%q", count, frozen.Version())
+	}
+	if frozen.Root() == nil || frozen.Root() == registry.Root || frozen.Root().ID != registry.Root.ID {
+		t.Error("视图的根节点应是原根节点的副本")
+	}
+
+	registry.Register(NewCWE("CWE-1390", "Weak Authentication"))
+	registry.Entries["CWE-79"].Name = "修改后的名称"
+
+	if _, err := frozen.GetByID("CWE-1390"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("之后注册的条目不应出现在视图中，实际为%v", err)
+	}
+	if entry, _ := frozen.GetByID("CWE-79"); entry.Name == "修改后的名称" {
+		t.Error("修改原注册表不应影响视图")
+	}
+	if frozen.Len() != count {
+		t.Errorf("视图的条目数不应改变，实际为%d", frozen.Len())
+	}
+
+	entries := frozen.Entries()
+	for i := 1; i < len(entries); i++ {
+		if CompareIDs(entries[i-1].ID, entries[i].ID) >= 0 {
+			t.Fatalf("条目应按CWE编号排序: %v", cweIDsOf(entries))
+		}
+	}
+	entries[0] = nil
+	if frozen.Entries()[0] == nil {
+		t.Error("Entries应返回新的切片")
+	}
+
+	thawed := frozen.Thaw()
+	thawed.Register(NewCWE("CWE-1427", "Prompt Injection"))
+	if _, err := frozen.GetByID("CWE-1427"); err == nil {
+		t.Error("修改Thaw返回的副本不应影响视图")
+	}
+}
+
+func TestAtomicRegistry(t *testing.T) {
+	holder := NewAtomicRegistry(newQueryTestRegistry())
+	old := holder.Load()
+
+	updated, err := holder.Update(func(working *Registry) error {
+		return working.Register(NewCWE("CWE-1427", "Prompt Injection"))
+	})
+	if err != nil {
+		t.Fatalf("Update失败: %v", err)
+	}
+	if holder.Load() != updated || updated.Len() != old.Len()+1 {
+		t.Error("Update后应替换为包含新条目的视图")
+	}
+	if _, err := old.GetByID("CWE-1427"); err == nil {
+		t.Error("旧视图不应包含新条目")
+	}
+
+	updateErr := errors.New("更新失败")
+	current, err := holder.Update(func(working *Registry) error {
+		working.Register(NewCWE("CWE-1", "应被丢弃"))
+		return updateErr
+	})
+	if !errors.Is(err, updateErr) || current != updated || holder.Load() != updated {
+		t.Error("update返回错误时应保留当前视图")
+	}
+
+	replacement := NewRegistry().Freeze()
+	if previous := holder.Swap(replacement); previous != updated {
+		t.Error("Swap应返回被替换的视图")
+	}
+	if holder.Load() != replacement || holder.Swap(nil) != replacement {
+		t.Error("Swap(nil)不应修改当前视图")
+	}
+
+	if NewAtomicRegistry(nil).Load().Len() != 0 {
+		t.Error("registry为nil时应持有空视图")
+	}
+}
+
+func TestAtomicRegistryConcurrentUpdates(t *testing.T) {
+	holder := NewAtomicRegistry(nil)
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 20; i++ {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			holder.Update(func(working *Registry) error {
+				return working.Register(NewCWE(id, id))
+			})
+		}(FormatID(i))
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			frozen := holder.Load()
+			for _, entry := range frozen.Entries() {
+				if _, err := frozen.GetByID(entry.ID); err != nil {
+					t.Errorf("视图中的条目应始终可读: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if holder.Load().Len() != 20 {
+		t.Errorf("并发的Update不应丢失修改，实际有%d个条目", holder.Load().Len())
+	}
+}
@@ -0,0 +1,184 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// HierarchyMode 控制注册表建立父子关系时如何处理已有父节点的子节点
+type HierarchyMode int
+
+const (
+	// HierarchyOverwrite 与CWE.AddChild相同: 子节点加入新父节点的Children，Parent指向最后添加的父节点
+	// 这是默认模式，与早期版本的行为一致
+	HierarchyOverwrite HierarchyMode = iota
+
+	// HierarchyStrict 子节点已有其他父节点时拒绝添加，返回包装了ErrMultipleParents的错误
+	// 适合要求严格树形结构的场景
+	HierarchyStrict
+
+	// HierarchyMultiParent 允许多个父节点并全部记录，Parent保持指向第一个父节点
+	// 对应CWE中一个条目可以有多个ChildOf关系的情况，通过Parents查询所有父节点
+	HierarchyMultiParent
+)
+
+// ErrMultipleParents 表示严格模式下子节点已经有其他父节点
+var ErrMultipleParents = errors.New("子节点已有其他父节点")
+
+// SetHierarchyMode 设置注册表建立父子关系的模式
+// 只影响之后通过AddChild和BuildHierarchy建立的关系，已有的关系不变
+func (r *Registry) SetHierarchyMode(mode HierarchyMode) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.hierarchyMode = mode
+}
+
+// GetHierarchyMode 返回注册表当前的层次结构模式
+func (r *Registry) GetHierarchyMode() HierarchyMode {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.hierarchyMode
+}
+
+// AddChild 按注册表的层次结构模式将子节点添加到父节点下
+//
+// 方法功能:
+// CWE.AddChild在同一节点被添加到多个父节点下时会静默覆盖Parent。
+// 通过注册表添加时，HierarchyStrict模式会拒绝第二个父节点，HierarchyMultiParent模式会记录所有父节点，
+// HierarchyOverwrite模式保持CWE.AddChild的行为。严格模式和多父节点模式下已经是该父节点的子节点时不做任何操作。
+//
+// 参数:
+// - parentID: string - 父节点ID，必须已注册
+// - childID: string - 子节点ID，必须已注册
+//
+// 返回值:
+// - error: 节点未注册时返回包装了ErrNotFound的错误，严格模式下冲突时返回包装了ErrMultipleParents的错误
+//
+// 使用示例:
+// ```go
+// registry.SetHierarchyMode(cwe.HierarchyMultiParent)
+// registry.AddChild("CWE-74", "CWE-79")
+// registry.AddChild("CWE-20", "CWE-79")
+//
+// parents, _ := registry.Parents("CWE-79") // [CWE-74 CWE-20]
+// ```
+func (r *Registry) AddChild(parentID, childID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	parent, exists := r.Entries[parentID]
+	if !exists {
+		return &notFoundError{id: parentID}
+	}
+	child, exists := r.Entries[childID]
+	if !exists {
+		return &notFoundError{id: childID}
+	}
+	return r.link(parent, child)
+}
+
+// Parents 返回条目的所有父节点
+//
+// HierarchyMultiParent模式下返回按添加顺序记录的所有父节点，第一个与Parent相同；
+// 其他模式下只返回Parent(没有父节点时返回空切片)。已从注册表中移除的父节点会被跳过。
+//
+// 返回值:
+// - []*CWE: 父节点列表
+// - error: 条目不存在时返回包装了ErrNotFound的错误
+func (r *Registry) Parents(id string) ([]*CWE, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	entry, exists := r.Entries[id]
+	if !exists {
+		return nil, &notFoundError{id: id}
+	}
+	return r.parentsOf(entry), nil
+}
+
+// parentsOf 返回条目的所有父节点，规则与Parents相同，调用方需持有读锁
+func (r *Registry) parentsOf(entry *CWE) []*CWE {
+	parents := make([]*CWE, 0, len(r.parents[entry.ID]))
+	for _, parentID := range r.parents[entry.ID] {
+		if parent, exists := r.Entries[parentID]; exists {
+			parents = append(parents, parent)
+		}
+	}
+	if len(parents) == 0 && entry.Parent != nil {
+		parents = append(parents, entry.Parent)
+	}
+	return parents
+}
+
+// link 按当前模式建立父子关系，调用方需持有写锁
+// HierarchyOverwrite模式与CWE.AddChild完全相同(包括重复添加)，其他模式下已是子节点时不做任何操作
+func (r *Registry) link(parent, child *CWE) error {
+	if r.hierarchyMode == HierarchyOverwrite {
+		parent.AddChild(child)
+		return nil
+	}
+	for _, existing := range parent.Children {
+		if existing == child {
+			return nil
+		}
+	}
+
+	switch r.hierarchyMode {
+	case HierarchyStrict:
+		if child.Parent != nil && child.Parent != parent {
+			return fmt.Errorf("%w: %s已属于%s，不能再添加到%s下", ErrMultipleParents, child.ID, child.Parent.ID, parent.ID)
+		}
+		parent.AddChild(child)
+	case HierarchyMultiParent:
+		first := child.Parent
+		parent.AddChild(child)
+		if first != nil {
+			child.Parent = first
+		}
+		r.recordParent(child, parent)
+	}
+	return nil
+}
+
+// recordParent 记录多父节点模式下的一个父节点
+// 第一次记录时先补上之前通过其他方式设置的Parent，保证记录的第一个父节点与Parent一致
+func (r *Registry) recordParent(child, parent *CWE) {
+	if r.parents == nil {
+		r.parents = make(map[string][]string)
+	}
+	recorded := r.parents[child.ID]
+	if len(recorded) == 0 && child.Parent != nil && child.Parent != parent {
+		recorded = append(recorded, child.Parent.ID)
+	}
+	for _, id := range recorded {
+		if id == parent.ID {
+			return
+		}
+	}
+	r.parents[child.ID] = append(recorded, parent.ID)
+}
+
+// copyParents 深拷贝多父节点记录，parents为nil时返回nil
+func copyParents(parents map[string][]string) map[string][]string {
+	if parents == nil {
+		return nil
+	}
+	copied := make(map[string][]string, len(parents))
+	for id, parentIDs := range parents {
+		copied[id] = append([]string(nil), parentIDs...)
+	}
+	return copied
+}
+
+// sortedParentIDs 返回parentChildMap中按CWE编号排序的父节点ID，使BuildHierarchy的结果与map遍历顺序无关
+func sortedParentIDs(parentChildMap map[string][]string) []string {
+	ids := make([]string, 0, len(parentChildMap))
+	for id := range parentChildMap {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return compareCWEIDs(ids[i], ids[j]) < 0
+	})
+	return ids
+}
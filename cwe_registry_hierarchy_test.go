@@ -0,0 +1,127 @@
+package cwe
+
+import (
+	"errors"
+	"testing"
+)
+
+// newHierarchyTestRegistry 创建包含CWE-707、CWE-74、CWE-20和CWE-79的注册表
+func newHierarchyTestRegistry(mode HierarchyMode) *Registry {
+	registry := NewRegistry()
+	for _, id := range []string{"CWE-707", "CWE-74", "CWE-20", "CWE-79"} {
+		registry.Register(NewCWE(id, id))
+	}
+	registry.SetHierarchyMode(mode)
+	return registry
+}
+
+// parentIDs 返回Parents结果的ID列表
+func parentIDs(t *testing.T, registry *Registry, id string) []string {
+	t.Helper()
+	parents, err := registry.Parents(id)
+	if err != nil {
+		t.Fatalf("Parents(%s)失败: %v", id, err)
+	}
+	ids := make([]string, 0, len(parents))
+	for _, parent := range parents {
+		ids = append(ids, parent.ID)
+	}
+	return ids
+}
+
+// TestRegistryAddChildOverwrite 测试默认模式保持CWE.AddChild的覆盖行为
+func TestRegistryAddChildOverwrite(t *testing.T) {
+	registry := newHierarchyTestRegistry(HierarchyOverwrite)
+	if registry.GetHierarchyMode() != HierarchyOverwrite {
+		t.Fatal("SetHierarchyMode未生效")
+	}
+
+	if err := registry.AddChild("CWE-74", "CWE-79"); err != nil {
+		t.Fatalf("AddChild失败: %v", err)
+	}
+	if err := registry.AddChild("CWE-20", "CWE-79"); err != nil {
+		t.Fatalf("AddChild失败: %v", err)
+	}
+
+	if registry.Entries["CWE-79"].Parent.ID != "CWE-20" {
+		t.Errorf("默认模式下Parent应指向最后添加的父节点")
+	}
+	if ids := parentIDs(t, registry, "CWE-79"); len(ids) != 1 || ids[0] != "CWE-20" {
+		t.Errorf("默认模式下Parents应只返回Parent，但得到 %v", ids)
+	}
+}
+
+// TestRegistryAddChildStrict 测试严格模式拒绝第二个父节点
+func TestRegistryAddChildStrict(t *testing.T) {
+	registry := newHierarchyTestRegistry(HierarchyStrict)
+
+	if err := registry.AddChild("CWE-74", "CWE-79"); err != nil {
+		t.Fatalf("AddChild失败: %v", err)
+	}
+	if err := registry.AddChild("CWE-74", "CWE-79"); err != nil {
+		t.Errorf("重复添加同一父节点不应报错: %v", err)
+	}
+	if len(registry.Entries["CWE-74"].Children) != 1 {
+		t.Errorf("重复添加不应产生重复的子节点")
+	}
+
+	err := registry.AddChild("CWE-20", "CWE-79")
+	if !errors.Is(err, ErrMultipleParents) {
+		t.Fatalf("期望ErrMultipleParents，但得到 %v", err)
+	}
+	if registry.Entries["CWE-79"].Parent.ID != "CWE-74" || len(registry.Entries["CWE-20"].Children) != 0 {
+		t.Error("被拒绝的添加不应修改层次结构")
+	}
+
+	err = registry.BuildHierarchy(map[string][]string{"CWE-707": {"CWE-74", "CWE-79"}})
+	if !errors.Is(err, ErrMultipleParents) {
+		t.Errorf("BuildHierarchy在严格模式下也应拒绝多父节点，但得到 %v", err)
+	}
+}
+
+// TestRegistryAddChildMultiParent 测试多父节点模式记录所有父节点
+func TestRegistryAddChildMultiParent(t *testing.T) {
+	registry := newHierarchyTestRegistry(HierarchyMultiParent)
+
+	// 先通过CWE.AddChild设置的父节点也会被记录为第一个父节点
+	registry.Entries["CWE-707"].AddChild(registry.Entries["CWE-74"])
+	if err := registry.AddChild("CWE-20", "CWE-74"); err != nil {
+		t.Fatalf("AddChild失败: %v", err)
+	}
+	if ids := parentIDs(t, registry, "CWE-74"); len(ids) != 2 || ids[0] != "CWE-707" || ids[1] != "CWE-20" {
+		t.Errorf("Parents结果不正确: %v", ids)
+	}
+
+	if err := registry.BuildHierarchy(map[string][]string{
+		"CWE-74": {"CWE-79"},
+		"CWE-20": {"CWE-79"},
+	}); err != nil {
+		t.Fatalf("BuildHierarchy失败: %v", err)
+	}
+	xss := registry.Entries["CWE-79"]
+	if xss.Parent.ID != "CWE-20" {
+		t.Errorf("Parent应保持指向第一个父节点(按编号先处理的CWE-20)，但得到 %s", xss.Parent.ID)
+	}
+	if ids := parentIDs(t, registry, "CWE-79"); len(ids) != 2 || ids[0] != "CWE-20" || ids[1] != "CWE-74" {
+		t.Errorf("Parents结果不正确: %v", ids)
+	}
+
+	if ids := parentIDs(t, registry, "CWE-707"); len(ids) != 0 {
+		t.Errorf("根节点不应有父节点，但得到 %v", ids)
+	}
+}
+
+// TestRegistryAddChildNotFound 测试节点不存在时的错误
+func TestRegistryAddChildNotFound(t *testing.T) {
+	registry := newHierarchyTestRegistry(HierarchyStrict)
+
+	if err := registry.AddChild("CWE-1", "CWE-79"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("父节点不存在时应返回ErrNotFound，但得到 %v", err)
+	}
+	if err := registry.AddChild("CWE-74", "CWE-1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("子节点不存在时应返回ErrNotFound，但得到 %v", err)
+	}
+	if _, err := registry.Parents("CWE-1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("条目不存在时应返回ErrNotFound，但得到 %v", err)
+	}
+}
@@ -0,0 +1,393 @@
+package cwe
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultRefreshInterval 是RegistryManager检查CWE新版本的默认间隔
+// MITRE通常每年发布数个版本，每周检查一次已经足够及时
+const DefaultRefreshInterval = 7 * 24 * time.Hour
+
+// RegistryBuildFunc 构建指定CWE版本的注册表，由RegistryManager在发现新版本时调用
+type RegistryBuildFunc func(ctx context.Context, version string) (*Registry, error)
+
+// RegistryUpdate 描述RegistryManager的一次注册表更新
+type RegistryUpdate struct {
+	// Registry 新的注册表
+	Registry *Registry
+
+	// PreviousVersion 更新前的CWE版本，第一次构建时为空
+	PreviousVersion string
+
+	// Version 新注册表的CWE版本
+	Version string
+
+	// UpdatedAt 更新完成的时间
+	UpdatedAt time.Time
+}
+
+// RegistryManager 持有一个长期使用的注册表，并按计划在CWE发布新版本时自动重新构建
+//
+// 每次刷新先通过GetVersion检查服务器版本，版本没有变化时不发送其他请求；
+// 发现新版本时在后台构建新的注册表，构建成功后整体替换旧的注册表，
+// 再通过OnUpdate注册的回调和Subscribe返回的通道通知订阅者，设置了SetEventEmitter时还会发出条目级的变化事件。
+// 构建失败时继续使用旧的注册表。
+// 通过Registry取得的注册表在替换后仍然可用，只是不再是最新版本。
+// 此结构体是线程安全的。
+type RegistryManager struct {
+	client   *APIClient
+	build    RegistryBuildFunc
+	interval time.Duration
+	logger   Logger
+
+	// mutex 保护以下字段
+	mutex       sync.RWMutex
+	registry    *Registry
+	version     string
+	updatedAt   time.Time
+	lastErr     error
+	callbacks   []func(RegistryUpdate)
+	subscribers []chan RegistryUpdate
+	emitter     *EventEmitter
+
+	// stop和done 在Start之后、Stop之前不为nil
+	stop chan struct{}
+	done chan struct{}
+
+	// refreshMutex 保证同一时间只进行一次刷新
+	refreshMutex sync.Mutex
+}
+
+// NewRegistryManager 创建管理指定视图注册表的RegistryManager
+//
+// 方法功能:
+// 默认每次发现新版本时用client创建新的DataFetcher，通过PinVersion固定到新版本后调用
+// BuildCWETreeWithView构建视图树，构建过程中服务器版本再次变化时放弃本次结果。
+// 需要不同的构建方式(如使用缓存、存储或其他视图)时使用SetBuildFunc。
+// 创建后注册表为空，需要调用Refresh或Start。
+//
+// 参数:
+// - client: *APIClient - 查询版本和构建注册表使用的API客户端
+// - viewID: string - 要构建的视图ID，如"1000"
+//
+// 返回值:
+// - *RegistryManager: 新的注册表管理器，检查间隔为DefaultRefreshInterval
+//
+// 使用示例:
+// ```go
+// manager := cwe.NewRegistryManager(cwe.NewAPIClient(), "1000")
+//
+//	manager.OnUpdate(func(update cwe.RegistryUpdate) {
+//	    log.Printf("CWE已从%s更新到%s", update.PreviousVersion, update.Version)
+//	})
+//
+// manager.Start(ctx)
+// defer manager.Stop()
+//
+// // 处理请求时总是使用最新的注册表
+// entry, err := manager.Registry().GetByID("CWE-79")
+// ```
+func NewRegistryManager(client *APIClient, viewID string) *RegistryManager {
+	return &RegistryManager{
+		client:   client,
+		interval: DefaultRefreshInterval,
+		build: func(ctx context.Context, version string) (*Registry, error) {
+			fetcher := NewDataFetcherWithClient(client.withContext(ctx))
+			fetcher.PinVersion(version)
+			return fetcher.BuildCWETreeWithView(viewID)
+		},
+	}
+}
+
+// SetBuildFunc 设置发现新版本时构建注册表的函数，build为nil时不做任何修改
+// 返回的注册表没有版本时，RegistryManager会将其设置为GetVersion返回的版本
+func (m *RegistryManager) SetBuildFunc(build RegistryBuildFunc) {
+	if build == nil {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.build = build
+}
+
+// SetRefreshInterval 设置Start启动的后台刷新检查新版本的间隔，<=0时使用DefaultRefreshInterval
+// 只影响之后调用的Start
+func (m *RegistryManager) SetRefreshInterval(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.interval = interval
+}
+
+// SetLogger 设置记录刷新结果的日志记录器，为nil时不记录日志
+func (m *RegistryManager) SetLogger(logger Logger) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.logger = logger
+}
+
+// SetRegistry 设置当前的注册表，不通知订阅者
+// 用于在启动时载入快照等已有的数据，之后的刷新只在服务器版本与registry.Version()不同时重新构建
+func (m *RegistryManager) SetRegistry(registry *Registry) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.registry = registry
+	m.version = ""
+	if registry != nil {
+		m.version = registry.Version()
+	}
+}
+
+// Registry 返回当前的注册表，尚未构建时返回nil
+func (m *RegistryManager) Registry() *Registry {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.registry
+}
+
+// Version 返回当前注册表的CWE版本，尚未构建时返回空字符串
+func (m *RegistryManager) Version() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.version
+}
+
+// UpdatedAt 返回最近一次替换注册表的时间，尚未构建时返回零值
+func (m *RegistryManager) UpdatedAt() time.Time {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.updatedAt
+}
+
+// LastError 返回最近一次刷新的错误，最近一次刷新成功(包括版本没有变化)时返回nil
+func (m *RegistryManager) LastError() error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.lastErr
+}
+
+// OnUpdate 注册在注册表被替换后调用的回调
+// 回调在刷新所在的goroutine中按注册顺序同步调用，应尽快返回
+func (m *RegistryManager) OnUpdate(callback func(RegistryUpdate)) {
+	if callback == nil {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.callbacks = append(m.callbacks, callback)
+}
+
+// Subscribe 返回在注册表被替换后接收更新的通道
+//
+// 方法功能:
+// 通道的缓冲区大小为1，接收方来不及处理时旧的更新会被丢弃，只保留最新的一次，
+// 因此刷新不会因为接收方阻塞而停止。通道不会被关闭。
+//
+// 返回值:
+// - <-chan RegistryUpdate: 接收更新的通道
+//
+// 使用示例:
+// ```go
+// updates := manager.Subscribe()
+//
+//	go func() {
+//	    for update := range updates {
+//	        log.Printf("CWE已更新到%s，共%d个条目", update.Version, len(update.Registry.Entries))
+//	    }
+//	}()
+//
+// ```
+func (m *RegistryManager) Subscribe() <-chan RegistryUpdate {
+	ch := make(chan RegistryUpdate, 1)
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.subscribers = append(m.subscribers, ch)
+	return ch
+}
+
+// Refresh 检查服务器的CWE版本，发现新版本时重新构建并替换注册表
+//
+// 方法功能:
+// 服务器版本与当前注册表的版本相同时只发送一个GetVersion请求；否则调用构建函数，
+// 构建成功后替换注册表并通知订阅者。获取版本或构建失败时保留当前的注册表。
+// 同一时间只进行一次刷新，并发调用会依次执行。
+//
+// 参数:
+// - ctx: context.Context - 用于版本检查和构建，取消后正在进行的请求立即失败，不再替换注册表
+//
+// 返回值:
+// - bool: 注册表是否被替换
+// - error: 获取版本或构建失败时返回错误
+func (m *RegistryManager) Refresh(ctx context.Context) (bool, error) {
+	m.refreshMutex.Lock()
+	defer m.refreshMutex.Unlock()
+
+	updated, err := m.refresh(ctx)
+
+	m.mutex.Lock()
+	m.lastErr = err
+	m.mutex.Unlock()
+	return updated, err
+}
+
+// refresh 执行一次刷新，调用方必须持有refreshMutex
+func (m *RegistryManager) refresh(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	resp, err := m.client.withContext(ctx).GetVersion()
+	if err != nil {
+		return false, err
+	}
+	if resp.Version == "" {
+		return false, errors.New("服务器没有返回CWE版本")
+	}
+
+	m.mutex.RLock()
+	current, previous, build := m.registry, m.version, m.build
+	m.mutex.RUnlock()
+	if current != nil && previous == resp.Version {
+		return false, nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	m.log().Info("发现新的CWE版本，开始重新构建注册表", "previous", previous, "version", resp.Version)
+
+	registry, err := build(ctx, resp.Version)
+	if err != nil {
+		return false, err
+	}
+	if registry == nil {
+		return false, errors.New("构建函数没有返回注册表")
+	}
+	if registry.Version() == "" {
+		registry.SetVersion(resp.Version)
+	}
+
+	update := RegistryUpdate{
+		Registry:        registry,
+		PreviousVersion: previous,
+		Version:         registry.Version(),
+		UpdatedAt:       time.Now(),
+	}
+
+	m.mutex.Lock()
+	m.registry = registry
+	m.version = update.Version
+	m.updatedAt = update.UpdatedAt
+	callbacks := append(([]func(RegistryUpdate))(nil), m.callbacks...)
+	subscribers := append([]chan RegistryUpdate(nil), m.subscribers...)
+	emitter := m.emitter
+	m.mutex.Unlock()
+
+	m.log().Info("注册表已更新", "version", update.Version, "entries", len(registry.Entries))
+	m.emitChanges(ctx, emitter, current, registry)
+	for _, callback := range callbacks {
+		callback(update)
+	}
+	for _, ch := range subscribers {
+		publishUpdate(ch, update)
+	}
+	return true, nil
+}
+
+// publishUpdate 向缓冲区大小为1的通道发送更新，通道已满时用新的更新替换旧的更新
+func publishUpdate(ch chan RegistryUpdate, update RegistryUpdate) {
+	for {
+		select {
+		case ch <- update:
+			return
+		default:
+		}
+		select {
+		case <-ch:
+		default:
+		}
+	}
+}
+
+// Start 在后台启动定期刷新
+//
+// 方法功能:
+// 立即进行一次刷新，之后每隔SetRefreshInterval设置的间隔刷新一次，直到调用Stop或ctx被取消。
+// 刷新失败只记录日志，下一个周期会重新尝试，错误可以通过LastError查看。
+// 已经启动时不做任何操作。
+//
+// 参数:
+// - ctx: context.Context - 取消后停止后台刷新
+func (m *RegistryManager) Start(ctx context.Context) {
+	m.mutex.Lock()
+	if m.stop != nil {
+		m.mutex.Unlock()
+		return
+	}
+	stop, done := make(chan struct{}), make(chan struct{})
+	m.stop, m.done = stop, done
+	interval := m.interval
+	m.mutex.Unlock()
+
+	go m.run(ctx, interval, stop, done)
+}
+
+// Stop 停止Start启动的后台刷新，并等待正在进行的刷新结束
+// 未启动时不做任何操作，停止后可以再次调用Start
+func (m *RegistryManager) Stop() {
+	m.mutex.Lock()
+	stop, done := m.stop, m.done
+	m.stop, m.done = nil, nil
+	m.mutex.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// run 是后台刷新的主循环
+func (m *RegistryManager) run(ctx context.Context, interval time.Duration, stop, done chan struct{}) {
+	defer close(done)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := m.Refresh(ctx); err != nil && ctx.Err() == nil {
+			m.log().Warn("刷新注册表失败", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// log 返回管理器的日志记录器，未设置时返回不记录任何内容的记录器
+func (m *RegistryManager) log() Logger {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	if m.logger == nil {
+		return nopLogger{}
+	}
+	return m.logger
+}
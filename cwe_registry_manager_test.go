@@ -0,0 +1,245 @@
+package cwe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// setupManagerTestServer 在setupResumableTreeServer的基础上提供版本接口，版本为*version
+func setupManagerTestServer(t *testing.T, version *atomic.Value) *httptest.Server {
+	tree := setupResumableTreeServer(new(int32))
+	t.Cleanup(tree.Close)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/cwe/version" {
+			fmt.Fprintf(w, `{"version": %q}`, version.Load().(string))
+			return
+		}
+		tree.Config.Handler.ServeHTTP(w, r)
+	}))
+	return server
+}
+
+func newManagerTestClient(serverURL string) *APIClient {
+	client := NewAPIClientWithOptions(serverURL, DefaultTimeout, NewHTTPRateLimiter(0))
+	client.GetHTTPClient().SetRetryDelay(time.Millisecond)
+	return client
+}
+
+func TestRegistryManagerRefresh(t *testing.T) {
+	var version atomic.Value
+	version.Store("4.15")
+	server := setupManagerTestServer(t, &version)
+	defer server.Close()
+
+	manager := NewRegistryManager(newManagerTestClient(server.URL), "1000")
+	if manager.Registry() != nil {
+		t.Fatal("刷新前不应有注册表")
+	}
+
+	var updates []RegistryUpdate
+	manager.OnUpdate(func(update RegistryUpdate) {
+		updates = append(updates, update)
+	})
+	ch := manager.Subscribe()
+
+	updated, err := manager.Refresh(context.Background())
+	if err != nil || !updated {
+		t.Fatalf("第一次刷新应构建注册表，实际为%v, %v", updated, err)
+	}
+	first := manager.Registry()
+	if len(first.Entries) != 4 || first.Version() != "4.15" || manager.Version() != "4.15" {
+		t.Errorf("注册表不正确: %d个条目，版本%q", len(first.Entries), first.Version())
+	}
+	if first.Entries["CWE-79"].Version != "4.15" {
+		t.Errorf("条目应标记版本，实际为%q", first.Entries["CWE-79"].Version)
+	}
+	if manager.UpdatedAt().IsZero() {
+		t.Error("UpdatedAt应记录更新时间")
+	}
+
+	updated, err = manager.Refresh(context.Background())
+	if err != nil || updated {
+		t.Errorf("版本不变时不应重新构建，实际为%v, %v", updated, err)
+	}
+	if manager.Registry() != first {
+		t.Error("版本不变时注册表不应被替换")
+	}
+
+	version.Store("4.16")
+	if updated, err := manager.Refresh(context.Background()); err != nil || !updated {
+		t.Fatalf("版本变化时应重新构建，实际为%v, %v", updated, err)
+	}
+	if manager.Registry() == first || manager.Version() != "4.16" {
+		t.Errorf("注册表应被替换为新版本，实际版本为%q", manager.Version())
+	}
+
+	if len(updates) != 2 || updates[1].PreviousVersion != "4.15" || updates[1].Version != "4.16" {
+		t.Errorf("回调收到的更新不正确: %+v", updates)
+	}
+
+	// 通道只保留最新的更新
+	select {
+	case update := <-ch:
+		if update.Version != "4.16" || update.Registry != manager.Registry() {
+			t.Errorf("通道应收到最新的更新，实际为%+v", update)
+		}
+	default:
+		t.Error("通道应收到更新")
+	}
+	select {
+	case update := <-ch:
+		t.Errorf("旧的更新应被丢弃，实际收到%+v", update)
+	default:
+	}
+}
+
+func TestRegistryManagerKeepsRegistryOnFailure(t *testing.T) {
+	var version atomic.Value
+	version.Store("4.15")
+	server := setupManagerTestServer(t, &version)
+	defer server.Close()
+
+	manager := NewRegistryManager(newManagerTestClient(server.URL), "1000")
+	if _, err := manager.Refresh(context.Background()); err != nil {
+		t.Fatalf("刷新失败: %v", err)
+	}
+	first := manager.Registry()
+
+	buildErr := errors.New("构建失败")
+	manager.SetBuildFunc(func(ctx context.Context, version string) (*Registry, error) {
+		return nil, buildErr
+	})
+	version.Store("4.16")
+
+	if updated, err := manager.Refresh(context.Background()); updated || !errors.Is(err, buildErr) {
+		t.Errorf("构建失败时应返回错误，实际为%v, %v", updated, err)
+	}
+	if manager.Registry() != first || manager.Version() != "4.15" {
+		t.Error("构建失败时应保留原来的注册表")
+	}
+	if !errors.Is(manager.LastError(), buildErr) {
+		t.Errorf("LastError应记录最近的错误，实际为%v", manager.LastError())
+	}
+}
+
+func TestRegistryManagerSetRegistry(t *testing.T) {
+	var version atomic.Value
+	version.Store("4.16")
+	server := setupManagerTestServer(t, &version)
+	defer server.Close()
+
+	loaded := NewRegistry()
+	loaded.SetVersion("4.16")
+
+	manager := NewRegistryManager(newManagerTestClient(server.URL), "1000")
+	manager.SetRegistry(loaded)
+	manager.SetBuildFunc(func(ctx context.Context, version string) (*Registry, error) {
+		t.Error("载入的注册表版本与服务器相同时不应重新构建")
+		return NewRegistry(), nil
+	})
+
+	if updated, err := manager.Refresh(context.Background()); err != nil || updated {
+		t.Errorf("刷新结果不正确: %v, %v", updated, err)
+	}
+	if manager.Registry() != loaded {
+		t.Error("应继续使用载入的注册表")
+	}
+}
+
+func TestRegistryManagerStartStop(t *testing.T) {
+	var version atomic.Value
+	version.Store("4.15")
+	server := setupManagerTestServer(t, &version)
+	defer server.Close()
+
+	var mutex sync.Mutex
+	var builds []string
+	manager := NewRegistryManager(newManagerTestClient(server.URL), "1000")
+	manager.SetRefreshInterval(10 * time.Millisecond)
+	manager.SetBuildFunc(func(ctx context.Context, version string) (*Registry, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		builds = append(builds, version)
+		return NewRegistry(), nil
+	})
+	updates := manager.Subscribe()
+
+	manager.Start(context.Background())
+	manager.Start(context.Background())
+	defer manager.Stop()
+
+	waitUpdate := func(want string) {
+		select {
+		case update := <-updates:
+			if update.Version != want {
+				t.Errorf("更新的版本应为%s，实际为%s", want, update.Version)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("等待版本%s的更新超时", want)
+		}
+	}
+	waitUpdate("4.15")
+	version.Store("4.16")
+	waitUpdate("4.16")
+
+	manager.Stop()
+	manager.Stop()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(builds) != 2 {
+		t.Errorf("每个版本应只构建一次，实际为%v", builds)
+	}
+}
+
+func TestRegistryManagerStopDuringSlowBuild(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/cwe/version" {
+			fmt.Fprint(w, `{"version": "4.15"}`)
+			return
+		}
+		// 构建请求一直不返回，直到客户端取消请求
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	manager := NewRegistryManager(newManagerTestClient(server.URL), "1000")
+	manager.Start(context.Background())
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("后台刷新应开始构建注册表")
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		manager.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("构建进行中时Stop应取消构建并立即返回")
+	}
+	if manager.Registry() != nil {
+		t.Error("取消的构建不应替换注册表")
+	}
+}
@@ -0,0 +1,107 @@
+package cwe
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultPathSeparator FormatPath默认使用的节点分隔符
+const DefaultPathSeparator = " → "
+
+// PathBetween 返回两个条目在层次结构中经过最近共同祖先的路径
+//
+// 方法功能:
+// 路径从fromID出发沿父节点向上到达最近的共同祖先(见LowestCommonAncestor)，再沿子节点向下到达toID。
+// toID是fromID的祖先时路径只向上，如CWE-79 → CWE-74 → CWE-707；fromID是toID的祖先时路径只向下；
+// 两者相同时路径只包含一个节点。多父节点的层次结构中每一段都选择最短的路径。
+// 适合在安全报告中说明某个弱点与策略关注的类别之间的关系。
+//
+// 参数:
+// - fromID: string - 起点条目ID，支持ParseCWEID接受的所有格式
+// - toID: string - 终点条目ID
+//
+// 返回值:
+// - []*CWE: 包括起点和终点的节点序列
+// - error: 条目不存在时返回包装了ErrNotFound的错误，没有共同祖先时返回包装了ErrNoCommonAncestor的错误
+//
+// 使用示例:
+// ```go
+// path, err := registry.PathBetween("CWE-79", "CWE-707")
+//
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// fmt.Println(cwe.FormatPath(path, cwe.PathFormatOptions{})) // CWE-79 → CWE-74 → CWE-707
+// ```
+func (r *Registry) PathBetween(fromID, toID string) ([]*CWE, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	from, exists := r.Entries[normalizeEntryID(fromID)]
+	if !exists {
+		return nil, &notFoundError{id: fromID}
+	}
+	to, exists := r.Entries[normalizeEntryID(toID)]
+	if !exists {
+		return nil, &notFoundError{id: toID}
+	}
+
+	up, down := r.commonAncestorChains(from, to)
+	if up == nil {
+		return nil, fmt.Errorf("%w: %s和%s", ErrNoCommonAncestor, from.ID, to.ID)
+	}
+
+	// down从终点向上到共同祖先，去掉共同祖先后倒序接在up之后
+	path := make([]*CWE, 0, len(up)+len(down)-1)
+	path = append(path, up...)
+	for i := len(down) - 2; i >= 0; i-- {
+		path = append(path, down[i])
+	}
+	return path, nil
+}
+
+// PathFormatOptions 控制FormatPath的输出
+type PathFormatOptions struct {
+	// Separator 节点之间的分隔符，为空时使用DefaultPathSeparator
+	Separator string
+
+	// WithNames 为true时在ID后附加名称，如"CWE-79 (Cross-site Scripting)"
+	WithNames bool
+
+	// Locale 附加名称时使用的语言，为空时使用原文，见CWE.LocalizedName
+	Locale string
+}
+
+// FormatPath 将节点序列格式化为一行文本，如"CWE-79 → CWE-74 → CWE-707"
+//
+// 参数:
+// - path: []*CWE - PathBetween、GetPath等返回的节点序列
+// - opts: PathFormatOptions - 格式选项
+//
+// 返回值:
+// - string: 格式化后的路径，path为空时返回空字符串
+func FormatPath(path []*CWE, opts PathFormatOptions) string {
+	separator := opts.Separator
+	if separator == "" {
+		separator = DefaultPathSeparator
+	}
+
+	parts := make([]string, 0, len(path))
+	for _, node := range path {
+		if node == nil {
+			continue
+		}
+		part := node.ID
+		if name := node.LocalizedName(opts.Locale); opts.WithNames && name != "" {
+			part = fmt.Sprintf("%s (%s)", node.ID, name)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, separator)
+}
+
+// PathBetween 返回两个条目之间的路径，见Registry.PathBetween
+func (s *FrozenRegistry) PathBetween(fromID, toID string) ([]*CWE, error) {
+	return s.registry.PathBetween(fromID, toID)
+}
@@ -0,0 +1,70 @@
+package cwe
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRegistryPathBetween(t *testing.T) {
+	registry := newQueryTestRegistry()
+
+	tests := []struct {
+		from, to string
+		want     []string
+	}{
+		{"CWE-79", "CWE-1000", []string{"CWE-79", "CWE-20", "CWE-1000"}},
+		{"CWE-1000", "CWE-306", []string{"CWE-1000", "CWE-287", "CWE-306"}},
+		{"CWE-79", "CWE-306", []string{"CWE-79", "CWE-20", "CWE-1000", "CWE-287", "CWE-306"}},
+		{"79", "89", []string{"CWE-79", "CWE-20", "CWE-89"}},
+		{"CWE-89", "CWE-89", []string{"CWE-89"}},
+	}
+	for _, tt := range tests {
+		path, err := registry.PathBetween(tt.from, tt.to)
+		if err != nil {
+			t.Fatalf("PathBetween(%s, %s)失败: %v", tt.from, tt.to, err)
+		}
+		if got := cweIDsOf(path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("PathBetween(%s, %s) = %v, 期望 %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+
+	// 多父节点时选择最短的路径
+	registry.SetHierarchyMode(HierarchyMultiParent)
+	registry.AddChild("CWE-287", "CWE-89")
+	if path, _ := registry.PathBetween("CWE-89", "CWE-306"); !reflect.DeepEqual(cweIDsOf(path), []string{"CWE-89", "CWE-287", "CWE-306"}) {
+		t.Errorf("多父节点的路径不正确: %v", cweIDsOf(path))
+	}
+
+	registry.Register(NewCWE("CWE-1390", "Weak Authentication"))
+	if _, err := registry.PathBetween("CWE-79", "CWE-1390"); !errors.Is(err, ErrNoCommonAncestor) {
+		t.Errorf("没有共同祖先时应返回ErrNoCommonAncestor，实际为%v", err)
+	}
+	if _, err := registry.PathBetween("CWE-99999", "CWE-79"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("条目不存在时应返回ErrNotFound，实际为%v", err)
+	}
+}
+
+func TestFormatPath(t *testing.T) {
+	registry := newQueryTestRegistry()
+	registry.Entries["CWE-20"].SetTranslation("zh-CN", TranslationFieldName, "输入验证不恰当")
+	path, _ := registry.Freeze().PathBetween("CWE-79", "CWE-1000")
+
+	tests := []struct {
+		opts PathFormatOptions
+		want string
+	}{
+		{PathFormatOptions{}, "CWE-79 → CWE-20 → CWE-1000"},
+		{PathFormatOptions{Separator: " > "}, "CWE-79 > CWE-20 > CWE-1000"},
+		{PathFormatOptions{WithNames: true}, "CWE-79 (Cross-site Scripting) → CWE-20 (Improper Input Validation) → CWE-1000 (Research Concepts)"},
+		{PathFormatOptions{WithNames: true, Locale: "zh-CN", Separator: " / "}, "CWE-79 (Cross-site Scripting) / CWE-20 (输入验证不恰当) / CWE-1000 (Research Concepts)"},
+	}
+	for _, tt := range tests {
+		if got := FormatPath(path, tt.opts); got != tt.want {
+			t.Errorf("FormatPath(%+v) = %q, 期望 %q", tt.opts, got, tt.want)
+		}
+	}
+	if got := FormatPath(nil, PathFormatOptions{}); got != "" {
+		t.Errorf("空路径应返回空字符串，实际为%q", got)
+	}
+}
@@ -0,0 +1,188 @@
+package cwe
+
+import (
+	"regexp"
+	"strings"
+)
+
+// severityRanks 严重性级别从低到高的排序，键为NormalizeSeverityRule统一后的写法
+var severityRanks = map[string]int{
+	"Info":     1,
+	"Low":      2,
+	"Medium":   3,
+	"High":     4,
+	"Critical": 5,
+}
+
+// severityRank 返回严重性的级别，支持NormalizeSeverityRule识别的所有写法
+// 空值和不认识的写法返回0
+func severityRank(severity string) int {
+	normalized, exists := severityAliases[strings.ToLower(strings.TrimSpace(severity))]
+	if !exists {
+		return 0
+	}
+	return severityRanks[normalized]
+}
+
+// Predicate 判断条目是否满足查询条件
+type Predicate func(entry *CWE) bool
+
+// RegistryQuery 是对注册表的链式查询，通过Registry.Query创建
+//
+// 每个条件方法都返回查询本身，多个条件之间是"与"的关系。
+// 条件在调用All、IDs或Count时才会求值，因此同一个查询可以在注册表更新后重复执行。
+type RegistryQuery struct {
+	registry *Registry
+
+	// conditions 每次执行时调用以生成条件，使依赖注册表状态的条件(如子树)在执行时计算
+	conditions []func() Predicate
+}
+
+// Query 创建对注册表的链式查询
+//
+// 方法功能:
+// 用可组合的条件代替对Entries的手工遍历，结果按CWE编号排序且不含重复条目。
+// 不添加任何条件时返回所有条目。
+//
+// 返回值:
+// - *RegistryQuery: 新的查询
+//
+// 使用示例:
+// ```go
+// // 输入验证类别下、严重性至少为High、有缓解措施的叶子节点
+// query := registry.Query().UnderAncestor("CWE-20").SeverityAtLeast("High")
+// results := query.HasMitigations().Leaf().All()
+//
+// // 自定义条件
+// injections := registry.Query().NameMatches(regexp.MustCompile(`(?i)injection`)).IDs()
+// ```
+func (r *Registry) Query() *RegistryQuery {
+	return &RegistryQuery{registry: r}
+}
+
+// Where 添加自定义条件
+func (q *RegistryQuery) Where(predicate Predicate) *RegistryQuery {
+	return q.where(func() Predicate { return predicate })
+}
+
+// where 添加在执行时生成的条件
+func (q *RegistryQuery) where(condition func() Predicate) *RegistryQuery {
+	q.conditions = append(q.conditions, condition)
+	return q
+}
+
+// SeverityAtLeast 只保留严重性不低于level的条目
+//
+// 级别从低到高为Info、Low、Medium、High、Critical，支持NormalizeSeverityRule识别的所有写法(如"高"、"HIGH")。
+// 比较使用EffectiveSeverity返回的生效严重性，没有严重性或严重性无法识别的条目不满足条件；
+// level无法识别时没有条目满足条件。
+func (q *RegistryQuery) SeverityAtLeast(level string) *RegistryQuery {
+	minimum := severityRank(level)
+	registry := q.registry
+	return q.Where(func(entry *CWE) bool {
+		severity := entry.Severity
+		if registry.severityOverlay != nil {
+			if overlay, exists := registry.severityOverlay.Get(entry.ID); exists {
+				severity = overlay
+			}
+		}
+		rank := severityRank(severity)
+		return minimum > 0 && rank >= minimum
+	})
+}
+
+// Leaf 只保留没有子节点的条目
+func (q *RegistryQuery) Leaf() *RegistryQuery {
+	return q.Where(func(entry *CWE) bool {
+		return entry.IsLeaf()
+	})
+}
+
+// UnderAncestor 只保留位于指定条目子树中的条目，不包括该条目本身
+//
+// 子树沿Children展开，因此HierarchyMultiParent模式下通过任一父节点可达的条目都满足条件。
+// id支持ParseCWEID接受的所有格式，条目不存在时没有条目满足条件。
+func (q *RegistryQuery) UnderAncestor(id string) *RegistryQuery {
+	ancestorID := normalizeEntryID(id)
+	registry := q.registry
+	return q.where(func() Predicate {
+		descendants := make(map[*CWE]bool)
+		if ancestor, err := registry.GetByID(ancestorID); err == nil {
+			collectDescendants(ancestor, descendants)
+		}
+		return func(entry *CWE) bool {
+			return descendants[entry]
+		}
+	})
+}
+
+// HasMitigations 只保留至少有一条缓解措施的条目
+func (q *RegistryQuery) HasMitigations() *RegistryQuery {
+	return q.Where(func(entry *CWE) bool {
+		return len(entry.Mitigations) > 0
+	})
+}
+
+// NameMatches 只保留名称匹配正则表达式的条目
+func (q *RegistryQuery) NameMatches(pattern *regexp.Regexp) *RegistryQuery {
+	return q.Where(func(entry *CWE) bool {
+		return pattern.MatchString(entry.Name)
+	})
+}
+
+// All 执行查询，返回按CWE编号排序的结果
+func (q *RegistryQuery) All() []*CWE {
+	predicates := make([]Predicate, 0, len(q.conditions))
+	for _, condition := range q.conditions {
+		predicates = append(predicates, condition())
+	}
+
+	results := make([]*CWE, 0)
+	seen := make(map[*CWE]bool)
+	for _, entry := range q.registry.Snapshot() {
+		if seen[entry] {
+			continue
+		}
+		seen[entry] = true
+		if matchAll(predicates, entry) {
+			results = append(results, entry)
+		}
+	}
+	return results
+}
+
+// IDs 执行查询，返回按CWE编号排序的结果ID
+func (q *RegistryQuery) IDs() []string {
+	results := q.All()
+	ids := make([]string, 0, len(results))
+	for _, entry := range results {
+		ids = append(ids, entry.ID)
+	}
+	return ids
+}
+
+// Count 执行查询，返回结果数
+func (q *RegistryQuery) Count() int {
+	return len(q.All())
+}
+
+// matchAll 判断条目是否满足所有条件
+func matchAll(predicates []Predicate, entry *CWE) bool {
+	for _, predicate := range predicates {
+		if !predicate(entry) {
+			return false
+		}
+	}
+	return true
+}
+
+// collectDescendants 将node的所有后代加入set，已访问的节点不重复展开
+func collectDescendants(node *CWE, set map[*CWE]bool) {
+	for _, child := range node.Children {
+		if set[child] {
+			continue
+		}
+		set[child] = true
+		collectDescendants(child, set)
+	}
+}
@@ -0,0 +1,103 @@
+package cwe
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// newQueryTestRegistry 创建查询测试用的注册表
+//
+//	CWE-1000
+//	├── CWE-20 (High)
+//	│   ├── CWE-79 (高, 有缓解措施)
+//	│   └── CWE-89 (Critical, 有缓解措施)
+//	└── CWE-287 (Medium)
+//	    └── CWE-306 (Low)
+func newQueryTestRegistry() *Registry {
+	return buildTestRegistry([]testNode{
+		{id: "CWE-1000", name: "Research Concepts"},
+		{id: "CWE-20", name: "Improper Input Validation", severity: "High", parent: "CWE-1000"},
+		{id: "CWE-79", name: "Cross-site Scripting", severity: "高", parent: "CWE-20", mitigations: []string{"Encode output"}},
+		{id: "CWE-89", name: "SQL Injection", severity: "Critical", parent: "CWE-20", mitigations: []string{"Use prepared statements"}},
+		{id: "CWE-287", name: "Improper Authentication", severity: "Medium", parent: "CWE-1000"},
+		{id: "CWE-306", name: "Missing Authentication for Critical Function", severity: "Low", parent: "CWE-287"},
+	})
+}
+
+func TestRegistryQuery(t *testing.T) {
+	registry := newQueryTestRegistry()
+
+	tests := []struct {
+		name  string
+		query *RegistryQuery
+		want  string
+	}{
+		{"全部", registry.Query(), "CWE-20,CWE-79,CWE-89,CWE-287,CWE-306,CWE-1000"},
+		{"叶子节点", registry.Query().Leaf(), "CWE-79,CWE-89,CWE-306"},
+		{"严重性至少High", registry.Query().SeverityAtLeast("High"), "CWE-20,CWE-79,CWE-89"},
+		{"严重性中文写法", registry.Query().SeverityAtLeast("中"), "CWE-20,CWE-79,CWE-89,CWE-287"},
+		{"无法识别的级别", registry.Query().SeverityAtLeast("unknown"), ""},
+		{"子树", registry.Query().UnderAncestor("20"), "CWE-79,CWE-89"},
+		{"不存在的祖先", registry.Query().UnderAncestor("CWE-9999"), ""},
+		{"有缓解措施", registry.Query().HasMitigations(), "CWE-79,CWE-89"},
+		{"名称匹配", registry.Query().NameMatches(regexp.MustCompile(`(?i)authentication`)), "CWE-287,CWE-306"},
+		{"组合条件", registry.Query().UnderAncestor("CWE-1000").Leaf().SeverityAtLeast("Low").NameMatches(regexp.MustCompile(`Auth`)), "CWE-306"},
+		{"自定义条件", registry.Query().Where(func(entry *CWE) bool { return strings.HasPrefix(entry.ID, "CWE-2") }), "CWE-20,CWE-287"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(tt.query.IDs(), ","); got != tt.want {
+				t.Errorf("IDs() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryQuerySeverityOverlay(t *testing.T) {
+	registry := newQueryTestRegistry()
+	overlay := NewSeverityOverlay()
+	overlay.Set("CWE-306", "Critical")
+	registry.SetSeverityOverlay(overlay)
+
+	if got := strings.Join(registry.Query().SeverityAtLeast("Critical").IDs(), ","); got != "CWE-89,CWE-306" {
+		t.Errorf("SeverityAtLeast应使用生效的严重性，实际 %s", got)
+	}
+}
+
+func TestRegistryQueryReevaluates(t *testing.T) {
+	registry := newQueryTestRegistry()
+	query := registry.Query().UnderAncestor("CWE-287")
+	if query.Count() != 1 {
+		t.Fatalf("期望1个结果，实际 %d", query.Count())
+	}
+
+	// 注册表更新后重新执行同一个查询
+	child := NewCWE("CWE-798", "Use of Hard-coded Credentials")
+	registry.Register(child)
+	registry.Entries["CWE-306"].AddChild(child)
+
+	if got := strings.Join(query.IDs(), ","); got != "CWE-306,CWE-798" {
+		t.Errorf("查询应在执行时重新计算子树，实际 %s", got)
+	}
+}
+
+func TestRegistryQueryDeduplicates(t *testing.T) {
+	registry := newQueryTestRegistry()
+	// 同一条目以两个键注册
+	registry.Entries["79"] = registry.Entries["CWE-79"]
+
+	if got := registry.Query().HasMitigations().Count(); got != 2 {
+		t.Errorf("结果不应包含重复条目，实际 %d 个", got)
+	}
+}
+
+func TestSeverityRank(t *testing.T) {
+	if severityRank("critical") <= severityRank("高") || severityRank("High") <= severityRank("medium") {
+		t.Error("严重性级别顺序不正确")
+	}
+	if severityRank("") != 0 || severityRank("urgent") != 0 {
+		t.Error("空值和无法识别的严重性级别应为0")
+	}
+}
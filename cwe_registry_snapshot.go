@@ -0,0 +1,360 @@
+package cwe
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// SnapshotEntry 是注册表快照中单个CWE条目的扁平表示
+// 子节点以ID引用的形式保存，因此可以安全地序列化为JSON而不会因Parent字段产生循环引用
+// EffectiveSeverity仅在通过Registry.SnapshotWithSeverity以SeverityBoth模式导出时填充
+type SnapshotEntry struct {
+	ID                string        `json:"id"`
+	Name              string        `json:"name"`
+	Description       string        `json:"description,omitempty"`
+	URL               string        `json:"url,omitempty"`
+	Severity          string        `json:"severity,omitempty"`
+	EffectiveSeverity string        `json:"effective_severity,omitempty"`
+	Status            string        `json:"status,omitempty"`
+	Abstraction       string        `json:"abstraction,omitempty"`
+	Language          string        `json:"language,omitempty"`
+	Version           string        `json:"version,omitempty"`
+	Mitigations       []string      `json:"mitigations,omitempty"`
+	Examples          []string      `json:"examples,omitempty"`
+	Children          []string      `json:"children,omitempty"`
+	Relations         []CWERelation `json:"relations,omitempty"`
+
+	AlternateTerms []string               `json:"alternate_terms,omitempty"`
+	Translations   map[string]Translation `json:"translations,omitempty"`
+
+	// MitigationDetails 类型化的缓解措施，见CWE.MitigationDetails
+	MitigationDetails []CWEMitigation `json:"mitigation_details,omitempty"`
+
+	// MappingNotes 映射注释，见CWE.MappingNotes
+	MappingNotes *CWEMappingNotes `json:"mapping_notes,omitempty"`
+
+	// ObservedExamples 已观察到的实例，见CWE.ObservedExamples
+	ObservedExamples []CWEObservedExample `json:"observed_examples,omitempty"`
+
+	// LastModified 条目最后一次提交或修改的日期，未知时省略
+	LastModified *time.Time `json:"last_modified,omitempty"`
+
+	// FetchedAt 条目从API获取的时间，见CWE.FetchedAt，未知时省略
+	FetchedAt *time.Time `json:"fetched_at,omitempty"`
+}
+
+// RegistrySnapshot 是注册表的可序列化快照
+// 保存所有条目、子节点引用以及根节点ID，可通过ToRegistry恢复为Registry
+type RegistrySnapshot struct {
+	// RootID 根节点ID，注册表没有根节点时为空
+	RootID string `json:"root_id,omitempty"`
+
+	// Entries 所有条目，按ID排序
+	Entries []SnapshotEntry `json:"entries"`
+
+	// ViewChildren 按视图记录的父子关系: 视图ID到父节点ID再到子节点ID列表的映射，见Registry.AddViewChild
+	ViewChildren map[string]map[string][]string `json:"view_children,omitempty"`
+
+	// HierarchyMode 注册表的层次结构模式，见Registry.SetHierarchyMode
+	HierarchyMode HierarchyMode `json:"hierarchy_mode,omitempty"`
+
+	// Parents 多父节点模式下记录的所有父节点: 条目ID到按添加顺序排列的父节点ID列表的映射，见Registry.Parents
+	Parents map[string][]string `json:"parents,omitempty"`
+
+	// Version 注册表的CWE内容版本，见Registry.Version
+	Version string `json:"version,omitempty"`
+
+	// MixedVersions 注册表是否允许混合不同版本的条目，见Registry.AllowMixedVersions
+	MixedVersions bool `json:"mixed_versions,omitempty"`
+
+	// Annotations 用户注解，以条目ID为键，与条目的MITRE字段分开保存，见Registry.SetTag
+	Annotations map[string]Annotation `json:"annotations,omitempty"`
+
+	// ChangeLog UpdateFrom记录的变更，见Registry.ChangesSince
+	ChangeLog []ChangeSet `json:"change_log,omitempty"`
+}
+
+// NewRegistrySnapshot 为注册表创建快照
+//
+// 方法功能:
+// 将注册表中的所有条目转换为扁平的SnapshotEntry，条目按ID排序以保证输出稳定。
+// 快照与注册表之间不共享切片，之后修改注册表不会影响已创建的快照。
+//
+// 参数:
+// - registry: *Registry - 要创建快照的注册表
+//
+// 返回值:
+// - *RegistrySnapshot: 注册表快照
+func NewRegistrySnapshot(registry *Registry) *RegistrySnapshot {
+	snapshot := &RegistrySnapshot{
+		Entries: make([]SnapshotEntry, 0, len(registry.Entries)),
+	}
+	if registry.Root != nil {
+		snapshot.RootID = registry.Root.ID
+	}
+
+	ids := make([]string, 0, len(registry.Entries))
+	for id := range registry.Entries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		snapshot.Entries = append(snapshot.Entries, newSnapshotEntry(registry.Entries[id]))
+	}
+
+	registry.mutex.RLock()
+	snapshot.ViewChildren = copyViewChildren(registry.viewChildren, nil)
+	snapshot.HierarchyMode = registry.hierarchyMode
+	snapshot.Parents = copyParents(registry.parents)
+	snapshot.Version = registry.version
+	snapshot.MixedVersions = registry.allowMixedVersions
+	snapshot.Annotations = copyAnnotations(registry.annotations, nil)
+	snapshot.ChangeLog = copyChangeLog(registry.changeLog)
+	registry.mutex.RUnlock()
+
+	return snapshot
+}
+
+// Snapshot 返回注册表中所有条目的有序切片
+//
+// 方法功能:
+// 在读锁下一次性复制所有条目，按CWE编号升序排列。返回的切片是调用方独有的，
+// 之后注册的条目不会出现在其中，因此可以在后台构建仍在通过Register添加条目时安全地遍历，
+// 而不必自己复制Entries映射。
+// 切片中的元素仍是注册表中的CWE对象，遍历时不应修改它们。
+//
+// 与NewRegistrySnapshot不同，Snapshot不做序列化转换，适合在内存中快速遍历。
+//
+// 返回值:
+// - []*CWE: 按CWE编号排序的条目
+//
+// 使用示例:
+// ```go
+//
+//	go func() { // 后台填充注册表
+//	    for _, entry := range fetched {
+//	        registry.Register(entry)
+//	    }
+//	}()
+//
+//	for _, entry := range registry.Snapshot() {
+//	    fmt.Println(entry.ID, entry.Name)
+//	}
+//
+// ```
+func (r *Registry) Snapshot() []*CWE {
+	r.mutex.RLock()
+	entries := make([]*CWE, 0, len(r.Entries))
+	for _, entry := range r.Entries {
+		entries = append(entries, entry)
+	}
+	r.mutex.RUnlock()
+
+	sortByCWEID(entries)
+	return entries
+}
+
+// toCWE 根据快照条目创建新的CWE对象，不建立父子关系
+func (e SnapshotEntry) toCWE() *CWE {
+	cwe := NewCWE(e.ID, e.Name)
+	cwe.Description = e.Description
+	cwe.URL = e.URL
+	cwe.Severity = e.Severity
+	cwe.Status = e.Status
+	cwe.Abstraction = e.Abstraction
+	cwe.Language = e.Language
+	cwe.Version = e.Version
+	cwe.Mitigations = append(cwe.Mitigations, e.Mitigations...)
+	cwe.MitigationDetails = copyMitigations(e.MitigationDetails)
+	cwe.MappingNotes = copyMappingNotes(e.MappingNotes)
+	cwe.Examples = append(cwe.Examples, e.Examples...)
+	cwe.ObservedExamples = append([]CWEObservedExample(nil), e.ObservedExamples...)
+	cwe.Relations = append([]CWERelation(nil), e.Relations...)
+	cwe.AlternateTerms = append([]string(nil), e.AlternateTerms...)
+	cwe.Translations = copyTranslations(e.Translations)
+	if e.LastModified != nil {
+		cwe.LastModified = *e.LastModified
+	}
+	if e.FetchedAt != nil {
+		cwe.FetchedAt = *e.FetchedAt
+	}
+	return cwe
+}
+
+// newSnapshotEntry 将单个CWE转换为快照条目
+func newSnapshotEntry(cwe *CWE) SnapshotEntry {
+	entry := SnapshotEntry{
+		ID:          cwe.ID,
+		Name:        cwe.Name,
+		Description: cwe.Description,
+		URL:         cwe.URL,
+		Severity:    cwe.Severity,
+		Status:      cwe.Status,
+		Abstraction: cwe.Abstraction,
+		Language:    cwe.Language,
+		Version:     cwe.Version,
+		Mitigations: append([]string(nil), cwe.Mitigations...),
+		Examples:    append([]string(nil), cwe.Examples...),
+		Relations:   append([]CWERelation(nil), cwe.Relations...),
+
+		AlternateTerms: append([]string(nil), cwe.AlternateTerms...),
+		Translations:   copyTranslations(cwe.Translations),
+
+		MitigationDetails: copyMitigations(cwe.MitigationDetails),
+		MappingNotes:      copyMappingNotes(cwe.MappingNotes),
+		ObservedExamples:  append([]CWEObservedExample(nil), cwe.ObservedExamples...),
+	}
+	if !cwe.LastModified.IsZero() {
+		lastModified := cwe.LastModified
+		entry.LastModified = &lastModified
+	}
+	if !cwe.FetchedAt.IsZero() {
+		fetchedAt := cwe.FetchedAt
+		entry.FetchedAt = &fetchedAt
+	}
+	if len(cwe.Children) > 0 {
+		entry.Children = make([]string, 0, len(cwe.Children))
+		for _, child := range cwe.Children {
+			entry.Children = append(entry.Children, child.ID)
+		}
+	}
+	return entry
+}
+
+// ToRegistry 从快照恢复注册表
+//
+// 方法功能:
+// 根据快照中的条目创建新的CWE对象并注册，然后按子节点引用重建父子关系和根节点。
+//
+// 返回值:
+// - *Registry: 恢复出的注册表
+// - error: 条目缺少ID、ID重复、引用了不存在的子节点或根节点不存在时返回错误，
+// 条目的版本与快照的版本不同且快照不允许混合版本时返回包装了ErrVersionMismatch的错误，
+// 注解引用了不存在的条目时返回错误
+func (s *RegistrySnapshot) ToRegistry() (*Registry, error) {
+	registry := NewRegistry()
+	registry.SetVersion(s.Version)
+	registry.AllowMixedVersions(s.MixedVersions)
+
+	for _, entry := range s.Entries {
+		if entry.ID == "" {
+			return nil, errors.New("entry without ID found")
+		}
+
+		if err := registry.Register(entry.toCWE()); err != nil {
+			return nil, err
+		}
+	}
+
+	// 以SeverityBoth模式导出的快照包含覆盖值，恢复为覆盖层
+	var overlay *SeverityOverlay
+	for _, entry := range s.Entries {
+		if entry.EffectiveSeverity == "" {
+			continue
+		}
+		if overlay == nil {
+			overlay = NewSeverityOverlay()
+		}
+		if err := overlay.Set(entry.ID, entry.EffectiveSeverity); err != nil {
+			return nil, err
+		}
+	}
+	registry.SetSeverityOverlay(overlay)
+
+	for _, entry := range s.Entries {
+		parent := registry.Entries[entry.ID]
+		for _, childID := range entry.Children {
+			child, exists := registry.Entries[childID]
+			if !exists {
+				return nil, fmt.Errorf("子节点%s未注册", childID)
+			}
+			parent.AddChild(child)
+		}
+	}
+
+	// Children只能还原一个Parent(最后添加的父节点)，按记录恢复多父节点，Parent指向第一个父节点
+	registry.hierarchyMode = s.HierarchyMode
+	for id, parentIDs := range s.Parents {
+		entry, exists := registry.Entries[id]
+		if !exists {
+			return nil, fmt.Errorf("记录父节点的条目%s未注册", id)
+		}
+		for _, parentID := range parentIDs {
+			if _, exists := registry.Entries[parentID]; !exists {
+				return nil, fmt.Errorf("条目%s的父节点%s未注册", id, parentID)
+			}
+		}
+		if len(parentIDs) > 0 {
+			entry.Parent = registry.Entries[parentIDs[0]]
+		}
+	}
+	registry.parents = copyParents(s.Parents)
+
+	if s.RootID != "" {
+		root, exists := registry.Entries[s.RootID]
+		if !exists {
+			return nil, fmt.Errorf("根节点%s未注册", s.RootID)
+		}
+		registry.Root = root
+	}
+
+	for viewID, children := range s.ViewChildren {
+		for parentID, childIDs := range children {
+			for _, childID := range childIDs {
+				if err := registry.AddViewChild(viewID, parentID, childID); err != nil {
+					return nil, fmt.Errorf("恢复视图%s的父子关系失败: %w", viewID, err)
+				}
+			}
+		}
+	}
+
+	if err := registry.restoreAnnotations(s.Annotations); err != nil {
+		return nil, err
+	}
+	registry.changeLog = copyChangeLog(s.ChangeLog)
+
+	return registry, nil
+}
+
+// ExportToPrettyJSON 将注册表导出为便于人工审阅的JSON
+//
+// 方法功能:
+// 以RegistrySnapshot的格式输出，条目按CWE编号升序排列(CWE-20排在CWE-100之前)，
+// 每个条目的字段顺序固定，使用两个空格缩进并以换行结尾，HTML字符不转义。
+// 相同的注册表总是产生逐字节相同的输出，适合提交到仓库中并在代码评审时查看差异。
+// 输出可以解码为RegistrySnapshot后通过ToRegistry恢复。
+//
+// 返回值:
+// - []byte: 格式化后的JSON数据
+// - error: 序列化失败时返回错误
+//
+// 使用示例:
+// ```go
+// data, err := registry.ExportToPrettyJSON()
+//
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// os.WriteFile("third_party/cwe/cwe-1000.json", data, 0644)
+// ```
+func (r *Registry) ExportToPrettyJSON() ([]byte, error) {
+	snapshot := NewRegistrySnapshot(r)
+	sort.SliceStable(snapshot.Entries, func(i, j int) bool {
+		return compareCWEIDs(snapshot.Entries[i].ID, snapshot.Entries[j].ID) < 0
+	})
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(snapshot); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,178 @@
+package cwe
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRegistrySnapshotRoundTrip(t *testing.T) {
+	registry := NewRegistry()
+	root := NewCWE("CWE-1000", "Research View")
+	child := NewCWE("CWE-79", "Cross-site Scripting")
+	child.Severity = "High"
+	child.Mitigations = append(child.Mitigations, "输出编码")
+	registry.Register(root)
+	registry.Register(child)
+	root.AddChild(child)
+	registry.Root = root
+
+	// 带有Parent引用的注册表也能通过快照序列化
+	data, err := json.Marshal(NewRegistrySnapshot(registry))
+	if err != nil {
+		t.Fatalf("快照序列化失败: %v", err)
+	}
+
+	var snapshot RegistrySnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatalf("快照反序列化失败: %v", err)
+	}
+
+	restored, err := snapshot.ToRegistry()
+	if err != nil {
+		t.Fatalf("ToRegistry失败: %v", err)
+	}
+
+	if restored.Root == nil || restored.Root.ID != "CWE-1000" {
+		t.Fatalf("根节点未正确恢复: %v", restored.Root)
+	}
+	restoredChild := restored.Entries["CWE-79"]
+	if restoredChild.Parent != restored.Root {
+		t.Error("子节点的Parent未正确恢复")
+	}
+	if restoredChild.Severity != "High" || len(restoredChild.Mitigations) != 1 {
+		t.Errorf("子节点字段未正确恢复: %+v", restoredChild)
+	}
+
+	// 快照不应与原注册表共享切片
+	child.Mitigations[0] = "已修改"
+	if snapshot.Entries[1].Mitigations[0] != "输出编码" {
+		t.Error("修改原注册表不应影响快照")
+	}
+}
+
+func TestRegistrySnapshotErrors(t *testing.T) {
+	missingChild := &RegistrySnapshot{
+		Entries: []SnapshotEntry{{ID: "CWE-1", Children: []string{"CWE-2"}}},
+	}
+	if _, err := missingChild.ToRegistry(); err == nil {
+		t.Error("引用不存在的子节点应返回错误")
+	}
+
+	missingRoot := &RegistrySnapshot{RootID: "CWE-2", Entries: []SnapshotEntry{{ID: "CWE-1"}}}
+	if _, err := missingRoot.ToRegistry(); err == nil {
+		t.Error("根节点不存在应返回错误")
+	}
+
+	noID := &RegistrySnapshot{Entries: []SnapshotEntry{{Name: "无ID"}}}
+	if _, err := noID.ToRegistry(); err == nil {
+		t.Error("无ID条目应返回错误")
+	}
+}
+
+func TestExportToPrettyJSON(t *testing.T) {
+	registry := NewRegistry()
+	root := NewCWE("CWE-1000", "Research <Concepts>")
+	child := NewCWE("CWE-20", "Input Validation")
+	leaf := NewCWE("CWE-100", "Deprecated")
+	registry.Register(leaf)
+	registry.Register(root)
+	registry.Register(child)
+	root.AddChild(child)
+	registry.Root = root
+
+	data, err := registry.ExportToPrettyJSON()
+	if err != nil {
+		t.Fatalf("ExportToPrettyJSON失败: %v", err)
+	}
+
+	expected := `{
+  "root_id": "CWE-1000",
+  "entries": [
+    {
+      "id": "CWE-20",
+      "name": "Input Validation"
+    },
+    {
+      "id": "CWE-100",
+      "name": "Deprecated"
+    },
+    {
+      "id": "CWE-1000",
+      "name": "Research <Concepts>",
+      "children": [
+        "CWE-20"
+      ]
+    }
+  ]
+}
+`
+	if string(data) != expected {
+		t.Errorf("输出不符合预期:\n%s", data)
+	}
+
+	again, _ := registry.ExportToPrettyJSON()
+	if string(again) != string(data) {
+		t.Error("相同的注册表应产生相同的输出")
+	}
+
+	var snapshot RegistrySnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatalf("输出应能解码为RegistrySnapshot: %v", err)
+	}
+	restored, err := snapshot.ToRegistry()
+	if err != nil || len(restored.Entries) != 3 || restored.Root.ID != "CWE-1000" {
+		t.Errorf("恢复注册表失败: %v", err)
+	}
+}
+
+func TestRegistrySnapshotEntries(t *testing.T) {
+	registry := NewRegistry()
+	for _, id := range []string{"CWE-100", "CWE-20", "CWE-79"} {
+		registry.Register(NewCWE(id, id))
+	}
+
+	entries := registry.Snapshot()
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+	}
+	if strings.Join(ids, ",") != "CWE-20,CWE-79,CWE-100" {
+		t.Errorf("Snapshot应按编号排序，实际为%v", ids)
+	}
+
+	// 之后注册的条目不影响已取得的快照
+	registry.Register(NewCWE("CWE-1", "new"))
+	if len(entries) != 3 || len(registry.Snapshot()) != 4 {
+		t.Error("快照不应随注册表变化")
+	}
+}
+
+func TestRegistrySnapshotConcurrentRegister(t *testing.T) {
+	registry := NewRegistry()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 500; i++ {
+			registry.Register(NewCWE(fmt.Sprintf("CWE-%d", i), "entry"))
+		}
+	}()
+
+	for i := 0; i < 50; i++ {
+		entries := registry.Snapshot()
+		for j := 1; j < len(entries); j++ {
+			if compareCWEIDs(entries[j-1].ID, entries[j].ID) >= 0 {
+				t.Fatalf("快照未排序: %s, %s", entries[j-1].ID, entries[j].ID)
+			}
+		}
+	}
+	wg.Wait()
+
+	if len(registry.Snapshot()) != 500 {
+		t.Errorf("应有500个条目，实际为%d", len(registry.Snapshot()))
+	}
+}
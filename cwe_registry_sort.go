@@ -0,0 +1,52 @@
+package cwe
+
+import "sort"
+
+// ByNumericID 是按CWE编号升序排列的比较函数，也是SortChildren的默认顺序
+// CWE-20排在CWE-100之前；无法解析编号的ID排在最后，按字符串顺序排列
+func ByNumericID(a, b *CWE) bool {
+	return compareCWEIDs(a.ID, b.ID) < 0
+}
+
+// SortChildren 对注册表中每个条目的子节点排序
+//
+// 方法功能:
+// 子节点的顺序默认取决于API的响应顺序、并发获取的完成顺序或map的遍历顺序，
+// 导致相同的数据导出为快照、XML或图表时结果不同。SortChildren按less稳定排序所有条目(包括Root)的Children，
+// 使导出的文件可以重复生成并直接比较。
+// BuildCWETreeWithView和BuildCWETreeResumable在构建完成后会按ByNumericID排序。
+//
+// 参数:
+// - less: func(a, b *CWE) bool - 比较函数，为nil时使用ByNumericID
+//
+// 使用示例:
+// ```go
+// // 按名称排序后导出
+//
+//	registry.SortChildren(func(a, b *cwe.CWE) bool {
+//	    return a.Name < b.Name
+//	})
+//
+// ```
+func (r *Registry) SortChildren(less func(a, b *CWE) bool) {
+	if less == nil {
+		less = ByNumericID
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	sortChildren := func(entry *CWE) {
+		sort.SliceStable(entry.Children, func(i, j int) bool {
+			return less(entry.Children[i], entry.Children[j])
+		})
+	}
+	for _, entry := range r.Entries {
+		sortChildren(entry)
+	}
+	if r.Root != nil {
+		if _, registered := r.Entries[r.Root.ID]; !registered {
+			sortChildren(r.Root)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package cwe
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func childIDs(entry *CWE) string {
+	ids := make([]string, 0, len(entry.Children))
+	for _, child := range entry.Children {
+		ids = append(ids, child.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestRegistrySortChildren(t *testing.T) {
+	registry := NewRegistry()
+	root := NewCWE("CWE-1000", "Root")
+	registry.Register(root)
+	registry.Root = root
+	for _, id := range []string{"CWE-100", "CWE-20", "CWE-3", "CWE-X"} {
+		child := NewCWE(id, strings.ToLower(id))
+		registry.Register(child)
+		root.AddChild(child)
+	}
+	nested := NewCWE("CWE-79", "b")
+	registry.Register(nested)
+	registry.Entries["CWE-20"].AddChild(NewCWE("CWE-80", "a"))
+	registry.Entries["CWE-20"].AddChild(nested)
+
+	registry.SortChildren(nil)
+	if got := childIDs(root); got != "CWE-3,CWE-20,CWE-100,CWE-X" {
+		t.Errorf("默认应按编号排序，实际 %s", got)
+	}
+	if got := childIDs(registry.Entries["CWE-20"]); got != "CWE-79,CWE-80" {
+		t.Errorf("所有条目的子节点都应排序，实际 %s", got)
+	}
+
+	registry.SortChildren(func(a, b *CWE) bool { return a.Name < b.Name })
+	if got := childIDs(registry.Entries["CWE-20"]); got != "CWE-80,CWE-79" {
+		t.Errorf("应使用自定义比较函数，实际 %s", got)
+	}
+}
+
+func TestRegistrySortChildrenUnregisteredRoot(t *testing.T) {
+	registry := NewRegistry()
+	registry.Root = NewCWE("CWE-1000", "Root")
+	registry.Root.AddChild(NewCWE("CWE-89", "b"))
+	registry.Root.AddChild(NewCWE("CWE-79", "a"))
+
+	registry.SortChildren(nil)
+	if got := childIDs(registry.Root); got != "CWE-79,CWE-89" {
+		t.Errorf("不在Entries中的Root也应排序，实际 %s", got)
+	}
+}
+
+func TestBuildCWETreeSortsChildren(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/cwe/view/CWE-1000":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"views": []map[string]interface{}{{"id": "CWE-1000", "name": "Research Concepts"}},
+			})
+		case r.URL.Path == "/cwe/CWE-1000/children":
+			// API返回的顺序不是编号顺序
+			json.NewEncoder(w).Encode([]string{"284", "79", "20"})
+		case strings.HasSuffix(r.URL.Path, "/children"):
+			json.NewEncoder(w).Encode([]string{})
+		case strings.HasPrefix(r.URL.Path, "/cwe/weakness/"):
+			id := strings.TrimPrefix(r.URL.Path, "/cwe/weakness/")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"weaknesses": []map[string]interface{}{{"id": id, "name": id}},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+
+	registry, err := fetcher.BuildCWETreeWithView("1000")
+	if err != nil {
+		t.Fatalf("BuildCWETreeWithView失败: %v", err)
+	}
+	if got := childIDs(registry.Root); got != "CWE-20,CWE-79,CWE-284" {
+		t.Errorf("BuildCWETreeWithView应按编号排序子节点，实际 %s", got)
+	}
+
+	registry, err = fetcher.BuildCWETreeResumable("1000", nil, ResumableBuildOptions{})
+	if err != nil {
+		t.Fatalf("BuildCWETreeResumable失败: %v", err)
+	}
+	if got := childIDs(registry.Root); got != "CWE-20,CWE-79,CWE-284" {
+		t.Errorf("BuildCWETreeResumable应按编号排序子节点，实际 %s", got)
+	}
+}
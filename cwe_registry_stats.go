@@ -0,0 +1,210 @@
+package cwe
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultLargestSubtrees Stats在LargestSubtrees中列出的子树数量
+const DefaultLargestSubtrees = 10
+
+// StatsUnknown 是Stats按严重性和抽象级别计数时，字段为空的条目使用的键
+const StatsUnknown = "Unknown"
+
+// RegistryStats 是注册表层次结构的统计信息，由Registry.Stats计算
+type RegistryStats struct {
+	// Entries 条目总数
+	Entries int `json:"entries"`
+
+	// Roots 遍历的根节点数：设置了Root时为1，否则为没有父节点的条目数
+	Roots int `json:"roots"`
+
+	// Unreachable 从根节点出发无法到达的条目数，这些条目不计入深度统计
+	Unreachable int `json:"unreachable"`
+
+	// MaxDepth 最大深度，根节点的深度为0
+	MaxDepth int `json:"max_depth"`
+
+	// AverageDepth 可到达条目的平均深度
+	AverageDepth float64 `json:"average_depth"`
+
+	// NodesPerLevel 每个深度的条目数，下标为深度；多父节点的条目按最浅的深度计算
+	NodesPerLevel []int `json:"nodes_per_level"`
+
+	// Leaves 没有子节点的条目数
+	Leaves int `json:"leaves"`
+
+	// LeafPercentage 叶子节点占所有条目的百分比(0-100)
+	LeafPercentage float64 `json:"leaf_percentage"`
+
+	// BySeverity 按生效的严重性(见EffectiveSeverity)统计的条目数
+	// 能识别的写法统一为"Critical"、"High"等，为空时计入StatsUnknown
+	BySeverity map[string]int `json:"by_severity"`
+
+	// ByAbstraction 按抽象级别统计的条目数，为空时计入StatsUnknown
+	ByAbstraction map[string]int `json:"by_abstraction"`
+
+	// LargestSubtrees 后代最多的非根条目，按后代数从多到少排列，最多DefaultLargestSubtrees个
+	LargestSubtrees []SubtreeSize `json:"largest_subtrees"`
+}
+
+// SubtreeSize 是一个条目及其后代数量
+type SubtreeSize struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+
+	// Descendants 后代数量，不包括条目本身
+	Descendants int `json:"descendants"`
+}
+
+// Stats 计算注册表层次结构的统计信息
+//
+// 方法功能:
+// 从Root(未设置时为所有没有父节点的条目)出发广度优先遍历，统计深度分布、每层的节点数、
+// 叶子节点比例、按严重性和抽象级别的计数以及后代最多的子树，
+// 可用于报告扫描规则对CWE的覆盖情况或比较不同视图的形状。
+//
+// 返回值:
+// - *RegistryStats: 统计信息，可以直接序列化为JSON
+//
+// 使用示例:
+// ```go
+// stats := registry.Stats()
+// fmt.Printf("%d个条目，最大深度%d，叶子节点占%.1f%%\n", stats.Entries, stats.MaxDepth, stats.LeafPercentage)
+//
+//	for depth, count := range stats.NodesPerLevel {
+//	    fmt.Printf("第%d层: %d\n", depth, count)
+//	}
+//
+// ```
+func (r *Registry) Stats() *RegistryStats {
+	roots := r.walkRoots()
+	entries := r.Snapshot()
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	stats := &RegistryStats{
+		Entries:       len(entries),
+		Roots:         len(roots),
+		NodesPerLevel: make([]int, 0),
+		BySeverity:    make(map[string]int),
+		ByAbstraction: make(map[string]int),
+	}
+
+	depths := make(map[*CWE]int)
+	queue := make([]*CWE, 0, len(roots))
+	for _, root := range roots {
+		if _, visited := depths[root]; !visited {
+			depths[root] = 0
+			queue = append(queue, root)
+		}
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, child := range node.Children {
+			if _, visited := depths[child]; !visited {
+				depths[child] = depths[node] + 1
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	reachable, totalDepth := 0, 0
+	for _, entry := range entries {
+		if depth, visited := depths[entry]; visited {
+			for len(stats.NodesPerLevel) <= depth {
+				stats.NodesPerLevel = append(stats.NodesPerLevel, 0)
+			}
+			stats.NodesPerLevel[depth]++
+			reachable++
+			totalDepth += depth
+		} else {
+			stats.Unreachable++
+		}
+
+		if len(entry.Children) == 0 {
+			stats.Leaves++
+		}
+		stats.BySeverity[statsSeverity(r.effectiveSeverityOf(entry))]++
+		stats.ByAbstraction[statsKey(entry.Abstraction)]++
+	}
+
+	if len(stats.NodesPerLevel) > 0 {
+		stats.MaxDepth = len(stats.NodesPerLevel) - 1
+	}
+	if reachable > 0 {
+		stats.AverageDepth = float64(totalDepth) / float64(reachable)
+	}
+	stats.LeafPercentage = percentage(stats.Leaves, stats.Entries)
+	stats.LargestSubtrees = largestSubtrees(entries, roots, DefaultLargestSubtrees)
+	return stats
+}
+
+// effectiveSeverityOf 返回条目考虑严重性覆盖层后的严重性，调用方需持有读锁
+func (r *Registry) effectiveSeverityOf(entry *CWE) string {
+	if r.severityOverlay != nil {
+		if severity, exists := r.severityOverlay.Get(entry.ID); exists {
+			return severity
+		}
+	}
+	return entry.Severity
+}
+
+// statsSeverity 返回严重性在统计中使用的键，能识别的写法统一为NormalizeSeverityRule的结果
+func statsSeverity(severity string) string {
+	if normalized, exists := severityAliases[strings.ToLower(strings.TrimSpace(severity))]; exists {
+		return normalized
+	}
+	return statsKey(severity)
+}
+
+// statsKey 返回字段在统计中使用的键，为空时返回StatsUnknown
+func statsKey(value string) string {
+	if value = strings.TrimSpace(value); value != "" {
+		return value
+	}
+	return StatsUnknown
+}
+
+// largestSubtrees 返回后代最多的n个非根条目，后代数相同时按CWE编号排序
+func largestSubtrees(entries, roots []*CWE, n int) []SubtreeSize {
+	isRoot := make(map[*CWE]bool, len(roots))
+	for _, root := range roots {
+		isRoot[root] = true
+	}
+
+	sizes := make([]SubtreeSize, 0)
+	for _, entry := range entries {
+		if isRoot[entry] || len(entry.Children) == 0 {
+			continue
+		}
+		sizes = append(sizes, SubtreeSize{ID: entry.ID, Name: entry.Name, Descendants: countDescendants(entry)})
+	}
+	sort.SliceStable(sizes, func(i, j int) bool {
+		return sizes[i].Descendants > sizes[j].Descendants
+	})
+	if len(sizes) > n {
+		sizes = sizes[:n]
+	}
+	return sizes
+}
+
+// countDescendants 返回节点不重复的后代数量
+func countDescendants(node *CWE) int {
+	visited := map[*CWE]bool{node: true}
+	stack := append([]*CWE(nil), node.Children...)
+	count := 0
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		count++
+		stack = append(stack, current.Children...)
+	}
+	return count
+}
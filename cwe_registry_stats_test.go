@@ -0,0 +1,75 @@
+package cwe
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestRegistryStats(t *testing.T) {
+	registry := newQueryTestRegistry()
+	registry.Entries["CWE-20"].Abstraction = "Class"
+	registry.Entries["CWE-79"].Abstraction = "Base"
+	registry.Entries["CWE-89"].Abstraction = "Base"
+	overlay := NewSeverityOverlay()
+	overlay.Set("CWE-306", "Critical")
+	registry.SetSeverityOverlay(overlay)
+
+	stats := registry.Stats()
+	if stats.Entries != 6 || stats.Roots != 1 || stats.Unreachable != 0 {
+		t.Errorf("条目统计不正确: %+v", stats)
+	}
+	if stats.MaxDepth != 2 || !reflect.DeepEqual(stats.NodesPerLevel, []int{1, 2, 3}) {
+		t.Errorf("深度统计不正确: %d, %v", stats.MaxDepth, stats.NodesPerLevel)
+	}
+	if stats.AverageDepth != 8.0/6 {
+		t.Errorf("平均深度不正确: %v", stats.AverageDepth)
+	}
+	if stats.Leaves != 3 || stats.LeafPercentage != 50 {
+		t.Errorf("叶子节点统计不正确: %d, %v", stats.Leaves, stats.LeafPercentage)
+	}
+
+	// "高"统一为High，CWE-306使用覆盖层中的严重性
+	wantSeverity := map[string]int{"Critical": 2, "High": 2, "Medium": 1, StatsUnknown: 1}
+	if !reflect.DeepEqual(stats.BySeverity, wantSeverity) {
+		t.Errorf("严重性统计不正确: %v", stats.BySeverity)
+	}
+	if !reflect.DeepEqual(stats.ByAbstraction, map[string]int{"Base": 2, "Class": 1, StatsUnknown: 3}) {
+		t.Errorf("抽象级别统计不正确: %v", stats.ByAbstraction)
+	}
+
+	wantSubtrees := []SubtreeSize{
+		{ID: "CWE-20", Name: "Improper Input Validation", Descendants: 2},
+		{ID: "CWE-287", Name: "Improper Authentication", Descendants: 1},
+	}
+	if !reflect.DeepEqual(stats.LargestSubtrees, wantSubtrees) {
+		t.Errorf("最大子树不正确: %+v", stats.LargestSubtrees)
+	}
+
+	if _, err := json.Marshal(stats); err != nil {
+		t.Errorf("统计信息应可以序列化为JSON: %v", err)
+	}
+}
+
+func TestRegistryStatsWithoutRoot(t *testing.T) {
+	registry := newQueryTestRegistry()
+	registry.Root = nil
+	registry.Register(NewCWE("CWE-1390", "Weak Authentication"))
+
+	// 没有Root时从所有没有父节点的条目出发
+	stats := registry.Stats()
+	if stats.Roots != 2 || !reflect.DeepEqual(stats.NodesPerLevel, []int{2, 2, 3}) || stats.Unreachable != 0 {
+		t.Errorf("统计不正确: %+v", stats)
+	}
+
+	registry.Root = registry.Entries["CWE-20"]
+	stats = registry.Stats()
+	if stats.Unreachable != 4 || !reflect.DeepEqual(stats.NodesPerLevel, []int{1, 2}) {
+		t.Errorf("无法到达的条目统计不正确: %+v", stats)
+	}
+
+	empty := NewRegistry().Stats()
+	if empty.Entries != 0 || empty.MaxDepth != 0 || empty.LeafPercentage != 0 || len(empty.LargestSubtrees) != 0 {
+		t.Errorf("空注册表的统计不正确: %+v", empty)
+	}
+}
@@ -0,0 +1,242 @@
+package cwe
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ValidationIssueKind 表示注册表校验发现的问题类型
+type ValidationIssueKind string
+
+const (
+	// IssueRootNotSet 注册表有条目但没有设置Root
+	IssueRootNotSet ValidationIssueKind = "root_not_set"
+
+	// IssueRootNotRegistered Root不是注册表中的条目
+	IssueRootNotRegistered ValidationIssueKind = "root_not_registered"
+
+	// IssueInvalidID 条目ID不符合"CWE-数字"格式，或与Entries中的键不一致
+	IssueInvalidID ValidationIssueKind = "invalid_id"
+
+	// IssueDanglingParent Parent不是注册表中的条目，或父节点的Children不包含该条目
+	IssueDanglingParent ValidationIssueKind = "dangling_parent"
+
+	// IssueDanglingChild Children中包含不在注册表中的节点
+	IssueDanglingChild ValidationIssueKind = "dangling_child"
+
+	// IssueDuplicateChild Children中同一子节点出现了多次
+	IssueDuplicateChild ValidationIssueKind = "duplicate_child"
+
+	// IssueCycle 沿Children存在环
+	IssueCycle ValidationIssueKind = "cycle"
+
+	// IssueMissingName 条目没有名称
+	IssueMissingName ValidationIssueKind = "missing_name"
+
+	// IssueMissingDescription 条目没有描述
+	IssueMissingDescription ValidationIssueKind = "missing_description"
+)
+
+// IssueLevel 表示问题的严重程度
+type IssueLevel int
+
+const (
+	// IssueWarning 不影响注册表的使用，但可能意味着数据不完整
+	IssueWarning IssueLevel = iota
+
+	// IssueError 结构性问题，遍历、导出或查询可能得到错误的结果
+	IssueError
+)
+
+// String 返回问题级别的名称
+func (l IssueLevel) String() string {
+	if l == IssueError {
+		return "error"
+	}
+	return "warning"
+}
+
+// ValidationIssue 表示注册表校验发现的一个问题
+type ValidationIssue struct {
+	// Kind 问题类型
+	Kind ValidationIssueKind
+
+	// Level 问题级别
+	Level IssueLevel
+
+	// ID 相关条目在Entries中的键，与整个注册表相关的问题为空
+	ID string
+
+	// Message 问题描述
+	Message string
+}
+
+// String 将问题格式化为"[级别] ID: 描述"
+func (i ValidationIssue) String() string {
+	if i.ID == "" {
+		return fmt.Sprintf("[%s] %s", i.Level, i.Message)
+	}
+	return fmt.Sprintf("[%s] %s: %s", i.Level, i.ID, i.Message)
+}
+
+// ValidationReport 是Registry.Validate的结果
+type ValidationReport struct {
+	// Issues 发现的问题，与注册表相关的问题在前，其余按条目的CWE编号和问题类型排序
+	Issues []ValidationIssue
+}
+
+// Valid 判断注册表是否没有IssueError级别的问题
+func (r *ValidationReport) Valid() bool {
+	return len(r.Errors()) == 0
+}
+
+// Errors 返回IssueError级别的问题
+func (r *ValidationReport) Errors() []ValidationIssue {
+	return r.filter(func(issue ValidationIssue) bool { return issue.Level == IssueError })
+}
+
+// ByKind 返回指定类型的问题
+func (r *ValidationReport) ByKind(kind ValidationIssueKind) []ValidationIssue {
+	return r.filter(func(issue ValidationIssue) bool { return issue.Kind == kind })
+}
+
+func (r *ValidationReport) filter(keep func(ValidationIssue) bool) []ValidationIssue {
+	result := make([]ValidationIssue, 0)
+	for _, issue := range r.Issues {
+		if keep(issue) {
+			result = append(result, issue)
+		}
+	}
+	return result
+}
+
+// validCWEIDPattern 规范的CWE ID格式
+var validCWEIDPattern = regexp.MustCompile(`^CWE-\d+$`)
+
+// Validate 检查注册表的结构和内容
+//
+// 方法功能:
+// 从JSON、XML或CSV导入以及多次合并后的注册表可能存在不易察觉的结构问题。Validate会检查:
+// - Root未设置(警告)或不是注册表中的条目(错误)
+// - ID不符合"CWE-数字"格式或与Entries的键不一致(错误)
+// - Parent指向注册表外的节点，或父节点的Children不包含该条目(错误)
+// - Children中包含注册表外的节点或重复的子节点(错误)
+// - 沿Children存在的环(错误)
+// - 缺少名称或描述(警告)
+// Validate只读取注册表，不做任何修改。
+//
+// 返回值:
+// - *ValidationReport: 校验结果，没有问题时Issues为空
+//
+// 使用示例:
+// ```go
+// registry, _ := cwe.ReadExportJSON(file)
+// report := registry.Validate()
+//
+//	if !report.Valid() {
+//	    for _, issue := range report.Errors() {
+//	        fmt.Println(issue)
+//	    }
+//	}
+//
+// ```
+func (r *Registry) Validate() *ValidationReport {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	registered := make(map[*CWE]bool, len(r.Entries))
+	for _, entry := range r.Entries {
+		registered[entry] = true
+	}
+
+	global := make([]ValidationIssue, 0)
+	switch {
+	case r.Root == nil && len(r.Entries) > 0:
+		global = append(global, ValidationIssue{Kind: IssueRootNotSet, Level: IssueWarning, Message: "注册表没有设置Root"})
+	case r.Root != nil && !registered[r.Root]:
+		global = append(global, ValidationIssue{Kind: IssueRootNotRegistered, Level: IssueError, Message: fmt.Sprintf("Root %s不是注册表中的条目", r.Root.ID)})
+	}
+
+	issues := make([]ValidationIssue, 0)
+	add := func(id string, kind ValidationIssueKind, level IssueLevel, format string, args ...interface{}) {
+		issues = append(issues, ValidationIssue{Kind: kind, Level: level, ID: id, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for key, entry := range r.Entries {
+		if entry == nil {
+			add(key, IssueInvalidID, IssueError, "条目为nil")
+			continue
+		}
+
+		switch {
+		case !validCWEIDPattern.MatchString(entry.ID):
+			add(key, IssueInvalidID, IssueError, "ID %q不符合CWE-数字格式", entry.ID)
+		case key != entry.ID:
+			add(key, IssueInvalidID, IssueError, "ID %s与注册表中的键不一致", entry.ID)
+		}
+
+		if parent := entry.Parent; parent != nil {
+			switch {
+			case !registered[parent]:
+				add(key, IssueDanglingParent, IssueError, "父节点%s不在注册表中", parent.ID)
+			case !containsCWE(parent.Children, entry):
+				add(key, IssueDanglingParent, IssueError, "父节点%s的子节点不包含该条目", parent.ID)
+			}
+		}
+
+		seen := make(map[*CWE]bool, len(entry.Children))
+		for _, child := range entry.Children {
+			switch {
+			case child == nil:
+				add(key, IssueDanglingChild, IssueError, "子节点为nil")
+			case !registered[child]:
+				add(key, IssueDanglingChild, IssueError, "子节点%s不在注册表中", child.ID)
+			case seen[child]:
+				add(key, IssueDuplicateChild, IssueError, "子节点%s重复出现", child.ID)
+			}
+			seen[child] = true
+		}
+
+		if strings.TrimSpace(entry.Name) == "" {
+			add(key, IssueMissingName, IssueWarning, "缺少名称")
+		}
+		if strings.TrimSpace(entry.Description) == "" {
+			add(key, IssueMissingDescription, IssueWarning, "缺少描述")
+		}
+	}
+
+	starts := make([]*CWE, 0, len(r.Entries))
+	for _, entry := range r.Entries {
+		if entry != nil {
+			starts = append(starts, entry)
+		}
+	}
+	sortByCWEID(starts)
+	for _, cycle := range findCycles(starts) {
+		add(cycle[0].ID, IssueCycle, IssueError, "存在环: %s", formatCycle(cycle))
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].ID != issues[j].ID {
+			return compareCWEIDs(issues[i].ID, issues[j].ID) < 0
+		}
+		if issues[i].Kind != issues[j].Kind {
+			return issues[i].Kind < issues[j].Kind
+		}
+		return issues[i].Message < issues[j].Message
+	})
+
+	return &ValidationReport{Issues: append(global, issues...)}
+}
+
+// containsCWE 判断nodes中是否包含node
+func containsCWE(nodes []*CWE, node *CWE) bool {
+	for _, n := range nodes {
+		if n == node {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package cwe
+
+import (
+	"strings"
+	"testing"
+)
+
+// newValidRegistry 创建没有任何问题的注册表
+func newValidRegistry() *Registry {
+	registry := newQueryTestRegistry()
+	for _, entry := range registry.Entries {
+		entry.Description = entry.Name + " description"
+	}
+	return registry
+}
+
+func TestValidateValidRegistry(t *testing.T) {
+	report := newValidRegistry().Validate()
+	if len(report.Issues) != 0 || !report.Valid() {
+		t.Errorf("期望没有问题，实际: %v", report.Issues)
+	}
+}
+
+func TestValidateStructuralProblems(t *testing.T) {
+	registry := newValidRegistry()
+
+	// 父节点不在注册表中
+	outsider := NewCWE("CWE-5000", "Outsider")
+	outsider.AddChild(registry.Entries["CWE-306"])
+	registry.Entries["CWE-287"].Children = nil
+	// 重复的子节点和注册表外的子节点
+	sqli := registry.Entries["CWE-89"]
+	registry.Entries["CWE-20"].Children = append(registry.Entries["CWE-20"].Children, sqli, NewCWE("CWE-6000", "Ghost"))
+	// 环: CWE-79 → CWE-20
+	registry.Entries["CWE-79"].Children = append(registry.Entries["CWE-79"].Children, registry.Entries["CWE-20"])
+	// ID问题
+	registry.Entries["bad"] = &CWE{ID: "bad", Name: "Bad", Description: "bad"}
+	registry.Entries["CWE-7"] = &CWE{ID: "CWE-8", Name: "Mismatch", Description: "mismatch"}
+
+	report := registry.Validate()
+	if report.Valid() {
+		t.Fatal("期望存在错误")
+	}
+
+	expect := map[ValidationIssueKind]string{
+		IssueDanglingParent: "CWE-306",
+		IssueDuplicateChild: "CWE-20",
+		IssueDanglingChild:  "CWE-20",
+		IssueCycle:          "CWE-20",
+	}
+	for kind, id := range expect {
+		issues := report.ByKind(kind)
+		if len(issues) != 1 || issues[0].ID != id || issues[0].Level != IssueError {
+			t.Errorf("%s: 期望%s上的一个错误，实际 %v", kind, id, issues)
+		}
+	}
+	if cycle := report.ByKind(IssueCycle); len(cycle) == 1 && !strings.Contains(cycle[0].Message, "CWE-20 → CWE-79 → CWE-20") {
+		t.Errorf("环描述不正确: %s", cycle[0].Message)
+	}
+	if invalid := report.ByKind(IssueInvalidID); len(invalid) != 2 || invalid[0].ID != "CWE-7" || invalid[1].ID != "bad" {
+		t.Errorf("ID问题不正确: %v", invalid)
+	}
+}
+
+func TestValidateParentNotLinked(t *testing.T) {
+	registry := newValidRegistry()
+	// Parent指向注册表中的条目，但该条目的Children不包含它
+	registry.Entries["CWE-306"].Parent = registry.Entries["CWE-20"]
+
+	issues := registry.Validate().ByKind(IssueDanglingParent)
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, "子节点不包含") {
+		t.Errorf("期望父子关系不一致的问题，实际 %v", issues)
+	}
+}
+
+func TestValidateRootAndWarnings(t *testing.T) {
+	registry := newValidRegistry()
+	registry.Root = nil
+	registry.Entries["CWE-79"].Description = ""
+	registry.Entries["CWE-89"].Name = " "
+
+	report := registry.Validate()
+	if !report.Valid() {
+		t.Errorf("警告不应使注册表无效: %v", report.Errors())
+	}
+	if len(report.Issues) != 3 || report.Issues[0].Kind != IssueRootNotSet {
+		t.Fatalf("期望Root警告在最前，共3个问题，实际 %v", report.Issues)
+	}
+	if report.Issues[1].String() != "[warning] CWE-79: 缺少描述" {
+		t.Errorf("问题格式不正确: %s", report.Issues[1])
+	}
+
+	registry.Root = NewCWE("CWE-1", "Detached")
+	if issues := registry.Validate().ByKind(IssueRootNotRegistered); len(issues) != 1 {
+		t.Errorf("Root不在注册表中时应报告错误，实际 %v", issues)
+	}
+
+	if report := NewRegistry().Validate(); len(report.Issues) != 0 {
+		t.Errorf("空注册表不应有问题: %v", report.Issues)
+	}
+}
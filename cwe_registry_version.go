@@ -0,0 +1,74 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrVersionMismatch 表示条目的CWE内容版本与期望的版本不同
+// 注册不同版本的条目或服务器版本与DataFetcher.PinVersion固定的版本不同时返回，
+// 可通过errors.Is(err, ErrVersionMismatch)判断
+var ErrVersionMismatch = errors.New("CWE版本不一致")
+
+// Version 返回注册表中条目所属的CWE内容版本
+// 未通过SetVersion设置时为第一个带有版本的条目的版本，注册表中没有带版本的条目时返回空字符串
+func (r *Registry) Version() string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.version
+}
+
+// SetVersion 设置注册表的CWE内容版本
+//
+// 方法功能:
+// 设置后通过Register注册的条目如果带有不同的Version会被拒绝，Version为空的条目不受限制。
+// 不调用此方法时，注册表的版本由第一个带有版本的条目决定。
+// 修改版本不会检查已经注册的条目。
+//
+// 参数:
+// - version: string - CWE内容版本，如"4.16"，为空时恢复为由之后注册的条目决定
+func (r *Registry) SetVersion(version string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.version = version
+}
+
+// AllowMixedVersions 设置是否允许注册不同CWE版本的条目
+//
+// 方法功能:
+// 默认情况下Register拒绝与注册表版本不同的条目，避免将不同版本的数据混在一起后
+// 得到不存在于任何版本中的层次结构。比较两个版本的数据等确实需要混合时可以允许，
+// 此时注册表的版本仍为第一个带有版本的条目的版本。
+//
+// 参数:
+// - allow: bool - 为true时允许混合不同版本的条目
+//
+// 使用示例:
+// ```go
+// registry := cwe.NewRegistry()
+// registry.AllowMixedVersions(true)
+//
+// registry.Register(oldEntry) // Version为"4.15"
+// registry.Register(newEntry) // Version为"4.16"，不会返回错误
+// ```
+func (r *Registry) AllowMixedVersions(allow bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.allowMixedVersions = allow
+}
+
+// checkVersion 检查条目的版本能否注册，必须在持有写锁时调用
+// 注册表还没有版本时采用条目的版本
+func (r *Registry) checkVersion(cwe *CWE) error {
+	if cwe.Version == "" {
+		return nil
+	}
+	if r.version == "" {
+		r.version = cwe.Version
+		return nil
+	}
+	if cwe.Version != r.version && !r.allowMixedVersions {
+		return fmt.Errorf("%w: %s的版本为%s，注册表的版本为%s", ErrVersionMismatch, cwe.ID, cwe.Version, r.version)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package cwe
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// newVersionedCWE 创建带有版本的CWE
+func newVersionedCWE(id, version string) *CWE {
+	entry := NewCWE(id, id)
+	entry.Version = version
+	return entry
+}
+
+func TestRegistryRejectsMixedVersions(t *testing.T) {
+	registry := NewRegistry()
+
+	if err := registry.Register(NewCWE("CWE-1", "无版本")); err != nil {
+		t.Fatalf("注册无版本的条目失败: %v", err)
+	}
+	if registry.Version() != "" {
+		t.Errorf("没有带版本的条目时版本应为空，实际为%q", registry.Version())
+	}
+
+	if err := registry.Register(newVersionedCWE("CWE-79", "4.16")); err != nil {
+		t.Fatalf("注册失败: %v", err)
+	}
+	if registry.Version() != "4.16" {
+		t.Errorf("注册表应采用第一个带版本的条目的版本，实际为%q", registry.Version())
+	}
+
+	err := registry.Register(newVersionedCWE("CWE-89", "4.15"))
+	if !errors.Is(err, ErrVersionMismatch) {
+		t.Errorf("注册其他版本的条目应返回ErrVersionMismatch，实际为%v", err)
+	}
+	if _, exists := registry.Entries["CWE-89"]; exists {
+		t.Error("被拒绝的条目不应注册")
+	}
+
+	if err := registry.Register(NewCWE("CWE-20", "无版本")); err != nil {
+		t.Errorf("无版本的条目不应受限制: %v", err)
+	}
+}
+
+func TestRegistryAllowMixedVersions(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetVersion("4.16")
+	registry.AllowMixedVersions(true)
+
+	if err := registry.Register(newVersionedCWE("CWE-79", "4.15")); err != nil {
+		t.Errorf("允许混合版本时注册失败: %v", err)
+	}
+	if registry.Version() != "4.16" {
+		t.Errorf("注册表版本不应改变，实际为%q", registry.Version())
+	}
+}
+
+func TestRegistrySnapshotKeepsVersion(t *testing.T) {
+	registry := NewRegistry()
+	registry.AllowMixedVersions(true)
+	registry.Register(newVersionedCWE("CWE-79", "4.16"))
+	registry.Register(newVersionedCWE("CWE-89", "4.15"))
+
+	data, err := json.Marshal(NewRegistrySnapshot(registry))
+	if err != nil {
+		t.Fatalf("序列化快照失败: %v", err)
+	}
+	var snapshot RegistrySnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatalf("解析快照失败: %v", err)
+	}
+	if snapshot.Version != "4.16" || !snapshot.MixedVersions {
+		t.Errorf("快照应保存版本信息，实际为%q, %v", snapshot.Version, snapshot.MixedVersions)
+	}
+
+	restored, err := snapshot.ToRegistry()
+	if err != nil {
+		t.Fatalf("恢复注册表失败: %v", err)
+	}
+	if restored.Version() != "4.16" || restored.Entries["CWE-89"].Version != "4.15" {
+		t.Errorf("恢复后版本不正确: %q, %q", restored.Version(), restored.Entries["CWE-89"].Version)
+	}
+
+	snapshot.MixedVersions = false
+	if _, err := snapshot.ToRegistry(); !errors.Is(err, ErrVersionMismatch) {
+		t.Errorf("不允许混合版本时恢复应返回ErrVersionMismatch，实际为%v", err)
+	}
+}
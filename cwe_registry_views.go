@@ -0,0 +1,207 @@
+package cwe
+
+import (
+	"fmt"
+	"sort"
+)
+
+// AddViewChild 添加属于指定视图的父子关系
+//
+// 方法功能:
+// 同一对条目之间的父子关系在不同视图(如研究视图1000、开发视图699、简化视图1003)中可能不同，
+// 而CWE.Children把所有视图的关系合并在一起。AddViewChild在按层次结构模式(见AddChild)建立父子关系的同时，
+// 记录该关系所属的视图，之后可以通过ChildrenInView和ParentsInView按视图查询，不需要重新获取。
+// 已经是子节点时不会重复添加到Children，重复记录同一视图的关系不做任何操作。
+// 构建树时DataFetcher会自动记录每条关系所属的视图。
+//
+// 参数:
+// - viewID: string - 视图ID
+// - parentID: string - 父节点ID，必须已注册
+// - childID: string - 子节点ID，必须已注册
+// 三个ID都支持ParseCWEID接受的所有格式
+//
+// 返回值:
+// - error: 视图ID无效时返回解析错误，节点未注册时返回包装了ErrNotFound的错误，
+// 严格模式下冲突时返回包装了ErrMultipleParents的错误
+//
+// 使用示例:
+// ```go
+// registry.AddViewChild("1000", "CWE-20", "CWE-1284")
+// registry.AddViewChild("699", "CWE-1215", "CWE-1284")
+//
+// children, _ := registry.ChildrenInView("CWE-20", "1000") // [CWE-1284]
+// ```
+func (r *Registry) AddViewChild(viewID, parentID, childID string) error {
+	normalizedViewID, err := ParseCWEID(viewID)
+	if err != nil {
+		return fmt.Errorf("视图ID无效: %w", err)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	parent, exists := r.Entries[normalizeEntryID(parentID)]
+	if !exists {
+		return &notFoundError{id: parentID}
+	}
+	child, exists := r.Entries[normalizeEntryID(childID)]
+	if !exists {
+		return &notFoundError{id: childID}
+	}
+
+	if !containsCWE(parent.Children, child) {
+		if err := r.link(parent, child); err != nil {
+			return err
+		}
+	}
+	r.recordViewChild(normalizedViewID, parent.ID, child.ID)
+	return nil
+}
+
+// ChildrenInView 返回条目在指定视图中的子节点
+//
+// 参数:
+// - id: string - 条目ID
+// - viewID: string - 视图ID
+//
+// 返回值:
+// - []*CWE: 按记录顺序排列的子节点；该视图中没有子节点或没有记录该视图时返回空切片
+// - error: 条目不存在时返回包装了ErrNotFound的错误
+func (r *Registry) ChildrenInView(id, viewID string) ([]*CWE, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	id = normalizeEntryID(id)
+	if _, exists := r.Entries[id]; !exists {
+		return nil, &notFoundError{id: id}
+	}
+	return r.entriesOf(r.viewChildren[normalizeEntryID(viewID)][id]), nil
+}
+
+// ParentsInView 返回条目在指定视图中的父节点
+//
+// 返回值:
+// - []*CWE: 按CWE编号排序的父节点；没有父节点时返回空切片
+// - error: 条目不存在时返回包装了ErrNotFound的错误
+func (r *Registry) ParentsInView(id, viewID string) ([]*CWE, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	id = normalizeEntryID(id)
+	if _, exists := r.Entries[id]; !exists {
+		return nil, &notFoundError{id: id}
+	}
+
+	parentIDs := make([]string, 0)
+	for parentID, childIDs := range r.viewChildren[normalizeEntryID(viewID)] {
+		if containsString(childIDs, id) {
+			parentIDs = append(parentIDs, parentID)
+		}
+	}
+	sort.Slice(parentIDs, func(i, j int) bool {
+		return compareCWEIDs(parentIDs[i], parentIDs[j]) < 0
+	})
+	return r.entriesOf(parentIDs), nil
+}
+
+// Views 返回注册表中记录了父子关系的视图ID，按CWE编号排序
+func (r *Registry) Views() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	views := make([]string, 0, len(r.viewChildren))
+	for viewID := range r.viewChildren {
+		views = append(views, viewID)
+	}
+	sort.Slice(views, func(i, j int) bool {
+		return compareCWEIDs(views[i], views[j]) < 0
+	})
+	return views
+}
+
+// tagViewChild 记录属于视图的父子关系，不修改Children
+// 构建树时父子关系已通过CWE.AddChild建立，只需补充视图信息；viewID为空时不做任何操作
+func (r *Registry) tagViewChild(viewID, parentID, childID string) {
+	if viewID == "" {
+		return
+	}
+	viewID = normalizeEntryID(viewID)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.recordViewChild(viewID, parentID, childID)
+}
+
+// recordViewChild 记录属于视图的父子关系，调用方需持有写锁
+func (r *Registry) recordViewChild(viewID, parentID, childID string) {
+	if r.viewChildren == nil {
+		r.viewChildren = make(map[string]map[string][]string)
+	}
+	children := r.viewChildren[viewID]
+	if children == nil {
+		children = make(map[string][]string)
+		r.viewChildren[viewID] = children
+	}
+	if !containsString(children[parentID], childID) {
+		children[parentID] = append(children[parentID], childID)
+	}
+}
+
+// removeViewChild 从所有视图中移除parentID到childID的父子关系，调用方需持有写锁
+func (r *Registry) removeViewChild(parentID, childID string) {
+	for _, children := range r.viewChildren {
+		if !containsString(children[parentID], childID) {
+			continue
+		}
+		remaining := make([]string, 0, len(children[parentID]))
+		for _, id := range children[parentID] {
+			if id != childID {
+				remaining = append(remaining, id)
+			}
+		}
+		if len(remaining) == 0 {
+			delete(children, parentID)
+		} else {
+			children[parentID] = remaining
+		}
+	}
+}
+
+// entriesOf 返回ids中已注册的条目，调用方需持有读锁
+func (r *Registry) entriesOf(ids []string) []*CWE {
+	entries := make([]*CWE, 0, len(ids))
+	for _, id := range ids {
+		if entry, exists := r.Entries[id]; exists {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// copyViewChildren 复制视图关系，keep不为nil时只保留父子节点都满足keep的关系
+func copyViewChildren(viewChildren map[string]map[string][]string, keep func(id string) bool) map[string]map[string][]string {
+	if viewChildren == nil {
+		return nil
+	}
+	copied := make(map[string]map[string][]string, len(viewChildren))
+	for viewID, children := range viewChildren {
+		for parentID, childIDs := range children {
+			if keep != nil && !keep(parentID) {
+				continue
+			}
+			for _, childID := range childIDs {
+				if keep != nil && !keep(childID) {
+					continue
+				}
+				if copied[viewID] == nil {
+					copied[viewID] = make(map[string][]string)
+				}
+				copied[viewID][parentID] = append(copied[viewID][parentID], childID)
+			}
+		}
+	}
+	if len(copied) == 0 {
+		return nil
+	}
+	return copied
+}
@@ -0,0 +1,251 @@
+package cwe
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newViewsTestRegistry() *Registry {
+	registry := NewRegistry()
+	registry.SetHierarchyMode(HierarchyMultiParent)
+	for _, id := range []string{"CWE-1000", "CWE-699", "CWE-20", "CWE-1215", "CWE-1284"} {
+		registry.Register(NewCWE(id, id))
+	}
+	registry.Root = registry.Entries["CWE-1000"]
+	return registry
+}
+
+func cweIDsOf(entries []*CWE) []string {
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+	}
+	return ids
+}
+
+func TestRegistryAddViewChild(t *testing.T) {
+	registry := newViewsTestRegistry()
+
+	steps := [][3]string{
+		{"1000", "CWE-1000", "CWE-20"},
+		{"1000", "CWE-20", "CWE-1284"},
+		{"699", "CWE-699", "CWE-1215"},
+		{"CWE-699", "1215", "1284"},
+		{"1000", "CWE-20", "CWE-1284"},
+	}
+	for _, step := range steps {
+		if err := registry.AddViewChild(step[0], step[1], step[2]); err != nil {
+			t.Fatalf("AddViewChild(%v)失败: %v", step, err)
+		}
+	}
+
+	children, err := registry.ChildrenInView("CWE-20", "1000")
+	if err != nil {
+		t.Fatalf("ChildrenInView失败: %v", err)
+	}
+	if got := cweIDsOf(children); !reflect.DeepEqual(got, []string{"CWE-1284"}) {
+		t.Errorf("CWE-20在视图1000中的子节点 = %v", got)
+	}
+
+	children, _ = registry.ChildrenInView("CWE-20", "699")
+	if len(children) != 0 {
+		t.Errorf("CWE-20在视图699中不应有子节点，实际为%v", cweIDsOf(children))
+	}
+
+	parents, _ := registry.ParentsInView("CWE-1284", "CWE-699")
+	if got := cweIDsOf(parents); !reflect.DeepEqual(got, []string{"CWE-1215"}) {
+		t.Errorf("CWE-1284在视图699中的父节点 = %v", got)
+	}
+
+	if got := len(registry.Entries["CWE-20"].Children); got != 1 {
+		t.Errorf("重复添加后CWE-20应只有1个子节点，实际为%d", got)
+	}
+	allParents, _ := registry.Parents("CWE-1284")
+	if got := cweIDsOf(allParents); !reflect.DeepEqual(got, []string{"CWE-20", "CWE-1215"}) {
+		t.Errorf("CWE-1284的所有父节点 = %v", got)
+	}
+
+	if got := registry.Views(); !reflect.DeepEqual(got, []string{"CWE-699", "CWE-1000"}) {
+		t.Errorf("Views() = %v", got)
+	}
+}
+
+func TestRegistryAddViewChildErrors(t *testing.T) {
+	registry := newViewsTestRegistry()
+
+	if err := registry.AddViewChild("1000", "CWE-20", "CWE-404"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("子节点不存在时应返回ErrNotFound，实际为%v", err)
+	}
+	if err := registry.AddViewChild("1000", "CWE-404", "CWE-20"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("父节点不存在时应返回ErrNotFound，实际为%v", err)
+	}
+	if err := registry.AddViewChild("abc", "CWE-1000", "CWE-20"); err == nil {
+		t.Error("视图ID无效时应返回错误")
+	}
+	if _, err := registry.ChildrenInView("CWE-404", "1000"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ChildrenInView对不存在的条目应返回ErrNotFound，实际为%v", err)
+	}
+	if _, err := registry.ParentsInView("CWE-404", "1000"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ParentsInView对不存在的条目应返回ErrNotFound，实际为%v", err)
+	}
+	if views := registry.Views(); len(views) != 0 {
+		t.Errorf("失败的添加不应记录视图，实际为%v", views)
+	}
+
+	strict := newViewsTestRegistry()
+	strict.SetHierarchyMode(HierarchyStrict)
+	strict.AddViewChild("1000", "CWE-20", "CWE-1284")
+	if err := strict.AddViewChild("699", "CWE-1215", "CWE-1284"); !errors.Is(err, ErrMultipleParents) {
+		t.Errorf("严格模式下第二个父节点应返回ErrMultipleParents，实际为%v", err)
+	}
+}
+
+func TestRegistryViewsCopied(t *testing.T) {
+	registry := newViewsTestRegistry()
+	registry.AddViewChild("1000", "CWE-1000", "CWE-20")
+	registry.AddViewChild("1000", "CWE-20", "CWE-1284")
+	registry.AddViewChild("699", "CWE-699", "CWE-1215")
+	registry.AddViewChild("699", "CWE-1215", "CWE-1284")
+
+	clone := registry.Clone()
+	registry.AddViewChild("699", "CWE-699", "CWE-20")
+	if children, _ := clone.ChildrenInView("CWE-699", "699"); !reflect.DeepEqual(cweIDsOf(children), []string{"CWE-1215"}) {
+		t.Errorf("克隆后对原注册表的修改不应影响副本，实际为%v", cweIDsOf(children))
+	}
+
+	subtree, err := registry.Subtree("CWE-20")
+	if err != nil {
+		t.Fatalf("Subtree失败: %v", err)
+	}
+	if children, _ := subtree.ChildrenInView("CWE-20", "1000"); !reflect.DeepEqual(cweIDsOf(children), []string{"CWE-1284"}) {
+		t.Errorf("子树中CWE-20在视图1000中的子节点 = %v", cweIDsOf(children))
+	}
+	if got := subtree.Views(); !reflect.DeepEqual(got, []string{"CWE-1000"}) {
+		t.Errorf("子树只应保留子树内的视图关系，实际为%v", got)
+	}
+
+	data, err := json.Marshal(NewRegistrySnapshot(registry))
+	if err != nil {
+		t.Fatalf("序列化快照失败: %v", err)
+	}
+	var snapshot RegistrySnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatalf("解析快照失败: %v", err)
+	}
+	restored, err := snapshot.ToRegistry()
+	if err != nil {
+		t.Fatalf("恢复快照失败: %v", err)
+	}
+	if children, _ := restored.ChildrenInView("CWE-699", "699"); !reflect.DeepEqual(cweIDsOf(children), []string{"CWE-1215", "CWE-20"}) {
+		t.Errorf("恢复后CWE-699在视图699中的子节点 = %v", cweIDsOf(children))
+	}
+	if parents, _ := restored.ParentsInView("CWE-1284", "1000"); !reflect.DeepEqual(cweIDsOf(parents), []string{"CWE-20"}) {
+		t.Errorf("恢复后CWE-1284在视图1000中的父节点 = %v", cweIDsOf(parents))
+	}
+}
+
+func TestBuildCWETreeWithViewTagsRelations(t *testing.T) {
+	var failChildren int32
+	server := setupResumableTreeServer(&failChildren)
+	defer server.Close()
+
+	registry, err := newResumableTestFetcher(server.URL).BuildCWETreeWithView("1000")
+	if err != nil {
+		t.Fatalf("构建CWE树失败: %v", err)
+	}
+
+	if got := registry.Views(); !reflect.DeepEqual(got, []string{"CWE-1000"}) {
+		t.Errorf("Views() = %v", got)
+	}
+	children, _ := registry.ChildrenInView("CWE-20", "1000")
+	if got := cweIDsOf(children); !reflect.DeepEqual(got, []string{"CWE-79", "CWE-89"}) {
+		t.Errorf("CWE-20在视图1000中的子节点 = %v", got)
+	}
+}
+
+func TestDataFetcherExtendWithView(t *testing.T) {
+	children := map[string][]string{
+		"CWE-1000@CWE-1000": {"20"},
+		"CWE-20@CWE-1000":   {"79"},
+		"CWE-79@CWE-1000":   {},
+		"CWE-699@CWE-699":   {"1215"},
+		"CWE-1215@CWE-699":  {"79"},
+		"CWE-79@CWE-699":    {},
+	}
+	requests := map[string]int{}
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/cwe/view/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/cwe/view/")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"views": []map[string]interface{}{{"id": id, "name": "View " + id}},
+		})
+	})
+	handler.HandleFunc("/cwe/category/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/cwe/category/")
+		requests[id]++
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"categories": []map[string]interface{}{{"id": id, "name": "Category " + id}},
+		})
+	})
+	handler.HandleFunc("/cwe/weakness/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/cwe/weakness/")
+		requests[id]++
+		if id != "CWE-79" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"weaknesses": []map[string]interface{}{{"id": id, "name": "Cross-site Scripting"}},
+		})
+	})
+	handler.HandleFunc("/cwe/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/cwe/"), "/children")
+		ids, exists := children[id+"@"+r.URL.Query().Get("view")]
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(ids)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+	registry, err := fetcher.BuildCWETreeWithView("1000")
+	if err != nil {
+		t.Fatalf("构建CWE树失败: %v", err)
+	}
+	registry.SetHierarchyMode(HierarchyMultiParent)
+
+	if err := fetcher.ExtendWithView(registry, "699"); err != nil {
+		t.Fatalf("ExtendWithView失败: %v", err)
+	}
+
+	if registry.Root.ID != "CWE-1000" {
+		t.Errorf("扩展视图不应改变Root，实际为%s", registry.Root.ID)
+	}
+	if requests["CWE-79"] != 1 {
+		t.Errorf("已注册的CWE-79不应重新获取，实际请求了%d次", requests["CWE-79"])
+	}
+	if got := registry.Views(); !reflect.DeepEqual(got, []string{"CWE-699", "CWE-1000"}) {
+		t.Errorf("Views() = %v", got)
+	}
+	parents, _ := registry.ParentsInView("CWE-79", "699")
+	if got := cweIDsOf(parents); !reflect.DeepEqual(got, []string{"CWE-1215"}) {
+		t.Errorf("CWE-79在视图699中的父节点 = %v", got)
+	}
+	parents, _ = registry.ParentsInView("CWE-79", "1000")
+	if got := cweIDsOf(parents); !reflect.DeepEqual(got, []string{"CWE-20"}) {
+		t.Errorf("CWE-79在视图1000中的父节点 = %v", got)
+	}
+	allParents, _ := registry.Parents("CWE-79")
+	if got := cweIDsOf(allParents); !reflect.DeepEqual(got, []string{"CWE-20", "CWE-1215"}) {
+		t.Errorf("CWE-79的所有父节点 = %v", got)
+	}
+}
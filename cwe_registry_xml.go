@@ -0,0 +1,279 @@
+package cwe
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// xmlRegistryEntry 是注册表XML格式中单个条目的表示
+// 子节点以ID引用的形式保存，避免Parent字段导致的循环引用
+type xmlRegistryEntry struct {
+	ID          string   `xml:"id,attr"`
+	Name        string   `xml:"name,attr"`
+	Description string   `xml:"description,omitempty"`
+	URL         string   `xml:"url,omitempty"`
+	Severity    string   `xml:"severity,omitempty"`
+	Status      string   `xml:"status,omitempty"`
+	Language    string   `xml:"language,omitempty"`
+	Mitigations []string `xml:"mitigations>mitigation,omitempty"`
+	Examples    []string `xml:"examples>example,omitempty"`
+	Children    []string `xml:"children>child,omitempty"`
+}
+
+// xmlRegistryDocument 是注册表XML格式的根元素
+type xmlRegistryDocument struct {
+	XMLName   xml.Name           `xml:"cwe-registry"`
+	Version   string             `xml:"version,attr"`
+	Timestamp string             `xml:"timestamp,attr,omitempty"`
+	RootID    string             `xml:"rootId,attr,omitempty"`
+	Entries   []xmlRegistryEntry `xml:"entries>cwe"`
+}
+
+// XMLImportOptions 控制XML导入行为的选项
+type XMLImportOptions struct {
+	// CreatePlaceholders 为true时，为未解析的子节点引用自动创建占位节点
+	// 占位节点只包含ID，Status被标记为StatusIncomplete
+	// 为false时，未解析的引用会被跳过，但仍会记录在导入报告中
+	CreatePlaceholders bool
+}
+
+// UnresolvedReference 表示XML导入时无法解析的子节点引用
+type UnresolvedReference struct {
+	// ParentID 引用该子节点的父节点ID
+	ParentID string
+
+	// ChildID 未能在导入数据中找到的子节点ID
+	ChildID string
+}
+
+// XMLImportReport 记录XML导入过程中发现的问题
+type XMLImportReport struct {
+	// Unresolved 所有未解析的子节点引用，按父节点ID和子节点ID排序
+	Unresolved []UnresolvedReference
+
+	// Placeholders 自动创建的占位节点ID列表，已排序
+	// 仅在XMLImportOptions.CreatePlaceholders为true时非空
+	Placeholders []string
+}
+
+// HasUnresolved 判断导入过程中是否存在未解析的引用
+func (r *XMLImportReport) HasUnresolved() bool {
+	return len(r.Unresolved) > 0
+}
+
+// ExportToXML 将CWE注册表导出为XML
+//
+// 方法功能:
+// 将注册表中的所有CWE条目序列化为XML格式，条目按ID排序以保证输出稳定。
+// 子节点以ID引用的形式保存，导入时通过ImportFromXML重建父子关系。
+//
+// 返回值:
+// - []byte: 带XML头的序列化数据
+// - error: 如序列化过程发生错误则返回错误，否则返回nil
+//
+// 数据样例:
+// ```xml
+// <cwe-registry version="1.0" timestamp="2024-01-01T00:00:00Z" rootId="CWE-1000">
+//
+//	<entries>
+//	  <cwe id="CWE-1000" name="Research View">
+//	    <children><child>CWE-20</child></children>
+//	  </cwe>
+//	</entries>
+//
+// </cwe-registry>
+// ```
+//
+// 相关方法:
+// - ImportFromXML(): 从XML数据导入CWE到注册表
+func (r *Registry) ExportToXML() ([]byte, error) {
+	doc := xmlRegistryDocument{
+		Version:   "1.0",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Entries:   make([]xmlRegistryEntry, 0, len(r.Entries)),
+	}
+	if r.Root != nil {
+		doc.RootID = r.Root.ID
+	}
+
+	ids := make([]string, 0, len(r.Entries))
+	for id := range r.Entries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		entry := r.Entries[id]
+		childIDs := make([]string, 0, len(entry.Children))
+		for _, child := range entry.Children {
+			childIDs = append(childIDs, child.ID)
+		}
+
+		doc.Entries = append(doc.Entries, xmlRegistryEntry{
+			ID:          entry.ID,
+			Name:        entry.Name,
+			Description: entry.Description,
+			URL:         entry.URL,
+			Severity:    entry.Severity,
+			Status:      entry.Status,
+			Language:    entry.Language,
+			Mitigations: entry.Mitigations,
+			Examples:    entry.Examples,
+			Children:    childIDs,
+		})
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
+
+// ImportFromXML 从XML数据导入CWE到当前Registry
+//
+// 方法功能:
+// 解析ExportToXML生成的XML数据，清空当前注册表并用解析出的条目替换。
+// 导入是宽松的: 引用了不存在节点的子节点关系不会导致失败，
+// 而是记录在返回的报告中；可选地为这些引用创建占位节点，
+// 这样部分导出的数据也能被有意义地加载。
+//
+// 参数:
+// - data: []byte - ExportToXML格式的XML数据
+// - opts: XMLImportOptions - 导入选项
+//
+// 返回值:
+// - *XMLImportReport: 导入报告，包含未解析的引用和创建的占位节点
+// - error: 如遇到空数据、解析错误、无ID条目、重复的ID或rootId无效则返回错误，此时注册表保持不变
+//
+// 错误处理:
+// - 空数据: 返回"empty XML data"
+// - 解析错误: 返回"failed to unmarshal XML: <原始错误>"
+// - 无ID条目: 返回"entry without ID found"
+// - 重复的ID: 返回"duplicate entry ID found: <ID>"
+// - rootId指向不存在的条目: 返回"root entry not found: <ID>"，没有rootId属性时注册表没有根节点
+//
+// 使用示例:
+// ```go
+// registry := cwe.NewRegistry()
+// report, err := registry.ImportFromXML(data, cwe.XMLImportOptions{CreatePlaceholders: true})
+//
+//	if err != nil {
+//	    log.Fatalf("导入XML数据失败: %v", err)
+//	}
+//
+//	for _, ref := range report.Unresolved {
+//	    fmt.Printf("%s 引用了缺失的子节点 %s\n", ref.ParentID, ref.ChildID)
+//	}
+//
+// ```
+//
+// 相关方法:
+// - ExportToXML(): 将注册表导出为XML数据
+func (r *Registry) ImportFromXML(data []byte, opts XMLImportOptions) (*XMLImportReport, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty XML data")
+	}
+
+	var doc xmlRegistryDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal XML: %w", err)
+	}
+
+	entries := make(map[string]*CWE, len(doc.Entries))
+	for _, xmlEntry := range doc.Entries {
+		id := strings.TrimSpace(xmlEntry.ID)
+		if id == "" {
+			return nil, errors.New("entry without ID found")
+		}
+
+		if _, exists := entries[id]; exists {
+			return nil, fmt.Errorf("duplicate entry ID found: %s", id)
+		}
+
+		entry := NewCWE(id, xmlEntry.Name)
+		entry.Description = xmlEntry.Description
+		entry.URL = xmlEntry.URL
+		entry.Severity = xmlEntry.Severity
+		entry.Status = xmlEntry.Status
+		entry.Language = xmlEntry.Language
+		if xmlEntry.Mitigations != nil {
+			entry.Mitigations = xmlEntry.Mitigations
+		}
+		if xmlEntry.Examples != nil {
+			entry.Examples = xmlEntry.Examples
+		}
+		entries[id] = entry
+	}
+
+	report := &XMLImportReport{
+		Unresolved:   make([]UnresolvedReference, 0),
+		Placeholders: make([]string, 0),
+	}
+
+	// 重建父子关系，记录无法解析的引用
+	// 同一个缺失节点被多次引用时只创建一个占位节点，但每次引用都会记录
+	placeholders := make(map[string]*CWE)
+	for _, xmlEntry := range doc.Entries {
+		parent := entries[strings.TrimSpace(xmlEntry.ID)]
+
+		for _, childID := range xmlEntry.Children {
+			childID = strings.TrimSpace(childID)
+			if childID == "" {
+				continue
+			}
+
+			if child, exists := entries[childID]; exists {
+				parent.AddChild(child)
+				continue
+			}
+
+			report.Unresolved = append(report.Unresolved, UnresolvedReference{
+				ParentID: parent.ID,
+				ChildID:  childID,
+			})
+			if !opts.CreatePlaceholders {
+				continue
+			}
+
+			placeholder, exists := placeholders[childID]
+			if !exists {
+				placeholder = NewCWE(childID, "")
+				placeholder.Status = StatusIncomplete
+				placeholders[childID] = placeholder
+				report.Placeholders = append(report.Placeholders, childID)
+			}
+			parent.AddChild(placeholder)
+		}
+	}
+
+	for id, placeholder := range placeholders {
+		entries[id] = placeholder
+	}
+
+	var root *CWE
+	if doc.RootID != "" {
+		if root = entries[doc.RootID]; root == nil {
+			return nil, fmt.Errorf("root entry not found: %s", doc.RootID)
+		}
+	}
+
+	sort.Slice(report.Unresolved, func(i, j int) bool {
+		if report.Unresolved[i].ParentID != report.Unresolved[j].ParentID {
+			return report.Unresolved[i].ParentID < report.Unresolved[j].ParentID
+		}
+		return report.Unresolved[i].ChildID < report.Unresolved[j].ChildID
+	})
+	sort.Strings(report.Placeholders)
+
+	r.Entries = entries
+	r.parents = nil
+	r.Root = root
+
+	return report, nil
+}
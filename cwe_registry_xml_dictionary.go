@@ -0,0 +1,485 @@
+package cwe
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultDictionaryViewID LoadFromXMLDictionary默认用于构建层次结构的视图(研究视图)
+const DefaultDictionaryViewID = "CWE-1000"
+
+// xmlDictionaryCatalog 是MITRE官方CWE字典(cwec_vX.Y.xml)的根元素
+// 只声明了转换为CWE所需的字段，标签不带命名空间，可以兼容不同版本的schema命名空间
+type xmlDictionaryCatalog struct {
+	XMLName    xml.Name                `xml:"Weakness_Catalog"`
+	Version    string                  `xml:"Version,attr"`
+	Date       string                  `xml:"Date,attr"`
+	Weaknesses []xmlDictionaryWeakness `xml:"Weaknesses>Weakness"`
+	Categories []xmlDictionaryCategory `xml:"Categories>Category"`
+	Views      []xmlDictionaryView     `xml:"Views>View"`
+}
+
+// xmlDictionaryText 是可能包含XHTML标记的结构化文本
+type xmlDictionaryText struct {
+	Inner string `xml:",innerxml"`
+}
+
+type xmlDictionaryWeakness struct {
+	ID                  string                     `xml:"ID,attr"`
+	Name                string                     `xml:"Name,attr"`
+	Abstraction         string                     `xml:"Abstraction,attr"`
+	Status              string                     `xml:"Status,attr"`
+	Description         xmlDictionaryText          `xml:"Description"`
+	ExtendedDescription xmlDictionaryText          `xml:"Extended_Description"`
+	RelatedWeaknesses   []xmlDictionaryRelation    `xml:"Related_Weaknesses>Related_Weakness"`
+	Mitigations         []xmlDictionaryMitigation  `xml:"Potential_Mitigations>Mitigation"`
+	ObservedExamples    []xmlDictionaryExample     `xml:"Observed_Examples>Observed_Example"`
+	MappingNotes        *xmlDictionaryMappingNotes `xml:"Mapping_Notes"`
+}
+
+type xmlDictionaryMappingNotes struct {
+	Usage     string            `xml:"Usage"`
+	Rationale xmlDictionaryText `xml:"Rationale"`
+	Comments  xmlDictionaryText `xml:"Comments"`
+	Reasons   []struct {
+		Type string `xml:"Type,attr"`
+	} `xml:"Reasons>Reason"`
+}
+
+type xmlDictionaryMitigation struct {
+	MitigationID       string            `xml:"Mitigation_ID,attr"`
+	Phases             []string          `xml:"Phase"`
+	Strategy           string            `xml:"Strategy"`
+	Description        xmlDictionaryText `xml:"Description"`
+	Effectiveness      string            `xml:"Effectiveness"`
+	EffectivenessNotes xmlDictionaryText `xml:"Effectiveness_Notes"`
+}
+
+type xmlDictionaryRelation struct {
+	Nature  string `xml:"Nature,attr"`
+	CWEID   string `xml:"CWE_ID,attr"`
+	ViewID  string `xml:"View_ID,attr"`
+	Ordinal string `xml:"Ordinal,attr"`
+}
+
+type xmlDictionaryExample struct {
+	Reference   string            `xml:"Reference"`
+	Description xmlDictionaryText `xml:"Description"`
+	Link        string            `xml:"Link"`
+}
+
+type xmlDictionaryMember struct {
+	CWEID  string `xml:"CWE_ID,attr"`
+	ViewID string `xml:"View_ID,attr"`
+}
+
+type xmlDictionaryCategory struct {
+	ID           string                     `xml:"ID,attr"`
+	Name         string                     `xml:"Name,attr"`
+	Status       string                     `xml:"Status,attr"`
+	Summary      xmlDictionaryText          `xml:"Summary"`
+	Members      []xmlDictionaryMember      `xml:"Relationships>Has_Member"`
+	MappingNotes *xmlDictionaryMappingNotes `xml:"Mapping_Notes"`
+}
+
+type xmlDictionaryView struct {
+	ID           string                     `xml:"ID,attr"`
+	Name         string                     `xml:"Name,attr"`
+	Status       string                     `xml:"Status,attr"`
+	Objective    xmlDictionaryText          `xml:"Objective"`
+	Members      []xmlDictionaryMember      `xml:"Members>Has_Member"`
+	MappingNotes *xmlDictionaryMappingNotes `xml:"Mapping_Notes"`
+}
+
+// toMappingNotes 转换为规范化的映射注释，字典中没有Mapping_Notes时返回nil
+func (n *xmlDictionaryMappingNotes) toMappingNotes() *CWEMappingNotes {
+	if n == nil {
+		return nil
+	}
+	notes := &CWEMappingNotes{
+		Usage:     NormalizeMappingUsage(n.Usage),
+		Rationale: dictionaryText(n.Rationale.Inner),
+		Comments:  dictionaryText(n.Comments.Inner),
+	}
+	for _, reason := range n.Reasons {
+		if reason.Type != "" {
+			notes.Reasons = append(notes.Reasons, reason.Type)
+		}
+	}
+	return notes
+}
+
+// XMLDictionaryOptions 控制LoadFromXMLDictionary的行为
+type XMLDictionaryOptions struct {
+	// ViewID 用于构建父子层次结构的视图ID，为空时使用DefaultDictionaryViewID
+	// 支持ParseCWEID接受的所有格式，如"1000"、"CWE-699"
+	ViewID string
+}
+
+// XMLDictionaryReport 记录加载官方字典的结果
+type XMLDictionaryReport struct {
+	// Version 字典的CWE版本，如"4.16"
+	Version string
+
+	// Date 字典的发布日期，如"2024-11-19"
+	Date string
+
+	// Weaknesses、Categories、Views 分别为导入的弱点、类别和视图数量
+	Weaknesses int
+	Categories int
+	Views      int
+
+	// Unresolved 所选视图中引用了字典里不存在的条目的关系，按父节点ID和子节点ID排序
+	Unresolved []UnresolvedReference
+}
+
+// dictionaryEdge 是所选视图中的一条父子关系
+type dictionaryEdge struct {
+	parent  string
+	child   string
+	primary bool
+}
+
+// LoadFromXMLDictionary 将MITRE官方发布的CWE XML字典解析为注册表
+//
+// 方法功能:
+// 直接读取https://cwe.mitre.org/data/xml/cwec_latest.xml.zip中的字典文件，
+// 不需要通过DataFetcher发起数百次REST请求即可离线获得完整的CWE数据。
+// 弱点、类别和视图都会转换为CWE条目，其中:
+// - 弱点的Description和Extended_Description合并为Description，Related_Weaknesses转换为Relations
+// - 缓解措施和观察到的示例只保留描述文本，XHTML标记会被去除
+// - 类别使用Summary、视图使用Objective作为Description
+// 字典中没有严重性字段，Severity保持为空，可以通过SetSeverityOverlay或ReclassifySeverity补充。
+//
+// 层次结构按opts.ViewID指定的视图构建: 弱点在该视图中的ChildOf关系、类别的Has_Member
+// 成员关系以及视图自身的成员列表都会转换为父子关系，视图中没有父节点的条目挂在视图条目下，
+// 视图条目作为注册表的Root。条目在视图中有多个父节点时会出现在每个父节点的Children中，
+// Parent指向Ordinal为Primary的父节点。返回的注册表处于HierarchyMultiParent模式，
+// 可以通过Registry.Parents查询条目在该视图中的所有父节点。
+//
+// 参数:
+// - r: io.Reader - 字典XML内容(已解压)
+// - opts: XMLDictionaryOptions - 加载选项
+//
+// 返回值:
+// - *Registry: 包含字典中所有条目的注册表
+// - *XMLDictionaryReport: 字典版本、条目数量和未解析的引用
+// - error: XML解析失败、条目ID重复或所选视图不存在时返回错误
+//
+// 使用示例:
+// ```go
+// registry, report, err := cwe.LoadFromXMLDictionaryFile("cwec_latest.xml.zip", cwe.XMLDictionaryOptions{})
+//
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// fmt.Printf("CWE %s: %d个弱点\n", report.Version, report.Weaknesses)
+// xss, _ := registry.GetByID("CWE-79")
+// fmt.Println(xss.Parent.ID) // 输出: CWE-74
+// ```
+func LoadFromXMLDictionary(r io.Reader, opts XMLDictionaryOptions) (*Registry, *XMLDictionaryReport, error) {
+	var catalog xmlDictionaryCatalog
+	if err := xml.NewDecoder(r).Decode(&catalog); err != nil {
+		return nil, nil, fmt.Errorf("解析CWE字典失败: %w", err)
+	}
+
+	viewID := DefaultDictionaryViewID
+	if opts.ViewID != "" {
+		id, err := ParseCWEID(opts.ViewID)
+		if err != nil {
+			return nil, nil, err
+		}
+		viewID = id
+	}
+
+	registry := NewRegistry()
+	report := &XMLDictionaryReport{
+		Version:    catalog.Version,
+		Date:       catalog.Date,
+		Weaknesses: len(catalog.Weaknesses),
+		Categories: len(catalog.Categories),
+		Views:      len(catalog.Views),
+		Unresolved: make([]UnresolvedReference, 0),
+	}
+
+	var edges []dictionaryEdge
+	register := func(entry *CWE) error {
+		if err := registry.Register(entry); err != nil {
+			return fmt.Errorf("导入CWE字典失败: %w", err)
+		}
+		return nil
+	}
+
+	for _, weakness := range catalog.Weaknesses {
+		entry := newDictionaryEntry(weakness.ID, weakness.Name, weakness.Status)
+		entry.Abstraction = weakness.Abstraction
+		entry.MappingNotes = weakness.MappingNotes.toMappingNotes()
+		entry.Description = joinDictionaryText(weakness.Description, weakness.ExtendedDescription)
+		for _, mitigation := range weakness.Mitigations {
+			if text := dictionaryText(mitigation.Description.Inner); text != "" {
+				entry.Mitigations = append(entry.Mitigations, text)
+				entry.MitigationDetails = append(entry.MitigationDetails, CWEMitigation{
+					MitigationID:       mitigation.MitigationID,
+					Phase:              mitigation.Phases,
+					Strategy:           mitigation.Strategy,
+					Description:        text,
+					Effectiveness:      mitigation.Effectiveness,
+					EffectivenessNotes: dictionaryText(mitigation.EffectivenessNotes.Inner),
+				})
+			}
+		}
+		for _, example := range weakness.ObservedExamples {
+			if text := dictionaryText(example.Description.Inner); text != "" {
+				entry.Examples = append(entry.Examples, text)
+				entry.ObservedExamples = append(entry.ObservedExamples, CWEObservedExample{
+					Reference:   strings.TrimSpace(example.Reference),
+					Description: text,
+					Link:        strings.TrimSpace(example.Link),
+				})
+			}
+		}
+
+		relations := make([]CWERelation, 0, len(weakness.RelatedWeaknesses))
+		for _, rel := range weakness.RelatedWeaknesses {
+			relations = append(relations, CWERelation{
+				Nature:  rel.Nature,
+				CweID:   rel.CWEID,
+				ViewID:  rel.ViewID,
+				Ordinal: rel.Ordinal,
+			})
+		}
+		entry.Relations = normalizeRelations(relations)
+		for _, rel := range entry.Relations {
+			if rel.Nature == "ChildOf" && rel.ViewID == viewID {
+				edges = append(edges, dictionaryEdge{parent: rel.CweID, child: entry.ID, primary: rel.Ordinal == "Primary"})
+			}
+		}
+
+		if err := register(entry); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, category := range catalog.Categories {
+		entry := newDictionaryEntry(category.ID, category.Name, category.Status)
+		entry.Description = dictionaryText(category.Summary.Inner)
+		entry.MappingNotes = category.MappingNotes.toMappingNotes()
+		for _, member := range category.Members {
+			if normalizeEntryID(member.ViewID) == viewID {
+				edges = append(edges, dictionaryEdge{parent: entry.ID, child: normalizeEntryID(member.CWEID)})
+			}
+		}
+
+		if err := register(entry); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, view := range catalog.Views {
+		entry := newDictionaryEntry(view.ID, view.Name, view.Status)
+		entry.Description = dictionaryText(view.Objective.Inner)
+		entry.MappingNotes = view.MappingNotes.toMappingNotes()
+		if entry.ID == viewID {
+			// 视图自身的成员列表中View_ID就是该视图，不需要再过滤
+			for _, member := range view.Members {
+				edges = append(edges, dictionaryEdge{parent: entry.ID, child: normalizeEntryID(member.CWEID)})
+			}
+		}
+
+		if err := register(entry); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	root, exists := registry.Entries[viewID]
+	if !exists {
+		return nil, nil, fmt.Errorf("CWE字典中不存在视图%s", viewID)
+	}
+	registry.Root = root
+
+	buildDictionaryHierarchy(registry, root, edges, report)
+	return registry, report, nil
+}
+
+// LoadFromXMLDictionaryFile 从文件加载MITRE官方CWE字典
+// 路径以.zip结尾时读取压缩包中的第一个.xml文件，因此可以直接使用下载的cwec_latest.xml.zip
+// 其余行为与LoadFromXMLDictionary相同
+func LoadFromXMLDictionaryFile(path string, opts XMLDictionaryOptions) (*Registry, *XMLDictionaryReport, error) {
+	if !strings.EqualFold(filepath.Ext(path), ".zip") {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer file.Close()
+		return LoadFromXMLDictionary(bufio.NewReader(file), opts)
+	}
+
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer archive.Close()
+
+	for _, file := range archive.File {
+		if !strings.EqualFold(filepath.Ext(file.Name), ".xml") {
+			continue
+		}
+		content, err := file.Open()
+		if err != nil {
+			return nil, nil, err
+		}
+		defer content.Close()
+		return LoadFromXMLDictionary(content, opts)
+	}
+	return nil, nil, fmt.Errorf("压缩包%s中没有XML文件", path)
+}
+
+// newDictionaryEntry 创建字典条目，ID规范化为"CWE-数字"格式并填充官方详情页网址
+func newDictionaryEntry(id, name, status string) *CWE {
+	entry := NewCWE(normalizeEntryID(id), name)
+	entry.URL = fmt.Sprintf("https://cwe.mitre.org/data/definitions/%s.html", strings.TrimPrefix(entry.ID, "CWE-"))
+	entry.Status = status
+	entry.Language = "en"
+	return entry
+}
+
+// buildDictionaryHierarchy 根据所选视图中的父子关系连接条目
+// 引用了不存在条目的关系记入report.Unresolved；没有父节点的条目挂在root下
+func buildDictionaryHierarchy(registry *Registry, root *CWE, edges []dictionaryEdge, report *XMLDictionaryReport) {
+	sort.Slice(edges, func(i, j int) bool {
+		if c := compareCWEIDs(edges[i].parent, edges[j].parent); c != 0 {
+			return c < 0
+		}
+		return compareCWEIDs(edges[i].child, edges[j].child) < 0
+	})
+
+	linked := make(map[string]bool)
+	hasParent := make(map[string]bool)
+	primary := make(map[*CWE]*CWE)
+	for i, edge := range edges {
+		if edge.parent == edge.child {
+			continue
+		}
+		duplicate := i > 0 && edge.parent == edges[i-1].parent && edge.child == edges[i-1].child
+
+		parent, parentExists := registry.Entries[edge.parent]
+		child, childExists := registry.Entries[edge.child]
+		if !parentExists || !childExists {
+			report.Unresolved = append(report.Unresolved, UnresolvedReference{ParentID: edge.parent, ChildID: edge.child})
+			continue
+		}
+
+		if !duplicate {
+			parent.AddChild(child)
+		}
+		linked[edge.parent] = true
+		linked[edge.child] = true
+		hasParent[edge.child] = true
+		if edge.primary {
+			primary[child] = parent
+		}
+	}
+
+	// AddChild会让Parent指向最后添加的父节点，这里改为指向Primary父节点
+	for child, parent := range primary {
+		child.Parent = parent
+	}
+
+	// 以多父节点模式记录所有父节点，Registry.Parents返回的第一个父节点与Parent一致
+	registry.hierarchyMode = HierarchyMultiParent
+	for _, edge := range edges {
+		parent, parentExists := registry.Entries[edge.parent]
+		child, childExists := registry.Entries[edge.child]
+		if parentExists && childExists && parent != child {
+			registry.recordParent(child, parent)
+		}
+	}
+
+	// 视图中没有父节点的条目(如研究视图中的Pillar)作为视图的直接子节点
+	topLevel := make([]string, 0)
+	for id := range linked {
+		if !hasParent[id] && id != root.ID {
+			topLevel = append(topLevel, id)
+		}
+	}
+	sort.Slice(topLevel, func(i, j int) bool {
+		return compareCWEIDs(topLevel[i], topLevel[j]) < 0
+	})
+	for _, id := range topLevel {
+		root.AddChild(registry.Entries[id])
+	}
+	root.Parent = nil
+
+	sort.Slice(report.Unresolved, func(i, j int) bool {
+		if report.Unresolved[i].ParentID != report.Unresolved[j].ParentID {
+			return report.Unresolved[i].ParentID < report.Unresolved[j].ParentID
+		}
+		return report.Unresolved[i].ChildID < report.Unresolved[j].ChildID
+	})
+}
+
+// joinDictionaryText 将多段结构化文本转换为纯文本，段落之间用空行分隔，空段落被跳过
+func joinDictionaryText(texts ...xmlDictionaryText) string {
+	paragraphs := make([]string, 0, len(texts))
+	for _, text := range texts {
+		if plain := dictionaryText(text.Inner); plain != "" {
+			paragraphs = append(paragraphs, plain)
+		}
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// dictionaryBlockElements 会产生换行的XHTML元素
+var dictionaryBlockElements = map[string]bool{
+	"p": true, "div": true, "br": true, "li": true, "ul": true, "ol": true,
+	"tr": true, "table": true, "pre": true,
+}
+
+// dictionaryText 去除结构化文本中的XHTML标记，折叠空白，块级元素之间用换行分隔
+// 内容无法解析时退回为折叠空白后的原始文本
+func dictionaryText(inner string) string {
+	decoder := xml.NewDecoder(strings.NewReader("<text>" + inner + "</text>"))
+	decoder.Strict = false
+
+	lines := make([]string, 0)
+	var current strings.Builder
+	flush := func() {
+		if line := strings.Join(strings.Fields(current.String()), " "); line != "" {
+			lines = append(lines, line)
+		}
+		current.Reset()
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return strings.Join(strings.Fields(inner), " ")
+		}
+		switch t := token.(type) {
+		case xml.CharData:
+			current.Write(t)
+		case xml.StartElement:
+			if dictionaryBlockElements[t.Name.Local] {
+				flush()
+			}
+		case xml.EndElement:
+			if dictionaryBlockElements[t.Name.Local] {
+				flush()
+			}
+		}
+	}
+	flush()
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,284 @@
+package cwe
+
+import (
+	"archive/zip"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// dictionaryXML 是按官方字典格式裁剪的小样本
+// 研究视图1000: 707 -> {74, 20} -> 79，79的Primary父节点为74
+// 开发视图699: 1019(类别) -> 79
+const dictionaryXML = `<?xml version="1.0" encoding="UTF-8"?>
+<Weakness_Catalog Name="CWE" Version="4.16" Date="2024-11-19"
+    xmlns="http://cwe.mitre.org/cwe-7" xmlns:xhtml="http://www.w3.org/1999/xhtml">
+  <Weaknesses>
+    <Weakness ID="79" Name="Improper Neutralization of Input During Web Page Generation ('Cross-site Scripting')" Abstraction="Base" Status="Stable">
+      <Description>The product does not neutralize user-controllable input.</Description>
+      <Extended_Description>
+        <xhtml:p>Cross-site scripting (XSS) vulnerabilities occur when:</xhtml:p>
+        <xhtml:ul><xhtml:li>Untrusted data enters a web application.</xhtml:li><xhtml:li>The application <xhtml:i>dynamically</xhtml:i> generates a page.</xhtml:li></xhtml:ul>
+      </Extended_Description>
+      <Related_Weaknesses>
+        <Related_Weakness Nature="ChildOf" CWE_ID="74" View_ID="1000" Ordinal="Primary"/>
+        <Related_Weakness Nature="ChildOf" CWE_ID="20" View_ID="1000"/>
+        <Related_Weakness Nature="ChildOf" CWE_ID="74" View_ID="1003" Ordinal="Primary"/>
+        <Related_Weakness Nature="CanPrecede" CWE_ID="494" View_ID="1000"/>
+      </Related_Weaknesses>
+      <Potential_Mitigations>
+        <Mitigation Mitigation_ID="MIT-43">
+          <Phase>Implementation</Phase>
+          <Description><xhtml:p>Use a vetted library &amp; framework.</xhtml:p></Description>
+        </Mitigation>
+      </Potential_Mitigations>
+      <Observed_Examples>
+        <Observed_Example>
+          <Reference>CVE-2021-25926</Reference>
+          <Description>Python Library Manager did not sufficiently neutralize a user-supplied search term.</Description>
+        </Observed_Example>
+      </Observed_Examples>
+      <Mapping_Notes>
+        <Usage>Allowed</Usage>
+        <Rationale>This CWE entry is at the Base level of abstraction.</Rationale>
+        <Comments>Carefully read both the name and description.</Comments>
+        <Reasons><Reason Type="Acceptable-Use"/></Reasons>
+      </Mapping_Notes>
+    </Weakness>
+    <Weakness ID="74" Name="Injection" Abstraction="Class" Status="Incomplete">
+      <Description>Injection.</Description>
+      <Related_Weaknesses>
+        <Related_Weakness Nature="ChildOf" CWE_ID="707" View_ID="1000" Ordinal="Primary"/>
+      </Related_Weaknesses>
+    </Weakness>
+    <Weakness ID="20" Name="Improper Input Validation" Abstraction="Class" Status="Stable">
+      <Description>Input validation.</Description>
+      <Related_Weaknesses>
+        <Related_Weakness Nature="ChildOf" CWE_ID="707" View_ID="1000" Ordinal="Primary"/>
+      </Related_Weaknesses>
+    </Weakness>
+    <Weakness ID="707" Name="Improper Neutralization" Abstraction="Pillar" Status="Incomplete">
+      <Description>Neutralization.</Description>
+    </Weakness>
+    <Weakness ID="71" Name="DEPRECATED: Apple '.DS_Store'" Abstraction="Variant" Status="Deprecated">
+      <Description>This entry has been deprecated.</Description>
+    </Weakness>
+  </Weaknesses>
+  <Categories>
+    <Category ID="1019" Name="Validate Inputs" Status="Draft">
+      <Summary>Weaknesses in this category are related to input validation.</Summary>
+      <Relationships>
+        <Has_Member CWE_ID="79" View_ID="699"/>
+        <Has_Member CWE_ID="9999" View_ID="699"/>
+        <Has_Member CWE_ID="20" View_ID="1008"/>
+      </Relationships>
+      <Mapping_Notes>
+        <Usage>Prohibited</Usage>
+        <Rationale>This entry is a Category.</Rationale>
+        <Reasons><Reason Type="Category"/></Reasons>
+      </Mapping_Notes>
+    </Category>
+  </Categories>
+  <Views>
+    <View ID="1000" Name="Research Concepts" Type="Graph" Status="Draft">
+      <Objective>This view is intended to facilitate research.</Objective>
+    </View>
+    <View ID="699" Name="Software Development" Type="Graph" Status="Draft">
+      <Objective>This view organizes weaknesses around concepts.</Objective>
+      <Members>
+        <Has_Member CWE_ID="1019" View_ID="699"/>
+      </Members>
+    </View>
+  </Views>
+</Weakness_Catalog>`
+
+// TestLoadFromXMLDictionary 测试默认研究视图下的条目转换和层次结构
+func TestLoadFromXMLDictionary(t *testing.T) {
+	registry, report, err := LoadFromXMLDictionary(strings.NewReader(dictionaryXML), XMLDictionaryOptions{})
+	if err != nil {
+		t.Fatalf("LoadFromXMLDictionary失败: %v", err)
+	}
+
+	if report.Version != "4.16" || report.Date != "2024-11-19" {
+		t.Errorf("版本信息不正确: %s %s", report.Version, report.Date)
+	}
+	if report.Weaknesses != 5 || report.Categories != 1 || report.Views != 2 {
+		t.Errorf("条目数量不正确: %+v", report)
+	}
+	if len(registry.Entries) != 8 {
+		t.Errorf("期望8个条目，但得到 %d 个", len(registry.Entries))
+	}
+	if len(report.Unresolved) != 0 {
+		t.Errorf("研究视图不应有未解析引用，但得到 %v", report.Unresolved)
+	}
+
+	if registry.Root == nil || registry.Root.ID != "CWE-1000" {
+		t.Fatalf("根节点应为CWE-1000，但得到 %v", registry.Root)
+	}
+	if len(registry.Root.Children) != 1 || registry.Root.Children[0].ID != "CWE-707" {
+		t.Fatalf("视图下应只有Pillar CWE-707，但得到 %v", registry.Root.Children)
+	}
+
+	xss := registry.Entries["CWE-79"]
+	if xss.Parent == nil || xss.Parent.ID != "CWE-74" {
+		t.Errorf("CWE-79的Parent应为Primary父节点CWE-74，但得到 %v", xss.Parent)
+	}
+	if len(registry.Entries["CWE-20"].Children) != 1 || len(registry.Entries["CWE-74"].Children) != 1 {
+		t.Error("CWE-79应同时出现在CWE-20和CWE-74的Children中")
+	}
+	if parents, _ := registry.Parents("CWE-79"); len(parents) != 2 || parents[0].ID != "CWE-74" || parents[1].ID != "CWE-20" {
+		t.Errorf("Parents应先返回Primary父节点，但得到 %v", parents)
+	}
+	if registry.Entries["CWE-74"].Parent.ID != "CWE-707" {
+		t.Errorf("CWE-74的父节点应为CWE-707")
+	}
+
+	if xss.URL != "https://cwe.mitre.org/data/definitions/79.html" || xss.Status != "Stable" || xss.Language != "en" || xss.Abstraction != "Base" {
+		t.Errorf("基本字段不正确: %s %s %s %s", xss.URL, xss.Status, xss.Language, xss.Abstraction)
+	}
+	expectedDescription := "The product does not neutralize user-controllable input.\n\n" +
+		"Cross-site scripting (XSS) vulnerabilities occur when:\n" +
+		"Untrusted data enters a web application.\n" +
+		"The application dynamically generates a page."
+	if xss.Description != expectedDescription {
+		t.Errorf("描述不正确:\n%q\n期望:\n%q", xss.Description, expectedDescription)
+	}
+	if len(xss.Mitigations) != 1 || xss.Mitigations[0] != "Use a vetted library & framework." {
+		t.Errorf("缓解措施不正确: %q", xss.Mitigations)
+	}
+	if len(xss.MitigationDetails) != 1 || xss.MitigationDetails[0].MitigationID != "MIT-43" ||
+		!reflect.DeepEqual(xss.MitigationDetails[0].Phase, []string{PhaseImplementation}) ||
+		xss.MitigationDetails[0].Description != xss.Mitigations[0] {
+		t.Errorf("类型化的缓解措施不正确: %+v", xss.MitigationDetails)
+	}
+	wantNotes := &CWEMappingNotes{
+		Usage:     MappingAllowed,
+		Rationale: "This CWE entry is at the Base level of abstraction.",
+		Comments:  "Carefully read both the name and description.",
+		Reasons:   []string{"Acceptable-Use"},
+	}
+	if !reflect.DeepEqual(xss.MappingNotes, wantNotes) {
+		t.Errorf("映射注释不正确: %+v", xss.MappingNotes)
+	}
+	if err := registry.ValidateMapping("CWE-1019"); !errors.Is(err, ErrMappingProhibited) {
+		t.Errorf("类别应禁止映射，实际: %v", err)
+	}
+	if len(xss.Examples) != 1 || !strings.HasPrefix(xss.Examples[0], "Python Library Manager") {
+		t.Errorf("示例不正确: %q", xss.Examples)
+	}
+	if len(xss.ObservedExamples) != 1 || xss.ObservedExamples[0].Reference != "CVE-2021-25926" || xss.ObservedExamples[0].Description != xss.Examples[0] {
+		t.Errorf("已观察到的实例不正确: %+v", xss.ObservedExamples)
+	}
+	if len(xss.Relations) != 4 {
+		t.Fatalf("期望4个关系，但得到 %d 个", len(xss.Relations))
+	}
+	if rel := xss.Relations[3]; rel.Nature != "CanPrecede" || rel.CweID != "CWE-494" || rel.ViewID != "CWE-1000" {
+		t.Errorf("关系未正确规范化: %+v", rel)
+	}
+
+	if deprecated := registry.Entries["CWE-71"]; deprecated.Status != StatusDeprecated || deprecated.Parent != nil {
+		t.Errorf("废弃条目应被导入且不在层次结构中: %+v", deprecated)
+	}
+	if category := registry.Entries["CWE-1019"]; !strings.HasPrefix(category.Description, "Weaknesses in this category") {
+		t.Errorf("类别应使用Summary作为描述，但得到 %q", category.Description)
+	}
+}
+
+// TestLoadFromXMLDictionaryView 测试按类别组织的视图和未解析引用
+func TestLoadFromXMLDictionaryView(t *testing.T) {
+	registry, report, err := LoadFromXMLDictionary(strings.NewReader(dictionaryXML), XMLDictionaryOptions{ViewID: "699"})
+	if err != nil {
+		t.Fatalf("LoadFromXMLDictionary失败: %v", err)
+	}
+
+	if registry.Root.ID != "CWE-699" {
+		t.Fatalf("根节点应为CWE-699，但得到 %s", registry.Root.ID)
+	}
+	if len(registry.Root.Children) != 1 || registry.Root.Children[0].ID != "CWE-1019" {
+		t.Fatalf("视图成员不正确: %v", registry.Root.Children)
+	}
+	category := registry.Entries["CWE-1019"]
+	if len(category.Children) != 1 || category.Children[0].ID != "CWE-79" {
+		t.Errorf("类别成员不正确: %v", category.Children)
+	}
+	if registry.Entries["CWE-79"].Parent != category {
+		t.Error("CWE-79的父节点应为类别CWE-1019")
+	}
+	if registry.Entries["CWE-74"].Parent != nil {
+		t.Error("研究视图的关系不应出现在开发视图中")
+	}
+
+	if len(report.Unresolved) != 1 || report.Unresolved[0] != (UnresolvedReference{ParentID: "CWE-1019", ChildID: "CWE-9999"}) {
+		t.Errorf("未解析引用不正确: %v", report.Unresolved)
+	}
+}
+
+// TestLoadFromXMLDictionaryErrors 测试视图不存在、ID重复和格式错误
+func TestLoadFromXMLDictionaryErrors(t *testing.T) {
+	if _, _, err := LoadFromXMLDictionary(strings.NewReader(dictionaryXML), XMLDictionaryOptions{ViewID: "1003"}); err == nil {
+		t.Error("视图不存在时应返回错误")
+	}
+	if _, _, err := LoadFromXMLDictionary(strings.NewReader(dictionaryXML), XMLDictionaryOptions{ViewID: "abc"}); err == nil {
+		t.Error("无效的视图ID应返回错误")
+	}
+	if _, _, err := LoadFromXMLDictionary(strings.NewReader("<Weakness_Catalog>"), XMLDictionaryOptions{}); err == nil {
+		t.Error("格式错误的XML应返回错误")
+	}
+
+	duplicate := strings.Replace(dictionaryXML, `<Weakness ID="71"`, `<Weakness ID="74"`, 1)
+	if _, _, err := LoadFromXMLDictionary(strings.NewReader(duplicate), XMLDictionaryOptions{}); err == nil {
+		t.Error("ID重复时应返回错误")
+	}
+}
+
+// TestLoadFromXMLDictionaryFile 测试从XML文件和官方zip压缩包加载
+func TestLoadFromXMLDictionaryFile(t *testing.T) {
+	dir := t.TempDir()
+
+	xmlPath := filepath.Join(dir, "cwec_v4.16.xml")
+	if err := os.WriteFile(xmlPath, []byte(dictionaryXML), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	zipPath := filepath.Join(dir, "cwec_latest.xml.zip")
+	file, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := zip.NewWriter(file)
+	content, err := archive.Create("cwec_v4.16.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := content.Write([]byte(dictionaryXML)); err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	for _, path := range []string{xmlPath, zipPath} {
+		registry, report, err := LoadFromXMLDictionaryFile(path, XMLDictionaryOptions{})
+		if err != nil {
+			t.Fatalf("加载%s失败: %v", path, err)
+		}
+		if report.Version != "4.16" || len(registry.Entries) != 8 {
+			t.Errorf("%s加载结果不正确: 版本%s，%d个条目", path, report.Version, len(registry.Entries))
+		}
+	}
+
+	emptyZip := filepath.Join(dir, "empty.zip")
+	file, err = os.Create(emptyZip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zip.NewWriter(file).Close()
+	file.Close()
+	if _, _, err := LoadFromXMLDictionaryFile(emptyZip, XMLDictionaryOptions{}); err == nil {
+		t.Error("压缩包中没有XML文件时应返回错误")
+	}
+}
@@ -0,0 +1,157 @@
+package cwe
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRegistryXMLRoundTrip 测试XML导出后再导入能恢复条目和层次结构
+func TestRegistryXMLRoundTrip(t *testing.T) {
+	registry := NewRegistry()
+	root := NewCWE("CWE-1000", "Research View")
+	child := NewCWE("CWE-20", "Improper Input Validation")
+	child.Severity = "High"
+	child.Mitigations = append(child.Mitigations, "输入验证")
+	registry.Register(root)
+	registry.Register(child)
+	root.AddChild(child)
+	registry.Root = root
+
+	data, err := registry.ExportToXML()
+	if err != nil {
+		t.Fatalf("ExportToXML失败: %v", err)
+	}
+
+	imported := NewRegistry()
+	report, err := imported.ImportFromXML(data, XMLImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportFromXML失败: %v", err)
+	}
+
+	if report.HasUnresolved() {
+		t.Errorf("完整导出不应有未解析引用，但得到 %v", report.Unresolved)
+	}
+	if len(imported.Entries) != 2 {
+		t.Fatalf("期望导入2个条目，但得到 %d 个", len(imported.Entries))
+	}
+	if imported.Root == nil || imported.Root.ID != "CWE-1000" {
+		t.Fatalf("根节点未正确恢复: %v", imported.Root)
+	}
+	if len(imported.Root.Children) != 1 || imported.Root.Children[0].ID != "CWE-20" {
+		t.Errorf("子节点关系未正确恢复")
+	}
+	if imported.Entries["CWE-20"].Parent != imported.Root {
+		t.Error("子节点的Parent未指向根节点")
+	}
+	if imported.Entries["CWE-20"].Severity != "High" {
+		t.Errorf("期望严重性为High，但得到 %s", imported.Entries["CWE-20"].Severity)
+	}
+}
+
+// partialXML 是一个引用了缺失子节点的部分导出
+const partialXML = `<?xml version="1.0" encoding="UTF-8"?>
+<cwe-registry version="1.0" rootId="CWE-1000">
+  <entries>
+    <cwe id="CWE-1000" name="Research View">
+      <children><child>CWE-20</child><child>CWE-284</child></children>
+    </cwe>
+    <cwe id="CWE-20" name="Improper Input Validation">
+      <children><child>CWE-284</child><child>CWE-79</child></children>
+    </cwe>
+  </entries>
+</cwe-registry>`
+
+// TestImportFromXMLUnresolved 测试缺失子节点被记录但不会导致导入失败
+func TestImportFromXMLUnresolved(t *testing.T) {
+	registry := NewRegistry()
+	report, err := registry.ImportFromXML([]byte(partialXML), XMLImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportFromXML失败: %v", err)
+	}
+
+	expected := []UnresolvedReference{
+		{ParentID: "CWE-1000", ChildID: "CWE-284"},
+		{ParentID: "CWE-20", ChildID: "CWE-284"},
+		{ParentID: "CWE-20", ChildID: "CWE-79"},
+	}
+	if len(report.Unresolved) != len(expected) {
+		t.Fatalf("期望 %d 个未解析引用，但得到 %v", len(expected), report.Unresolved)
+	}
+	for i, ref := range expected {
+		if report.Unresolved[i] != ref {
+			t.Errorf("未解析引用[%d]期望 %v，但得到 %v", i, ref, report.Unresolved[i])
+		}
+	}
+
+	if len(report.Placeholders) != 0 {
+		t.Errorf("未开启占位节点时不应创建占位节点，但得到 %v", report.Placeholders)
+	}
+	if len(registry.Entries) != 2 {
+		t.Errorf("期望2个条目，但得到 %d 个", len(registry.Entries))
+	}
+	if len(registry.Root.Children) != 1 {
+		t.Errorf("根节点应只保留已解析的子节点，但有 %d 个", len(registry.Root.Children))
+	}
+}
+
+// TestImportFromXMLPlaceholders 测试为缺失子节点创建占位节点
+func TestImportFromXMLPlaceholders(t *testing.T) {
+	registry := NewRegistry()
+	report, err := registry.ImportFromXML([]byte(partialXML), XMLImportOptions{CreatePlaceholders: true})
+	if err != nil {
+		t.Fatalf("ImportFromXML失败: %v", err)
+	}
+
+	if strings.Join(report.Placeholders, ",") != "CWE-284,CWE-79" {
+		t.Errorf("占位节点列表不符合预期: %v", report.Placeholders)
+	}
+	if len(report.Unresolved) != 3 {
+		t.Errorf("每次缺失引用都应被记录，期望3个，但得到 %d 个", len(report.Unresolved))
+	}
+
+	placeholder, err := registry.GetByID("CWE-284")
+	if err != nil {
+		t.Fatalf("占位节点应被注册: %v", err)
+	}
+	if placeholder.Status != StatusIncomplete {
+		t.Errorf("占位节点状态应为%s，但得到 %s", StatusIncomplete, placeholder.Status)
+	}
+	if placeholder.Name != "" {
+		t.Errorf("占位节点只应包含ID，但名称为 %s", placeholder.Name)
+	}
+	if len(registry.Root.Children) != 2 {
+		t.Errorf("根节点应包含占位子节点，期望2个，但有 %d 个", len(registry.Root.Children))
+	}
+}
+
+// TestImportFromXMLErrors 测试XML导入的错误情况
+func TestImportFromXMLErrors(t *testing.T) {
+	registry := NewRegistry()
+
+	if _, err := registry.ImportFromXML(nil, XMLImportOptions{}); err == nil {
+		t.Error("空数据应返回错误")
+	}
+	if _, err := registry.ImportFromXML([]byte("<not-xml"), XMLImportOptions{}); err == nil {
+		t.Error("无效XML应返回错误")
+	}
+
+	noID := `<cwe-registry version="1.0"><entries><cwe name="无ID"></cwe></entries></cwe-registry>`
+	if _, err := registry.ImportFromXML([]byte(noID), XMLImportOptions{}); err == nil {
+		t.Error("无ID条目应返回错误")
+	}
+
+	registry.Register(NewCWE("CWE-1", "原有条目"))
+	duplicate := `<cwe-registry version="1.0"><entries>
+		<cwe id="CWE-79" name="XSS"></cwe><cwe id="CWE-79" name="重复"></cwe>
+	</entries></cwe-registry>`
+	if _, err := registry.ImportFromXML([]byte(duplicate), XMLImportOptions{}); err == nil || !strings.Contains(err.Error(), "CWE-79") {
+		t.Errorf("重复的ID应返回错误，实际为%v", err)
+	}
+	unknownRoot := `<cwe-registry version="1.0" rootId="CWE-1000"><entries><cwe id="CWE-79" name="XSS"></cwe></entries></cwe-registry>`
+	if _, err := registry.ImportFromXML([]byte(unknownRoot), XMLImportOptions{}); err == nil || !strings.Contains(err.Error(), "CWE-1000") {
+		t.Errorf("rootId指向不存在的条目应返回错误，实际为%v", err)
+	}
+	if len(registry.Entries) != 1 || registry.Entries["CWE-1"] == nil {
+		t.Error("导入失败时注册表不应被修改")
+	}
+}
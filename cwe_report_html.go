@@ -0,0 +1,229 @@
+package cwe
+
+import (
+	"html/template"
+	"io"
+	"time"
+)
+
+// DefaultHTMLReportTemplate 是WriteHTMLReport默认使用的模板
+//
+// 模板的数据为*HTMLReport，其中的"node"子模板递归渲染单个节点。
+// 自定义模板时可以以它为基础修改，例如替换样式或增加字段：
+//
+//	tmpl := template.Must(template.New("report").Parse(cwe.DefaultHTMLReportTemplate))
+//	template.Must(tmpl.Parse(`{{define "style"}}body { font-size: 14px; }{{end}}`))
+const DefaultHTMLReportTemplate = `<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>{{template "style" .}}</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">{{.Count}} entries · generated {{.GeneratedAt.Format "2006-01-02 15:04:05 MST"}}</p>
+<div class="tree">
+{{range .Roots}}{{template "node" .}}{{end}}
+</div>
+</body>
+</html>
+{{define "style"}}
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+.meta { color: #666; }
+details { margin-left: 1.2em; border-left: 1px solid #ddd; padding-left: .6em; }
+summary { cursor: pointer; padding: .2em 0; }
+.id { font-family: monospace; font-weight: bold; }
+.severity { border-radius: 3px; padding: 0 .4em; font-size: .85em; }
+.severity-Critical { background: #d73027; color: #fff; }
+.severity-High { background: #fc8d59; }
+.severity-Medium { background: #fee08b; }
+.severity-Low { background: #d9ef8b; }
+.description { color: #444; max-width: 60em; }
+.repeated { color: #888; font-style: italic; }
+{{end}}
+{{define "node"}}<details{{if le .Depth 1}} open{{end}}>
+<summary><span class="id">{{if .URL}}<a href="{{.URL}}">{{.ID}}</a>{{else}}{{.ID}}{{end}}</span> {{.Name}}{{if .Severity}} <span class="severity severity-{{.Severity}}">{{.Severity}}</span>{{end}}</summary>
+{{if .Repeated}}<p class="repeated">Shown above.</p>
+{{else}}{{if .Description}}<p class="description">{{.Description}}</p>
+{{end}}{{if .Mitigations}}<ul class="mitigations">
+{{range .Mitigations}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}{{range .Children}}{{template "node" .}}{{end}}{{end}}</details>
+{{end}}`
+
+// HTMLReportOptions 控制HTML报告的生成
+type HTMLReportOptions struct {
+	// Title 报告标题，为空时使用"CWE Report"
+	Title string
+
+	// RootID 只报告以该条目为根的子树，为空时从注册表的Root开始；注册表没有Root时报告所有条目
+	RootID string
+
+	// Template 自定义模板，数据为*HTMLReport；为nil时使用DefaultHTMLReportTemplate
+	Template *template.Template
+
+	// GeneratedAt 报告中显示的生成时间，为零值时使用当前时间
+	GeneratedAt time.Time
+
+	// Locale 名称和描述使用的语言，如"zh-CN"，没有该语言的翻译时使用原文，见CWE.LocalizedName
+	// 同时作为页面的lang属性，为空时为"en"
+	Locale string
+}
+
+// HTMLReport 是传给报告模板的数据
+type HTMLReport struct {
+	// Title 报告标题
+	Title string
+
+	// Lang 页面的语言标签
+	Lang string
+
+	// GeneratedAt 生成时间
+	GeneratedAt time.Time
+
+	// Count 报告中不重复的条目数量
+	Count int
+
+	// Roots 顶层节点
+	Roots []*HTMLReportNode
+}
+
+// HTMLReportNode 是报告中的一个节点
+type HTMLReportNode struct {
+	ID          string
+	Name        string
+	Description string
+	URL         string
+
+	// Severity 生效的严重性，覆盖层优先
+	Severity    string
+	Mitigations []string
+
+	// Depth 节点深度，顶层节点为0
+	Depth int
+
+	// Repeated 为true表示该条目已在报告中更早的位置出现过(多父节点或环)，不再展开子节点
+	Repeated bool
+
+	// Children 子节点，按CWE编号排序
+	Children []*HTMLReportNode
+}
+
+// defaultHTMLReportTemplate 解析后的默认模板
+var defaultHTMLReportTemplate = template.Must(template.New("report").Parse(DefaultHTMLReportTemplate))
+
+// WriteHTMLReport 将注册表或其中的子树渲染为独立的HTML页面
+//
+// 方法功能:
+// 生成不依赖外部资源的单个HTML文件，以可折叠的树展示条目的ID、名称、严重性、描述和缓解措施，
+// 便于分享给不使用命令行工具的人员。默认只展开前两层。
+// 同一个条目在树中出现多次时只在第一次出现的位置展开，之后的位置标记为重复。
+//
+// 参数:
+// - w: io.Writer - 输出目标
+// - opts: HTMLReportOptions - 报告选项
+//
+// 返回值:
+// - error: RootID不存在、模板执行失败或写入失败时返回错误
+//
+// 使用示例:
+// ```go
+// file, _ := os.Create("input-validation.html")
+// defer file.Close()
+//
+//	err := registry.WriteHTMLReport(file, cwe.HTMLReportOptions{
+//	    Title:  "Input Validation",
+//	    RootID: "CWE-20",
+//	})
+//
+// ```
+func (r *Registry) WriteHTMLReport(w io.Writer, opts HTMLReportOptions) error {
+	report, err := r.HTMLReport(opts)
+	if err != nil {
+		return err
+	}
+
+	tmpl := opts.Template
+	if tmpl == nil {
+		tmpl = defaultHTMLReportTemplate
+	}
+	return tmpl.Execute(w, report)
+}
+
+// HTMLReport 按选项收集报告数据但不渲染，可用于在其他模板引擎中渲染
+func (r *Registry) HTMLReport(opts HTMLReportOptions) (*HTMLReport, error) {
+	d, err := r.diagram(DiagramOptions{RootID: opts.RootID})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &HTMLReport{
+		Title:       opts.Title,
+		Lang:        NormalizeLocale(opts.Locale),
+		GeneratedAt: opts.GeneratedAt,
+		Count:       len(d.nodes),
+	}
+	if report.Lang == "" {
+		report.Lang = DefaultLanguage
+	}
+	if report.Title == "" {
+		report.Title = "CWE Report"
+	}
+	if report.GeneratedAt.IsZero() {
+		report.GeneratedAt = time.Now()
+	}
+
+	var roots []*CWE
+	switch {
+	case opts.RootID != "":
+		roots = d.nodes[:1]
+	case r.Root != nil:
+		roots = []*CWE{r.Root}
+	default:
+		for _, entry := range d.nodes {
+			if entry.Parent == nil {
+				roots = append(roots, entry)
+			}
+		}
+	}
+
+	visited := make(map[string]bool)
+	for _, root := range roots {
+		report.Roots = append(report.Roots, r.htmlReportNode(root, 0, opts.Locale, visited))
+	}
+	if opts.RootID == "" && r.Root == nil {
+		// 环中的条目都有父节点，补充为顶层节点
+		for _, entry := range d.nodes {
+			if !visited[entry.ID] {
+				report.Roots = append(report.Roots, r.htmlReportNode(entry, 0, opts.Locale, visited))
+			}
+		}
+	}
+	return report, nil
+}
+
+// htmlReportNode 递归创建报告节点，名称和描述使用locale的翻译，visited记录已展开的条目
+func (r *Registry) htmlReportNode(entry *CWE, depth int, locale string, visited map[string]bool) *HTMLReportNode {
+	node := &HTMLReportNode{
+		ID:          entry.ID,
+		Name:        entry.LocalizedName(locale),
+		Description: entry.LocalizedDescription(locale),
+		URL:         entry.URL,
+		Severity:    r.diagramSeverity(entry),
+		Mitigations: entry.Mitigations,
+		Depth:       depth,
+		Repeated:    visited[entry.ID],
+	}
+	if node.Repeated {
+		return node
+	}
+	visited[entry.ID] = true
+
+	children := append([]*CWE(nil), entry.Children...)
+	sortByCWEID(children)
+	for _, child := range children {
+		node.Children = append(node.Children, r.htmlReportNode(child, depth+1, locale, visited))
+	}
+	return node
+}
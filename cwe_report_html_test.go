@@ -0,0 +1,101 @@
+package cwe
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRegistryWriteHTMLReport 测试使用默认模板生成HTML报告
+func TestRegistryWriteHTMLReport(t *testing.T) {
+	registry := newExportTestRegistry()
+	registry.Entries["CWE-79"].Description = "Improper neutralization & encoding"
+	registry.Entries["CWE-79"].URL = "https://cwe.mitre.org/data/definitions/79.html"
+
+	var buf bytes.Buffer
+	err := registry.WriteHTMLReport(&buf, HTMLReportOptions{
+		GeneratedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("WriteHTMLReport失败: %v", err)
+	}
+	output := buf.String()
+
+	for _, expected := range []string{
+		"<title>CWE Report</title>",
+		"4 entries · generated 2024-01-02 03:04:05 UTC",
+		`<a href="https://cwe.mitre.org/data/definitions/79.html">CWE-79</a>`,
+		`<span class="severity severity-High">High</span>`,
+		"Improper neutralization &amp; encoding",
+		"<li>对输出进行编码</li>",
+		"SQL Injection &lt;script&gt;",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("报告中缺少%q:\n%s", expected, output)
+		}
+	}
+	if strings.Index(output, "CWE-20") > strings.Index(output, "CWE-79") {
+		t.Error("父节点应出现在子节点之前")
+	}
+}
+
+// TestRegistryHTMLReportSubtree 测试子树报告和重复条目
+func TestRegistryHTMLReportSubtree(t *testing.T) {
+	registry := newExportTestRegistry()
+	registry.SetHierarchyMode(HierarchyMultiParent)
+	registry.Register(NewCWE("CWE-74", "Injection"))
+	registry.AddChild("CWE-1000", "CWE-74")
+	registry.AddChild("CWE-74", "CWE-89")
+
+	report, err := registry.HTMLReport(HTMLReportOptions{Title: "Research"})
+	if err != nil {
+		t.Fatalf("HTMLReport失败: %v", err)
+	}
+	if report.Title != "Research" || report.Count != 5 || len(report.Roots) != 1 {
+		t.Fatalf("报告数据不正确: %+v", report)
+	}
+	injection := report.Roots[0].Children[1]
+	if injection.ID != "CWE-74" || !injection.Children[0].Repeated || injection.Children[0].Depth != 2 {
+		t.Errorf("第二次出现的CWE-89应标记为重复: %+v", injection.Children[0])
+	}
+
+	report, err = registry.HTMLReport(HTMLReportOptions{RootID: "20"})
+	if err != nil {
+		t.Fatalf("HTMLReport失败: %v", err)
+	}
+	if report.Count != 3 || report.Roots[0].ID != "CWE-20" || len(report.Roots[0].Children) != 2 {
+		t.Errorf("子树报告不正确: %+v", report.Roots[0])
+	}
+
+	if _, err := registry.HTMLReport(HTMLReportOptions{RootID: "CWE-99999"}); err == nil {
+		t.Error("RootID不存在时应返回错误")
+	}
+}
+
+// TestRegistryWriteHTMLReportCustomTemplate 测试自定义模板
+func TestRegistryWriteHTMLReportCustomTemplate(t *testing.T) {
+	registry := newExportTestRegistry()
+
+	tmpl := template.Must(template.New("report").Parse(`{{.Title}}:{{range .Roots}}{{template "node" .}}{{end}}` +
+		`{{define "node"}} {{.ID}}{{range .Children}}{{template "node" .}}{{end}}{{end}}`))
+	var buf bytes.Buffer
+	if err := registry.WriteHTMLReport(&buf, HTMLReportOptions{Title: "T", Template: tmpl}); err != nil {
+		t.Fatalf("WriteHTMLReport失败: %v", err)
+	}
+	if buf.String() != "T: CWE-1000 CWE-20 CWE-79 CWE-89" {
+		t.Errorf("自定义模板输出不正确: %q", buf.String())
+	}
+
+	// 在默认模板的基础上只替换样式
+	custom := template.Must(template.New("report").Parse(DefaultHTMLReportTemplate))
+	template.Must(custom.Parse(`{{define "style"}}body { color: red; }{{end}}`))
+	buf.Reset()
+	if err := registry.WriteHTMLReport(&buf, HTMLReportOptions{Template: custom}); err != nil {
+		t.Fatalf("WriteHTMLReport失败: %v", err)
+	}
+	if !strings.Contains(buf.String(), "body { color: red; }") || strings.Contains(buf.String(), "font-family") {
+		t.Errorf("样式未被替换:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,408 @@
+package cwe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// RuleMapping 维护检测规则ID与CWE ID之间的映射关系
+//
+// 面向静态分析工具等规则作者：每条规则可以映射到一个或多个CWE，
+// 映射可以在代码中通过Map注册，也可以通过LoadJSON从文件加载。
+// 注册后可以查询覆盖情况、找出映射到已废弃CWE的规则，并导出为SARIF兼容格式。
+//
+// RuleMapping是并发安全的，可以在多个goroutine中使用
+type RuleMapping struct {
+	mutex sync.RWMutex
+
+	// rules 规则ID到CWE ID列表的映射，CWE ID已规范化且去重
+	rules map[string][]string
+}
+
+// RuleMappingIssue 表示一条有问题的规则映射
+type RuleMappingIssue struct {
+	// RuleID 规则ID
+	RuleID string
+
+	// CWEID 映射到的CWE ID
+	CWEID string
+
+	// Reason 问题描述
+	Reason string
+}
+
+// RuleCoverage 表示规则集对注册表中CWE的覆盖情况
+type RuleCoverage struct {
+	// Covered 至少有一条规则映射到的CWE ID，已排序
+	Covered []string
+
+	// Uncovered 没有任何规则映射到的CWE ID，已排序
+	Uncovered []string
+
+	// Ratio 覆盖率，取值范围[0, 1]；注册表为空时为0
+	Ratio float64
+}
+
+// NewRuleMapping 创建一个空的规则映射
+func NewRuleMapping() *RuleMapping {
+	return &RuleMapping{
+		rules: make(map[string][]string),
+	}
+}
+
+// Map 将规则映射到一个或多个CWE
+//
+// 方法功能:
+// 为指定规则添加CWE映射。CWE ID会通过ParseCWEID规范化，重复的映射会被忽略。
+// 同一规则可以多次调用Map，映射会累积。
+//
+// 参数:
+// - ruleID: string - 规则ID，不能为空
+// - cweIDs: ...string - 一个或多个CWE ID，支持ParseCWEID接受的所有格式
+//
+// 返回值:
+// - error: 规则ID为空、未提供CWE ID或CWE ID无法解析时返回错误；出错时不会修改映射
+//
+// 使用示例:
+// ```go
+// mapping := cwe.NewRuleMapping()
+// mapping.Map("go/sql-injection", "CWE-89")
+// mapping.Map("go/reflected-xss", "79", "CWE-116")
+// ```
+func (m *RuleMapping) Map(ruleID string, cweIDs ...string) error {
+	ruleID = strings.TrimSpace(ruleID)
+	if ruleID == "" {
+		return errors.New("规则ID不能为空")
+	}
+	if len(cweIDs) == 0 {
+		return fmt.Errorf("规则%s必须映射到至少一个CWE", ruleID)
+	}
+
+	normalized := make([]string, 0, len(cweIDs))
+	for _, id := range cweIDs {
+		cweID, err := ParseCWEID(id)
+		if err != nil {
+			return fmt.Errorf("规则%s的CWE ID %q无效: %w", ruleID, id, err)
+		}
+		normalized = append(normalized, cweID)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	existing := m.rules[ruleID]
+	for _, cweID := range normalized {
+		if !containsString(existing, cweID) {
+			existing = append(existing, cweID)
+		}
+	}
+	m.rules[ruleID] = existing
+
+	return nil
+}
+
+// LoadJSON 从JSON数据加载规则映射
+//
+// 方法功能:
+// 解析形如{"规则ID": ["CWE-79", "CWE-80"]}的JSON数据，并将其中的映射累加到当前映射中。
+//
+// 参数:
+// - data: []byte - JSON数据
+//
+// 返回值:
+// - error: 解析失败或任一映射无效时返回错误
+//
+// 数据样例:
+// ```json
+//
+//	{
+//	  "go/sql-injection": ["CWE-89"],
+//	  "go/reflected-xss": ["CWE-79", "CWE-116"]
+//	}
+//
+// ```
+func (m *RuleMapping) LoadJSON(data []byte) error {
+	var raw map[string][]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	// 按规则ID排序，使错误信息稳定
+	ruleIDs := make([]string, 0, len(raw))
+	for ruleID := range raw {
+		ruleIDs = append(ruleIDs, ruleID)
+	}
+	sort.Strings(ruleIDs)
+
+	for _, ruleID := range ruleIDs {
+		if err := m.Map(ruleID, raw[ruleID]...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Rules 返回所有已注册的规则ID，已排序
+func (m *RuleMapping) Rules() []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	ruleIDs := make([]string, 0, len(m.rules))
+	for ruleID := range m.rules {
+		ruleIDs = append(ruleIDs, ruleID)
+	}
+	sort.Strings(ruleIDs)
+	return ruleIDs
+}
+
+// CWEsFor 返回规则映射到的CWE ID列表，已排序；规则不存在时返回空切片
+func (m *RuleMapping) CWEsFor(ruleID string) []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	result := append([]string{}, m.rules[ruleID]...)
+	sort.Strings(result)
+	return result
+}
+
+// RulesFor 返回映射到指定CWE的规则ID列表，已排序
+// cweID会通过ParseCWEID规范化，无法解析时返回空切片
+func (m *RuleMapping) RulesFor(cweID string) []string {
+	result := make([]string, 0)
+
+	normalized, err := ParseCWEID(cweID)
+	if err != nil {
+		return result
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for ruleID, cweIDs := range m.rules {
+		if containsString(cweIDs, normalized) {
+			result = append(result, ruleID)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Coverage 计算规则集对注册表中CWE的覆盖情况
+//
+// 方法功能:
+// 检查注册表中的每个条目是否至少被一条规则映射到。
+// 映射到注册表之外CWE的规则不影响结果。
+//
+// 参数:
+// - registry: *Registry - 作为覆盖范围基准的注册表
+//
+// 返回值:
+// - *RuleCoverage: 覆盖情况
+func (m *RuleMapping) Coverage(registry *Registry) *RuleCoverage {
+	mapped := m.mappedCWEs()
+
+	coverage := &RuleCoverage{
+		Covered:   make([]string, 0),
+		Uncovered: make([]string, 0),
+	}
+	for id := range registry.Entries {
+		if mapped[id] {
+			coverage.Covered = append(coverage.Covered, id)
+		} else {
+			coverage.Uncovered = append(coverage.Uncovered, id)
+		}
+	}
+	sort.Strings(coverage.Covered)
+	sort.Strings(coverage.Uncovered)
+
+	if len(registry.Entries) > 0 {
+		coverage.Ratio = float64(len(coverage.Covered)) / float64(len(registry.Entries))
+	}
+
+	return coverage
+}
+
+// Validate 检查规则映射中的问题
+//
+// 方法功能:
+// 对照注册表检查每条映射，找出映射到已废弃CWE(Status为StatusDeprecated)
+// 或注册表中不存在的CWE的规则，便于规则作者及时更新映射。
+//
+// 参数:
+// - registry: *Registry - 用于校验的注册表
+//
+// 返回值:
+// - []RuleMappingIssue: 发现的问题，按规则ID和CWE ID排序；没有问题时返回空切片
+func (m *RuleMapping) Validate(registry *Registry) []RuleMappingIssue {
+	issues := make([]RuleMappingIssue, 0)
+
+	for _, ruleID := range m.Rules() {
+		for _, cweID := range m.CWEsFor(ruleID) {
+			entry, exists := registry.Entries[cweID]
+			switch {
+			case !exists:
+				issues = append(issues, RuleMappingIssue{RuleID: ruleID, CWEID: cweID, Reason: "CWE不存在于注册表中"})
+			case strings.EqualFold(entry.Status, StatusDeprecated):
+				issues = append(issues, RuleMappingIssue{RuleID: ruleID, CWEID: cweID, Reason: "CWE已被废弃"})
+			}
+		}
+	}
+
+	return issues
+}
+
+// DeprecatedMappings 返回映射到已废弃CWE的规则
+// 是Validate的便捷形式，只保留废弃相关的问题
+func (m *RuleMapping) DeprecatedMappings(registry *Registry) []RuleMappingIssue {
+	result := make([]RuleMappingIssue, 0)
+	for _, issue := range m.Validate(registry) {
+		if entry, exists := registry.Entries[issue.CWEID]; exists && strings.EqualFold(entry.Status, StatusDeprecated) {
+			result = append(result, issue)
+		}
+	}
+	return result
+}
+
+// ExportSARIF 将规则映射导出为SARIF 2.1.0兼容的JSON
+//
+// 方法功能:
+// 生成一个只包含工具规则定义的SARIF日志：每条规则通过relationships
+// 引用CWE分类法(taxonomy)中的条目，可直接合并到扫描工具输出的SARIF中。
+// 如提供了registry，分类法条目会带上CWE名称。
+//
+// 参数:
+// - toolName: string - 工具名称，写入tool.driver.name
+// - registry: *Registry - 可选的注册表，用于补充CWE名称，可为nil
+//
+// 返回值:
+// - []byte: 缩进格式的SARIF JSON
+// - error: 序列化失败时返回错误
+func (m *RuleMapping) ExportSARIF(toolName string, registry *Registry) ([]byte, error) {
+	type sarifToolComponentRef struct {
+		Name string `json:"name"`
+	}
+	type sarifTarget struct {
+		ID            string                `json:"id"`
+		ToolComponent sarifToolComponentRef `json:"toolComponent"`
+	}
+	type sarifRelationship struct {
+		Target sarifTarget `json:"target"`
+		Kinds  []string    `json:"kinds"`
+	}
+	type sarifRule struct {
+		ID            string              `json:"id"`
+		Relationships []sarifRelationship `json:"relationships"`
+	}
+	type sarifTaxon struct {
+		ID               string            `json:"id"`
+		Name             string            `json:"name,omitempty"`
+		ShortDescription map[string]string `json:"shortDescription,omitempty"`
+	}
+	type sarifTaxonomy struct {
+		Name         string       `json:"name"`
+		Organization string       `json:"organization"`
+		Taxa         []sarifTaxon `json:"taxa"`
+	}
+	type sarifDriver struct {
+		Name  string                  `json:"name"`
+		Rules []sarifRule             `json:"rules"`
+		Taxa  []sarifToolComponentRef `json:"supportedTaxonomies"`
+	}
+	type sarifRun struct {
+		Tool       map[string]sarifDriver `json:"tool"`
+		Taxonomies []sarifTaxonomy        `json:"taxonomies"`
+	}
+	type sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+
+	rules := make([]sarifRule, 0)
+	taxonIDs := make(map[string]bool)
+	for _, ruleID := range m.Rules() {
+		rule := sarifRule{ID: ruleID, Relationships: make([]sarifRelationship, 0)}
+		for _, cweID := range m.CWEsFor(ruleID) {
+			taxonID := strings.TrimPrefix(cweID, "CWE-")
+			taxonIDs[taxonID] = true
+			rule.Relationships = append(rule.Relationships, sarifRelationship{
+				Target: sarifTarget{ID: taxonID, ToolComponent: sarifToolComponentRef{Name: "CWE"}},
+				Kinds:  []string{"superset"},
+			})
+		}
+		rules = append(rules, rule)
+	}
+
+	sortedTaxa := make([]string, 0, len(taxonIDs))
+	for id := range taxonIDs {
+		sortedTaxa = append(sortedTaxa, id)
+	}
+	sort.Slice(sortedTaxa, func(i, j int) bool {
+		a, _ := strconv.Atoi(sortedTaxa[i])
+		b, _ := strconv.Atoi(sortedTaxa[j])
+		return a < b
+	})
+
+	taxa := make([]sarifTaxon, 0, len(sortedTaxa))
+	for _, id := range sortedTaxa {
+		taxon := sarifTaxon{ID: id}
+		if registry != nil {
+			if entry, exists := registry.Entries["CWE-"+id]; exists && entry.Name != "" {
+				taxon.Name = entry.Name
+				taxon.ShortDescription = map[string]string{"text": entry.Name}
+			}
+		}
+		taxa = append(taxa, taxon)
+	}
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: map[string]sarifDriver{
+				"driver": {
+					Name:  toolName,
+					Rules: rules,
+					Taxa:  []sarifToolComponentRef{{Name: "CWE"}},
+				},
+			},
+			Taxonomies: []sarifTaxonomy{{
+				Name:         "CWE",
+				Organization: "MITRE",
+				Taxa:         taxa,
+			}},
+		}},
+	}
+
+	return json.MarshalIndent(log, "", "  ")
+}
+
+// mappedCWEs 返回所有被至少一条规则映射到的CWE ID集合
+func (m *RuleMapping) mappedCWEs() map[string]bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	mapped := make(map[string]bool)
+	for _, cweIDs := range m.rules {
+		for _, cweID := range cweIDs {
+			mapped[cweID] = true
+		}
+	}
+	return mapped
+}
+
+// containsString 判断字符串切片中是否包含指定字符串
+func containsString(slice []string, s string) bool {
+	for _, item := range slice {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package cwe
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func newRuleMappingTestRegistry() *Registry {
+	registry := NewRegistry()
+	registry.Register(NewCWE("CWE-79", "Cross-site Scripting"))
+	registry.Register(NewCWE("CWE-89", "SQL Injection"))
+	registry.Register(NewCWE("CWE-20", "Improper Input Validation"))
+
+	deprecated := NewCWE("CWE-19", "Data Processing Errors")
+	deprecated.Status = StatusDeprecated
+	registry.Register(deprecated)
+
+	return registry
+}
+
+func TestRuleMappingMap(t *testing.T) {
+	mapping := NewRuleMapping()
+
+	if err := mapping.Map("go/xss", "79", "cwe-79", "CWE-116"); err != nil {
+		t.Fatalf("Map失败: %v", err)
+	}
+	if err := mapping.Map("go/xss", "CWE-79"); err != nil {
+		t.Fatalf("重复Map失败: %v", err)
+	}
+
+	if got := mapping.CWEsFor("go/xss"); !reflect.DeepEqual(got, []string{"CWE-116", "CWE-79"}) {
+		t.Errorf("CWEsFor结果不符合预期: %v", got)
+	}
+	if got := mapping.RulesFor("79"); !reflect.DeepEqual(got, []string{"go/xss"}) {
+		t.Errorf("RulesFor结果不符合预期: %v", got)
+	}
+
+	if err := mapping.Map("", "CWE-79"); err == nil {
+		t.Error("空规则ID应返回错误")
+	}
+	if err := mapping.Map("go/empty"); err == nil {
+		t.Error("未提供CWE ID应返回错误")
+	}
+	if err := mapping.Map("go/bad", "not-a-cwe"); err == nil {
+		t.Error("无效CWE ID应返回错误")
+	}
+	if len(mapping.Rules()) != 1 {
+		t.Errorf("出错的映射不应被注册，但规则列表为 %v", mapping.Rules())
+	}
+}
+
+func TestRuleMappingLoadJSON(t *testing.T) {
+	mapping := NewRuleMapping()
+	data := []byte(`{"go/sqli": ["CWE-89"], "go/legacy": ["19", "CWE-20"]}`)
+	if err := mapping.LoadJSON(data); err != nil {
+		t.Fatalf("LoadJSON失败: %v", err)
+	}
+
+	if got := mapping.Rules(); !reflect.DeepEqual(got, []string{"go/legacy", "go/sqli"}) {
+		t.Errorf("规则列表不符合预期: %v", got)
+	}
+
+	if err := mapping.LoadJSON([]byte(`{`)); err == nil {
+		t.Error("无效JSON应返回错误")
+	}
+}
+
+func TestRuleMappingCoverageAndValidate(t *testing.T) {
+	registry := newRuleMappingTestRegistry()
+	mapping := NewRuleMapping()
+	mapping.Map("go/sqli", "CWE-89")
+	mapping.Map("go/legacy", "CWE-19", "CWE-9999")
+
+	coverage := mapping.Coverage(registry)
+	if !reflect.DeepEqual(coverage.Covered, []string{"CWE-19", "CWE-89"}) {
+		t.Errorf("Covered不符合预期: %v", coverage.Covered)
+	}
+	if !reflect.DeepEqual(coverage.Uncovered, []string{"CWE-20", "CWE-79"}) {
+		t.Errorf("Uncovered不符合预期: %v", coverage.Uncovered)
+	}
+	if coverage.Ratio != 0.5 {
+		t.Errorf("期望覆盖率0.5，但得到 %v", coverage.Ratio)
+	}
+
+	issues := mapping.Validate(registry)
+	if len(issues) != 2 {
+		t.Fatalf("期望2个问题，但得到 %v", issues)
+	}
+
+	deprecated := mapping.DeprecatedMappings(registry)
+	if len(deprecated) != 1 || deprecated[0].RuleID != "go/legacy" || deprecated[0].CWEID != "CWE-19" {
+		t.Errorf("废弃映射不符合预期: %v", deprecated)
+	}
+}
+
+func TestRuleMappingExportSARIF(t *testing.T) {
+	registry := newRuleMappingTestRegistry()
+	mapping := NewRuleMapping()
+	mapping.Map("go/sqli", "CWE-89")
+	mapping.Map("go/xss", "CWE-79")
+
+	data, err := mapping.ExportSARIF("example-scanner", registry)
+	if err != nil {
+		t.Fatalf("ExportSARIF失败: %v", err)
+	}
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID            string `json:"id"`
+						Relationships []struct {
+							Target struct {
+								ID string `json:"id"`
+							} `json:"target"`
+						} `json:"relationships"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Taxonomies []struct {
+				Name string `json:"name"`
+				Taxa []struct {
+					ID   string `json:"id"`
+					Name string `json:"name"`
+				} `json:"taxa"`
+			} `json:"taxonomies"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("SARIF输出无法解析: %v", err)
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("SARIF结构不符合预期: %s", data)
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "example-scanner" || len(run.Tool.Driver.Rules) != 2 {
+		t.Errorf("driver不符合预期: %+v", run.Tool.Driver)
+	}
+	if run.Tool.Driver.Rules[0].ID != "go/sqli" || run.Tool.Driver.Rules[0].Relationships[0].Target.ID != "89" {
+		t.Errorf("规则关系不符合预期: %+v", run.Tool.Driver.Rules[0])
+	}
+	taxa := run.Taxonomies[0].Taxa
+	if len(taxa) != 2 || taxa[0].ID != "79" || taxa[0].Name != "Cross-site Scripting" {
+		t.Errorf("分类法条目不符合预期: %+v", taxa)
+	}
+}
@@ -0,0 +1,222 @@
+package cwe
+
+import (
+	"strings"
+)
+
+// CVSS影响指标的取值，与CVSS v3.1向量中的缩写相同
+const (
+	// CVSSImpactHigh 高影响(H)
+	CVSSImpactHigh = "H"
+
+	// CVSSImpactLow 低影响(L)
+	CVSSImpactLow = "L"
+
+	// CVSSImpactNone 无影响(N)
+	CVSSImpactNone = "N"
+)
+
+// CVSSAttackVectorNetwork 表示可通过网络利用的攻击向量(AV:N)
+const CVSSAttackVectorNetwork = "N"
+
+// highImpacts 在CVSS中通常对应高影响的CWE影响类型
+// 以"DoS:"开头的影响对可用性同样视为高影响，见impactLevel
+var highImpacts = map[string]bool{
+	"execute unauthorized code or commands": true,
+	"gain privileges or assume identity":    true,
+	"bypass protection mechanism":           true,
+	"read application data":                 true,
+	"modify application data":               true,
+	"read memory":                           true,
+	"modify memory":                         true,
+	"read files or directories":             true,
+	"modify files or directories":           true,
+}
+
+// impactRanks CVSS影响级别从低到高的排序，空值表示未知
+var impactRanks = map[string]int{
+	"":             0,
+	CVSSImpactNone: 1,
+	CVSSImpactLow:  2,
+	CVSSImpactHigh: 3,
+}
+
+// networkPlatforms 表示弱点通常可通过网络利用的适用平台类别
+var networkPlatforms = map[string]bool{
+	"web based":       true,
+	"web server":      true,
+	"cloud computing": true,
+	"client server":   true,
+}
+
+// ScoringHint 是根据CWE分类给出的CVSS评分参考
+//
+// 它只依据弱点条目本身的常见影响、适用平台和利用可能性推断，
+// 用于扫描器在只知道CWE分类时给出初始的严重性估计，不能代替针对具体漏洞的CVSS评估。
+// 无法推断的指标为空字符串。
+type ScoringHint struct {
+	// CWEID 弱点ID
+	CWEID string `json:"cwe_id"`
+
+	// LikelihoodOfExploit 利用可能性(High, Medium, Low)，取自弱点条目
+	LikelihoodOfExploit string `json:"likelihood_of_exploit,omitempty"`
+
+	// AttackVector CVSS攻击向量，适用平台包含Web等网络类别时为CVSSAttackVectorNetwork
+	AttackVector string `json:"attack_vector,omitempty"`
+
+	// Confidentiality 机密性影响，取值为CVSSImpactHigh、CVSSImpactLow或CVSSImpactNone
+	Confidentiality string `json:"confidentiality,omitempty"`
+
+	// Integrity 完整性影响
+	Integrity string `json:"integrity,omitempty"`
+
+	// Availability 可用性影响
+	Availability string `json:"availability,omitempty"`
+
+	// Impacts 常见影响中出现的所有影响类型，按首次出现的顺序去重
+	Impacts []string `json:"impacts,omitempty"`
+
+	// Severity 估计的严重性(Critical, High, Medium, Low)，没有常见影响信息时为空
+	Severity string `json:"severity,omitempty"`
+}
+
+// NewScoringHint 根据API返回的弱点信息生成CVSS评分参考
+//
+// 方法功能:
+// 将常见影响(common_consequences)的范围映射到CVSS的机密性、完整性和可用性指标：
+// 执行代码、提升权限、读写数据或内存等影响视为高影响，"DoS:"类影响对可用性视为高影响，其余视为低影响；
+// 执行代码的影响同时使三项指标都为高。没有常见影响信息时三项指标为空，表示未知。
+// 常见影响中出现过但未涉及的范围为CVSSImpactNone。
+// 适用平台包含Web Based、Web Server、Cloud Computing或Client Server时攻击向量为网络。
+//
+// 严重性估计规则: 三项指标都为高且可通过网络利用时为Critical，任一指标为高时为High，
+// 否则为Medium；利用可能性为Low时再降低一级。
+//
+// 参数:
+// - weakness: *CWEWeakness - 弱点信息，通常来自FetchWeaknessFull
+//
+// 返回值:
+// - ScoringHint: 评分参考，weakness为nil时返回零值
+//
+// 使用示例:
+// ```go
+// _, weakness, err := fetcher.FetchWeaknessFull("CWE-89")
+// hint := cwe.NewScoringHint(weakness)
+// fmt.Println(hint.Vector(), hint.Severity) // CVSS:3.1/AV:N/C:H/I:H/A:H Critical
+// ```
+func NewScoringHint(weakness *CWEWeakness) ScoringHint {
+	if weakness == nil {
+		return ScoringHint{}
+	}
+
+	hint := ScoringHint{
+		CWEID:               normalizeEntryID(weakness.ID),
+		LikelihoodOfExploit: strings.TrimSpace(weakness.LikelihoodOfExploit),
+	}
+
+	for _, platform := range weakness.ApplicablePlatforms {
+		if networkPlatforms[strings.ToLower(strings.TrimSpace(platform.Class))] {
+			hint.AttackVector = CVSSAttackVectorNetwork
+			break
+		}
+	}
+
+	if len(weakness.CommonConsequences) == 0 {
+		return hint
+	}
+
+	hint.Confidentiality = CVSSImpactNone
+	hint.Integrity = CVSSImpactNone
+	hint.Availability = CVSSImpactNone
+	for _, consequence := range weakness.CommonConsequences {
+		for _, impact := range consequence.Impact {
+			impact = strings.TrimSpace(impact)
+			if impact != "" && !containsString(hint.Impacts, impact) {
+				hint.Impacts = append(hint.Impacts, impact)
+			}
+			if strings.EqualFold(impact, "Execute Unauthorized Code or Commands") {
+				hint.Confidentiality = CVSSImpactHigh
+				hint.Integrity = CVSSImpactHigh
+				hint.Availability = CVSSImpactHigh
+			}
+		}
+
+		for _, scope := range consequence.Scope {
+			switch strings.ToLower(strings.TrimSpace(scope)) {
+			case "confidentiality":
+				hint.Confidentiality = maxImpact(hint.Confidentiality, impactLevel(consequence.Impact, false))
+			case "integrity":
+				hint.Integrity = maxImpact(hint.Integrity, impactLevel(consequence.Impact, false))
+			case "availability":
+				hint.Availability = maxImpact(hint.Availability, impactLevel(consequence.Impact, true))
+			}
+		}
+	}
+
+	hint.Severity = hint.estimateSeverity()
+	return hint
+}
+
+// Vector 返回由已推断指标组成的部分CVSS v3.1向量
+// 只包含AV、C、I、A中已知的指标，如"CVSS:3.1/AV:N/C:H/I:L/A:N"；没有任何已知指标时返回空字符串
+func (h ScoringHint) Vector() string {
+	parts := make([]string, 0, 4)
+	for _, metric := range []struct{ name, value string }{
+		{"AV", h.AttackVector},
+		{"C", h.Confidentiality},
+		{"I", h.Integrity},
+		{"A", h.Availability},
+	} {
+		if metric.value != "" {
+			parts = append(parts, metric.name+":"+metric.value)
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "CVSS:3.1/" + strings.Join(parts, "/")
+}
+
+// estimateSeverity 按NewScoringHint中描述的规则估计严重性
+func (h ScoringHint) estimateSeverity() string {
+	levels := []string{"Low", "Medium", "High", "Critical"}
+
+	highCount := 0
+	for _, value := range []string{h.Confidentiality, h.Integrity, h.Availability} {
+		if value == CVSSImpactHigh {
+			highCount++
+		}
+	}
+
+	level := 1
+	switch {
+	case highCount == 3 && h.AttackVector == CVSSAttackVectorNetwork:
+		level = 3
+	case highCount > 0:
+		level = 2
+	}
+	if strings.EqualFold(h.LikelihoodOfExploit, "Low") {
+		level--
+	}
+	return levels[level]
+}
+
+// impactLevel 返回一组影响类型对应的CVSS影响级别
+// availability为true时"DoS:"类影响视为高影响
+func impactLevel(impacts []string, availability bool) string {
+	for _, impact := range impacts {
+		normalized := strings.ToLower(strings.TrimSpace(impact))
+		if highImpacts[normalized] || (availability && strings.HasPrefix(normalized, "dos:")) {
+			return CVSSImpactHigh
+		}
+	}
+	return CVSSImpactLow
+}
+
+// maxImpact 返回两个CVSS影响级别中较高的一个
+func maxImpact(a, b string) string {
+	if impactRanks[b] > impactRanks[a] {
+		return b
+	}
+	return a
+}
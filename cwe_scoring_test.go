@@ -0,0 +1,93 @@
+package cwe
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewScoringHint(t *testing.T) {
+	tests := []struct {
+		name         string
+		weakness     *CWEWeakness
+		wantVector   string
+		wantSeverity string
+	}{
+		{
+			name: "执行代码且可通过网络利用",
+			weakness: &CWEWeakness{
+				ID:                  "89",
+				LikelihoodOfExploit: "High",
+				ApplicablePlatforms: []CWEApplicablePlatform{{Type: "Language", Class: "Not Language-Specific"}, {Type: "Technology", Class: "Web Based"}},
+				CommonConsequences: []CWEConsequence{
+					{Scope: []string{"Confidentiality", "Integrity"}, Impact: []string{"Execute Unauthorized Code or Commands"}},
+					{Scope: []string{"Confidentiality"}, Impact: []string{"Read Application Data"}},
+				},
+			},
+			wantVector:   "CVSS:3.1/AV:N/C:H/I:H/A:H",
+			wantSeverity: "Critical",
+		},
+		{
+			name: "拒绝服务",
+			weakness: &CWEWeakness{
+				ID: "CWE-400",
+				CommonConsequences: []CWEConsequence{
+					{Scope: []string{"Availability"}, Impact: []string{"DoS: Resource Consumption (CPU)"}},
+					{Scope: []string{"Integrity"}, Impact: []string{"Unexpected State"}},
+				},
+			},
+			wantVector:   "CVSS:3.1/C:N/I:L/A:H",
+			wantSeverity: "High",
+		},
+		{
+			name: "低影响且利用可能性低",
+			weakness: &CWEWeakness{
+				ID:                  "CWE-1",
+				LikelihoodOfExploit: "Low",
+				CommonConsequences: []CWEConsequence{
+					{Scope: []string{"Non-Repudiation", "Confidentiality"}, Impact: []string{"Hide Activities"}},
+				},
+			},
+			wantVector:   "CVSS:3.1/C:L/I:N/A:N",
+			wantSeverity: "Low",
+		},
+		{
+			name: "没有常见影响",
+			weakness: &CWEWeakness{
+				ID:                  "CWE-2",
+				ApplicablePlatforms: []CWEApplicablePlatform{{Type: "Technology", Class: "web server"}},
+			},
+			wantVector:   "CVSS:3.1/AV:N",
+			wantSeverity: "",
+		},
+		{
+			name:         "没有任何信息",
+			weakness:     &CWEWeakness{ID: "CWE-3"},
+			wantVector:   "",
+			wantSeverity: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hint := NewScoringHint(tt.weakness)
+			if got := hint.Vector(); got != tt.wantVector {
+				t.Errorf("Vector() = %q, 期望 %q", got, tt.wantVector)
+			}
+			if hint.Severity != tt.wantSeverity {
+				t.Errorf("Severity = %q, 期望 %q", hint.Severity, tt.wantSeverity)
+			}
+		})
+	}
+
+	hint := NewScoringHint(tests[0].weakness)
+	if hint.CWEID != "CWE-89" || hint.LikelihoodOfExploit != "High" {
+		t.Errorf("评分参考字段不正确: %+v", hint)
+	}
+	if !reflect.DeepEqual(hint.Impacts, []string{"Execute Unauthorized Code or Commands", "Read Application Data"}) {
+		t.Errorf("Impacts = %v", hint.Impacts)
+	}
+
+	if zero := NewScoringHint(nil); !reflect.DeepEqual(zero, ScoringHint{}) {
+		t.Errorf("weakness为nil时应返回零值，实际为%+v", zero)
+	}
+}
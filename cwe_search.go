@@ -1,9 +1,5 @@
 package cwe
 
-import (
-	"strings"
-)
-
 // FindByID 在CWE树中查找特定ID的节点
 //
 // 方法功能:
@@ -36,7 +32,7 @@ import (
 // 边界情况:
 // - 如root为nil，返回nil
 // - 如树中不存在匹配ID的节点，返回nil
-// - 如树中存在循环引用，可能导致栈溢出
+// - 如树中存在循环引用，可能导致栈溢出，可先用DetectCycles检查
 //
 // 相关方法:
 // - FindByKeyword(): 根据关键词在CWE树中查找节点
@@ -58,10 +54,11 @@ func FindByID(root *CWE, id string) *CWE {
 	return nil
 }
 
-// FindByKeyword 在CWE树中查找名称或描述包含关键词的节点
+// FindByKeyword 在CWE树中查找名称、描述或替代术语包含关键词的节点
 //
 // 方法功能:
-// 在CWE树中搜索名称或描述中包含指定关键词的所有节点。
+// 在CWE树中搜索名称、描述或替代术语中包含指定关键词的所有节点。
+// 关键词是DefaultSynonyms中的术语(如"XSS"、"SQLi")时，同时查找包含其同义词的节点。
 // 搜索不区分大小写，且会检查每个节点及其所有子节点。
 // 该方法使用深度优先搜索算法，适用于查找与特定主题相关的所有CWE。
 //
@@ -91,7 +88,7 @@ func FindByID(root *CWE, id string) *CWE {
 // 边界情况:
 // - 如root为nil，返回空切片
 // - 如keyword为空字符串，可能会匹配大量节点
-// - 如树中存在循环引用，可能导致栈溢出
+// - 如树中存在循环引用，可能导致栈溢出，可先用DetectCycles检查
 //
 // 性能考虑:
 // - 对于大型CWE树，此方法可能需要遍历大量节点，性能可能较低
@@ -106,14 +103,13 @@ func FindByKeyword(root *CWE, keyword string) []*CWE {
 		return result
 	}
 
-	keyword = strings.ToLower(keyword)
+	terms := DefaultSynonyms.Expand(keyword)
 
 	// 递归搜索树
 	var search func(node *CWE)
 	search = func(node *CWE) {
 		// 检查当前节点
-		if strings.Contains(strings.ToLower(node.Name), keyword) ||
-			strings.Contains(strings.ToLower(node.Description), keyword) {
+		if matchesAnyTerm(node, terms, false) {
 			result = append(result, node)
 		}
 
@@ -0,0 +1,188 @@
+package cwe
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 分页搜索的默认值
+const (
+	// DefaultSearchLimit 未指定Limit时每页返回的结果数
+	DefaultSearchLimit = 20
+
+	// MaxSearchLimit 每页允许的最大结果数，超过时按此值截断
+	MaxSearchLimit = 1000
+)
+
+// cursorPrefix 游标编码前的前缀，用于识别无效游标
+const cursorPrefix = "after:"
+
+// SearchQuery 描述一次分页搜索
+//
+// Offset和Cursor二选一：Offset适合跳页，Cursor在两次请求之间注册表发生增删时
+// 也不会重复或遗漏结果，适合"加载更多"式的接口。
+type SearchQuery struct {
+	// Keyword 关键词，不区分大小写地匹配ID、名称、描述和替代术语；为空时匹配所有条目
+	// 关键词是同义词表中的术语时同时匹配其同义词，见Registry.SetSynonyms
+	Keyword string
+
+	// Limit 每页的结果数，<=0时使用DefaultSearchLimit，超过MaxSearchLimit时截断
+	Limit int
+
+	// Offset 跳过的结果数
+	Offset int
+
+	// Cursor 上一页返回的NextCursor，从该位置之后继续
+	Cursor string
+}
+
+// SearchPage 是一页搜索结果
+type SearchPage struct {
+	// Results 本页结果，按CWE编号升序排列
+	Results []*CWE
+
+	// Total 匹配的结果总数
+	Total int
+
+	// NextCursor 获取下一页的游标，没有更多结果时为空
+	NextCursor string
+}
+
+// Search 在注册表中分页搜索CWE
+//
+// 方法功能:
+// 匹配名称、描述或ID包含关键词的条目，按CWE编号升序(CWE-20排在CWE-100之前)稳定排序后分页返回。
+// 排序只依赖ID，因此相同的查询在注册表不变时总是返回相同的分页结果。
+//
+// 参数:
+// - query: SearchQuery - 搜索条件和分页参数
+//
+// 返回值:
+// - *SearchPage: 本页结果
+// - error: 同时指定Offset和Cursor、Offset为负或游标无效时返回错误
+//
+// 使用示例:
+// ```go
+// page, err := registry.Search(cwe.SearchQuery{Keyword: "injection", Limit: 10})
+//
+//	for page != nil && err == nil {
+//	    for _, entry := range page.Results {
+//	        fmt.Println(entry.ID, entry.Name)
+//	    }
+//	    if page.NextCursor == "" {
+//	        break
+//	    }
+//	    page, err = registry.Search(cwe.SearchQuery{Keyword: "injection", Limit: 10, Cursor: page.NextCursor})
+//	}
+//
+// ```
+func (r *Registry) Search(query SearchQuery) (*SearchPage, error) {
+	if query.Offset < 0 {
+		return nil, errors.New("offset不能为负数")
+	}
+	if query.Offset > 0 && query.Cursor != "" {
+		return nil, errors.New("不能同时指定offset和cursor")
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit > MaxSearchLimit {
+		limit = MaxSearchLimit
+	}
+
+	matches := r.matchKeyword(query.Keyword)
+	sortByCWEID(matches)
+
+	start := query.Offset
+	if query.Cursor != "" {
+		after, err := decodeSearchCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		start = sort.Search(len(matches), func(i int) bool {
+			return compareCWEIDs(matches[i].ID, after) > 0
+		})
+	}
+	if start > len(matches) {
+		start = len(matches)
+	}
+
+	end := start + limit
+	if end > len(matches) {
+		end = len(matches)
+	}
+
+	page := &SearchPage{
+		Results: matches[start:end],
+		Total:   len(matches),
+	}
+	if end < len(matches) {
+		page.NextCursor = encodeSearchCursor(matches[end-1].ID)
+	}
+	return page, nil
+}
+
+// matchKeyword 返回ID、名称、描述或替代术语包含关键词或其同义词的所有条目
+func (r *Registry) matchKeyword(keyword string) []*CWE {
+	terms := r.getSynonyms().Expand(keyword)
+
+	matches := make([]*CWE, 0)
+	for _, entry := range r.Entries {
+		if terms[0] == "" || matchesAnyTerm(entry, terms, true) {
+			matches = append(matches, entry)
+		}
+	}
+	return matches
+}
+
+// sortByCWEID 按CWE编号升序排序
+func sortByCWEID(entries []*CWE) {
+	sort.Slice(entries, func(i, j int) bool {
+		return compareCWEIDs(entries[i].ID, entries[j].ID) < 0
+	})
+}
+
+// compareCWEIDs 按编号比较两个CWE ID
+// 无法解析编号的ID排在可解析的ID之后，并按字符串比较
+func compareCWEIDs(a, b string) int {
+	na, errA := cweIDNumber(a)
+	nb, errB := cweIDNumber(b)
+
+	switch {
+	case errA == nil && errB == nil && na != nb:
+		if na < nb {
+			return -1
+		}
+		return 1
+	case errA == nil && errB != nil:
+		return -1
+	case errA != nil && errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// cweIDNumber 返回"CWE-数字"格式ID中的编号
+func cweIDNumber(id string) (int, error) {
+	return strconv.Atoi(strings.TrimPrefix(id, "CWE-"))
+}
+
+// encodeSearchCursor 将最后一个结果的ID编码为不透明的游标
+func encodeSearchCursor(lastID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + lastID))
+}
+
+// decodeSearchCursor 从游标中解码最后一个结果的ID
+func decodeSearchCursor(cursor string) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(data), cursorPrefix) {
+		return "", fmt.Errorf("无效的游标: %q", cursor)
+	}
+	return strings.TrimPrefix(string(data), cursorPrefix), nil
+}
@@ -0,0 +1,110 @@
+package cwe
+
+import (
+	"fmt"
+	"testing"
+)
+
+func newSearchPageTestRegistry() *Registry {
+	registry := NewRegistry()
+	for _, n := range []int{100, 20, 79, 89, 1000, 2, 352} {
+		entry := NewCWE(fmt.Sprintf("CWE-%d", n), fmt.Sprintf("Weakness %d", n))
+		if n == 79 || n == 89 {
+			entry.Description = "Injection flaw"
+		}
+		registry.Register(entry)
+	}
+	return registry
+}
+
+func pageIDs(page *SearchPage) []string {
+	ids := make([]string, 0, len(page.Results))
+	for _, entry := range page.Results {
+		ids = append(ids, entry.ID)
+	}
+	return ids
+}
+
+func TestSearchOffsetPagination(t *testing.T) {
+	registry := newSearchPageTestRegistry()
+
+	page, err := registry.Search(SearchQuery{Limit: 3})
+	if err != nil {
+		t.Fatalf("Search失败: %v", err)
+	}
+	if ids := fmt.Sprint(pageIDs(page)); ids != "[CWE-2 CWE-20 CWE-79]" {
+		t.Errorf("第一页应按编号排序，但得到 %s", ids)
+	}
+	if page.Total != 7 || page.NextCursor == "" {
+		t.Errorf("Total=%d NextCursor=%q", page.Total, page.NextCursor)
+	}
+
+	page, _ = registry.Search(SearchQuery{Limit: 3, Offset: 6})
+	if ids := fmt.Sprint(pageIDs(page)); ids != "[CWE-1000]" || page.NextCursor != "" {
+		t.Errorf("最后一页不正确: %s, cursor=%q", ids, page.NextCursor)
+	}
+
+	page, _ = registry.Search(SearchQuery{Offset: 100})
+	if len(page.Results) != 0 {
+		t.Errorf("超出范围的offset应返回空页")
+	}
+}
+
+func TestSearchCursorPagination(t *testing.T) {
+	registry := newSearchPageTestRegistry()
+
+	var all []string
+	query := SearchQuery{Limit: 2}
+	for i := 0; i < 10; i++ {
+		page, err := registry.Search(query)
+		if err != nil {
+			t.Fatalf("Search失败: %v", err)
+		}
+		all = append(all, pageIDs(page)...)
+
+		// 翻页期间新增的靠前条目不应导致重复
+		if i == 0 {
+			registry.Register(NewCWE("CWE-1", "New"))
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+
+	if fmt.Sprint(all) != "[CWE-2 CWE-20 CWE-79 CWE-89 CWE-100 CWE-352 CWE-1000]" {
+		t.Errorf("游标翻页结果不正确: %v", all)
+	}
+}
+
+func TestSearchKeywordAndErrors(t *testing.T) {
+	registry := newSearchPageTestRegistry()
+
+	page, err := registry.Search(SearchQuery{Keyword: "INJECTION"})
+	if err != nil || fmt.Sprint(pageIDs(page)) != "[CWE-79 CWE-89]" || page.Total != 2 {
+		t.Errorf("关键词搜索不正确: %v, %v", pageIDs(page), err)
+	}
+
+	if _, err := registry.Search(SearchQuery{Offset: 1, Cursor: encodeSearchCursor("CWE-2")}); err == nil {
+		t.Error("同时指定offset和cursor应返回错误")
+	}
+	if _, err := registry.Search(SearchQuery{Offset: -1}); err == nil {
+		t.Error("负数offset应返回错误")
+	}
+	if _, err := registry.Search(SearchQuery{Cursor: "not-a-cursor"}); err == nil {
+		t.Error("无效游标应返回错误")
+	}
+}
+
+func TestCompareCWEIDs(t *testing.T) {
+	if compareCWEIDs("CWE-20", "CWE-100") >= 0 {
+		t.Error("CWE-20应排在CWE-100之前")
+	}
+	if compareCWEIDs("CWE-100", "custom") >= 0 {
+		t.Error("无法解析的ID应排在后面")
+	}
+	if compareCWEIDs("CWE-79", "CWE-79") != 0 {
+		t.Error("相同ID应相等")
+	}
+}
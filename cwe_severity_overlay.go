@@ -0,0 +1,288 @@
+package cwe
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SeverityOverlay 保存组织自定义的严重性级别
+//
+// 覆盖层独立于注册表中的条目存在，不会修改上游的Severity字段。
+// 通过Registry.SetSeverityOverlay挂载到注册表后，Registry.EffectiveSeverity会优先使用覆盖值。
+//
+// SeverityOverlay是并发安全的，可以在多个goroutine中使用
+type SeverityOverlay struct {
+	mutex sync.RWMutex
+
+	// overrides CWE ID到自定义严重性的映射，ID已规范化
+	overrides map[string]string
+}
+
+// SeverityMode 控制导出时包含哪些严重性值
+type SeverityMode int
+
+const (
+	// SeverityUpstream 只导出上游的严重性
+	SeverityUpstream SeverityMode = iota
+
+	// SeverityEffective 用生效的严重性(覆盖值优先)替换Severity字段
+	SeverityEffective
+
+	// SeverityBoth Severity字段保留上游值，覆盖值写入EffectiveSeverity字段
+	SeverityBoth
+)
+
+// NewSeverityOverlay 创建一个空的严重性覆盖层
+func NewSeverityOverlay() *SeverityOverlay {
+	return &SeverityOverlay{
+		overrides: make(map[string]string),
+	}
+}
+
+// Set 为指定CWE设置自定义严重性
+//
+// 参数:
+// - id: string - CWE ID，支持ParseCWEID接受的所有格式
+// - severity: string - 自定义严重性，如"Critical"、"High"，不能为空
+//
+// 返回值:
+// - error: ID无法解析或严重性为空时返回错误
+func (o *SeverityOverlay) Set(id, severity string) error {
+	normalized, err := ParseCWEID(id)
+	if err != nil {
+		return err
+	}
+
+	severity = strings.TrimSpace(severity)
+	if severity == "" {
+		return fmt.Errorf("%s的严重性不能为空", normalized)
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.overrides[normalized] = severity
+	return nil
+}
+
+// Remove 删除指定CWE的自定义严重性
+func (o *SeverityOverlay) Remove(id string) {
+	normalized, err := ParseCWEID(id)
+	if err != nil {
+		return
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	delete(o.overrides, normalized)
+}
+
+// Get 获取指定CWE的自定义严重性
+// 第二个返回值表示是否存在覆盖值
+func (o *SeverityOverlay) Get(id string) (string, bool) {
+	normalized, err := ParseCWEID(id)
+	if err != nil {
+		return "", false
+	}
+
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	severity, exists := o.overrides[normalized]
+	return severity, exists
+}
+
+// IDs 返回所有设置了覆盖值的CWE ID，已排序
+func (o *SeverityOverlay) IDs() []string {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	ids := make([]string, 0, len(o.overrides))
+	for id := range o.overrides {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// LoadJSON 从JSON数据加载覆盖值
+//
+// 方法功能:
+// 解析形如{"CWE-79": "Critical"}的JSON数据，并将其中的覆盖值合并到当前覆盖层。
+//
+// 参数:
+// - data: []byte - JSON数据
+//
+// 返回值:
+// - error: 解析失败或任一覆盖值无效时返回错误
+func (o *SeverityOverlay) LoadJSON(data []byte) error {
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	return o.setAll(raw)
+}
+
+// LoadYAML 从YAML数据加载覆盖值
+//
+// 方法功能:
+// 解析每行一个"CWE ID: 严重性"的扁平YAML映射，并将其中的覆盖值合并到当前覆盖层。
+// 仅支持这种单层映射：允许空行、#开头的注释和用引号包裹的值，不支持嵌套结构。
+//
+// 参数:
+// - data: []byte - YAML数据
+//
+// 返回值:
+// - error: 某行格式无效或覆盖值无效时返回错误，错误中包含行号
+//
+// 数据样例:
+// ```yaml
+// # 组织内部的严重性定义
+// CWE-79: Critical
+// CWE-89: "Critical"
+// ```
+func (o *SeverityOverlay) LoadYAML(data []byte) error {
+	raw := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || line == "---" {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			return fmt.Errorf("第%d行格式无效: %q", lineNo, line)
+		}
+
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = value[:i]
+		}
+		key = unquoteYAMLScalar(key)
+		value = unquoteYAMLScalar(value)
+		if key == "" || value == "" {
+			return fmt.Errorf("第%d行格式无效: %q", lineNo, line)
+		}
+
+		raw[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return o.setAll(raw)
+}
+
+// setAll 按ID顺序批量设置覆盖值，使错误信息稳定
+func (o *SeverityOverlay) setAll(raw map[string]string) error {
+	ids := make([]string, 0, len(raw))
+	for id := range raw {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if err := o.Set(id, raw[id]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unquoteYAMLScalar 去除YAML标量两端的空白和引号
+func unquoteYAMLScalar(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		s = s[1 : len(s)-1]
+	}
+	return strings.TrimSpace(s)
+}
+
+// SetSeverityOverlay 为注册表挂载严重性覆盖层
+// 传入nil表示移除覆盖层
+func (r *Registry) SetSeverityOverlay(overlay *SeverityOverlay) {
+	r.severityOverlay = overlay
+}
+
+// GetSeverityOverlay 获取注册表挂载的严重性覆盖层，未挂载时返回nil
+func (r *Registry) GetSeverityOverlay() *SeverityOverlay {
+	return r.severityOverlay
+}
+
+// EffectiveSeverity 获取CWE生效的严重性
+//
+// 方法功能:
+// 优先返回覆盖层中的自定义严重性；没有覆盖值时返回条目上游的Severity字段。
+// 注册表中的条目本身不会被修改。
+//
+// 参数:
+// - id: string - CWE ID
+//
+// 返回值:
+// - string: 生效的严重性，可能为空字符串(上游没有严重性且没有覆盖值)
+// - error: 注册表中不存在该条目时返回错误
+//
+// 使用示例:
+// ```go
+// overlay := cwe.NewSeverityOverlay()
+// overlay.LoadJSON([]byte(`{"CWE-79": "Critical"}`))
+// registry.SetSeverityOverlay(overlay)
+//
+// severity, _ := registry.EffectiveSeverity("CWE-79") // "Critical"
+// ```
+func (r *Registry) EffectiveSeverity(id string) (string, error) {
+	entry, err := r.GetByID(id)
+	if err != nil {
+		return "", err
+	}
+
+	if r.severityOverlay != nil {
+		if severity, exists := r.severityOverlay.Get(entry.ID); exists {
+			return severity, nil
+		}
+	}
+
+	return entry.Severity, nil
+}
+
+// SnapshotWithSeverity 按指定的严重性模式创建注册表快照
+//
+// 方法功能:
+// 与NewRegistrySnapshot相同，但会根据mode决定快照中包含的严重性值:
+// - SeverityUpstream: 只包含上游严重性
+// - SeverityEffective: Severity字段为生效的严重性
+// - SeverityBoth: Severity字段为上游严重性，覆盖值写入EffectiveSeverity字段
+//
+// 使用SeverityBoth导出的快照在ToRegistry时会重建覆盖层。
+func (r *Registry) SnapshotWithSeverity(mode SeverityMode) *RegistrySnapshot {
+	snapshot := NewRegistrySnapshot(r)
+	if mode == SeverityUpstream || r.severityOverlay == nil {
+		return snapshot
+	}
+
+	for i := range snapshot.Entries {
+		entry := &snapshot.Entries[i]
+		severity, exists := r.severityOverlay.Get(entry.ID)
+		if !exists {
+			continue
+		}
+
+		if mode == SeverityEffective {
+			entry.Severity = severity
+		} else {
+			entry.EffectiveSeverity = severity
+		}
+	}
+
+	return snapshot
+}
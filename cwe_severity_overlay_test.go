@@ -0,0 +1,129 @@
+package cwe
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newSeverityTestRegistry() *Registry {
+	registry := NewRegistry()
+	xss := NewCWE("CWE-79", "Cross-site Scripting")
+	xss.Severity = "Medium"
+	sqli := NewCWE("CWE-89", "SQL Injection")
+	sqli.Severity = "High"
+	registry.Register(xss)
+	registry.Register(sqli)
+	return registry
+}
+
+func TestSeverityOverlaySetGet(t *testing.T) {
+	overlay := NewSeverityOverlay()
+
+	if err := overlay.Set("79", "Critical"); err != nil {
+		t.Fatalf("Set失败: %v", err)
+	}
+	if severity, ok := overlay.Get("CWE-79"); !ok || severity != "Critical" {
+		t.Errorf("期望Critical，但得到 %q (%v)", severity, ok)
+	}
+
+	if err := overlay.Set("CWE-89", " "); err == nil {
+		t.Error("空严重性应返回错误")
+	}
+	if err := overlay.Set("invalid", "High"); err == nil {
+		t.Error("无效ID应返回错误")
+	}
+
+	overlay.Remove("cwe-79")
+	if _, ok := overlay.Get("CWE-79"); ok {
+		t.Error("Remove后不应再有覆盖值")
+	}
+}
+
+func TestSeverityOverlayLoad(t *testing.T) {
+	overlay := NewSeverityOverlay()
+	if err := overlay.LoadJSON([]byte(`{"CWE-79": "Critical", "89": "Low"}`)); err != nil {
+		t.Fatalf("LoadJSON失败: %v", err)
+	}
+	if !reflect.DeepEqual(overlay.IDs(), []string{"CWE-79", "CWE-89"}) {
+		t.Errorf("IDs不符合预期: %v", overlay.IDs())
+	}
+
+	yaml := []byte(`# 组织内部的严重性定义
+---
+CWE-20: "High"   # 输入验证
+cwe-787: 'Critical'
+
+`)
+	if err := overlay.LoadYAML(yaml); err != nil {
+		t.Fatalf("LoadYAML失败: %v", err)
+	}
+	if severity, _ := overlay.Get("CWE-20"); severity != "High" {
+		t.Errorf("期望High，但得到 %q", severity)
+	}
+	if severity, _ := overlay.Get("CWE-787"); severity != "Critical" {
+		t.Errorf("期望Critical，但得到 %q", severity)
+	}
+
+	if err := overlay.LoadYAML([]byte("CWE-79 Critical")); err == nil {
+		t.Error("缺少冒号的行应返回错误")
+	}
+	if err := overlay.LoadJSON([]byte(`[]`)); err == nil {
+		t.Error("非映射JSON应返回错误")
+	}
+}
+
+func TestRegistryEffectiveSeverity(t *testing.T) {
+	registry := newSeverityTestRegistry()
+
+	severity, err := registry.EffectiveSeverity("CWE-79")
+	if err != nil || severity != "Medium" {
+		t.Errorf("未挂载覆盖层时应返回上游值Medium，但得到 %q, %v", severity, err)
+	}
+
+	overlay := NewSeverityOverlay()
+	overlay.Set("CWE-79", "Critical")
+	registry.SetSeverityOverlay(overlay)
+
+	if severity, _ := registry.EffectiveSeverity("CWE-79"); severity != "Critical" {
+		t.Errorf("期望覆盖值Critical，但得到 %q", severity)
+	}
+	if severity, _ := registry.EffectiveSeverity("CWE-89"); severity != "High" {
+		t.Errorf("没有覆盖值时应返回上游值High，但得到 %q", severity)
+	}
+	if registry.Entries["CWE-79"].Severity != "Medium" {
+		t.Error("覆盖层不应修改上游条目")
+	}
+	if _, err := registry.EffectiveSeverity("CWE-1"); err == nil {
+		t.Error("不存在的条目应返回错误")
+	}
+}
+
+func TestRegistrySnapshotWithSeverity(t *testing.T) {
+	registry := newSeverityTestRegistry()
+	overlay := NewSeverityOverlay()
+	overlay.Set("CWE-79", "Critical")
+	registry.SetSeverityOverlay(overlay)
+
+	upstream := registry.SnapshotWithSeverity(SeverityUpstream)
+	if upstream.Entries[0].Severity != "Medium" || upstream.Entries[0].EffectiveSeverity != "" {
+		t.Errorf("SeverityUpstream结果不符合预期: %+v", upstream.Entries[0])
+	}
+
+	effective := registry.SnapshotWithSeverity(SeverityEffective)
+	if effective.Entries[0].Severity != "Critical" {
+		t.Errorf("SeverityEffective结果不符合预期: %+v", effective.Entries[0])
+	}
+
+	both := registry.SnapshotWithSeverity(SeverityBoth)
+	if both.Entries[0].Severity != "Medium" || both.Entries[0].EffectiveSeverity != "Critical" {
+		t.Errorf("SeverityBoth结果不符合预期: %+v", both.Entries[0])
+	}
+
+	restored, err := both.ToRegistry()
+	if err != nil {
+		t.Fatalf("ToRegistry失败: %v", err)
+	}
+	if severity, _ := restored.EffectiveSeverity("CWE-79"); severity != "Critical" {
+		t.Errorf("恢复后的注册表应重建覆盖层，但得到 %q", severity)
+	}
+}
@@ -0,0 +1,210 @@
+package cwe
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SeverityRule 根据条目计算新的严重性
+// 返回新值和true表示需要修改；返回false表示该规则不处理此条目
+// 规则看到的entry.Severity是前面的规则处理后的值，因此规则可以串联使用
+type SeverityRule func(entry *CWE) (string, bool)
+
+// severityAliases NormalizeSeverityRule识别的写法，键为小写
+var severityAliases = map[string]string{
+	"critical":      "Critical",
+	"严重":            "Critical",
+	"致命":            "Critical",
+	"high":          "High",
+	"高":             "High",
+	"高危":            "High",
+	"medium":        "Medium",
+	"moderate":      "Medium",
+	"中":             "Medium",
+	"中危":            "Medium",
+	"low":           "Low",
+	"低":             "Low",
+	"低危":            "Low",
+	"info":          "Info",
+	"informational": "Info",
+	"信息":            "Info",
+}
+
+// NormalizeSeverityRule 返回将常见的严重性写法统一为英文首字母大写形式的规则
+// 例如"高"、"HIGH"、"high"都会被改为"High"；不认识的写法和空值保持不变
+func NormalizeSeverityRule() SeverityRule {
+	return SeverityMappingRule(severityAliases)
+}
+
+// SeverityMappingRule 返回按映射表替换严重性的规则
+// 映射表的键不区分大小写、忽略首尾空白，值为替换后的严重性
+//
+// 使用示例:
+// ```go
+// // 组织策略: 不使用Medium，统一升级为High
+// rule := cwe.SeverityMappingRule(map[string]string{"Medium": "High"})
+// ```
+func SeverityMappingRule(mapping map[string]string) SeverityRule {
+	normalized := make(map[string]string, len(mapping))
+	for from, to := range mapping {
+		normalized[strings.ToLower(strings.TrimSpace(from))] = to
+	}
+
+	return func(entry *CWE) (string, bool) {
+		severity, exists := normalized[strings.ToLower(strings.TrimSpace(entry.Severity))]
+		return severity, exists
+	}
+}
+
+// SeverityByIDRule 返回为指定CWE设置固定严重性的规则，ID支持ParseCWEID接受的所有格式
+// 无法解析的ID会被忽略
+func SeverityByIDRule(severities map[string]string) SeverityRule {
+	normalized := make(map[string]string, len(severities))
+	for id, severity := range severities {
+		if n, err := ParseCWEID(id); err == nil {
+			normalized[n] = severity
+		}
+	}
+
+	return func(entry *CWE) (string, bool) {
+		severity, exists := normalized[entry.ID]
+		return severity, exists
+	}
+}
+
+// SeverityChange 记录一个条目严重性的变化
+type SeverityChange struct {
+	// ID 条目ID
+	ID string `json:"id"`
+
+	// Original 修改前的严重性
+	Original string `json:"original"`
+
+	// Severity 修改后的严重性
+	Severity string `json:"severity"`
+}
+
+// SeverityReclassifyReport 是一次批量重新分级的结果
+type SeverityReclassifyReport struct {
+	// Changes 发生变化的条目，按CWE编号排序
+	Changes []SeverityChange `json:"changes"`
+
+	// Unchanged 严重性没有变化的条目数量
+	Unchanged int `json:"unchanged"`
+
+	// DryRun 为true时注册表未被修改
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ReclassifySeverity 按规则批量重新计算注册表中所有条目的严重性
+//
+// 方法功能:
+// 替代用户自己编写的遍历循环，一次性对所有条目依次应用规则，记录每个变化的原始值，
+// 并返回变更报告，便于审计和回滚。条目按CWE编号顺序处理，报告的顺序是确定的。
+// 规则只修改条目的Severity字段，不影响通过SetSeverityOverlay挂载的覆盖层。
+//
+// 参数:
+// - dryRun: bool - 为true时只计算变更报告，不修改注册表
+// - rules: ...SeverityRule - 按顺序应用的规则
+//
+// 返回值:
+// - *SeverityReclassifyReport: 变更报告
+//
+// 使用示例:
+// ```go
+// report := registry.ReclassifySeverity(false,
+//
+//	cwe.NormalizeSeverityRule(), // "高"/"HIGH" -> "High"
+//	cwe.SeverityByIDRule(map[string]string{"CWE-79": "Critical"}), // 组织策略
+//
+// )
+// report.WriteCSV(os.Stdout)
+//
+// // 需要时可以撤销
+// report.Revert(registry)
+// ```
+func (r *Registry) ReclassifySeverity(dryRun bool, rules ...SeverityRule) *SeverityReclassifyReport {
+	entries := make([]*CWE, 0, len(r.Entries))
+	for _, entry := range r.Entries {
+		entries = append(entries, entry)
+	}
+	sortByCWEID(entries)
+
+	report := &SeverityReclassifyReport{
+		Changes: make([]SeverityChange, 0),
+		DryRun:  dryRun,
+	}
+
+	for _, entry := range entries {
+		original := entry.Severity
+
+		// 在副本上应用规则，使每条规则看到前面规则的结果
+		working := *entry
+		for _, rule := range rules {
+			if severity, ok := rule(&working); ok {
+				working.Severity = severity
+			}
+		}
+
+		if working.Severity == original {
+			report.Unchanged++
+			continue
+		}
+
+		report.Changes = append(report.Changes, SeverityChange{
+			ID:       entry.ID,
+			Original: original,
+			Severity: working.Severity,
+		})
+		if !dryRun {
+			entry.Severity = working.Severity
+		}
+	}
+
+	return report
+}
+
+// Revert 将报告中的条目恢复为原始严重性
+// 只恢复当前严重性仍等于报告中新值的条目，返回实际恢复的数量；DryRun报告不做任何修改
+func (rep *SeverityReclassifyReport) Revert(registry *Registry) int {
+	if rep.DryRun {
+		return 0
+	}
+
+	reverted := 0
+	for _, change := range rep.Changes {
+		entry, exists := registry.Entries[change.ID]
+		if !exists || entry.Severity != change.Severity {
+			continue
+		}
+		entry.Severity = change.Original
+		reverted++
+	}
+	return reverted
+}
+
+// WriteCSV 以CSV格式写出变更报告，列为id、original、severity
+func (rep *SeverityReclassifyReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"id", "original", "severity"}); err != nil {
+		return err
+	}
+	for _, change := range rep.Changes {
+		if err := writer.Write([]string{change.ID, change.Original, change.Severity}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// String 返回报告摘要
+func (rep *SeverityReclassifyReport) String() string {
+	summary := fmt.Sprintf("%d个条目的严重性发生变化，%d个条目未变化", len(rep.Changes), rep.Unchanged)
+	if rep.DryRun {
+		summary += "(试运行，未修改注册表)"
+	}
+	return summary
+}
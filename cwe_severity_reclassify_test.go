@@ -0,0 +1,86 @@
+package cwe
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// newReclassifyTestRegistry 创建严重性写法不统一的测试注册表
+func newReclassifyTestRegistry() *Registry {
+	registry := NewRegistry()
+	severities := map[string]string{
+		"CWE-20":  "高",
+		"CWE-79":  "HIGH",
+		"CWE-89":  "High",
+		"CWE-100": " medium ",
+		"CWE-200": "",
+		"CWE-300": "unknown",
+	}
+	for id, severity := range severities {
+		entry := NewCWE(id, id)
+		entry.Severity = severity
+		registry.Register(entry)
+	}
+	return registry
+}
+
+func TestReclassifySeverity(t *testing.T) {
+	registry := newReclassifyTestRegistry()
+
+	report := registry.ReclassifySeverity(false,
+		NormalizeSeverityRule(),
+		SeverityByIDRule(map[string]string{"79": "Critical", "bad": "Low"}),
+	)
+
+	expected := []SeverityChange{
+		{ID: "CWE-20", Original: "高", Severity: "High"},
+		{ID: "CWE-79", Original: "HIGH", Severity: "Critical"},
+		{ID: "CWE-100", Original: " medium ", Severity: "Medium"},
+	}
+	if !reflect.DeepEqual(report.Changes, expected) {
+		t.Errorf("变更不符合预期:\n got: %+v\nwant: %+v", report.Changes, expected)
+	}
+	if report.Unchanged != 3 {
+		t.Errorf("Unchanged应为3，实际为%d", report.Unchanged)
+	}
+	if registry.Entries["CWE-79"].Severity != "Critical" || registry.Entries["CWE-300"].Severity != "unknown" {
+		t.Error("注册表未按规则修改")
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV失败: %v", err)
+	}
+	wantCSV := "id,original,severity\nCWE-20,高,High\nCWE-79,HIGH,Critical\nCWE-100,\" medium \",Medium\n"
+	if buf.String() != wantCSV {
+		t.Errorf("CSV不符合预期:\n%s", buf.String())
+	}
+
+	// 回滚时跳过之后又被修改过的条目
+	registry.Entries["CWE-20"].Severity = "Low"
+	if reverted := report.Revert(registry); reverted != 2 {
+		t.Errorf("应恢复2个条目，实际为%d", reverted)
+	}
+	if registry.Entries["CWE-79"].Severity != "HIGH" || registry.Entries["CWE-20"].Severity != "Low" {
+		t.Error("回滚结果不正确")
+	}
+}
+
+func TestReclassifySeverityDryRun(t *testing.T) {
+	registry := newReclassifyTestRegistry()
+
+	report := registry.ReclassifySeverity(true, SeverityMappingRule(map[string]string{"HIGH": "Critical"}))
+	if len(report.Changes) != 2 || !report.DryRun {
+		t.Errorf("试运行应报告2个变更，实际为%+v", report)
+	}
+	if registry.Entries["CWE-79"].Severity != "HIGH" {
+		t.Error("试运行不应修改注册表")
+	}
+	if report.Revert(registry) != 0 {
+		t.Error("试运行报告的Revert不应修改任何条目")
+	}
+	if report.String() != "2个条目的严重性发生变化，4个条目未变化(试运行，未修改注册表)" {
+		t.Errorf("String() = %q", report.String())
+	}
+}
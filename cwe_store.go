@@ -0,0 +1,267 @@
+package cwe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StoreMetadata 是持久化存储的元数据
+type StoreMetadata struct {
+	// Version 存储中条目对应的CWE版本，如"4.14"
+	Version string `json:"version,omitempty"`
+
+	// UpdatedAt 元数据最后一次更新的时间
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// Store 是按ID存取CWE条目的持久化存储
+//
+// Store只保存条目本身的内容(名称、描述、缓解措施、关系等)，不保存父子结构；
+// 需要完整保存树时使用RegistrySnapshot。条目以ID为键，重复Put同一ID会覆盖原有条目。
+// 实现必须可以被多个goroutine并发使用。
+//
+// 内置实现有NewMemoryStore和NewFileStore，其他持久化技术(数据库、对象存储等)实现此接口后
+// 即可通过DataFetcher.SetStore使用。
+type Store interface {
+	// Put 保存条目，条目ID不能为空
+	Put(entry *CWE) error
+
+	// Get 返回ID对应条目的副本，条目不存在时返回包装了ErrNotFound的错误
+	Get(id string) (*CWE, error)
+
+	// List 返回所有条目的ID，按CWE编号排序
+	List() ([]string, error)
+
+	// Metadata 返回存储的元数据，从未设置过时返回零值
+	Metadata() (StoreMetadata, error)
+
+	// SetMetadata 设置存储的元数据
+	SetMetadata(metadata StoreMetadata) error
+}
+
+// MemoryStore 是基于内存的Store实现，进程退出后数据丢失
+type MemoryStore struct {
+	mutex    sync.RWMutex
+	entries  map[string]SnapshotEntry
+	metadata StoreMetadata
+}
+
+// NewMemoryStore 创建空的内存存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]SnapshotEntry)}
+}
+
+// Put 保存条目的副本，之后修改entry不会影响存储中的数据
+func (s *MemoryStore) Put(entry *CWE) error {
+	stored, err := newStoreEntry(entry)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.entries[stored.ID] = stored
+	return nil
+}
+
+// Get 返回条目的副本
+func (s *MemoryStore) Get(id string) (*CWE, error) {
+	key := normalizeEntryID(id)
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	stored, exists := s.entries[key]
+	if !exists {
+		return nil, &notFoundError{id: key}
+	}
+	return stored.toCWE(), nil
+}
+
+// List 返回所有条目的ID，按CWE编号排序
+func (s *MemoryStore) List() ([]string, error) {
+	s.mutex.RLock()
+	ids := make([]string, 0, len(s.entries))
+	for id := range s.entries {
+		ids = append(ids, id)
+	}
+	s.mutex.RUnlock()
+
+	sortStoreIDs(ids)
+	return ids, nil
+}
+
+// Metadata 返回存储的元数据
+func (s *MemoryStore) Metadata() (StoreMetadata, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.metadata, nil
+}
+
+// SetMetadata 设置存储的元数据
+func (s *MemoryStore) SetMetadata(metadata StoreMetadata) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.metadata = metadata
+	return nil
+}
+
+// storeMetadataFile FileStore保存元数据的文件名
+const storeMetadataFile = "metadata.json"
+
+// storeEntriesDir FileStore保存条目的子目录名
+const storeEntriesDir = "entries"
+
+// FileStore 是基于文件系统的Store实现
+//
+// 每个条目保存为entries目录下的一个JSON文件(如entries/CWE-79.json)，元数据保存在metadata.json中。
+// 文件先写入临时文件再重命名，多个进程共享同一目录时不会读到写了一半的文件。
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore 创建使用dir目录的文件存储，目录不存在时自动创建
+//
+// 方法功能:
+// 已有的目录中保存的条目和元数据会被保留，可以在下次运行时继续使用。
+//
+// 参数:
+// - dir: string - 存储目录
+//
+// 返回值:
+// - *FileStore: 文件存储
+// - error: 创建目录失败时返回错误
+//
+// 使用示例:
+// ```go
+// store, err := cwe.NewFileStore(filepath.Join(os.TempDir(), "cwe-store"))
+//
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// store.Put(entry)
+// cached, err := store.Get("CWE-79")
+// ```
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Join(dir, storeEntriesDir), 0755); err != nil {
+		return nil, fmt.Errorf("创建存储目录失败: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Put 将条目写入对应的JSON文件
+func (s *FileStore) Put(entry *CWE) error {
+	stored, err := newStoreEntry(entry)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("序列化条目%s失败: %w", stored.ID, err)
+	}
+	return writeFileAtomic(s.entryPath(stored.ID), data)
+}
+
+// Get 从对应的JSON文件读取条目
+func (s *FileStore) Get(id string) (*CWE, error) {
+	key := normalizeEntryID(id)
+
+	data, err := os.ReadFile(s.entryPath(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, &notFoundError{id: key}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var stored SnapshotEntry
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("解析条目%s失败: %w", key, err)
+	}
+	return stored.toCWE(), nil
+}
+
+// List 返回entries目录下所有条目的ID，按CWE编号排序
+func (s *FileStore) List() ([]string, error) {
+	files, err := os.ReadDir(filepath.Join(s.dir, storeEntriesDir))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(files))
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		ids = append(ids, strings.TrimSuffix(name, ".json"))
+	}
+
+	sortStoreIDs(ids)
+	return ids, nil
+}
+
+// Metadata 读取metadata.json，文件不存在时返回零值
+func (s *FileStore) Metadata() (StoreMetadata, error) {
+	var metadata StoreMetadata
+
+	data, err := os.ReadFile(filepath.Join(s.dir, storeMetadataFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return metadata, nil
+	}
+	if err != nil {
+		return metadata, err
+	}
+
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return metadata, fmt.Errorf("解析存储元数据失败: %w", err)
+	}
+	return metadata, nil
+}
+
+// SetMetadata 写入metadata.json
+func (s *FileStore) SetMetadata(metadata StoreMetadata) error {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(s.dir, storeMetadataFile), data)
+}
+
+// entryPath 返回条目对应的文件路径
+// ID中除字母、数字、'-'和'_'以外的字符替换为'_'，避免ID中的路径分隔符逃逸出存储目录
+func (s *FileStore) entryPath(id string) string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, id)
+	return filepath.Join(s.dir, storeEntriesDir, name+".json")
+}
+
+// newStoreEntry 将条目转换为存储使用的扁平表示，不保存子节点引用
+func newStoreEntry(entry *CWE) (SnapshotEntry, error) {
+	if entry == nil || entry.ID == "" {
+		return SnapshotEntry{}, errors.New("条目ID不能为空")
+	}
+	stored := newSnapshotEntry(entry)
+	stored.ID = normalizeEntryID(entry.ID)
+	stored.Children = nil
+	return stored, nil
+}
+
+// sortStoreIDs 按CWE编号排序ID
+func sortStoreIDs(ids []string) {
+	sort.Slice(ids, func(i, j int) bool {
+		return compareCWEIDs(ids[i], ids[j]) < 0
+	})
+}
@@ -0,0 +1,112 @@
+package cwe
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// testStores 返回所有内置的Store实现
+func testStores(t *testing.T) map[string]Store {
+	fileStore, err := NewFileStore(filepath.Join(t.TempDir(), "store"))
+	if err != nil {
+		t.Fatalf("NewFileStore失败: %v", err)
+	}
+	return map[string]Store{
+		"memory": NewMemoryStore(),
+		"file":   fileStore,
+	}
+}
+
+func TestStorePutGetList(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			entry := NewCWE("CWE-79", "Cross-site Scripting")
+			entry.Severity = "High"
+			entry.Mitigations = []string{"Encode output"}
+			entry.Relations = []CWERelation{{Nature: "ChildOf", CweID: "CWE-74", ViewID: "1000"}}
+			entry.AddChild(NewCWE("CWE-80", "Basic XSS"))
+
+			for _, e := range []*CWE{entry, NewCWE("CWE-100", "Deprecated"), NewCWE("CWE-20", "Input Validation")} {
+				if err := store.Put(e); err != nil {
+					t.Fatalf("Put失败: %v", err)
+				}
+			}
+
+			got, err := store.Get("79")
+			if err != nil {
+				t.Fatalf("Get失败: %v", err)
+			}
+			if got.ID != "CWE-79" || got.Severity != "High" || !reflect.DeepEqual(got.Mitigations, entry.Mitigations) || !reflect.DeepEqual(got.Relations, entry.Relations) {
+				t.Errorf("读取的条目不正确: %+v", got)
+			}
+			if len(got.Children) != 0 {
+				t.Error("存储不应保存子节点")
+			}
+
+			// 修改原条目不影响存储
+			entry.Name = "changed"
+			if got, _ := store.Get("CWE-79"); got.Name != "Cross-site Scripting" {
+				t.Errorf("存储中的条目不应随原条目修改，实际为%s", got.Name)
+			}
+
+			ids, err := store.List()
+			if err != nil {
+				t.Fatalf("List失败: %v", err)
+			}
+			if !reflect.DeepEqual(ids, []string{"CWE-20", "CWE-79", "CWE-100"}) {
+				t.Errorf("List应按CWE编号排序，实际为%v", ids)
+			}
+
+			if _, err := store.Get("CWE-404"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("条目不存在时应返回ErrNotFound，实际为%v", err)
+			}
+			if err := store.Put(&CWE{}); err == nil {
+				t.Error("条目ID为空时应返回错误")
+			}
+		})
+	}
+}
+
+func TestStoreMetadata(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			metadata, err := store.Metadata()
+			if err != nil || metadata.Version != "" {
+				t.Fatalf("未设置时应返回零值，实际为%+v %v", metadata, err)
+			}
+
+			if err := store.SetMetadata(StoreMetadata{Version: "4.16"}); err != nil {
+				t.Fatalf("SetMetadata失败: %v", err)
+			}
+			metadata, err = store.Metadata()
+			if err != nil || metadata.Version != "4.16" {
+				t.Errorf("元数据不正确: %+v %v", metadata, err)
+			}
+		})
+	}
+}
+
+func TestFileStorePersists(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore失败: %v", err)
+	}
+	if err := store.Put(NewCWE("CWE-89", "SQL Injection")); err != nil {
+		t.Fatalf("Put失败: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "entries", "CWE-89.json")); err != nil {
+		t.Errorf("条目应保存为单独的文件: %v", err)
+	}
+
+	reopened, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("重新打开失败: %v", err)
+	}
+	if got, err := reopened.Get("CWE-89"); err != nil || got.Name != "SQL Injection" {
+		t.Errorf("重新打开后应能读取条目: %v %v", got, err)
+	}
+}
@@ -0,0 +1,117 @@
+package cwe
+
+// Subtree 将指定条目及其所有后代深拷贝到新的注册表
+//
+// 方法功能:
+// 新注册表的Root为rootID对应条目的副本，其Parent为nil；其余副本的Parent和Children只指向新注册表中的副本，
+// 修改新注册表不会影响原注册表。多父节点的条目只拷贝一次，子树外的父节点关系被丢弃。
+// 新注册表沿用原注册表的层次模式和严重性覆盖层。
+//
+// 参数:
+// - rootID: string - 子树根节点ID，支持ParseCWEID接受的所有格式
+//
+// 返回值:
+// - *Registry: 只包含子树的新注册表
+// - error: 条目不存在时返回包装了ErrNotFound的错误
+//
+// 使用示例:
+// ```go
+// inputValidation, err := registry.Subtree("CWE-20")
+//
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// data, _ := inputValidation.ExportToJSON()
+// ```
+func (r *Registry) Subtree(rootID string) (*Registry, error) {
+	root, err := r.GetByID(normalizeEntryID(rootID))
+	if err != nil {
+		return nil, err
+	}
+
+	copies := copyTree([]*CWE{root})
+
+	subtree := NewRegistry()
+	subtree.Root = copies[root]
+	r.mutex.RLock()
+	subtree.hierarchyMode = r.hierarchyMode
+	subtree.severityOverlay = r.severityOverlay
+	for original, entryCopy := range copies {
+		subtree.Entries[entryCopy.ID] = entryCopy
+		for _, parentID := range r.parents[original.ID] {
+			if parent, exists := r.Entries[parentID]; exists && copies[parent] != nil {
+				if subtree.parents == nil {
+					subtree.parents = make(map[string][]string)
+				}
+				subtree.parents[entryCopy.ID] = append(subtree.parents[entryCopy.ID], parentID)
+			}
+		}
+	}
+	subtree.viewChildren = copyViewChildren(r.viewChildren, func(id string) bool {
+		_, exists := subtree.Entries[id]
+		return exists
+	})
+	subtree.annotations = copyAnnotations(r.annotations, func(id string) bool {
+		_, exists := subtree.Entries[id]
+		return exists
+	})
+	r.mutex.RUnlock()
+
+	return subtree, nil
+}
+
+// Prune 从当前节点的子树中移除满足条件的分支
+//
+// 方法功能:
+// 深度优先检查所有后代(不包括当前节点本身)，predicate返回true的节点连同其整个子树从父节点的Children中移除，
+// 被移除节点的子树不再检查。被移除节点的Parent如果指向移除它的父节点会被置为nil。
+// Prune只修改树结构，注册表中的条目不会被删除；需要保留原树时先使用Registry.Subtree得到副本。
+//
+// 参数:
+// - predicate: func(*CWE) bool - 返回true表示移除该分支
+//
+// 返回值:
+// - int: 移除的分支数(不计被移除分支内部的节点)
+//
+// 使用示例:
+// ```go
+// // 移除已弃用的分支
+//
+//	removed := registry.Root.Prune(func(entry *cwe.CWE) bool {
+//	    return entry.Status == "Deprecated"
+//	})
+//
+// ```
+func (c *CWE) Prune(predicate func(*CWE) bool) int {
+	return c.prune(predicate, make(map[*CWE]bool))
+}
+
+// prune 是Prune的递归实现，visited避免共享节点和环被重复检查
+func (c *CWE) prune(predicate func(*CWE) bool, visited map[*CWE]bool) int {
+	visited[c] = true
+	removed := 0
+	kept := c.Children[:0]
+	for _, child := range c.Children {
+		if predicate(child) {
+			if child.Parent == c {
+				child.Parent = nil
+			}
+			removed++
+			continue
+		}
+		kept = append(kept, child)
+	}
+	// 清除被移除的尾部元素，避免底层数组继续引用被移除的节点
+	for i := len(kept); i < len(c.Children); i++ {
+		c.Children[i] = nil
+	}
+	c.Children = kept
+
+	for _, child := range c.Children {
+		if !visited[child] {
+			removed += child.prune(predicate, visited)
+		}
+	}
+	return removed
+}
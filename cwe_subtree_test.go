@@ -0,0 +1,102 @@
+package cwe
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRegistrySubtree(t *testing.T) {
+	registry := newQueryTestRegistry()
+
+	subtree, err := registry.Subtree("20")
+	if err != nil {
+		t.Fatalf("Subtree失败: %v", err)
+	}
+
+	if got := strings.Join(subtree.Query().IDs(), ","); got != "CWE-20,CWE-79,CWE-89" {
+		t.Errorf("子树条目不正确: %s", got)
+	}
+	if subtree.Root == nil || subtree.Root.ID != "CWE-20" || subtree.Root.Parent != nil {
+		t.Fatalf("子树Root不正确: %+v", subtree.Root)
+	}
+	if subtree.Root == registry.Entries["CWE-20"] {
+		t.Fatal("子树应是深拷贝")
+	}
+
+	xss := subtree.Entries["CWE-79"]
+	if xss.Parent != subtree.Root {
+		t.Error("副本的Parent应指向子树中的副本")
+	}
+	if len(subtree.Root.Children) != 2 || subtree.Root.Children[0] != xss {
+		t.Errorf("副本的Children不正确: %v", subtree.Root.Children)
+	}
+
+	// 修改副本不影响原注册表
+	xss.Name = "changed"
+	xss.Mitigations[0] = "changed"
+	subtree.Root.AddChild(NewCWE("CWE-1", "new"))
+	original := registry.Entries["CWE-79"]
+	if original.Name == "changed" || original.Mitigations[0] == "changed" {
+		t.Error("修改副本影响了原条目")
+	}
+	if len(registry.Entries["CWE-20"].Children) != 2 {
+		t.Error("修改副本影响了原树结构")
+	}
+
+	if _, err := registry.Subtree("CWE-9999"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("不存在的根节点应返回ErrNotFound，实际 %v", err)
+	}
+}
+
+func TestRegistrySubtreeSharedNode(t *testing.T) {
+	registry := newQueryTestRegistry()
+	registry.SetHierarchyMode(HierarchyMultiParent)
+	// CWE-89同时是CWE-287的子节点
+	if err := registry.BuildHierarchy(map[string][]string{"CWE-287": {"CWE-89"}}); err != nil {
+		t.Fatalf("BuildHierarchy失败: %v", err)
+	}
+
+	subtree, err := registry.Subtree("CWE-287")
+	if err != nil {
+		t.Fatalf("Subtree失败: %v", err)
+	}
+	if got := strings.Join(subtree.Query().IDs(), ","); got != "CWE-89,CWE-287,CWE-306" {
+		t.Fatalf("子树条目不正确: %s", got)
+	}
+	// 原父节点CWE-20不在子树中，Parent改为子树中的父节点
+	if subtree.Entries["CWE-89"].Parent != subtree.Root {
+		t.Error("原父节点不在子树中时Parent应指向子树中的父节点")
+	}
+	parents, err := subtree.Parents("CWE-89")
+	if err != nil || len(parents) != 1 || parents[0].ID != "CWE-287" {
+		t.Errorf("子树外的父节点关系应被丢弃: %v %v", parents, err)
+	}
+}
+
+func TestCWEPrune(t *testing.T) {
+	registry := newQueryTestRegistry()
+	xss := registry.Entries["CWE-79"]
+
+	removed := registry.Root.Prune(func(entry *CWE) bool {
+		return entry.ID == "CWE-79" || entry.ID == "CWE-287"
+	})
+	if removed != 2 {
+		t.Errorf("期望移除2个分支，实际 %d", removed)
+	}
+
+	ids := make([]string, 0)
+	registry.Root.Walk(func(entry *CWE) bool {
+		ids = append(ids, entry.ID)
+		return true
+	})
+	if got := strings.Join(ids, ","); got != "CWE-1000,CWE-20,CWE-89" {
+		t.Errorf("剪枝后的树不正确: %s", got)
+	}
+	if xss.Parent != nil {
+		t.Error("被移除节点的Parent应被置为nil")
+	}
+	if _, exists := registry.Entries["CWE-79"]; !exists {
+		t.Error("Prune不应删除注册表中的条目")
+	}
+}
@@ -0,0 +1,155 @@
+package cwe
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultSuggestionLimit SuggestIDs的n不大于0时返回的最大建议数
+const DefaultSuggestionLimit = 5
+
+// SuggestIDs 返回与query最接近的条目ID，用于"您是否要找"提示
+//
+// 方法功能:
+// 按编辑距离对注册表中的条目排序，容忍输入中的拼写错误。
+// query是CWE ID时(支持ParseCWEID接受的所有格式)比较编号，如"CWE-87"会建议CWE-89、CWE-287等；
+// 否则比较条目的名称和替代术语，名称中与query单词数相同的任意连续片段都参与比较，
+// 因此"SQL Injecton"可以找到名称很长的CWE-89。比较不区分大小写，并忽略标点符号。
+// 编辑距离超过query长度的四分之一(至少为1)的条目不作为建议。
+//
+// 参数:
+// - query: string - 用户输入的ID或名称
+// - n: int - 最多返回的建议数，不大于0时使用DefaultSuggestionLimit
+//
+// 返回值:
+// - []string: 建议的条目ID，按编辑距离从小到大排序，距离相同时按CWE编号排序；没有建议时返回nil
+//
+// 使用示例:
+// ```go
+// entry, err := registry.GetByID(input)
+//
+//	if errors.Is(err, cwe.ErrNotFound) {
+//	    if suggestions := registry.SuggestIDs(input, 3); len(suggestions) > 0 {
+//	        fmt.Printf("未找到%s，您是否要找: %s\n", input, strings.Join(suggestions, ", "))
+//	    }
+//	}
+//
+// ```
+func (r *Registry) SuggestIDs(query string, n int) []string {
+	if n <= 0 {
+		n = DefaultSuggestionLimit
+	}
+
+	type suggestion struct {
+		id       string
+		distance int
+	}
+	var suggestions []suggestion
+
+	r.mutex.RLock()
+	if id, err := ParseCWEID(query); err == nil {
+		number := strings.TrimPrefix(id, "CWE-")
+		maxDistance := suggestionThreshold(number)
+		for _, entry := range r.Entries {
+			entryNumber := strings.TrimPrefix(normalizeEntryID(entry.ID), "CWE-")
+			if distance := editDistance(number, entryNumber); distance <= maxDistance {
+				suggestions = append(suggestions, suggestion{entry.ID, distance})
+			}
+		}
+	} else if words := suggestionWords(query); len(words) > 0 {
+		normalized := strings.Join(words, " ")
+		maxDistance := suggestionThreshold(normalized)
+		for _, entry := range r.Entries {
+			distance := maxDistance + 1
+			for _, text := range append([]string{entry.Name}, entry.AlternateTerms...) {
+				if d := windowDistance(normalized, len(words), suggestionWords(text)); d < distance {
+					distance = d
+				}
+			}
+			if distance <= maxDistance {
+				suggestions = append(suggestions, suggestion{entry.ID, distance})
+			}
+		}
+	}
+	r.mutex.RUnlock()
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].distance != suggestions[j].distance {
+			return suggestions[i].distance < suggestions[j].distance
+		}
+		return compareCWEIDs(suggestions[i].id, suggestions[j].id) < 0
+	})
+	if len(suggestions) > n {
+		suggestions = suggestions[:n]
+	}
+
+	var ids []string
+	for _, s := range suggestions {
+		ids = append(ids, s.id)
+	}
+	return ids
+}
+
+// suggestionThreshold 返回作为建议允许的最大编辑距离
+func suggestionThreshold(query string) int {
+	if threshold := len([]rune(query)) / 4; threshold > 1 {
+		return threshold
+	}
+	return 1
+}
+
+// suggestionWords 将文本拆分为小写的单词，字母、数字和"-"以外的字符视为分隔符
+func suggestionWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	})
+}
+
+// windowDistance 返回query与words中连续size个单词组成的片段之间的最小编辑距离
+// words少于size个单词时与整个words比较
+func windowDistance(query string, size int, words []string) int {
+	if len(words) <= size {
+		return editDistance(query, strings.Join(words, " "))
+	}
+	best := -1
+	for i := 0; i+size <= len(words); i++ {
+		if d := editDistance(query, strings.Join(words[i:i+size], " ")); best < 0 || d < best {
+			best = d
+		}
+	}
+	return best
+}
+
+// editDistance 计算a和b之间按字符计的Levenshtein编辑距离
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = previous[j-1] + cost
+			if d := previous[j] + 1; d < current[j] {
+				current[j] = d
+			}
+			if d := current[j-1] + 1; d < current[j] {
+				current[j] = d
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// SuggestIDs 返回与query最接近的条目ID，见Registry.SuggestIDs
+func (s *FrozenRegistry) SuggestIDs(query string, n int) []string {
+	return s.registry.SuggestIDs(query, n)
+}
@@ -0,0 +1,55 @@
+package cwe
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"sql injecton", "sql injection", 1},
+		{"注入", "注人", 1},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, 期望 %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestRegistrySuggestIDs(t *testing.T) {
+	registry := newQueryTestRegistry()
+	registry.Entries["CWE-89"].Name = "Improper Neutralization of Special Elements used in an SQL Command ('SQL Injection')"
+	registry.Entries["CWE-306"].AlternateTerms = []string{"Unauthenticated Function"}
+
+	tests := []struct {
+		query string
+		n     int
+		want  []string
+	}{
+		{"CWE-87", 0, []string{"CWE-89", "CWE-287"}},
+		{"87", 1, []string{"CWE-89"}},
+		{"CWE-2870", 0, []string{"CWE-287"}},
+		{"SQL Injecton", 0, []string{"CWE-89"}},
+		{"cross site scriptng", 0, []string{"CWE-79"}},
+		{"Unauthenticated Functoin", 0, []string{"CWE-306"}},
+		{"improper authentcation", 0, []string{"CWE-287"}},
+		{"completely unrelated", 0, nil},
+		{"", 0, nil},
+	}
+	for _, tt := range tests {
+		if got := registry.SuggestIDs(tt.query, tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SuggestIDs(%q, %d) = %v, 期望 %v", tt.query, tt.n, got, tt.want)
+		}
+	}
+
+	if got := registry.Freeze().SuggestIDs("SQL Injecton", 0); !reflect.DeepEqual(got, []string{"CWE-89"}) {
+		t.Errorf("FrozenRegistry.SuggestIDs = %v", got)
+	}
+}
@@ -0,0 +1,180 @@
+package cwe
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SynonymTable 记录关键词搜索时视为等价的术语
+//
+// 同一组中的术语互为同义词，搜索其中任意一个时也会匹配包含其他术语的条目，
+// 例如搜索"XSS"可以找到名称中只有"Cross-site Scripting"的CWE-79。
+// 术语不区分大小写，关键词与术语完全相同(忽略首尾空白)时才会扩展。
+// 此结构体是线程安全的。
+type SynonymTable struct {
+	mutex sync.RWMutex
+
+	// groups 术语(小写)到所在同义词组的映射，组内的术语均为小写
+	groups map[string][]string
+}
+
+// NewSynonymTable 创建空的同义词表
+func NewSynonymTable() *SynonymTable {
+	return &SynonymTable{groups: make(map[string][]string)}
+}
+
+// Add 将terms登记为一组同义词
+//
+// 方法功能:
+// 任意一个术语已经在其他组中时，两个组合并为一组。少于两个非空术语时不做任何修改。
+//
+// 参数:
+// - terms: ...string - 互为同义词的术语，如"SQLi"和"SQL Injection"
+//
+// 使用示例:
+// ```go
+// synonyms := cwe.NewSynonymTable()
+// synonyms.Add("SSTI", "Server-Side Template Injection", "template injection")
+//
+// registry.SetSynonyms(synonyms)
+// page, err := registry.Search(cwe.SearchQuery{Keyword: "ssti"})
+// ```
+func (t *SynonymTable) Add(terms ...string) {
+	seen := make(map[string]bool)
+	var group []string
+	for _, term := range terms {
+		term = normalizeSynonym(term)
+		if term != "" && !seen[term] {
+			seen[term] = true
+			group = append(group, term)
+		}
+	}
+	if len(group) < 2 {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	merged := append([]string(nil), group...)
+	for _, term := range group {
+		for _, existing := range t.groups[term] {
+			if !seen[existing] {
+				seen[existing] = true
+				merged = append(merged, existing)
+			}
+		}
+	}
+	sort.Strings(merged)
+	for _, term := range merged {
+		t.groups[term] = merged
+	}
+}
+
+// Synonyms 返回term的所有同义词，不包括term本身，按字母顺序排序
+func (t *SynonymTable) Synonyms(term string) []string {
+	term = normalizeSynonym(term)
+
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	var synonyms []string
+	for _, synonym := range t.groups[term] {
+		if synonym != term {
+			synonyms = append(synonyms, synonym)
+		}
+	}
+	return synonyms
+}
+
+// Expand 返回搜索keyword时需要匹配的所有术语(小写)，第一个元素是keyword本身
+func (t *SynonymTable) Expand(keyword string) []string {
+	keyword = normalizeSynonym(keyword)
+	if t == nil || keyword == "" {
+		return []string{keyword}
+	}
+	return append([]string{keyword}, t.Synonyms(keyword)...)
+}
+
+// normalizeSynonym 将术语规范化为小写并合并连续的空白
+func normalizeSynonym(term string) string {
+	return strings.ToLower(strings.Join(strings.Fields(term), " "))
+}
+
+// DefaultSynonyms 是关键词搜索默认使用的同义词表，包含常见安全术语的缩写
+// 可以通过Add补充，影响所有未通过SetSynonyms设置其他同义词表的注册表和FindByKeyword
+var DefaultSynonyms = newDefaultSynonyms()
+
+// newDefaultSynonyms 创建DefaultSynonyms
+// 扩展后的术语取自CWE条目名称中的写法，以便通过子串匹配找到对应的条目
+func newDefaultSynonyms() *SynonymTable {
+	table := NewSynonymTable()
+	for _, group := range [][]string{
+		{"XSS", "Cross-site Scripting"},
+		{"SQLi", "SQL Injection"},
+		{"CSRF", "XSRF", "Cross-Site Request Forgery"},
+		{"SSRF", "Server-Side Request Forgery"},
+		{"XXE", "XML External Entity"},
+		{"RCE", "Code Injection"},
+		{"OS Command Injection", "Shell Injection"},
+		{"LFI", "Path Traversal"},
+		{"Directory Traversal", "Path Traversal"},
+		{"UAF", "Use After Free"},
+		{"Buffer Overflow", "Out-of-bounds Write"},
+		{"Buffer Over-read", "Out-of-bounds Read"},
+		{"TOCTOU", "Time-of-check Time-of-use"},
+		{"DoS", "Denial of Service", "Uncontrolled Resource Consumption"},
+		{"IDOR", "Authorization Bypass Through User-Controlled Key"},
+		{"Open Redirect", "URL Redirection to Untrusted Site"},
+		{"Deserialization", "Deserialization of Untrusted Data"},
+		{"Hardcoded Password", "Hard-coded Password"},
+		{"Hardcoded Credentials", "Hard-coded Credentials"},
+		{"NPD", "NULL Pointer Dereference"},
+		{"Integer Overflow", "Integer Overflow or Wraparound"},
+		{"Clickjacking", "Improper Restriction of Rendered UI Layers or Frames"},
+	} {
+		table.Add(group...)
+	}
+	return table
+}
+
+// SetSynonyms 设置Search扩展关键词使用的同义词表
+// synonyms为nil时使用DefaultSynonyms；不需要同义词扩展时传入NewSynonymTable()创建的空表
+func (r *Registry) SetSynonyms(synonyms *SynonymTable) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.synonyms = synonyms
+}
+
+// getSynonyms 返回Search使用的同义词表
+func (r *Registry) getSynonyms() *SynonymTable {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if r.synonyms == nil {
+		return DefaultSynonyms
+	}
+	return r.synonyms
+}
+
+// matchesAnyTerm 判断条目的名称、描述或替代术语是否包含terms中的任意一个(terms均为小写)
+// includeID为true时同时匹配ID
+func matchesAnyTerm(entry *CWE, terms []string, includeID bool) bool {
+	fields := make([]string, 0, 3+len(entry.AlternateTerms))
+	fields = append(fields, strings.ToLower(entry.Name), strings.ToLower(entry.Description))
+	if includeID {
+		fields = append(fields, strings.ToLower(entry.ID))
+	}
+	for _, term := range entry.AlternateTerms {
+		fields = append(fields, strings.ToLower(term))
+	}
+
+	for _, term := range terms {
+		for _, field := range fields {
+			if strings.Contains(field, term) {
+				return true
+			}
+		}
+	}
+	return false
+}
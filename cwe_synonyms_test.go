@@ -0,0 +1,103 @@
+package cwe
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSynonymTable(t *testing.T) {
+	table := NewSynonymTable()
+	table.Add("SQLi", "SQL Injection")
+	table.Add("sql  injection", "SQLI Attack")
+	table.Add("single")
+
+	if got := table.Synonyms(" sqli "); !reflect.DeepEqual(got, []string{"sql injection", "sqli attack"}) {
+		t.Errorf("Synonyms不正确，重叠的组应合并: %v", got)
+	}
+	if got := table.Synonyms("single"); got != nil {
+		t.Errorf("少于两个术语时不应登记，实际为%v", got)
+	}
+	if got := table.Expand("SQL Injection"); !reflect.DeepEqual(got, []string{"sql injection", "sqli", "sqli attack"}) {
+		t.Errorf("Expand不正确: %v", got)
+	}
+	if got := table.Expand("unknown"); !reflect.DeepEqual(got, []string{"unknown"}) {
+		t.Errorf("不在表中的关键词应只返回自身: %v", got)
+	}
+
+	var nilTable *SynonymTable
+	if got := nilTable.Expand("XSS"); !reflect.DeepEqual(got, []string{"xss"}) {
+		t.Errorf("nil表不应扩展关键词: %v", got)
+	}
+}
+
+func TestRegistrySearchSynonyms(t *testing.T) {
+	registry := newQueryTestRegistry()
+	registry.Entries["CWE-306"].AlternateTerms = []string{"Unauthenticated Access"}
+
+	tests := []struct {
+		keyword string
+		want    []string
+	}{
+		{"xss", []string{"CWE-79"}},
+		{"SQLi", []string{"CWE-89"}},
+		{"unauthenticated", []string{"CWE-306"}},
+		{"79", []string{"CWE-79"}},
+	}
+	for _, tt := range tests {
+		page, err := registry.Search(SearchQuery{Keyword: tt.keyword})
+		if err != nil {
+			t.Fatalf("Search(%q)失败: %v", tt.keyword, err)
+		}
+		if got := cweIDsOf(page.Results); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Search(%q) = %v, 期望 %v", tt.keyword, got, tt.want)
+		}
+	}
+
+	custom := NewSynonymTable()
+	custom.Add("auth", "Authentication")
+	registry.SetSynonyms(custom)
+	page, _ := registry.Search(SearchQuery{Keyword: "xss"})
+	if len(page.Results) != 0 {
+		t.Errorf("自定义同义词表不包含xss时不应扩展，实际为%v", cweIDsOf(page.Results))
+	}
+	if got := registry.Clone().getSynonyms(); got != custom {
+		t.Error("Clone应保留同义词表")
+	}
+
+	registry.SetSynonyms(nil)
+	page, _ = registry.Search(SearchQuery{Keyword: "xss"})
+	if got := cweIDsOf(page.Results); !reflect.DeepEqual(got, []string{"CWE-79"}) {
+		t.Errorf("SetSynonyms(nil)后应恢复默认同义词表，实际为%v", got)
+	}
+}
+
+func TestFindByKeywordSynonyms(t *testing.T) {
+	registry := newQueryTestRegistry()
+	registry.Entries["CWE-20"].AlternateTerms = []string{"Input Sanitization"}
+
+	if got := cweIDsOf(FindByKeyword(registry.Root, "sqli")); !reflect.DeepEqual(got, []string{"CWE-89"}) {
+		t.Errorf("FindByKeyword(sqli) = %v", got)
+	}
+	if got := cweIDsOf(FindByKeyword(registry.Root, "sanitization")); !reflect.DeepEqual(got, []string{"CWE-20"}) {
+		t.Errorf("FindByKeyword应匹配替代术语，实际为%v", got)
+	}
+}
+
+func TestConvertToCWEAlternateTerms(t *testing.T) {
+	fetcher := NewDataFetcher()
+	entry, err := fetcher.convertToCWE(&CWEWeakness{
+		ID:   "79",
+		Name: "Cross-site Scripting",
+		AlternateTerms: []CWEAlternateTerm{
+			{Term: "XSS"},
+			{Term: ""},
+			{Term: "HTML Injection", Description: "..."},
+		},
+	})
+	if err != nil {
+		t.Fatalf("convertToCWE失败: %v", err)
+	}
+	if !reflect.DeepEqual(entry.AlternateTerms, []string{"XSS", "HTML Injection"}) {
+		t.Errorf("AlternateTerms不正确: %v", entry.AlternateTerms)
+	}
+}
@@ -0,0 +1,192 @@
+// Code generated by cwegen; DO NOT EDIT.
+
+package cwe
+
+// KnownCWEVersion 内置常量和查找表对应的CWE版本
+const KnownCWEVersion = "4.16"
+
+// 内置的CWE ID常量
+const (
+	// CWE20 Improper Input Validation
+	CWE20 CWEID = "CWE-20"
+	// CWE22 Improper Limitation of a Pathname to a Restricted Directory ('Path Traversal')
+	CWE22 CWEID = "CWE-22"
+	// CWE62 UNIX Hard Link
+	CWE62 CWEID = "CWE-62"
+	// CWE71 DEPRECATED: Apple '.DS_Store'
+	CWE71 CWEID = "CWE-71"
+	// CWE77 Improper Neutralization of Special Elements used in a Command ('Command Injection')
+	CWE77 CWEID = "CWE-77"
+	// CWE78 Improper Neutralization of Special Elements used in an OS Command ('OS Command Injection')
+	CWE78 CWEID = "CWE-78"
+	// CWE79 Improper Neutralization of Input During Web Page Generation ('Cross-site Scripting')
+	CWE79 CWEID = "CWE-79"
+	// CWE89 Improper Neutralization of Special Elements used in an SQL Command ('SQL Injection')
+	CWE89 CWEID = "CWE-89"
+	// CWE94 Improper Control of Generation of Code ('Code Injection')
+	CWE94 CWEID = "CWE-94"
+	// CWE119 Improper Restriction of Operations within the Bounds of a Memory Buffer
+	CWE119 CWEID = "CWE-119"
+	// CWE125 Out-of-bounds Read
+	CWE125 CWEID = "CWE-125"
+	// CWE190 Integer Overflow or Wraparound
+	CWE190 CWEID = "CWE-190"
+	// CWE200 Exposure of Sensitive Information to an Unauthorized Actor
+	CWE200 CWEID = "CWE-200"
+	// CWE269 Improper Privilege Management
+	CWE269 CWEID = "CWE-269"
+	// CWE276 Incorrect Default Permissions
+	CWE276 CWEID = "CWE-276"
+	// CWE287 Improper Authentication
+	CWE287 CWEID = "CWE-287"
+	// CWE306 Missing Authentication for Critical Function
+	CWE306 CWEID = "CWE-306"
+	// CWE352 Cross-Site Request Forgery (CSRF)
+	CWE352 CWEID = "CWE-352"
+	// CWE362 Concurrent Execution using Shared Resource with Improper Synchronization ('Race Condition')
+	CWE362 CWEID = "CWE-362"
+	// CWE400 Uncontrolled Resource Consumption
+	CWE400 CWEID = "CWE-400"
+	// CWE416 Use After Free
+	CWE416 CWEID = "CWE-416"
+	// CWE434 Unrestricted Upload of File with Dangerous Type
+	CWE434 CWEID = "CWE-434"
+	// CWE476 NULL Pointer Dereference
+	CWE476 CWEID = "CWE-476"
+	// CWE502 Deserialization of Untrusted Data
+	CWE502 CWEID = "CWE-502"
+	// CWE699 Software Development
+	CWE699 CWEID = "CWE-699"
+	// CWE787 Out-of-bounds Write
+	CWE787 CWEID = "CWE-787"
+	// CWE798 Use of Hard-coded Credentials
+	CWE798 CWEID = "CWE-798"
+	// CWE862 Missing Authorization
+	CWE862 CWEID = "CWE-862"
+	// CWE863 Incorrect Authorization
+	CWE863 CWEID = "CWE-863"
+	// CWE918 Server-Side Request Forgery (SSRF)
+	CWE918 CWEID = "CWE-918"
+	// CWE1000 Research Concepts
+	CWE1000 CWEID = "CWE-1000"
+	// CWE1003 Weaknesses for Simplified Mapping of Published Vulnerabilities
+	CWE1003 CWEID = "CWE-1003"
+	// CWE1194 Hardware Design
+	CWE1194 CWEID = "CWE-1194"
+	// CWE1425 Weaknesses in the 2023 CWE Top 25 Most Dangerous Software Weaknesses
+	CWE1425 CWEID = "CWE-1425"
+	// CWE1430 Weaknesses in the 2024 CWE Top 25 Most Dangerous Software Weaknesses
+	CWE1430 CWEID = "CWE-1430"
+)
+
+// knownViewNames 已知视图的名称
+var knownViewNames = map[CWEID]string{
+	CWE1000: "Research Concepts",
+	CWE699:  "Software Development",
+	CWE1194: "Hardware Design",
+	CWE1003: "Weaknesses for Simplified Mapping of Published Vulnerabilities",
+	CWE1425: "Weaknesses in the 2023 CWE Top 25 Most Dangerous Software Weaknesses",
+	CWE1430: "Weaknesses in the 2024 CWE Top 25 Most Dangerous Software Weaknesses",
+}
+
+// top25Lists 各年份的Top 25列表，按排名排序
+var top25Lists = map[int][]CWEID{
+	2023: {
+		CWE787,
+		CWE79,
+		CWE89,
+		CWE416,
+		CWE78,
+		CWE20,
+		CWE125,
+		CWE22,
+		CWE352,
+		CWE434,
+		CWE862,
+		CWE476,
+		CWE287,
+		CWE190,
+		CWE502,
+		CWE77,
+		CWE119,
+		CWE798,
+		CWE918,
+		CWE306,
+		CWE362,
+		CWE269,
+		CWE94,
+		CWE863,
+		CWE276,
+	},
+	2024: {
+		CWE79,
+		CWE787,
+		CWE89,
+		CWE352,
+		CWE22,
+		CWE125,
+		CWE78,
+		CWE416,
+		CWE862,
+		CWE434,
+		CWE94,
+		CWE20,
+		CWE77,
+		CWE287,
+		CWE269,
+		CWE502,
+		CWE200,
+		CWE863,
+		CWE918,
+		CWE119,
+		CWE476,
+		CWE798,
+		CWE190,
+		CWE400,
+		CWE306,
+	},
+}
+
+// deprecatedAliases 废弃条目到替代条目的映射
+var deprecatedAliases = map[CWEID]CWEID{
+	CWE71: CWE62,
+}
+
+// knownEntries 快照中条目的元数据，见LookupKnown
+var knownEntries = map[CWEID]KnownEntry{
+	CWE20:   {ID: CWE20, Name: "Improper Input Validation"},
+	CWE22:   {ID: CWE22, Name: "Improper Limitation of a Pathname to a Restricted Directory ('Path Traversal')"},
+	CWE62:   {ID: CWE62, Name: "UNIX Hard Link"},
+	CWE71:   {ID: CWE71, Name: "DEPRECATED: Apple '.DS_Store'", Status: "Deprecated"},
+	CWE77:   {ID: CWE77, Name: "Improper Neutralization of Special Elements used in a Command ('Command Injection')"},
+	CWE78:   {ID: CWE78, Name: "Improper Neutralization of Special Elements used in an OS Command ('OS Command Injection')"},
+	CWE79:   {ID: CWE79, Name: "Improper Neutralization of Input During Web Page Generation ('Cross-site Scripting')"},
+	CWE89:   {ID: CWE89, Name: "Improper Neutralization of Special Elements used in an SQL Command ('SQL Injection')"},
+	CWE94:   {ID: CWE94, Name: "Improper Control of Generation of Code ('Code Injection')"},
+	CWE119:  {ID: CWE119, Name: "Improper Restriction of Operations within the Bounds of a Memory Buffer"},
+	CWE125:  {ID: CWE125, Name: "Out-of-bounds Read"},
+	CWE190:  {ID: CWE190, Name: "Integer Overflow or Wraparound"},
+	CWE200:  {ID: CWE200, Name: "Exposure of Sensitive Information to an Unauthorized Actor"},
+	CWE269:  {ID: CWE269, Name: "Improper Privilege Management"},
+	CWE276:  {ID: CWE276, Name: "Incorrect Default Permissions"},
+	CWE287:  {ID: CWE287, Name: "Improper Authentication"},
+	CWE306:  {ID: CWE306, Name: "Missing Authentication for Critical Function"},
+	CWE352:  {ID: CWE352, Name: "Cross-Site Request Forgery (CSRF)"},
+	CWE362:  {ID: CWE362, Name: "Concurrent Execution using Shared Resource with Improper Synchronization ('Race Condition')"},
+	CWE400:  {ID: CWE400, Name: "Uncontrolled Resource Consumption"},
+	CWE416:  {ID: CWE416, Name: "Use After Free"},
+	CWE434:  {ID: CWE434, Name: "Unrestricted Upload of File with Dangerous Type"},
+	CWE476:  {ID: CWE476, Name: "NULL Pointer Dereference"},
+	CWE502:  {ID: CWE502, Name: "Deserialization of Untrusted Data"},
+	CWE699:  {ID: CWE699, Name: "Software Development"},
+	CWE787:  {ID: CWE787, Name: "Out-of-bounds Write"},
+	CWE798:  {ID: CWE798, Name: "Use of Hard-coded Credentials"},
+	CWE862:  {ID: CWE862, Name: "Missing Authorization"},
+	CWE863:  {ID: CWE863, Name: "Incorrect Authorization"},
+	CWE918:  {ID: CWE918, Name: "Server-Side Request Forgery (SSRF)"},
+	CWE1000: {ID: CWE1000, Name: "Research Concepts"},
+	CWE1003: {ID: CWE1003, Name: "Weaknesses for Simplified Mapping of Published Vulnerabilities"},
+	CWE1194: {ID: CWE1194, Name: "Hardware Design"},
+	CWE1425: {ID: CWE1425, Name: "Weaknesses in the 2023 CWE Top 25 Most Dangerous Software Weaknesses"},
+	CWE1430: {ID: CWE1430, Name: "Weaknesses in the 2024 CWE Top 25 Most Dangerous Software Weaknesses"},
+}
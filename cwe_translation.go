@@ -0,0 +1,319 @@
+package cwe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// 可以翻译的字段，用于CWE.SetTranslation
+const (
+	// TranslationFieldName 条目名称
+	TranslationFieldName = "name"
+
+	// TranslationFieldDescription 条目描述
+	TranslationFieldDescription = "description"
+)
+
+// Translation 是条目在某种语言下的名称和描述，为空的字段表示没有翻译
+type Translation struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// SetTranslation 设置条目在指定语言下的文本
+//
+// 方法功能:
+// 翻译与Name、Description分开保存，不会覆盖原文；渲染和导出函数通过选项中的Locale选择语言，
+// 也可以通过LocalizedName和LocalizedDescription读取。
+// text为空时删除该字段的翻译，两个字段都没有翻译时删除该语言。
+//
+// 参数:
+// - locale: string - 语言标签，如"zh-CN"、"ja"，大小写和"_"分隔符会被规范化，见NormalizeLocale
+// - field: string - TranslationFieldName或TranslationFieldDescription
+// - text: string - 翻译后的文本
+//
+// 返回值:
+// - error: 语言标签为空或字段不支持翻译时返回错误
+//
+// 使用示例:
+// ```go
+// xss := cwe.NewCWE("CWE-79", "Improper Neutralization of Input During Web Page Generation")
+// xss.SetTranslation("zh-CN", cwe.TranslationFieldName, "跨站脚本")
+//
+// fmt.Println(xss.LocalizedName("zh-CN")) // 跨站脚本
+// fmt.Println(xss.LocalizedName("fr"))    // 没有翻译，输出英文原文
+// ```
+func (c *CWE) SetTranslation(locale, field, text string) error {
+	locale = NormalizeLocale(locale)
+	if locale == "" {
+		return errors.New("语言标签不能为空")
+	}
+
+	translation := c.Translations[locale]
+	switch field {
+	case TranslationFieldName:
+		translation.Name = text
+	case TranslationFieldDescription:
+		translation.Description = text
+	default:
+		return fmt.Errorf("字段%q不支持翻译", field)
+	}
+
+	if translation == (Translation{}) {
+		delete(c.Translations, locale)
+		if len(c.Translations) == 0 {
+			c.Translations = nil
+		}
+		return nil
+	}
+	if c.Translations == nil {
+		c.Translations = make(map[string]Translation)
+	}
+	c.Translations[locale] = translation
+	return nil
+}
+
+// Translation 返回条目在指定语言下的翻译，不进行语言回退
+func (c *CWE) Translation(locale string) (Translation, bool) {
+	translation, exists := c.Translations[NormalizeLocale(locale)]
+	return translation, exists
+}
+
+// Locales 返回条目有翻译的所有语言，按字母顺序排序
+func (c *CWE) Locales() []string {
+	locales := make([]string, 0, len(c.Translations))
+	for locale := range c.Translations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// LocalizedName 返回指定语言下的名称
+// 依次尝试完整的语言标签和去掉地区等后缀的标签(如"zh-Hant-TW"、"zh-Hant"、"zh")，都没有翻译时返回Name
+func (c *CWE) LocalizedName(locale string) string {
+	return c.localized(locale, c.Name, func(t Translation) string { return t.Name })
+}
+
+// LocalizedDescription 返回指定语言下的描述，语言回退规则与LocalizedName相同
+func (c *CWE) LocalizedDescription(locale string) string {
+	return c.localized(locale, c.Description, func(t Translation) string { return t.Description })
+}
+
+// localized 按语言回退规则查找field返回的翻译，找不到时返回original
+func (c *CWE) localized(locale, original string, field func(Translation) string) string {
+	if len(c.Translations) == 0 {
+		return original
+	}
+	for _, candidate := range localeFallbacks(locale) {
+		if text := field(c.Translations[candidate]); text != "" {
+			return text
+		}
+	}
+	return original
+}
+
+// NormalizeLocale 将语言标签规范化为BCP 47的常用写法
+//
+// 语言部分小写，4个字母的文字部分首字母大写，2个字母或3个数字的地区部分大写，"_"视为"-"，
+// 如"zh_cn"规范化为"zh-CN"，"ZH-hant-tw"规范化为"zh-Hant-TW"。空白标签返回空字符串。
+func NormalizeLocale(locale string) string {
+	locale = strings.TrimSpace(locale)
+	if locale == "" {
+		return ""
+	}
+
+	parts := strings.Split(strings.ReplaceAll(locale, "_", "-"), "-")
+	for i, part := range parts {
+		part = strings.ToLower(part)
+		switch {
+		case i == 0:
+		case len(part) == 4 && isASCIILetters(part):
+			part = strings.ToUpper(part[:1]) + part[1:]
+		case len(part) == 2 && isASCIILetters(part), len(part) == 3 && isASCIIDigits(part):
+			part = strings.ToUpper(part)
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, "-")
+}
+
+// localeFallbacks 返回查找翻译时依次尝试的语言标签，从完整的标签到只有语言部分
+func localeFallbacks(locale string) []string {
+	locale = NormalizeLocale(locale)
+	if locale == "" {
+		return nil
+	}
+
+	var candidates []string
+	for {
+		candidates = append(candidates, locale)
+		index := strings.LastIndex(locale, "-")
+		if index < 0 {
+			return candidates
+		}
+		locale = locale[:index]
+	}
+}
+
+func isASCIILetters(s string) bool {
+	for _, r := range s {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isASCIIDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// TranslationFile 是社区翻译文件的内容，一个文件包含一种语言的翻译
+//
+// JSON格式如下，条目ID支持ParseCWEID接受的所有格式:
+//
+//	{
+//	  "locale": "zh-CN",
+//	  "entries": {
+//	    "CWE-79": {"name": "跨站脚本", "description": "..."},
+//	    "89": {"name": "SQL注入"}
+//	  }
+//	}
+type TranslationFile struct {
+	// Locale 翻译的语言标签
+	Locale string `json:"locale"`
+
+	// Entries 条目ID到翻译的映射
+	Entries map[string]Translation `json:"entries"`
+}
+
+// TranslationReport 记录ApplyTranslations的结果
+type TranslationReport struct {
+	// Applied 设置了翻译的条目ID，按CWE编号排序
+	Applied []string
+
+	// Missing 翻译文件中有但注册表中不存在的条目ID，按CWE编号排序
+	Missing []string
+}
+
+// ReadTranslationFile 从JSON读取翻译文件
+//
+// 返回值:
+// - *TranslationFile: 翻译文件，语言标签和条目ID已经规范化
+// - error: JSON格式错误、缺少语言标签、条目ID无效或规范化后重复时返回错误
+func ReadTranslationFile(rd io.Reader) (*TranslationFile, error) {
+	var file TranslationFile
+	if err := json.NewDecoder(rd).Decode(&file); err != nil {
+		return nil, fmt.Errorf("解析翻译文件失败: %w", err)
+	}
+
+	file.Locale = NormalizeLocale(file.Locale)
+	if file.Locale == "" {
+		return nil, errors.New("翻译文件缺少locale")
+	}
+
+	entries := make(map[string]Translation, len(file.Entries))
+	for id, translation := range file.Entries {
+		normalized, err := ParseCWEID(id)
+		if err != nil {
+			return nil, fmt.Errorf("翻译文件中的条目ID无效: %w", err)
+		}
+		if _, exists := entries[normalized]; exists {
+			return nil, fmt.Errorf("翻译文件中的条目%s重复", normalized)
+		}
+		entries[normalized] = translation
+	}
+	file.Entries = entries
+	return &file, nil
+}
+
+// LoadTranslationFile 从文件读取翻译，见ReadTranslationFile
+func LoadTranslationFile(path string) (*TranslationFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadTranslationFile(file)
+}
+
+// ApplyTranslations 将翻译文件中的翻译设置到注册表的条目上
+//
+// 方法功能:
+// 对翻译文件中的每个条目调用SetTranslation，只设置文件中不为空的字段，
+// 条目已有的同语言翻译中的其他字段保持不变。注册表中不存在的条目记录在报告中，不会报错，
+// 因为社区翻译通常覆盖所有版本的条目，而注册表可能只包含一个视图。
+//
+// 参数:
+// - file: *TranslationFile - ReadTranslationFile或LoadTranslationFile的返回值
+//
+// 返回值:
+// - *TranslationReport: 设置了翻译和不存在的条目
+//
+// 使用示例:
+// ```go
+// file, err := cwe.LoadTranslationFile("translations/zh-CN.json")
+//
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// report := registry.ApplyTranslations(file)
+// fmt.Printf("翻译了%d个条目\n", len(report.Applied))
+//
+// markdown := cwe.RenderMarkdown(entry, cwe.MarkdownOptions{Locale: "zh-CN"})
+// ```
+func (r *Registry) ApplyTranslations(file *TranslationFile) *TranslationReport {
+	report := &TranslationReport{}
+	if file == nil {
+		return report
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for id, translation := range file.Entries {
+		entry, exists := r.Entries[normalizeEntryID(id)]
+		if !exists {
+			report.Missing = append(report.Missing, id)
+			continue
+		}
+		if translation.Name != "" {
+			entry.SetTranslation(file.Locale, TranslationFieldName, translation.Name)
+		}
+		if translation.Description != "" {
+			entry.SetTranslation(file.Locale, TranslationFieldDescription, translation.Description)
+		}
+		report.Applied = append(report.Applied, entry.ID)
+	}
+
+	for _, ids := range [][]string{report.Applied, report.Missing} {
+		sort.Slice(ids, func(i, j int) bool {
+			return compareCWEIDs(ids[i], ids[j]) < 0
+		})
+	}
+	return report
+}
+
+// copyTranslations 复制翻译映射，没有翻译时返回nil
+func copyTranslations(translations map[string]Translation) map[string]Translation {
+	if len(translations) == 0 {
+		return nil
+	}
+	copied := make(map[string]Translation, len(translations))
+	for locale, translation := range translations {
+		copied[locale] = translation
+	}
+	return copied
+}
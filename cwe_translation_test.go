@@ -0,0 +1,204 @@
+package cwe
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	tests := map[string]string{
+		"zh_cn":      "zh-CN",
+		"ZH-hant-tw": "zh-Hant-TW",
+		"EN":         "en",
+		"es-419":     "es-419",
+		" ja ":       "ja",
+		"":           "",
+	}
+	for input, want := range tests {
+		if got := NormalizeLocale(input); got != want {
+			t.Errorf("NormalizeLocale(%q) = %q, 期望 %q", input, got, want)
+		}
+	}
+}
+
+func TestCWESetTranslation(t *testing.T) {
+	entry := NewCWE("CWE-79", "Cross-site Scripting")
+	entry.Description = "original"
+
+	if err := entry.SetTranslation("zh_cn", TranslationFieldName, "跨站脚本"); err != nil {
+		t.Fatalf("SetTranslation失败: %v", err)
+	}
+	entry.SetTranslation("zh", TranslationFieldDescription, "中文描述")
+
+	if err := entry.SetTranslation("", TranslationFieldName, "x"); err == nil {
+		t.Error("语言标签为空时应返回错误")
+	}
+	if err := entry.SetTranslation("ja", "severity", "x"); err == nil {
+		t.Error("不支持翻译的字段应返回错误")
+	}
+
+	if translation, ok := entry.Translation("zh-CN"); !ok || translation.Name != "跨站脚本" {
+		t.Errorf("Translation不正确: %+v, %v", translation, ok)
+	}
+	if got := entry.Locales(); !reflect.DeepEqual(got, []string{"zh", "zh-CN"}) {
+		t.Errorf("Locales不正确: %v", got)
+	}
+
+	tests := []struct {
+		locale, name, description string
+	}{
+		{"zh-CN", "跨站脚本", "中文描述"},
+		{"zh-Hans-CN", "Cross-site Scripting", "中文描述"},
+		{"fr", "Cross-site Scripting", "original"},
+		{"", "Cross-site Scripting", "original"},
+	}
+	for _, tt := range tests {
+		if got := entry.LocalizedName(tt.locale); got != tt.name {
+			t.Errorf("LocalizedName(%q) = %q, 期望 %q", tt.locale, got, tt.name)
+		}
+		if got := entry.LocalizedDescription(tt.locale); got != tt.description {
+			t.Errorf("LocalizedDescription(%q) = %q, 期望 %q", tt.locale, got, tt.description)
+		}
+	}
+
+	entry.SetTranslation("zh-CN", TranslationFieldName, "")
+	entry.SetTranslation("zh", TranslationFieldDescription, "")
+	if entry.Translations != nil {
+		t.Errorf("删除所有翻译后Translations应为nil，实际为%v", entry.Translations)
+	}
+}
+
+func TestTranslationsAreCopied(t *testing.T) {
+	registry := newQueryTestRegistry()
+	registry.Entries["CWE-79"].SetTranslation("zh-CN", TranslationFieldName, "跨站脚本")
+
+	clone := registry.Clone()
+	clone.Entries["CWE-79"].SetTranslation("zh-CN", TranslationFieldName, "修改后")
+	if registry.Entries["CWE-79"].LocalizedName("zh-CN") != "跨站脚本" {
+		t.Error("修改副本的翻译不应影响原注册表")
+	}
+
+	data, err := json.Marshal(NewRegistrySnapshot(registry))
+	if err != nil {
+		t.Fatalf("序列化快照失败: %v", err)
+	}
+	var snapshot RegistrySnapshot
+	json.Unmarshal(data, &snapshot)
+	restored, err := snapshot.ToRegistry()
+	if err != nil {
+		t.Fatalf("恢复注册表失败: %v", err)
+	}
+	if restored.Entries["CWE-79"].LocalizedName("zh-CN") != "跨站脚本" {
+		t.Error("快照应保存翻译")
+	}
+}
+
+func TestReadTranslationFile(t *testing.T) {
+	file, err := ReadTranslationFile(strings.NewReader(`{
+		"locale": "zh_cn",
+		"entries": {
+			"79": {"name": "跨站脚本", "description": "在生成网页时未正确中和输入"},
+			"CWE-89": {"name": "SQL注入"},
+			"CWE-99999": {"name": "不存在"}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("ReadTranslationFile失败: %v", err)
+	}
+	if file.Locale != "zh-CN" || len(file.Entries) != 3 || file.Entries["CWE-79"].Name != "跨站脚本" {
+		t.Errorf("翻译文件不正确: %+v", file)
+	}
+
+	registry := newQueryTestRegistry()
+	registry.Entries["CWE-89"].SetTranslation("zh-CN", TranslationFieldDescription, "已有的描述")
+	report := registry.ApplyTranslations(file)
+
+	if !reflect.DeepEqual(report.Applied, []string{"CWE-79", "CWE-89"}) || !reflect.DeepEqual(report.Missing, []string{"CWE-99999"}) {
+		t.Errorf("报告不正确: %+v", report)
+	}
+	if got := registry.Entries["CWE-89"].LocalizedDescription("zh-CN"); got != "已有的描述" {
+		t.Errorf("文件中为空的字段不应覆盖已有的翻译，实际为%q", got)
+	}
+
+	invalid := []string{
+		`{"entries": {"79": {"name": "x"}}}`,
+		`{"locale": "zh", "entries": {"abc": {"name": "x"}}}`,
+		`{"locale": "zh", "entries": {"79": {"name": "x"}, "CWE-79": {"name": "y"}}}`,
+		`not json`,
+	}
+	for _, data := range invalid {
+		if _, err := ReadTranslationFile(strings.NewReader(data)); err == nil {
+			t.Errorf("应拒绝无效的翻译文件: %s", data)
+		}
+	}
+}
+
+func TestLoadTranslationFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ja.json")
+	os.WriteFile(path, []byte(`{"locale": "ja", "entries": {"CWE-20": {"name": "不適切な入力検証"}}}`), 0644)
+
+	file, err := LoadTranslationFile(path)
+	if err != nil {
+		t.Fatalf("LoadTranslationFile失败: %v", err)
+	}
+	if file.Entries["CWE-20"].Name != "不適切な入力検証" {
+		t.Errorf("翻译不正确: %+v", file.Entries)
+	}
+
+	if _, err := LoadTranslationFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("文件不存在时应返回错误")
+	}
+}
+
+func TestRenderWithLocale(t *testing.T) {
+	registry := newQueryTestRegistry()
+	xss := registry.Entries["CWE-79"]
+	xss.Description = "Original description"
+	xss.SetTranslation("zh-CN", TranslationFieldName, "跨站脚本")
+	xss.SetTranslation("zh-CN", TranslationFieldDescription, "中文描述")
+
+	markdown := RenderMarkdown(xss, MarkdownOptions{Locale: "zh-CN"})
+	if !strings.Contains(markdown, "CWE-79: 跨站脚本") || !strings.Contains(markdown, "中文描述") {
+		t.Errorf("Markdown应使用翻译:\n%s", markdown)
+	}
+	if markdown := RenderMarkdown(xss, MarkdownOptions{}); !strings.Contains(markdown, "Cross-site Scripting") {
+		t.Errorf("未设置Locale时应使用原文:\n%s", markdown)
+	}
+
+	var buf bytes.Buffer
+	if err := registry.ExportToMermaid(&buf, DiagramOptions{Locale: "zh-CN"}); err != nil {
+		t.Fatalf("ExportToMermaid失败: %v", err)
+	}
+	if !strings.Contains(buf.String(), "跨站脚本") || !strings.Contains(buf.String(), "SQL Injection") {
+		t.Errorf("图应使用翻译，没有翻译的节点使用原文:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := registry.ExportToDOT(&buf, DiagramOptions{Locale: "zh-CN"}); err != nil {
+		t.Fatalf("ExportToDOT失败: %v", err)
+	}
+	if !strings.Contains(buf.String(), "跨站脚本") {
+		t.Errorf("DOT应使用翻译:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	opts := HTMLReportOptions{Locale: "zh_cn", GeneratedAt: time.Unix(0, 0)}
+	if err := registry.WriteHTMLReport(&buf, opts); err != nil {
+		t.Fatalf("WriteHTMLReport失败: %v", err)
+	}
+	html := buf.String()
+	if !strings.Contains(html, `<html lang="zh-CN">`) || !strings.Contains(html, "跨站脚本") || !strings.Contains(html, "中文描述") {
+		t.Errorf("HTML报告应使用翻译:\n%s", html)
+	}
+
+	report, _ := registry.HTMLReport(HTMLReportOptions{})
+	if report.Lang != "en" {
+		t.Errorf("未设置Locale时页面语言应为en，实际为%q", report.Lang)
+	}
+}
@@ -0,0 +1,144 @@
+package cwe
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// URLCheckResult 表示单个CWE条目URL的检查结果
+type URLCheckResult struct {
+	// ID 被检查的CWE条目ID
+	ID string
+
+	// URL 被检查的网址
+	URL string
+
+	// StatusCode 最终得到的HTTP状态码，请求失败时为0
+	StatusCode int
+
+	// Err 请求过程中发生的错误，成功得到响应时为nil
+	Err error
+}
+
+// Alive 判断URL是否可访问
+// 只有成功得到2xx或3xx响应时才视为可访问
+func (r URLCheckResult) Alive() bool {
+	return r.Err == nil && r.StatusCode >= 200 && r.StatusCode < 400
+}
+
+// URLHealthReport 汇总注册表中所有条目URL的检查结果
+type URLHealthReport struct {
+	// Checked 实际发出检查请求的URL数量
+	Checked int
+
+	// Dead 无法访问的URL检查结果，按ID排序
+	Dead []URLCheckResult
+
+	// Missing URL字段为空的条目ID，已排序
+	Missing []string
+}
+
+// CheckURL 检查单个URL是否可访问
+//
+// 方法功能:
+// 通过传入的HTTPClient发送HEAD请求，因此会遵循客户端的速率限制和重试策略。
+// 部分服务器不支持HEAD请求(返回405或501)，此时会退回使用GET请求。
+//
+// 参数:
+// - ctx: context.Context - 请求上下文，用于取消和超时控制
+// - client: *HTTPClient - 用于发送请求的HTTP客户端，为nil时使用DefaultHTTPClient
+// - id: string - 条目ID，仅用于填充结果
+// - url: string - 要检查的网址
+//
+// 返回值:
+// - URLCheckResult: 检查结果，使用Alive()判断是否可访问
+func CheckURL(ctx context.Context, client *HTTPClient, id, url string) URLCheckResult {
+	if client == nil {
+		client = DefaultHTTPClient
+	}
+
+	result := URLCheckResult{ID: id, URL: url}
+
+	resp, err := client.Head(ctx, url)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+		resp.Body.Close()
+		resp, err = client.Get(ctx, url)
+	}
+
+	if resp != nil {
+		result.StatusCode = resp.StatusCode
+		resp.Body.Close()
+	}
+	if err != nil {
+		result.Err = err
+	} else if !result.Alive() {
+		result.Err = fmt.Errorf("URL返回错误状态码: %d", resp.StatusCode)
+	}
+
+	return result
+}
+
+// CheckRegistryURLs 检查注册表中所有条目的URL是否可访问
+//
+// 方法功能:
+// 依次检查注册表中每个条目的URL字段，并汇总无法访问和缺失的URL。
+// 由于convertToCWE等转换过程得到的URL可能是推断出来的，
+// 视图和类别的URL偶尔会出错，此方法可用于定期发现失效链接。
+// 所有请求都通过传入的HTTPClient发送，遵循其速率限制，因此对大型注册表会比较耗时。
+//
+// 参数:
+// - ctx: context.Context - 请求上下文，取消后剩余条目不再检查
+// - registry: *Registry - 要检查的注册表
+// - client: *HTTPClient - 用于发送请求的HTTP客户端，为nil时使用DefaultHTTPClient
+//
+// 返回值:
+// - *URLHealthReport: 检查报告
+// - error: 上下文被取消时返回ctx.Err()，同时返回已完成部分的报告
+//
+// 使用示例:
+// ```go
+// report, err := cwe.CheckRegistryURLs(context.Background(), registry, nil)
+//
+//	if err != nil {
+//	    log.Printf("检查被中断: %v", err)
+//	}
+//
+//	for _, dead := range report.Dead {
+//	    fmt.Printf("%s 的链接失效: %s (%v)\n", dead.ID, dead.URL, dead.Err)
+//	}
+//
+// ```
+func CheckRegistryURLs(ctx context.Context, registry *Registry, client *HTTPClient) (*URLHealthReport, error) {
+	report := &URLHealthReport{
+		Dead:    make([]URLCheckResult, 0),
+		Missing: make([]string, 0),
+	}
+
+	ids := make([]string, 0, len(registry.Entries))
+	for id := range registry.Entries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		entry := registry.Entries[id]
+		if entry.URL == "" {
+			report.Missing = append(report.Missing, id)
+			continue
+		}
+
+		result := CheckURL(ctx, client, id, entry.URL)
+		report.Checked++
+		if !result.Alive() {
+			report.Dead = append(report.Dead, result)
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,109 @@
+package cwe
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// setupURLCheckServer 创建一个模拟不同链接状态的测试服务器
+func setupURLCheckServer() *httptest.Server {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler.HandleFunc("/gone", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	handler.HandleFunc("/no-head", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	return httptest.NewServer(handler)
+}
+
+func newURLCheckTestClient() *HTTPClient {
+	client := NewHttpClient(
+		WithMaxRetries(1),
+		WithRetryInterval(10*time.Millisecond),
+	)
+	client.SetRateLimiter(NewHTTPRateLimiter(0))
+	return client
+}
+
+func TestCheckURL(t *testing.T) {
+	server := setupURLCheckServer()
+	defer server.Close()
+
+	client := newURLCheckTestClient()
+
+	if result := CheckURL(context.Background(), client, "CWE-1", server.URL+"/ok"); !result.Alive() {
+		t.Errorf("/ok 应该可访问，但得到状态码 %d, 错误 %v", result.StatusCode, result.Err)
+	}
+
+	result := CheckURL(context.Background(), client, "CWE-2", server.URL+"/gone")
+	if result.Alive() {
+		t.Error("/gone 应该不可访问")
+	}
+	if result.StatusCode != http.StatusNotFound {
+		t.Errorf("期望状态码404，但得到 %d", result.StatusCode)
+	}
+
+	// 不支持HEAD时应退回GET
+	if result := CheckURL(context.Background(), client, "CWE-3", server.URL+"/no-head"); !result.Alive() {
+		t.Errorf("/no-head 应通过GET检查成功，但得到状态码 %d", result.StatusCode)
+	}
+}
+
+func TestCheckRegistryURLs(t *testing.T) {
+	server := setupURLCheckServer()
+	defer server.Close()
+
+	registry := NewRegistry()
+	alive := NewCWE("CWE-79", "XSS")
+	alive.URL = server.URL + "/ok"
+	dead := NewCWE("CWE-89", "SQL注入")
+	dead.URL = server.URL + "/gone"
+	noURL := NewCWE("CWE-1000", "研究视图")
+	registry.Register(alive)
+	registry.Register(dead)
+	registry.Register(noURL)
+
+	report, err := CheckRegistryURLs(context.Background(), registry, newURLCheckTestClient())
+	if err != nil {
+		t.Fatalf("CheckRegistryURLs失败: %v", err)
+	}
+
+	if report.Checked != 2 {
+		t.Errorf("期望检查2个URL，但检查了 %d 个", report.Checked)
+	}
+	if len(report.Dead) != 1 || report.Dead[0].ID != "CWE-89" {
+		t.Errorf("期望CWE-89为失效链接，但得到 %v", report.Dead)
+	}
+	if len(report.Missing) != 1 || report.Missing[0] != "CWE-1000" {
+		t.Errorf("期望CWE-1000缺失URL，但得到 %v", report.Missing)
+	}
+}
+
+func TestCheckRegistryURLsCanceled(t *testing.T) {
+	registry := NewRegistry()
+	entry := NewCWE("CWE-79", "XSS")
+	entry.URL = "http://127.0.0.1:0/unreachable"
+	registry.Register(entry)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	report, err := CheckRegistryURLs(ctx, registry, newURLCheckTestClient())
+	if err == nil {
+		t.Error("上下文取消后应返回错误")
+	}
+	if report == nil || report.Checked != 0 {
+		t.Errorf("取消后不应再检查任何URL，但得到 %v", report)
+	}
+}
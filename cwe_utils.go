@@ -132,3 +132,41 @@ func ParseCWEID(id string) (string, error) {
 	// 上述模式都不匹配，则返回错误
 	return "", errors.New("无法解析CWE ID")
 }
+
+// cweReferencePattern 匹配文本中的CWE引用，如"CWE-79"、"cwe 79"、"CWE_79"、"CWE:79"
+// 也接受排版软件常用的Unicode连字符和破折号(U+2010至U+2015)
+var cweReferencePattern = regexp.MustCompile(`(?i)\bCWE\s*[-_:\x{2010}-\x{2015}]?\s*(\d+)\b`)
+
+// ExtractIDs 从任意文本中提取所有CWE引用
+//
+// 方法功能:
+// 在安全公告、提交信息等自由文本中查找CWE引用，将其规范化为"CWE-数字"格式并去重。
+// 结果按在文本中首次出现的顺序排列。只有带"CWE"前缀的引用会被识别，
+// 孤立的数字不会被当作CWE ID；编号为0的引用视为无效并被忽略。
+//
+// 参数:
+// - text: string - 要扫描的文本
+//
+// 返回值:
+// - []string: 规范化后的CWE ID列表，没有找到引用时返回空切片
+//
+// 使用示例:
+// ```go
+// ids := cwe.ExtractIDs("Fix XSS (cwe-79) and SQLi, see CWE-089 and CWE 79.")
+// fmt.Println(ids) // 输出: [CWE-79 CWE-89]
+// ```
+func ExtractIDs(text string) []string {
+	ids := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, match := range cweReferencePattern.FindAllStringSubmatch(text, -1) {
+		id, err := ParseCWEID(match[1])
+		if err != nil || id == "CWE-0" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	return ids
+}
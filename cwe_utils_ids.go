@@ -0,0 +1,175 @@
+package cwe
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxIDRangeSize 是ExpandIDRange允许展开的最大ID数，避免"CWE-1..CWE-99999999"之类的输入耗尽内存
+const MaxIDRangeSize = 10000
+
+// idRangeSeparator ID范围起止之间的分隔符
+const idRangeSeparator = ".."
+
+// IDError 表示ID列表中某个位置的输入无法解析
+type IDError struct {
+	// Index 输入在列表中的位置，从0开始
+	Index int
+
+	// Input 原始输入
+	Input string
+
+	// Err 失败原因
+	Err error
+}
+
+func (e *IDError) Error() string {
+	return fmt.Sprintf("第%d项%q无效: %v", e.Index+1, e.Input, e.Err)
+}
+
+func (e *IDError) Unwrap() error {
+	return e.Err
+}
+
+// IDListError 汇总ParseCWEIDs中所有无法解析的输入
+type IDListError struct {
+	// Errors 每个无效输入的错误，按位置排列
+	Errors []*IDError
+}
+
+func (e *IDListError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	return fmt.Sprintf("%d项ID无效，%v", len(e.Errors), e.Errors[0])
+}
+
+// ParseCWEIDs 批量解析CWE ID
+//
+// 方法功能:
+// 对每一项调用ParseCWEID，包含".."的项作为范围通过ExpandIDRange展开(如"CWE-79..CWE-81")。
+// 结果按输入顺序排列并去重。某些项无效时仍会返回其余项的解析结果，
+// 同时返回*IDListError，其中记录了每个无效项的位置和原因，便于命令行和配置文件给出准确的错误提示。
+//
+// 参数:
+// - inputs: []string - 要解析的ID或ID范围
+//
+// 返回值:
+// - []string: 规范化为"CWE-数字"格式的ID
+// - error: 有无效项时返回*IDListError
+//
+// 使用示例:
+// ```go
+// ids, err := cwe.ParseCWEIDs([]string{"79", "cwe-89", "CWE-120..CWE-122", "xss"})
+// fmt.Println(ids) // 输出: [CWE-79 CWE-89 CWE-120 CWE-121 CWE-122]
+// fmt.Println(err) // 输出: 第4项"xss"无效: 无法解析CWE ID
+// ```
+func ParseCWEIDs(inputs []string) ([]string, error) {
+	ids := make([]string, 0, len(inputs))
+	seen := make(map[string]bool, len(inputs))
+	var listErr *IDListError
+
+	for i, input := range inputs {
+		var parsed []string
+		var err error
+		if strings.Contains(input, idRangeSeparator) {
+			parsed, err = ExpandIDRange(input)
+		} else {
+			var id string
+			id, err = ParseCWEID(input)
+			parsed = []string{id}
+		}
+		if err != nil {
+			if listErr == nil {
+				listErr = &IDListError{}
+			}
+			listErr.Errors = append(listErr.Errors, &IDError{Index: i, Input: input, Err: err})
+			continue
+		}
+
+		for _, id := range parsed {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	if listErr != nil {
+		return ids, listErr
+	}
+	return ids, nil
+}
+
+// ParseIDRange 解析"起始..结束"格式的ID范围
+//
+// 起止ID支持ParseCWEID接受的所有格式，如"CWE-79..CWE-90"、"79..90"、"cwe-79 .. 90"。
+//
+// 返回值:
+// - string: 规范化的起始ID
+// - string: 规范化的结束ID
+// - error: 格式错误、ID无法解析或起始编号大于结束编号时返回错误
+func ParseIDRange(s string) (string, string, error) {
+	parts := strings.Split(s, idRangeSeparator)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("ID范围%q格式错误，应为\"起始..结束\"", s)
+	}
+
+	start, err := ParseCWEID(parts[0])
+	if err != nil {
+		return "", "", fmt.Errorf("ID范围的起始ID无效: %w", err)
+	}
+	end, err := ParseCWEID(parts[1])
+	if err != nil {
+		return "", "", fmt.Errorf("ID范围的结束ID无效: %w", err)
+	}
+	if CompareIDs(start, end) > 0 {
+		return "", "", fmt.Errorf("ID范围%q的起始ID大于结束ID", s)
+	}
+	return start, end, nil
+}
+
+// ExpandIDRange 将"起始..结束"格式的ID范围展开为ID列表，包括起止ID
+//
+// 返回值:
+// - []string: 按编号升序排列的ID
+// - error: ParseIDRange返回错误或范围超过MaxIDRangeSize时返回错误
+func ExpandIDRange(s string) ([]string, error) {
+	start, end, err := ParseIDRange(s)
+	if err != nil {
+		return nil, err
+	}
+	from, err := cweIDNumber(start)
+	if err != nil {
+		return nil, err
+	}
+	to, err := cweIDNumber(end)
+	if err != nil {
+		return nil, err
+	}
+	if to-from+1 > MaxIDRangeSize {
+		return nil, fmt.Errorf("ID范围%q包含%d个ID，超过上限%d", s, to-from+1, MaxIDRangeSize)
+	}
+
+	ids := make([]string, 0, to-from+1)
+	for n := from; n <= to; n++ {
+		ids = append(ids, FormatID(n))
+	}
+	return ids, nil
+}
+
+// CompareIDs 按编号比较两个CWE ID
+//
+// ID先按ParseCWEID规范化，因此"79"、"cwe-79"和"CWE-079"相等，"CWE-20"排在"CWE-100"之前。
+// 无法解析的ID排在可解析的ID之后，相互之间按字符串比较。
+//
+// 返回值:
+// - int: a小于b时为-1，相等时为0，大于时为1
+func CompareIDs(a, b string) int {
+	return compareCWEIDs(normalizeEntryID(a), normalizeEntryID(b))
+}
+
+// FormatID 将编号格式化为"CWE-数字"格式的ID，如FormatID(79)返回"CWE-79"
+func FormatID(n int) string {
+	return fmt.Sprintf("CWE-%d", n)
+}
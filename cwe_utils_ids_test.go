@@ -0,0 +1,90 @@
+package cwe
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseCWEIDs(t *testing.T) {
+	ids, err := ParseCWEIDs([]string{"79", "cwe-89", "CWE-120..CWE-122", "CWE-079", "xss", "90..80"})
+
+	if got := strings.Join(ids, ","); got != "CWE-79,CWE-89,CWE-120,CWE-121,CWE-122" {
+		t.Errorf("解析结果不正确: %s", got)
+	}
+
+	var listErr *IDListError
+	if !errors.As(err, &listErr) {
+		t.Fatalf("期望*IDListError，实际 %v", err)
+	}
+	if len(listErr.Errors) != 2 || listErr.Errors[0].Index != 4 || listErr.Errors[1].Index != 5 {
+		t.Fatalf("错误位置不正确: %v", listErr.Errors)
+	}
+	if listErr.Errors[0].Input != "xss" {
+		t.Errorf("应记录原始输入，实际 %q", listErr.Errors[0].Input)
+	}
+	if !strings.HasPrefix(err.Error(), "2项ID无效，第5项\"xss\"无效") {
+		t.Errorf("错误信息不正确: %v", err)
+	}
+
+	ids, err = ParseCWEIDs([]string{"CWE-1", " 2 "})
+	if err != nil || strings.Join(ids, ",") != "CWE-1,CWE-2" {
+		t.Errorf("ParseCWEIDs() = %v, %v", ids, err)
+	}
+}
+
+func TestParseIDRange(t *testing.T) {
+	tests := []struct {
+		input      string
+		start, end string
+		wantErr    bool
+	}{
+		{"CWE-79..CWE-90", "CWE-79", "CWE-90", false},
+		{"cwe-79 .. 90", "CWE-79", "CWE-90", false},
+		{"79..79", "CWE-79", "CWE-79", false},
+		{"90..79", "", "", true},
+		{"79..", "", "", true},
+		{"79..80..81", "", "", true},
+		{"79-90", "", "", true},
+	}
+
+	for _, tt := range tests {
+		start, end, err := ParseIDRange(tt.input)
+		if (err != nil) != tt.wantErr || start != tt.start || end != tt.end {
+			t.Errorf("ParseIDRange(%q) = %q, %q, %v", tt.input, start, end, err)
+		}
+	}
+}
+
+func TestExpandIDRange(t *testing.T) {
+	ids, err := ExpandIDRange("CWE-8..10")
+	if err != nil || strings.Join(ids, ",") != "CWE-8,CWE-9,CWE-10" {
+		t.Errorf("ExpandIDRange() = %v, %v", ids, err)
+	}
+
+	if _, err := ExpandIDRange("1..20000"); err == nil {
+		t.Error("超过MaxIDRangeSize的范围应返回错误")
+	}
+}
+
+func TestCompareIDs(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"CWE-20", "CWE-100", -1},
+		{"79", "cwe-079", 0},
+		{"CWE-200", "89", 1},
+		{"CWE-79", "invalid", -1},
+		{"abc", "abd", -1},
+	}
+	for _, tt := range tests {
+		if got := CompareIDs(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareIDs(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	if FormatID(79) != "CWE-79" {
+		t.Errorf("FormatID(79) = %s", FormatID(79))
+	}
+}
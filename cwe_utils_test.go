@@ -141,3 +141,37 @@ func TestParseCWEIDAdvanced(t *testing.T) {
 		}
 	}
 }
+
+// TestExtractIDs 测试从自由文本中提取CWE ID
+func TestExtractIDs(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{"空文本", "", []string{}},
+		{"没有引用", "Fixed a bug in parser 79", []string{}},
+		{"单个引用", "This is CWE-79.", []string{"CWE-79"}},
+		{"去重并保持顺序", "CWE-89, cwe-79 and CWE 89 again", []string{"CWE-89", "CWE-79"}},
+		{"前导零", "see CWE-0079", []string{"CWE-79"}},
+		{"多种分隔符", "CWE_20 CWE:22 CWE‑78 CWE787", []string{"CWE-20", "CWE-22", "CWE-78", "CWE-787"}},
+		{"括号和标点", "(CWE-352); [CWE-434]", []string{"CWE-352", "CWE-434"}},
+		{"忽略无效编号", "CWE-0 and CWE-Other", []string{}},
+		{"忽略单词内部", "XCWE-79 CWE-79abc", []string{}},
+		{"提交信息", "fix: sanitize input\n\nRefs: CWE-116, NVD-CWE-noinfo, CWE-116", []string{"CWE-116"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := ExtractIDs(tt.text)
+			if len(ids) != len(tt.expected) {
+				t.Fatalf("ExtractIDs(%q) = %v, 期望 %v", tt.text, ids, tt.expected)
+			}
+			for i := range ids {
+				if ids[i] != tt.expected[i] {
+					t.Errorf("ExtractIDs(%q)[%d] = %s, 期望 %s", tt.text, i, ids[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package cwe
+
+// Walk 以深度优先(先序)方式遍历以root为根的CWE树
+//
+// 方法功能:
+// 对每个节点调用visitor，子节点按Children中的顺序访问。
+// visitor返回false时跳过该节点的子树，遍历继续处理其他分支。
+// 每个节点最多访问一次，因此多父节点的条目只在第一次到达时访问，树中存在循环引用也不会死循环。
+//
+// 参数:
+// - root: *CWE - 遍历的起始节点，为nil时不做任何操作
+// - visitor: func(*CWE) bool - 节点访问函数，返回false时不再展开该节点
+//
+// 使用示例:
+// ```go
+// // 打印输入验证类别下的所有条目，跳过已弃用条目的子树
+//
+//	cwe.Walk(registry.Entries["CWE-20"], func(entry *cwe.CWE) bool {
+//	    if entry.Status == "Deprecated" {
+//	        return false
+//	    }
+//	    fmt.Println(entry.ID, entry.Name)
+//	    return true
+//	})
+//
+// ```
+func Walk(root *CWE, visitor func(*CWE) bool) {
+	if root == nil {
+		return
+	}
+	walkDFS(root, pruning(visitor), make(map[*CWE]bool))
+}
+
+// WalkBFS 以广度优先方式遍历以root为根的CWE树
+//
+// 方法功能:
+// 与Walk相同，但按层次访问节点：先访问root，再访问所有深度为1的节点，依此类推。
+// visitor返回false时不再将该节点的子节点加入队列。
+//
+// 参数:
+// - root: *CWE - 遍历的起始节点，为nil时不做任何操作
+// - visitor: func(*CWE) bool - 节点访问函数，返回false时不再展开该节点
+func WalkBFS(root *CWE, visitor func(*CWE) bool) {
+	if root == nil {
+		return
+	}
+	walkBFS([]*CWE{root}, pruning(visitor), make(map[*CWE]bool))
+}
+
+// Walk 以深度优先方式遍历以当前节点为根的子树，规则与包级函数Walk相同
+func (c *CWE) Walk(visitor func(*CWE) bool) {
+	Walk(c, visitor)
+}
+
+// WalkBFS 以广度优先方式遍历以当前节点为根的子树，规则与包级函数WalkBFS相同
+func (c *CWE) WalkBFS(visitor func(*CWE) bool) {
+	WalkBFS(c, visitor)
+}
+
+// Walk 以深度优先方式遍历注册表中的树
+//
+// 设置了Root时从Root开始遍历；否则依次从每个没有父节点的条目开始，按CWE编号顺序遍历。
+// 不能从这些起点到达的条目不会被访问。visitor的规则与包级函数Walk相同。
+func (r *Registry) Walk(visitor func(*CWE) bool) {
+	r.walkDFS(pruning(visitor))
+}
+
+// WalkBFS 以广度优先方式遍历注册表中的树，起点与Registry.Walk相同
+// 没有Root时多个起点作为同一层处理
+func (r *Registry) WalkBFS(visitor func(*CWE) bool) {
+	walkBFS(r.walkRoots(), pruning(visitor), make(map[*CWE]bool))
+}
+
+// walkDFS 从每个起点深度优先遍历注册表
+func (r *Registry) walkDFS(visit walkVisitor) {
+	visited := make(map[*CWE]bool)
+	for _, root := range r.walkRoots() {
+		if !visited[root] && !walkDFS(root, visit, visited) {
+			return
+		}
+	}
+}
+
+// walkRoots 返回遍历注册表时的起点
+func (r *Registry) walkRoots() []*CWE {
+	if r.Root != nil {
+		return []*CWE{r.Root}
+	}
+	roots := make([]*CWE, 0)
+	for _, entry := range r.Snapshot() {
+		if entry.Parent == nil {
+			roots = append(roots, entry)
+		}
+	}
+	return roots
+}
+
+// walkVisitor 是内部遍历使用的访问函数
+// descend为false时不展开当前节点，cont为false时立即结束整个遍历
+type walkVisitor func(node *CWE) (descend, cont bool)
+
+// pruning 将公开的visitor转换为walkVisitor，visitor返回false时只跳过子树
+func pruning(visitor func(*CWE) bool) walkVisitor {
+	return func(node *CWE) (bool, bool) {
+		return visitor(node), true
+	}
+}
+
+// walkDFS 从node开始深度优先遍历，返回false表示遍历已被终止
+func walkDFS(node *CWE, visit walkVisitor, visited map[*CWE]bool) bool {
+	visited[node] = true
+	descend, cont := visit(node)
+	if !cont {
+		return false
+	}
+	if !descend {
+		return true
+	}
+	for _, child := range node.Children {
+		if !visited[child] && !walkDFS(child, visit, visited) {
+			return false
+		}
+	}
+	return true
+}
+
+// walkBFS 从roots开始广度优先遍历
+func walkBFS(roots []*CWE, visit walkVisitor, visited map[*CWE]bool) {
+	queue := make([]*CWE, 0, len(roots))
+	for _, root := range roots {
+		if !visited[root] {
+			visited[root] = true
+			queue = append(queue, root)
+		}
+	}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		descend, cont := visit(node)
+		if !cont {
+			return
+		}
+		if !descend {
+			continue
+		}
+		for _, child := range node.Children {
+			if !visited[child] {
+				visited[child] = true
+				queue = append(queue, child)
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+//go:build go1.23
+
+package cwe
+
+import "iter"
+
+// All 返回以深度优先(先序)方式遍历子树的迭代器，顺序与Walk相同
+//
+// 在range循环中break会立即结束遍历，不会访问剩余的节点。
+//
+// 使用示例:
+// ```go
+// inputValidation := registry.Entries["CWE-20"]
+//
+//	for entry := range inputValidation.All() {
+//	    if entry.ID == "CWE-79" {
+//	        break
+//	    }
+//	}
+//
+// ```
+func (c *CWE) All() iter.Seq[*CWE] {
+	return func(yield func(*CWE) bool) {
+		if c == nil {
+			return
+		}
+		walkDFS(c, yielding(yield), make(map[*CWE]bool))
+	}
+}
+
+// All 返回以深度优先方式遍历注册表中的树的迭代器，起点和顺序与Registry.Walk相同
+func (r *Registry) All() iter.Seq[*CWE] {
+	return func(yield func(*CWE) bool) {
+		r.walkDFS(yielding(yield))
+	}
+}
+
+// yielding 将迭代器的yield转换为walkVisitor，yield返回false时结束整个遍历
+func yielding(yield func(*CWE) bool) walkVisitor {
+	return func(node *CWE) (bool, bool) {
+		cont := yield(node)
+		return cont, cont
+	}
+}
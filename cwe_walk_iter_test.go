@@ -0,0 +1,37 @@
+//go:build go1.23
+
+package cwe
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCWEAll(t *testing.T) {
+	registry := newQueryTestRegistry()
+
+	ids := make([]string, 0)
+	for entry := range registry.Entries["CWE-20"].All() {
+		ids = append(ids, entry.ID)
+	}
+	if got := strings.Join(ids, ","); got != "CWE-20,CWE-79,CWE-89" {
+		t.Errorf("All()顺序不正确: %s", got)
+	}
+
+	// break立即结束整个遍历
+	ids = ids[:0]
+	for entry := range registry.All() {
+		ids = append(ids, entry.ID)
+		if entry.ID == "CWE-79" {
+			break
+		}
+	}
+	if got := strings.Join(ids, ","); got != "CWE-1000,CWE-20,CWE-79" {
+		t.Errorf("break后不应继续遍历: %s", got)
+	}
+
+	var nilEntry *CWE
+	for range nilEntry.All() {
+		t.Error("nil节点不应产生任何元素")
+	}
+}
@@ -0,0 +1,68 @@
+package cwe
+
+import (
+	"strings"
+	"testing"
+)
+
+func collectWalk(walk func(func(*CWE) bool), prune string) string {
+	ids := make([]string, 0)
+	walk(func(entry *CWE) bool {
+		ids = append(ids, entry.ID)
+		return entry.ID != prune
+	})
+	return strings.Join(ids, ",")
+}
+
+func TestWalk(t *testing.T) {
+	registry := newQueryTestRegistry()
+	root := registry.Root
+
+	if got := collectWalk(root.Walk, ""); got != "CWE-1000,CWE-20,CWE-79,CWE-89,CWE-287,CWE-306" {
+		t.Errorf("深度优先顺序不正确: %s", got)
+	}
+	if got := collectWalk(root.WalkBFS, ""); got != "CWE-1000,CWE-20,CWE-287,CWE-79,CWE-89,CWE-306" {
+		t.Errorf("广度优先顺序不正确: %s", got)
+	}
+
+	// visitor返回false时跳过子树但继续其他分支
+	if got := collectWalk(root.Walk, "CWE-20"); got != "CWE-1000,CWE-20,CWE-287,CWE-306" {
+		t.Errorf("深度优先剪枝不正确: %s", got)
+	}
+	if got := collectWalk(root.WalkBFS, "CWE-20"); got != "CWE-1000,CWE-20,CWE-287,CWE-306" {
+		t.Errorf("广度优先剪枝不正确: %s", got)
+	}
+
+	Walk(nil, func(*CWE) bool {
+		t.Error("nil根节点不应调用visitor")
+		return true
+	})
+}
+
+func TestWalkVisitsSharedNodesOnce(t *testing.T) {
+	registry := newQueryTestRegistry()
+	// CWE-79同时是CWE-287的子节点，并构造一个环
+	registry.Entries["CWE-287"].Children = append(registry.Entries["CWE-287"].Children, registry.Entries["CWE-79"])
+	registry.Entries["CWE-79"].Children = append(registry.Entries["CWE-79"].Children, registry.Root)
+
+	if got := collectWalk(registry.Root.Walk, ""); got != "CWE-1000,CWE-20,CWE-79,CWE-89,CWE-287,CWE-306" {
+		t.Errorf("共享节点和环应只访问一次: %s", got)
+	}
+	if got := collectWalk(registry.Root.WalkBFS, ""); got != "CWE-1000,CWE-20,CWE-287,CWE-79,CWE-89,CWE-306" {
+		t.Errorf("共享节点和环应只访问一次: %s", got)
+	}
+}
+
+func TestRegistryWalkWithoutRoot(t *testing.T) {
+	registry := newQueryTestRegistry()
+	registry.Root = nil
+	orphan := NewCWE("CWE-1", "Orphan")
+	registry.Register(orphan)
+
+	if got := collectWalk(registry.Walk, ""); got != "CWE-1,CWE-1000,CWE-20,CWE-79,CWE-89,CWE-287,CWE-306" {
+		t.Errorf("没有Root时应从每个顶层条目开始遍历: %s", got)
+	}
+	if got := collectWalk(registry.WalkBFS, "CWE-287"); got != "CWE-1,CWE-1000,CWE-20,CWE-287,CWE-79,CWE-89" {
+		t.Errorf("没有Root时广度优先遍历不正确: %s", got)
+	}
+}
@@ -0,0 +1,119 @@
+package cwepb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Client 是CWEService的gRPC客户端
+//
+// Client使用net/http发送请求，既可以访问NewHandler提供的服务，也可以访问其他语言实现的标准gRPC服务；
+// 访问标准gRPC服务时httpClient必须支持HTTP/2(如使用TLS的http.Client)。
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient 创建客户端
+//
+// 参数:
+// - baseURL: string - 服务地址，如"https://localhost:8443"
+// - httpClient: *http.Client - 发送请求的HTTP客户端，为nil时使用http.DefaultClient
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), httpClient: httpClient}
+}
+
+// GetCWE 获取单个条目
+func (c *Client) GetCWE(ctx context.Context, req *GetCWERequest) (*CWE, error) {
+	resp := &CWE{}
+	if err := c.invoke(ctx, "GetCWE", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Search 分页搜索条目
+func (c *Client) Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
+	resp := &SearchResponse{}
+	if err := c.invoke(ctx, "Search", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetTree 获取子树
+func (c *Client) GetTree(ctx context.Context, req *GetTreeRequest) (*TreeNode, error) {
+	resp := &TreeNode{}
+	if err := c.invoke(ctx, "GetTree", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// invoke 发送一元调用，失败时返回*Status或网络错误
+func (c *Client) invoke(ctx context.Context, method string, req, resp Message) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		c.baseURL+"/"+ServiceName+"/"+method, bytes.NewReader(frame(req.Marshal())))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/grpc+proto")
+	httpReq.Header.Set("TE", "trailers")
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("调用%s失败: %w", method, err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return &Status{Code: httpStatusCode(httpResp.StatusCode), Message: fmt.Sprintf("HTTP状态码%d", httpResp.StatusCode)}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(httpResp.Body, MaxMessageSize+5+1))
+	if err != nil {
+		return fmt.Errorf("读取%s的响应失败: %w", method, err)
+	}
+
+	// 只有状态没有消息时(Trailers-Only)状态在响应头中
+	statusHeader := httpResp.Trailer
+	if httpResp.Header.Get("Grpc-Status") != "" {
+		statusHeader = httpResp.Header
+	}
+	code, err := strconv.Atoi(statusHeader.Get("Grpc-Status"))
+	if err != nil {
+		return &Status{Code: CodeInternal, Message: "响应缺少有效的grpc-status"}
+	}
+	if Code(code) != CodeOK {
+		return &Status{Code: Code(code), Message: decodeStatusMessage(statusHeader.Get("Grpc-Message"))}
+	}
+
+	data, err := readFrame(bytes.NewReader(body))
+	if err != nil {
+		return &Status{Code: CodeInternal, Message: fmt.Sprintf("读取响应消息失败: %v", err)}
+	}
+	if err := resp.Unmarshal(data); err != nil {
+		return &Status{Code: CodeInternal, Message: fmt.Sprintf("解码响应失败: %v", err)}
+	}
+	return nil
+}
+
+// httpStatusCode 按gRPC规范将非200的HTTP状态码映射为gRPC状态码
+func httpStatusCode(status int) Code {
+	switch status {
+	case http.StatusNotFound:
+		return CodeUnimplemented
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return CodeUnavailable
+	default:
+		return CodeUnknown
+	}
+}
@@ -0,0 +1,146 @@
+package cwepb
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/scagogogo/cwe"
+)
+
+// FromCWE 将条目转换为消息，父子关系转换为ID引用
+func FromCWE(entry *cwe.CWE) *CWE {
+	if entry == nil {
+		return nil
+	}
+
+	m := &CWE{
+		Id:             entry.ID,
+		Name:           entry.Name,
+		Description:    entry.Description,
+		Url:            entry.URL,
+		Severity:       entry.Severity,
+		Status:         entry.Status,
+		Abstraction:    entry.Abstraction,
+		Language:       entry.Language,
+		Version:        entry.Version,
+		Mitigations:    append([]string(nil), entry.Mitigations...),
+		Examples:       append([]string(nil), entry.Examples...),
+		AlternateTerms: append([]string(nil), entry.AlternateTerms...),
+	}
+	if entry.Parent != nil {
+		m.ParentId = entry.Parent.ID
+	}
+	for _, child := range entry.Children {
+		m.ChildIds = append(m.ChildIds, child.ID)
+	}
+	for _, relation := range entry.Relations {
+		m.Relations = append(m.Relations, &Relation{
+			Nature:  relation.Nature,
+			CweId:   relation.CweID,
+			ViewId:  relation.ViewID,
+			Ordinal: relation.Ordinal,
+		})
+	}
+	return m
+}
+
+// ToCWE 将消息转换为条目，不建立父子关系
+// ParentId和ChildIds只有在ToRegistry中才会解析为指针
+func (m *CWE) ToCWE() *cwe.CWE {
+	entry := cwe.NewCWE(m.Id, m.Name)
+	entry.Description = m.Description
+	entry.URL = m.Url
+	entry.Severity = m.Severity
+	entry.Status = m.Status
+	entry.Abstraction = m.Abstraction
+	entry.Language = m.Language
+	entry.Version = m.Version
+	entry.Mitigations = append([]string(nil), m.Mitigations...)
+	entry.Examples = append([]string(nil), m.Examples...)
+	entry.AlternateTerms = append([]string(nil), m.AlternateTerms...)
+	for _, relation := range m.Relations {
+		entry.Relations = append(entry.Relations, cwe.CWERelation{
+			Nature:  relation.Nature,
+			CweID:   relation.CweId,
+			ViewID:  relation.ViewId,
+			Ordinal: relation.Ordinal,
+		})
+	}
+	return entry
+}
+
+// FromRegistry 将注册表转换为消息，条目按CWE编号排序
+func FromRegistry(registry *cwe.Registry) *Registry {
+	snapshot := cwe.NewRegistrySnapshot(registry)
+
+	m := &Registry{RootId: snapshot.RootID}
+	for _, entry := range snapshot.Entries {
+		m.Entries = append(m.Entries, FromCWE(registry.Entries[entry.ID]))
+	}
+	sort.SliceStable(m.Entries, func(i, j int) bool {
+		return cwe.CompareIDs(m.Entries[i].Id, m.Entries[j].Id) < 0
+	})
+	return m
+}
+
+// ToRegistry 将消息转换为注册表，按ChildIds重建父子关系
+//
+// 返回值:
+// - *cwe.Registry: 注册表，Root为RootId对应的条目
+// - error: 条目ID重复、RootId或ChildIds引用了不存在的条目时返回错误
+func (m *Registry) ToRegistry() (*cwe.Registry, error) {
+	registry := cwe.NewRegistry()
+	for _, entry := range m.Entries {
+		if _, err := registry.GetByID(entry.Id); err == nil {
+			return nil, fmt.Errorf("条目%s重复", entry.Id)
+		}
+		if err := registry.Register(entry.ToCWE()); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, entry := range m.Entries {
+		parent := registry.Entries[entry.Id]
+		for _, childID := range entry.ChildIds {
+			child, exists := registry.Entries[childID]
+			if !exists {
+				return nil, fmt.Errorf("%s的子节点%s不存在", entry.Id, childID)
+			}
+			parent.AddChild(child)
+		}
+	}
+
+	if m.RootId != "" {
+		root, err := registry.GetByID(m.RootId)
+		if err != nil {
+			return nil, fmt.Errorf("根节点%s不存在", m.RootId)
+		}
+		registry.Root = root
+	}
+	return registry, nil
+}
+
+// FromTreeNode 将树节点转换为消息
+func FromTreeNode(node *cwe.TreeNode) *TreeNode {
+	if node == nil {
+		return nil
+	}
+	m := &TreeNode{Cwe: FromCWE(node.CWE)}
+	for _, child := range node.Children {
+		m.Children = append(m.Children, FromTreeNode(child))
+	}
+	return m
+}
+
+// ToTreeNode 将消息转换为树节点，每个节点包装一个新的条目
+func (m *TreeNode) ToTreeNode() *cwe.TreeNode {
+	var entry *cwe.CWE
+	if m.Cwe != nil {
+		entry = m.Cwe.ToCWE()
+	}
+	node := cwe.NewTreeNode(entry)
+	for _, child := range m.Children {
+		node.AddChild(child.ToTreeNode())
+	}
+	return node
+}
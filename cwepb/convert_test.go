@@ -0,0 +1,96 @@
+package cwepb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/scagogogo/cwe"
+)
+
+// newTestRegistry 创建CWE-1000 -> {CWE-20 -> {CWE-79, CWE-89}, CWE-74}的注册表
+func newTestRegistry() *cwe.Registry {
+	registry := cwe.NewRegistry()
+	for _, entry := range []*cwe.CWE{
+		cwe.NewCWE("CWE-1000", "Research Concepts"),
+		cwe.NewCWE("CWE-20", "Improper Input Validation"),
+		cwe.NewCWE("CWE-74", "Injection"),
+		cwe.NewCWE("CWE-79", "Cross-site Scripting"),
+		cwe.NewCWE("CWE-89", "SQL Injection"),
+	} {
+		registry.Register(entry)
+	}
+	xss := registry.Entries["CWE-79"]
+	xss.Severity = "High"
+	xss.Mitigations = []string{"对输出进行编码"}
+	xss.Relations = []cwe.CWERelation{{Nature: "ChildOf", CweID: "CWE-20", ViewID: "CWE-1000"}}
+
+	registry.AddChild("CWE-1000", "CWE-20")
+	registry.AddChild("CWE-1000", "CWE-74")
+	registry.AddChild("CWE-20", "CWE-79")
+	registry.AddChild("CWE-20", "CWE-89")
+	registry.Root = registry.Entries["CWE-1000"]
+	return registry
+}
+
+func TestRegistryConversion(t *testing.T) {
+	registry := newTestRegistry()
+
+	m := FromRegistry(registry)
+	if m.RootId != "CWE-1000" || len(m.Entries) != 5 || m.Entries[0].Id != "CWE-20" {
+		t.Fatalf("消息不正确: root=%s entries=%d", m.RootId, len(m.Entries))
+	}
+
+	var decoded Registry
+	if err := decoded.Unmarshal(m.Marshal()); err != nil {
+		t.Fatalf("解码失败: %v", err)
+	}
+	restored, err := decoded.ToRegistry()
+	if err != nil {
+		t.Fatalf("ToRegistry失败: %v", err)
+	}
+
+	if restored.Root == nil || restored.Root.ID != "CWE-1000" {
+		t.Fatalf("根节点不正确: %v", restored.Root)
+	}
+	xss := restored.Entries["CWE-79"]
+	if xss.Parent == nil || xss.Parent.ID != "CWE-20" {
+		t.Errorf("CWE-79的父节点应为CWE-20")
+	}
+	if xss.Severity != "High" || !reflect.DeepEqual(xss.Mitigations, []string{"对输出进行编码"}) ||
+		!reflect.DeepEqual(xss.Relations, registry.Entries["CWE-79"].Relations) {
+		t.Errorf("CWE-79的字段不正确: %+v", xss)
+	}
+	if len(restored.Entries["CWE-20"].Children) != 2 {
+		t.Errorf("CWE-20应有2个子节点，实际为%d", len(restored.Entries["CWE-20"].Children))
+	}
+
+	for name, bad := range map[string]*Registry{
+		"条目重复":   {Entries: []*CWE{{Id: "CWE-1"}, {Id: "CWE-1"}}},
+		"子节点不存在": {Entries: []*CWE{{Id: "CWE-1", ChildIds: []string{"CWE-2"}}}},
+		"根节点不存在": {RootId: "CWE-2", Entries: []*CWE{{Id: "CWE-1"}}},
+	} {
+		if _, err := bad.ToRegistry(); err == nil {
+			t.Errorf("%s时应返回错误", name)
+		}
+	}
+}
+
+func TestTreeNodeConversion(t *testing.T) {
+	registry := newTestRegistry()
+
+	tree := cwe.NewTreeNode(registry.Entries["CWE-20"])
+	tree.AddChild(cwe.NewTreeNode(registry.Entries["CWE-79"]))
+
+	m := FromTreeNode(tree)
+	if m.Cwe.Id != "CWE-20" || len(m.Children) != 1 || m.Children[0].Cwe.Id != "CWE-79" {
+		t.Fatalf("消息不正确: %+v", m)
+	}
+
+	restored := m.ToTreeNode()
+	if restored.CWE.ID != "CWE-20" || len(restored.Children) != 1 || restored.Children[0].CWE.Name != "Cross-site Scripting" {
+		t.Errorf("树节点不正确: %+v", restored)
+	}
+	if FromTreeNode(nil) != nil || FromCWE(nil) != nil {
+		t.Error("nil应转换为nil")
+	}
+}
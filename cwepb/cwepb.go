@@ -0,0 +1,437 @@
+// Package cwepb 提供CWE数据的protobuf消息和gRPC服务
+//
+// 消息和服务定义见同目录下的cwe.proto。为了不引入protobuf和gRPC依赖，本包的Go类型和编解码是手工编写的，
+// 二进制格式与protoc生成的代码完全兼容；服务端NewHandler和客户端Client实现了gRPC的一元调用协议，
+// 可以与其他语言根据cwe.proto生成的gRPC客户端和服务端互通。
+//
+// gRPC基于HTTP/2，将NewHandler挂载到启用了HTTP/2的服务器(TLS，或通过h2c)即可被标准gRPC客户端访问；
+// 本包的Client在HTTP/1.1上同样可以工作。
+//
+// 使用示例:
+//
+//	registry, _ := cwe.NewOfflineRegistry()
+//	srv := &http.Server{Addr: ":8443", Handler: cwepb.NewHandler(cwepb.NewRegistryService(registry))}
+//	log.Fatal(srv.ListenAndServeTLS("server.crt", "server.key"))
+//
+//	// 客户端
+//	client := cwepb.NewClient("https://localhost:8443", http.DefaultClient)
+//	entry, err := client.GetCWE(ctx, &cwepb.GetCWERequest{Id: "CWE-79"})
+package cwepb
+
+// Message 是本包所有protobuf消息实现的接口
+type Message interface {
+	// Marshal 将消息编码为protobuf二进制格式
+	Marshal() []byte
+
+	// Unmarshal 从protobuf二进制格式解码消息，未知字段会被忽略
+	Unmarshal(data []byte) error
+}
+
+// Relation 与其他CWE的类型化关系
+type Relation struct {
+	Nature  string
+	CweId   string
+	ViewId  string
+	Ordinal string
+}
+
+// CWE 一个CWE条目，父子关系以ID引用表示
+type CWE struct {
+	Id             string
+	Name           string
+	Description    string
+	Url            string
+	Severity       string
+	Status         string
+	Language       string
+	Mitigations    []string
+	Examples       []string
+	ParentId       string
+	ChildIds       []string
+	Relations      []*Relation
+	Version        string
+	AlternateTerms []string
+	Abstraction    string
+}
+
+// Registry 整个注册表
+type Registry struct {
+	RootId  string
+	Entries []*CWE
+}
+
+// TreeNode 树中的一个节点，子节点内嵌
+type TreeNode struct {
+	Cwe      *CWE
+	Children []*TreeNode
+}
+
+// GetCWERequest 是GetCWE的请求
+type GetCWERequest struct {
+	Id string
+}
+
+// SearchRequest 是Search的请求，字段含义与cwe.SearchQuery相同
+type SearchRequest struct {
+	Query  string
+	Limit  int32
+	Offset int32
+	Cursor string
+}
+
+// SearchResponse 是Search的响应
+type SearchResponse struct {
+	Results    []*CWE
+	Total      int32
+	NextCursor string
+}
+
+// GetTreeRequest 是GetTree的请求
+type GetTreeRequest struct {
+	// RootId 子树的根节点，为空时使用注册表的根节点
+	RootId string
+
+	// MaxDepth 最大深度，根节点深度为0；0表示不限制
+	MaxDepth int32
+}
+
+// Marshal 将消息编码为protobuf二进制格式
+func (m *Relation) Marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, m.Nature)
+	b = appendString(b, 2, m.CweId)
+	b = appendString(b, 3, m.ViewId)
+	b = appendString(b, 4, m.Ordinal)
+	return b
+}
+
+// Unmarshal 从protobuf二进制格式解码消息
+func (m *Relation) Unmarshal(data []byte) error {
+	*m = Relation{}
+	r := &fieldReader{data: data}
+	for {
+		ok, err := r.next()
+		if !ok || err != nil {
+			return err
+		}
+		var target *string
+		switch r.field {
+		case 1:
+			target = &m.Nature
+		case 2:
+			target = &m.CweId
+		case 3:
+			target = &m.ViewId
+		case 4:
+			target = &m.Ordinal
+		default:
+			continue
+		}
+		if *target, err = r.string(); err != nil {
+			return err
+		}
+	}
+}
+
+// Marshal 将消息编码为protobuf二进制格式
+func (m *CWE) Marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, m.Id)
+	b = appendString(b, 2, m.Name)
+	b = appendString(b, 3, m.Description)
+	b = appendString(b, 4, m.Url)
+	b = appendString(b, 5, m.Severity)
+	b = appendString(b, 6, m.Status)
+	b = appendString(b, 7, m.Language)
+	b = appendStrings(b, 8, m.Mitigations)
+	b = appendStrings(b, 9, m.Examples)
+	b = appendString(b, 10, m.ParentId)
+	b = appendStrings(b, 11, m.ChildIds)
+	for _, relation := range m.Relations {
+		b = appendMessage(b, 12, relation.Marshal())
+	}
+	b = appendString(b, 13, m.Version)
+	b = appendStrings(b, 14, m.AlternateTerms)
+	b = appendString(b, 15, m.Abstraction)
+	return b
+}
+
+// Unmarshal 从protobuf二进制格式解码消息
+func (m *CWE) Unmarshal(data []byte) error {
+	*m = CWE{}
+	r := &fieldReader{data: data}
+	for {
+		ok, err := r.next()
+		if !ok || err != nil {
+			return err
+		}
+
+		var target *string
+		var list *[]string
+		switch r.field {
+		case 1:
+			target = &m.Id
+		case 2:
+			target = &m.Name
+		case 3:
+			target = &m.Description
+		case 4:
+			target = &m.Url
+		case 5:
+			target = &m.Severity
+		case 6:
+			target = &m.Status
+		case 7:
+			target = &m.Language
+		case 8:
+			list = &m.Mitigations
+		case 9:
+			list = &m.Examples
+		case 10:
+			target = &m.ParentId
+		case 11:
+			list = &m.ChildIds
+		case 12:
+			data, err := r.message()
+			if err != nil {
+				return err
+			}
+			relation := &Relation{}
+			if err := relation.Unmarshal(data); err != nil {
+				return err
+			}
+			m.Relations = append(m.Relations, relation)
+			continue
+		case 13:
+			target = &m.Version
+		case 14:
+			list = &m.AlternateTerms
+		case 15:
+			target = &m.Abstraction
+		default:
+			continue
+		}
+
+		value, err := r.string()
+		if err != nil {
+			return err
+		}
+		if target != nil {
+			*target = value
+		} else {
+			*list = append(*list, value)
+		}
+	}
+}
+
+// Marshal 将消息编码为protobuf二进制格式
+func (m *Registry) Marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, m.RootId)
+	for _, entry := range m.Entries {
+		b = appendMessage(b, 2, entry.Marshal())
+	}
+	return b
+}
+
+// Unmarshal 从protobuf二进制格式解码消息
+func (m *Registry) Unmarshal(data []byte) error {
+	*m = Registry{}
+	r := &fieldReader{data: data}
+	for {
+		ok, err := r.next()
+		if !ok || err != nil {
+			return err
+		}
+		switch r.field {
+		case 1:
+			if m.RootId, err = r.string(); err != nil {
+				return err
+			}
+		case 2:
+			entry, err := unmarshalCWE(r)
+			if err != nil {
+				return err
+			}
+			m.Entries = append(m.Entries, entry)
+		}
+	}
+}
+
+// Marshal 将消息编码为protobuf二进制格式
+func (m *TreeNode) Marshal() []byte {
+	var b []byte
+	if m.Cwe != nil {
+		b = appendMessage(b, 1, m.Cwe.Marshal())
+	}
+	for _, child := range m.Children {
+		b = appendMessage(b, 2, child.Marshal())
+	}
+	return b
+}
+
+// Unmarshal 从protobuf二进制格式解码消息
+func (m *TreeNode) Unmarshal(data []byte) error {
+	*m = TreeNode{}
+	r := &fieldReader{data: data}
+	for {
+		ok, err := r.next()
+		if !ok || err != nil {
+			return err
+		}
+		switch r.field {
+		case 1:
+			if m.Cwe, err = unmarshalCWE(r); err != nil {
+				return err
+			}
+		case 2:
+			data, err := r.message()
+			if err != nil {
+				return err
+			}
+			child := &TreeNode{}
+			if err := child.Unmarshal(data); err != nil {
+				return err
+			}
+			m.Children = append(m.Children, child)
+		}
+	}
+}
+
+// Marshal 将消息编码为protobuf二进制格式
+func (m *GetCWERequest) Marshal() []byte {
+	return appendString(nil, 1, m.Id)
+}
+
+// Unmarshal 从protobuf二进制格式解码消息
+func (m *GetCWERequest) Unmarshal(data []byte) error {
+	*m = GetCWERequest{}
+	r := &fieldReader{data: data}
+	for {
+		ok, err := r.next()
+		if !ok || err != nil {
+			return err
+		}
+		if r.field == 1 {
+			if m.Id, err = r.string(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Marshal 将消息编码为protobuf二进制格式
+func (m *SearchRequest) Marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, m.Query)
+	b = appendInt32(b, 2, m.Limit)
+	b = appendInt32(b, 3, m.Offset)
+	b = appendString(b, 4, m.Cursor)
+	return b
+}
+
+// Unmarshal 从protobuf二进制格式解码消息
+func (m *SearchRequest) Unmarshal(data []byte) error {
+	*m = SearchRequest{}
+	r := &fieldReader{data: data}
+	for {
+		ok, err := r.next()
+		if !ok || err != nil {
+			return err
+		}
+		switch r.field {
+		case 1:
+			m.Query, err = r.string()
+		case 2:
+			m.Limit, err = r.int32()
+		case 3:
+			m.Offset, err = r.int32()
+		case 4:
+			m.Cursor, err = r.string()
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Marshal 将消息编码为protobuf二进制格式
+func (m *SearchResponse) Marshal() []byte {
+	var b []byte
+	for _, entry := range m.Results {
+		b = appendMessage(b, 1, entry.Marshal())
+	}
+	b = appendInt32(b, 2, m.Total)
+	b = appendString(b, 3, m.NextCursor)
+	return b
+}
+
+// Unmarshal 从protobuf二进制格式解码消息
+func (m *SearchResponse) Unmarshal(data []byte) error {
+	*m = SearchResponse{}
+	r := &fieldReader{data: data}
+	for {
+		ok, err := r.next()
+		if !ok || err != nil {
+			return err
+		}
+		switch r.field {
+		case 1:
+			entry, err := unmarshalCWE(r)
+			if err != nil {
+				return err
+			}
+			m.Results = append(m.Results, entry)
+		case 2:
+			if m.Total, err = r.int32(); err != nil {
+				return err
+			}
+		case 3:
+			if m.NextCursor, err = r.string(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Marshal 将消息编码为protobuf二进制格式
+func (m *GetTreeRequest) Marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, m.RootId)
+	b = appendInt32(b, 2, m.MaxDepth)
+	return b
+}
+
+// Unmarshal 从protobuf二进制格式解码消息
+func (m *GetTreeRequest) Unmarshal(data []byte) error {
+	*m = GetTreeRequest{}
+	r := &fieldReader{data: data}
+	for {
+		ok, err := r.next()
+		if !ok || err != nil {
+			return err
+		}
+		switch r.field {
+		case 1:
+			m.RootId, err = r.string()
+		case 2:
+			m.MaxDepth, err = r.int32()
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// unmarshalCWE 解码当前的嵌套CWE消息字段
+func unmarshalCWE(r *fieldReader) (*CWE, error) {
+	data, err := r.message()
+	if err != nil {
+		return nil, err
+	}
+	entry := &CWE{}
+	if err := entry.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
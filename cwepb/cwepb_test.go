@@ -0,0 +1,98 @@
+package cwepb
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestMarshalWireFormat(t *testing.T) {
+	// 与protoc生成的代码对相同消息的编码结果一致
+	tests := []struct {
+		name string
+		msg  Message
+		want []byte
+	}{
+		{"字符串字段", &GetCWERequest{Id: "CWE-79"}, []byte{0x0a, 0x06, 'C', 'W', 'E', '-', '7', '9'}},
+		{"零值不编码", &GetCWERequest{}, nil},
+		{"int32字段", &GetTreeRequest{MaxDepth: 300}, []byte{0x10, 0xac, 0x02}},
+		{"负数int32", &SearchRequest{Offset: -1}, []byte{0x18, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}},
+		{"repeated字段保留空字符串", &CWE{ChildIds: []string{"", "CWE-1"}}, []byte{0x5a, 0x00, 0x5a, 0x05, 'C', 'W', 'E', '-', '1'}},
+		{"嵌套消息", &TreeNode{Cwe: &CWE{Id: "A"}}, []byte{0x0a, 0x03, 0x0a, 0x01, 'A'}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.msg.Marshal(); !bytes.Equal(got, tt.want) {
+				t.Errorf("Marshal() = % x, 期望 % x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	entry := &CWE{
+		Id:             "CWE-79",
+		Name:           "Cross-site Scripting",
+		Description:    "跨站脚本",
+		Url:            "https://cwe.mitre.org/data/definitions/79.html",
+		Severity:       "High",
+		Status:         "Stable",
+		Abstraction:    "Base",
+		Language:       "en",
+		Mitigations:    []string{"对输出进行编码", "输入验证"},
+		Examples:       []string{"CVE-2021-25926"},
+		ParentId:       "CWE-74",
+		ChildIds:       []string{"CWE-80", "CWE-81"},
+		Relations:      []*Relation{{Nature: "ChildOf", CweId: "CWE-74", ViewId: "CWE-1000", Ordinal: "Primary"}},
+		Version:        "4.16",
+		AlternateTerms: []string{"XSS"},
+	}
+
+	messages := []struct {
+		in  Message
+		out Message
+	}{
+		{entry, &CWE{}},
+		{&Registry{RootId: "CWE-79", Entries: []*CWE{entry, {Id: "CWE-80"}}}, &Registry{}},
+		{&TreeNode{Cwe: entry, Children: []*TreeNode{{Cwe: &CWE{Id: "CWE-80"}}, {Cwe: &CWE{Id: "CWE-81"}}}}, &TreeNode{}},
+		{&SearchRequest{Query: "注入", Limit: 10, Offset: 20, Cursor: "abc"}, &SearchRequest{}},
+		{&SearchResponse{Results: []*CWE{entry}, Total: 42, NextCursor: "next"}, &SearchResponse{}},
+		{&GetTreeRequest{RootId: "CWE-20", MaxDepth: 2}, &GetTreeRequest{}},
+	}
+	for _, m := range messages {
+		if err := m.out.Unmarshal(m.in.Marshal()); err != nil {
+			t.Fatalf("%T解码失败: %v", m.in, err)
+		}
+		if !reflect.DeepEqual(m.in, m.out) {
+			t.Errorf("%T往返后不一致:\n%+v\n%+v", m.in, m.in, m.out)
+		}
+	}
+}
+
+func TestUnmarshalUnknownAndInvalid(t *testing.T) {
+	// 未知字段(varint、fixed64、fixed32和bytes)被忽略
+	data := []byte{
+		0xa0, 0x06, 0x01, // 字段100 varint
+		0xa9, 0x06, 1, 2, 3, 4, 5, 6, 7, 8, // 字段101 fixed64
+		0xb5, 0x06, 1, 2, 3, 4, // 字段102 fixed32
+		0xba, 0x06, 0x01, 'x', // 字段103 bytes
+		0x0a, 0x01, 'A',
+	}
+	var req GetCWERequest
+	if err := req.Unmarshal(data); err != nil || req.Id != "A" {
+		t.Errorf("应忽略未知字段，实际为%+v, %v", req, err)
+	}
+
+	invalid := map[string][]byte{
+		"截断的长度":   {0x0a, 0x05, 'A'},
+		"截断的标签":   {0x80},
+		"字段编号为0":  {0x02, 0x00},
+		"线格式类型错误": {0x08, 0x01},
+		"不支持的类型":  {0x0b},
+	}
+	for name, data := range invalid {
+		if err := (&GetCWERequest{}).Unmarshal(data); err == nil {
+			t.Errorf("%s: 应返回错误", name)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package cwepb
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/scagogogo/cwe"
+)
+
+// ServiceName 是cwe.proto中定义的gRPC服务全名
+const ServiceName = "cwe.v1.CWEService"
+
+// MaxMessageSize 是服务端和客户端接受的单个消息的最大字节数，与gRPC的默认值相同
+const MaxMessageSize = 4 << 20
+
+// CWEServiceServer 是cwe.proto中CWEService的服务端接口
+type CWEServiceServer interface {
+	GetCWE(ctx context.Context, req *GetCWERequest) (*CWE, error)
+	Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error)
+	GetTree(ctx context.Context, req *GetTreeRequest) (*TreeNode, error)
+}
+
+// RegistryService 是由Registry支撑的CWEService实现
+//
+// RegistryService只读取注册表，可以并发处理请求；
+// 服务期间修改注册表的层次结构需要调用方自行同步。
+type RegistryService struct {
+	registry *cwe.Registry
+}
+
+// NewRegistryService 创建由注册表支撑的服务
+func NewRegistryService(registry *cwe.Registry) *RegistryService {
+	return &RegistryService{registry: registry}
+}
+
+// GetCWE 获取单个条目
+// ID无效时返回CodeInvalidArgument，条目不存在时返回CodeNotFound
+func (s *RegistryService) GetCWE(ctx context.Context, req *GetCWERequest) (*CWE, error) {
+	entry, err := s.lookup(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return FromCWE(entry), nil
+}
+
+// Search 分页搜索条目，参数含义与cwe.Registry.Search相同
+// 游标或分页参数无效时返回CodeInvalidArgument
+func (s *RegistryService) Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
+	page, err := s.registry.Search(cwe.SearchQuery{
+		Keyword: req.Query,
+		Limit:   int(req.Limit),
+		Offset:  int(req.Offset),
+		Cursor:  req.Cursor,
+	})
+	if err != nil {
+		return nil, Errorf(CodeInvalidArgument, "%v", err)
+	}
+
+	resp := &SearchResponse{Total: int32(page.Total), NextCursor: page.NextCursor}
+	for _, entry := range page.Results {
+		resp.Results = append(resp.Results, FromCWE(entry))
+	}
+	return resp, nil
+}
+
+// GetTree 获取以RootId为根的子树
+// 节点沿Children展开，已在当前路径上的节点不会再次展开，因此环不会导致无限递归
+func (s *RegistryService) GetTree(ctx context.Context, req *GetTreeRequest) (*TreeNode, error) {
+	var root *cwe.CWE
+	if req.RootId == "" {
+		if root = s.registry.Root; root == nil {
+			return nil, Errorf(CodeNotFound, "注册表没有根节点")
+		}
+	} else {
+		var err error
+		if root, err = s.lookup(req.RootId); err != nil {
+			return nil, err
+		}
+	}
+	return buildTree(root, 0, int(req.MaxDepth), map[*cwe.CWE]bool{}), nil
+}
+
+// lookup 查找条目并转换为带状态码的错误
+func (s *RegistryService) lookup(id string) (*cwe.CWE, error) {
+	normalized, err := cwe.ParseCWEID(id)
+	if err != nil {
+		return nil, Errorf(CodeInvalidArgument, "%v", err)
+	}
+	entry, err := s.registry.GetByID(normalized)
+	if err != nil {
+		return nil, Errorf(CodeNotFound, "%v", err)
+	}
+	return entry, nil
+}
+
+// buildTree 构建树节点，maxDepth为0时不限制深度
+func buildTree(entry *cwe.CWE, depth, maxDepth int, onPath map[*cwe.CWE]bool) *TreeNode {
+	node := &TreeNode{Cwe: FromCWE(entry)}
+	if maxDepth > 0 && depth >= maxDepth {
+		return node
+	}
+
+	onPath[entry] = true
+	defer delete(onPath, entry)
+	for _, child := range entry.Children {
+		if onPath[child] {
+			continue
+		}
+		node.Children = append(node.Children, buildTree(child, depth+1, maxDepth, onPath))
+	}
+	return node
+}
+
+// handler 实现gRPC一元调用协议的http.Handler
+type handler struct {
+	service CWEServiceServer
+}
+
+// NewHandler 创建提供CWEService的http.Handler
+//
+// 方法功能:
+// 按gRPC over HTTP/2协议处理"/cwe.v1.CWEService/{方法}"的POST请求，
+// 请求和响应体为带5字节长度前缀的protobuf消息，状态通过grpc-status和grpc-message尾部返回。
+// 不支持压缩消息和流式调用。
+//
+// 参数:
+// - service: CWEServiceServer - 服务实现，通常为NewRegistryService的返回值
+//
+// 返回值:
+// - http.Handler: 可以挂载到http.Server或http.ServeMux的处理器
+func NewHandler(service CWEServiceServer) http.Handler {
+	return &handler{service: service}
+}
+
+// ServeHTTP 处理请求
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "gRPC请求必须使用POST方法", http.StatusMethodNotAllowed)
+		return
+	}
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		http.Error(w, "不支持的Content-Type: "+r.Header.Get("Content-Type"), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/grpc+proto")
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+
+	resp, err := h.invoke(r)
+	if err != nil {
+		w.WriteHeader(http.StatusOK)
+		writeStatus(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(frame(resp.Marshal()))
+	writeStatus(w, nil)
+}
+
+// invoke 解码请求并调用对应的服务方法
+func (h *handler) invoke(r *http.Request) (Message, error) {
+	method := strings.TrimPrefix(r.URL.Path, "/"+ServiceName+"/")
+	if method == r.URL.Path {
+		return nil, Errorf(CodeUnimplemented, "未知的服务: %s", r.URL.Path)
+	}
+
+	var req Message
+	switch method {
+	case "GetCWE":
+		req = &GetCWERequest{}
+	case "Search":
+		req = &SearchRequest{}
+	case "GetTree":
+		req = &GetTreeRequest{}
+	default:
+		return nil, Errorf(CodeUnimplemented, "未知的方法: %s", method)
+	}
+
+	data, err := readFrame(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Unmarshal(data); err != nil {
+		return nil, Errorf(CodeInvalidArgument, "解码请求失败: %v", err)
+	}
+
+	ctx := r.Context()
+	switch req := req.(type) {
+	case *GetCWERequest:
+		return h.service.GetCWE(ctx, req)
+	case *SearchRequest:
+		return h.service.Search(ctx, req)
+	default:
+		return h.service.GetTree(ctx, req.(*GetTreeRequest))
+	}
+}
+
+// writeStatus 写入grpc-status和grpc-message尾部
+func writeStatus(w http.ResponseWriter, err error) {
+	code := CodeOf(err)
+	w.Header().Set("Grpc-Status", strconv.Itoa(int(code)))
+	if err != nil {
+		message := err.Error()
+		var status *Status
+		if errors.As(err, &status) {
+			message = status.Message
+		}
+		w.Header().Set("Grpc-Message", encodeStatusMessage(message))
+	}
+}
+
+// frame 为消息添加gRPC的5字节前缀: 1字节压缩标记和4字节大端长度
+func frame(data []byte) []byte {
+	b := make([]byte, 5, 5+len(data))
+	binary.BigEndian.PutUint32(b[1:], uint32(len(data)))
+	return append(b, data...)
+}
+
+// readFrame 读取一个带前缀的gRPC消息
+func readFrame(r io.Reader) ([]byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, Errorf(CodeInvalidArgument, "读取消息前缀失败: %v", err)
+	}
+	if header[0] != 0 {
+		return nil, Errorf(CodeUnimplemented, "不支持压缩的消息")
+	}
+
+	length := binary.BigEndian.Uint32(header[1:])
+	if length > MaxMessageSize {
+		return nil, Errorf(CodeInvalidArgument, "消息长度%d超过上限%d", length, MaxMessageSize)
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, Errorf(CodeInvalidArgument, "读取消息失败: %v", err)
+	}
+	return data, nil
+}
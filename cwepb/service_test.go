@@ -0,0 +1,171 @@
+package cwepb
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestClient 启动使用HTTP/2的测试服务并返回客户端
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	ts := httptest.NewUnstartedServer(NewHandler(NewRegistryService(newTestRegistry())))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+	return NewClient(ts.URL, ts.Client())
+}
+
+func treeIDs(node *TreeNode) []string {
+	ids := []string{node.Cwe.Id}
+	for _, child := range node.Children {
+		ids = append(ids, treeIDs(child)...)
+	}
+	return ids
+}
+
+func TestServiceGetCWE(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	entry, err := client.GetCWE(ctx, &GetCWERequest{Id: "79"})
+	if err != nil {
+		t.Fatalf("GetCWE失败: %v", err)
+	}
+	if entry.Id != "CWE-79" || entry.ParentId != "CWE-20" || entry.Severity != "High" {
+		t.Errorf("条目不正确: %+v", entry)
+	}
+
+	_, err = client.GetCWE(ctx, &GetCWERequest{Id: "CWE-404"})
+	if CodeOf(err) != CodeNotFound {
+		t.Errorf("条目不存在时应返回CodeNotFound，实际为%v", err)
+	}
+	if !strings.Contains(err.(*Status).Message, "未找到") {
+		t.Errorf("状态消息应正确解码中文，实际为%q", err.(*Status).Message)
+	}
+
+	if _, err := client.GetCWE(ctx, &GetCWERequest{Id: "abc"}); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("ID无效时应返回CodeInvalidArgument，实际为%v", err)
+	}
+}
+
+func TestServiceSearch(t *testing.T) {
+	client := newTestClient(t)
+
+	resp, err := client.Search(context.Background(), &SearchRequest{Query: "injection", Limit: 1})
+	if err != nil {
+		t.Fatalf("Search失败: %v", err)
+	}
+	if resp.Total != 2 || len(resp.Results) != 1 || resp.Results[0].Id != "CWE-74" || resp.NextCursor == "" {
+		t.Fatalf("搜索结果不正确: %+v", resp)
+	}
+
+	next, err := client.Search(context.Background(), &SearchRequest{Query: "injection", Limit: 1, Cursor: resp.NextCursor})
+	if err != nil || len(next.Results) != 1 || next.Results[0].Id != "CWE-89" {
+		t.Errorf("第二页结果不正确: %+v, %v", next, err)
+	}
+
+	if _, err := client.Search(context.Background(), &SearchRequest{Cursor: "!!!"}); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("游标无效时应返回CodeInvalidArgument，实际为%v", err)
+	}
+}
+
+func TestServiceGetTree(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	tree, err := client.GetTree(ctx, &GetTreeRequest{})
+	if err != nil {
+		t.Fatalf("GetTree失败: %v", err)
+	}
+	if got := strings.Join(treeIDs(tree), ","); got != "CWE-1000,CWE-20,CWE-79,CWE-89,CWE-74" {
+		t.Errorf("树 = %s", got)
+	}
+
+	tree, err = client.GetTree(ctx, &GetTreeRequest{RootId: "CWE-1000", MaxDepth: 1})
+	if err != nil || strings.Join(treeIDs(tree), ",") != "CWE-1000,CWE-20,CWE-74" {
+		t.Errorf("限制深度后的树不正确: %v", err)
+	}
+
+	if _, err := client.GetTree(ctx, &GetTreeRequest{RootId: "CWE-404"}); CodeOf(err) != CodeNotFound {
+		t.Errorf("根节点不存在时应返回CodeNotFound，实际为%v", err)
+	}
+}
+
+func TestServiceGetTreeCycle(t *testing.T) {
+	registry := newTestRegistry()
+	registry.Entries["CWE-79"].Children = append(registry.Entries["CWE-79"].Children, registry.Entries["CWE-20"])
+
+	tree, err := NewRegistryService(registry).GetTree(context.Background(), &GetTreeRequest{RootId: "CWE-20"})
+	if err != nil {
+		t.Fatalf("GetTree失败: %v", err)
+	}
+	if got := strings.Join(treeIDs(tree), ","); got != "CWE-20,CWE-79,CWE-89" {
+		t.Errorf("环中的节点不应再次展开，实际为%s", got)
+	}
+}
+
+func TestHandlerProtocolErrors(t *testing.T) {
+	ts := httptest.NewServer(NewHandler(NewRegistryService(newTestRegistry())))
+	defer ts.Close()
+
+	// HTTP/1.1上同样可以调用
+	client := NewClient(ts.URL+"/", nil)
+	if _, err := client.GetCWE(context.Background(), &GetCWERequest{Id: "CWE-20"}); err != nil {
+		t.Errorf("HTTP/1.1调用失败: %v", err)
+	}
+
+	var unknown SearchResponse
+	if err := client.invoke(context.Background(), "Unknown", &GetCWERequest{}, &unknown); CodeOf(err) != CodeUnimplemented {
+		t.Errorf("未知方法应返回CodeUnimplemented，实际为%v", err)
+	}
+
+	resp, err := http.Get(ts.URL + "/" + ServiceName + "/GetCWE")
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET请求应返回405，实际为%d", resp.StatusCode)
+	}
+
+	resp, err = http.Post(ts.URL+"/"+ServiceName+"/GetCWE", "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnsupportedMediaType {
+		t.Errorf("非gRPC请求应返回415，实际为%d", resp.StatusCode)
+	}
+
+	// 压缩的消息
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/"+ServiceName+"/GetCWE", strings.NewReader("\x01\x00\x00\x00\x00"))
+	req.Header.Set("Content-Type", "application/grpc")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	// 读完响应体后trailer才可用
+	io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.Trailer.Get("Grpc-Status") != "12" {
+		t.Errorf("压缩的消息应返回CodeUnimplemented，实际为%q", resp.Trailer.Get("Grpc-Status"))
+	}
+}
+
+func TestStatusMessageEncoding(t *testing.T) {
+	for _, message := range []string{"plain", "100% 完成", "换行\n", ""} {
+		if got := decodeStatusMessage(encodeStatusMessage(message)); got != message {
+			t.Errorf("往返后为%q，期望%q", got, message)
+		}
+	}
+	if got := encodeStatusMessage("a%b"); got != "a%25b" {
+		t.Errorf("encodeStatusMessage = %q", got)
+	}
+	if got := decodeStatusMessage("bad%zz"); got != "bad%zz" {
+		t.Errorf("无效的编码应保持原样，实际为%q", got)
+	}
+}
@@ -0,0 +1,83 @@
+package cwepb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Code 是gRPC状态码
+type Code int
+
+// 本包使用的gRPC状态码，取值与gRPC规范相同
+const (
+	CodeOK              Code = 0
+	CodeUnknown         Code = 2
+	CodeInvalidArgument Code = 3
+	CodeNotFound        Code = 5
+	CodeUnimplemented   Code = 12
+	CodeInternal        Code = 13
+	CodeUnavailable     Code = 14
+)
+
+// Status 是携带gRPC状态码的错误
+// 服务实现返回*Status时，状态码和消息原样发送给客户端；Client调用失败时也返回*Status
+type Status struct {
+	Code    Code
+	Message string
+}
+
+// Error 实现error接口
+func (s *Status) Error() string {
+	return fmt.Sprintf("gRPC错误(状态码%d): %s", s.Code, s.Message)
+}
+
+// Errorf 创建指定状态码的错误
+func Errorf(code Code, format string, args ...interface{}) error {
+	return &Status{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// CodeOf 返回错误的gRPC状态码
+// err为nil时返回CodeOK，不是*Status的错误返回CodeInternal
+func CodeOf(err error) Code {
+	if err == nil {
+		return CodeOK
+	}
+	var status *Status
+	if errors.As(err, &status) {
+		return status.Code
+	}
+	return CodeInternal
+}
+
+// encodeStatusMessage 按gRPC规范对grpc-message进行百分号编码
+// 可打印的ASCII字符(百分号除外)保持原样，其余字节编码为%XX
+func encodeStatusMessage(message string) string {
+	var b strings.Builder
+	for i := 0; i < len(message); i++ {
+		c := message[i]
+		if c >= 0x20 && c <= 0x7e && c != '%' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// decodeStatusMessage 解码grpc-message，无效的编码保持原样
+func decodeStatusMessage(message string) string {
+	var b strings.Builder
+	for i := 0; i < len(message); i++ {
+		if message[i] == '%' && i+2 < len(message) {
+			var c byte
+			if _, err := fmt.Sscanf(message[i+1:i+3], "%02X", &c); err == nil {
+				b.WriteByte(c)
+				i += 2
+				continue
+			}
+		}
+		b.WriteByte(message[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,155 @@
+package cwepb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// protobuf线格式的类型
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// errTruncated 表示消息在字段中间结束
+var errTruncated = errors.New("protobuf消息被截断")
+
+// appendUvarint 写入varint编码的无符号整数
+func appendUvarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+// appendTag 写入字段编号和线格式类型
+func appendTag(b []byte, field int, wireType int) []byte {
+	return appendUvarint(b, uint64(field)<<3|uint64(wireType))
+}
+
+// appendString 写入字符串字段，proto3中空字符串不编码
+func appendString(b []byte, field int, s string) []byte {
+	if s == "" {
+		return b
+	}
+	return appendRawString(b, field, s)
+}
+
+// appendRawString 写入字符串，用于repeated字段中的每个元素(空字符串也要编码)
+func appendRawString(b []byte, field int, s string) []byte {
+	b = appendTag(b, field, wireBytes)
+	b = appendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// appendStrings 写入repeated string字段
+func appendStrings(b []byte, field int, values []string) []byte {
+	for _, s := range values {
+		b = appendRawString(b, field, s)
+	}
+	return b
+}
+
+// appendInt32 写入int32字段，零值不编码；负数按protobuf规则编码为10字节
+func appendInt32(b []byte, field int, v int32) []byte {
+	if v == 0 {
+		return b
+	}
+	b = appendTag(b, field, wireVarint)
+	return appendUvarint(b, uint64(int64(v)))
+}
+
+// appendMessage 写入嵌套消息字段
+func appendMessage(b []byte, field int, data []byte) []byte {
+	b = appendTag(b, field, wireBytes)
+	b = appendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// fieldReader 逐个读取消息中的字段
+type fieldReader struct {
+	data []byte
+
+	// 当前字段
+	field    int
+	wireType int
+	varint   uint64
+	bytes    []byte
+}
+
+// next 读取下一个字段，没有更多字段时返回false
+// 未知的fixed32和fixed64字段会被读取但不解析，由调用方忽略
+func (r *fieldReader) next() (bool, error) {
+	if len(r.data) == 0 {
+		return false, nil
+	}
+
+	tag, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		return false, errTruncated
+	}
+	r.data = r.data[n:]
+	r.field = int(tag >> 3)
+	r.wireType = int(tag & 7)
+	if r.field == 0 {
+		return false, fmt.Errorf("无效的protobuf字段编号0")
+	}
+
+	switch r.wireType {
+	case wireVarint:
+		v, n := binary.Uvarint(r.data)
+		if n <= 0 {
+			return false, errTruncated
+		}
+		r.varint = v
+		r.data = r.data[n:]
+	case wireBytes:
+		length, n := binary.Uvarint(r.data)
+		if n <= 0 || uint64(len(r.data)-n) < length {
+			return false, errTruncated
+		}
+		r.bytes = r.data[n : n+int(length)]
+		r.data = r.data[n+int(length):]
+	case wireFixed64:
+		if len(r.data) < 8 {
+			return false, errTruncated
+		}
+		r.data = r.data[8:]
+	case wireFixed32:
+		if len(r.data) < 4 {
+			return false, errTruncated
+		}
+		r.data = r.data[4:]
+	default:
+		return false, fmt.Errorf("不支持的protobuf线格式类型%d", r.wireType)
+	}
+	return true, nil
+}
+
+// string 返回当前字段的字符串值
+func (r *fieldReader) string() (string, error) {
+	if r.wireType != wireBytes {
+		return "", fmt.Errorf("字段%d的线格式类型应为%d，实际为%d", r.field, wireBytes, r.wireType)
+	}
+	return string(r.bytes), nil
+}
+
+// message 返回当前嵌套消息字段的内容
+func (r *fieldReader) message() ([]byte, error) {
+	if r.wireType != wireBytes {
+		return nil, fmt.Errorf("字段%d的线格式类型应为%d，实际为%d", r.field, wireBytes, r.wireType)
+	}
+	return r.bytes, nil
+}
+
+// int32 返回当前字段的int32值
+func (r *fieldReader) int32() (int32, error) {
+	if r.wireType != wireVarint {
+		return 0, fmt.Errorf("字段%d的线格式类型应为%d，实际为%d", r.field, wireVarint, r.wireType)
+	}
+	return int32(r.varint), nil
+}
@@ -0,0 +1,137 @@
+// Package cwetest 提供用于测试CWE API客户端的录制数据和回放工具
+//
+// 包内附带了一组经过精简的MITRE CWE REST API真实响应(fixtures)，
+// 保留了真实接口的字段命名(如PascalCase键名、不带"CWE-"前缀的ID、
+// 以对象数组形式返回的子节点列表)，用于替代各测试文件中手写的近似响应。
+//
+// 使用示例:
+//
+//	client := cwe.NewAPIClientWithOptions(cwe.BaseURL, 0, cwe.NewHTTPRateLimiter(0))
+//	client.GetHTTPClient().SetClient(&http.Client{Transport: cwetest.NewReplayTransport()})
+//
+//	weakness, err := client.GetWeakness("79") // 返回录制的CWE-79响应
+//
+// 包内还提供了构建测试注册表的工具：MiniTree返回各测试和示例通用的
+// CWE-1000/20/79/89小型树，Build根据节点表构建注册表，Generate按随机种子生成可复现的大型树。
+// Server是可以注册任意数据并注入故障的模拟API服务，用于替代测试中手写的httptest处理函数。
+// 由于本包依赖cwe包，cwe包自身的测试需要放在外部测试包(package cwe_test)中才能使用它们。
+package cwetest
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+//go:embed fixtures/*.json
+var fixtureFS embed.FS
+
+// routes 将API路径(相对于/api/v1)映射到录制的响应文件
+var routes = map[string]string{
+	"/cwe/version":      "version.json",
+	"/cwe/weakness/74":  "weakness_74.json",
+	"/cwe/weakness/79":  "weakness_79.json",
+	"/cwe/category/189": "category_189.json",
+	"/cwe/view/1003":    "view_1003.json",
+	"/cwe/74/children":  "children_74.json",
+	"/cwe/79/parents":   "parents_79.json",
+}
+
+// Routes 返回所有可回放的API路径，已排序
+func Routes() []string {
+	paths := make([]string, 0, len(routes))
+	for path := range routes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Fixture 返回指定API路径的录制响应体
+//
+// 参数:
+// - path: string - 相对于API根路径的请求路径，如"/cwe/weakness/79"；
+// 路径中的"CWE-79"形式的ID会按"79"处理，查询参数会被忽略
+//
+// 返回值:
+// - []byte: 录制的JSON响应体
+// - error: 没有对应的录制数据时返回错误
+func Fixture(path string) ([]byte, error) {
+	name, exists := routes[normalizePath(path)]
+	if !exists {
+		return nil, fmt.Errorf("没有路径%s的录制数据", path)
+	}
+	return fixtureFS.ReadFile("fixtures/" + name)
+}
+
+// MustFixture 与Fixture相同，但找不到录制数据时会panic，便于在测试中使用
+func MustFixture(path string) []byte {
+	data, err := Fixture(path)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// ReplayTransport 是回放录制响应的http.RoundTripper
+// 有录制数据的请求返回200和对应的响应体，其他请求返回404
+type ReplayTransport struct {
+	// BasePath 请求URL中API根路径的前缀，会在匹配前去除，默认为"/api/v1"
+	BasePath string
+}
+
+// NewReplayTransport 创建一个回放录制响应的Transport
+func NewReplayTransport() *ReplayTransport {
+	return &ReplayTransport{BasePath: "/api/v1"}
+}
+
+// RoundTrip 实现http.RoundTripper接口
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, t.BasePath)
+
+	resp := &http.Response{
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+		Request:    req,
+	}
+
+	data, err := Fixture(path)
+	if err != nil || req.Method != http.MethodGet {
+		resp.StatusCode = http.StatusNotFound
+		resp.Status = "404 Not Found"
+		resp.Body = io.NopCloser(strings.NewReader(""))
+		return resp, nil
+	}
+
+	resp.StatusCode = http.StatusOK
+	resp.Status = "200 OK"
+	resp.Header.Set("Content-Type", "application/json")
+	resp.ContentLength = int64(len(data))
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	return resp, nil
+}
+
+// normalizePath 去除查询参数和ID中的"CWE-"前缀
+func normalizePath(path string) string {
+	if i := strings.Index(path, "?"); i >= 0 {
+		path = path[:i]
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if len(segment) > 4 && strings.EqualFold(segment[:4], "CWE-") {
+			segments[i] = segment[4:]
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,56 @@
+package cwetest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestFixturesAreValidJSON(t *testing.T) {
+	for _, path := range Routes() {
+		data, err := Fixture(path)
+		if err != nil {
+			t.Errorf("读取%s的录制数据失败: %v", path, err)
+			continue
+		}
+		if !json.Valid(data) {
+			t.Errorf("%s的录制数据不是有效的JSON", path)
+		}
+	}
+}
+
+func TestFixtureNormalizesPath(t *testing.T) {
+	if _, err := Fixture("/cwe/weakness/CWE-79"); err != nil {
+		t.Errorf("CWE-前缀的ID应被识别: %v", err)
+	}
+	if _, err := Fixture("/cwe/74/children?view=1000"); err != nil {
+		t.Errorf("查询参数应被忽略: %v", err)
+	}
+	if _, err := Fixture("/cwe/weakness/1"); err == nil {
+		t.Error("不存在的路径应返回错误")
+	}
+}
+
+func TestReplayTransport(t *testing.T) {
+	client := &http.Client{Transport: NewReplayTransport()}
+
+	resp, err := client.Get("https://cwe-api.mitre.org/api/v1/cwe/version")
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != string(MustFixture("/cwe/version")) {
+		t.Errorf("回放响应不符合预期: %d %s", resp.StatusCode, body)
+	}
+
+	resp, err = client.Get("https://cwe-api.mitre.org/api/v1/cwe/weakness/1")
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("没有录制数据的请求应返回404，但得到 %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,352 @@
+package cwetest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scagogogo/cwe"
+)
+
+// Failure 描述Server注入的一次或多次故障
+type Failure struct {
+	// Path 匹配的API路径，如"/cwe/weakness/79"，与Fixture一样忽略查询参数和"CWE-"前缀；
+	// 为空时匹配所有请求
+	Path string
+
+	// Status 返回的状态码，为0时不修改状态码(只延迟)
+	Status int
+
+	// Times 故障生效的次数，<=0表示一直生效
+	Times int
+
+	// Delay 返回响应之前的延迟，可用于测试超时
+	Delay time.Duration
+}
+
+// Server 是可配置的CWE API模拟服务
+//
+// 条目响应由cwe包的响应结构(如cwe.WeaknessResponse)序列化而来，ID与官方API一样不带"CWE-"前缀，
+// 子节点和父节点以官方API的对象数组形式返回。
+// 通过RegisterWeakness、RegisterChildren等方法注册数据，未注册的路径返回404。
+// 通过InjectFailure可以让指定路径返回错误状态码或延迟响应，用于测试重试和降级逻辑。
+// 所有方法都是并发安全的，可以在服务运行期间修改数据。
+//
+// 由于本包依赖cwe包，cwe包自身的内部测试无法使用Server，需要使用它的测试应放在外部测试包中。
+//
+// 使用示例:
+//
+//	srv := cwetest.NewServer()
+//	defer srv.Close()
+//
+//	srv.RegisterWeakness(&cwe.CWEWeakness{ID: "79", Name: "Cross-site Scripting"})
+//	srv.RegisterChildren("20", "", "79", "89")
+//	srv.InjectFailure(cwetest.Failure{Path: "/cwe/weakness/89", Status: http.StatusServiceUnavailable, Times: 1})
+//
+//	client := srv.Client()
+//	weakness, err := client.GetWeakness("CWE-79")
+type Server struct {
+	// URL 服务的API根地址，可作为cwe.NewAPIClientWithOptions的baseURL
+	URL string
+
+	server *httptest.Server
+
+	mutex      sync.Mutex
+	version    map[string]string
+	weaknesses map[string]*cwe.CWEWeakness
+	categories map[string]*cwe.CWECategory
+	views      map[string]*cwe.CWEView
+	children   map[string][]string
+	parents    map[string][]string
+	failures   []*Failure
+	requests   []string
+}
+
+// NewServer 创建并启动模拟服务，使用完毕后需要调用Close
+func NewServer() *Server {
+	s := &Server{
+		weaknesses: make(map[string]*cwe.CWEWeakness),
+		categories: make(map[string]*cwe.CWECategory),
+		views:      make(map[string]*cwe.CWEView),
+		children:   make(map[string][]string),
+		parents:    make(map[string][]string),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL
+	return s
+}
+
+// Close 关闭服务
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Client 返回指向本服务的APIClient
+// 客户端不限速，重试间隔为1毫秒，测试失败重试时不必等待
+func (s *Server) Client() *cwe.APIClient {
+	client := cwe.NewAPIClientWithOptions(s.URL, cwe.DefaultTimeout, cwe.NewHTTPRateLimiter(0))
+	client.GetHTTPClient().SetRetryDelay(time.Millisecond)
+	return client
+}
+
+// SetVersion 设置/cwe/version返回的内容版本和发布日期
+func (s *Server) SetVersion(version, releaseDate string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.version = map[string]string{"ContentVersion": version, "ContentDate": releaseDate}
+}
+
+// RegisterWeakness 注册弱点，ID可以带或不带"CWE-"前缀，响应中的ID不带前缀
+// 弱点同时可以通过/cwe/{id}获取
+func (s *Server) RegisterWeakness(weakness *cwe.CWEWeakness) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	copied := *weakness
+	copied.ID = rawID(weakness.ID)
+	s.weaknesses[copied.ID] = &copied
+}
+
+// RegisterCategory 注册类别
+func (s *Server) RegisterCategory(category *cwe.CWECategory) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	copied := *category
+	copied.ID = rawID(category.ID)
+	s.categories[copied.ID] = &copied
+}
+
+// RegisterView 注册视图
+func (s *Server) RegisterView(view *cwe.CWEView) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	copied := *view
+	copied.ID = rawID(view.ID)
+	s.views[copied.ID] = &copied
+}
+
+// RegisterChildren 注册条目的子节点，同时记录子节点的父节点
+//
+// viewID不为空时只在请求带有相同的view参数时返回；带view参数的请求没有对应的注册时，
+// 回退到viewID为空时注册的子节点。重复注册会替换之前的子节点列表。
+func (s *Server) RegisterChildren(id, viewID string, childIDs ...string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key := relationKey(id, viewID)
+	ids := make([]string, 0, len(childIDs))
+	for _, childID := range childIDs {
+		ids = append(ids, rawID(childID))
+		parentKey := relationKey(childID, viewID)
+		if !containsString(s.parents[parentKey], rawID(id)) {
+			s.parents[parentKey] = append(s.parents[parentKey], rawID(id))
+		}
+	}
+	s.children[key] = ids
+}
+
+// RegisterRegistry 注册注册表中的所有条目和父子关系
+// 根节点注册为视图，其余条目注册为弱点，子节点在viewID为空时注册
+func (s *Server) RegisterRegistry(registry *cwe.Registry) {
+	for _, entry := range registry.Snapshot() {
+		if entry == registry.Root {
+			s.RegisterView(&cwe.CWEView{ID: entry.ID, Name: entry.Name, Description: entry.Description, Status: entry.Status})
+		} else {
+			weakness := &cwe.CWEWeakness{
+				ID:                entry.ID,
+				Name:              entry.Name,
+				Description:       entry.Description,
+				URL:               entry.URL,
+				Severity:          entry.Severity,
+				Status:            entry.Status,
+				RelatedWeaknesses: entry.Relations,
+				MappingNotes:      entry.MappingNotes,
+			}
+			weakness.Mitigations = entry.TypedMitigations()
+			s.RegisterWeakness(weakness)
+		}
+
+		if len(entry.Children) > 0 {
+			childIDs := make([]string, 0, len(entry.Children))
+			for _, child := range entry.Children {
+				childIDs = append(childIDs, child.ID)
+			}
+			s.RegisterChildren(entry.ID, "", childIDs...)
+		}
+	}
+}
+
+// InjectFailure 注入故障，多个故障匹配同一请求时使用最先注入且仍然生效的一个
+func (s *Server) InjectFailure(failure Failure) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	copied := failure
+	if copied.Path != "" {
+		copied.Path = normalizePath(copied.Path)
+	}
+	s.failures = append(s.failures, &copied)
+}
+
+// ClearFailures 移除所有注入的故障
+func (s *Server) ClearFailures() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.failures = nil
+}
+
+// Requests 返回服务收到的所有请求路径(包括查询参数)，按接收顺序排列
+func (s *Server) Requests() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// RequestCount 返回匹配path的请求数，匹配规则与Failure.Path相同
+func (s *Server) RequestCount(path string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	path = normalizePath(path)
+	count := 0
+	for _, request := range s.requests {
+		if normalizePath(request) == path {
+			count++
+		}
+	}
+	return count
+}
+
+// serveHTTP 处理请求
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path := normalizePath(r.URL.Path)
+
+	s.mutex.Lock()
+	s.requests = append(s.requests, r.URL.RequestURI())
+	failure := s.takeFailure(path)
+	var body interface{}
+	if failure == nil || failure.Status == 0 {
+		body = s.lookup(path, rawID(r.URL.Query().Get("view")))
+	}
+	s.mutex.Unlock()
+
+	if failure != nil {
+		if failure.Delay > 0 {
+			select {
+			case <-time.After(failure.Delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if failure.Status != 0 {
+			http.Error(w, http.StatusText(failure.Status), failure.Status)
+			return
+		}
+	}
+
+	if body == nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+// takeFailure 返回匹配路径的故障并减少其剩余次数，调用方需持有锁
+func (s *Server) takeFailure(path string) *Failure {
+	for i, failure := range s.failures {
+		if failure.Path != "" && failure.Path != path {
+			continue
+		}
+		if failure.Times > 0 {
+			failure.Times--
+			if failure.Times == 0 {
+				s.failures = append(s.failures[:i:i], s.failures[i+1:]...)
+			}
+		}
+		return failure
+	}
+	return nil
+}
+
+// lookup 返回路径对应的响应体，没有注册数据时返回nil，调用方需持有锁
+func (s *Server) lookup(path, viewID string) interface{} {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 2 || parts[0] != "cwe" {
+		return nil
+	}
+
+	switch {
+	case len(parts) == 2 && parts[1] == "version":
+		if s.version == nil {
+			return nil
+		}
+		return s.version
+	case len(parts) == 2:
+		result := make(map[string]*cwe.CWEWeakness)
+		for _, id := range strings.Split(parts[1], ",") {
+			weakness, exists := s.weaknesses[rawID(id)]
+			if !exists {
+				return nil
+			}
+			result[weakness.ID] = weakness
+		}
+		return cwe.CWEsResponse{CWEs: result}
+	case len(parts) == 3 && parts[1] == "weakness":
+		if weakness, exists := s.weaknesses[parts[2]]; exists {
+			return cwe.WeaknessResponse{Weaknesses: []*cwe.CWEWeakness{weakness}}
+		}
+	case len(parts) == 3 && parts[1] == "category":
+		if category, exists := s.categories[parts[2]]; exists {
+			return cwe.CategoryResponse{Categories: []*cwe.CWECategory{category}}
+		}
+	case len(parts) == 3 && parts[1] == "view":
+		if view, exists := s.views[parts[2]]; exists {
+			return cwe.ViewResponse{Views: []*cwe.CWEView{view}}
+		}
+	case len(parts) == 3 && (parts[2] == "children" || parts[2] == "parents"):
+		relations := s.children
+		if parts[2] == "parents" {
+			relations = s.parents
+		}
+		ids, exists := relations[relationKey(parts[1], viewID)]
+		if !exists && viewID != "" {
+			ids, exists = relations[relationKey(parts[1], "")]
+		}
+		if !exists {
+			return nil
+		}
+		entries := make([]map[string]interface{}, 0, len(ids))
+		for _, id := range ids {
+			entry := map[string]interface{}{"Type": "weakness", "ID": id}
+			if viewID != "" {
+				entry["ViewID"] = viewID
+			}
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+	return nil
+}
+
+// rawID 去除ID的"CWE-"前缀
+func rawID(id string) string {
+	if len(id) > 4 && strings.EqualFold(id[:4], "CWE-") {
+		return id[4:]
+	}
+	return id
+}
+
+// relationKey 返回子节点和父节点映射的键
+func relationKey(id, viewID string) string {
+	return rawID(id) + "@" + rawID(viewID)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
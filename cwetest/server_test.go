@@ -0,0 +1,112 @@
+package cwetest
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scagogogo/cwe"
+)
+
+func TestServerRegister(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.SetVersion("4.16", "2024-11-19")
+	srv.RegisterWeakness(&cwe.CWEWeakness{ID: "CWE-79", Name: "Cross-site Scripting", Severity: "High"})
+	srv.RegisterCategory(&cwe.CWECategory{ID: "189", Name: "Numeric Errors"})
+	srv.RegisterView(&cwe.CWEView{ID: "1000", Name: "Research Concepts"})
+	srv.RegisterChildren("CWE-20", "", "79", "CWE-89")
+	srv.RegisterChildren("20", "1000", "79")
+	client := srv.Client()
+
+	version, err := client.GetVersion()
+	if err != nil || version.Version != "4.16" {
+		t.Errorf("版本不正确: %+v, %v", version, err)
+	}
+	weakness, err := client.GetWeakness("79")
+	if err != nil || weakness.ID != "79" || weakness.Severity != "High" {
+		t.Errorf("弱点不正确: %+v, %v", weakness, err)
+	}
+	if category, err := client.GetCategory("CWE-189"); err != nil || category.Name != "Numeric Errors" {
+		t.Errorf("类别不正确: %+v, %v", category, err)
+	}
+	if view, err := client.GetView("1000"); err != nil || view.Name != "Research Concepts" {
+		t.Errorf("视图不正确: %+v, %v", view, err)
+	}
+	if cwes, err := client.GetCWEs([]string{"79"}); err != nil || cwes["79"] == nil {
+		t.Errorf("GetCWEs结果不正确: %v, %v", cwes, err)
+	}
+
+	children, err := client.GetChildren("20", "")
+	if err != nil || !reflect.DeepEqual(children, []string{"79", "89"}) {
+		t.Errorf("子节点不正确: %v, %v", children, err)
+	}
+	children, err = client.GetChildren("20", "1000")
+	if err != nil || !reflect.DeepEqual(children, []string{"79"}) {
+		t.Errorf("视图中的子节点不正确: %v, %v", children, err)
+	}
+	// 视图没有单独注册时回退到不限视图的注册
+	children, err = client.GetChildren("20", "699")
+	if err != nil || len(children) != 2 {
+		t.Errorf("应回退到不限视图的子节点: %v, %v", children, err)
+	}
+	parents, err := client.GetParents("89", "")
+	if err != nil || !reflect.DeepEqual(parents, []string{"20"}) {
+		t.Errorf("父节点不正确: %v, %v", parents, err)
+	}
+
+	if _, err := client.GetWeakness("89"); err == nil {
+		t.Error("未注册的弱点应返回错误")
+	}
+	if srv.RequestCount("/cwe/weakness/CWE-79") != 1 || !strings.HasPrefix(srv.Requests()[0], "/cwe/version") {
+		t.Errorf("请求记录不正确: %v", srv.Requests())
+	}
+}
+
+func TestServerInjectFailure(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.RegisterWeakness(&cwe.CWEWeakness{ID: "79", Name: "Cross-site Scripting"})
+	client := srv.Client()
+	client.GetHTTPClient().SetMaxRetries(3)
+
+	// 前两次失败，第三次重试成功
+	srv.InjectFailure(Failure{Path: "/cwe/weakness/CWE-79", Status: http.StatusServiceUnavailable, Times: 2})
+	if _, err := client.GetWeakness("79"); err != nil {
+		t.Fatalf("重试后应成功: %v", err)
+	}
+	if count := srv.RequestCount("/cwe/weakness/79"); count != 3 {
+		t.Errorf("应请求3次，实际为%d", count)
+	}
+
+	// 一直生效的故障
+	srv.InjectFailure(Failure{Status: http.StatusInternalServerError})
+	if _, err := client.GetWeakness("79"); err == nil {
+		t.Error("注入故障后应返回错误")
+	}
+	srv.ClearFailures()
+
+	// 只延迟的故障
+	srv.InjectFailure(Failure{Path: "/cwe/weakness/79", Delay: 20 * time.Millisecond, Times: 1})
+	start := time.Now()
+	if _, err := client.GetWeakness("79"); err != nil || time.Since(start) < 20*time.Millisecond {
+		t.Errorf("应在延迟后成功返回: %v", err)
+	}
+}
+
+func TestServerRegisterRegistry(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.RegisterRegistry(MiniTree())
+
+	registry, err := cwe.NewDataFetcherWithClient(srv.Client()).BuildCWETreeWithView("1000")
+	if err != nil {
+		t.Fatalf("构建树失败: %v", err)
+	}
+	if len(registry.Entries) != 4 || registry.Entries["CWE-79"].Parent.ID != "CWE-20" {
+		t.Errorf("构建的树不正确: %d个条目", len(registry.Entries))
+	}
+}
@@ -0,0 +1,149 @@
+package cwetest
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/scagogogo/cwe"
+)
+
+// Node 描述测试注册表中的一个条目，用于以表格形式定义测试树
+type Node struct {
+	// ID 条目ID，必须为"CWE-数字"格式
+	ID string
+
+	// Name 条目名称
+	Name string
+
+	// Parent 父节点ID，为空表示根节点；表格中只能有一个根节点
+	Parent string
+
+	// Severity 严重性，可以为空
+	Severity string
+
+	// Description 描述，可以为空
+	Description string
+}
+
+// MiniTreeNodes 返回MiniTree使用的节点表
+//
+// 树结构:
+//
+//	CWE-1000 Research Concepts
+//	└── CWE-20 Improper Input Validation
+//	    ├── CWE-79 Cross-site Scripting
+//	    └── CWE-89 SQL Injection
+//
+// 返回值是新的切片，可以修改后传给Build构造变体。
+func MiniTreeNodes() []Node {
+	return []Node{
+		{ID: "CWE-1000", Name: "Research Concepts", Description: "研究视图"},
+		{ID: "CWE-20", Name: "Improper Input Validation", Parent: "CWE-1000", Severity: "Medium",
+			Description: "输入验证不当"},
+		{ID: "CWE-79", Name: "Cross-site Scripting", Parent: "CWE-20", Severity: "High",
+			Description: "网页生成过程中未正确中和输入，导致跨站脚本"},
+		{ID: "CWE-89", Name: "SQL Injection", Parent: "CWE-20", Severity: "High",
+			Description: "SQL命令中使用的特殊元素未正确中和，导致SQL注入"},
+	}
+}
+
+// MiniTree 返回测试和示例中通用的小型CWE树，Root为CWE-1000
+//
+// 每次调用都返回新的注册表，测试可以随意修改而不会相互影响。
+//
+// 使用示例:
+//
+//	registry := cwetest.MiniTree()
+//	xss := registry.Entries["CWE-79"]
+//	fmt.Println(xss.Parent.ID) // 输出: CWE-20
+func MiniTree() *cwe.Registry {
+	return MustBuild(MiniTreeNodes())
+}
+
+// Build 根据节点表构建注册表
+//
+// 参数:
+// - nodes: []Node - 节点表，父节点可以出现在子节点之后；子节点按表中顺序添加
+//
+// 返回值:
+// - *cwe.Registry: 构建的注册表，Root为Parent为空的节点
+// - error: ID重复、引用了不存在的父节点或根节点不唯一时返回错误
+func Build(nodes []Node) (*cwe.Registry, error) {
+	registry := cwe.NewRegistry()
+
+	for _, node := range nodes {
+		entry := cwe.NewCWE(node.ID, node.Name)
+		entry.Severity = node.Severity
+		entry.Description = node.Description
+		entry.URL = fmt.Sprintf("https://cwe.mitre.org/data/definitions/%s.html", strings.TrimPrefix(node.ID, "CWE-"))
+		if err := registry.Register(entry); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, node := range nodes {
+		entry := registry.Entries[node.ID]
+		if node.Parent == "" {
+			if registry.Root != nil {
+				return nil, fmt.Errorf("存在多个根节点: %s和%s", registry.Root.ID, node.ID)
+			}
+			registry.Root = entry
+			continue
+		}
+
+		parent, exists := registry.Entries[node.Parent]
+		if !exists {
+			return nil, fmt.Errorf("%s的父节点%s不存在", node.ID, node.Parent)
+		}
+		parent.AddChild(entry)
+	}
+
+	return registry, nil
+}
+
+// MustBuild 与Build相同，但出错时会panic，适合在测试中构建固定的节点表
+func MustBuild(nodes []Node) *cwe.Registry {
+	registry, err := Build(nodes)
+	if err != nil {
+		panic(err)
+	}
+	return registry
+}
+
+// Generate 使用固定的随机种子生成指定大小的注册表
+//
+// 相同的seed和size总是生成完全相同的树，适合需要较大数据量的性能测试和属性测试。
+// 根节点为CWE-1000，其余节点的ID从CWE-1开始依次编号(跳过1000)，每个节点的父节点
+// 随机选自在它之前生成的节点，严重性从High、Medium、Low中随机选择。
+//
+// 参数:
+// - seed: int64 - 随机种子
+// - size: int - 节点总数(包括根节点)，<1时按1处理
+//
+// 返回值:
+// - *cwe.Registry: 生成的注册表
+func Generate(seed int64, size int) *cwe.Registry {
+	if size < 1 {
+		size = 1
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	severities := []string{"High", "Medium", "Low"}
+
+	nodes := make([]Node, 0, size)
+	nodes = append(nodes, Node{ID: "CWE-1000", Name: "Research Concepts"})
+	for number := 1; len(nodes) < size; number++ {
+		if number == 1000 {
+			continue
+		}
+		nodes = append(nodes, Node{
+			ID:       fmt.Sprintf("CWE-%d", number),
+			Name:     fmt.Sprintf("Generated Weakness %d", number),
+			Parent:   nodes[rng.Intn(len(nodes))].ID,
+			Severity: severities[rng.Intn(len(severities))],
+		})
+	}
+
+	return MustBuild(nodes)
+}
@@ -0,0 +1,102 @@
+package cwetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/scagogogo/cwe"
+)
+
+func TestMiniTree(t *testing.T) {
+	registry := MiniTree()
+
+	if registry.Root == nil || registry.Root.ID != "CWE-1000" {
+		t.Fatalf("根节点应为CWE-1000，实际为%v", registry.Root)
+	}
+	if len(registry.Entries) != 4 {
+		t.Errorf("应有4个条目，实际为%d", len(registry.Entries))
+	}
+
+	input := registry.Entries["CWE-20"]
+	if input.Parent != registry.Root || len(input.Children) != 2 {
+		t.Errorf("CWE-20的父子关系不正确")
+	}
+	if input.Children[0].ID != "CWE-79" || input.Children[1].ID != "CWE-89" {
+		t.Errorf("子节点应按节点表顺序添加")
+	}
+	if registry.Entries["CWE-79"].Severity != "High" {
+		t.Errorf("CWE-79的严重性应为High")
+	}
+
+	// 每次调用返回独立的注册表
+	registry.Entries["CWE-79"].Name = "modified"
+	if MiniTree().Entries["CWE-79"].Name != "Cross-site Scripting" {
+		t.Error("修改返回的注册表不应影响之后的调用")
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	tests := map[string][]Node{
+		"重复ID":   {{ID: "CWE-1"}, {ID: "CWE-1", Parent: "CWE-1"}},
+		"父节点不存在": {{ID: "CWE-1"}, {ID: "CWE-2", Parent: "CWE-3"}},
+		"多个根节点":  {{ID: "CWE-1"}, {ID: "CWE-2"}},
+	}
+	for name, nodes := range tests {
+		if _, err := Build(nodes); err == nil {
+			t.Errorf("%s: 应返回错误", name)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustBuild出错时应panic")
+		}
+	}()
+	MustBuild(tests["多个根节点"])
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	snapshot := func(registry *cwe.Registry) string {
+		data, err := json.Marshal(cwe.NewRegistrySnapshot(registry))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	first := Generate(42, 1200)
+	if len(first.Entries) != 1200 {
+		t.Fatalf("应生成1200个条目，实际为%d", len(first.Entries))
+	}
+	if snapshot(first) != snapshot(Generate(42, 1200)) {
+		t.Error("相同的种子应生成相同的树")
+	}
+	if snapshot(first) == snapshot(Generate(7, 1200)) {
+		t.Error("不同的种子应生成不同的树")
+	}
+
+	// 所有节点都能到达根节点
+	for id, entry := range first.Entries {
+		if entry.GetRoot() != first.Root {
+			t.Fatalf("%s无法到达根节点", id)
+		}
+	}
+
+	if got := Generate(1, 0); len(got.Entries) != 1 || got.Root == nil {
+		t.Errorf("size<1时应只生成根节点")
+	}
+}
+
+func ExampleMiniTree() {
+	registry := MiniTree()
+	for _, child := range registry.Entries["CWE-20"].Children {
+		fmt.Println(child.ID, child.Name)
+	}
+	fmt.Println(reflect.DeepEqual(registry.Entries["CWE-79"].Parent, registry.Entries["CWE-20"]))
+	// Output:
+	// CWE-79 Cross-site Scripting
+	// CWE-89 SQL Injection
+	// true
+}
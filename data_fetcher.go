@@ -1,22 +1,73 @@
 package cwe
 
+import "time"
+
 // DataFetcher 提供从API获取CWE数据并转换为本地数据结构的功能
 type DataFetcher struct {
 	client *APIClient
+
+	// hooks 构建树时在每个节点抓取前后调用的钩子
+	hooks FetchHooks
+
+	// failOnEmptyView 为true时视图没有子节点会使构建失败
+	failOnEmptyView bool
+
+	// nvd FetchByCVE使用的NVD客户端，为nil时按需创建
+	nvd *NVDClient
+
+	// logger 记录树构建进度的日志记录器，为nil时不记录日志
+	logger Logger
+
+	// tracerProvider 记录树构建span的TracerProvider，为nil时使用API客户端的设置
+	tracerProvider TracerProvider
+
+	// batchSize FetchMultiple每个请求包含的ID数量，<=0时使用DefaultBatchSize
+	batchSize int
+
+	// batchConcurrency FetchMultiple同时进行的请求数，<=0时使用DefaultBatchConcurrency
+	batchConcurrency int
+
+	// treeErrorMode 构建树时单个节点获取失败的处理方式
+	treeErrorMode TreeErrorMode
+
+	// capec FetchRelatedAttackPatterns补充攻击模式详情使用的目录，为nil时使用内置映射
+	capec *CAPECCatalog
+
+	// store FetchWeakness、FetchCategory和FetchView读写的持久化存储，为nil时不使用存储
+	store *storeState
+
+	// storeMaxAge 从存储读取的条目的最长时间，<=0时不限制，见SetStoreMaxAge
+	storeMaxAge time.Duration
+
+	// flights 合并对同一条目的并发获取
+	flights flightGroup
+
+	// version 固定的CWE版本和服务器的CWE版本，见PinVersion
+	version versionState
+
+	// search Search回退时使用的本地注册表和服务器是否支持搜索
+	search searchState
+
+	// stale 从API获取失败时是否回退到存储中的数据，见SetStaleFallback
+	stale staleFallbackState
 }
 
 // NewDataFetcher 创建新的数据获取器
-func NewDataFetcher() *DataFetcher {
-	return &DataFetcher{
-		client: NewAPIClient(),
-	}
+// 默认使用NewAPIClient创建的客户端访问官方API，可通过WithBaseURL等选项调整
+func NewDataFetcher(options ...DataFetcherOption) *DataFetcher {
+	return NewDataFetcherWithClient(NewAPIClient(), options...)
 }
 
 // NewDataFetcherWithClient 使用自定义API客户端创建数据获取器
-func NewDataFetcherWithClient(client *APIClient) *DataFetcher {
-	return &DataFetcher{
+// options会修改client的配置，如WithBaseURL设置client的基础URL
+func NewDataFetcherWithClient(client *APIClient, options ...DataFetcherOption) *DataFetcher {
+	f := &DataFetcher{
 		client: client,
 	}
+	for _, option := range options {
+		option(f)
+	}
+	return f
 }
 
 // GetCurrentVersion 获取当前CWE版本
@@ -3,53 +3,135 @@ package cwe
 import "fmt"
 
 // FetchWeakness 获取特定ID的弱点并转换为CWE结构
+// 通过SetStore设置了存储时先从存储读取，从API获取的结果会写入存储。
+// 多个goroutine同时获取同一ID时只发送一次请求，除第一个调用者外都得到结果的副本。
+// 启用SetStaleFallback后获取失败时可能同时返回存储中的条目和*StaleDataError。
 func (f *DataFetcher) FetchWeakness(id string) (*CWE, error) {
+	if cwe, ok := f.loadFromStore(id); ok {
+		return cwe, nil
+	}
+	cwe, err := f.flights.do("weakness/"+normalizeEntryID(id), func() (*CWE, error) {
+		cwe, _, err := f.FetchWeaknessFull(id)
+		if err == nil {
+			f.saveToStore(cwe)
+		}
+		return cwe, err
+	})
+	return f.fallbackToStale(id, cwe, err)
+}
+
+// FetchWeaknessFull 获取特定ID的弱点，同时返回转换后的CWE结构和API返回的完整弱点信息
+//
+// 方法功能:
+// FetchWeakness只保留适合构建树的字段，相关弱点、常见影响、检测方法、已观察到的实例等
+// 只能通过CWEWeakness获取。本方法只发送一次请求，返回的两个值来自同一个响应，
+// 调用方不必再用APIClient重新获取。
+//
+// 参数:
+// - id: string - 弱点ID，支持ParseCWEID接受的所有格式
+//
+// 返回值:
+// - *CWE: 转换后的CWE结构，可以注册到Registry中
+// - *CWEWeakness: API返回的完整弱点信息，受APIClient.SetFieldMask影响
+// - error: ID无效或获取失败时返回错误
+//
+// 使用示例:
+// ```go
+// entry, weakness, err := fetcher.FetchWeaknessFull("79")
+//
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// registry.Register(entry)
+//
+//	for _, consequence := range weakness.CommonConsequences {
+//	    fmt.Println(consequence.Scope, consequence.Impact)
+//	}
+//
+// ```
+func (f *DataFetcher) FetchWeaknessFull(id string) (*CWE, *CWEWeakness, error) {
 	// 尝试规范化ID
 	normalizedID, err := ParseCWEID(id)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// 从API获取数据
 	weakness, err := f.client.GetWeakness(normalizedID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// 创建CWE实例
 	cwe, err := f.convertToCWE(weakness)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if err := f.tagVersion(cwe); err != nil {
+		return nil, nil, err
 	}
 
-	return cwe, nil
+	return cwe, weakness, nil
 }
 
 // FetchCategory 获取特定ID的类别并转换为CWE结构
+// 与FetchWeakness相同，设置了存储时会读写存储，并发获取同一ID时只发送一次请求，可能回退到存储中的数据
 func (f *DataFetcher) FetchCategory(id string) (*CWE, error) {
+	if cwe, ok := f.loadFromStore(id); ok {
+		return cwe, nil
+	}
+	cwe, err := f.flights.do("category/"+normalizeEntryID(id), func() (*CWE, error) {
+		cwe, _, err := f.FetchCategoryFull(id)
+		if err == nil {
+			f.saveToStore(cwe)
+		}
+		return cwe, err
+	})
+	return f.fallbackToStale(id, cwe, err)
+}
+
+// FetchCategoryFull 获取特定ID的类别，同时返回转换后的CWE结构和API返回的完整类别信息
+// 与FetchWeaknessFull类似，完整信息中包含成员列表和映射注释等FetchCategory不保留的字段
+func (f *DataFetcher) FetchCategoryFull(id string) (*CWE, *CWECategory, error) {
 	// 尝试规范化ID
 	normalizedID, err := ParseCWEID(id)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// 从API获取数据
 	category, err := f.client.GetCategory(normalizedID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// 创建CWE实例
 	cwe, err := f.convertCategoryToCWE(category)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if err := f.tagVersion(cwe); err != nil {
+		return nil, nil, err
 	}
 
-	return cwe, nil
+	return cwe, category, nil
 }
 
 // FetchView 获取特定ID的视图并转换为CWE结构
+// 与FetchWeakness相同，设置了存储时会读写存储，并发获取同一ID时只发送一次请求，可能回退到存储中的数据
 func (f *DataFetcher) FetchView(id string) (*CWE, error) {
+	if cwe, ok := f.loadFromStore(id); ok {
+		return cwe, nil
+	}
+	cwe, err := f.flights.do("view/"+normalizeEntryID(id), func() (*CWE, error) {
+		return f.fetchView(id)
+	})
+	return f.fallbackToStale(id, cwe, err)
+}
+
+// fetchView 从API获取视图，获取成功时写入存储
+func (f *DataFetcher) fetchView(id string) (*CWE, error) {
 	// 尝试规范化ID
 	normalizedID, err := ParseCWEID(id)
 	if err != nil {
@@ -67,20 +149,24 @@ func (f *DataFetcher) FetchView(id string) (*CWE, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := f.tagVersion(cwe); err != nil {
+		return nil, err
+	}
 
+	f.saveToStore(cwe)
 	return cwe, nil
 }
 
 // FetchCWEByIDWithRelations 获取一个CWE，并包含其关系
 func (f *DataFetcher) FetchCWEByIDWithRelations(id string, viewID string) (*CWE, error) {
 	// 首先获取主要CWE
-	cwe, err := f.FetchWeakness(id)
+	cwe, err := f.acceptStale(f.FetchWeakness(id))
 	if err != nil {
 		// 尝试作为类别
-		cwe, err = f.FetchCategory(id)
+		cwe, err = f.acceptStale(f.FetchCategory(id))
 		if err != nil {
 			// 尝试作为视图
-			cwe, err = f.FetchView(id)
+			cwe, err = f.acceptStale(f.FetchView(id))
 			if err != nil {
 				return nil, fmt.Errorf("无法获取ID为%s的CWE: %w", id, err)
 			}
@@ -97,16 +183,37 @@ func (f *DataFetcher) FetchCWEByIDWithRelations(id string, viewID string) (*CWE,
 	return cwe, nil
 }
 
+// PopulateRelations 通过GetRelations获取CWE的类型化关系并填充Relations字段
+//
+// 获取弱点时Relations已根据响应中的related_weaknesses填充，通常不需要再调用此方法；
+// 在使用SetFieldMask丢弃了FieldRelatedWeaknesses，或CWE来自快照等其他来源时，
+// 可以用它单独补充关系。viewID不为空时只保留该视图中的关系。
+// 获取失败时返回错误，cwe.Relations保持不变。
+func (f *DataFetcher) PopulateRelations(cwe *CWE, viewID string) error {
+	relations, err := f.client.GetRelations(cwe.ID, viewID)
+	if err != nil {
+		return err
+	}
+	cwe.Relations = relations
+	return nil
+}
+
 // convertToCWE 将API返回的弱点转换为CWE结构
 func (f *DataFetcher) convertToCWE(weakness *CWEWeakness) (*CWE, error) {
 	if weakness == nil {
 		return nil, fmt.Errorf("弱点信息为空")
 	}
 
-	cwe := NewCWE(weakness.ID, weakness.Name)
+	cwe := NewCWE(normalizeEntryID(weakness.ID), weakness.Name)
 	cwe.Description = weakness.Description
 	cwe.URL = weakness.URL
 	cwe.Severity = weakness.Severity
+	cwe.Status = weakness.Status
+	cwe.Abstraction = weakness.Abstraction
+	cwe.Language = weakness.Language
+	cwe.MappingNotes = normalizeMappingNotes(weakness.MappingNotes)
+	cwe.raw = weakness.raw
+	cwe.LastModified = weakness.LastModified()
 
 	// 处理缓解措施
 	if len(weakness.Mitigations) > 0 {
@@ -115,6 +222,7 @@ func (f *DataFetcher) convertToCWE(weakness *CWEWeakness) (*CWE, error) {
 			mitigations = append(mitigations, m.Description)
 		}
 		cwe.Mitigations = mitigations
+		cwe.MitigationDetails = copyMitigations(weakness.Mitigations)
 	}
 
 	// 处理示例
@@ -124,6 +232,16 @@ func (f *DataFetcher) convertToCWE(weakness *CWEWeakness) (*CWE, error) {
 			examples = append(examples, e.Description)
 		}
 		cwe.Examples = examples
+		cwe.ObservedExamples = append([]CWEObservedExample(nil), weakness.ObservedExamples...)
+	}
+
+	cwe.Relations = normalizeRelations(weakness.RelatedWeaknesses)
+
+	// 处理替代术语
+	for _, term := range weakness.AlternateTerms {
+		if term.Term != "" {
+			cwe.AlternateTerms = append(cwe.AlternateTerms, term.Term)
+		}
 	}
 
 	return cwe, nil
@@ -135,9 +253,14 @@ func (f *DataFetcher) convertCategoryToCWE(category *CWECategory) (*CWE, error)
 		return nil, fmt.Errorf("类别信息为空")
 	}
 
-	cwe := NewCWE(category.ID, category.Name)
+	cwe := NewCWE(normalizeEntryID(category.ID), category.Name)
 	cwe.Description = category.Description
 	cwe.URL = category.URL
+	cwe.Status = category.Status
+	cwe.Language = category.Language
+	cwe.MappingNotes = normalizeMappingNotes(category.MappingNotes)
+	cwe.raw = category.raw
+	cwe.LastModified = category.LastModified()
 
 	return cwe, nil
 }
@@ -148,9 +271,23 @@ func (f *DataFetcher) convertViewToCWE(view *CWEView) (*CWE, error) {
 		return nil, fmt.Errorf("视图信息为空")
 	}
 
-	cwe := NewCWE(view.ID, view.Name)
+	cwe := NewCWE(normalizeEntryID(view.ID), view.Name)
 	cwe.Description = view.Description
 	cwe.URL = view.URL
+	cwe.Status = view.Status
+	cwe.Language = view.Language
+	cwe.MappingNotes = normalizeMappingNotes(view.MappingNotes)
+	cwe.raw = view.raw
+	cwe.LastModified = view.LastModified()
 
 	return cwe, nil
 }
+
+// normalizeEntryID 将API返回的ID规范化为"CWE-数字"格式
+// 官方API返回的ID不带"CWE-"前缀(如"79")；无法解析的ID保持原样
+func normalizeEntryID(id string) string {
+	if normalized, err := ParseCWEID(id); err == nil {
+		return normalized
+	}
+	return id
+}
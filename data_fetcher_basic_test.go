@@ -2,8 +2,10 @@ package cwe
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 )
 
@@ -397,3 +399,68 @@ func setupFetchRelationsTestServer() *httptest.Server {
 
 	return httptest.NewServer(handler)
 }
+
+func TestFetchWeaknessFull(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/cwe/weakness/CWE-79":
+			fmt.Fprint(w, `{"weaknesses": [{
+				"id": "79",
+				"name": "Cross-site Scripting",
+				"related_weaknesses": [{"nature": "ChildOf", "cwe_id": "74", "view_id": "1000", "ordinal": "Primary"}],
+				"common_consequences": [{"scope": ["Confidentiality"], "impact": ["Read Application Data"]}],
+				"detection_methods": [{"method": "Automated Static Analysis", "effectiveness": "High"}],
+				"observed_examples": [{"reference": "CVE-2021-25926", "description": "Python Library Manager did not sufficiently neutralize a user-supplied search term"}]
+			}]}`)
+		case "/cwe/category/CWE-1019":
+			fmt.Fprint(w, `{"categories": [{"id": "1019", "name": "Validate Inputs", "members": ["20", "79"]}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewAPIClientWithOptions(server.URL, DefaultTimeout, NewHTTPRateLimiter(0))
+	fetcher := NewDataFetcherWithClient(client)
+
+	entry, weakness, err := fetcher.FetchWeaknessFull("79")
+	if err != nil {
+		t.Fatalf("FetchWeaknessFull失败: %v", err)
+	}
+	if atomic.LoadInt32(&requests) != 1 {
+		t.Errorf("应只发送1次请求，实际为%d", requests)
+	}
+	if entry.ID != "CWE-79" || entry.Name != "Cross-site Scripting" {
+		t.Errorf("CWE结构不正确: %s %s", entry.ID, entry.Name)
+	}
+	if len(entry.Relations) != 1 || entry.Relations[0].CweID != "CWE-74" {
+		t.Errorf("CWE结构应包含规范化的关系，实际为%v", entry.Relations)
+	}
+	if len(weakness.CommonConsequences) != 1 || weakness.CommonConsequences[0].Impact[0] != "Read Application Data" {
+		t.Errorf("完整信息应包含常见影响，实际为%v", weakness.CommonConsequences)
+	}
+	if len(weakness.DetectionMethods) != 1 || weakness.DetectionMethods[0].Method != "Automated Static Analysis" {
+		t.Errorf("完整信息应包含检测方法，实际为%v", weakness.DetectionMethods)
+	}
+	if len(weakness.ObservedExamples) != 1 || weakness.ObservedExamples[0].Reference != "CVE-2021-25926" {
+		t.Errorf("完整信息应包含已观察到的实例，实际为%v", weakness.ObservedExamples)
+	}
+
+	category, full, err := fetcher.FetchCategoryFull("CWE-1019")
+	if err != nil {
+		t.Fatalf("FetchCategoryFull失败: %v", err)
+	}
+	if category.ID != "CWE-1019" || len(full.Members) != 2 {
+		t.Errorf("类别信息不正确: %s %v", category.ID, full.Members)
+	}
+
+	if _, _, err := fetcher.FetchWeaknessFull("invalid"); err == nil {
+		t.Error("无效的ID应返回错误")
+	}
+	if _, _, err := fetcher.FetchWeaknessFull("404"); err == nil {
+		t.Error("不存在的弱点应返回错误")
+	}
+}
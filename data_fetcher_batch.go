@@ -0,0 +1,134 @@
+package cwe
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultBatchSize 是FetchMultiple每个请求包含的默认ID数量
+// 所有ID拼接在同一个URL中，过多的ID会超出服务器的URL长度限制
+const DefaultBatchSize = 50
+
+// DefaultBatchConcurrency 是FetchMultiple默认同时进行的请求数
+const DefaultBatchConcurrency = 4
+
+// BatchError 表示FetchMultiple中部分ID获取失败
+//
+// FetchMultiple返回BatchError时，注册表中仍包含所有获取成功的条目。
+// 整个分批请求失败时，该批次中的每个ID都记录为该请求的错误；
+// 请求成功但响应中缺少的ID记录为可通过errors.Is(err, ErrNotFound)判断的错误。
+type BatchError struct {
+	// Failures 获取失败的ID(CWE-数字格式)及其原因
+	Failures map[string]error
+}
+
+func (e *BatchError) Error() string {
+	ids := e.FailedIDs()
+	const maxListed = 5
+	if len(ids) > maxListed {
+		return fmt.Sprintf("%d个CWE获取失败: %s 等，%s: %v",
+			len(ids), strings.Join(ids[:maxListed], ", "), ids[0], e.Failures[ids[0]])
+	}
+	return fmt.Sprintf("%d个CWE获取失败: %s，%s: %v",
+		len(ids), strings.Join(ids, ", "), ids[0], e.Failures[ids[0]])
+}
+
+// FailedIDs 返回获取失败的ID，按CWE编号排序
+func (e *BatchError) FailedIDs() []string {
+	ids := make([]string, 0, len(e.Failures))
+	for id := range e.Failures {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return compareCWEIDs(ids[i], ids[j]) < 0
+	})
+	return ids
+}
+
+// SetBatchSize 设置FetchMultiple每个请求包含的ID数量
+//
+// 方法功能:
+// FetchMultiple将ID列表按该大小分批，每批拼接为一个/cwe/{ids}请求。
+// 当API对单个请求的ID数量有限制，或通过URL长度受限的代理访问时，可以调小该值。
+//
+// 参数:
+// - size: int - 每批的ID数量，<=0时使用DefaultBatchSize
+func (f *DataFetcher) SetBatchSize(size int) {
+	f.batchSize = size
+}
+
+// SetBatchConcurrency 设置FetchMultiple同时进行的请求数
+//
+// 方法功能:
+// 分批请求并发执行，实际请求速率仍受客户端的速率限制器约束，
+// 因此提高并发数只能减少等待响应的时间，不会超出速率限制。
+//
+// 参数:
+// - concurrency: int - 同时进行的请求数，<=0时使用DefaultBatchConcurrency
+func (f *DataFetcher) SetBatchConcurrency(concurrency int) {
+	f.batchConcurrency = concurrency
+}
+
+// fetchBatches 分批并发获取ids，返回获取成功的条目和失败的ID
+// ids必须是已规范化且去重的ID
+func (f *DataFetcher) fetchBatches(ids []string) (map[string]*CWEWeakness, map[string]error) {
+	size := f.batchSize
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+	concurrency := f.batchConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+
+	batches := make([][]string, 0, (len(ids)+size-1)/size)
+	for start := 0; start < len(ids); start += size {
+		end := start + size
+		if end > len(ids) {
+			end = len(ids)
+		}
+		batches = append(batches, ids[start:end])
+	}
+
+	var (
+		mutex    sync.Mutex
+		wg       sync.WaitGroup
+		results  = make(map[string]*CWEWeakness, len(ids))
+		failures = make(map[string]error)
+		slots    = make(chan struct{}, concurrency)
+	)
+	for _, batch := range batches {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(batch []string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			data, err := f.client.GetCWEs(batch)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				for _, id := range batch {
+					failures[id] = err
+				}
+				return
+			}
+			for key, weakness := range data {
+				if weakness != nil {
+					results[normalizeEntryID(key)] = weakness
+				}
+			}
+			for _, id := range batch {
+				if _, exists := results[id]; !exists {
+					failures[id] = &notFoundError{id: id}
+				}
+			}
+		}(batch)
+	}
+	wg.Wait()
+
+	return results, failures
+}
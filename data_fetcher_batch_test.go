@@ -0,0 +1,139 @@
+package cwe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newBatchTestServer 创建按/cwe/{ids}返回条目的服务器，failing中的ID所在的批次返回500，
+// missing中的ID不出现在响应中
+func newBatchTestServer(failing, missing map[string]bool) (*httptest.Server, *[]string) {
+	var mutex sync.Mutex
+	requests := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idList := strings.TrimPrefix(r.URL.Path, "/cwe/")
+		mutex.Lock()
+		requests = append(requests, idList)
+		mutex.Unlock()
+
+		cwes := make(map[string]interface{})
+		for _, id := range strings.Split(idList, ",") {
+			if failing[id] {
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+			if missing[id] {
+				continue
+			}
+			cwes[strings.TrimPrefix(id, "CWE-")] = map[string]interface{}{
+				"name": "Name of " + id,
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"cwes": cwes})
+	}))
+	return server, &requests
+}
+
+func newBatchTestFetcher(url string) *DataFetcher {
+	client := NewAPIClientWithOptions(url, DefaultTimeout, NewHTTPRateLimiter(0))
+	client.GetHTTPClient().SetRetryDelay(time.Millisecond)
+	client.GetHTTPClient().SetMaxRetries(1)
+	return NewDataFetcherWithClient(client)
+}
+
+func TestFetchMultipleChunksRequests(t *testing.T) {
+	server, requests := newBatchTestServer(nil, nil)
+	defer server.Close()
+
+	fetcher := newBatchTestFetcher(server.URL)
+	fetcher.SetBatchSize(3)
+	fetcher.SetBatchConcurrency(2)
+
+	ids := make([]string, 0, 10)
+	for i := 1; i <= 10; i++ {
+		ids = append(ids, fmt.Sprintf("%d", i))
+	}
+	// 重复的ID只请求一次
+	ids = append(ids, "CWE-1")
+
+	registry, err := fetcher.FetchMultiple(ids)
+	if err != nil {
+		t.Fatalf("FetchMultiple failed: %v", err)
+	}
+	if len(registry.Entries) != 10 {
+		t.Errorf("Expected 10 entries, got %d", len(registry.Entries))
+	}
+	if len(*requests) != 4 {
+		t.Errorf("Expected 4 batched requests, got %d: %v", len(*requests), *requests)
+	}
+	for _, request := range *requests {
+		if n := len(strings.Split(request, ",")); n > 3 {
+			t.Errorf("Batch %q has %d IDs, want at most 3", request, n)
+		}
+	}
+
+	entry, err := registry.GetByID("CWE-7")
+	if err != nil {
+		t.Fatalf("GetByID(CWE-7) failed: %v", err)
+	}
+	if entry.Name != "Name of CWE-7" {
+		t.Errorf("Unexpected name %q", entry.Name)
+	}
+}
+
+func TestFetchMultiplePartialFailure(t *testing.T) {
+	server, _ := newBatchTestServer(map[string]bool{"CWE-4": true}, map[string]bool{"CWE-2": true})
+	defer server.Close()
+
+	fetcher := newBatchTestFetcher(server.URL)
+	fetcher.SetBatchSize(3)
+
+	registry, err := fetcher.FetchMultiple([]string{"1", "2", "3", "4", "5", "6", "7"})
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("Expected *BatchError, got %v", err)
+	}
+	if registry == nil {
+		t.Fatal("Expected partial registry")
+	}
+
+	// 批次[4 5 6]整体失败，2不在响应中
+	want := []string{"CWE-2", "CWE-4", "CWE-5", "CWE-6"}
+	if got := batchErr.FailedIDs(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("FailedIDs() = %v, want %v", got, want)
+	}
+	if !errors.Is(batchErr.Failures["CWE-2"], ErrNotFound) {
+		t.Errorf("Missing ID should wrap ErrNotFound, got %v", batchErr.Failures["CWE-2"])
+	}
+	if errors.Is(batchErr.Failures["CWE-5"], ErrNotFound) {
+		t.Errorf("Failed batch should report the request error, got %v", batchErr.Failures["CWE-5"])
+	}
+	if !strings.Contains(err.Error(), "4个CWE获取失败") {
+		t.Errorf("Unexpected error message: %v", err)
+	}
+
+	for _, id := range []string{"CWE-1", "CWE-3", "CWE-7"} {
+		if _, err := registry.GetByID(id); err != nil {
+			t.Errorf("Expected %s in partial registry: %v", id, err)
+		}
+	}
+	if len(registry.Entries) != 3 {
+		t.Errorf("Expected 3 entries, got %d", len(registry.Entries))
+	}
+}
+
+func TestFetchMultipleInvalidID(t *testing.T) {
+	fetcher := newBatchTestFetcher("http://127.0.0.1:0")
+	if _, err := fetcher.FetchMultiple([]string{"79", "not-an-id"}); err == nil {
+		t.Error("Expected error for invalid ID")
+	}
+}
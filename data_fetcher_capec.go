@@ -0,0 +1,75 @@
+package cwe
+
+import "fmt"
+
+// SetCAPECCatalog 设置FetchRelatedAttackPatterns补充攻击模式详情时使用的CAPEC目录
+// 未设置时使用BundledCAPECCatalog的内置映射
+func (f *DataFetcher) SetCAPECCatalog(catalog *CAPECCatalog) {
+	f.capec = catalog
+}
+
+// FetchRelatedAttackPatterns 从CWE API获取弱点相关的CAPEC攻击模式
+//
+// 方法功能:
+// 获取弱点的related_attack_patterns字段，得到最新的攻击模式编号列表，
+// 再从SetCAPECCatalog设置的目录(默认为内置映射)中补充名称、严重性等详情。
+// 目录中没有的攻击模式只包含ID和URL。与GetRelatedAttackPatterns不同，结果不受内置映射覆盖范围的限制。
+//
+// 参数:
+// - id: string - CWE ID，支持ParseCWEID接受的所有格式
+//
+// 返回值:
+// - []AttackPattern: 按CAPEC编号排序的攻击模式，弱点没有相关攻击模式时为空切片
+// - error: ID无效、获取弱点失败或API返回了无效的CAPEC ID时返回错误
+//
+// 使用示例:
+// ```go
+// file, _ := os.Open("1000.csv")
+// catalog, _ := cwe.LoadCAPECCatalogCSV(file)
+// fetcher.SetCAPECCatalog(catalog)
+//
+// patterns, err := fetcher.FetchRelatedAttackPatterns("CWE-79")
+// ```
+func (f *DataFetcher) FetchRelatedAttackPatterns(id string) ([]AttackPattern, error) {
+	normalizedID, err := ParseCWEID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	weakness, err := f.client.GetWeakness(normalizedID)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := f.capec
+	if catalog == nil {
+		if catalog, err = BundledCAPECCatalog(); err != nil {
+			return nil, err
+		}
+		f.capec = catalog
+	}
+
+	patterns := make([]AttackPattern, 0, len(weakness.RelatedAttackPatterns))
+	seen := make(map[string]bool, len(weakness.RelatedAttackPatterns))
+	for _, rawID := range weakness.RelatedAttackPatterns {
+		capecID, err := ParseCAPECID(rawID)
+		if err != nil {
+			return nil, fmt.Errorf("%s的相关攻击模式%w", normalizedID, err)
+		}
+		if seen[capecID] {
+			continue
+		}
+		seen[capecID] = true
+
+		pattern, err := catalog.Get(capecID)
+		if err != nil {
+			pattern = AttackPattern{
+				ID:  capecID,
+				URL: capecURL(capecID),
+			}
+		}
+		patterns = append(patterns, pattern)
+	}
+	sortAttackPatterns(patterns)
+	return patterns, nil
+}
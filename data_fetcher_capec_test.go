@@ -0,0 +1,68 @@
+package cwe
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestFetchRelatedAttackPatterns(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/cwe/weakness/CWE-89":
+			fmt.Fprint(w, `{"weaknesses": [{"id": "89", "name": "SQL Injection", "related_attack_patterns": ["66", "7", "9999", "66"]}]}`)
+		case "/cwe/weakness/CWE-1":
+			fmt.Fprint(w, `{"weaknesses": [{"id": "1", "name": "No Patterns"}]}`)
+		case "/cwe/weakness/CWE-2":
+			fmt.Fprint(w, `{"weaknesses": [{"id": "2", "name": "Bad Patterns", "related_attack_patterns": ["abc"]}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+
+	patterns, err := fetcher.FetchRelatedAttackPatterns("89")
+	if err != nil {
+		t.Fatalf("FetchRelatedAttackPatterns失败: %v", err)
+	}
+	if got := attackPatternIDs(patterns); !reflect.DeepEqual(got, []string{"CAPEC-7", "CAPEC-66", "CAPEC-9999"}) {
+		t.Errorf("攻击模式 = %v", got)
+	}
+	if patterns[1].Name != "SQL Injection" {
+		t.Errorf("应从内置映射补充名称，实际为%q", patterns[1].Name)
+	}
+	if patterns[2].Name != "" || patterns[2].URL != "https://capec.mitre.org/data/definitions/9999.html" {
+		t.Errorf("目录中没有的攻击模式应只包含ID和URL，实际为%+v", patterns[2])
+	}
+
+	catalog := NewCAPECCatalog()
+	catalog.Add(AttackPattern{ID: "CAPEC-9999", Name: "Custom Pattern"})
+	fetcher.SetCAPECCatalog(catalog)
+	patterns, _ = fetcher.FetchRelatedAttackPatterns("CWE-89")
+	if patterns[2].Name != "Custom Pattern" || patterns[1].Name != "" {
+		t.Errorf("应使用设置的目录补充详情，实际为%+v", patterns)
+	}
+
+	if patterns, err := fetcher.FetchRelatedAttackPatterns("CWE-1"); err != nil || len(patterns) != 0 {
+		t.Errorf("没有相关攻击模式时应返回空切片，实际为%v, %v", patterns, err)
+	}
+	if _, err := fetcher.FetchRelatedAttackPatterns("CWE-2"); err == nil {
+		t.Error("API返回无效的CAPEC ID时应返回错误")
+	}
+	if _, err := fetcher.FetchRelatedAttackPatterns("CWE-404"); err == nil {
+		t.Error("弱点不存在时应返回错误")
+	}
+	if _, err := fetcher.FetchRelatedAttackPatterns("abc"); err == nil {
+		t.Error("ID无效时应返回错误")
+	}
+
+	fetcher.SetFieldMask(FieldsMinimal)
+	if patterns, _ := fetcher.FetchRelatedAttackPatterns("CWE-89"); len(patterns) != 0 {
+		t.Errorf("掩码不包含FieldRelatedAttackPatterns时不应返回攻击模式，实际为%v", attackPatternIDs(patterns))
+	}
+}
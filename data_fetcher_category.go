@@ -0,0 +1,97 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FetchCategoryWithMembers 获取类别并将其成员解析为子节点
+//
+// 方法功能:
+// FetchCategory只保留类别本身的信息，丢弃了API返回的成员列表。
+// FetchCategoryWithMembers获取类别后逐个解析成员(依次尝试作为弱点和类别获取)，
+// 并按注册表的层次结构模式将成员添加为类别的子节点，使按类别浏览(如开发视图CWE-699下的各个类别)
+// 与按视图浏览一样可以沿Children导航。
+// 类别和成员已在注册表中时直接复用，不会重新获取；新获取的条目会注册到注册表。
+// 只解析一层成员，作为成员的类别不会继续展开，需要时可以对其再次调用本方法。
+// 单个成员获取失败时的处理方式与BuildCWETreeWithView相同，见SetTreeErrorMode；抓取钩子同样生效。
+//
+// 参数:
+// - id: string - 类别ID，支持ParseCWEID接受的所有格式
+// - registry: *Registry - 用作缓存并保存结果的注册表，为nil时使用一个新的注册表
+//
+// 返回值:
+// - *CWE: 类别节点，Children包含解析成功的成员
+// - error: ID无效或类别获取失败时返回错误；TreeErrorCollect模式下有成员获取失败时为*MultiError，类别节点仍然返回
+//
+// 使用示例:
+// ```go
+// registry := cwe.NewRegistry()
+// category, err := fetcher.FetchCategoryWithMembers("CWE-1019", registry)
+//
+//	for _, member := range category.Children {
+//	    fmt.Printf("%s: %s\n", member.ID, member.Name)
+//	}
+//
+// ```
+func (f *DataFetcher) FetchCategoryWithMembers(id string, registry *Registry) (*CWE, error) {
+	normalizedID, err := ParseCWEID(id)
+	if err != nil {
+		return nil, err
+	}
+	if registry == nil {
+		registry = NewRegistry()
+	}
+
+	// 成员列表只能从API获取，注册表中已有的类别节点仍然复用
+	fetched, full, err := f.FetchCategoryFull(normalizedID)
+	if err != nil {
+		return nil, fmt.Errorf("获取类别失败: %w", err)
+	}
+	category, err := registry.GetByID(normalizedID)
+	if err != nil {
+		category = fetched
+		if err := registry.Register(category); err != nil {
+			return nil, err
+		}
+	}
+
+	failures := &MultiError{}
+	for _, memberID := range full.Members {
+		memberID = normalizeEntryID(memberID)
+
+		member, err := registry.GetByID(memberID)
+		if err != nil {
+			node, isNew, err := f.fetchTreeNode(registry, category, memberID, "")
+			if err != nil {
+				if errors.Is(err, ErrSkipNode) {
+					continue
+				}
+				if err := f.recordNodeFailure(failures, memberID, category.ID, err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if isNew {
+				if err := registry.Register(node); err != nil {
+					return nil, err
+				}
+			}
+			member = node
+		}
+
+		if containsCWE(category.Children, member) {
+			continue
+		}
+		if err := registry.AddChild(category.ID, member.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	f.log().Debug("解析类别成员", "id", category.ID, "members", len(full.Members), "children", len(category.Children))
+
+	if len(failures.Errors) > 0 {
+		return category, failures
+	}
+	return category, nil
+}
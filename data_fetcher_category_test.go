@@ -0,0 +1,126 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func setupCategoryMembersServer(requests map[string]int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/cwe/category/CWE-1019":
+			fmt.Fprint(w, `{"categories": [{"id": "1019", "name": "Validate Inputs", "members": ["20", "79", "1215", "404"]}]}`)
+		case "/cwe/category/CWE-1215":
+			fmt.Fprint(w, `{"categories": [{"id": "1215", "name": "Data Validation Issues", "members": ["20"]}]}`)
+		case "/cwe/weakness/CWE-20", "/cwe/weakness/CWE-79":
+			id := strings.TrimPrefix(r.URL.Path, "/cwe/weakness/CWE-")
+			fmt.Fprintf(w, `{"weaknesses": [{"id": "%s", "name": "Weakness %s"}]}`, id, id)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestFetchCategoryWithMembers(t *testing.T) {
+	requests := map[string]int{}
+	server := setupCategoryMembersServer(requests)
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+	registry := NewRegistry()
+	registry.SetHierarchyMode(HierarchyMultiParent)
+	cached := NewCWE("CWE-79", "Cross-site Scripting")
+	registry.Register(cached)
+
+	category, err := fetcher.FetchCategoryWithMembers("1019", registry)
+	if err != nil {
+		t.Fatalf("FetchCategoryWithMembers失败: %v", err)
+	}
+
+	if got := cweIDsOf(category.Children); !reflect.DeepEqual(got, []string{"CWE-20", "CWE-79", "CWE-1215"}) {
+		t.Errorf("类别的子节点 = %v", got)
+	}
+	if category.Children[1] != cached {
+		t.Error("注册表中已有的成员应直接复用")
+	}
+	if requests["/cwe/weakness/CWE-79"] != 0 {
+		t.Errorf("注册表中已有的成员不应重新获取，实际请求了%d次", requests["/cwe/weakness/CWE-79"])
+	}
+	if category.Children[2].Name != "Data Validation Issues" {
+		t.Errorf("类别成员应作为类别获取，实际名称为%q", category.Children[2].Name)
+	}
+	if len(category.Children[2].Children) != 0 {
+		t.Error("作为成员的类别不应继续展开")
+	}
+	if _, err := registry.GetByID("CWE-1019"); err != nil {
+		t.Errorf("类别应注册到注册表: %v", err)
+	}
+
+	// 展开成员类别时复用已注册的节点，关系按多父节点模式记录
+	nested, err := fetcher.FetchCategoryWithMembers("CWE-1215", registry)
+	if err != nil {
+		t.Fatalf("FetchCategoryWithMembers失败: %v", err)
+	}
+	if nested != category.Children[2] {
+		t.Error("注册表中已有的类别节点应直接复用")
+	}
+	if requests["/cwe/weakness/CWE-20"] != 1 {
+		t.Errorf("CWE-20应只获取1次，实际为%d次", requests["/cwe/weakness/CWE-20"])
+	}
+	parents, _ := registry.Parents("CWE-20")
+	if got := cweIDsOf(parents); !reflect.DeepEqual(got, []string{"CWE-1019", "CWE-1215"}) {
+		t.Errorf("CWE-20的父节点 = %v", got)
+	}
+
+	// 重复调用不会重复添加子节点
+	if _, err := fetcher.FetchCategoryWithMembers("CWE-1019", registry); err != nil {
+		t.Fatalf("FetchCategoryWithMembers失败: %v", err)
+	}
+	if len(category.Children) != 3 {
+		t.Errorf("重复调用后应仍有3个子节点，实际为%d", len(category.Children))
+	}
+}
+
+func TestFetchCategoryWithMembersErrors(t *testing.T) {
+	server := setupCategoryMembersServer(map[string]int{})
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+
+	if _, err := fetcher.FetchCategoryWithMembers("invalid", nil); err == nil {
+		t.Error("ID无效时应返回错误")
+	}
+	if _, err := fetcher.FetchCategoryWithMembers("CWE-9999", nil); err == nil {
+		t.Error("类别不存在时应返回错误")
+	}
+
+	category, err := fetcher.FetchCategoryWithMembers("CWE-1019", nil)
+	if err != nil {
+		t.Fatalf("默认模式下应跳过获取失败的成员，实际返回%v", err)
+	}
+	if len(category.Children) != 3 {
+		t.Errorf("应有3个子节点，实际为%d", len(category.Children))
+	}
+
+	fetcher.SetTreeErrorMode(TreeErrorCollect)
+	category, err = fetcher.FetchCategoryWithMembers("CWE-1019", nil)
+	var multiErr *MultiError
+	if !errors.As(err, &multiErr) || len(multiErr.Errors) != 1 || !strings.Contains(multiErr.Errors[0].Error(), "CWE-404") {
+		t.Fatalf("收集模式下应返回包含CWE-404的MultiError，实际为%v", err)
+	}
+	if category == nil || len(category.Children) != 3 {
+		t.Error("收集模式下仍应返回类别节点")
+	}
+
+	fetcher.SetTreeErrorMode(TreeErrorStrict)
+	if _, err := fetcher.FetchCategoryWithMembers("CWE-1019", nil); !errors.As(err, new(*NodeError)) {
+		t.Errorf("严格模式下应返回NodeError，实际为%v", err)
+	}
+}
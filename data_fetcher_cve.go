@@ -0,0 +1,62 @@
+package cwe
+
+import "fmt"
+
+// SetNVDClient 设置FetchByCVE查询CVE映射时使用的NVD客户端
+// 未设置时FetchByCVE第一次调用会使用NewNVDClient创建的默认客户端
+func (f *DataFetcher) SetNVDClient(client *NVDClient) {
+	f.nvd = client
+}
+
+// FetchByCVE 获取CVE映射的CWE条目
+//
+// 方法功能:
+// 通过NVD API查询CVE映射的CWE ID，再依次将每个ID作为弱点或类别从CWE API获取。
+// 返回顺序与NVDClient.GetCVEWeaknesses相同，即NVD自己的分析在前。
+//
+// 参数:
+// - cveID: string - CVE ID，如"CVE-2021-44228"，不区分大小写
+//
+// 返回值:
+// - []*CWE: CVE映射的CWE条目，CVE没有映射具体CWE时为空切片
+// - error: CVE ID格式无效、CVE不存在(包装ErrCVENotFound)、NVD请求失败或某个CWE获取失败时返回错误
+//
+// 使用示例:
+// ```go
+// fetcher := cwe.NewDataFetcher()
+//
+// nvd := cwe.NewNVDClient()
+// nvd.SetAPIKey(os.Getenv("NVD_API_KEY"))
+// fetcher.SetNVDClient(nvd)
+//
+// entries, err := fetcher.FetchByCVE("CVE-2021-44228")
+//
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	for _, entry := range entries {
+//	    fmt.Printf("%s: %s\n", entry.ID, entry.Name)
+//	}
+//
+// ```
+func (f *DataFetcher) FetchByCVE(cveID string) ([]*CWE, error) {
+	if f.nvd == nil {
+		f.nvd = NewNVDClient()
+	}
+
+	ids, err := f.nvd.GetCVEWeaknesses(cveID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*CWE, 0, len(ids))
+	for _, id := range ids {
+		entry, err := f.fetchWeaknessOrCategory(id)
+		if err != nil {
+			return nil, fmt.Errorf("获取%s映射的%s失败: %w", cveID, id, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,87 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// setupCVEFetchServer 创建提供CWE-79弱点和CWE-20弱点的测试服务器，CWE-917不存在
+func setupCVEFetchServer() *httptest.Server {
+	mux := http.NewServeMux()
+	weakness := func(id, name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"weaknesses": [{"id": %q, "name": %q}]}`, id, name)
+		}
+	}
+	for _, path := range []string{"79", "CWE-79"} {
+		mux.HandleFunc("/cwe/weakness/"+path, weakness("79", "Cross-site Scripting"))
+	}
+	for _, path := range []string{"20", "CWE-20"} {
+		mux.HandleFunc("/cwe/weakness/"+path, weakness("20", "Improper Input Validation"))
+	}
+	return httptest.NewServer(mux)
+}
+
+func newCVETestFetcher(cweURL, nvdURL string) *DataFetcher {
+	client := NewAPIClientWithOptions(cweURL, DefaultTimeout, NewHTTPRateLimiter(0))
+	client.GetHTTPClient().SetRetryDelay(time.Millisecond)
+	fetcher := NewDataFetcherWithClient(client)
+	fetcher.SetNVDClient(newNVDTestClient(nvdURL))
+	return fetcher
+}
+
+func TestFetchByCVE(t *testing.T) {
+	cweServer := setupCVEFetchServer()
+	defer cweServer.Close()
+	nvdServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"vulnerabilities": [{"cve": {"id": %q, "weaknesses": [
+			{"source": "nvd@nist.gov", "type": "Primary", "description": [{"lang": "en", "value": "CWE-79"}]},
+			{"source": "cna@example.com", "type": "Secondary", "description": [{"lang": "en", "value": "CWE-20"}]}
+		]}}]}`, r.URL.Query().Get("cveId"))
+	}))
+	defer nvdServer.Close()
+
+	fetcher := newCVETestFetcher(cweServer.URL, nvdServer.URL)
+
+	entries, err := fetcher.FetchByCVE("CVE-2023-1234")
+	if err != nil {
+		t.Fatalf("FetchByCVE失败: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("期望2个条目，实际为%d", len(entries))
+	}
+	if entries[0].ID != "CWE-79" || entries[0].Name != "Cross-site Scripting" {
+		t.Errorf("第一个条目应为CWE-79，实际为%s %s", entries[0].ID, entries[0].Name)
+	}
+	if entries[1].ID != "CWE-20" {
+		t.Errorf("第二个条目应为CWE-20，实际为%s", entries[1].ID)
+	}
+}
+
+func TestFetchByCVEErrors(t *testing.T) {
+	cweServer := setupCVEFetchServer()
+	defer cweServer.Close()
+	nvdServer := setupNVDServer()
+	defer nvdServer.Close()
+
+	fetcher := newCVETestFetcher(cweServer.URL, nvdServer.URL)
+
+	if _, err := fetcher.FetchByCVE("CVE-2099-99999"); !errors.Is(err, ErrCVENotFound) {
+		t.Errorf("应返回ErrCVENotFound，实际为%v", err)
+	}
+
+	// CVE-2021-44228映射的CWE-917在CWE API中不存在
+	if _, err := fetcher.FetchByCVE("CVE-2021-44228"); err == nil {
+		t.Error("CWE获取失败时应返回错误")
+	}
+
+	entries, err := fetcher.FetchByCVE("CVE-2020-0001")
+	if err != nil || len(entries) != 0 {
+		t.Errorf("没有映射具体CWE时应返回空切片，实际为%v, %v", entries, err)
+	}
+}
@@ -0,0 +1,227 @@
+package cwe
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// researchViewID 研究视图(Research Concepts)的ID，弱点之间的ChildOf层次关系以此视图为准
+const researchViewID = "CWE-1000"
+
+// detectionEffectivenessRank 检测方法有效性的排序，数值越大越有效，未知的取值排在最后
+var detectionEffectivenessRank = map[string]int{
+	"high":          6,
+	"moderate":      5,
+	"soar partial":  4,
+	"opportunistic": 3,
+	"limited":       2,
+	"none":          1,
+}
+
+// DetectionGuidance 弱点的检测指导，汇总了弱点本身及其祖先弱点的检测方法
+type DetectionGuidance struct {
+	// ID 弱点ID，格式为"CWE-数字"
+	ID string `json:"id"`
+
+	// Name 弱点名称
+	Name string `json:"name"`
+
+	// Methods 按检测方法名称合并后的检测方法，按有效性从高到低、距离从近到远、名称排序
+	Methods []DetectionGuidanceMethod `json:"methods"`
+}
+
+// DetectionGuidanceMethod 检测指导中的一种检测方法
+type DetectionGuidanceMethod struct {
+	// Method 检测方法名称，如"Automated Static Analysis"
+	Method string `json:"method"`
+
+	// Description 距离最近的弱点对该方法的描述
+	Description string `json:"description,omitempty"`
+
+	// Effectiveness 所有来源中最高的有效性，如"High"
+	Effectiveness string `json:"effectiveness,omitempty"`
+
+	// EffectivenessNotes 与Effectiveness来自同一来源的有效性备注
+	EffectivenessNotes string `json:"effectiveness_notes,omitempty"`
+
+	// Sources 列出了该方法的弱点ID，按距离从近到远排列
+	Sources []string `json:"sources"`
+
+	// Distance 最近来源与查询弱点的距离，0表示查询的弱点本身，1表示父弱点，依此类推
+	Distance int `json:"distance"`
+}
+
+// Inherited 判断检测方法是否只来自祖先弱点
+func (m DetectionGuidanceMethod) Inherited() bool {
+	return m.Distance > 0
+}
+
+// FetchDetectionMethods 获取弱点的检测方法
+//
+// 方法功能:
+// 等同于FetchWeaknessFull后读取CWEWeakness.DetectionMethods。
+// 通过SetFieldMask丢弃了FieldDetectionMethods时结果为空。
+//
+// 参数:
+// - id: string - CWE ID，支持ParseCWEID接受的所有格式
+//
+// 返回值:
+// - []CWEDetectionMethod: API返回的检测方法，弱点没有检测方法时为空切片
+// - error: ID无效或获取弱点失败时返回错误
+//
+// 使用示例:
+// ```go
+// methods, err := fetcher.FetchDetectionMethods("CWE-89")
+//
+//	for _, method := range methods {
+//	    fmt.Println(method.Method, method.Effectiveness)
+//	}
+//
+// ```
+func (f *DataFetcher) FetchDetectionMethods(id string) ([]CWEDetectionMethod, error) {
+	_, weakness, err := f.FetchWeaknessFull(id)
+	if err != nil {
+		return nil, err
+	}
+	methods := make([]CWEDetectionMethod, 0, len(weakness.DetectionMethods))
+	return append(methods, weakness.DetectionMethods...), nil
+}
+
+// GetDetectionGuidance 获取弱点及其祖先弱点的检测方法，生成检测指导
+//
+// 方法功能:
+// 很多具体的弱点(如Variant)没有列出检测方法，适用的方法记录在更抽象的父弱点中。
+// 本方法沿研究视图(CWE-1000)中的ChildOf关系广度优先地获取所有祖先弱点，
+// 按检测方法名称(不区分大小写)合并，有效性取所有来源中最高的一个，描述取距离最近的来源。
+// 每个弱点只请求一次，祖先关系依赖related_weaknesses字段，
+// 通过SetFieldMask丢弃了FieldRelatedWeaknesses时只包含弱点本身的检测方法，
+// 丢弃了FieldDetectionMethods时结果为空。
+//
+// 参数:
+// - id: string - CWE ID，支持ParseCWEID接受的所有格式
+//
+// 返回值:
+// - *DetectionGuidance: 检测指导，没有任何检测方法时Methods为空切片
+// - error: ID无效或获取弱点及任一祖先弱点失败时返回错误
+//
+// 使用示例:
+// ```go
+// guidance, err := fetcher.GetDetectionGuidance("CWE-564")
+//
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	for _, method := range guidance.Methods {
+//	    fmt.Printf("%s (%s) 来自%v\n", method.Method, method.Effectiveness, method.Sources)
+//	}
+//
+// ```
+func (f *DataFetcher) GetDetectionGuidance(id string) (*DetectionGuidance, error) {
+	lineage, err := f.fetchWeaknessLineage(id)
+	if err != nil {
+		return nil, err
+	}
+
+	guidance := &DetectionGuidance{
+		ID:      lineage[0].id,
+		Name:    lineage[0].weakness.Name,
+		Methods: []DetectionGuidanceMethod{},
+	}
+	index := make(map[string]int)
+	for _, node := range lineage {
+		for _, method := range node.weakness.DetectionMethods {
+			name := strings.TrimSpace(method.Method)
+			if name == "" {
+				continue
+			}
+			key := strings.ToLower(name)
+			i, ok := index[key]
+			if !ok {
+				index[key] = len(guidance.Methods)
+				guidance.Methods = append(guidance.Methods, DetectionGuidanceMethod{
+					Method:             name,
+					Description:        method.Description,
+					Effectiveness:      method.Effectiveness,
+					EffectivenessNotes: method.EffectivenessNotes,
+					Sources:            []string{node.id},
+					Distance:           node.distance,
+				})
+				continue
+			}
+
+			merged := &guidance.Methods[i]
+			if merged.Sources[len(merged.Sources)-1] != node.id {
+				merged.Sources = append(merged.Sources, node.id)
+			}
+			if merged.Description == "" {
+				merged.Description = method.Description
+			}
+			if effectivenessRank(method.Effectiveness) > effectivenessRank(merged.Effectiveness) {
+				merged.Effectiveness = method.Effectiveness
+				merged.EffectivenessNotes = method.EffectivenessNotes
+			}
+		}
+	}
+
+	sort.SliceStable(guidance.Methods, func(i, j int) bool {
+		a, b := guidance.Methods[i], guidance.Methods[j]
+		if ra, rb := effectivenessRank(a.Effectiveness), effectivenessRank(b.Effectiveness); ra != rb {
+			return ra > rb
+		}
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		return a.Method < b.Method
+	})
+	return guidance, nil
+}
+
+// lineageNode 弱点祖先链中的一个弱点
+type lineageNode struct {
+	id       string
+	weakness *CWEWeakness
+	distance int
+}
+
+// fetchWeaknessLineage 获取弱点本身及其在研究视图中的所有祖先弱点
+// 结果按广度优先顺序排列，第一个元素是弱点本身；多条路径到达的祖先只保留距离最近的一次
+func (f *DataFetcher) fetchWeaknessLineage(id string) ([]lineageNode, error) {
+	normalizedID, err := ParseCWEID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	_, weakness, err := f.FetchWeaknessFull(normalizedID)
+	if err != nil {
+		return nil, err
+	}
+
+	lineage := []lineageNode{{id: normalizedID, weakness: weakness}}
+	visited := map[string]bool{normalizedID: true}
+	for i := 0; i < len(lineage); i++ {
+		node := lineage[i]
+		for _, rel := range normalizeRelations(node.weakness.RelatedWeaknesses) {
+			if rel.Nature != RelationChildOf || (rel.ViewID != "" && rel.ViewID != researchViewID) {
+				continue
+			}
+			if visited[rel.CweID] {
+				continue
+			}
+			visited[rel.CweID] = true
+
+			_, parent, err := f.FetchWeaknessFull(rel.CweID)
+			if err != nil {
+				return nil, fmt.Errorf("获取%s的祖先弱点%s失败: %w", normalizedID, rel.CweID, err)
+			}
+			lineage = append(lineage, lineageNode{id: rel.CweID, weakness: parent, distance: node.distance + 1})
+		}
+	}
+	return lineage, nil
+}
+
+// effectivenessRank 返回有效性的排序值，未知或为空的有效性返回0
+func effectivenessRank(effectiveness string) int {
+	return detectionEffectivenessRank[strings.ToLower(strings.TrimSpace(effectiveness))]
+}
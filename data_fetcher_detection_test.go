@@ -0,0 +1,128 @@
+package cwe
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func setupDetectionTestServer(requests map[string]int, mu *sync.Mutex) *httptest.Server {
+	responses := map[string]string{
+		// CWE-564没有自己的检测方法，同时属于另一个视图中的CWE-999
+		"/cwe/weakness/CWE-564": `{"weaknesses": [{"id": "564", "name": "SQL Injection: Hibernate", "related_weaknesses": [
+			{"nature": "ChildOf", "cwe_id": "89", "view_id": "1000"},
+			{"nature": "ChildOf", "cwe_id": "999", "view_id": "1003"}]}]}`,
+		"/cwe/weakness/CWE-89": `{"weaknesses": [{"id": "89", "name": "SQL Injection", "related_weaknesses": [
+			{"nature": "ChildOf", "cwe_id": "943", "view_id": "1000"},
+			{"nature": "ChildOf", "cwe_id": "74", "view_id": "1000"},
+			{"nature": "CanPrecede", "cwe_id": "200", "view_id": "1000"}],
+			"detection_methods": [
+			{"method": "Automated Static Analysis", "description": "SQL specific", "effectiveness": "Moderate"},
+			{"method": "Manual Analysis", "effectiveness": "Limited"}]}]}`,
+		"/cwe/weakness/CWE-943": `{"weaknesses": [{"id": "943", "name": "Data Query Logic", "related_weaknesses": [
+			{"nature": "ChildOf", "cwe_id": "74", "view_id": "1000"}],
+			"detection_methods": [{"method": "automated static analysis", "description": "Query", "effectiveness": "High", "effectiveness_notes": "Most tools"}]}]}`,
+		"/cwe/weakness/CWE-74": `{"weaknesses": [{"id": "74", "name": "Injection", "related_weaknesses": [
+			{"nature": "ChildOf", "cwe_id": "707", "view_id": "1000"}],
+			"detection_methods": [{"method": "Fuzzing", "effectiveness": "High"}]}]}`,
+		"/cwe/weakness/CWE-707": `{"weaknesses": [{"id": "707", "name": "Improper Neutralization",
+			"detection_methods": [{"method": "Manual Analysis", "description": "Review", "effectiveness": "SOAR Partial"}]}]}`,
+		"/cwe/weakness/CWE-1": `{"weaknesses": [{"id": "1", "name": "Broken", "related_weaknesses": [
+			{"nature": "ChildOf", "cwe_id": "404", "view_id": "1000"}]}]}`,
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}))
+}
+
+func TestFetchDetectionMethods(t *testing.T) {
+	var mu sync.Mutex
+	server := setupDetectionTestServer(map[string]int{}, &mu)
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+
+	methods, err := fetcher.FetchDetectionMethods("89")
+	if err != nil {
+		t.Fatalf("FetchDetectionMethods失败: %v", err)
+	}
+	if len(methods) != 2 || methods[0].Method != "Automated Static Analysis" || methods[0].Effectiveness != "Moderate" {
+		t.Errorf("检测方法不正确: %+v", methods)
+	}
+	if methods, err := fetcher.FetchDetectionMethods("CWE-564"); err != nil || methods == nil || len(methods) != 0 {
+		t.Errorf("没有检测方法时应返回空切片，实际为%v, %v", methods, err)
+	}
+	if _, err := fetcher.FetchDetectionMethods("abc"); err == nil {
+		t.Error("ID无效时应返回错误")
+	}
+}
+
+func TestGetDetectionGuidance(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	server := setupDetectionTestServer(requests, &mu)
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+
+	guidance, err := fetcher.GetDetectionGuidance("564")
+	if err != nil {
+		t.Fatalf("GetDetectionGuidance失败: %v", err)
+	}
+	if guidance.ID != "CWE-564" || guidance.Name != "SQL Injection: Hibernate" {
+		t.Errorf("弱点信息不正确: %s %s", guidance.ID, guidance.Name)
+	}
+
+	want := []DetectionGuidanceMethod{
+		{Method: "Automated Static Analysis", Description: "SQL specific", Effectiveness: "High", EffectivenessNotes: "Most tools", Sources: []string{"CWE-89", "CWE-943"}, Distance: 1},
+		{Method: "Fuzzing", Effectiveness: "High", Sources: []string{"CWE-74"}, Distance: 2},
+		{Method: "Manual Analysis", Description: "Review", Effectiveness: "SOAR Partial", Sources: []string{"CWE-89", "CWE-707"}, Distance: 1},
+	}
+	if !reflect.DeepEqual(guidance.Methods, want) {
+		t.Errorf("检测方法不正确:\n%+v\n期望:\n%+v", guidance.Methods, want)
+	}
+	if !guidance.Methods[0].Inherited() {
+		t.Error("只来自祖先的检测方法应标记为继承")
+	}
+
+	// 多条路径到达的祖先只请求一次，其他视图和其他性质的关系被忽略
+	for _, path := range []string{"/cwe/weakness/CWE-74", "/cwe/weakness/CWE-707"} {
+		if requests[path] != 1 {
+			t.Errorf("%s应只请求一次，实际为%d", path, requests[path])
+		}
+	}
+	if requests["/cwe/weakness/CWE-999"] != 0 || requests["/cwe/weakness/CWE-200"] != 0 {
+		t.Errorf("不应获取研究视图以外的祖先或非ChildOf关系: %v", requests)
+	}
+
+	guidance, err = fetcher.GetDetectionGuidance("CWE-707")
+	if err != nil || len(guidance.Methods) != 1 || guidance.Methods[0].Inherited() {
+		t.Errorf("弱点本身的检测方法不应标记为继承: %+v, %v", guidance, err)
+	}
+
+	if _, err := fetcher.GetDetectionGuidance("CWE-1"); err == nil {
+		t.Error("祖先弱点获取失败时应返回错误")
+	}
+	if _, err := fetcher.GetDetectionGuidance("abc"); err == nil {
+		t.Error("ID无效时应返回错误")
+	}
+
+	// 丢弃关系字段时只包含弱点本身
+	fetcher.SetFieldMask(FieldDetectionMethods)
+	guidance, err = fetcher.GetDetectionGuidance("89")
+	if err != nil || len(guidance.Methods) != 2 || guidance.Methods[0].Sources[0] != "CWE-89" || guidance.Methods[0].Effectiveness != "Moderate" {
+		t.Errorf("掩码不包含FieldRelatedWeaknesses时只应包含弱点本身的检测方法: %+v, %v", guidance, err)
+	}
+}
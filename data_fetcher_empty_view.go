@@ -0,0 +1,53 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrEmptyView 表示构建的视图树中根节点没有任何子节点
+//
+// CWE的视图(如研究视图1000)总是包含大量条目，空树几乎总是意味着镜像不完整、
+// 反向代理路由错误或视图ID写错，而不是视图本身为空。
+var ErrEmptyView = errors.New("视图没有子节点")
+
+// SetFailOnEmptyView 设置视图没有子节点时是否将构建视为失败
+//
+// 方法功能:
+// 默认情况下，BuildCWETreeWithView和BuildCWETreeResumable在视图没有子节点时仍返回只包含视图节点的注册表，
+// 同时在Registry.Warnings()中记录一条包装了ErrEmptyView的警告。
+// 设置为true后，这种情况会直接返回包装了ErrEmptyView的错误，可通过errors.Is判断。
+//
+// 参数:
+// - fail: bool - 为true时视图没有子节点会返回错误
+//
+// 使用示例:
+// ```go
+// fetcher := cwe.NewDataFetcher()
+// fetcher.SetFailOnEmptyView(true)
+//
+// registry, err := fetcher.BuildCWETreeWithView("1000")
+//
+//	if errors.Is(err, cwe.ErrEmptyView) {
+//	    log.Fatal("视图为空，请检查API地址是否正确")
+//	}
+//
+// ```
+func (f *DataFetcher) SetFailOnEmptyView(fail bool) {
+	f.failOnEmptyView = fail
+}
+
+// checkEmptyView 检查构建完成的视图树是否为空
+// 根节点没有子节点时，根据设置返回错误或在注册表中记录警告
+func (f *DataFetcher) checkEmptyView(registry *Registry, viewID string) error {
+	if registry.Root == nil || len(registry.Root.Children) > 0 {
+		return nil
+	}
+
+	err := fmt.Errorf("%w: %s", ErrEmptyView, viewID)
+	if f.failOnEmptyView {
+		return err
+	}
+	registry.addWarning(err)
+	return nil
+}
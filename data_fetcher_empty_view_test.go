@@ -0,0 +1,75 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// setupEmptyViewServer 创建视图存在但子节点列表为空的测试服务器
+func setupEmptyViewServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cwe/view/CWE-1000", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"views": [{"id": "CWE-1000", "name": "Research Concepts"}]}`)
+	})
+	mux.HandleFunc("/cwe/CWE-1000/children", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[]`)
+	})
+	return httptest.NewServer(mux)
+}
+
+func newEmptyViewTestFetcher(serverURL string) *DataFetcher {
+	client := NewAPIClientWithOptions(serverURL, DefaultTimeout, NewHTTPRateLimiter(0))
+	client.GetHTTPClient().SetRetryDelay(time.Millisecond)
+	return NewDataFetcherWithClient(client)
+}
+
+func TestBuildCWETreeWithViewEmptyView(t *testing.T) {
+	server := setupEmptyViewServer()
+	defer server.Close()
+
+	fetcher := newEmptyViewTestFetcher(server.URL)
+
+	// 默认只记录警告
+	registry, err := fetcher.BuildCWETreeWithView("1000")
+	if err != nil {
+		t.Fatalf("默认情况下空视图不应返回错误: %v", err)
+	}
+	warnings := registry.Warnings()
+	if len(warnings) != 1 || !errors.Is(warnings[0], ErrEmptyView) {
+		t.Errorf("应记录一条ErrEmptyView警告，实际为%v", warnings)
+	}
+
+	// 设置后视为失败
+	fetcher.SetFailOnEmptyView(true)
+	registry, err = fetcher.BuildCWETreeWithView("1000")
+	if !errors.Is(err, ErrEmptyView) {
+		t.Errorf("应返回ErrEmptyView，实际为%v", err)
+	}
+	if registry != nil {
+		t.Error("失败时不应返回注册表")
+	}
+}
+
+func TestBuildCWETreeResumableEmptyView(t *testing.T) {
+	server := setupEmptyViewServer()
+	defer server.Close()
+
+	fetcher := newEmptyViewTestFetcher(server.URL)
+	fetcher.SetFailOnEmptyView(true)
+
+	if _, err := fetcher.BuildCWETreeResumable("1000", nil, ResumableBuildOptions{}); !errors.Is(err, ErrEmptyView) {
+		t.Errorf("应返回ErrEmptyView，实际为%v", err)
+	}
+}
+
+func TestRegistryWarningsEmpty(t *testing.T) {
+	if warnings := NewRegistry().Warnings(); warnings != nil {
+		t.Errorf("新注册表不应有警告，实际为%v", warnings)
+	}
+}
@@ -0,0 +1,121 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+)
+
+// TreeErrorMode 控制构建树时如何处理单个节点的获取失败
+type TreeErrorMode int
+
+const (
+	// TreeErrorSkip 跳过获取失败的节点及其子树，只记录日志
+	// 这是默认模式，与早期版本的行为一致
+	TreeErrorSkip TreeErrorMode = iota
+
+	// TreeErrorCollect 跳过获取失败的节点，构建完成后将所有失败汇总为*MultiError，与注册表一起返回
+	TreeErrorCollect
+
+	// TreeErrorStrict 任一节点获取失败时立即中止构建，返回包装了*NodeError的错误
+	TreeErrorStrict
+)
+
+// NodeError 表示构建树时单个节点获取失败
+type NodeError struct {
+	// ID 获取失败的节点ID
+	ID string
+
+	// ParentID 父节点ID
+	ParentID string
+
+	// Err 失败原因
+	Err error
+}
+
+func (e *NodeError) Error() string {
+	return fmt.Sprintf("获取%s(父节点%s)失败: %v", e.ID, e.ParentID, e.Err)
+}
+
+func (e *NodeError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError 汇总多个错误
+//
+// errors.Is和errors.As会依次检查其中的每个错误，例如errors.Is(err, ErrNotFound)
+// 在任一错误包装了ErrNotFound时返回true。
+type MultiError struct {
+	Errors []error
+}
+
+func (e *MultiError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	return fmt.Sprintf("%d个错误，第一个: %v", len(e.Errors), e.Errors[0])
+}
+
+// Is 判断是否有任一错误匹配target
+func (e *MultiError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As 将第一个匹配target类型的错误赋值给target
+func (e *MultiError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTreeErrorMode 设置构建树时单个节点获取失败的处理方式
+//
+// 方法功能:
+// 默认的TreeErrorSkip模式下，获取失败的节点会被静默跳过，得到的树可能不完整。
+// TreeErrorCollect模式下BuildCWETreeWithView在构建完成后返回注册表和汇总了每个失败节点的*MultiError；
+// TreeErrorStrict模式下第一个失败就会中止构建。
+// 被抓取钩子以ErrSkipNode跳过的节点不视为失败。
+//
+// 参数:
+// - mode: TreeErrorMode - 错误处理模式
+//
+// 使用示例:
+// ```go
+// fetcher := cwe.NewDataFetcher()
+// fetcher.SetTreeErrorMode(cwe.TreeErrorCollect)
+//
+// registry, err := fetcher.BuildCWETreeWithView("1000")
+// var multiErr *cwe.MultiError
+//
+//	if errors.As(err, &multiErr) {
+//	    for _, nodeErr := range multiErr.Errors {
+//	        log.Printf("节点获取失败: %v", nodeErr)
+//	    }
+//	} else if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// ```
+func (f *DataFetcher) SetTreeErrorMode(mode TreeErrorMode) {
+	f.treeErrorMode = mode
+}
+
+// recordNodeFailure 按错误处理模式记录节点的获取失败
+// 严格模式下返回*NodeError，调用方应中止构建；其他模式下返回nil
+func (f *DataFetcher) recordNodeFailure(failures *MultiError, id, parentID string, err error) error {
+	nodeErr := &NodeError{ID: id, ParentID: parentID, Err: err}
+	switch f.treeErrorMode {
+	case TreeErrorStrict:
+		return nodeErr
+	case TreeErrorCollect:
+		failures.Errors = append(failures.Errors, nodeErr)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package cwe
+
+import (
+	"errors"
+	"testing"
+)
+
+// newFailingTreeFetcher 创建CWE-20的子节点列表获取失败、CWE-89被钩子拒绝的获取器
+func newFailingTreeFetcher(serverURL string, mode TreeErrorMode) *DataFetcher {
+	fetcher := newResumableTestFetcher(serverURL)
+	fetcher.client.GetHTTPClient().SetMaxRetries(1)
+	fetcher.SetTreeErrorMode(mode)
+	fetcher.SetFetchHooks(FetchHooks{
+		Before: func(req *FetchNodeRequest) error {
+			if req.ID == "CWE-89" {
+				return errors.New("拒绝获取")
+			}
+			return nil
+		},
+	})
+	return fetcher
+}
+
+func TestTreeErrorModeSkip(t *testing.T) {
+	failChildren := int32(1)
+	server := setupResumableTreeServer(&failChildren)
+	defer server.Close()
+
+	registry, err := newFailingTreeFetcher(server.URL, TreeErrorSkip).BuildCWETreeWithView("1000")
+	if err != nil {
+		t.Fatalf("默认模式不应返回错误: %v", err)
+	}
+	if len(registry.Entries) != 2 {
+		t.Errorf("期望2个条目，实际 %d 个", len(registry.Entries))
+	}
+}
+
+func TestTreeErrorModeCollect(t *testing.T) {
+	failChildren := int32(1)
+	server := setupResumableTreeServer(&failChildren)
+	defer server.Close()
+
+	registry, err := newFailingTreeFetcher(server.URL, TreeErrorCollect).BuildCWETreeWithView("1000")
+
+	var multiErr *MultiError
+	if !errors.As(err, &multiErr) {
+		t.Fatalf("期望*MultiError，实际 %v", err)
+	}
+	if registry == nil || registry.Root == nil || registry.Root.ID != "CWE-1000" {
+		t.Fatal("收集模式应同时返回注册表")
+	}
+	if _, exists := registry.Entries["CWE-20"]; !exists {
+		t.Error("子节点列表获取失败的节点本身应保留在注册表中")
+	}
+
+	if len(multiErr.Errors) != 2 {
+		t.Fatalf("期望2个失败，实际 %d 个: %v", len(multiErr.Errors), multiErr.Errors)
+	}
+	failed := make(map[string]string)
+	for _, e := range multiErr.Errors {
+		var nodeErr *NodeError
+		if !errors.As(e, &nodeErr) {
+			t.Fatalf("每个失败都应是*NodeError，实际 %T", e)
+		}
+		failed[nodeErr.ID] = nodeErr.ParentID
+	}
+	if failed["CWE-20"] != "CWE-1000" || failed["CWE-89"] != "CWE-1000" {
+		t.Errorf("失败节点不正确: %v", failed)
+	}
+}
+
+func TestTreeErrorModeStrict(t *testing.T) {
+	failChildren := int32(1)
+	server := setupResumableTreeServer(&failChildren)
+	defer server.Close()
+
+	registry, err := newFailingTreeFetcher(server.URL, TreeErrorStrict).BuildCWETreeWithView("1000")
+	if registry != nil {
+		t.Error("严格模式失败时不应返回注册表")
+	}
+
+	var nodeErr *NodeError
+	if !errors.As(err, &nodeErr) {
+		t.Fatalf("期望包装了*NodeError的错误，实际 %v", err)
+	}
+	if nodeErr.ID != "CWE-20" {
+		t.Errorf("应在第一个失败的节点处中止，实际 %s", nodeErr.ID)
+	}
+}
+
+func TestTreeErrorModeIgnoresSkipNode(t *testing.T) {
+	var failChildren int32
+	server := setupResumableTreeServer(&failChildren)
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+	fetcher.SetTreeErrorMode(TreeErrorStrict)
+	fetcher.SetFetchHooks(FetchHooks{
+		Before: func(req *FetchNodeRequest) error {
+			if req.ID == "CWE-20" {
+				return ErrSkipNode
+			}
+			return nil
+		},
+	})
+
+	if _, err := fetcher.BuildCWETreeWithView("1000"); err != nil {
+		t.Errorf("ErrSkipNode不应视为失败: %v", err)
+	}
+}
+
+func TestMultiError(t *testing.T) {
+	err := &MultiError{Errors: []error{
+		errors.New("first"),
+		&NodeError{ID: "CWE-79", ParentID: "CWE-20", Err: &notFoundError{id: "CWE-79"}},
+	}}
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("errors.Is应检查每个错误")
+	}
+	var nodeErr *NodeError
+	if !errors.As(err, &nodeErr) || nodeErr.ID != "CWE-79" {
+		t.Errorf("errors.As应找到*NodeError，实际 %v", nodeErr)
+	}
+	if err.Error() != "2个错误，第一个: first" {
+		t.Errorf("错误信息不正确: %s", err.Error())
+	}
+
+	single := &MultiError{Errors: []error{errors.New("only")}}
+	if single.Error() != "only" {
+		t.Errorf("单个错误时应直接返回该错误信息，实际 %s", single.Error())
+	}
+}
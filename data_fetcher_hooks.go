@@ -0,0 +1,121 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSkipNode 由抓取钩子返回，表示跳过当前节点及其整个子树
+// 被跳过的节点不会被注册，也不会被添加为父节点的子节点
+var ErrSkipNode = errors.New("跳过节点")
+
+// FetchNodeRequest 描述构建树时对单个节点的抓取
+type FetchNodeRequest struct {
+	// ID 要抓取的节点ID，Before钩子可以修改它以抓取另一个节点
+	ID string
+
+	// ParentID 父节点ID
+	ParentID string
+
+	// ViewID 正在构建的视图ID
+	ViewID string
+}
+
+// FetchHooks 构建树时在每个节点抓取前后调用的钩子
+//
+// 钩子在BuildCWETreeWithView、FetchCWEByIDWithRelations、BuildCWETreeResumable、ExtendWithView和FetchCategoryWithMembers中生效，
+// 只在节点需要从API获取时调用，已在注册表中的节点不会触发钩子。
+type FetchHooks struct {
+	// Before 在抓取节点前调用，可以修改req.ID
+	// 返回ErrSkipNode时跳过该节点及其子树；返回其他错误时按抓取失败处理
+	Before func(req *FetchNodeRequest) error
+
+	// After 在抓取节点后调用，node在抓取失败时为nil，err为抓取错误
+	// 可以修改node的字段；返回ErrSkipNode时丢弃该节点及其子树；返回其他错误时按抓取失败处理
+	After func(req *FetchNodeRequest, node *CWE, err error) error
+}
+
+// SetFetchHooks 设置构建树时使用的抓取钩子
+//
+// 方法功能:
+// 在不修改树构建逻辑的情况下，跳过已知无关的子树或为每个节点添加监控等。
+//
+// 参数:
+// - hooks: FetchHooks - 抓取钩子，零值表示不使用钩子
+//
+// 使用示例:
+// ```go
+// fetcher := cwe.NewDataFetcher()
+//
+//	fetcher.SetFetchHooks(cwe.FetchHooks{
+//	    Before: func(req *cwe.FetchNodeRequest) error {
+//	        if req.ID == "CWE-1228" { // 跳过API/函数误用子树
+//	            return cwe.ErrSkipNode
+//	        }
+//	        return nil
+//	    },
+//	    After: func(req *cwe.FetchNodeRequest, node *cwe.CWE, err error) error {
+//	        log.Printf("抓取%s: %v", req.ID, err)
+//	        return nil
+//	    },
+//	})
+//
+// registry, err := fetcher.BuildCWETreeWithView("1000")
+// ```
+func (f *DataFetcher) SetFetchHooks(hooks FetchHooks) {
+	f.hooks = hooks
+}
+
+// fetchTreeNode 在构建树时获取父节点下的一个子节点，并调用抓取钩子
+//
+// 返回值:
+// - *CWE: 子节点；钩子修改后的ID已在注册表中时返回已有条目
+// - bool: 子节点是否为新获取的节点，调用方需要注册新节点并继续处理其子节点
+// - error: 节点被跳过时返回ErrSkipNode，抓取失败时返回相应错误
+func (f *DataFetcher) fetchTreeNode(registry *Registry, parent *CWE, childID, viewID string) (node *CWE, isNew bool, err error) {
+	defer func() {
+		switch {
+		case errors.Is(err, ErrSkipNode):
+			f.log().Debug("跳过节点", "id", childID, "parent", parent.ID)
+		case err != nil:
+			f.log().Warn("获取节点失败，已跳过", "id", childID, "parent", parent.ID, "error", err)
+		case isNew:
+			f.log().Debug("获取节点", "id", node.ID, "parent", parent.ID, "depth", nodeDepth(parent)+1, "fetched", len(registry.Entries)+1)
+		}
+	}()
+
+	req := &FetchNodeRequest{
+		ID:       childID,
+		ParentID: parent.ID,
+		ViewID:   viewID,
+	}
+
+	if f.hooks.Before != nil {
+		if err := f.hooks.Before(req); err != nil {
+			return nil, false, err
+		}
+		if req.ID != childID {
+			normalized, err := ParseCWEID(req.ID)
+			if err != nil {
+				return nil, false, fmt.Errorf("钩子返回了无效的ID: %w", err)
+			}
+			req.ID = normalized
+			if existing, exists := registry.Entries[req.ID]; exists {
+				return existing, false, nil
+			}
+		}
+	}
+
+	child, err := f.fetchWeaknessOrCategory(req.ID)
+
+	if f.hooks.After != nil {
+		if hookErr := f.hooks.After(req, child, err); hookErr != nil {
+			return nil, false, hookErr
+		}
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return child, true, nil
+}
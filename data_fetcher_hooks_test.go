@@ -0,0 +1,125 @@
+package cwe
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFetchHooksSkipSubtree(t *testing.T) {
+	var failChildren int32
+	server := setupResumableTreeServer(&failChildren)
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+
+	var before []string
+	fetcher.SetFetchHooks(FetchHooks{
+		Before: func(req *FetchNodeRequest) error {
+			before = append(before, req.ParentID+">"+req.ID)
+			if req.ID == "CWE-20" {
+				return ErrSkipNode
+			}
+			return nil
+		},
+	})
+
+	registry, err := fetcher.BuildCWETreeWithView("1000")
+	if err != nil {
+		t.Fatalf("BuildCWETreeWithView失败: %v", err)
+	}
+
+	if _, exists := registry.Entries["CWE-20"]; exists {
+		t.Error("被跳过的节点不应注册")
+	}
+	if _, exists := registry.Entries["CWE-79"]; exists {
+		t.Error("被跳过节点的子树不应被抓取")
+	}
+	if len(registry.Root.Children) != 1 || registry.Root.Children[0].ID != "CWE-89" {
+		t.Errorf("根节点子节点不正确: %v", registry.Root.Children)
+	}
+	if strings.Join(before, ",") != "CWE-1000>CWE-20,CWE-1000>CWE-89" {
+		t.Errorf("Before钩子调用顺序不正确: %v", before)
+	}
+}
+
+func TestFetchHooksAfter(t *testing.T) {
+	var failChildren int32
+	server := setupResumableTreeServer(&failChildren)
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+
+	fetched := make(map[string]error)
+	fetcher.SetFetchHooks(FetchHooks{
+		After: func(req *FetchNodeRequest, node *CWE, err error) error {
+			fetched[req.ID] = err
+			if node != nil {
+				node.Name = strings.ToUpper(node.Name)
+			}
+			if req.ID == "CWE-79" {
+				return ErrSkipNode
+			}
+			return nil
+		},
+	})
+
+	registry, err := fetcher.BuildCWETreeResumable("1000", nil, ResumableBuildOptions{})
+	if err != nil {
+		t.Fatalf("BuildCWETreeResumable失败: %v", err)
+	}
+
+	if len(fetched) != 3 {
+		t.Errorf("After钩子应对每个新节点调用一次，但调用了 %d 次: %v", len(fetched), fetched)
+	}
+	if registry.Entries["CWE-89"].Name != "SQL INJECTION" {
+		t.Errorf("After钩子对节点的修改应保留，但名称为 %q", registry.Entries["CWE-89"].Name)
+	}
+	if _, exists := registry.Entries["CWE-79"]; exists {
+		t.Error("After钩子返回ErrSkipNode时应丢弃节点")
+	}
+}
+
+func TestFetchHooksRedirect(t *testing.T) {
+	var failChildren int32
+	server := setupResumableTreeServer(&failChildren)
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+	fetcher.SetFetchHooks(FetchHooks{
+		Before: func(req *FetchNodeRequest) error {
+			if req.ID == "CWE-79" {
+				req.ID = "89"
+			}
+			return nil
+		},
+	})
+
+	registry, err := fetcher.BuildCWETreeWithView("1000")
+	if err != nil {
+		t.Fatalf("BuildCWETreeWithView失败: %v", err)
+	}
+
+	children := registry.Entries["CWE-20"].Children
+	if len(children) != 2 || children[0] != registry.Entries["CWE-89"] {
+		t.Errorf("重定向到已有节点时应复用注册表中的条目: %v", children)
+	}
+	if _, exists := registry.Entries["CWE-79"]; exists {
+		t.Error("被重定向的节点不应注册")
+	}
+}
+
+func TestFetchHooksInvalidRedirect(t *testing.T) {
+	fetcher := NewDataFetcherWithClient(NewAPIClientWithOptions("http://127.0.0.1:0", DefaultTimeout, NewHTTPRateLimiter(0)))
+	fetcher.SetFetchHooks(FetchHooks{
+		Before: func(req *FetchNodeRequest) error {
+			req.ID = "not an id"
+			return nil
+		},
+	})
+
+	_, _, err := fetcher.fetchTreeNode(NewRegistry(), NewCWE("CWE-1000", "root"), "CWE-79", "CWE-1000")
+	if err == nil || errors.Is(err, ErrSkipNode) {
+		t.Errorf("无效的重定向ID应返回错误，但得到 %v", err)
+	}
+}
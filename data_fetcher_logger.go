@@ -0,0 +1,56 @@
+package cwe
+
+// SetLogger 设置数据获取器记录树构建进度的日志记录器
+//
+// 方法功能:
+// 设置后，BuildCWETreeWithView和BuildCWETreeResumable在开始和完成时以Info级别记录视图、
+// 节点数量和耗时，每个抓取的节点以Debug级别记录ID、父节点、深度和已获取的节点数，
+// 无法获取而被跳过的节点以Warn级别记录。传入nil关闭日志。
+//
+// 数据获取器不会修改API客户端的设置，如需同时记录每个HTTP请求，需要另外调用APIClient.SetLogger。
+//
+// 参数:
+// - logger: Logger - 日志记录器，为nil时不记录日志
+//
+// 使用示例:
+// ```go
+// logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+//
+// client := cwe.NewAPIClient()
+// client.SetLogger(logger)
+//
+// fetcher := cwe.NewDataFetcherWithClient(client)
+// fetcher.SetLogger(logger)
+//
+// registry, err := fetcher.BuildCWETreeWithView("1000")
+// ```
+func (f *DataFetcher) SetLogger(logger Logger) {
+	f.logger = logger
+}
+
+// log 返回数据获取器的日志记录器，未设置时返回不记录任何内容的记录器
+func (f *DataFetcher) log() Logger {
+	if f.logger == nil {
+		return nopLogger{}
+	}
+	return f.logger
+}
+
+// nopLogger 是不记录任何内容的Logger
+type nopLogger struct{}
+
+func (nopLogger) Debug(msg string, keysAndValues ...interface{}) {}
+func (nopLogger) Info(msg string, keysAndValues ...interface{})  {}
+func (nopLogger) Warn(msg string, keysAndValues ...interface{})  {}
+func (nopLogger) Error(msg string, keysAndValues ...interface{}) {}
+
+// nodeDepth 返回节点沿Parent到根节点的层数，根节点为0
+func nodeDepth(node *CWE) int {
+	depth := 0
+	seen := map[*CWE]bool{node: true}
+	for parent := node.Parent; parent != nil && !seen[parent]; parent = parent.Parent {
+		seen[parent] = true
+		depth++
+	}
+	return depth
+}
@@ -0,0 +1,112 @@
+package cwe
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// setupLoggerTreeServer 创建视图CWE-1000下有CWE-20，CWE-20下有CWE-79和无法获取的CWE-999的测试服务器
+func setupLoggerTreeServer() *httptest.Server {
+	mux := http.NewServeMux()
+	writeJSON := func(path, body string) {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, body)
+		})
+	}
+	writeJSON("/cwe/view/CWE-1000", `{"views": [{"id": "1000", "name": "Research Concepts"}]}`)
+	writeJSON("/cwe/CWE-1000/children", `["20"]`)
+	writeJSON("/cwe/weakness/CWE-20", `{"weaknesses": [{"id": "20", "name": "Improper Input Validation"}]}`)
+	writeJSON("/cwe/CWE-20/children", `["79", "999"]`)
+	writeJSON("/cwe/weakness/CWE-79", `{"weaknesses": [{"id": "79", "name": "Cross-site Scripting"}]}`)
+	writeJSON("/cwe/CWE-79/children", `[]`)
+	return httptest.NewServer(mux)
+}
+
+func TestDataFetcherLogger(t *testing.T) {
+	server := setupLoggerTreeServer()
+	defer server.Close()
+
+	client := NewAPIClientWithOptions(server.URL, DefaultTimeout, NewHTTPRateLimiter(0))
+	client.GetHTTPClient().SetRetryDelay(time.Millisecond)
+	client.GetHTTPClient().SetMaxRetries(1)
+	fetcher := NewDataFetcherWithClient(client)
+
+	logger := &testLogger{}
+	fetcher.SetLogger(logger)
+
+	registry, err := fetcher.BuildCWETreeWithView("1000")
+	if err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	if start := logger.find("开始构建CWE树"); len(start) != 1 || start[0].fields["view"] != "CWE-1000" {
+		t.Errorf("应记录构建开始，实际为%+v", start)
+	}
+	done := logger.find("CWE树构建完成")
+	if len(done) != 1 || done[0].fields["nodes"] != len(registry.Entries) || done[0].level != "info" {
+		t.Errorf("应记录构建完成及节点数量，实际为%+v", done)
+	}
+
+	nodes := logger.find("获取节点")
+	if len(nodes) != 2 {
+		t.Fatalf("应记录2个获取的节点，实际为%+v", nodes)
+	}
+	if nodes[0].fields["id"] != "CWE-20" || nodes[0].fields["depth"] != 1 {
+		t.Errorf("CWE-20应位于第1层，实际为%+v", nodes[0])
+	}
+	if nodes[1].fields["id"] != "CWE-79" || nodes[1].fields["depth"] != 2 || nodes[1].fields["parent"] != "CWE-20" {
+		t.Errorf("CWE-79应位于第2层，实际为%+v", nodes[1])
+	}
+
+	skipped := logger.find("获取节点失败，已跳过")
+	if len(skipped) != 1 || skipped[0].fields["id"] != "CWE-999" || skipped[0].level != "warn" {
+		t.Errorf("应以Warn级别记录无法获取的CWE-999，实际为%+v", skipped)
+	}
+
+	// HTTP请求由API客户端的日志记录器负责
+	if len(logger.find("HTTP请求")) != 0 {
+		t.Error("数据获取器的日志记录器不应记录HTTP请求")
+	}
+}
+
+func TestDataFetcherLoggerResumable(t *testing.T) {
+	server := setupLoggerTreeServer()
+	defer server.Close()
+
+	client := NewAPIClientWithOptions(server.URL, DefaultTimeout, NewHTTPRateLimiter(0))
+	client.GetHTTPClient().SetRetryDelay(time.Millisecond)
+	client.GetHTTPClient().SetMaxRetries(1)
+	fetcher := NewDataFetcherWithClient(client)
+
+	logger := &testLogger{}
+	fetcher.SetLogger(logger)
+
+	if _, err := fetcher.BuildCWETreeResumable("1000", nil, ResumableBuildOptions{}); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if len(logger.find("CWE树构建完成")) != 1 || len(logger.find("获取节点")) != 2 {
+		t.Errorf("可恢复构建应记录进度，实际为%+v", logger.entries)
+	}
+}
+
+func TestNodeDepth(t *testing.T) {
+	root := NewCWE("CWE-1000", "root")
+	child := NewCWE("CWE-20", "child")
+	grandchild := NewCWE("CWE-79", "grandchild")
+	root.AddChild(child)
+	child.AddChild(grandchild)
+
+	if nodeDepth(root) != 0 || nodeDepth(child) != 1 || nodeDepth(grandchild) != 2 {
+		t.Error("节点深度计算不正确")
+	}
+
+	// 出现环时不应死循环
+	root.Parent = grandchild
+	if depth := nodeDepth(grandchild); depth != 2 {
+		t.Errorf("有环时深度应为2，实际为%d", depth)
+	}
+}
@@ -6,27 +6,56 @@ import (
 )
 
 // FetchMultiple 获取多个CWE并转换为Registry
+//
+// 方法功能:
+// ID列表按SetBatchSize设置的大小分批请求，批次之间并发执行，并发数由SetBatchConcurrency设置。
+// 重复的ID只请求一次，注册表中的ID统一为"CWE-数字"格式。
+//
+// 返回值:
+// - *Registry: 获取成功的条目，部分ID失败时仍然返回
+// - error: ID格式错误时返回解析错误；服务器版本与PinVersion固定的版本不同时返回包装了ErrVersionMismatch的错误；
+// 部分或全部ID获取失败时返回*BatchError
+//
+// 使用示例:
+// ```go
+// registry, err := fetcher.FetchMultiple(ids)
+//
+//	var batchErr *cwe.BatchError
+//	if errors.As(err, &batchErr) {
+//	    log.Printf("以下CWE获取失败: %v", batchErr.FailedIDs())
+//	} else if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// ```
 func (f *DataFetcher) FetchMultiple(ids []string) (*Registry, error) {
 	if len(ids) == 0 {
 		return nil, fmt.Errorf("必须提供至少一个CWE ID")
 	}
 
-	// 规范化IDs
+	// 规范化并去重IDs
 	normalizedIDs := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
 	for _, id := range ids {
 		normalized, err := ParseCWEID(id)
 		if err != nil {
 			return nil, err
 		}
+		if seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
 		normalizedIDs = append(normalizedIDs, normalized)
 	}
 
-	// 从API获取数据
-	data, err := f.client.GetCWEs(normalizedIDs)
+	version, err := f.sourceVersion(false)
 	if err != nil {
 		return nil, err
 	}
 
+	// 从API分批获取数据
+	data, failures := f.fetchBatches(normalizedIDs)
+
 	// 创建Registry
 	registry := NewRegistry()
 
@@ -38,10 +67,15 @@ func (f *DataFetcher) FetchMultiple(ids []string) (*Registry, error) {
 			Description: cweData.Description,
 			Severity:    cweData.Severity,
 			URL:         cweData.URL,
+			Language:    cweData.Language,
+			Version:     version,
 		}
 		registry.Register(cwe)
 	}
 
+	if len(failures) > 0 {
+		return registry, &BatchError{Failures: failures}
+	}
 	return registry, nil
 }
 
@@ -66,10 +100,10 @@ func (f *DataFetcher) PopulateChildrenRecursive(cwe *CWE, viewID string) error {
 		}
 
 		// 尝试获取子节点
-		child, err := f.FetchWeakness(childID)
+		child, err := f.acceptStale(f.FetchWeakness(childID))
 		if err != nil {
 			// 如果不是weakness，尝试作为category获取
-			child, err = f.FetchCategory(childID)
+			child, err = f.acceptStale(f.FetchCategory(childID))
 			if err != nil {
 				// 跳过无法获取的节点
 				continue
@@ -0,0 +1,192 @@
+package cwe
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// PrefetchTask 表示一次在后台进行的预取，由DataFetcher.Prefetch创建
+type PrefetchTask struct {
+	done    chan struct{}
+	fetched int64
+
+	// registry和err在done关闭后才能读取
+	registry *Registry
+	err      error
+}
+
+// Done 返回预取结束(完成、失败或被取消)时关闭的通道
+func (t *PrefetchTask) Done() <-chan struct{} {
+	return t.done
+}
+
+// Wait 等待预取结束，返回包含所有已获取条目的注册表
+//
+// 部分条目获取失败时，注册表中仍包含其他条目，错误为汇总了每个失败节点的*MultiError；
+// 上下文被取消时返回已获取的条目和ctx.Err()。
+func (t *PrefetchTask) Wait() (*Registry, error) {
+	<-t.done
+	return t.registry, t.err
+}
+
+// Fetched 返回目前已获取的条目数，可以在预取进行时调用以显示进度
+func (t *PrefetchTask) Fetched() int {
+	return int(atomic.LoadInt64(&t.fetched))
+}
+
+// prefetchResult 是预取单个节点的结果
+type prefetchResult struct {
+	id       string
+	entry    *CWE
+	children []string
+	err      error
+
+	// childErr 获取子节点ID失败的原因，此时条目本身仍然有效
+	childErr error
+}
+
+// Prefetch 在后台获取指定CWE及其后代
+//
+// 方法功能:
+// 立即返回，在后台逐层获取ids中的条目及其depth层以内的后代(子节点不限定视图)，
+// 同时进行的请求数与FetchMultiple相同，见SetBatchConcurrency。
+// 条目通过FetchWeakness和FetchCategory获取，因此会写入SetStore设置的存储，
+// 使用NewCachedAPIClient时也会写入响应缓存；交互式工具可以在用户展开节点之前预热数据。
+// 同一条目只获取一次，获取失败的节点不再展开。
+//
+// 参数:
+// - ctx: context.Context - 取消ctx会停止发送新的请求，Wait返回已获取的条目和ctx.Err()
+// - ids: []string - 要预取的CWE ID，支持ParseCWEID接受的所有格式
+// - depth: int - 展开的层数，0表示只获取ids本身，<0表示获取所有后代
+//
+// 返回值:
+// - *PrefetchTask: 预取任务，通过Wait获取结果
+//
+// 使用示例:
+// ```go
+// task := fetcher.Prefetch(ctx, []string{"CWE-20", "CWE-74"}, 2)
+//
+// // 继续处理用户交互，之后获取结果
+// registry, err := task.Wait()
+// ```
+func (f *DataFetcher) Prefetch(ctx context.Context, ids []string, depth int) *PrefetchTask {
+	task := &PrefetchTask{done: make(chan struct{})}
+	go func() {
+		defer close(task.done)
+		task.registry, task.err = f.prefetch(ctx, task, ids, depth)
+	}()
+	return task
+}
+
+// prefetch 按层获取条目，每层内并发获取，层与层之间按顺序进行
+func (f *DataFetcher) prefetch(ctx context.Context, task *PrefetchTask, ids []string, depth int) (*Registry, error) {
+	registry := NewRegistry()
+	failures := &MultiError{}
+
+	concurrency := f.batchConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+
+	seen := make(map[string]bool)
+	var level []string
+	for _, id := range ids {
+		normalized, err := ParseCWEID(id)
+		if err != nil {
+			failures.Errors = append(failures.Errors, &NodeError{ID: id, Err: err})
+			continue
+		}
+		if !seen[normalized] {
+			seen[normalized] = true
+			level = append(level, normalized)
+		}
+	}
+
+	parents := make(map[string][]string)
+	for remaining := depth; len(level) > 0; remaining-- {
+		if err := ctx.Err(); err != nil {
+			return registry, err
+		}
+
+		results := f.prefetchLevel(ctx, level, remaining != 0, concurrency)
+
+		var next []string
+		for _, result := range results {
+			if result.err != nil {
+				if ctx.Err() == nil {
+					failures.Errors = append(failures.Errors, &NodeError{ID: result.id, Err: result.err})
+				}
+				continue
+			}
+			if err := registry.Register(result.entry); err != nil {
+				failures.Errors = append(failures.Errors, &NodeError{ID: result.id, Err: err})
+				continue
+			}
+			atomic.AddInt64(&task.fetched, 1)
+			if result.childErr != nil && ctx.Err() == nil {
+				failures.Errors = append(failures.Errors, &NodeError{ID: result.id, Err: result.childErr})
+			}
+
+			for _, parentID := range parents[result.id] {
+				registry.AddChild(parentID, result.id)
+			}
+			for _, childID := range result.children {
+				childID = normalizeEntryID(childID)
+				if seen[childID] {
+					// 已获取或正在本层获取的子节点只补充父子关系
+					if _, exists := registry.Entries[childID]; exists {
+						registry.AddChild(result.id, childID)
+					} else {
+						parents[childID] = append(parents[childID], result.id)
+					}
+					continue
+				}
+				seen[childID] = true
+				parents[childID] = append(parents[childID], result.id)
+				next = append(next, childID)
+			}
+		}
+		level = next
+	}
+
+	if err := ctx.Err(); err != nil {
+		return registry, err
+	}
+	if len(failures.Errors) > 0 {
+		return registry, failures
+	}
+	return registry, nil
+}
+
+// prefetchLevel 以最多concurrency个并发请求获取一层节点，expand为true时同时获取子节点ID
+// 结果与ids的顺序一致
+func (f *DataFetcher) prefetchLevel(ctx context.Context, ids []string, expand bool, concurrency int) []prefetchResult {
+	results := make([]prefetchResult, len(ids))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, id := range ids {
+		results[i].id = id
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			results[i].err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(result *prefetchResult) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			result.entry, result.err = f.fetchWeaknessOrCategory(result.id)
+			if result.err != nil || !expand {
+				return
+			}
+			result.children, result.childErr = f.client.GetChildren(result.id, "")
+		}(&results[i])
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,66 @@
+package cwe
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPrefetch(t *testing.T) {
+	var failChildren int32
+	server := setupResumableTreeServer(&failChildren)
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+	store := NewMemoryStore()
+	fetcher.SetStore(store, StoreReadWrite)
+
+	task := fetcher.Prefetch(context.Background(), []string{"20", "CWE-20"}, -1)
+	<-task.Done()
+	registry, err := task.Wait()
+	if err != nil {
+		t.Fatalf("Prefetch失败: %v", err)
+	}
+
+	if ids := cweIDsOf(registry.Snapshot()); !reflect.DeepEqual(ids, []string{"CWE-20", "CWE-79", "CWE-89"}) {
+		t.Errorf("预取的条目不正确: %v", ids)
+	}
+	if task.Fetched() != 3 {
+		t.Errorf("已获取条目数应为3，实际为%d", task.Fetched())
+	}
+	if children := cweIDsOf(registry.Entries["CWE-20"].Children); !reflect.DeepEqual(children, []string{"CWE-79", "CWE-89"}) {
+		t.Errorf("应建立父子关系，实际子节点为%v", children)
+	}
+	if ids, _ := store.List(); len(ids) != 3 {
+		t.Errorf("预取的条目应写入存储，实际为%v", ids)
+	}
+}
+
+func TestPrefetchDepthAndFailures(t *testing.T) {
+	var failChildren int32
+	server := setupResumableTreeServer(&failChildren)
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+
+	registry, err := fetcher.Prefetch(context.Background(), []string{"CWE-20"}, 0).Wait()
+	if err != nil || len(registry.Entries) != 1 {
+		t.Errorf("depth为0时只应获取指定条目: %v %v", cweIDsOf(registry.Snapshot()), err)
+	}
+
+	registry, err = fetcher.Prefetch(context.Background(), []string{"CWE-79", "CWE-404", "bad"}, 1).Wait()
+	var multiErr *MultiError
+	if !errors.As(err, &multiErr) || len(multiErr.Errors) != 2 {
+		t.Fatalf("应汇总获取失败的节点，实际为%v", err)
+	}
+	if _, exists := registry.Entries["CWE-79"]; !exists {
+		t.Error("其他条目获取失败时仍应返回获取成功的条目")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := fetcher.Prefetch(ctx, []string{"CWE-20"}, -1).Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("上下文取消时应返回context.Canceled，实际为%v", err)
+	}
+}
@@ -0,0 +1,38 @@
+package cwe
+
+// ProgressFunc 是构建树时的进度回调
+//
+// 参数:
+// - fetched: int - 注册表中已有的节点数，包括视图节点
+// - queued: int - 已发现但尚未处理的节点数，随着子节点列表的获取而增长，构建完成时为0
+// - currentID: string - 刚处理完的节点ID
+type ProgressFunc func(fetched, queued int, currentID string)
+
+// treeProgress 跟踪递归构建树时的待处理节点数并调用进度回调
+// nil指针和callback为nil时所有方法都不做任何操作
+type treeProgress struct {
+	callback ProgressFunc
+	queued   int
+}
+
+// enqueue 记录新发现的n个子节点
+func (p *treeProgress) enqueue(n int) {
+	if p != nil {
+		p.queued += n
+	}
+}
+
+// dequeue 记录开始处理一个子节点
+func (p *treeProgress) dequeue() {
+	if p != nil && p.queued > 0 {
+		p.queued--
+	}
+}
+
+// report 调用进度回调
+func (p *treeProgress) report(registry *Registry, currentID string) {
+	if p == nil || p.callback == nil {
+		return
+	}
+	p.callback(len(registry.Entries), p.queued, currentID)
+}
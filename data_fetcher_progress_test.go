@@ -0,0 +1,85 @@
+package cwe
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// progressCall 是一次进度回调的参数
+type progressCall struct {
+	fetched   int
+	queued    int
+	currentID string
+}
+
+func newProgressTestFetcher(url string) *DataFetcher {
+	client := NewAPIClientWithOptions(url, DefaultTimeout, NewHTTPRateLimiter(0))
+	client.GetHTTPClient().SetRetryDelay(time.Millisecond)
+	client.GetHTTPClient().SetMaxRetries(1)
+	return NewDataFetcherWithClient(client)
+}
+
+func TestBuildCWETreeWithViewProgress(t *testing.T) {
+	server := setupLoggerTreeServer()
+	defer server.Close()
+
+	fetcher := newProgressTestFetcher(server.URL)
+
+	var calls []progressCall
+	registry, err := fetcher.BuildCWETreeWithViewProgress("1000", func(fetched, queued int, currentID string) {
+		calls = append(calls, progressCall{fetched, queued, currentID})
+	})
+	if err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if len(registry.Entries) != 3 {
+		t.Errorf("应获取3个节点，实际为%d", len(registry.Entries))
+	}
+
+	// CWE-999无法获取，仍会报告一次进度
+	expected := []progressCall{
+		{1, 0, "CWE-1000"},
+		{2, 0, "CWE-20"},
+		{3, 1, "CWE-79"},
+		{3, 0, "CWE-999"},
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("进度回调应为%v，实际为%v", expected, calls)
+	}
+
+	// progress为nil时与BuildCWETreeWithView相同
+	if _, err := fetcher.BuildCWETreeWithViewProgress("1000", nil); err != nil {
+		t.Errorf("progress为nil时构建失败: %v", err)
+	}
+}
+
+func TestBuildCWETreeResumableProgress(t *testing.T) {
+	server := setupLoggerTreeServer()
+	defer server.Close()
+
+	fetcher := newProgressTestFetcher(server.URL)
+
+	var calls []progressCall
+	_, err := fetcher.BuildCWETreeResumable("1000", nil, ResumableBuildOptions{
+		Progress: func(fetched, queued int, currentID string) {
+			calls = append(calls, progressCall{fetched, queued, currentID})
+		},
+	})
+	if err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	if len(calls) == 0 {
+		t.Fatal("应报告进度")
+	}
+	last := calls[len(calls)-1]
+	if last.fetched != 3 || last.queued != 0 {
+		t.Errorf("最后一次进度应为3个节点、0个待处理，实际为%+v", last)
+	}
+	for i := 1; i < len(calls); i++ {
+		if calls[i].fetched < calls[i-1].fetched {
+			t.Errorf("已获取的节点数不应减少: %v", calls)
+		}
+	}
+}
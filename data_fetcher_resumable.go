@@ -0,0 +1,225 @@
+package cwe
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultCheckpointEvery 是可恢复构建默认的检查点间隔(按处理的节点数计)
+const DefaultCheckpointEvery = 10
+
+// BuildState 表示可恢复树构建的进度
+// 它可以直接序列化为JSON保存到状态文件中，中断后通过BuildCWETreeResumable继续构建
+type BuildState struct {
+	// ViewID 正在构建的视图ID
+	ViewID string `json:"view_id"`
+
+	// Snapshot 已获取的条目及其已展开的父子关系
+	Snapshot RegistrySnapshot `json:"snapshot"`
+
+	// Pending 尚未展开子节点的条目ID队列，按广度优先顺序排列
+	Pending []string `json:"pending"`
+}
+
+// Complete 判断构建是否已完成
+func (s *BuildState) Complete() bool {
+	return len(s.Pending) == 0
+}
+
+// ResumableBuildOptions 控制可恢复构建的选项
+type ResumableBuildOptions struct {
+	// CheckpointEvery 每处理多少个节点调用一次Checkpoint，<=0时使用DefaultCheckpointEvery
+	CheckpointEvery int
+
+	// Checkpoint 检查点回调，通常用于将状态写入文件
+	// 构建完成和因错误中止时也会调用一次；回调返回错误会中止构建
+	Checkpoint func(state *BuildState) error
+
+	// Progress 进度回调，在处理完每个子节点和展开完每个节点后调用，为nil时不报告进度
+	// queued为当前节点尚未处理的子节点数加上等待展开的节点数
+	Progress ProgressFunc
+}
+
+// BuildCWETreeResumable 以可恢复的方式根据视图ID构建CWE树
+//
+// 方法功能:
+// 与BuildCWETreeWithView相同，构建指定视图下的完整CWE树，但采用广度优先的方式逐个展开节点，
+// 并定期通过Checkpoint回调输出当前进度。进度只在节点的子节点全部处理完成后才会保存，
+// 因此任何检查点都是一致的，可以安全地用于恢复。
+//
+// 获取子节点列表失败时构建会中止并返回错误，该节点保留在待处理队列中，恢复后会重新尝试；
+// 单个子节点无法获取时与BuildCWETreeWithView一样会被跳过。
+//
+// 参数:
+// - viewID: string - 视图ID，支持ParseCWEID接受的所有格式
+// - state: *BuildState - 之前保存的构建状态；为nil时从头开始构建
+// - opts: ResumableBuildOptions - 构建选项
+//
+// 返回值:
+// - *Registry: 构建完成的注册表，Root为视图节点
+// - error: 视图ID无效、状态与视图不匹配、获取失败或检查点回调出错时返回错误
+//
+// 使用示例:
+// ```go
+// fetcher := cwe.NewDataFetcher()
+// registry, err := fetcher.BuildCWETreeResumable("1000", previousState, cwe.ResumableBuildOptions{
+//
+//	CheckpointEvery: 20,
+//	Checkpoint: func(state *cwe.BuildState) error {
+//	    data, _ := json.Marshal(state)
+//	    return os.WriteFile("build.state", data, 0644)
+//	},
+//
+// })
+// ```
+func (f *DataFetcher) BuildCWETreeResumable(viewID string, state *BuildState, opts ResumableBuildOptions) (*Registry, error) {
+	span := f.startTreeSpan(viewID, state != nil)
+	registry, err := f.buildCWETreeResumable(viewID, state, opts)
+	endTreeSpan(span, registry, err)
+	return registry, err
+}
+
+// buildCWETreeResumable 实现BuildCWETreeResumable，不包括追踪
+func (f *DataFetcher) buildCWETreeResumable(viewID string, state *BuildState, opts ResumableBuildOptions) (*Registry, error) {
+	normalizedViewID, err := ParseCWEID(viewID)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpointEvery := opts.CheckpointEvery
+	if checkpointEvery <= 0 {
+		checkpointEvery = DefaultCheckpointEvery
+	}
+
+	start := time.Now()
+	f.log().Info("开始构建CWE树", "view", normalizedViewID, "resumed", state != nil)
+
+	var registry *Registry
+	var pending []string
+
+	if state == nil {
+		view, err := f.acceptStale(f.FetchView(normalizedViewID))
+		if err != nil {
+			return nil, fmt.Errorf("获取视图失败: %w", err)
+		}
+
+		registry = NewRegistry()
+		registry.Register(view)
+		registry.Root = view
+		pending = []string{view.ID}
+	} else {
+		if state.ViewID != normalizedViewID {
+			return nil, fmt.Errorf("构建状态属于视图%s，与请求的视图%s不匹配", state.ViewID, normalizedViewID)
+		}
+
+		registry, err = state.Snapshot.ToRegistry()
+		if err != nil {
+			return nil, fmt.Errorf("恢复构建状态失败: %w", err)
+		}
+		pending = append([]string{}, state.Pending...)
+	}
+
+	checkpoint := func() error {
+		if opts.Checkpoint == nil {
+			return nil
+		}
+		return opts.Checkpoint(&BuildState{
+			ViewID:   normalizedViewID,
+			Snapshot: *NewRegistrySnapshot(registry),
+			Pending:  append([]string{}, pending...),
+		})
+	}
+
+	processed := 0
+	for len(pending) > 0 {
+		node, exists := registry.Entries[pending[0]]
+		if !exists {
+			return nil, fmt.Errorf("待处理节点%s不在构建状态中", pending[0])
+		}
+
+		childIDs, err := f.client.GetChildren(node.ID, normalizedViewID)
+		if err != nil {
+			f.log().Error("获取子节点列表失败，构建中止", "id", node.ID, "pending", len(pending), "error", err)
+			if cpErr := checkpoint(); cpErr != nil {
+				return nil, fmt.Errorf("保存检查点失败: %w", cpErr)
+			}
+			return nil, fmt.Errorf("获取%s的子节点失败: %w", node.ID, err)
+		}
+
+		for i, childID := range childIDs {
+			if !strings.HasPrefix(childID, "CWE-") {
+				childID = "CWE-" + childID
+			}
+
+			f.expandChild(registry, node, childID, normalizedViewID, &pending)
+
+			if opts.Progress != nil {
+				opts.Progress(len(registry.Entries), len(childIDs)-i-1+len(pending)-1, childID)
+			}
+		}
+
+		pending = pending[1:]
+		processed++
+		if opts.Progress != nil {
+			opts.Progress(len(registry.Entries), len(pending), node.ID)
+		}
+
+		if processed%checkpointEvery == 0 || len(pending) == 0 {
+			if err := checkpoint(); err != nil {
+				return nil, fmt.Errorf("保存检查点失败: %w", err)
+			}
+		}
+	}
+
+	// 恢复时队列可能已为空，此时也输出一次最终状态
+	if processed == 0 {
+		if err := checkpoint(); err != nil {
+			return nil, fmt.Errorf("保存检查点失败: %w", err)
+		}
+	}
+
+	if err := f.checkEmptyView(registry, normalizedViewID); err != nil {
+		return nil, err
+	}
+
+	// 子节点的顺序取决于API的响应顺序，统一按编号排序使结果可复现
+	registry.SortChildren(nil)
+
+	f.log().Info("CWE树构建完成", "view", normalizedViewID, "nodes", len(registry.Entries), "duration", time.Since(start))
+	return registry, nil
+}
+
+// expandChild 处理节点的一个子节点，新获取的子节点会被注册并加入待处理队列
+func (f *DataFetcher) expandChild(registry *Registry, node *CWE, childID, viewID string, pending *[]string) {
+	if existingChild, exists := registry.Entries[childID]; exists {
+		node.AddChild(existingChild)
+		registry.tagViewChild(viewID, node.ID, existingChild.ID)
+		return
+	}
+
+	child, isNew, err := f.fetchTreeNode(registry, node, childID, viewID)
+	if err != nil {
+		// 跳过无法获取或被钩子跳过的节点
+		return
+	}
+	if !isNew {
+		node.AddChild(child)
+		registry.tagViewChild(viewID, node.ID, child.ID)
+		return
+	}
+
+	registry.Register(child)
+	node.AddChild(child)
+	registry.tagViewChild(viewID, node.ID, child.ID)
+	*pending = append(*pending, child.ID)
+}
+
+// fetchWeaknessOrCategory 依次尝试将ID作为弱点和类别获取
+func (f *DataFetcher) fetchWeaknessOrCategory(id string) (*CWE, error) {
+	cwe, err := f.acceptStale(f.FetchWeakness(id))
+	if err == nil {
+		return cwe, nil
+	}
+	return f.acceptStale(f.FetchCategory(id))
+}
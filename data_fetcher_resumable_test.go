@@ -0,0 +1,159 @@
+package cwe
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// setupResumableTreeServer 创建一个模拟视图CWE-1000的服务器
+// 树结构: CWE-1000 -> CWE-20 -> (CWE-79, CWE-89)；CWE-1000 -> CWE-89
+// failChildren为1时，CWE-20的子节点请求返回404
+func setupResumableTreeServer(failChildren *int32) *httptest.Server {
+	children := map[string][]string{
+		"CWE-1000": {"20", "89"},
+		"CWE-20":   {"79", "89"},
+		"CWE-79":   {},
+		"CWE-89":   {},
+	}
+	weaknesses := map[string]string{
+		"CWE-79": "Cross-site Scripting",
+		"CWE-89": "SQL Injection",
+	}
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/cwe/view/CWE-1000", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"views": []map[string]interface{}{{"id": "CWE-1000", "name": "Research Concepts"}},
+		})
+	})
+	handler.HandleFunc("/cwe/category/CWE-20", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"categories": []map[string]interface{}{{"id": "CWE-20", "name": "Improper Input Validation"}},
+		})
+	})
+	handler.HandleFunc("/cwe/weakness/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/cwe/weakness/")
+		name, exists := weaknesses[id]
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"weaknesses": []map[string]interface{}{{"id": id, "name": name}},
+		})
+	})
+	handler.HandleFunc("/cwe/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/cwe/"), "/children")
+		ids, exists := children[id]
+		if !exists || (id == "CWE-20" && atomic.LoadInt32(failChildren) == 1) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(ids)
+	})
+
+	return httptest.NewServer(handler)
+}
+
+func newResumableTestFetcher(serverURL string) *DataFetcher {
+	client := NewAPIClientWithOptions(serverURL, DefaultTimeout, NewHTTPRateLimiter(0))
+	client.GetHTTPClient().SetRetryDelay(time.Millisecond)
+	return NewDataFetcherWithClient(client)
+}
+
+func TestBuildCWETreeResumable(t *testing.T) {
+	var failChildren int32
+	server := setupResumableTreeServer(&failChildren)
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+
+	checkpoints := 0
+	registry, err := fetcher.BuildCWETreeResumable("1000", nil, ResumableBuildOptions{
+		CheckpointEvery: 1,
+		Checkpoint: func(state *BuildState) error {
+			checkpoints++
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("BuildCWETreeResumable失败: %v", err)
+	}
+
+	if len(registry.Entries) != 4 {
+		t.Errorf("期望4个条目，但得到 %d 个", len(registry.Entries))
+	}
+	if registry.Root == nil || registry.Root.ID != "CWE-1000" {
+		t.Fatalf("根节点不正确: %v", registry.Root)
+	}
+	if len(registry.Root.Children) != 2 {
+		t.Errorf("根节点期望2个子节点，但有 %d 个", len(registry.Root.Children))
+	}
+	if len(registry.Entries["CWE-20"].Children) != 2 {
+		t.Errorf("CWE-20期望2个子节点，但有 %d 个", len(registry.Entries["CWE-20"].Children))
+	}
+	if checkpoints != 4 {
+		t.Errorf("每个节点处理后都应保存检查点，期望4次，但得到 %d 次", checkpoints)
+	}
+}
+
+func TestBuildCWETreeResumableResume(t *testing.T) {
+	var failChildren int32
+	atomic.StoreInt32(&failChildren, 1)
+	server := setupResumableTreeServer(&failChildren)
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+
+	var saved []byte
+	save := func(state *BuildState) error {
+		data, err := json.Marshal(state)
+		saved = data
+		return err
+	}
+
+	_, err := fetcher.BuildCWETreeResumable("CWE-1000", nil, ResumableBuildOptions{Checkpoint: save})
+	if err == nil {
+		t.Fatal("CWE-20的子节点请求失败时应返回错误")
+	}
+
+	var state BuildState
+	if err := json.Unmarshal(saved, &state); err != nil {
+		t.Fatalf("检查点无法解析: %v", err)
+	}
+	if state.Complete() || state.Pending[0] != "CWE-20" {
+		t.Fatalf("中断后CWE-20应位于待处理队列首位，但得到 %v", state.Pending)
+	}
+
+	// 不匹配的视图应被拒绝
+	if _, err := fetcher.BuildCWETreeResumable("699", &state, ResumableBuildOptions{}); err == nil {
+		t.Error("视图不匹配时应返回错误")
+	}
+
+	atomic.StoreInt32(&failChildren, 0)
+	registry, err := fetcher.BuildCWETreeResumable("1000", &state, ResumableBuildOptions{Checkpoint: save})
+	if err != nil {
+		t.Fatalf("恢复构建失败: %v", err)
+	}
+
+	if len(registry.Entries) != 4 {
+		t.Errorf("期望4个条目，但得到 %d 个", len(registry.Entries))
+	}
+	if len(registry.Entries["CWE-20"].Children) != 2 {
+		t.Errorf("恢复后CWE-20应有2个子节点，但有 %d 个", len(registry.Entries["CWE-20"].Children))
+	}
+	if len(registry.Root.Children) != 2 {
+		t.Errorf("恢复后根节点不应出现重复子节点，但有 %d 个", len(registry.Root.Children))
+	}
+
+	var final BuildState
+	json.Unmarshal(saved, &final)
+	if !final.Complete() {
+		t.Errorf("构建完成后最终状态应为完成，但待处理队列为 %v", final.Pending)
+	}
+}
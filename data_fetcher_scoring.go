@@ -0,0 +1,31 @@
+package cwe
+
+// FetchScoringHint 获取弱点并生成CVSS评分参考
+//
+// 方法功能:
+// 等同于FetchWeaknessFull后调用NewScoringHint。评分参考依赖常见影响、适用平台和利用可能性字段，
+// 通过SetFieldMask丢弃了FieldCommonConsequences或FieldApplicablePlatforms时，相应的指标为空。
+//
+// 参数:
+// - id: string - CWE ID，支持ParseCWEID接受的所有格式
+//
+// 返回值:
+// - ScoringHint: 评分参考
+// - error: ID无效或获取弱点失败时返回错误
+//
+// 使用示例:
+// ```go
+// hint, err := fetcher.FetchScoringHint("CWE-79")
+//
+//	if err == nil && hint.Severity != "" {
+//	    finding.Severity = hint.Severity
+//	}
+//
+// ```
+func (f *DataFetcher) FetchScoringHint(id string) (ScoringHint, error) {
+	_, weakness, err := f.FetchWeaknessFull(id)
+	if err != nil {
+		return ScoringHint{}, err
+	}
+	return NewScoringHint(weakness), nil
+}
@@ -0,0 +1,49 @@
+package cwe
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchScoringHint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/cwe/weakness/CWE-79" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"weaknesses": [{
+			"id": "79",
+			"name": "Cross-site Scripting",
+			"likelihood_of_exploit": "High",
+			"applicable_platforms": [{"type": "Technology", "class": "Web Based", "prevalence": "Often"}],
+			"common_consequences": [
+				{"scope": ["Access Control", "Confidentiality"], "impact": ["Bypass Protection Mechanism", "Read Application Data"]},
+				{"scope": ["Integrity"], "impact": ["Modify Application Data"]}
+			]
+		}]}`)
+	}))
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+
+	hint, err := fetcher.FetchScoringHint("79")
+	if err != nil {
+		t.Fatalf("FetchScoringHint失败: %v", err)
+	}
+	if hint.Vector() != "CVSS:3.1/AV:N/C:H/I:H/A:N" || hint.Severity != "High" {
+		t.Errorf("评分参考不正确: %s %s", hint.Vector(), hint.Severity)
+	}
+
+	fetcher.SetFieldMask(FieldsMinimal)
+	hint, _ = fetcher.FetchScoringHint("CWE-79")
+	if hint.Vector() != "" || hint.LikelihoodOfExploit != "High" {
+		t.Errorf("丢弃常见影响和适用平台后只应保留利用可能性，实际为%+v", hint)
+	}
+
+	if _, err := fetcher.FetchScoringHint("CWE-404"); err == nil {
+		t.Error("弱点不存在时应返回错误")
+	}
+}
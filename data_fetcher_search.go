@@ -0,0 +1,133 @@
+package cwe
+
+import (
+	"errors"
+	"sync"
+)
+
+// searchState 记录Search使用的本地注册表和服务器是否支持搜索
+type searchState struct {
+	mutex sync.Mutex
+
+	// registry 服务器不支持搜索时使用的本地注册表，为nil时使用内置的离线数据
+	registry *Registry
+
+	// unsupported 服务器已经返回过ErrSearchUnsupported，之后不再请求服务器
+	unsupported bool
+}
+
+// SetSearchRegistry 设置Search在服务器不支持搜索时使用的本地注册表
+//
+// 通常传入已经通过BuildCWETreeWithView等方法构建并缓存的注册表。
+// 传入nil时使用内置的离线数据，见NewOfflineRegistry。
+func (f *DataFetcher) SetSearchRegistry(registry *Registry) {
+	f.search.mutex.Lock()
+	defer f.search.mutex.Unlock()
+	f.search.registry = registry
+}
+
+// Search 按关键词搜索条目，优先使用服务器端搜索，不支持时回退到本地注册表
+//
+// 方法功能:
+// 先调用APIClient.Search在服务器端搜索；服务器不支持搜索(ErrSearchUnsupported)时记住这一点，
+// 本次和之后的调用都直接在SetSearchRegistry设置的本地注册表中搜索，未设置时使用内置的离线数据。
+// 服务器搜索因网络等其他原因失败时，本次调用同样回退到本地搜索并以Warn级别记录日志。
+// 两种来源的结果格式相同，本地搜索与Registry.Search一样匹配替代术语和同义词。
+//
+// 参数:
+// - query: string - 关键词
+//
+// 返回值:
+// - []*CWE: 所有匹配的条目，按CWE编号排序；来自本地注册表的条目与注册表共享，调用方不应修改
+// - error: 服务器搜索失败且本地注册表不可用时返回错误
+//
+// 使用示例:
+// ```go
+// fetcher := cwe.NewDataFetcher()
+// fetcher.SetSearchRegistry(cachedRegistry)
+//
+// results, err := fetcher.Search("sql injection")
+//
+//	for _, entry := range results {
+//	    fmt.Println(entry.ID, entry.Name)
+//	}
+//
+// ```
+func (f *DataFetcher) Search(query string) ([]*CWE, error) {
+	f.search.mutex.Lock()
+	unsupported := f.search.unsupported
+	f.search.mutex.Unlock()
+
+	if !unsupported {
+		weaknesses, err := f.client.Search(query)
+		if err == nil {
+			return f.convertSearchResults(weaknesses)
+		}
+		if errors.Is(err, ErrSearchUnsupported) {
+			f.search.mutex.Lock()
+			f.search.unsupported = true
+			f.search.mutex.Unlock()
+			f.log().Info("API不支持关键词搜索，改为在本地注册表中搜索")
+		} else {
+			f.log().Warn("服务器端搜索失败，改为在本地注册表中搜索", "query", query, "error", err)
+		}
+	}
+
+	registry, err := f.searchRegistry()
+	if err != nil {
+		return nil, err
+	}
+	return searchAll(registry, query)
+}
+
+// convertSearchResults 将服务器返回的搜索结果转换为条目并按CWE编号排序
+func (f *DataFetcher) convertSearchResults(weaknesses []*CWEWeakness) ([]*CWE, error) {
+	results := make([]*CWE, 0, len(weaknesses))
+	for _, weakness := range weaknesses {
+		if weakness == nil {
+			continue
+		}
+		entry, err := f.convertToCWE(weakness)
+		if err != nil {
+			return nil, err
+		}
+		if err := f.tagVersion(entry); err != nil {
+			return nil, err
+		}
+		results = append(results, entry)
+	}
+	sortByCWEID(results)
+	return results, nil
+}
+
+// searchRegistry 返回本地搜索使用的注册表，未设置时加载并保存内置的离线数据
+func (f *DataFetcher) searchRegistry() (*Registry, error) {
+	f.search.mutex.Lock()
+	defer f.search.mutex.Unlock()
+
+	if f.search.registry == nil {
+		registry, err := NewOfflineRegistry()
+		if err != nil {
+			return nil, err
+		}
+		f.search.registry = registry
+	}
+	return f.search.registry, nil
+}
+
+// searchAll 在注册表中搜索并返回所有分页的结果
+func searchAll(registry *Registry, query string) ([]*CWE, error) {
+	results := make([]*CWE, 0)
+	search := SearchQuery{Keyword: query, Limit: MaxSearchLimit}
+	for {
+		page, err := registry.Search(search)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, page.Results...)
+		if page.NextCursor == "" {
+			return results, nil
+		}
+		search.Cursor = page.NextCursor
+	}
+}
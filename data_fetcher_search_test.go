@@ -0,0 +1,117 @@
+package cwe
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+// setupSearchTestServer 创建/search返回status的服务器，status为200时分两页返回CWE-89和CWE-79
+func setupSearchTestServer(status *int32, requests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		atomic.AddInt32(requests, 1)
+		if code := int(atomic.LoadInt32(status)); code != http.StatusOK {
+			w.WriteHeader(code)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("cursor") == "" {
+			w.Write([]byte(`{"total": 2, "results": [{"id": "89", "name": "SQL Injection"}], "next_cursor": "page2"}`))
+			return
+		}
+		w.Write([]byte(`{"total": 2, "results": [{"id": "79", "name": "Cross-site Scripting", "alternate_terms": [{"term": "XSS"}]}]}`))
+	}))
+}
+
+func TestAPIClientSearch(t *testing.T) {
+	status, requests := int32(http.StatusOK), int32(0)
+	server := setupSearchTestServer(&status, &requests)
+	defer server.Close()
+
+	client := NewAPIClientWithOptions(server.URL, DefaultTimeout, NewHTTPRateLimiter(0))
+	results, err := client.Search("injection")
+	if err != nil {
+		t.Fatalf("Search失败: %v", err)
+	}
+	if len(results) != 2 || results[0].ID != "89" || results[1].ID != "79" || atomic.LoadInt32(&requests) != 2 {
+		t.Errorf("应沿游标获取所有分页，实际为%d个结果、%d个请求", len(results), requests)
+	}
+
+	atomic.StoreInt32(&status, http.StatusNotFound)
+	if _, err := client.Search("injection"); !errors.Is(err, ErrSearchUnsupported) {
+		t.Errorf("404应返回ErrSearchUnsupported，实际为%v", err)
+	}
+}
+
+func TestDataFetcherSearchServer(t *testing.T) {
+	status, requests := int32(http.StatusOK), int32(0)
+	server := setupSearchTestServer(&status, &requests)
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+	fetcher.SetSearchRegistry(newQueryTestRegistry())
+
+	results, err := fetcher.Search("injection")
+	if err != nil {
+		t.Fatalf("Search失败: %v", err)
+	}
+	if got := cweIDsOf(results); !reflect.DeepEqual(got, []string{"CWE-79", "CWE-89"}) {
+		t.Errorf("服务器结果应按CWE编号排序，实际为%v", got)
+	}
+	if !reflect.DeepEqual(results[0].AlternateTerms, []string{"XSS"}) {
+		t.Errorf("服务器结果应转换为条目: %+v", results[0])
+	}
+}
+
+func TestDataFetcherSearchFallback(t *testing.T) {
+	status, requests := int32(http.StatusMethodNotAllowed), int32(0)
+	server := setupSearchTestServer(&status, &requests)
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+	fetcher.SetSearchRegistry(newQueryTestRegistry())
+
+	for i := 0; i < 2; i++ {
+		results, err := fetcher.Search("sqli")
+		if err != nil {
+			t.Fatalf("Search失败: %v", err)
+		}
+		if got := cweIDsOf(results); !reflect.DeepEqual(got, []string{"CWE-89"}) {
+			t.Errorf("本地搜索结果不正确: %v", got)
+		}
+	}
+	if atomic.LoadInt32(&requests) != 1 {
+		t.Errorf("服务器不支持搜索后不应再请求，实际请求%d次", requests)
+	}
+}
+
+func TestDataFetcherSearchTransientFailure(t *testing.T) {
+	status, requests := int32(http.StatusBadRequest), int32(0)
+	server := setupSearchTestServer(&status, &requests)
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+
+	// 未设置本地注册表时使用内置的离线数据
+	results, err := fetcher.Search("Cross-site Scripting")
+	if err != nil {
+		t.Fatalf("Search失败: %v", err)
+	}
+	if len(results) == 0 || results[0].ID != "CWE-79" {
+		t.Errorf("离线数据的搜索结果不正确: %v", cweIDsOf(results))
+	}
+
+	// 其他错误只影响当次调用，服务器恢复后重新使用服务器端搜索
+	atomic.StoreInt32(&status, http.StatusOK)
+	results, err = fetcher.Search("injection")
+	if err != nil || len(results) != 2 {
+		t.Errorf("服务器恢复后应使用服务器端搜索，实际为%v, %v", cweIDsOf(results), err)
+	}
+}
@@ -0,0 +1,76 @@
+package cwe
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// errFlightPanicked 是获取过程中发生panic时等待者得到的错误
+var errFlightPanicked = errors.New("获取条目时发生panic")
+
+// flightGroup 合并对同一条目的并发获取，使同一时刻每个键只发送一次请求
+// 零值可以直接使用
+type flightGroup struct {
+	mutex sync.Mutex
+	calls map[string]*flightCall
+}
+
+// flightCall 是一次正在进行的获取
+type flightCall struct {
+	done chan struct{}
+
+	// entry 获取结果的快照，等待者据此各自创建副本
+	entry SnapshotEntry
+	err   error
+
+	// raw 获取结果的原始JSON，快照中不包含此字段，见CWE.Raw
+	raw json.RawMessage
+}
+
+// do 执行fn并返回结果，fn执行期间以相同key调用do的goroutine等待并共享同一个结果
+//
+// 第一个调用者得到fn返回的CWE本身，其他调用者各自得到一份不含子节点的副本，
+// 避免多个调用者在同一个CWE对象上建立父子关系而互相影响。副本同样带有启用SetRetainRaw时保留的原始JSON。
+func (g *flightGroup) do(key string, fn func() (*CWE, error)) (*CWE, error) {
+	g.mutex.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	if call, exists := g.calls[key]; exists {
+		g.mutex.Unlock()
+		<-call.done
+		if call.err != nil {
+			return nil, call.err
+		}
+		entry := call.entry.toCWE()
+		if call.raw != nil {
+			entry.raw = append(json.RawMessage(nil), call.raw...)
+		}
+		return entry, nil
+	}
+
+	call := &flightCall{done: make(chan struct{}), err: errFlightPanicked}
+	g.calls[key] = call
+	g.mutex.Unlock()
+
+	// fn发生panic时也要唤醒等待者，否则它们会一直阻塞
+	defer func() {
+		g.mutex.Lock()
+		delete(g.calls, key)
+		g.mutex.Unlock()
+		close(call.done)
+	}()
+
+	entry, err := fn()
+	if err == nil {
+		// 在返回给调用者之前创建快照，调用者之后对entry的修改不会影响等待者
+		call.entry = newSnapshotEntry(entry)
+		call.entry.Children = nil
+		if entry.raw != nil {
+			call.raw = append(json.RawMessage(nil), entry.raw...)
+		}
+	}
+	call.err = err
+	return entry, err
+}
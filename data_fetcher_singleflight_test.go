@@ -0,0 +1,167 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchWeaknessDeduplicatesConcurrentRequests(t *testing.T) {
+	var requests int32
+	arrived := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		select {
+		case arrived <- struct{}{}:
+		default:
+		}
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"weaknesses": [{"id": "79", "name": "Cross-site Scripting"}]}`)
+	}))
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+
+	const callers = 5
+	results := make([]*CWE, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], errs[0] = fetcher.FetchWeakness("79")
+	}()
+	<-arrived
+	for i := 1; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = fetcher.FetchWeakness("CWE-79")
+		}(i)
+	}
+	// 等待其他调用者加入正在进行的获取
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if requests != 1 {
+		t.Errorf("并发获取同一ID应只请求1次，实际为%d次", requests)
+	}
+	seen := make(map[*CWE]bool)
+	for i, result := range results {
+		if errs[i] != nil {
+			t.Fatalf("调用者%d获取失败: %v", i, errs[i])
+		}
+		if result.Name != "Cross-site Scripting" {
+			t.Errorf("调用者%d得到的名称不正确: %s", i, result.Name)
+		}
+		if seen[result] {
+			t.Error("每个调用者应得到独立的CWE对象")
+		}
+		seen[result] = true
+	}
+
+	// 获取完成后再次获取会重新请求
+	if _, err := fetcher.FetchWeakness("79"); err != nil || requests != 2 {
+		t.Errorf("获取完成后应重新请求，实际请求%d次: %v", requests, err)
+	}
+}
+
+func TestFetchWeaknessDeduplicatedRetainsRaw(t *testing.T) {
+	arrived := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case arrived <- struct{}{}:
+		default:
+		}
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"weaknesses": [{"id": "79", "name": "Cross-site Scripting", "Extra": "x"}]}`)
+	}))
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+	fetcher.SetRetainRaw(true)
+
+	const callers = 3
+	results := make([]*CWE, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], errs[0] = fetcher.FetchWeakness("79")
+	}()
+	<-arrived
+	for i := 1; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = fetcher.FetchWeakness("79")
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i, result := range results {
+		if errs[i] != nil {
+			t.Fatalf("调用者%d获取失败: %v", i, errs[i])
+		}
+		if !strings.Contains(string(result.Raw()), `"Extra"`) {
+			t.Errorf("调用者%d应得到原始JSON，实际为%q", i, result.Raw())
+		}
+	}
+	// 每个调用者的原始JSON互相独立
+	results[1].Raw()[0] = 'X'
+	if results[2].Raw()[0] == 'X' || results[0].Raw()[0] == 'X' {
+		t.Error("修改一个调用者的原始JSON不应影响其他调用者")
+	}
+}
+
+func TestFlightGroupSharesError(t *testing.T) {
+	var group flightGroup
+	failure := errors.New("boom")
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		group.do("k", func() (*CWE, error) {
+			close(started)
+			<-release
+			return nil, failure
+		})
+	}()
+	<-started
+
+	var err error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, err = group.do("k", func() (*CWE, error) {
+			t.Error("等待者不应再次执行获取")
+			return nil, nil
+		})
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if !errors.Is(err, failure) {
+		t.Errorf("等待者应得到相同的错误，实际为%v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// StaleDataError 表示从API获取条目失败，返回的条目是存储中之前保存的数据
+//
+// 启用SetStaleFallback后，FetchWeakness、FetchCategory和FetchView在这种情况下同时返回条目和*StaleDataError，
+// 调用方可以用errors.As取出数据的新旧程度，决定是否接受。errors.Is和errors.As也会检查API请求失败的原因。
+type StaleDataError struct {
+	// ID 条目ID
+	ID string
+
+	// FetchedAt 存储中的条目从API获取的时间，零值表示未知(如旧版本写入存储的条目)
+	FetchedAt time.Time
+
+	// Age 返回数据时条目距FetchedAt的时长，FetchedAt未知时为0
+	Age time.Duration
+
+	// Err 从API获取失败的原因
+	Err error
+}
+
+func (e *StaleDataError) Error() string {
+	if e.FetchedAt.IsZero() {
+		return fmt.Sprintf("获取%s失败，使用存储中获取时间未知的数据: %v", e.ID, e.Err)
+	}
+	return fmt.Sprintf("获取%s失败，使用存储中%s前获取的数据: %v", e.ID, e.Age.Round(time.Second), e.Err)
+}
+
+func (e *StaleDataError) Unwrap() error {
+	return e.Err
+}
+
+// staleFallbackState 是DataFetcher回退到存储中过期数据的配置
+type staleFallbackState struct {
+	enabled bool
+
+	// maxAge 可以使用的数据的最长时间，<=0时不限制
+	maxAge time.Duration
+}
+
+// SetStaleFallback 设置从API获取失败时是否回退到存储中的数据
+//
+// 方法功能:
+// 启用后，FetchWeakness、FetchCategory和FetchView从API获取失败时，如果SetStore设置的存储中有该条目，
+// 返回存储中的条目和包装了原始错误的*StaleDataError，而不是只返回错误。存储模式为StoreWriteOnly时同样会读取存储，
+// 这样定期刷新存储的任务在API不可用时仍能得到上一次的数据。通过PinVersion固定了版本时，不会回退到其他版本的条目。
+//
+// 构建树和FetchMultiple等内部使用这些方法的操作会接受过期数据并记录警告日志，不会因此跳过节点或中止构建。
+// 没有设置存储时此设置不起作用。
+//
+// 参数:
+// - enabled: bool - 是否启用回退
+// - maxAge: time.Duration - 可以使用的数据的最长时间，超过时仍返回原始错误；<=0时不限制。
+// 大于0时不会使用获取时间未知的条目
+//
+// 使用示例:
+// ```go
+// fetcher.SetStore(store, cwe.StoreWriteOnly)
+// fetcher.SetStaleFallback(true, 30*24*time.Hour)
+//
+// entry, err := fetcher.FetchWeakness("79")
+// var stale *cwe.StaleDataError
+//
+//	if errors.As(err, &stale) {
+//	    log.Printf("CWE API不可用，使用%s前的数据", stale.Age)
+//	} else if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// ```
+func (f *DataFetcher) SetStaleFallback(enabled bool, maxAge time.Duration) {
+	f.stale = staleFallbackState{enabled: enabled, maxAge: maxAge}
+}
+
+// fallbackToStale 在启用回退且从API获取失败时返回存储中的条目和*StaleDataError，其他情况原样返回
+func (f *DataFetcher) fallbackToStale(id string, entry *CWE, err error) (*CWE, error) {
+	if err == nil || !f.stale.enabled || f.store == nil {
+		return entry, err
+	}
+	normalizedID, parseErr := ParseCWEID(id)
+	if parseErr != nil {
+		return entry, err
+	}
+	stored, getErr := f.store.store.Get(normalizedID)
+	if getErr != nil {
+		return entry, err
+	}
+	if pinned := f.PinnedVersion(); pinned != "" && stored.Version != "" && stored.Version != pinned {
+		return entry, err
+	}
+
+	staleErr := &StaleDataError{ID: normalizedID, FetchedAt: stored.FetchedAt, Err: err}
+	if !stored.FetchedAt.IsZero() {
+		staleErr.Age = time.Since(stored.FetchedAt)
+	}
+	if f.stale.maxAge > 0 && (stored.FetchedAt.IsZero() || staleErr.Age > f.stale.maxAge) {
+		return entry, err
+	}
+	return stored, staleErr
+}
+
+// acceptStale 将*StaleDataError视为获取成功并记录警告日志，供构建树等不向调用方返回单个条目错误的操作使用
+func (f *DataFetcher) acceptStale(entry *CWE, err error) (*CWE, error) {
+	var staleErr *StaleDataError
+	if entry != nil && errors.As(err, &staleErr) {
+		f.log().Warn("从API获取失败，使用存储中的数据", "id", staleErr.ID, "age", staleErr.Age, "error", staleErr.Err)
+		return entry, nil
+	}
+	return entry, err
+}
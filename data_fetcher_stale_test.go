@@ -0,0 +1,118 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newStaleTestServer 返回提供视图CWE-1000及其子节点CWE-79的服务器，failing非0时获取条目的请求返回503，
+// 版本和子节点列表不受影响
+func newStaleTestServer(failing *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/cwe/version":
+			fmt.Fprint(w, `{"version": "4.16"}`)
+		case "/cwe/CWE-1000/children":
+			fmt.Fprint(w, `["79"]`)
+		case "/cwe/CWE-79/children":
+			fmt.Fprint(w, `[]`)
+		case "/cwe/view/CWE-1000", "/cwe/weakness/CWE-79":
+			if atomic.LoadInt32(failing) != 0 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			} else if r.URL.Path == "/cwe/view/CWE-1000" {
+				fmt.Fprint(w, `{"views": [{"id": "1000", "name": "Research Concepts"}]}`)
+			} else {
+				fmt.Fprint(w, `{"weaknesses": [{"id": "79", "name": "Cross-site Scripting"}]}`)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestFetcherStaleFallback(t *testing.T) {
+	var failing int32
+	server := newStaleTestServer(&failing)
+	defer server.Close()
+
+	store := NewMemoryStore()
+	fetcher := newResumableTestFetcher(server.URL)
+	fetcher.SetStore(store, StoreWriteOnly)
+
+	entry, err := fetcher.FetchWeakness("79")
+	if err != nil || entry.FetchedAt.IsZero() {
+		t.Fatalf("写入存储时应记录获取时间: %v, %v", entry, err)
+	}
+
+	atomic.StoreInt32(&failing, 1)
+	if _, err := fetcher.FetchWeakness("79"); err == nil {
+		t.Fatal("未启用回退时应返回错误")
+	}
+
+	fetcher.SetStaleFallback(true, 0)
+	entry, err = fetcher.FetchWeakness("CWE-79")
+	var staleErr *StaleDataError
+	if !errors.As(err, &staleErr) || entry == nil || entry.Name != "Cross-site Scripting" {
+		t.Fatalf("启用回退后应返回存储中的条目和StaleDataError: %v, %v", entry, err)
+	}
+	if staleErr.ID != "CWE-79" || staleErr.FetchedAt.IsZero() || staleErr.Age < 0 || staleErr.Err == nil {
+		t.Errorf("StaleDataError不正确: %+v", staleErr)
+	}
+
+	if _, err := fetcher.FetchWeakness("CWE-80"); err == nil || errors.As(err, &staleErr) {
+		t.Errorf("存储中没有的条目应返回原始错误，实际: %v", err)
+	}
+
+	// 超过最长时间或获取时间未知的数据不使用
+	store.Put(NewCWE("CWE-80", "Unknown Age"))
+	fetcher.SetStaleFallback(true, time.Hour)
+	if _, err := fetcher.FetchWeakness("CWE-80"); err == nil || errors.As(err, &staleErr) {
+		t.Errorf("限制最长时间时不应使用获取时间未知的数据，实际: %v", err)
+	}
+	old, _ := store.Get("CWE-79")
+	old.FetchedAt = time.Now().Add(-2 * time.Hour)
+	old.Version = "4.16"
+	store.Put(old)
+	if _, err := fetcher.FetchWeakness("CWE-79"); err == nil || errors.As(err, &staleErr) {
+		t.Errorf("超过最长时间的数据不应使用，实际: %v", err)
+	}
+	fetcher.SetStaleFallback(true, 0)
+	if _, err := fetcher.FetchWeakness("CWE-80"); !errors.As(err, &staleErr) || !staleErr.FetchedAt.IsZero() || staleErr.Age != 0 {
+		t.Errorf("不限制时间时应使用获取时间未知的数据，实际: %v", err)
+	}
+
+	fetcher.PinVersion("4.15")
+	if _, err := fetcher.FetchWeakness("CWE-79"); err == nil || errors.As(err, &staleErr) {
+		t.Errorf("不应回退到其他版本的条目，实际: %v", err)
+	}
+}
+
+func TestBuildTreeAcceptsStaleData(t *testing.T) {
+	var failing int32
+	server := newStaleTestServer(&failing)
+	defer server.Close()
+
+	store := NewMemoryStore()
+	fetcher := newResumableTestFetcher(server.URL)
+	fetcher.SetStore(store, StoreWriteOnly)
+	if _, err := fetcher.BuildCWETreeWithView("1000"); err != nil {
+		t.Fatalf("构建树失败: %v", err)
+	}
+
+	atomic.StoreInt32(&failing, 1)
+	fetcher.SetStaleFallback(true, 0)
+	fetcher.SetTreeErrorMode(TreeErrorStrict)
+	registry, err := fetcher.BuildCWETreeWithView("1000")
+	if err != nil {
+		t.Fatalf("获取条目失败时应使用存储中的数据构建树: %v", err)
+	}
+	if registry.Root == nil || registry.Root.ID != "CWE-1000" || len(registry.Root.Children) != 1 {
+		t.Errorf("树结构不正确: %v", registry.Root)
+	}
+}
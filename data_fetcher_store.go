@@ -0,0 +1,146 @@
+package cwe
+
+import (
+	"sync"
+	"time"
+)
+
+// StoreMode 控制DataFetcher如何使用SetStore设置的存储
+type StoreMode int
+
+const (
+	// StoreReadWrite 先从存储读取，未命中时从API获取并写入存储
+	// 命中的条目不会刷新，需要定期更新时配合SetStoreMaxAge使用
+	StoreReadWrite StoreMode = iota
+
+	// StoreReadOnly 先从存储读取，未命中时从API获取但不写入存储
+	// 适合多个进程共享一个只由同步任务维护的存储
+	StoreReadOnly
+
+	// StoreWriteOnly 总是从API获取并写入存储，不从存储读取
+	// 适合定期刷新存储中的数据
+	StoreWriteOnly
+)
+
+// storeState 是DataFetcher使用存储的状态
+type storeState struct {
+	store Store
+	mode  StoreMode
+
+	// versionOnce 保证元数据中的CWE版本只在第一次写入时检查一次
+	versionOnce sync.Once
+}
+
+// SetStore 设置FetchWeakness、FetchCategory和FetchView读写的持久化存储
+//
+// 方法功能:
+// 设置后上述方法按mode先查询存储(读穿透)，并将从API成功获取的条目写入存储(写穿透)，
+// 调用方不必关心数据保存在内存、文件还是其他实现了Store的后端中。
+// 构建树时的节点也通过这些方法获取，因此同样会使用存储。
+// 第一次写入时如果存储的元数据中没有CWE版本，会写入DataFetcher.Version返回的版本。
+// 通过PinVersion固定了版本时，存储中属于其他版本的条目视为不存在。
+// 写入存储失败不影响获取结果，只记录警告日志。store为nil时取消使用存储。
+//
+// Store不区分条目类型，存储中已有某个ID时，FetchWeakness、FetchCategory和FetchView都会返回该条目。
+//
+// 默认不检查存储中条目的新旧：StoreReadWrite和StoreReadOnly模式下，条目一旦写入存储就不会再从API获取。
+// 需要刷新时可以用SetStoreMaxAge让超过一定时间的条目重新获取，或由同步任务以StoreWriteOnly模式定期刷新。
+//
+// 参数:
+// - store: Store - 持久化存储，如NewMemoryStore或NewFileStore的返回值
+// - mode: StoreMode - 读写方式
+//
+// 使用示例:
+// ```go
+// store, err := cwe.NewFileStore("cwe-store")
+//
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// fetcher := cwe.NewDataFetcher()
+// fetcher.SetStore(store, cwe.StoreReadWrite)
+//
+// // 第二次运行时直接从存储读取
+// registry, err := fetcher.BuildCWETreeWithView("1000")
+// ```
+func (f *DataFetcher) SetStore(store Store, mode StoreMode) {
+	if store == nil {
+		f.store = nil
+		return
+	}
+	f.store = &storeState{store: store, mode: mode}
+}
+
+// SetStoreMaxAge 设置从存储读取的条目的最长时间
+//
+// 方法功能:
+// 设置后，StoreReadWrite和StoreReadOnly模式下距FetchedAt超过maxAge的条目视为未命中，重新从API获取；
+// StoreReadWrite模式会用新获取的条目覆盖存储中的旧数据。获取时间未知的条目(如旧版本写入存储的条目)同样视为过期。
+// 过期的条目仍可作为SetStaleFallback的回退数据。此设置在调用SetStore更换存储后继续有效。
+//
+// 参数:
+// - maxAge: time.Duration - 条目的最长时间，<=0时不限制(默认)
+//
+// 使用示例:
+// ```go
+// fetcher.SetStore(store, cwe.StoreReadWrite)
+// fetcher.SetStoreMaxAge(7 * 24 * time.Hour)
+// ```
+func (f *DataFetcher) SetStoreMaxAge(maxAge time.Duration) {
+	f.storeMaxAge = maxAge
+}
+
+// loadFromStore 从存储读取条目，未设置存储、模式不允许读取、条目不存在、条目不属于固定的版本
+// 或条目超过SetStoreMaxAge设置的最长时间时返回false
+func (f *DataFetcher) loadFromStore(id string) (*CWE, bool) {
+	if f.store == nil || f.store.mode == StoreWriteOnly {
+		return nil, false
+	}
+	normalizedID, err := ParseCWEID(id)
+	if err != nil {
+		return nil, false
+	}
+	entry, err := f.store.store.Get(normalizedID)
+	if err != nil {
+		return nil, false
+	}
+	if pinned := f.PinnedVersion(); pinned != "" && entry.Version != "" && entry.Version != pinned {
+		return nil, false
+	}
+	if f.storeMaxAge > 0 && (entry.FetchedAt.IsZero() || time.Since(entry.FetchedAt) > f.storeMaxAge) {
+		return nil, false
+	}
+	return entry, true
+}
+
+// saveToStore 将从API获取的条目写入存储，失败时只记录日志
+// 条目没有FetchedAt时记录为当前时间
+func (f *DataFetcher) saveToStore(entry *CWE) {
+	if f.store == nil || f.store.mode == StoreReadOnly {
+		return
+	}
+	if entry.FetchedAt.IsZero() {
+		entry.FetchedAt = time.Now()
+	}
+
+	f.store.versionOnce.Do(func() {
+		metadata, err := f.store.store.Metadata()
+		if err != nil || metadata.Version != "" {
+			return
+		}
+		version, err := f.sourceVersion(true)
+		if err != nil || version == "" {
+			return
+		}
+		metadata.Version = version
+		metadata.UpdatedAt = time.Now()
+		if err := f.store.store.SetMetadata(metadata); err != nil {
+			f.log().Warn("写入存储元数据失败", "error", err)
+		}
+	})
+
+	if err := f.store.store.Put(entry); err != nil {
+		f.log().Warn("写入存储失败", "id", entry.ID, "error", err)
+	}
+}
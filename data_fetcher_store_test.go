@@ -0,0 +1,133 @@
+package cwe
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newStoreTestServer 返回提供CWE-79和版本信息的服务器，requests记录弱点请求次数
+func newStoreTestServer(requests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/cwe/version":
+			fmt.Fprint(w, `{"version": "4.16"}`)
+		case "/cwe/weakness/CWE-79":
+			atomic.AddInt32(requests, 1)
+			fmt.Fprint(w, `{"weaknesses": [{"id": "79", "name": "Cross-site Scripting"}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestFetcherStoreReadWrite(t *testing.T) {
+	var requests int32
+	server := newStoreTestServer(&requests)
+	defer server.Close()
+
+	store := NewMemoryStore()
+	fetcher := newResumableTestFetcher(server.URL)
+	fetcher.SetStore(store, StoreReadWrite)
+
+	for i := 0; i < 2; i++ {
+		entry, err := fetcher.FetchWeakness("79")
+		if err != nil {
+			t.Fatalf("FetchWeakness失败: %v", err)
+		}
+		if entry.Name != "Cross-site Scripting" {
+			t.Errorf("名称不正确: %s", entry.Name)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("第二次应从存储读取，实际请求%d次", requests)
+	}
+
+	if _, err := store.Get("CWE-79"); err != nil {
+		t.Errorf("获取的条目应写入存储: %v", err)
+	}
+	if metadata, _ := store.Metadata(); metadata.Version != "4.16" {
+		t.Errorf("第一次写入时应记录CWE版本，实际为%+v", metadata)
+	}
+
+	if _, err := fetcher.FetchWeakness("CWE-404"); err == nil {
+		t.Error("条目不存在时应返回错误")
+	}
+}
+
+func TestFetcherStoreModes(t *testing.T) {
+	var requests int32
+	server := newStoreTestServer(&requests)
+	defer server.Close()
+
+	store := NewMemoryStore()
+	fetcher := newResumableTestFetcher(server.URL)
+
+	fetcher.SetStore(store, StoreReadOnly)
+	fetcher.FetchWeakness("CWE-79")
+	if ids, _ := store.List(); len(ids) != 0 {
+		t.Errorf("只读模式不应写入存储，实际为%v", ids)
+	}
+
+	store.Put(NewCWE("CWE-79", "Stored"))
+	if entry, _ := fetcher.FetchWeakness("CWE-79"); entry.Name != "Stored" {
+		t.Errorf("只读模式应从存储读取，实际为%s", entry.Name)
+	}
+
+	fetcher.SetStore(store, StoreWriteOnly)
+	if entry, _ := fetcher.FetchWeakness("CWE-79"); entry.Name != "Cross-site Scripting" {
+		t.Errorf("只写模式应从API获取，实际为%s", entry.Name)
+	}
+	if entry, _ := store.Get("CWE-79"); entry.Name != "Cross-site Scripting" {
+		t.Errorf("只写模式应刷新存储，实际为%s", entry.Name)
+	}
+	if requests != 2 {
+		t.Errorf("应请求2次，实际为%d", requests)
+	}
+
+	fetcher.SetStore(nil, StoreReadWrite)
+	fetcher.FetchWeakness("CWE-79")
+	if requests != 3 {
+		t.Errorf("取消存储后应直接请求API，实际请求%d次", requests)
+	}
+}
+
+func TestFetcherStoreMaxAge(t *testing.T) {
+	var requests int32
+	server := newStoreTestServer(&requests)
+	defer server.Close()
+
+	store := NewMemoryStore()
+	fetcher := newResumableTestFetcher(server.URL)
+	fetcher.SetStore(store, StoreReadWrite)
+	fetcher.SetStoreMaxAge(time.Hour)
+
+	old := NewCWE("CWE-79", "Stored")
+	old.FetchedAt = time.Now().Add(-2 * time.Hour)
+	store.Put(old)
+	if entry, _ := fetcher.FetchWeakness("79"); entry == nil || entry.Name != "Cross-site Scripting" {
+		t.Errorf("超过最长时间的条目应重新获取，实际为%v", entry)
+	}
+	if entry, _ := store.Get("CWE-79"); entry.Name != "Cross-site Scripting" || time.Since(entry.FetchedAt) > time.Minute {
+		t.Errorf("重新获取的条目应写入存储，实际为%v", entry)
+	}
+	if _, err := fetcher.FetchWeakness("79"); err != nil || requests != 1 {
+		t.Errorf("未过期的条目应从存储读取，实际请求%d次: %v", requests, err)
+	}
+
+	// 获取时间未知的条目视为过期
+	store.Put(NewCWE("CWE-79", "Unknown Age"))
+	if entry, _ := fetcher.FetchWeakness("79"); entry.Name != "Cross-site Scripting" || requests != 2 {
+		t.Errorf("获取时间未知的条目应重新获取，实际请求%d次", requests)
+	}
+
+	fetcher.SetStoreMaxAge(0)
+	store.Put(old)
+	if entry, _ := fetcher.FetchWeakness("79"); entry.Name != "Stored" || requests != 2 {
+		t.Errorf("不限制时间时应从存储读取，实际为%s", entry.Name)
+	}
+}
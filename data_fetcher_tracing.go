@@ -0,0 +1,79 @@
+package cwe
+
+import (
+	"context"
+	"errors"
+)
+
+// SetTracerProvider 设置数据获取器记录树构建span的TracerProvider
+//
+// 方法功能:
+// 设置后BuildCWETreeWithView、BuildCWETreeWithViewProgress和BuildCWETreeResumable每次构建创建一个名为
+// "cwe.BuildCWETree"的span，属性包括cwe.view、cwe.resumed，构建结束时补充cwe.nodes(注册表中的条目数)、
+// cwe.root_children(视图的直接子节点数)和cwe.failed_nodes(获取失败的节点数)，构建失败时记录错误。
+// 未设置时使用API客户端的TracerProvider(见HTTPClient.SetTracerProvider)，两者都未设置时不追踪。
+//
+// 参数:
+// - provider: TracerProvider - 为nil时使用API客户端的TracerProvider
+func (f *DataFetcher) SetTracerProvider(provider TracerProvider) {
+	f.tracerProvider = provider
+}
+
+// startSpan 创建数据获取器的span，未设置TracerProvider时返回不记录任何内容的span
+func (f *DataFetcher) startSpan(name string) Span {
+	provider := f.tracerProvider
+	if provider == nil && f.client != nil {
+		provider = f.client.GetHTTPClient().GetTracerProvider()
+	}
+	if provider == nil {
+		return nopSpan{}
+	}
+
+	_, span := provider.Tracer(TracerName).Start(context.Background(), name)
+	return span
+}
+
+// startTreeSpan 创建树构建的span
+func (f *DataFetcher) startTreeSpan(viewID string, resumed bool) Span {
+	if normalized, err := ParseCWEID(viewID); err == nil {
+		viewID = normalized
+	}
+
+	span := f.startSpan("cwe.BuildCWETree")
+	span.SetAttributes(
+		SpanAttribute{Key: "cwe.view", Value: viewID},
+		SpanAttribute{Key: "cwe.resumed", Value: resumed},
+	)
+	return span
+}
+
+// endTreeSpan 补充树构建结果的属性并结束span，registry为nil表示构建失败
+func endTreeSpan(span Span, registry *Registry, err error) {
+	if registry != nil {
+		failed := 0
+		var failures *MultiError
+		if errors.As(err, &failures) {
+			failed = len(failures.Errors)
+		}
+		rootChildren := 0
+		if registry.Root != nil {
+			rootChildren = len(registry.Root.Children)
+		}
+		span.SetAttributes(
+			SpanAttribute{Key: "cwe.nodes", Value: len(registry.Entries)},
+			SpanAttribute{Key: "cwe.root_children", Value: rootChildren},
+			SpanAttribute{Key: "cwe.failed_nodes", Value: failed},
+		)
+	}
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// nopSpan 是不记录任何内容的Span
+type nopSpan struct{}
+
+func (nopSpan) SetAttributes(attrs ...SpanAttribute) {}
+func (nopSpan) RecordError(err error)                {}
+func (nopSpan) End()                                 {}
@@ -1,21 +1,65 @@
 package cwe
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 )
 
 // BuildCWETreeWithView 根据视图ID构建完整的CWE树
+// 视图没有子节点时的处理方式见SetFailOnEmptyView，单个节点获取失败时的处理方式见SetTreeErrorMode
+// 构建完成后所有条目的子节点按CWE编号排序，见Registry.SortChildren
 func (f *DataFetcher) BuildCWETreeWithView(viewID string) (*Registry, error) {
+	return f.BuildCWETreeWithViewProgress(viewID, nil)
+}
+
+// BuildCWETreeWithViewProgress 根据视图ID构建完整的CWE树，并通过progress报告进度
+//
+// 方法功能:
+// 与BuildCWETreeWithView相同，另外在获取视图后以及处理完每个子节点后调用progress，
+// 便于命令行工具或界面在耗时数分钟的完整构建中显示进度条。
+// progress在构建所在的goroutine中同步调用，应尽快返回。
+//
+// 参数:
+// - viewID: string - 视图ID，支持ParseCWEID接受的所有格式
+// - progress: ProgressFunc - 进度回调，为nil时不报告进度
+//
+// 返回值:
+// - *Registry: 构建完成的注册表，Root为视图节点
+// - error: 与BuildCWETreeWithView相同；TreeErrorCollect模式下有节点获取失败时为*MultiError，注册表仍然返回
+//
+// 使用示例:
+// ```go
+// fetcher := cwe.NewDataFetcher()
+//
+//	registry, err := fetcher.BuildCWETreeWithViewProgress("1000", func(fetched, queued int, currentID string) {
+//	    fmt.Printf("\r已获取%d个，待处理%d个，当前%s", fetched, queued, currentID)
+//	})
+//
+// ```
+func (f *DataFetcher) BuildCWETreeWithViewProgress(viewID string, progress ProgressFunc) (*Registry, error) {
+	span := f.startTreeSpan(viewID, false)
+	registry, err := f.buildCWETreeWithView(viewID, progress)
+	endTreeSpan(span, registry, err)
+	return registry, err
+}
+
+// buildCWETreeWithView 实现BuildCWETreeWithViewProgress，不包括追踪
+func (f *DataFetcher) buildCWETreeWithView(viewID string, progress ProgressFunc) (*Registry, error) {
 	normalizedViewID, err := ParseCWEID(viewID)
 	if err != nil {
 		return nil, err
 	}
 
+	start := time.Now()
+	f.log().Info("开始构建CWE树", "view", normalizedViewID)
+
 	// 获取视图信息
-	view, err := f.FetchView(normalizedViewID)
+	view, err := f.acceptStale(f.FetchView(normalizedViewID))
 	if err != nil {
+		f.log().Error("获取视图失败", "view", normalizedViewID, "error", err)
 		return nil, fmt.Errorf("获取视图失败: %w", err)
 	}
 
@@ -23,22 +67,43 @@ func (f *DataFetcher) BuildCWETreeWithView(viewID string) (*Registry, error) {
 	registry.Register(view)
 	registry.Root = view
 
+	tracker := &treeProgress{callback: progress}
+	tracker.report(registry, view.ID)
+
 	// 获取树中所有节点并添加到注册表
-	err = f.populateTree(registry, view, normalizedViewID)
+	failures := &MultiError{}
+	err = f.populateTree(registry, view, normalizedViewID, tracker, failures)
 	if err != nil {
+		f.log().Error("填充CWE树失败", "view", normalizedViewID, "error", err)
 		return nil, fmt.Errorf("填充CWE树失败: %w", err)
 	}
 
+	if err := f.checkEmptyView(registry, normalizedViewID); err != nil {
+		return nil, err
+	}
+
+	// 子节点的顺序取决于API的响应顺序，统一按编号排序使结果可复现
+	registry.SortChildren(nil)
+
+	if len(failures.Errors) > 0 {
+		f.log().Warn("CWE树构建完成，部分节点获取失败", "view", normalizedViewID, "nodes", len(registry.Entries), "failed", len(failures.Errors), "duration", time.Since(start))
+		return registry, failures
+	}
+
+	f.log().Info("CWE树构建完成", "view", normalizedViewID, "nodes", len(registry.Entries), "duration", time.Since(start))
 	return registry, nil
 }
 
 // 辅助方法：递归填充CWE树
-func (f *DataFetcher) populateTree(registry *Registry, node *CWE, viewID string) error {
+// progress为nil时不报告进度；节点获取失败按treeErrorMode记录到failures，
+// 严格模式下返回*NodeError，其他错误表示node自身的子节点列表获取失败
+func (f *DataFetcher) populateTree(registry *Registry, node *CWE, viewID string, progress *treeProgress, failures *MultiError) error {
 	// 获取当前节点的直接子节点
 	childrenIDs, err := f.client.GetChildren(node.ID, viewID)
 	if err != nil {
 		return err
 	}
+	progress.enqueue(len(childrenIDs))
 
 	// 没有子节点，直接返回
 	if len(childrenIDs) == 0 {
@@ -51,24 +116,36 @@ func (f *DataFetcher) populateTree(registry *Registry, node *CWE, viewID string)
 		if !strings.HasPrefix(childID, "CWE-") {
 			childID = "CWE-" + childID
 		}
+		progress.dequeue()
 
 		// 检查是否已经在注册表中
 		existingChild, err := registry.GetByID(childID)
 		if err == nil {
 			// 已存在，直接添加关系
 			node.AddChild(existingChild)
+			registry.tagViewChild(viewID, node.ID, existingChild.ID)
+			progress.report(registry, childID)
 			continue
 		}
 
-		// 尝试获取子节点
-		child, err := f.FetchWeakness(childID)
+		// 获取子节点，依次尝试作为weakness和category
+		child, isNew, err := f.fetchTreeNode(registry, node, childID, viewID)
 		if err != nil {
-			// 如果不是weakness，尝试作为category获取
-			child, err = f.FetchCategory(childID)
-			if err != nil {
-				// 跳过无法获取的节点
+			// 跳过无法获取或被钩子跳过的节点
+			progress.report(registry, childID)
+			if errors.Is(err, ErrSkipNode) {
 				continue
 			}
+			if err := f.recordNodeFailure(failures, childID, node.ID, err); err != nil {
+				return err
+			}
+			continue
+		}
+		if !isNew {
+			node.AddChild(child)
+			registry.tagViewChild(viewID, node.ID, child.ID)
+			progress.report(registry, childID)
+			continue
 		}
 
 		// 添加到注册表
@@ -76,11 +153,22 @@ func (f *DataFetcher) populateTree(registry *Registry, node *CWE, viewID string)
 
 		// 添加为子节点
 		node.AddChild(child)
+		registry.tagViewChild(viewID, node.ID, child.ID)
+		progress.report(registry, child.ID)
 
 		// 递归处理子节点
-		err = f.populateTree(registry, child, viewID)
+		err = f.populateTree(registry, child, viewID, progress, failures)
 		if err != nil {
+			var nodeErr *NodeError
+			if errors.As(err, &nodeErr) {
+				// 严格模式下的失败，中止构建
+				return err
+			}
 			// 处理错误但继续其他节点
+			f.log().Warn("获取子节点列表失败，已跳过其子树", "id", child.ID, "error", err)
+			if err := f.recordNodeFailure(failures, child.ID, node.ID, fmt.Errorf("获取子节点列表失败: %w", err)); err != nil {
+				return err
+			}
 			continue
 		}
 	}
@@ -163,9 +251,9 @@ func isParentRelation(relationType string) bool {
 // sortAllNodes 递归排序树中所有节点的子节点
 func sortAllNodes(nodes []*TreeNode) {
 	for _, node := range nodes {
-		// 根据CWE ID排序子节点
-		sort.Slice(node.Children, func(i, j int) bool {
-			return strings.Compare(node.Children[i].CWE.ID, node.Children[j].CWE.ID) < 0
+		// 根据CWE编号排序子节点
+		sort.SliceStable(node.Children, func(i, j int) bool {
+			return compareCWEIDs(node.Children[i].CWE.ID, node.Children[j].CWE.ID) < 0
 		})
 
 		// 递归排序子节点的子节点
@@ -0,0 +1,127 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// versionState 是DataFetcher的CWE版本状态
+type versionState struct {
+	mutex sync.Mutex
+
+	// pinned PinVersion固定的版本，为空表示不固定
+	pinned string
+
+	// detected 通过GetVersion获取的服务器版本，获取失败时为空
+	detected string
+
+	// checked 是否已经查询过服务器版本，每个DataFetcher只查询一次
+	checked bool
+}
+
+// PinVersion 将DataFetcher固定到指定的CWE内容版本
+//
+// 方法功能:
+// 官方API只提供最新版本的数据，因此固定版本的作用是保证获取到的数据确实属于该版本:
+// 第一次获取条目时通过GetVersion查询服务器版本，与固定的版本不同时
+// FetchWeakness、FetchCategory、FetchView、FetchMultiple和构建树的方法都返回包装了ErrVersionMismatch的错误，
+// 而不是悄悄地混入新版本的数据；SetStore设置的存储中版本不同的条目也会被忽略。
+// 服务器不提供版本信息时无法检查，获取的条目按固定的版本标记。
+// 使用NewCachedAPIClient时同时设置缓存使用的版本，离线时可以直接读取该版本的缓存。
+// version为空时取消固定。
+//
+// 参数:
+// - version: string - CWE内容版本，如"4.16"
+//
+// 使用示例:
+// ```go
+// fetcher := cwe.NewDataFetcher()
+// fetcher.PinVersion("4.16")
+//
+// registry, err := fetcher.BuildCWETreeWithView("1000")
+//
+//	if errors.Is(err, cwe.ErrVersionMismatch) {
+//	    log.Fatal("服务器已更新到新的CWE版本")
+//	}
+//
+// ```
+func (f *DataFetcher) PinVersion(version string) {
+	version = strings.TrimSpace(version)
+
+	f.version.mutex.Lock()
+	f.version.pinned = version
+	f.version.mutex.Unlock()
+
+	if version != "" {
+		f.client.SetCacheVersion(version)
+	}
+}
+
+// PinnedVersion 返回PinVersion固定的版本，未固定时返回空字符串
+func (f *DataFetcher) PinnedVersion() string {
+	f.version.mutex.Lock()
+	defer f.version.mutex.Unlock()
+	return f.version.pinned
+}
+
+// Version 返回DataFetcher获取的条目所属的CWE内容版本
+//
+// 方法功能:
+// 返回固定的版本或服务器的版本，也是获取的CWE条目的Version字段和构建出的注册表的版本。
+// 服务器版本只查询一次，之后直接返回查询结果。
+// 未固定版本时，获取条目不会为了查询版本而发送额外的请求，调用过此方法(或SetStore的存储写入过条目)
+// 之后获取的条目才会标记版本；需要每个条目都带有版本时，在获取之前调用一次。
+//
+// 返回值:
+// - string: CWE内容版本
+// - error: 服务器版本与固定的版本不同时返回包装了ErrVersionMismatch的错误，
+// 未固定版本且无法获取服务器版本时返回错误
+func (f *DataFetcher) Version() (string, error) {
+	version, err := f.sourceVersion(true)
+	if err != nil {
+		return "", err
+	}
+	if version == "" {
+		return "", errors.New("无法获取CWE版本")
+	}
+	return version, nil
+}
+
+// sourceVersion 返回用于标记条目的版本，无法确定版本时返回空字符串
+// detect为true或固定了版本时，第一次调用会通过GetVersion查询服务器版本；
+// 否则只使用已经查询到的版本，不发送额外的请求
+func (f *DataFetcher) sourceVersion(detect bool) (string, error) {
+	f.version.mutex.Lock()
+	defer f.version.mutex.Unlock()
+
+	pinned := f.version.pinned
+	if !f.version.checked && (detect || pinned != "") {
+		f.version.checked = true
+		if resp, err := f.client.GetVersion(); err == nil {
+			f.version.detected = resp.Version
+		} else {
+			f.log().Debug("获取CWE版本失败", "error", err)
+		}
+	}
+
+	detected := f.version.detected
+	if pinned == "" {
+		return detected, nil
+	}
+	if detected != "" && detected != pinned {
+		return "", fmt.Errorf("%w: 固定的版本为%s，服务器的版本为%s", ErrVersionMismatch, pinned, detected)
+	}
+	return pinned, nil
+}
+
+// tagVersion 用来源版本标记从API获取的条目
+func (f *DataFetcher) tagVersion(cwe *CWE) error {
+	version, err := f.sourceVersion(false)
+	if err != nil {
+		return err
+	}
+	cwe.Version = version
+	return nil
+}
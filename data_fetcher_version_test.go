@@ -0,0 +1,122 @@
+package cwe
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFetcherVersionTagsEntries(t *testing.T) {
+	var requests int32
+	server := newStoreTestServer(&requests)
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+
+	entry, err := fetcher.FetchWeakness("79")
+	if err != nil {
+		t.Fatalf("FetchWeakness失败: %v", err)
+	}
+	if entry.Version != "" {
+		t.Errorf("未查询版本时不应标记版本，实际为%q", entry.Version)
+	}
+
+	version, err := fetcher.Version()
+	if err != nil || version != "4.16" {
+		t.Fatalf("Version应返回服务器版本，实际为%q, %v", version, err)
+	}
+
+	entry, err = fetcher.FetchWeakness("79")
+	if err != nil {
+		t.Fatalf("FetchWeakness失败: %v", err)
+	}
+	if entry.Version != "4.16" {
+		t.Errorf("查询版本后获取的条目应标记版本，实际为%q", entry.Version)
+	}
+
+	registry := NewRegistry()
+	if err := registry.Register(entry); err != nil {
+		t.Fatalf("注册失败: %v", err)
+	}
+	if registry.Version() != "4.16" {
+		t.Errorf("注册表应采用条目的版本，实际为%q", registry.Version())
+	}
+}
+
+func TestFetcherPinVersion(t *testing.T) {
+	var requests int32
+	server := newStoreTestServer(&requests)
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+	fetcher.PinVersion("4.16")
+
+	entry, err := fetcher.FetchWeakness("79")
+	if err != nil {
+		t.Fatalf("版本一致时FetchWeakness失败: %v", err)
+	}
+	if entry.Version != "4.16" {
+		t.Errorf("条目应标记固定的版本，实际为%q", entry.Version)
+	}
+
+	pinned := newResumableTestFetcher(server.URL)
+	pinned.PinVersion("4.15")
+	if pinned.PinnedVersion() != "4.15" {
+		t.Errorf("PinnedVersion不正确: %q", pinned.PinnedVersion())
+	}
+
+	before := requests
+	if _, err := pinned.FetchWeakness("79"); !errors.Is(err, ErrVersionMismatch) {
+		t.Errorf("服务器版本不同时应返回ErrVersionMismatch，实际为%v", err)
+	}
+	if _, err := pinned.FetchMultiple([]string{"79"}); !errors.Is(err, ErrVersionMismatch) {
+		t.Errorf("FetchMultiple应返回ErrVersionMismatch，实际为%v", err)
+	}
+	if _, err := pinned.Version(); !errors.Is(err, ErrVersionMismatch) {
+		t.Errorf("Version应返回ErrVersionMismatch，实际为%v", err)
+	}
+	if requests != before+1 {
+		t.Errorf("版本不同时仍会请求条目，但只应请求一次，实际为%d次", requests-before)
+	}
+}
+
+func TestFetcherPinVersionIgnoresStoredEntriesOfOtherVersions(t *testing.T) {
+	var requests int32
+	server := newStoreTestServer(&requests)
+	defer server.Close()
+
+	store := NewMemoryStore()
+	stale := NewCWE("CWE-79", "Old Name")
+	stale.Version = "4.15"
+	store.Put(stale)
+
+	fetcher := newResumableTestFetcher(server.URL)
+	fetcher.SetStore(store, StoreReadWrite)
+	fetcher.PinVersion("4.16")
+
+	entry, err := fetcher.FetchWeakness("CWE-79")
+	if err != nil {
+		t.Fatalf("FetchWeakness失败: %v", err)
+	}
+	if entry.Name != "Cross-site Scripting" || requests != 1 {
+		t.Errorf("存储中其他版本的条目应被忽略，实际为%q，请求%d次", entry.Name, requests)
+	}
+	if stored, _ := store.Get("CWE-79"); stored.Version != "4.16" {
+		t.Errorf("存储中的条目应更新为固定的版本，实际为%q", stored.Version)
+	}
+}
+
+func TestFetcherVersionUnavailable(t *testing.T) {
+	server := setupResumableTreeServer(new(int32))
+	defer server.Close()
+
+	fetcher := newResumableTestFetcher(server.URL)
+	if _, err := fetcher.Version(); err == nil {
+		t.Error("服务器不提供版本时Version应返回错误")
+	}
+
+	fetcher.PinVersion("4.16")
+	version, err := fetcher.Version()
+	if err != nil || version != "4.16" {
+		t.Errorf("无法检查时应返回固定的版本，实际为%q, %v", version, err)
+	}
+}
@@ -0,0 +1,108 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ExtendWithView 将另一个视图的父子关系加入已有的注册表
+//
+// 方法功能:
+// 从视图节点开始广度优先地获取该视图下每个节点的子节点列表，已在注册表中的条目直接复用，不会重新获取；
+// 每条父子关系通过AddViewChild记录所属视图，之后可以用ChildrenInView按视图查询。
+// 注册表的Root保持不变，新视图的视图节点作为普通条目注册。
+// 同一条目在不同视图中常有不同的父节点，建议先将注册表设置为HierarchyMultiParent模式；
+// HierarchyStrict模式下遇到不同的父节点会返回包装了ErrMultipleParents的错误。
+// 单个节点获取失败时的处理方式与BuildCWETreeWithView相同，见SetTreeErrorMode。
+//
+// 参数:
+// - registry: *Registry - 要扩展的注册表，通常由BuildCWETreeWithView构建
+// - viewID: string - 视图ID，支持ParseCWEID接受的所有格式
+//
+// 返回值:
+// - error: 获取视图或视图的子节点列表失败时返回错误；TreeErrorCollect模式下有节点获取失败时为*MultiError
+//
+// 使用示例:
+// ```go
+// registry, err := fetcher.BuildCWETreeWithView("1000")
+// registry.SetHierarchyMode(cwe.HierarchyMultiParent)
+// err = fetcher.ExtendWithView(registry, "699")
+//
+// research, _ := registry.ChildrenInView("CWE-20", "1000")
+// development, _ := registry.ChildrenInView("CWE-20", "699")
+// ```
+func (f *DataFetcher) ExtendWithView(registry *Registry, viewID string) error {
+	normalizedViewID, err := ParseCWEID(viewID)
+	if err != nil {
+		return err
+	}
+
+	view, err := registry.GetByID(normalizedViewID)
+	if err != nil {
+		if view, err = f.acceptStale(f.FetchView(normalizedViewID)); err != nil {
+			return fmt.Errorf("获取视图失败: %w", err)
+		}
+		if err := registry.Register(view); err != nil {
+			return err
+		}
+	}
+
+	f.log().Info("开始扩展视图", "view", normalizedViewID, "nodes", len(registry.Entries))
+
+	failures := &MultiError{}
+	expanded := map[string]bool{view.ID: true}
+	pending := []*CWE{view}
+	for len(pending) > 0 {
+		node := pending[0]
+		pending = pending[1:]
+
+		childIDs, err := f.client.GetChildren(node.ID, normalizedViewID)
+		if err != nil {
+			if node == view {
+				return fmt.Errorf("获取视图%s的子节点失败: %w", normalizedViewID, err)
+			}
+			f.log().Warn("获取子节点列表失败，已跳过其子树", "id", node.ID, "error", err)
+			if err := f.recordNodeFailure(failures, node.ID, "", fmt.Errorf("获取子节点列表失败: %w", err)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, childID := range childIDs {
+			childID = normalizeEntryID(childID)
+			child, err := registry.GetByID(childID)
+			if err != nil {
+				fetched, isNew, err := f.fetchTreeNode(registry, node, childID, normalizedViewID)
+				if err != nil {
+					if errors.Is(err, ErrSkipNode) {
+						continue
+					}
+					if err := f.recordNodeFailure(failures, childID, node.ID, err); err != nil {
+						return err
+					}
+					continue
+				}
+				if isNew {
+					registry.Register(fetched)
+				}
+				child = fetched
+			}
+
+			if err := registry.AddViewChild(normalizedViewID, node.ID, child.ID); err != nil {
+				return err
+			}
+			if !expanded[child.ID] {
+				expanded[child.ID] = true
+				pending = append(pending, child)
+			}
+		}
+	}
+
+	registry.SortChildren(nil)
+
+	if len(failures.Errors) > 0 {
+		return failures
+	}
+	f.log().Info("视图扩展完成", "view", normalizedViewID, "nodes", len(registry.Entries))
+	return nil
+}
@@ -4,7 +4,7 @@ package main
 
 import (
 	"fmt"
-	"strings"
+	"regexp"
 
 	"github.com/scagogogo/cwe" // 导入CWE库
 )
@@ -65,22 +65,17 @@ func main() {
 		fmt.Printf("  %d. %s: %s\n", i+1, result.ID, result.Name)
 	}
 
-	// 示例4: 自定义筛选函数
-	fmt.Println("\n4. 使用自定义函数筛选")
+	// 示例4: 使用Query筛选
+	fmt.Println("\n4. 使用Query筛选")
 	// 找出所有叶子节点
-	leaves := filterCWEs(registry.Entries, func(c *cwe.CWE) bool {
-		return c.IsLeaf()
-	})
+	leaves := registry.Query().Leaf().All()
 	fmt.Printf("找到叶子节点: %d个\n", len(leaves))
 	for i, leaf := range leaves {
 		fmt.Printf("  %d. %s: %s\n", i+1, leaf.ID, leaf.Name)
 	}
 
-	// 找出所有严重性为"高"的CWE
-	highSeverity := filterCWEs(registry.Entries, func(c *cwe.CWE) bool {
-		return strings.Contains(strings.ToLower(c.Severity), "高") ||
-			strings.Contains(strings.ToLower(c.Severity), "high")
-	})
+	// 找出所有严重性至少为"高"的CWE，"高"、"High"、"HIGH"等写法都能识别
+	highSeverity := registry.Query().SeverityAtLeast("High").All()
 	fmt.Printf("找到高严重性CWE: %d个\n", len(highSeverity))
 	for i, cwe := range highSeverity {
 		fmt.Printf("  %d. %s: %s (严重性: %s)\n", i+1, cwe.ID, cwe.Name, cwe.Severity)
@@ -89,23 +84,11 @@ func main() {
 	// 示例5: 复合条件筛选
 	fmt.Println("\n5. 复合条件筛选")
 	// 找出所有输入验证类别下的且有缓解措施的叶子节点
-	inputValidationWithMitigations := filterCWEs(registry.Entries, func(c *cwe.CWE) bool {
-		// 检查是否是叶子节点
-		isLeaf := c.IsLeaf()
-		// 检查是否有缓解措施
-		hasMitigations := len(c.Mitigations) > 0
-		// 检查是否在输入验证类别下
-		inInputValidation := false
-		current := c
-		for current != nil && current.Parent != nil {
-			if current.Parent.ID == "CWE-20" {
-				inInputValidation = true
-				break
-			}
-			current = current.Parent
-		}
-		return isLeaf && hasMitigations && inInputValidation
-	})
+	inputValidationWithMitigations := registry.Query().
+		UnderAncestor("CWE-20").
+		HasMitigations().
+		Leaf().
+		All()
 	fmt.Printf("找到输入验证类别下有缓解措施的叶子节点: %d个\n", len(inputValidationWithMitigations))
 	for i, cwe := range inputValidationWithMitigations {
 		fmt.Printf("  %d. %s: %s\n", i+1, cwe.ID, cwe.Name)
@@ -120,18 +103,14 @@ func main() {
 		}
 	}
 
-	fmt.Println("\n==== 示例完成 ====")
-}
+	// 名称匹配正则表达式，也可以用Where添加任意自定义条件
+	injections := registry.Query().
+		NameMatches(regexp.MustCompile(`(?i)injection`)).
+		Where(func(c *cwe.CWE) bool { return c.Description != "" }).
+		IDs()
+	fmt.Printf("名称包含injection且有描述的CWE: %v\n", injections)
 
-// filterCWEs 根据提供的过滤函数筛选CWE
-func filterCWEs(entries map[string]*cwe.CWE, filter func(*cwe.CWE) bool) []*cwe.CWE {
-	results := make([]*cwe.CWE, 0)
-	for _, entry := range entries {
-		if filter(entry) {
-			results = append(results, entry)
-		}
-	}
-	return results
+	fmt.Println("\n==== 示例完成 ====")
 }
 
 // buildTestRegistry 构建一个测试用的CWE注册表
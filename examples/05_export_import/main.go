@@ -4,12 +4,10 @@ package main
 
 import (
 	"encoding/json"
-	"encoding/xml"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"time"
 
 	"github.com/scagogogo/cwe" // 导入CWE库
 )
@@ -106,17 +104,11 @@ func main() {
 
 	// 示例6: 导出特定视图的子树
 	fmt.Println("\n6. 导出特定视图的子树")
-	inputValidation, err := registry.GetByID("CWE-20")
+	// Subtree将条目及其所有后代深拷贝到新的注册表，Root和Parent指针都指向副本
+	subRegistry, err := registry.Subtree("CWE-20")
 	if err != nil {
-		fmt.Printf("获取输入验证条目失败: %v\n", err)
+		fmt.Printf("获取输入验证子树失败: %v\n", err)
 	} else {
-		// 创建一个新的注册表只包含这个子树
-		subRegistry := cwe.NewRegistry()
-		subRegistry.Root = inputValidation
-
-		// 添加所有子节点到注册表
-		addCWEAndChildrenToRegistry(inputValidation, subRegistry)
-
 		// 导出这个子树
 		subTreePath := filepath.Join(tmpDir, "input_validation_subtree.json")
 		err = exportToJSON(subRegistry, subTreePath)
@@ -145,131 +137,39 @@ func main() {
 }
 
 // 导出注册表到JSON文件
+// 使用库提供的带版本信息的导出格式，子节点以ID引用保存
 func exportToJSON(registry *cwe.Registry, filePath string) error {
-	// 创建一个导出结构
-	exportData := struct {
-		Version   string              `json:"version"`
-		Timestamp string              `json:"timestamp"`
-		RootID    string              `json:"rootId"`
-		Entries   map[string]*cwe.CWE `json:"entries"`
-	}{
-		Version:   "1.0",
-		Timestamp: time.Now().Format(time.RFC3339),
-		RootID:    registry.Root.ID,
-		Entries:   registry.Entries,
-	}
-
-	// 编码为JSON
-	data, err := json.MarshalIndent(exportData, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	// 写入文件
-	return ioutil.WriteFile(filePath, data, 0644)
+	return registry.ExportToJSONFile(filePath)
 }
 
 // 导出注册表到XML文件
 func exportToXML(registry *cwe.Registry, filePath string) error {
-	// 创建一个适合XML导出的结构
-	type CWEXML struct {
-		ID          string   `xml:"id,attr"`
-		Name        string   `xml:"name,attr"`
-		Description string   `xml:"description,omitempty"`
-		URL         string   `xml:"url,omitempty"`
-		Severity    string   `xml:"severity,omitempty"`
-		Mitigations []string `xml:"mitigations>mitigation,omitempty"`
-		Children    []string `xml:"children>child,omitempty"`
-	}
-
-	exportData := struct {
-		XMLName   xml.Name `xml:"cwe-registry"`
-		Version   string   `xml:"version,attr"`
-		Timestamp string   `xml:"timestamp,attr"`
-		RootID    string   `xml:"rootId,attr"`
-		Entries   []CWEXML `xml:"entries>cwe"`
-	}{
-		Version:   "1.0",
-		Timestamp: time.Now().Format(time.RFC3339),
-		RootID:    registry.Root.ID,
-		Entries:   make([]CWEXML, 0, len(registry.Entries)),
-	}
-
-	// 转换所有条目为XML格式
-	for _, entry := range registry.Entries {
-		childIDs := make([]string, 0, len(entry.Children))
-		for _, child := range entry.Children {
-			childIDs = append(childIDs, child.ID)
-		}
-
-		xmlEntry := CWEXML{
-			ID:          entry.ID,
-			Name:        entry.Name,
-			Description: entry.Description,
-			URL:         entry.URL,
-			Severity:    entry.Severity,
-			Mitigations: entry.Mitigations,
-			Children:    childIDs,
-		}
-		exportData.Entries = append(exportData.Entries, xmlEntry)
-	}
-
-	// 编码为XML
-	data, err := xml.MarshalIndent(exportData, "", "  ")
+	data, err := registry.ExportToXML()
 	if err != nil {
 		return err
 	}
 
-	// 添加XML头
-	xmlHeader := []byte(xml.Header)
-	data = append(xmlHeader, data...)
-
 	// 写入文件
 	return ioutil.WriteFile(filePath, data, 0644)
 }
 
 // 从JSON文件导入注册表
+// 父子关系和根节点由ImportFromJSONFile自动重建
 func importFromJSON(filePath string) (*cwe.Registry, error) {
-	// 读取文件
-	data, err := ioutil.ReadFile(filePath)
-	if err != nil {
-		return nil, err
-	}
-
-	// 解码JSON
-	importData := struct {
-		Version   string              `json:"version"`
-		Timestamp string              `json:"timestamp"`
-		RootID    string              `json:"rootId"`
-		Entries   map[string]*cwe.CWE `json:"entries"`
-	}{}
-
-	err = json.Unmarshal(data, &importData)
-	if err != nil {
+	registry := cwe.NewRegistry()
+	if err := registry.ImportFromJSONFile(filePath); err != nil {
 		return nil, err
 	}
 
-	// 创建注册表
-	registry := cwe.NewRegistry()
-	registry.Entries = importData.Entries
-
-	// 设置根节点
-	registry.Root = registry.Entries[importData.RootID]
 	if registry.Root == nil {
-		return nil, fmt.Errorf("根节点ID %s 不存在于导入的数据中", importData.RootID)
-	}
-
-	// 重建父子关系
-	for _, entry := range registry.Entries {
-		for _, child := range entry.Children {
-			child.Parent = entry
-		}
+		return nil, fmt.Errorf("导入的数据中没有根节点")
 	}
 
 	return registry, nil
 }
 
 // 从XML文件导入注册表
+// 缺失的子节点引用会以占位节点的形式加载，并打印出来
 func importFromXML(filePath string) (*cwe.Registry, error) {
 	// 读取文件
 	data, err := ioutil.ReadFile(filePath)
@@ -277,64 +177,18 @@ func importFromXML(filePath string) (*cwe.Registry, error) {
 		return nil, err
 	}
 
-	// 定义与导出时相同的XML结构
-	type CWEXML struct {
-		ID          string   `xml:"id,attr"`
-		Name        string   `xml:"name,attr"`
-		Description string   `xml:"description,omitempty"`
-		URL         string   `xml:"url,omitempty"`
-		Severity    string   `xml:"severity,omitempty"`
-		Mitigations []string `xml:"mitigations>mitigation,omitempty"`
-		Children    []string `xml:"children>child,omitempty"`
-	}
-
-	importData := struct {
-		XMLName   xml.Name `xml:"cwe-registry"`
-		Version   string   `xml:"version,attr"`
-		Timestamp string   `xml:"timestamp,attr"`
-		RootID    string   `xml:"rootId,attr"`
-		Entries   []CWEXML `xml:"entries>cwe"`
-	}{}
-
-	// 解码XML
-	err = xml.Unmarshal(data, &importData)
+	registry := cwe.NewRegistry()
+	report, err := registry.ImportFromXML(data, cwe.XMLImportOptions{CreatePlaceholders: true})
 	if err != nil {
 		return nil, err
 	}
 
-	// 创建注册表
-	registry := cwe.NewRegistry()
-
-	// 首先创建所有CWE对象
-	for _, xmlEntry := range importData.Entries {
-		entry := cwe.NewCWE(xmlEntry.ID, xmlEntry.Name)
-		entry.Description = xmlEntry.Description
-		entry.URL = xmlEntry.URL
-		entry.Severity = xmlEntry.Severity
-		entry.Mitigations = xmlEntry.Mitigations
-
-		registry.Register(entry)
+	for _, ref := range report.Unresolved {
+		fmt.Printf("警告: %s 引用了缺失的子节点 %s\n", ref.ParentID, ref.ChildID)
 	}
 
-	// 设置根节点
-	registry.Root = registry.Entries[importData.RootID]
 	if registry.Root == nil {
-		return nil, fmt.Errorf("根节点ID %s 不存在于导入的数据中", importData.RootID)
-	}
-
-	// 然后建立父子关系
-	for _, xmlEntry := range importData.Entries {
-		parent := registry.Entries[xmlEntry.ID]
-		if parent == nil {
-			continue
-		}
-
-		for _, childID := range xmlEntry.Children {
-			child := registry.Entries[childID]
-			if child != nil {
-				parent.AddChild(child)
-			}
-		}
+		return nil, fmt.Errorf("导入的数据中没有根节点")
 	}
 
 	return registry, nil
@@ -352,15 +206,6 @@ func exportSingleCWE(cweEntry *cwe.CWE, filePath string) error {
 	return ioutil.WriteFile(filePath, data, 0644)
 }
 
-// 将CWE及其所有子节点添加到注册表
-func addCWEAndChildrenToRegistry(cweEntry *cwe.CWE, registry *cwe.Registry) {
-	registry.Register(cweEntry)
-
-	for _, child := range cweEntry.Children {
-		addCWEAndChildrenToRegistry(child, registry)
-	}
-}
-
 // 构建测试用的CWE注册表
 func buildTestRegistry() *cwe.Registry {
 	// 创建注册表
@@ -1,3 +1,7 @@
 module rate_limited_client_example
 
 go 1.24.2
+
+require github.com/scagogogo/cwe v0.0.0
+
+replace github.com/scagogogo/cwe => ../../
@@ -0,0 +1,132 @@
+package cwe
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrBodyTooLarge 表示响应体超过了SetMaxBodySize设置的大小限制
+var ErrBodyTooLarge = errors.New("响应体超过大小限制")
+
+// BodyTooLargeError 是响应体超过大小限制时读取响应体返回的错误，可以通过errors.Is(err, ErrBodyTooLarge)判断
+type BodyTooLargeError struct {
+	// URL 请求的URL
+	URL string
+
+	// Limit 大小限制，单位为字节
+	Limit int64
+
+	// ContentLength 服务器通过Content-Length声明的大小，未声明时为-1
+	ContentLength int64
+}
+
+func (e *BodyTooLargeError) Error() string {
+	if e.ContentLength >= 0 {
+		return fmt.Sprintf("%s的响应体为%d字节，超过%d字节的限制", e.URL, e.ContentLength, e.Limit)
+	}
+	return fmt.Sprintf("%s的响应体超过%d字节的限制", e.URL, e.Limit)
+}
+
+func (e *BodyTooLargeError) Unwrap() error {
+	return ErrBodyTooLarge
+}
+
+// WithMaxBodySize 设置响应体的最大字节数，见SetMaxBodySize
+func WithMaxBodySize(maxBytes int64) ClientOption {
+	return func(c *HTTPClient) {
+		c.SetMaxBodySize(maxBytes)
+	}
+}
+
+// SetMaxBodySize 设置响应体的最大字节数
+//
+// 方法功能:
+// 防止配置错误或行为异常的镜像返回意外巨大的响应时耗尽内存。
+// 限制作用于解压后的响应体，因此同样能防御压缩炸弹。
+// 服务器声明的Content-Length超过限制时第一次读取就返回错误，不会读取任何数据；
+// 未声明时在读取的数据超过限制时返回错误。错误类型为*BodyTooLargeError，
+// APIClient的方法会将其包装后返回，可以通过errors.Is(err, ErrBodyTooLarge)判断。
+// 超限不会触发重试，因为重新请求通常得到同样大小的响应。默认不限制。
+//
+// 参数:
+// - maxBytes: int64 - 最大字节数，<=0表示不限制
+//
+// 使用示例:
+// ```go
+// client := cwe.NewAPIClient()
+// client.GetHTTPClient().SetMaxBodySize(32 << 20) // 32 MiB
+//
+// _, err := client.GetView("1000")
+//
+//	if errors.Is(err, cwe.ErrBodyTooLarge) {
+//	    log.Printf("镜像返回的响应过大: %v", err)
+//	}
+//
+// ```
+func (c *HTTPClient) SetMaxBodySize(maxBytes int64) {
+	if maxBytes < 0 {
+		maxBytes = 0
+	}
+	c.maxBodySize = maxBytes
+}
+
+// GetMaxBodySize 返回响应体的最大字节数，0表示不限制
+func (c *HTTPClient) GetMaxBodySize() int64 {
+	return c.maxBodySize
+}
+
+// bodyLimitRoundTrip 包装next，为响应体设置大小限制
+// 位于解压之外、拦截器之内，使限制作用于解压后的响应体，拦截器读取响应体时同样受到限制
+func (c *HTTPClient) bodyLimitRoundTrip(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		resp, err := next(req)
+		limit := c.maxBodySize
+		if err != nil || limit <= 0 || resp.Body == nil || resp.Body == http.NoBody {
+			return resp, err
+		}
+
+		resp.Body = &limitedBody{
+			body:      resp.Body,
+			remaining: limit,
+			err: &BodyTooLargeError{
+				URL:           req.URL.String(),
+				Limit:         limit,
+				ContentLength: resp.ContentLength,
+			},
+		}
+		return resp, nil
+	}
+}
+
+// limitedBody 读取超过remaining字节的数据时返回err
+type limitedBody struct {
+	body      io.ReadCloser
+	remaining int64
+	err       *BodyTooLargeError
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.err.ContentLength > b.err.Limit {
+		return 0, b.err
+	}
+	if b.remaining < 0 {
+		return 0, b.err
+	}
+
+	// 多读一个字节，用于区分响应体恰好等于限制和超过限制
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.body.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n + int(b.remaining), b.err
+	}
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	return b.body.Close()
+}
@@ -0,0 +1,103 @@
+package cwe
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestHTTPClientMaxBodySize(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		body := strings.Repeat("x", 100)
+		switch r.URL.Path {
+		case "/chunked":
+			// 不设置Content-Length，分两次写出
+			w.Write([]byte(body[:50]))
+			w.(http.Flusher).Flush()
+			w.Write([]byte(body[50:]))
+		case "/gzip":
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			gz.Write([]byte(strings.Repeat("x", 10000)))
+			gz.Close()
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write(buf.Bytes())
+		default:
+			w.Write([]byte(body))
+		}
+	}))
+	defer server.Close()
+
+	client := NewHttpClient(WithMaxBodySize(100), WithRateLimit(1000))
+	if client.GetMaxBodySize() != 100 {
+		t.Fatalf("GetMaxBodySize = %d", client.GetMaxBodySize())
+	}
+
+	// 恰好等于限制时可以完整读取
+	resp, err := client.GetSimple(server.URL + "/exact")
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || len(data) != 100 {
+		t.Errorf("应完整读取100字节，实际为%d, %v", len(data), err)
+	}
+
+	client.SetMaxBodySize(60)
+	for _, path := range []string{"/exact", "/chunked", "/gzip"} {
+		resp, err := client.GetSimple(server.URL + path)
+		if err != nil {
+			t.Fatalf("%s: 请求失败: %v", path, err)
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		var tooLarge *BodyTooLargeError
+		if !errors.Is(err, ErrBodyTooLarge) || !errors.As(err, &tooLarge) || tooLarge.Limit != 60 {
+			t.Errorf("%s: 应返回BodyTooLargeError，实际: %v", path, err)
+		}
+		if len(data) > 60 {
+			t.Errorf("%s: 读取的数据不应超过限制，实际为%d字节", path, len(data))
+		}
+	}
+
+	// 声明了Content-Length时不读取任何数据
+	resp, _ = client.GetSimple(server.URL + "/exact")
+	data, err = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if len(data) != 0 || !strings.Contains(err.Error(), "100字节") {
+		t.Errorf("Content-Length超过限制时不应读取数据: %d, %v", len(data), err)
+	}
+
+	// 超限不会触发重试
+	if got := atomic.LoadInt32(&requests); got != 5 {
+		t.Errorf("服务器应收到5个请求，实际为%d", got)
+	}
+}
+
+func TestAPIClientMaxBodySize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version": "4.15", "release_date": "` + strings.Repeat("x", 1000) + `"}`))
+	}))
+	defer server.Close()
+
+	client := NewAPIClientWithOptions(server.URL, DefaultTimeout, NewHTTPRateLimiter(0))
+	client.GetHTTPClient().SetMaxBodySize(512)
+	if _, err := client.GetVersion(); !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("响应过大时应返回ErrBodyTooLarge，实际: %v", err)
+	}
+
+	client.GetHTTPClient().SetMaxBodySize(0)
+	if _, err := client.GetVersion(); err != nil {
+		t.Errorf("不限制时应成功，实际: %v", err)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -59,6 +61,14 @@ type HTTPClient struct {
 	// 可以通过SetRateLimiter方法替换或调整
 	rateLimiter *HTTPRateLimiter
 
+	// rateLimiters 按目标主机选择限流器的注册表，为nil时所有请求使用rateLimiter
+	// 可以通过SetRateLimiterRegistry方法设置
+	rateLimiters *RateLimiterRegistry
+
+	// adaptiveMaxInterval 自适应限速的请求间隔上限，<=0时不启用自适应限速
+	// 可以通过SetAdaptiveRateLimit方法设置
+	adaptiveMaxInterval time.Duration
+
 	// maxRetries 表示请求失败时的最大重试次数
 	// 可以通过SetMaxRetries方法调整
 	// 实际请求次数 = maxRetries + 1（初始请求）
@@ -67,6 +77,50 @@ type HTTPClient struct {
 	// retryDelay 表示两次重试之间的等待时间
 	// 可以通过SetRetryDelay方法调整
 	retryDelay time.Duration
+
+	// maxRateLimitWait 根据服务器限流头部等待的最长时间
+	// 可以通过SetMaxRateLimitWait方法调整
+	maxRateLimitWait time.Duration
+
+	// rateLimit 最近一次从响应头中观察到的限流状态，由rateLimitMutex保护
+	rateLimit      RateLimitStatus
+	rateLimitMutex sync.Mutex
+
+	// interceptors 通过Use添加的拦截器，由interceptorMutex保护
+	interceptors     []Interceptor
+	interceptorMutex sync.RWMutex
+
+	// logger 日志记录器，为nil时不记录日志
+	// 可以通过SetLogger方法设置
+	logger Logger
+
+	// metrics 指标记录器，为nil时不记录指标
+	// 可以通过SetMetrics方法设置
+	metrics MetricsRecorder
+
+	// tracerProvider 为每个请求创建span的TracerProvider，为nil时不追踪
+	// 可以通过SetTracerProvider方法设置
+	tracerProvider TracerProvider
+
+	// disableCompression 为true时不请求gzip压缩的响应
+	// 可以通过SetCompression方法调整
+	disableCompression bool
+
+	// compressRequests 为true时使用gzip压缩请求体
+	// 可以通过SetRequestCompression方法调整
+	compressRequests bool
+
+	// maxBodySize 响应体(解压后)的最大字节数，<=0时不限制
+	// 可以通过SetMaxBodySize方法调整
+	maxBodySize int64
+
+	// attemptTimeout 单次尝试(包括读取响应体)的超时时间，<=0时不限制
+	// 可以通过SetAttemptTimeout方法调整
+	attemptTimeout time.Duration
+
+	// overallTimeout 包括所有重试、限速等待和重试间隔在内的总超时时间，<=0时不限制
+	// 可以通过SetOverallTimeout方法调整
+	overallTimeout time.Duration
 }
 
 // ClientOption 是HTTP客户端的配置选项函数类型
@@ -95,7 +149,7 @@ func WithRateLimit(requestsPerSecond float64) ClientOption {
 	return func(c *HTTPClient) {
 		if requestsPerSecond > 0 {
 			interval := time.Duration(1000.0 / requestsPerSecond * float64(time.Millisecond))
-			c.rateLimiter = NewHTTPRateLimiter(interval)
+			c.SetRateLimiter(NewHTTPRateLimiter(interval))
 		}
 	}
 }
@@ -108,6 +162,9 @@ func NewHttpClient(options ...ClientOption) *HTTPClient {
 		rateLimiter: DefaultRateLimiter, // 默认使用全局限制器
 		maxRetries:  3,                  // 默认最多重试3次
 		retryDelay:  1 * time.Second,    // 默认重试间隔1秒
+
+		maxRateLimitWait: DefaultMaxRateLimitWait,
+		rateLimit:        RateLimitStatus{Limit: -1, Remaining: -1},
 	}
 
 	// 应用所有选项
@@ -127,6 +184,15 @@ func (c *HTTPClient) Get(ctx context.Context, url string) (*http.Response, error
 	return c.Do(req)
 }
 
+// Head 发送HTTP HEAD请求，支持上下文控制
+func (c *HTTPClient) Head(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "HEAD", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
 // Post 发送HTTP POST请求，支持上下文控制
 func (c *HTTPClient) Post(ctx context.Context, url string, body []byte) (*http.Response, error) {
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
@@ -140,41 +206,13 @@ func (c *HTTPClient) Post(ctx context.Context, url string, body []byte) (*http.R
 // GetSimple 发送HTTP GET请求，不支持上下文
 // 向指定URL发送HTTP GET请求，支持自动重试和速率限制。
 func (c *HTTPClient) GetSimple(url string) (*http.Response, error) {
-	var resp *http.Response
-	var err error
-
-	for attempt := 0; attempt <= c.maxRetries; attempt++ {
-		// 第一次请求和重试都需要等待速率限制
-		c.rateLimiter.WaitForRequest()
-
-		// 重试时增加延迟
-		if attempt > 0 {
-			time.Sleep(c.retryDelay)
+	return c.doWithRetry(context.Background(), url, func(ctx context.Context) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, err
 		}
-
-		resp, err = c.client.Get(url)
-
-		// 请求成功且状态码小于500，视为成功
-		if err == nil && resp.StatusCode < 500 {
-			return resp, nil
-		}
-
-		// 请求失败，关闭响应体防止资源泄露
-		if resp != nil && resp.Body != nil {
-			resp.Body.Close()
-		}
-
-		// 达到最大重试次数，返回最后一次错误
-		if attempt == c.maxRetries {
-			if err != nil {
-				return nil, fmt.Errorf("达到最大重试次数(%d)后请求仍然失败: %w", c.maxRetries, err)
-			}
-			return resp, fmt.Errorf("达到最大重试次数(%d)后请求仍然返回错误状态码: %d", c.maxRetries, resp.StatusCode)
-		}
-	}
-
-	// 理论上不会执行到这里
-	return nil, fmt.Errorf("未知错误")
+		return c.send(req)
+	})
 }
 
 // PostSimple 发送简单的HTTP POST请求，不支持上下文
@@ -182,8 +220,8 @@ func (c *HTTPClient) GetSimple(url string) (*http.Response, error) {
 func (c *HTTPClient) PostSimple(url string, contentType string, body io.Reader) (*http.Response, error) {
 	// 如果body为nil，可以直接使用不需要特殊处理
 	if body == nil {
-		return c.doWithRetry(func() (*http.Response, error) {
-			return c.client.Post(url, contentType, nil)
+		return c.doWithRetry(context.Background(), url, func(ctx context.Context) (*http.Response, error) {
+			return c.post(ctx, url, contentType, nil)
 		})
 	}
 
@@ -193,10 +231,10 @@ func (c *HTTPClient) PostSimple(url string, contentType string, body io.Reader)
 		return nil, fmt.Errorf("读取请求体失败: %w", err)
 	}
 
-	return c.doWithRetry(func() (*http.Response, error) {
+	return c.doWithRetry(context.Background(), url, func(ctx context.Context) (*http.Response, error) {
 		// 每次请求都创建新的bytes.Reader
 		bodyReader := bytes.NewReader(bodyBytes)
-		return c.client.Post(url, contentType, bodyReader)
+		return c.post(ctx, url, contentType, bodyReader)
 	})
 }
 
@@ -272,8 +310,8 @@ func (c *HTTPClient) PostSimple(url string, contentType string, body io.Reader)
 // - Post(): 发送POST请求
 // - Do(): 执行自定义请求
 func (c *HTTPClient) PostForm(url string, data url.Values) (*http.Response, error) {
-	return c.doWithRetry(func() (*http.Response, error) {
-		return c.client.PostForm(url, data)
+	return c.doWithRetry(context.Background(), url, func(ctx context.Context) (*http.Response, error) {
+		return c.post(ctx, url, "application/x-www-form-urlencoded", strings.NewReader(data.Encode()))
 	})
 }
 
@@ -313,6 +351,8 @@ func (c *HTTPClient) PostForm(url string, data url.Values) (*http.Response, erro
 // 3. 重试逻辑：
 //   - 5xx错误触发重试
 //   - 网络错误触发重试
+//   - 429错误按Retry-After等待后重试
+//   - 服务器公布的配额(X-RateLimit-Remaining)用完时，在下次请求前等待到重置时间
 //   - 达到最大重试次数后返回错误
 //
 // 使用示例：
@@ -354,10 +394,10 @@ func (c *HTTPClient) PostForm(url string, data url.Values) (*http.Response, erro
 func (c *HTTPClient) Do(req *http.Request) (*http.Response, error) {
 	// 如果请求没有body，可以安全地重试
 	if req.Body == nil {
-		return c.doWithRetry(func() (*http.Response, error) {
+		return c.doWithRetry(req.Context(), req.URL.String(), func(ctx context.Context) (*http.Response, error) {
 			// 克隆请求以确保安全
-			reqCopy := cloneRequest(req)
-			return c.client.Do(reqCopy)
+			reqCopy := cloneRequest(req).WithContext(ctx)
+			return c.send(reqCopy)
 		})
 	}
 
@@ -370,10 +410,10 @@ func (c *HTTPClient) Do(req *http.Request) (*http.Response, error) {
 	req.Body.Close()
 
 	// 使用闭包保存原始请求和body数据
-	return c.doWithRetry(func() (*http.Response, error) {
-		reqCopy := cloneRequest(req)
+	return c.doWithRetry(req.Context(), req.URL.String(), func(ctx context.Context) (*http.Response, error) {
+		reqCopy := cloneRequest(req).WithContext(ctx)
 		reqCopy.Body = io.NopCloser(bytes.NewReader(bodyBytes))
-		return c.client.Do(reqCopy)
+		return c.send(reqCopy)
 	})
 }
 
@@ -384,13 +424,15 @@ func (c *HTTPClient) Do(req *http.Request) (*http.Response, error) {
 // 处理请求重试、速率限制和请求体重用等核心功能。
 //
 // 参数：
-// - req *http.Request: HTTP请求对象
-//   - 原始请求对象，会被克隆用于重试
-//   - 请求体会被保存以支持重试
+// - ctx context.Context: 请求的上下文
+//   - 取消后不再重试，等待限速和重试间隔时也会立即返回
+//
+// - rawURL string: 请求的目标URL
+//   - 用于按主机选择速率限制器
 //
-// - reqBody []byte: 请求体数据
-//   - 用于重试时重建请求体
-//   - 如果原请求没有请求体，则为nil
+// - requestFunc func(ctx context.Context) (*http.Response, error): 发送一次请求的函数
+//   - 每次重试都会调用，需要自行重建请求体
+//   - ctx是本次尝试的上下文，设置了单次尝试超时时带有截止时间，请求必须使用它
 //
 // 返回值：
 // - *http.Response: HTTP响应对象
@@ -441,23 +483,71 @@ func (c *HTTPClient) Do(req *http.Request) (*http.Response, error) {
 // - 这是一个内部方法，不应直接调用
 // - 修改此方法时需考虑对所有HTTP方法的影响
 // - 需要维护请求体的完整性
-func (c *HTTPClient) doWithRetry(requestFunc func() (*http.Response, error)) (*http.Response, error) {
+func (c *HTTPClient) doWithRetry(ctx context.Context, rawURL string, requestFunc func(ctx context.Context) (*http.Response, error)) (*http.Response, error) {
 	var resp *http.Response
 	var err error
+	limiter := c.limiterFor(rawURL)
+
+	// 总超时覆盖所有尝试以及期间的限速等待和重试间隔
+	ctx, cancel := c.overallContext(ctx)
 
 	for attempt := 0; attempt <= c.maxRetries; attempt++ {
 		// 第一次请求和重试都需要等待速率限制
-		c.rateLimiter.WaitForRequest()
+		waitStart := time.Now()
+		if err := limiter.WaitContext(ctx); err != nil {
+			cancel()
+			return nil, c.deadlineError(err)
+		}
+
+		// 服务器公布的配额已用完时，等待到时间窗口重置
+		if err := c.waitForServerLimit(ctx); err != nil {
+			cancel()
+			return nil, c.deadlineError(err)
+		}
+		if c.metrics != nil {
+			c.metrics.ObserveRateLimitWait(time.Since(waitStart))
+		}
+
+		if attempt > 0 && c.metrics != nil {
+			c.metrics.ObserveRetry()
+		}
 
 		// 重试时增加延迟
 		if attempt > 0 {
-			time.Sleep(c.retryDelay)
+			if err := sleepContext(ctx, c.retryDelay); err != nil {
+				cancel()
+				return nil, c.deadlineError(err)
+			}
 		}
 
-		resp, err = requestFunc()
+		attemptCtx, attemptCancel := c.attemptContext(ctx)
+		resp, err = requestFunc(attemptCtx)
+		if err == nil {
+			c.observeRateLimit(resp)
+			c.adaptRateLimit(limiter, resp)
+		}
+
+		// 429表示触发了服务器限流，按Retry-After等待后重试；重试次数用完时将响应交给调用方处理
+		if err == nil && resp.StatusCode == http.StatusTooManyRequests && attempt < c.maxRetries {
+			resp.Body.Close()
+			attemptCancel()
+			wait := c.tooManyRequestsWait()
+			c.logRetry(attempt+1, resp, nil, wait)
+			if err := sleepContext(ctx, wait); err != nil {
+				cancel()
+				return nil, c.deadlineError(err)
+			}
+			continue
+		}
 
 		// 请求成功且状态码小于500，视为成功
 		if err == nil && resp.StatusCode < 500 {
+			if c.attemptTimeout > 0 || c.overallTimeout > 0 {
+				resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: func() {
+					attemptCancel()
+					cancel()
+				}}
+			}
 			return resp, nil
 		}
 
@@ -465,17 +555,30 @@ func (c *HTTPClient) doWithRetry(requestFunc func() (*http.Response, error)) (*h
 		if resp != nil && resp.Body != nil {
 			resp.Body.Close()
 		}
+		attemptCancel()
+
+		// 总超时已到或调用方取消了请求，不再重试
+		if ctx.Err() != nil {
+			cancel()
+			if err == nil {
+				err = fmt.Errorf("服务器返回错误状态码: %d", resp.StatusCode)
+			}
+			return nil, c.deadlineError(fmt.Errorf("%w: %v", ctx.Err(), err))
+		}
 
 		// 达到最大重试次数，返回最后一次错误
 		if attempt == c.maxRetries {
+			cancel()
 			if err != nil {
 				return nil, fmt.Errorf("达到最大重试次数(%d)后请求仍然失败: %w", c.maxRetries, err)
 			}
 			return resp, fmt.Errorf("达到最大重试次数(%d)后请求仍然返回错误状态码: %d", c.maxRetries, resp.StatusCode)
 		}
+		c.logRetry(attempt+1, resp, err, c.retryDelay)
 	}
 
 	// 理论上不会执行到这里
+	cancel()
 	return nil, fmt.Errorf("未知错误")
 }
 
@@ -558,13 +661,16 @@ func cloneRequest(req *http.Request) *http.Request {
 // 设置和获取方法
 
 // SetRateLimiter 设置速率限制器
+// 设置后所有请求共用该限制器，通过SetRateLimiterRegistry启用的按主机限速会被取消
 func (c *HTTPClient) SetRateLimiter(limiter *HTTPRateLimiter) {
 	if limiter != nil {
 		c.rateLimiter = limiter
+		c.rateLimiters = nil
 	}
 }
 
 // GetRateLimiter 获取速率限制器
+// 通过SetRateLimiterRegistry启用按主机限速时，请求不使用此限制器，应通过注册表查看各主机的限制器
 func (c *HTTPClient) GetRateLimiter() *HTTPRateLimiter {
 	return c.rateLimiter
 }
@@ -0,0 +1,133 @@
+package cwe
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// WithCompression 设置是否请求gzip压缩的响应，默认启用
+func WithCompression(enabled bool) ClientOption {
+	return func(c *HTTPClient) {
+		c.SetCompression(enabled)
+	}
+}
+
+// WithRequestCompression 启用请求体的gzip压缩，见SetRequestCompression
+func WithRequestCompression() ClientOption {
+	return func(c *HTTPClient) {
+		c.SetRequestCompression(true)
+	}
+}
+
+// SetCompression 设置是否请求gzip压缩的响应
+//
+// 方法功能:
+// 启用时(默认)每个请求都带有Accept-Encoding: gzip，服务器返回Content-Encoding: gzip的响应时
+// 自动解压，调用方和拦截器读取到的始终是解压后的响应体，响应头中的Content-Encoding和Content-Length会被移除，
+// resp.Uncompressed为true。视图、后代等较大的JSON响应压缩后通常只有原来的十分之一左右。
+// 关闭时请求带有Accept-Encoding: identity，服务器返回未压缩的响应，便于调试时查看原始传输内容。
+// 请求已经设置了Accept-Encoding时不做任何处理，由调用方自行解码。
+//
+// 参数:
+// - enabled: bool - 是否启用
+func (c *HTTPClient) SetCompression(enabled bool) {
+	c.disableCompression = !enabled
+}
+
+// SetRequestCompression 设置是否使用gzip压缩请求体
+//
+// 方法功能:
+// 启用后有请求体且未设置Content-Encoding的请求(如POST)会先压缩请求体，并设置Content-Encoding: gzip。
+// 默认关闭，因为并非所有服务器都支持压缩的请求体，只应在确认服务器支持时启用。
+//
+// 参数:
+// - enabled: bool - 是否启用
+func (c *HTTPClient) SetRequestCompression(enabled bool) {
+	c.compressRequests = enabled
+}
+
+// compressionRoundTrip 包装next，按配置压缩请求体并解压响应
+// 位于拦截器链的最内层，使拦截器看到的是未压缩的请求体和解压后的响应
+func (c *HTTPClient) compressionRoundTrip(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		if c.compressRequests && req.Body != nil && req.Body != http.NoBody && req.Header.Get("Content-Encoding") == "" {
+			compressed, err := gzipRequestBody(req)
+			if err != nil {
+				return nil, err
+			}
+			req = compressed
+		}
+
+		decode := false
+		if req.Header.Get("Accept-Encoding") == "" {
+			req = req.Clone(req.Context())
+			if c.disableCompression {
+				req.Header.Set("Accept-Encoding", "identity")
+			} else {
+				req.Header.Set("Accept-Encoding", "gzip")
+				decode = true
+			}
+		}
+
+		resp, err := next(req)
+		if err != nil || !decode {
+			return resp, err
+		}
+		if req.Method != http.MethodHead && strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+			resp.Body = &gzipResponseBody{body: resp.Body}
+			resp.Header.Del("Content-Encoding")
+			resp.Header.Del("Content-Length")
+			resp.ContentLength = -1
+			resp.Uncompressed = true
+		}
+		return resp, nil
+	}
+}
+
+// gzipRequestBody 返回请求体经过gzip压缩的请求副本
+func gzipRequestBody(req *http.Request) (*http.Request, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err := io.Copy(writer, req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	compressed := req.Clone(req.Context())
+	body := buf.Bytes()
+	compressed.Body = io.NopCloser(bytes.NewReader(body))
+	compressed.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	compressed.ContentLength = int64(len(body))
+	compressed.Header.Set("Content-Encoding", "gzip")
+	return compressed, nil
+}
+
+// gzipResponseBody 在第一次读取时才创建gzip.Reader，使空响应体或格式错误在读取时以错误返回
+type gzipResponseBody struct {
+	body   io.ReadCloser
+	reader *gzip.Reader
+	err    error
+}
+
+func (b *gzipResponseBody) Read(p []byte) (int, error) {
+	if b.reader == nil && b.err == nil {
+		b.reader, b.err = gzip.NewReader(b.body)
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	return b.reader.Read(p)
+}
+
+func (b *gzipResponseBody) Close() error {
+	return b.body.Close()
+}
@@ -0,0 +1,139 @@
+package cwe
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// gzipBytes 返回data经过gzip压缩后的内容
+func gzipBytes(t *testing.T, data string) []byte {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	writer.Write([]byte(data))
+	if err := writer.Close(); err != nil {
+		t.Fatalf("压缩失败: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestHTTPClientDecompressesResponse(t *testing.T) {
+	body := `{"weaknesses": [{"id": "79"}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			w.Header().Set("X-Accept-Encoding", r.Header.Get("Accept-Encoding"))
+			w.Write([]byte(body))
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(gzipBytes(t, body))
+	}))
+	defer server.Close()
+
+	client := NewHttpClient()
+	client.SetRateLimiter(NewHTTPRateLimiter(0))
+
+	var seen string
+	client.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			resp, err := next(req)
+			if err == nil {
+				seen = resp.Header.Get("Content-Encoding")
+			}
+			return resp, err
+		}
+	})
+
+	resp, err := client.GetSimple(server.URL)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("读取响应失败: %v", err)
+	}
+	if string(data) != body {
+		t.Errorf("响应应被解压，实际为%q", data)
+	}
+	if !resp.Uncompressed || seen != "" {
+		t.Errorf("解压后应移除Content-Encoding，拦截器看到%q", seen)
+	}
+
+	// 关闭后不再请求压缩
+	client.SetCompression(false)
+	resp, err = client.GetSimple(server.URL)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.Uncompressed || resp.Header.Get("X-Accept-Encoding") != "identity" {
+		t.Errorf("关闭压缩后应请求未压缩的响应，实际为%q", resp.Header.Get("X-Accept-Encoding"))
+	}
+}
+
+func TestHTTPClientCorruptGzipResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write([]byte("not gzip"))
+	}))
+	defer server.Close()
+
+	client := NewHttpClient()
+	client.SetRateLimiter(NewHTTPRateLimiter(0))
+
+	resp, err := client.GetSimple(server.URL)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+	if _, err := io.ReadAll(resp.Body); err == nil {
+		t.Error("无效的gzip响应读取时应返回错误")
+	}
+}
+
+func TestHTTPClientRequestCompression(t *testing.T) {
+	var received string
+	var encoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding = r.Header.Get("Content-Encoding")
+		reader := io.Reader(r.Body)
+		if encoding == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			reader = gz
+		}
+		data, _ := io.ReadAll(reader)
+		received = string(data)
+	}))
+	defer server.Close()
+
+	client := NewHttpClient(WithRequestCompression())
+	client.SetRateLimiter(NewHTTPRateLimiter(0))
+
+	resp, err := client.PostSimple(server.URL, "application/json", bytes.NewReader([]byte(`{"ids": ["79"]}`)))
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+	if encoding != "gzip" || received != `{"ids": ["79"]}` {
+		t.Errorf("请求体应被压缩，实际编码%q内容%q", encoding, received)
+	}
+
+	client.SetRequestCompression(false)
+	resp, err = client.PostSimple(server.URL, "application/json", bytes.NewReader([]byte("plain")))
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+	if encoding != "" || received != "plain" {
+		t.Errorf("关闭后请求体不应压缩，实际编码%q内容%q", encoding, received)
+	}
+}
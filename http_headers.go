@@ -0,0 +1,111 @@
+package cwe
+
+import (
+	"net/http"
+	"strings"
+)
+
+// WithUserAgent 设置每个请求的User-Agent
+// 请求已经设置了User-Agent时保留请求中的值；ua为空时不做任何修改
+func WithUserAgent(ua string) ClientOption {
+	return WithDefaultHeaders(map[string]string{"User-Agent": ua})
+}
+
+// WithDefaultHeaders 为每个请求添加默认请求头
+//
+// 方法功能:
+// 请求中没有的请求头会被添加，已经设置的请求头保留请求中的值，因此单个请求仍可以覆盖默认值。
+// 请求头作用于包括重试在内的每一次实际发送的请求，值为空的请求头会被忽略。
+// 多次使用时按顺序叠加。
+//
+// 参数:
+// - headers: map[string]string - 请求头名称到值的映射
+//
+// 返回值:
+// - ClientOption: HTTP客户端配置选项
+//
+// 使用示例:
+// ```go
+//
+//	client := cwe.NewAPIClient(cwe.WithDefaultHeaders(map[string]string{
+//	    "X-Tenant": "security-team",
+//	}))
+//
+// ```
+func WithDefaultHeaders(headers map[string]string) ClientOption {
+	defaults := make(http.Header, len(headers))
+	for name, value := range headers {
+		if value = strings.TrimSpace(value); value != "" {
+			defaults.Set(name, value)
+		}
+	}
+	return func(c *HTTPClient) {
+		if len(defaults) == 0 {
+			return
+		}
+		c.Use(func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				for name, values := range defaults {
+					if req.Header.Get(name) == "" {
+						req.Header[name] = values
+					}
+				}
+				return next(req)
+			}
+		})
+	}
+}
+
+// WithBearerToken 为每个请求添加"Authorization: Bearer <token>"认证头
+// 适用于在CWE API前部署了需要令牌的API网关的场景；请求已经设置了Authorization时保留请求中的值
+func WithBearerToken(token string) ClientOption {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return func(c *HTTPClient) {}
+	}
+	return WithDefaultHeaders(map[string]string{"Authorization": "Bearer " + token})
+}
+
+// WithAPIKey 为每个请求添加API密钥请求头
+//
+// 方法功能:
+// 以header指定的请求头发送密钥，header为空时使用"X-API-Key"。
+// 请求已经设置了该请求头时保留请求中的值。
+//
+// 参数:
+// - header: string - 请求头名称，如"X-API-Key"、"apikey"
+// - key: string - API密钥，为空时不做任何修改
+//
+// 返回值:
+// - ClientOption: HTTP客户端配置选项
+//
+// 使用示例:
+// ```go
+// client := cwe.NewAPIClient(cwe.WithAPIKey("X-Gateway-Key", os.Getenv("CWE_GATEWAY_KEY")))
+// ```
+func WithAPIKey(header, key string) ClientOption {
+	if header = strings.TrimSpace(header); header == "" {
+		header = "X-API-Key"
+	}
+	return WithDefaultHeaders(map[string]string{header: key})
+}
+
+// ApplyOptions 将HTTP客户端配置选项应用到API客户端内部使用的HTTP客户端
+//
+// 方法功能:
+// 用于NewAPIClientWithOptions等无法直接传入ClientOption的构造函数，
+// 效果与在NewAPIClient中传入相同的选项一致。
+//
+// 参数:
+// - options: ...ClientOption - 配置选项
+//
+// 使用示例:
+// ```go
+// client := cwe.NewAPIClientWithOptions("https://cwe-gateway.example.com/api/v1", 0)
+// client.ApplyOptions(cwe.WithUserAgent("scanner/1.2"), cwe.WithBearerToken(token))
+// ```
+func (c *APIClient) ApplyOptions(options ...ClientOption) {
+	for _, option := range options {
+		option(c.client)
+	}
+}
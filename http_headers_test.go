@@ -0,0 +1,74 @@
+package cwe
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIClientHeaderOptions(t *testing.T) {
+	var seen http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"version": "4.16"}`))
+	}))
+	defer server.Close()
+
+	client := NewAPIClient(
+		WithUserAgent("scanner/1.2"),
+		WithDefaultHeaders(map[string]string{"X-Tenant": "security", "X-Empty": " "}),
+		WithBearerToken("secret"),
+		WithAPIKey("", "key-1"),
+	)
+	client.baseURL = server.URL
+	client.SetRateLimiter(NewHTTPRateLimiter(0))
+
+	if _, err := client.GetVersion(); err != nil {
+		t.Fatalf("GetVersion失败: %v", err)
+	}
+
+	expected := map[string]string{
+		"User-Agent":    "scanner/1.2",
+		"X-Tenant":      "security",
+		"Authorization": "Bearer secret",
+		"X-Api-Key":     "key-1",
+		"X-Empty":       "",
+	}
+	for name, value := range expected {
+		if got := seen.Get(name); got != value {
+			t.Errorf("请求头%s应为%q，实际为%q", name, value, got)
+		}
+	}
+}
+
+func TestDefaultHeadersKeepRequestValues(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	client := NewAPIClientWithOptions(server.URL, 0, NewHTTPRateLimiter(0))
+	client.ApplyOptions(WithBearerToken("default"), WithBearerToken(""))
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	req.Header.Set("Authorization", "Bearer override")
+	resp, err := client.GetHTTPClient().Do(req)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+	if auth != "Bearer override" {
+		t.Errorf("请求中已有的认证头应保留，实际为%q", auth)
+	}
+
+	resp, err = client.GetHTTPClient().GetSimple(server.URL)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+	if auth != "Bearer default" {
+		t.Errorf("应添加默认认证头，实际为%q", auth)
+	}
+}
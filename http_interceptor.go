@@ -0,0 +1,104 @@
+package cwe
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// RoundTripFunc 发送单个HTTP请求并返回响应，签名与http.RoundTripper.RoundTrip相同
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip 实现http.RoundTripper接口
+func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Interceptor 是HTTPClient的请求拦截器，包装next并返回新的RoundTripFunc
+// 拦截器可以在调用next前修改请求(如添加认证头、请求ID)，或在调用后检查响应(如记录日志、指标)
+type Interceptor func(next RoundTripFunc) RoundTripFunc
+
+// Use 为HTTP客户端添加拦截器
+//
+// 方法功能:
+// 拦截器按添加顺序从外到内执行：先添加的拦截器最先看到请求、最后看到响应。
+// 拦截器作用于每一次实际发送的请求，重试的请求会再次经过所有拦截器；
+// 速率限制的等待发生在拦截器之前，不计入拦截器看到的耗时。
+// 与替换底层http.Client不同，之后调用SetClient不会移除已添加的拦截器。
+// Use是并发安全的，但通常应在发送请求前完成配置。
+//
+// 参数:
+// - interceptors: ...Interceptor - 要添加的拦截器，nil会被忽略
+//
+// 使用示例:
+// ```go
+// client := cwe.NewAPIClient()
+//
+// // 为每个请求添加请求ID
+//
+//	client.GetHTTPClient().Use(func(next cwe.RoundTripFunc) cwe.RoundTripFunc {
+//	    return func(req *http.Request) (*http.Response, error) {
+//	        req.Header.Set("X-Request-ID", uuid.NewString())
+//	        return next(req)
+//	    }
+//	})
+//
+// // 记录每个请求的耗时
+//
+//	client.GetHTTPClient().Use(func(next cwe.RoundTripFunc) cwe.RoundTripFunc {
+//	    return func(req *http.Request) (*http.Response, error) {
+//	        start := time.Now()
+//	        resp, err := next(req)
+//	        log.Printf("%s %s %v", req.Method, req.URL, time.Since(start))
+//	        return resp, err
+//	    }
+//	})
+//
+// ```
+func (c *HTTPClient) Use(interceptors ...Interceptor) {
+	c.interceptorMutex.Lock()
+	defer c.interceptorMutex.Unlock()
+
+	for _, interceptor := range interceptors {
+		if interceptor != nil {
+			c.interceptors = append(c.interceptors, interceptor)
+		}
+	}
+}
+
+// send 通过拦截器链和底层客户端发送请求
+func (c *HTTPClient) send(req *http.Request) (*http.Response, error) {
+	c.interceptorMutex.RLock()
+	interceptors := c.interceptors
+	c.interceptorMutex.RUnlock()
+
+	next := c.bodyLimitRoundTrip(c.compressionRoundTrip(c.client.Do))
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		next = interceptors[i](next)
+	}
+	next = c.tracingRoundTrip(next)
+
+	start := time.Now()
+	resp, err := next(req)
+	duration := time.Since(start)
+	c.logRequest(req, resp, err, duration)
+	if c.metrics != nil {
+		status := 0
+		if err == nil {
+			status = resp.StatusCode
+		}
+		c.metrics.ObserveRequest(metricsEndpoint(req.URL.Path), status, duration)
+	}
+	return resp, err
+}
+
+// post 构建POST请求并通过send发送，行为与http.Client.Post相同
+func (c *HTTPClient) post(ctx context.Context, url, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return c.send(req)
+}
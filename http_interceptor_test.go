@@ -0,0 +1,126 @@
+package cwe
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHTTPClientUseOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Seen-Auth", r.Header.Get("Authorization"))
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := NewHttpClient()
+	client.SetRateLimiter(NewHTTPRateLimiter(0))
+
+	var mutex sync.Mutex
+	var calls []string
+	trace := func(name string) Interceptor {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				mutex.Lock()
+				calls = append(calls, name+">")
+				mutex.Unlock()
+				resp, err := next(req)
+				mutex.Lock()
+				calls = append(calls, "<"+name)
+				mutex.Unlock()
+				return resp, err
+			}
+		}
+	}
+	auth := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("Authorization", "Bearer token")
+			return next(req)
+		}
+	}
+	client.Use(trace("a"), nil, trace("b"))
+	client.Use(auth)
+
+	// 替换底层客户端不应移除拦截器
+	client.SetClient(&http.Client{Timeout: time.Second})
+
+	resp, err := client.GetSimple(server.URL)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+
+	if got := resp.Header.Get("X-Seen-Auth"); got != "Bearer token" {
+		t.Errorf("拦截器添加的请求头应发送到服务器，实际为%q", got)
+	}
+	expected := []string{"a>", "b>", "<b", "<a"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("拦截器执行顺序应为%v，实际为%v", expected, calls)
+	}
+}
+
+func TestHTTPClientUseRetriesAndMethods(t *testing.T) {
+	var mutex sync.Mutex
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requests++
+		first := requests == 1
+		mutex.Unlock()
+		if first {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("X-Content-Type", r.Header.Get("Content-Type"))
+	}))
+	defer server.Close()
+
+	client := NewHttpClient(WithRetryInterval(time.Millisecond))
+	client.SetRateLimiter(NewHTTPRateLimiter(0))
+
+	var seen []string
+	client.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			mutex.Lock()
+			seen = append(seen, req.Method)
+			mutex.Unlock()
+			return next(req)
+		}
+	})
+
+	// 第一次返回500后重试，两次请求都应经过拦截器
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+
+	resp, err = client.PostSimple(server.URL, "text/plain", strings.NewReader("body"))
+	if err != nil {
+		t.Fatalf("POST请求失败: %v", err)
+	}
+	resp.Body.Close()
+	if got := resp.Header.Get("X-Content-Type"); got != "text/plain" {
+		t.Errorf("PostSimple应设置Content-Type，实际为%q", got)
+	}
+
+	resp, err = client.PostForm(server.URL, url.Values{"a": {"1"}})
+	if err != nil {
+		t.Fatalf("表单请求失败: %v", err)
+	}
+	resp.Body.Close()
+	if got := resp.Header.Get("X-Content-Type"); got != "application/x-www-form-urlencoded" {
+		t.Errorf("PostForm应设置表单Content-Type，实际为%q", got)
+	}
+
+	expected := []string{"GET", "GET", "POST", "POST"}
+	if !reflect.DeepEqual(seen, expected) {
+		t.Errorf("拦截器应看到%v，实际为%v", expected, seen)
+	}
+}
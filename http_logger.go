@@ -0,0 +1,81 @@
+package cwe
+
+import (
+	"net/http"
+	"time"
+)
+
+// Logger 是客户端和数据获取器使用的结构化日志接口
+//
+// keysAndValues为交替出现的键和值，如("url", u, "status", 200)，与log/slog和logr的约定相同。
+// Go 1.21及以上版本的*slog.Logger可以直接作为Logger使用；其他日志库只需编写一个简单的适配器。
+// 实现必须是并发安全的。
+type Logger interface {
+	// Debug 记录每个HTTP请求、每个抓取的节点等详细信息
+	Debug(msg string, keysAndValues ...interface{})
+
+	// Info 记录树构建开始和完成等里程碑
+	Info(msg string, keysAndValues ...interface{})
+
+	// Warn 记录重试、被跳过的节点等不影响最终结果的问题
+	Warn(msg string, keysAndValues ...interface{})
+
+	// Error 记录导致操作失败的错误
+	Error(msg string, keysAndValues ...interface{})
+}
+
+// SetLogger 设置HTTP客户端的日志记录器
+//
+// 方法功能:
+// 设置后，每个实际发送的请求都会以Debug级别记录方法、URL、状态码和耗时，
+// 请求出错、重试和服务器限流等待以Warn级别记录。传入nil关闭日志。
+// 应在发送请求前调用。
+//
+// 参数:
+// - logger: Logger - 日志记录器，为nil时不记录日志
+//
+// 使用示例:
+// ```go
+// client := cwe.NewAPIClient()
+// client.SetLogger(slog.Default())
+// ```
+func (c *HTTPClient) SetLogger(logger Logger) {
+	c.logger = logger
+}
+
+// GetLogger 获取HTTP客户端的日志记录器，未设置时返回nil
+func (c *HTTPClient) GetLogger() Logger {
+	return c.logger
+}
+
+// logRequest 记录一次实际发送的请求
+func (c *HTTPClient) logRequest(req *http.Request, resp *http.Response, err error, duration time.Duration) {
+	if c.logger == nil {
+		return
+	}
+	if err != nil {
+		c.logger.Warn("HTTP请求出错", "method", req.Method, "url", req.URL.String(), "duration", duration, "error", err)
+		return
+	}
+	c.logger.Debug("HTTP请求", "method", req.Method, "url", req.URL.String(), "status", resp.StatusCode, "duration", duration)
+}
+
+// logRetry 记录一次将要进行的重试，attempt为即将进行的重试序号(从1开始)
+func (c *HTTPClient) logRetry(attempt int, resp *http.Response, err error, wait time.Duration) {
+	if c.logger == nil {
+		return
+	}
+	keysAndValues := []interface{}{"attempt", attempt, "maxRetries", c.maxRetries, "wait", wait}
+	if err != nil {
+		keysAndValues = append(keysAndValues, "error", err)
+	} else {
+		keysAndValues = append(keysAndValues, "status", resp.StatusCode)
+	}
+	c.logger.Warn("重试HTTP请求", keysAndValues...)
+}
+
+// SetLogger 设置API客户端的日志记录器，等同于GetClient().SetLogger(logger)
+// 通过NewCachedAPIClient复制的客户端与原客户端共享日志记录器
+func (c *APIClient) SetLogger(logger Logger) {
+	c.client.SetLogger(logger)
+}
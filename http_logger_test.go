@@ -0,0 +1,133 @@
+package cwe
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testLogEntry 是testLogger记录的一条日志
+type testLogEntry struct {
+	level  string
+	msg    string
+	fields map[string]interface{}
+}
+
+// testLogger 是记录所有日志的Logger，用于断言
+type testLogger struct {
+	mutex   sync.Mutex
+	entries []testLogEntry
+}
+
+func (l *testLogger) add(level, msg string, keysAndValues []interface{}) {
+	fields := make(map[string]interface{})
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		fields[fmt.Sprint(keysAndValues[i])] = keysAndValues[i+1]
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.entries = append(l.entries, testLogEntry{level: level, msg: msg, fields: fields})
+}
+
+func (l *testLogger) Debug(msg string, kv ...interface{}) { l.add("debug", msg, kv) }
+func (l *testLogger) Info(msg string, kv ...interface{})  { l.add("info", msg, kv) }
+func (l *testLogger) Warn(msg string, kv ...interface{})  { l.add("warn", msg, kv) }
+func (l *testLogger) Error(msg string, kv ...interface{}) { l.add("error", msg, kv) }
+
+// find 返回指定消息的所有日志
+func (l *testLogger) find(msg string) []testLogEntry {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	var result []testLogEntry
+	for _, entry := range l.entries {
+		if entry.msg == msg {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+func TestHTTPClientLogger(t *testing.T) {
+	var mutex sync.Mutex
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requests++
+		first := requests == 1
+		mutex.Unlock()
+		if first {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := NewHttpClient(WithRetryInterval(time.Millisecond))
+	client.SetRateLimiter(NewHTTPRateLimiter(0))
+
+	logger := &testLogger{}
+	client.SetLogger(logger)
+	if client.GetLogger() != logger {
+		t.Fatal("GetLogger应返回设置的日志记录器")
+	}
+
+	resp, err := client.GetSimple(server.URL + "/cwe/version")
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+
+	requestLogs := logger.find("HTTP请求")
+	if len(requestLogs) != 2 {
+		t.Fatalf("应记录2次请求，实际为%d", len(requestLogs))
+	}
+	if requestLogs[0].fields["status"] != http.StatusBadGateway || requestLogs[1].fields["status"] != http.StatusOK {
+		t.Errorf("状态码记录不正确: %v", requestLogs)
+	}
+	if requestLogs[1].fields["url"] != server.URL+"/cwe/version" || requestLogs[1].level != "debug" {
+		t.Errorf("请求日志不正确: %+v", requestLogs[1])
+	}
+	if _, ok := requestLogs[1].fields["duration"].(time.Duration); !ok {
+		t.Error("请求日志应包含耗时")
+	}
+
+	retryLogs := logger.find("重试HTTP请求")
+	if len(retryLogs) != 1 || retryLogs[0].fields["attempt"] != 1 || retryLogs[0].level != "warn" {
+		t.Errorf("应以Warn级别记录1次重试，实际为%+v", retryLogs)
+	}
+
+	// 关闭日志后不再记录
+	client.SetLogger(nil)
+	resp, err = client.GetSimple(server.URL)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+	if len(logger.find("HTTP请求")) != 2 {
+		t.Error("SetLogger(nil)后不应再记录日志")
+	}
+}
+
+func TestHTTPClientLoggerError(t *testing.T) {
+	client := NewHttpClient(WithMaxRetries(1), WithRetryInterval(time.Millisecond))
+	client.SetRateLimiter(NewHTTPRateLimiter(0))
+	logger := &testLogger{}
+	client.SetLogger(logger)
+
+	// 连接已关闭的服务器
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	if _, err := client.GetSimple(url); err == nil {
+		t.Fatal("请求已关闭的服务器应失败")
+	}
+	errorLogs := logger.find("HTTP请求出错")
+	if len(errorLogs) != 2 || errorLogs[0].fields["error"] == nil {
+		t.Errorf("应记录2次请求错误，实际为%+v", errorLogs)
+	}
+}
@@ -0,0 +1,68 @@
+package cwe
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MetricsRecorder 接收HTTP客户端和API客户端的运行指标
+//
+// 实现可以将指标转发到Prometheus、StatsD等监控系统，MetricsCollector是一个不依赖第三方库、
+// 直接输出Prometheus文本格式的内置实现。实现必须是并发安全的，并且应尽快返回。
+type MetricsRecorder interface {
+	// ObserveRequest 记录一次实际发送的请求，重试的每次请求分别记录
+	// endpoint是将ID替换为"{id}"后的URL路径，如"/api/v1/cwe/weakness/{id}"；
+	// 请求出错没有响应时status为0
+	ObserveRequest(endpoint string, status int, duration time.Duration)
+
+	// ObserveRetry 记录一次重试
+	ObserveRetry()
+
+	// ObserveRateLimitWait 记录发送请求前在速率限制器和服务器限流上等待的时间
+	ObserveRateLimitWait(wait time.Duration)
+
+	// ObserveCache 记录一次响应缓存查询，kind为条目类型，如"weakness"、"children"
+	ObserveCache(kind string, hit bool)
+}
+
+// SetMetrics 设置HTTP客户端的指标记录器，传入nil关闭指标记录
+// 应在发送请求前调用
+//
+// 使用示例:
+// ```go
+// metrics := cwe.NewMetricsCollector()
+//
+// client := cwe.NewAPIClient()
+// client.SetMetrics(metrics)
+//
+// http.Handle("/metrics", metrics)
+// ```
+func (c *HTTPClient) SetMetrics(metrics MetricsRecorder) {
+	c.metrics = metrics
+}
+
+// GetMetrics 获取HTTP客户端的指标记录器，未设置时返回nil
+func (c *HTTPClient) GetMetrics() MetricsRecorder {
+	return c.metrics
+}
+
+// SetMetrics 设置API客户端的指标记录器，等同于GetClient().SetMetrics(metrics)
+// 启用响应缓存时还会记录缓存命中情况
+func (c *APIClient) SetMetrics(metrics MetricsRecorder) {
+	c.client.SetMetrics(metrics)
+}
+
+// metricsIDSegment 匹配URL路径中表示ID的段，如"79"、"CWE-79"、"79,89"
+var metricsIDSegment = regexp.MustCompile(`(?i)^(cwe-)?\d+(,(cwe-)?\d+)*$`)
+
+// metricsEndpoint 将URL路径中的ID替换为"{id}"，避免每个条目产生一个新的标签值
+func metricsEndpoint(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if metricsIDSegment.MatchString(segment) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
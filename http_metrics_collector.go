@@ -0,0 +1,230 @@
+package cwe
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMetricsBuckets 是MetricsCollector中耗时直方图默认的桶上界(秒)
+// 覆盖从几毫秒的缓存代理响应到默认速率限制下约10秒的等待
+var DefaultMetricsBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// MetricsCollector 是在内存中汇总指标并以Prometheus文本格式输出的MetricsRecorder
+//
+// 输出的指标:
+// - cwe_api_requests_total{endpoint,status}: 请求总数，请求出错时status为"error"
+// - cwe_api_request_duration_seconds{endpoint}: 请求耗时直方图
+// - cwe_api_retries_total: 重试总数
+// - cwe_api_rate_limit_wait_seconds: 速率限制等待时间直方图
+// - cwe_api_cache_requests_total{kind,result}: 缓存查询总数，result为"hit"或"miss"
+//
+// MetricsCollector实现了http.Handler，可以直接挂载到服务的/metrics路径，由Prometheus抓取；
+// 已经使用Prometheus客户端库的服务也可以自行实现MetricsRecorder，将指标注册到已有的注册表中。
+// MetricsCollector是并发安全的，多个客户端可以共享同一个实例。
+type MetricsCollector struct {
+	mutex sync.Mutex
+
+	buckets   []float64
+	requests  map[[2]string]float64
+	durations map[string]*metricsHistogram
+	retries   float64
+	waits     *metricsHistogram
+	cache     map[[2]string]float64
+}
+
+// metricsHistogram 是累积直方图，counts[i]为不超过buckets[i]的观测数
+type metricsHistogram struct {
+	counts []float64
+	sum    float64
+	count  float64
+}
+
+// NewMetricsCollector 创建使用DefaultMetricsBuckets的指标收集器
+func NewMetricsCollector() *MetricsCollector {
+	return NewMetricsCollectorWithBuckets(DefaultMetricsBuckets)
+}
+
+// NewMetricsCollectorWithBuckets 创建使用自定义直方图桶上界(秒)的指标收集器
+// 上界会被排序并去重，为空时使用DefaultMetricsBuckets
+func NewMetricsCollectorWithBuckets(buckets []float64) *MetricsCollector {
+	sorted := make([]float64, 0, len(buckets))
+	for _, bucket := range buckets {
+		if !math.IsNaN(bucket) && !math.IsInf(bucket, 0) {
+			sorted = append(sorted, bucket)
+		}
+	}
+	if len(sorted) == 0 {
+		sorted = append(sorted, DefaultMetricsBuckets...)
+	}
+	sort.Float64s(sorted)
+	unique := sorted[:1]
+	for _, bucket := range sorted[1:] {
+		if bucket != unique[len(unique)-1] {
+			unique = append(unique, bucket)
+		}
+	}
+
+	return &MetricsCollector{
+		buckets:   unique,
+		requests:  make(map[[2]string]float64),
+		durations: make(map[string]*metricsHistogram),
+		waits:     &metricsHistogram{counts: make([]float64, len(unique))},
+		cache:     make(map[[2]string]float64),
+	}
+}
+
+// ObserveRequest 实现MetricsRecorder
+func (m *MetricsCollector) ObserveRequest(endpoint string, status int, duration time.Duration) {
+	statusLabel := "error"
+	if status != 0 {
+		statusLabel = strconv.Itoa(status)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.requests[[2]string{endpoint, statusLabel}]++
+	histogram, exists := m.durations[endpoint]
+	if !exists {
+		histogram = &metricsHistogram{counts: make([]float64, len(m.buckets))}
+		m.durations[endpoint] = histogram
+	}
+	histogram.observe(m.buckets, duration.Seconds())
+}
+
+// ObserveRetry 实现MetricsRecorder
+func (m *MetricsCollector) ObserveRetry() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.retries++
+}
+
+// ObserveRateLimitWait 实现MetricsRecorder
+func (m *MetricsCollector) ObserveRateLimitWait(wait time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.waits.observe(m.buckets, wait.Seconds())
+}
+
+// ObserveCache 实现MetricsRecorder
+func (m *MetricsCollector) ObserveCache(kind string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.cache[[2]string{kind, result}]++
+}
+
+// observe 记录一个观测值
+func (h *metricsHistogram) observe(buckets []float64, value float64) {
+	for i, bucket := range buckets {
+		if value <= bucket {
+			h.counts[i]++
+		}
+	}
+	h.sum += value
+	h.count++
+}
+
+// WritePrometheus 以Prometheus文本格式(0.0.4)写出所有指标，标签值按字典序排列
+//
+// 返回值:
+// - error: 写入失败时返回错误
+func (m *MetricsCollector) WritePrometheus(w io.Writer) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	out := bufio.NewWriter(w)
+
+	writeHeader(out, "cwe_api_requests_total", "counter", "CWE API请求总数")
+	for _, key := range sortedLabelKeys(m.requests) {
+		fmt.Fprintf(out, "cwe_api_requests_total{endpoint=%s,status=%s} %s\n",
+			quoteLabel(key[0]), quoteLabel(key[1]), formatMetric(m.requests[key]))
+	}
+
+	writeHeader(out, "cwe_api_request_duration_seconds", "histogram", "CWE API请求耗时")
+	endpoints := make([]string, 0, len(m.durations))
+	for endpoint := range m.durations {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+	for _, endpoint := range endpoints {
+		m.writeHistogram(out, "cwe_api_request_duration_seconds", "endpoint="+quoteLabel(endpoint)+",", m.durations[endpoint])
+	}
+
+	writeHeader(out, "cwe_api_retries_total", "counter", "CWE API请求重试总数")
+	fmt.Fprintf(out, "cwe_api_retries_total %s\n", formatMetric(m.retries))
+
+	writeHeader(out, "cwe_api_rate_limit_wait_seconds", "histogram", "发送请求前等待速率限制的时间")
+	m.writeHistogram(out, "cwe_api_rate_limit_wait_seconds", "", m.waits)
+
+	writeHeader(out, "cwe_api_cache_requests_total", "counter", "响应缓存查询总数")
+	for _, key := range sortedLabelKeys(m.cache) {
+		fmt.Fprintf(out, "cwe_api_cache_requests_total{kind=%s,result=%s} %s\n",
+			quoteLabel(key[0]), quoteLabel(key[1]), formatMetric(m.cache[key]))
+	}
+
+	return out.Flush()
+}
+
+// ServeHTTP 实现http.Handler，以Prometheus文本格式输出所有指标
+func (m *MetricsCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WritePrometheus(w)
+}
+
+// writeHistogram 写出直方图的桶、总和和计数，labels为额外的标签前缀，如`endpoint="/x",`
+func (m *MetricsCollector) writeHistogram(out io.Writer, name, labels string, h *metricsHistogram) {
+	for i, bucket := range m.buckets {
+		fmt.Fprintf(out, "%s_bucket{%sle=\"%s\"} %s\n", name, labels, formatMetric(bucket), formatMetric(h.counts[i]))
+	}
+	fmt.Fprintf(out, "%s_bucket{%sle=\"+Inf\"} %s\n", name, labels, formatMetric(h.count))
+
+	suffix := ""
+	if labels != "" {
+		suffix = "{" + strings.TrimSuffix(labels, ",") + "}"
+	}
+	fmt.Fprintf(out, "%s_sum%s %s\n", name, suffix, formatMetric(h.sum))
+	fmt.Fprintf(out, "%s_count%s %s\n", name, suffix, formatMetric(h.count))
+}
+
+// writeHeader 写出指标的HELP和TYPE行
+func writeHeader(out io.Writer, name, metricType, help string) {
+	fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+// sortedLabelKeys 返回按字典序排列的标签组合
+func sortedLabelKeys(values map[[2]string]float64) [][2]string {
+	keys := make([][2]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	return keys
+}
+
+// quoteLabel 按Prometheus文本格式转义并加引号
+func quoteLabel(value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+	return `"` + value + `"`
+}
+
+// formatMetric 格式化指标值
+func formatMetric(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,86 @@
+package cwe
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetricsCollectorWritePrometheus(t *testing.T) {
+	collector := NewMetricsCollectorWithBuckets([]float64{1, 0.1, 1})
+
+	collector.ObserveRequest("/cwe/weakness/{id}", 200, 50*time.Millisecond)
+	collector.ObserveRequest("/cwe/weakness/{id}", 200, 500*time.Millisecond)
+	collector.ObserveRequest("/cwe/weakness/{id}", 0, 2*time.Second)
+	collector.ObserveRequest("/cwe/\"quoted\"", 404, 0)
+	collector.ObserveRetry()
+	collector.ObserveRateLimitWait(0)
+	collector.ObserveCache("weakness", true)
+	collector.ObserveCache("weakness", false)
+	collector.ObserveCache("weakness", true)
+
+	var out strings.Builder
+	if err := collector.WritePrometheus(&out); err != nil {
+		t.Fatalf("写出指标失败: %v", err)
+	}
+	text := out.String()
+
+	for _, want := range []string{
+		"# TYPE cwe_api_requests_total counter\n",
+		`cwe_api_requests_total{endpoint="/cwe/\"quoted\"",status="404"} 1` + "\n",
+		`cwe_api_requests_total{endpoint="/cwe/weakness/{id}",status="200"} 2` + "\n",
+		`cwe_api_requests_total{endpoint="/cwe/weakness/{id}",status="error"} 1` + "\n",
+		"# TYPE cwe_api_request_duration_seconds histogram\n",
+		`cwe_api_request_duration_seconds_bucket{endpoint="/cwe/weakness/{id}",le="0.1"} 1` + "\n",
+		`cwe_api_request_duration_seconds_bucket{endpoint="/cwe/weakness/{id}",le="1"} 2` + "\n",
+		`cwe_api_request_duration_seconds_bucket{endpoint="/cwe/weakness/{id}",le="+Inf"} 3` + "\n",
+		`cwe_api_request_duration_seconds_sum{endpoint="/cwe/weakness/{id}"} 2.55` + "\n",
+		`cwe_api_request_duration_seconds_count{endpoint="/cwe/weakness/{id}"} 3` + "\n",
+		"cwe_api_retries_total 1\n",
+		`cwe_api_rate_limit_wait_seconds_bucket{le="0.1"} 1` + "\n",
+		"cwe_api_rate_limit_wait_seconds_count 1\n",
+		`cwe_api_cache_requests_total{kind="weakness",result="hit"} 2` + "\n",
+		`cwe_api_cache_requests_total{kind="weakness",result="miss"} 1` + "\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("输出中缺少%q\n%s", want, text)
+		}
+	}
+
+	// 重复的桶上界只输出一次
+	if strings.Count(text, `cwe_api_rate_limit_wait_seconds_bucket{le="1"}`) != 1 {
+		t.Error("重复的桶上界应被去重")
+	}
+
+	// 标签值按字典序排列，输出稳定
+	if strings.Index(text, `status="200"`) > strings.Index(text, `status="error"`) {
+		t.Error("标签组合应按字典序排列")
+	}
+}
+
+func TestMetricsCollectorServeHTTP(t *testing.T) {
+	collector := NewMetricsCollector()
+
+	// 通过API客户端产生指标
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version": "4.16"}`))
+	}))
+	defer server.Close()
+	client := NewAPIClientWithOptions(server.URL, DefaultTimeout, NewHTTPRateLimiter(0))
+	client.SetMetrics(collector)
+	if _, err := client.GetVersion(); err != nil {
+		t.Fatalf("获取版本失败: %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	collector.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := recorder.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type不正确: %s", ct)
+	}
+	if !strings.Contains(recorder.Body.String(), `cwe_api_requests_total{endpoint="/cwe/version",status="200"} 1`) {
+		t.Errorf("应包含版本请求的指标:\n%s", recorder.Body.String())
+	}
+}
@@ -0,0 +1,132 @@
+package cwe
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testMetrics 是记录所有指标的MetricsRecorder，用于断言
+type testMetrics struct {
+	mutex     sync.Mutex
+	requests  []string
+	retries   int
+	waits     int
+	cacheHits map[string]int
+	cacheMiss map[string]int
+}
+
+func newTestMetrics() *testMetrics {
+	return &testMetrics{cacheHits: make(map[string]int), cacheMiss: make(map[string]int)}
+}
+
+func (m *testMetrics) ObserveRequest(endpoint string, status int, duration time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.requests = append(m.requests, endpoint+" "+http.StatusText(status))
+}
+
+func (m *testMetrics) ObserveRetry() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.retries++
+}
+
+func (m *testMetrics) ObserveRateLimitWait(wait time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.waits++
+}
+
+func (m *testMetrics) ObserveCache(kind string, hit bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if hit {
+		m.cacheHits[kind]++
+	} else {
+		m.cacheMiss[kind]++
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	tests := map[string]string{
+		"/api/v1/cwe/weakness/79":        "/api/v1/cwe/weakness/{id}",
+		"/api/v1/cwe/weakness/CWE-79":    "/api/v1/cwe/weakness/{id}",
+		"/api/v1/cwe/79,89,cwe-20":       "/api/v1/cwe/{id}",
+		"/api/v1/cwe/CWE-1000/children":  "/api/v1/cwe/{id}/children",
+		"/api/v1/cwe/weakness/all":       "/api/v1/cwe/weakness/all",
+		"/api/v1/cwe/version":            "/api/v1/cwe/version",
+		"/rest/json/cves/2.0":            "/rest/json/cves/2.0",
+		"/api/v1/cwe/view/1000/children": "/api/v1/cwe/view/{id}/children",
+	}
+	for path, expected := range tests {
+		if got := metricsEndpoint(path); got != expected {
+			t.Errorf("metricsEndpoint(%q) = %q，期望%q", path, got, expected)
+		}
+	}
+}
+
+func TestHTTPClientMetrics(t *testing.T) {
+	var mutex sync.Mutex
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requests++
+		first := requests == 1
+		mutex.Unlock()
+		if first {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := NewHttpClient(WithRetryInterval(time.Millisecond))
+	client.SetRateLimiter(NewHTTPRateLimiter(0))
+	metrics := newTestMetrics()
+	client.SetMetrics(metrics)
+	if client.GetMetrics() != metrics {
+		t.Fatal("GetMetrics应返回设置的指标记录器")
+	}
+
+	resp, err := client.GetSimple(server.URL + "/cwe/weakness/79")
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+
+	expected := []string{"/cwe/weakness/{id} Service Unavailable", "/cwe/weakness/{id} OK"}
+	if len(metrics.requests) != 2 || metrics.requests[0] != expected[0] || metrics.requests[1] != expected[1] {
+		t.Errorf("请求指标应为%v，实际为%v", expected, metrics.requests)
+	}
+	if metrics.retries != 1 {
+		t.Errorf("应记录1次重试，实际为%d", metrics.retries)
+	}
+	if metrics.waits != 2 {
+		t.Errorf("每次请求都应记录速率限制等待，实际为%d", metrics.waits)
+	}
+}
+
+func TestAPIClientCacheMetrics(t *testing.T) {
+	var requests int32
+	server := setupCacheTestServer(&requests)
+	defer server.Close()
+
+	client := NewCachedAPIClient(newCacheTestClient(server.URL), NewMemoryCache(0))
+	client.SetCacheVersion("4.16")
+	metrics := newTestMetrics()
+	client.SetMetrics(metrics)
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetWeakness("79"); err != nil {
+			t.Fatalf("获取弱点失败: %v", err)
+		}
+	}
+
+	if metrics.cacheMiss[cacheKindWeakness] != 1 || metrics.cacheHits[cacheKindWeakness] != 2 {
+		t.Errorf("应记录1次未命中和2次命中，实际为%v/%v", metrics.cacheMiss, metrics.cacheHits)
+	}
+}
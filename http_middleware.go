@@ -0,0 +1,218 @@
+package cwe
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// 中间件默认读取的参数名
+const (
+	// DefaultCWEQueryParam 默认读取的查询参数名
+	DefaultCWEQueryParam = "cwe"
+
+	// DefaultCWEHeader 默认读取的请求头名
+	DefaultCWEHeader = "X-CWE-ID"
+)
+
+// ErrMissingCWE 表示请求中没有提供CWE ID
+var ErrMissingCWE = errors.New("请求中缺少CWE ID")
+
+// CWELookup 根据规范化的CWE ID查找条目
+// 条目不存在时应返回包装了ErrNotFound的错误，以便中间件返回404
+type CWELookup func(ctx context.Context, id string) (*CWE, error)
+
+// RegistryLookup 返回在注册表中查找条目的CWELookup
+func RegistryLookup(registry *Registry) CWELookup {
+	return func(ctx context.Context, id string) (*CWE, error) {
+		return registry.GetByID(id)
+	}
+}
+
+// FetcherLookup 返回通过API获取条目的CWELookup，依次尝试作为弱点和类别获取
+// API对两者都返回404时错误包装ErrNotFound，中间件返回404；网络错误等其他失败按500处理
+func FetcherLookup(fetcher *DataFetcher) CWELookup {
+	return func(ctx context.Context, id string) (*CWE, error) {
+		return fetcher.fetchWeaknessOrCategory(id)
+	}
+}
+
+// CWEMiddlewareOptions 控制CWE中间件的行为
+type CWEMiddlewareOptions struct {
+	// Lookup 查找条目的函数，必须设置
+	Lookup CWELookup
+
+	// QueryParam 读取CWE ID的查询参数名，为空时使用DefaultCWEQueryParam
+	QueryParam string
+
+	// Header 读取CWE ID的请求头名，为空时使用DefaultCWEHeader；查询参数优先
+	Header string
+
+	// Required 为true时，请求中没有CWE ID会返回400；为false时直接交给下一个处理器
+	Required bool
+
+	// OnError 解析或查找失败时调用，用于自定义错误响应
+	// 为nil时使用WriteCWEError：无效ID或缺少ID返回400，条目不存在返回404，其他错误返回500
+	OnError func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// cweContextKey 是请求上下文中保存CWE条目的键
+type cweContextKey struct{}
+
+// ContextWithCWE 返回保存了CWE条目的新上下文
+func ContextWithCWE(ctx context.Context, entry *CWE) context.Context {
+	return context.WithValue(ctx, cweContextKey{}, entry)
+}
+
+// CWEFromContext 从上下文中取出中间件解析的CWE条目
+// 请求中没有CWE ID(且Required为false)时返回nil和false
+func CWEFromContext(ctx context.Context) (*CWE, bool) {
+	entry, ok := ctx.Value(cweContextKey{}).(*CWE)
+	return entry, ok && entry != nil
+}
+
+// ResolveRequestCWE 从请求中读取并查找CWE条目
+//
+// 方法功能:
+// 中间件的核心逻辑，不依赖net/http的中间件形式。本包只提供net/http中间件，不依赖gin、echo等框架；
+// 这些框架都可以取得底层的*http.Request，直接调用此函数和CWEErrorStatus即可实现相同的行为。
+// 先读取查询参数，没有时读取请求头，然后用ParseCWEID规范化并调用opts.Lookup查找。
+//
+// 参数:
+// - r: *http.Request - 请求
+// - opts: CWEMiddlewareOptions - 选项，只使用Lookup、QueryParam和Header
+//
+// 返回值:
+// - *CWE: 找到的条目
+// - error: 请求中没有ID时返回ErrMissingCWE，ID无效时返回解析错误，查找失败时返回Lookup的错误
+//
+// 使用示例:
+// ```go
+// // 在自定义的处理器中使用，例如只对部分路由校验CWE ID
+//
+//	func handleFinding(w http.ResponseWriter, r *http.Request) {
+//	    entry, err := cwe.ResolveRequestCWE(r, cwe.CWEMiddlewareOptions{Lookup: lookup})
+//	    if err != nil {
+//	        http.Error(w, err.Error(), cwe.CWEErrorStatus(err))
+//	        return
+//	    }
+//	    fmt.Fprintf(w, "%s: %s", entry.ID, entry.Name)
+//	}
+//
+// ```
+func ResolveRequestCWE(r *http.Request, opts CWEMiddlewareOptions) (*CWE, error) {
+	param := opts.QueryParam
+	if param == "" {
+		param = DefaultCWEQueryParam
+	}
+	header := opts.Header
+	if header == "" {
+		header = DefaultCWEHeader
+	}
+
+	raw := strings.TrimSpace(r.URL.Query().Get(param))
+	if raw == "" {
+		raw = strings.TrimSpace(r.Header.Get(header))
+	}
+	if raw == "" {
+		return nil, ErrMissingCWE
+	}
+
+	id, err := ParseCWEID(raw)
+	if err != nil {
+		return nil, &invalidCWEError{err: err}
+	}
+
+	return opts.Lookup(r.Context(), id)
+}
+
+// CWEMiddleware 返回解析请求中的CWE ID并将条目放入请求上下文的net/http中间件
+//
+// 方法功能:
+// 接受CWE ID的微服务可以用它统一完成ID校验和条目查找，处理器通过CWEFromContext取得条目。
+// 其他Web框架可以基于ResolveRequestCWE编写适配器。
+//
+// 参数:
+// - opts: CWEMiddlewareOptions - 中间件选项，Lookup必须设置
+//
+// 返回值:
+// - func(http.Handler) http.Handler: 中间件
+//
+// 错误处理:
+// - opts.Lookup为nil时panic，这属于编程错误
+//
+// 使用示例:
+// ```go
+// registry, err := snapshot.ToRegistry() // 从快照加载的完整注册表
+// withCWE := cwe.CWEMiddleware(cwe.CWEMiddlewareOptions{
+//
+//	Lookup:   cwe.RegistryLookup(registry),
+//	Required: true,
+//
+// })
+//
+//	http.Handle("/findings", withCWE(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//	    entry, _ := cwe.CWEFromContext(r.Context())
+//	    fmt.Fprintf(w, "%s: %s", entry.ID, entry.Name)
+//	})))
+//
+// // GET /findings?cwe=79 或携带请求头 X-CWE-ID: CWE-79
+// ```
+func CWEMiddleware(opts CWEMiddlewareOptions) func(http.Handler) http.Handler {
+	if opts.Lookup == nil {
+		panic("cwe: CWEMiddlewareOptions.Lookup不能为nil")
+	}
+	onError := opts.OnError
+	if onError == nil {
+		onError = WriteCWEError
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			entry, err := ResolveRequestCWE(r, opts)
+			if errors.Is(err, ErrMissingCWE) && !opts.Required {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if err != nil {
+				onError(w, r, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ContextWithCWE(r.Context(), entry)))
+		})
+	}
+}
+
+// CWEErrorStatus 返回ResolveRequestCWE错误对应的HTTP状态码
+// 缺少或无效的ID返回400，条目不存在返回404，其他错误返回500
+func CWEErrorStatus(err error) int {
+	var invalid *invalidCWEError
+	switch {
+	case errors.Is(err, ErrMissingCWE), errors.As(err, &invalid):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// WriteCWEError 是中间件默认的错误处理函数，按CWEErrorStatus写入状态码和纯文本错误信息
+func WriteCWEError(w http.ResponseWriter, r *http.Request, err error) {
+	http.Error(w, err.Error(), CWEErrorStatus(err))
+}
+
+// invalidCWEError 表示请求中的CWE ID格式无效
+type invalidCWEError struct {
+	err error
+}
+
+func (e *invalidCWEError) Error() string {
+	return "无效的CWE ID: " + e.err.Error()
+}
+
+func (e *invalidCWEError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,143 @@
+package cwe
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newMiddlewareTestHandler 返回一个输出上下文中CWE条目ID的处理器
+func newMiddlewareTestHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entry, ok := CWEFromContext(r.Context())
+		if !ok {
+			w.Write([]byte("none"))
+			return
+		}
+		w.Write([]byte(entry.ID))
+	})
+}
+
+func TestCWEMiddleware(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(NewCWE("CWE-79", "Cross-site Scripting"))
+
+	tests := []struct {
+		name     string
+		required bool
+		target   string
+		header   string
+		status   int
+		body     string
+	}{
+		{"查询参数", true, "/?cwe=79", "", http.StatusOK, "CWE-79"},
+		{"请求头", true, "/", "cwe-79", http.StatusOK, "CWE-79"},
+		{"查询参数优先", true, "/?cwe=CWE-79", "CWE-89", http.StatusOK, "CWE-79"},
+		{"缺少ID且必需", true, "/", "", http.StatusBadRequest, ""},
+		{"缺少ID且可选", false, "/", "", http.StatusOK, "none"},
+		{"无效ID", false, "/?cwe=abc", "", http.StatusBadRequest, ""},
+		{"条目不存在", true, "/?cwe=89", "", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := CWEMiddleware(CWEMiddlewareOptions{
+				Lookup:   RegistryLookup(registry),
+				Required: tt.required,
+			})(newMiddlewareTestHandler())
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set(DefaultCWEHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("状态码应为%d，实际为%d", tt.status, rec.Code)
+			}
+			if tt.body != "" && rec.Body.String() != tt.body {
+				t.Errorf("响应应为%q，实际为%q", tt.body, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestCWEMiddlewareCustomOptions(t *testing.T) {
+	lookupErr := errors.New("上游不可用")
+	var handled error
+
+	handler := CWEMiddleware(CWEMiddlewareOptions{
+		Lookup: func(ctx context.Context, id string) (*CWE, error) {
+			return nil, lookupErr
+		},
+		QueryParam: "weakness",
+		OnError: func(w http.ResponseWriter, r *http.Request, err error) {
+			handled = err
+			w.WriteHeader(http.StatusTeapot)
+		},
+	})(newMiddlewareTestHandler())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?weakness=79", nil))
+
+	if rec.Code != http.StatusTeapot || !errors.Is(handled, lookupErr) {
+		t.Errorf("应调用自定义错误处理，状态码%d，错误%v", rec.Code, handled)
+	}
+	if CWEErrorStatus(lookupErr) != http.StatusInternalServerError {
+		t.Error("其他错误应对应500")
+	}
+}
+
+func TestCWEMiddlewareNilLookup(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Lookup为nil时应panic")
+		}
+	}()
+	CWEMiddleware(CWEMiddlewareOptions{})
+}
+
+func TestRegistryGetByIDNotFound(t *testing.T) {
+	_, err := NewRegistry().GetByID("CWE-1")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("应返回ErrNotFound，实际为%v", err)
+	}
+	if err.Error() != "未找到ID为CWE-1的CWE" {
+		t.Errorf("错误信息不应改变，实际为%q", err.Error())
+	}
+}
+
+func TestCWEMiddlewareFetcherLookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cwe/weakness/CWE-79":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"weaknesses": [{"id": "79", "name": "Cross-site Scripting"}]}`))
+		case "/cwe/weakness/CWE-500", "/cwe/category/CWE-500":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	handler := CWEMiddleware(CWEMiddlewareOptions{
+		Lookup:   FetcherLookup(newResumableTestFetcher(server.URL)),
+		Required: true,
+	})(newMiddlewareTestHandler())
+
+	for target, status := range map[string]int{
+		"/?cwe=79":     http.StatusOK,
+		"/?cwe=999999": http.StatusNotFound,
+		"/?cwe=500":    http.StatusInternalServerError,
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != status {
+			t.Errorf("%s: 状态码应为%d，实际为%d", target, status, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package cwe
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxRateLimitWait 是根据服务器限流头部等待的最长时间
+// 服务器要求的等待时间超过此值时按此值等待，避免异常的头部使客户端长时间挂起
+const DefaultMaxRateLimitWait = time.Minute
+
+// RateLimitStatus 表示从服务器响应头中观察到的限流状态
+//
+// 支持以下响应头(不区分大小写):
+// - X-RateLimit-Limit / RateLimit-Limit: 时间窗口内允许的请求总数
+// - X-RateLimit-Remaining / RateLimit-Remaining: 时间窗口内剩余的请求数
+// - X-RateLimit-Reset / RateLimit-Reset: 时间窗口重置时间，支持Unix时间戳和相对秒数
+// - Retry-After: 429响应中要求的等待时间，支持秒数和HTTP日期
+type RateLimitStatus struct {
+	// Known 服务器是否返回过限流头部，为false时其余字段无意义
+	Known bool
+
+	// Limit 时间窗口内允许的请求总数，未知时为-1
+	Limit int
+
+	// Remaining 时间窗口内剩余的请求数，未知时为-1
+	Remaining int
+
+	// Reset 时间窗口重置的时间，未知时为零值
+	Reset time.Time
+
+	// RetryAfter 最近一次429响应要求的等待时间
+	RetryAfter time.Duration
+
+	// UpdatedAt 最近一次更新限流状态的时间
+	UpdatedAt time.Time
+}
+
+// Exhausted 判断当前时间窗口内的请求配额是否已经用完且尚未重置
+func (s RateLimitStatus) Exhausted(now time.Time) bool {
+	return s.Known && s.Remaining == 0 && s.Reset.After(now)
+}
+
+// RateLimitStatus 获取最近一次从响应头中观察到的限流状态
+//
+// 方法功能:
+// 返回服务器通过X-RateLimit-*等响应头公布的限流信息。
+// 服务器从未返回过这类头部时，返回值的Known字段为false。
+//
+// 使用示例:
+// ```go
+// client := cwe.NewAPIClient()
+// client.GetWeakness("79")
+//
+// status := client.GetHTTPClient().RateLimitStatus()
+//
+//	if status.Known {
+//	    fmt.Printf("剩余请求数: %d/%d，重置时间: %v\n", status.Remaining, status.Limit, status.Reset)
+//	}
+//
+// ```
+func (c *HTTPClient) RateLimitStatus() RateLimitStatus {
+	c.rateLimitMutex.Lock()
+	defer c.rateLimitMutex.Unlock()
+
+	return c.rateLimit
+}
+
+// SetMaxRateLimitWait 设置根据服务器限流头部等待的最长时间
+func (c *HTTPClient) SetMaxRateLimitWait(wait time.Duration) {
+	if wait > 0 {
+		c.maxRateLimitWait = wait
+	}
+}
+
+// GetMaxRateLimitWait 获取根据服务器限流头部等待的最长时间
+func (c *HTTPClient) GetMaxRateLimitWait() time.Duration {
+	return c.maxRateLimitWait
+}
+
+// RateLimitStatus 获取最近一次从响应头中观察到的限流状态
+// 等同于client.GetHTTPClient().RateLimitStatus()
+func (c *APIClient) RateLimitStatus() RateLimitStatus {
+	return c.client.RateLimitStatus()
+}
+
+// waitForServerLimit 在服务器公布的配额用完时，等待到时间窗口重置，ctx被取消时返回其错误
+func (c *HTTPClient) waitForServerLimit(ctx context.Context) error {
+	now := time.Now()
+	status := c.RateLimitStatus()
+	if !status.Exhausted(now) {
+		return nil
+	}
+
+	return sleepContext(ctx, c.capRateLimitWait(status.Reset.Sub(now)))
+}
+
+// observeRateLimit 从响应头中更新限流状态
+// 响应中没有任何限流头部时保持原有状态不变
+func (c *HTTPClient) observeRateLimit(resp *http.Response) {
+	now := time.Now()
+	limit, hasLimit := rateLimitHeader(resp.Header, "Limit")
+	remaining, hasRemaining := rateLimitHeader(resp.Header, "Remaining")
+	reset, hasReset := rateLimitHeader(resp.Header, "Reset")
+	retryAfter, hasRetryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), now)
+
+	if !hasLimit && !hasRemaining && !hasReset && !hasRetryAfter {
+		return
+	}
+
+	c.rateLimitMutex.Lock()
+	defer c.rateLimitMutex.Unlock()
+
+	status := RateLimitStatus{
+		Known:     true,
+		Limit:     -1,
+		Remaining: -1,
+		UpdatedAt: now,
+	}
+	if hasLimit {
+		status.Limit = limit
+	}
+	if hasRemaining {
+		status.Remaining = remaining
+	}
+	if hasReset {
+		status.Reset = parseRateLimitReset(reset, now)
+	}
+	if hasRetryAfter {
+		status.RetryAfter = retryAfter
+		// 429响应通常只带Retry-After，据此推算配额恢复的时间
+		if !hasReset {
+			status.Reset = now.Add(retryAfter)
+		}
+		if !hasRemaining {
+			status.Remaining = 0
+		}
+	}
+
+	c.rateLimit = status
+}
+
+// tooManyRequestsWait 计算收到429响应后重试前需要等待的时间
+// 优先使用Retry-After，其次使用重置时间，都没有时使用重试间隔
+func (c *HTTPClient) tooManyRequestsWait() time.Duration {
+	status := c.RateLimitStatus()
+
+	wait := c.retryDelay
+	if status.RetryAfter > 0 {
+		wait = status.RetryAfter
+	} else if until := time.Until(status.Reset); until > 0 {
+		wait = until
+	}
+
+	return c.capRateLimitWait(wait)
+}
+
+// capRateLimitWait 将等待时间限制在maxRateLimitWait以内
+func (c *HTTPClient) capRateLimitWait(wait time.Duration) time.Duration {
+	max := c.maxRateLimitWait
+	if max <= 0 {
+		max = DefaultMaxRateLimitWait
+	}
+	if wait > max {
+		return max
+	}
+	return wait
+}
+
+// rateLimitHeader 读取X-RateLimit-<name>或RateLimit-<name>头部的整数值
+func rateLimitHeader(header http.Header, name string) (int, bool) {
+	for _, key := range []string{"X-RateLimit-" + name, "RateLimit-" + name} {
+		value := strings.TrimSpace(header.Get(key))
+		if value == "" {
+			continue
+		}
+		if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// parseRateLimitReset 将重置头部的值转换为时间
+// 大于10^9的值视为Unix时间戳(秒)，否则视为距现在的秒数
+func parseRateLimitReset(value int, now time.Time) time.Time {
+	if value > 1000000000 {
+		return time.Unix(int64(value), 0)
+	}
+	return now.Add(time.Duration(value) * time.Second)
+}
+
+// parseRetryAfter 解析Retry-After头部，支持秒数和HTTP日期两种格式
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := date.Sub(now)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
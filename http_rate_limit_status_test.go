@@ -0,0 +1,169 @@
+package cwe
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newRateLimitStatusTestClient() *HTTPClient {
+	client := NewHttpClient()
+	client.SetRateLimiter(NewHTTPRateLimiter(0))
+	client.SetRetryDelay(time.Millisecond)
+	return client
+}
+
+func TestRateLimitStatusUnknown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := newRateLimitStatusTestClient()
+	resp, err := client.Get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+
+	status := client.RateLimitStatus()
+	if status.Known || status.Limit != -1 || status.Remaining != -1 {
+		t.Errorf("没有限流头部时状态应为未知，但得到 %+v", status)
+	}
+}
+
+func TestRateLimitStatusFromHeaders(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Unix()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+	}))
+	defer server.Close()
+
+	client := NewAPIClientWithOptions(server.URL, DefaultTimeout, NewHTTPRateLimiter(0))
+	if _, err := client.GetHTTPClient().Get(context.Background(), server.URL); err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+
+	status := client.RateLimitStatus()
+	if !status.Known || status.Limit != 100 || status.Remaining != 42 {
+		t.Errorf("限流状态不正确: %+v", status)
+	}
+	if status.Reset.Unix() != reset {
+		t.Errorf("重置时间应为 %d，但得到 %d", reset, status.Reset.Unix())
+	}
+	if status.Exhausted(time.Now()) {
+		t.Error("仍有剩余配额时不应视为耗尽")
+	}
+}
+
+func TestRateLimit429RetryAfter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := newRateLimitStatusTestClient()
+	resp, err := client.Get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("429后重试应成功，但状态码为 %d", resp.StatusCode)
+	}
+	if atomic.LoadInt32(&calls) != 2 {
+		t.Errorf("期望请求2次，但请求了 %d 次", calls)
+	}
+	if !client.RateLimitStatus().Known {
+		t.Error("Retry-After应更新限流状态")
+	}
+}
+
+func TestRateLimit429ExhaustsRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := newRateLimitStatusTestClient()
+	client.SetMaxRetries(2)
+	client.SetMaxRateLimitWait(time.Millisecond)
+
+	resp, err := client.Get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("429不应作为网络错误返回: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("重试用完后应返回最后的429响应，但得到 %d", resp.StatusCode)
+	}
+	if atomic.LoadInt32(&calls) != 3 {
+		t.Errorf("期望请求3次，但请求了 %d 次", calls)
+	}
+}
+
+func TestRateLimitProactivePause(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("RateLimit-Remaining", "0")
+		w.Header().Set("RateLimit-Reset", "1")
+	}))
+	defer server.Close()
+
+	client := newRateLimitStatusTestClient()
+	client.SetMaxRateLimitWait(50 * time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(context.Background(), server.URL)
+		if err != nil {
+			t.Fatalf("请求失败: %v", err)
+		}
+		resp.Body.Close()
+		if i == 0 && !client.RateLimitStatus().Exhausted(time.Now()) {
+			t.Fatal("剩余配额为0时应视为耗尽")
+		}
+	}
+
+	start := time.Now()
+	resp, err := client.Get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("配额耗尽时应在请求前等待，但只用了 %v", elapsed)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Now()
+
+	if wait, ok := parseRetryAfter("5", now); !ok || wait != 5*time.Second {
+		t.Errorf("秒数格式解析错误: %v %v", wait, ok)
+	}
+
+	date := now.Add(10 * time.Second).UTC().Format(http.TimeFormat)
+	if wait, ok := parseRetryAfter(date, now); !ok || wait < 8*time.Second || wait > 10*time.Second {
+		t.Errorf("HTTP日期格式解析错误: %v %v", wait, ok)
+	}
+
+	if _, ok := parseRetryAfter("soon", now); ok {
+		t.Error("无效的值不应解析成功")
+	}
+}
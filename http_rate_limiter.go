@@ -33,10 +33,14 @@ type HTTPRateLimiter struct {
 	// 示例：5 * time.Second 表示每5秒允许1个请求
 	interval time.Duration
 
-	// lastRequest 记录上一次请求的时间
+	// lastRequest 记录上一次请求的时间，有调用者在等待时为最后一个已预约的发送时间
 	// 用于计算是否需要等待才能发送下一个请求
 	lastRequest time.Time
 
+	// slowdown 自适应限速在interval之上额外增加的间隔
+	// 由Throttle增加、Recover逐步减少，见EffectiveInterval
+	slowdown time.Duration
+
 	// mutex 用于在并发环境下保护lastRequest的访问
 	// 确保在多个goroutine中使用时的线程安全
 	mutex sync.Mutex
@@ -127,21 +131,11 @@ func NewHTTPRateLimiter(interval time.Duration) *HTTPRateLimiter {
 // - 每次调用都会获取锁，在高并发场景下可能影响性能
 // - 如果多个goroutine同时等待，它们会按照调用顺序依次获得发送请求的机会
 func (r *HTTPRateLimiter) WaitForRequest() {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
-
-	now := time.Now()
-	elapsed := now.Sub(r.lastRequest)
-
-	// 如果距离上次请求的时间小于指定间隔，则等待
-	if elapsed < r.interval {
-		waitTime := r.interval - elapsed
-		time.Sleep(waitTime)
-		now = time.Now()
+	// 在锁内预约发送时间，等待时不持有锁，避免阻塞SetInterval等方法
+	_, slot := r.reserve()
+	if wait := time.Until(slot); wait > 0 {
+		time.Sleep(wait)
 	}
-
-	// 更新上次请求时间
-	r.lastRequest = now
 }
 
 // ResetLastRequest 重置上次请求时间，使得下一次请求可以立即发送
@@ -0,0 +1,136 @@
+package cwe
+
+import (
+	"net/http"
+	"time"
+)
+
+// DefaultAdaptiveMaxInterval 是自适应限速将请求间隔放慢到的默认上限
+const DefaultAdaptiveMaxInterval = 2 * time.Minute
+
+// minThrottleInterval 是被服务器限流后请求间隔的最小值，避免interval为0时放慢不起作用
+const minThrottleInterval = time.Second
+
+// Throttle 在服务器限流后放慢请求频率
+//
+// 方法功能:
+// 将生效的请求间隔翻倍(至少为1秒)，且不小于retryAfter，最大不超过maxInterval；
+// retryAfter大于0时，下一个请求至少在retryAfter之后才会被放行。
+// 之后每次成功的请求调用Recover会逐步恢复到SetInterval设置的间隔。
+// 通常由启用了自适应限速的HTTPClient在收到429或503响应时自动调用。
+//
+// 参数:
+// - retryAfter: time.Duration - 服务器通过Retry-After要求的等待时间，未提供时为0
+// - maxInterval: time.Duration - 请求间隔的上限，<=0时使用DefaultAdaptiveMaxInterval
+func (r *HTTPRateLimiter) Throttle(retryAfter, maxInterval time.Duration) {
+	if maxInterval <= 0 {
+		maxInterval = DefaultAdaptiveMaxInterval
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	target := 2 * (r.interval + r.slowdown)
+	if target < minThrottleInterval {
+		target = minThrottleInterval
+	}
+	if target < retryAfter {
+		target = retryAfter
+	}
+	if target > maxInterval {
+		target = maxInterval
+	}
+	if target > r.interval {
+		r.slowdown = target - r.interval
+	}
+
+	if retryAfter > 0 {
+		next := time.Now().Add(retryAfter).Add(-(r.interval + r.slowdown))
+		if next.After(r.lastRequest) {
+			r.lastRequest = next
+		}
+	}
+}
+
+// Recover 在请求成功后逐步恢复请求频率
+// 每次调用减少Throttle额外增加的间隔的四分之一，剩余不足10毫秒时完全恢复
+func (r *HTTPRateLimiter) Recover() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.slowdown == 0 {
+		return
+	}
+	r.slowdown -= r.slowdown / 4
+	if r.slowdown < 10*time.Millisecond {
+		r.slowdown = 0
+	}
+}
+
+// EffectiveInterval 返回当前生效的请求间隔，即SetInterval设置的间隔加上自适应限速的放慢量
+func (r *HTTPRateLimiter) EffectiveInterval() time.Duration {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.interval + r.slowdown
+}
+
+// EffectiveRate 返回当前生效的每秒请求数，不限速时返回0
+func (r *HTTPRateLimiter) EffectiveRate() float64 {
+	interval := r.EffectiveInterval()
+	if interval <= 0 {
+		return 0
+	}
+	return float64(time.Second) / float64(interval)
+}
+
+// WithAdaptiveRateLimit 启用自适应限速，见SetAdaptiveRateLimit
+func WithAdaptiveRateLimit(maxInterval time.Duration) ClientOption {
+	return func(c *HTTPClient) {
+		c.SetAdaptiveRateLimit(maxInterval)
+	}
+}
+
+// SetAdaptiveRateLimit 启用根据服务器限流自动调整请求频率的自适应限速
+//
+// 方法功能:
+// 启用后收到429或503响应时调用当前速率限制器的Throttle放慢请求频率(遵守Retry-After)，
+// 之后每个成功的响应调用Recover逐步恢复，长时间构建树时即使服务器开始限流也能继续进行，
+// 而不是在重试次数用完后失败。按主机限速时只影响对应主机的限制器。
+// 限制器在多个客户端之间共享时，放慢对所有客户端生效。
+// 当前生效的频率可以通过速率限制器的EffectiveInterval或EffectiveRate查看。
+//
+// 参数:
+// - maxInterval: time.Duration - 请求间隔的上限，<=0时关闭自适应限速
+//
+// 使用示例:
+// ```go
+// client := cwe.NewAPIClient(cwe.WithAdaptiveRateLimit(time.Minute))
+// fetcher := cwe.NewDataFetcherWithClient(client)
+//
+// registry, err := fetcher.BuildCWETreeWithView("1000")
+// fmt.Println("当前请求间隔:", client.GetRateLimiter().EffectiveInterval())
+// ```
+func (c *HTTPClient) SetAdaptiveRateLimit(maxInterval time.Duration) {
+	c.adaptiveMaxInterval = maxInterval
+}
+
+// adaptRateLimit 启用自适应限速时根据响应状态码调整limiter
+func (c *HTTPClient) adaptRateLimit(limiter *HTTPRateLimiter, resp *http.Response) {
+	if c.adaptiveMaxInterval <= 0 {
+		return
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		retryAfter, _ := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		limiter.Throttle(c.capRateLimitWait(retryAfter), c.adaptiveMaxInterval)
+		if c.logger != nil {
+			c.logger.Warn("服务器限流，放慢请求频率", "status", resp.StatusCode, "interval", limiter.EffectiveInterval())
+		}
+	default:
+		if resp.StatusCode < 500 {
+			limiter.Recover()
+		}
+	}
+}
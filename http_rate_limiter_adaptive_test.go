@@ -0,0 +1,99 @@
+package cwe
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPRateLimiterThrottleRecover(t *testing.T) {
+	limiter := NewHTTPRateLimiter(0)
+
+	steps := []struct {
+		retryAfter  time.Duration
+		maxInterval time.Duration
+		expected    time.Duration
+	}{
+		{0, time.Minute, time.Second},
+		{0, time.Minute, 2 * time.Second},
+		{5 * time.Second, time.Minute, 5 * time.Second},
+		{0, 7 * time.Second, 7 * time.Second},
+	}
+	for _, step := range steps {
+		limiter.Throttle(step.retryAfter, step.maxInterval)
+		if got := limiter.EffectiveInterval(); got != step.expected {
+			t.Errorf("Throttle(%v, %v)后间隔应为%v，实际为%v", step.retryAfter, step.maxInterval, step.expected, got)
+		}
+	}
+	if limiter.GetInterval() != 0 {
+		t.Error("Throttle不应修改SetInterval设置的间隔")
+	}
+	if rate := limiter.EffectiveRate(); rate <= 0 || rate >= 1 {
+		t.Errorf("放慢后每秒请求数应小于1，实际为%v", rate)
+	}
+
+	limiter.Recover()
+	if got := limiter.EffectiveInterval(); got >= 7*time.Second || got == 0 {
+		t.Errorf("Recover应逐步恢复，实际间隔为%v", got)
+	}
+	for i := 0; i < 100; i++ {
+		limiter.Recover()
+	}
+	if got := limiter.EffectiveInterval(); got != 0 || limiter.EffectiveRate() != 0 {
+		t.Errorf("多次成功后应完全恢复，实际间隔为%v", got)
+	}
+}
+
+func TestHTTPClientAdaptiveRateLimit(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	limiter := NewHTTPRateLimiter(0)
+	client := NewHttpClient(WithAdaptiveRateLimit(80 * time.Millisecond))
+	client.SetRateLimiter(limiter)
+	client.SetRetryDelay(time.Millisecond)
+
+	resp, err := client.GetSimple(server.URL)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+
+	// 429放慢到上限80毫秒，重试成功后恢复四分之一
+	if got := limiter.EffectiveInterval(); got != 60*time.Millisecond {
+		t.Errorf("限流后间隔应为60ms，实际为%v", got)
+	}
+
+	for i := 0; i < 20; i++ {
+		resp, err := client.GetSimple(server.URL)
+		if err != nil {
+			t.Fatalf("请求失败: %v", err)
+		}
+		resp.Body.Close()
+	}
+	if got := limiter.EffectiveInterval(); got != 0 {
+		t.Errorf("持续成功后应恢复原来的频率，实际间隔为%v", got)
+	}
+
+	// 未启用自适应限速时不调整
+	client.SetAdaptiveRateLimit(0)
+	atomic.StoreInt32(&requests, 0)
+	resp, err = client.GetSimple(server.URL)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+	if got := limiter.EffectiveInterval(); got != 0 {
+		t.Errorf("关闭自适应限速后不应放慢，实际间隔为%v", got)
+	}
+}
@@ -0,0 +1,217 @@
+package cwe
+
+import (
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimiterRegistry 按目标主机管理速率限制器
+//
+// 每个主机使用独立的HTTPRateLimiter，访问本地镜像不会受到为cwe-api.mitre.org设置的保守限制影响，
+// 反之亦然。第一次访问未登记的主机时，按默认间隔创建新的限制器。
+// 主机名不区分大小写，http的80端口和https的443端口会被省略，"cwe-api.mitre.org"和
+// "https://cwe-api.mitre.org:443/api/v1"对应同一个限制器。
+// 此结构体是线程安全的，通常在多个客户端之间共享。
+type RateLimiterRegistry struct {
+	mutex           sync.Mutex
+	limiters        map[string]*HTTPRateLimiter
+	defaultInterval time.Duration
+
+	// loopbackInterval 未登记的本机地址(localhost、127.0.0.0/8、::1)使用的请求间隔
+	loopbackInterval time.Duration
+}
+
+// NewRateLimiterRegistry 创建按主机管理速率限制器的注册表
+//
+// 参数:
+// - defaultInterval: time.Duration - 未登记的主机使用的请求间隔，<=0表示不限制；
+// 本机地址默认也使用此间隔，可以通过SetLoopbackInterval单独设置
+//
+// 返回值:
+// - *RateLimiterRegistry: 空的注册表
+func NewRateLimiterRegistry(defaultInterval time.Duration) *RateLimiterRegistry {
+	return &RateLimiterRegistry{
+		limiters:         make(map[string]*HTTPRateLimiter),
+		defaultInterval:  defaultInterval,
+		loopbackInterval: defaultInterval,
+	}
+}
+
+// Limiter 返回主机使用的速率限制器，未登记的主机会按默认间隔创建并登记
+// host可以是主机名、"主机名:端口"或完整的URL
+func (r *RateLimiterRegistry) Limiter(host string) *HTTPRateLimiter {
+	key := rateLimiterHost(host)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	limiter, exists := r.limiters[key]
+	if !exists {
+		interval := r.defaultInterval
+		if isLoopbackHost(key) {
+			interval = r.loopbackInterval
+		}
+		limiter = NewHTTPRateLimiter(interval)
+		r.limiters[key] = limiter
+	}
+	return limiter
+}
+
+// Set 为主机指定速率限制器，limiter为nil时移除登记，之后按默认间隔重新创建
+// 同一个限制器可以登记给多个主机，使它们共享请求频率
+func (r *RateLimiterRegistry) Set(host string, limiter *HTTPRateLimiter) {
+	key := rateLimiterHost(host)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if limiter == nil {
+		delete(r.limiters, key)
+		return
+	}
+	r.limiters[key] = limiter
+}
+
+// SetInterval 调整主机的请求间隔，<=0表示不限制
+//
+// 方法功能:
+// 修改主机当前使用的限制器(未登记时先按默认间隔创建)，与该主机共享同一限制器的其他主机也会受影响。
+//
+// 使用示例:
+// ```go
+// // 内网镜像每100毫秒1个请求
+// cwe.DefaultRateLimiters.SetInterval("http://cwe-mirror.internal:8080", 100*time.Millisecond)
+//
+// client := cwe.NewAPIClientWithOptions("http://cwe-mirror.internal:8080/api/v1", 0)
+// ```
+func (r *RateLimiterRegistry) SetInterval(host string, interval time.Duration) {
+	r.Limiter(host).SetInterval(interval)
+}
+
+// SetDefaultInterval 设置之后新登记的主机使用的请求间隔，已登记的主机不受影响
+func (r *RateLimiterRegistry) SetDefaultInterval(interval time.Duration) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.defaultInterval = interval
+}
+
+// SetLoopbackInterval 设置之后新登记的本机地址(localhost、127.0.0.0/8、::1)使用的请求间隔
+// 本机上的镜像或测试服务器通常不需要与公共API相同的保守限制
+func (r *RateLimiterRegistry) SetLoopbackInterval(interval time.Duration) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.loopbackInterval = interval
+}
+
+// Intervals 返回所有已登记主机当前的请求间隔，用于检查各主机的限速配置
+func (r *RateLimiterRegistry) Intervals() map[string]time.Duration {
+	r.mutex.Lock()
+	limiters := make(map[string]*HTTPRateLimiter, len(r.limiters))
+	for host, limiter := range r.limiters {
+		limiters[host] = limiter
+	}
+	r.mutex.Unlock()
+
+	intervals := make(map[string]time.Duration, len(limiters))
+	for host, limiter := range limiters {
+		intervals[host] = limiter.GetInterval()
+	}
+	return intervals
+}
+
+// Hosts 返回所有已登记的主机，按字母顺序排序
+func (r *RateLimiterRegistry) Hosts() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	hosts := make([]string, 0, len(r.limiters))
+	for host := range r.limiters {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// rateLimiterHost 将主机名、"主机名:端口"或URL规范化为注册表的键
+func rateLimiterHost(host string) string {
+	host = strings.TrimSpace(host)
+	scheme := ""
+	if strings.Contains(host, "://") {
+		if parsed, err := url.Parse(host); err == nil {
+			scheme = parsed.Scheme
+			host = parsed.Host
+		}
+	}
+	host = strings.ToLower(host)
+
+	switch {
+	case strings.HasSuffix(host, ":443") && scheme != "http":
+		host = strings.TrimSuffix(host, ":443")
+	case strings.HasSuffix(host, ":80") && scheme != "https":
+		host = strings.TrimSuffix(host, ":80")
+	}
+	return host
+}
+
+// isLoopbackHost 判断规范化后的主机是否为本机地址
+func isLoopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// DefaultRateLimiters 是API客户端默认使用的按主机速率限制器注册表
+// cwe-api.mitre.org使用DefaultRateLimiter，本机地址不限速，其他主机各自使用每10秒1个请求的独立限制器
+var DefaultRateLimiters = newDefaultRateLimiters()
+
+// newDefaultRateLimiters 创建DefaultRateLimiters，官方API主机与DefaultRateLimiter共享限制器，
+// 使直接使用DefaultRateLimiter的旧代码和新的API客户端仍遵守同一个请求频率
+func newDefaultRateLimiters() *RateLimiterRegistry {
+	registry := NewRateLimiterRegistry(DefaultRateLimiter.GetInterval())
+	registry.SetLoopbackInterval(0)
+	registry.Set(BaseURL, DefaultRateLimiter)
+	return registry
+}
+
+// WithRateLimiterRegistry 按目标主机选择速率限制器，见SetRateLimiterRegistry
+func WithRateLimiterRegistry(registry *RateLimiterRegistry) ClientOption {
+	return func(c *HTTPClient) {
+		c.SetRateLimiterRegistry(registry)
+	}
+}
+
+// SetRateLimiterRegistry 设置按目标主机选择速率限制器的注册表
+//
+// 方法功能:
+// 设置后每个请求使用registry中对应主机的限制器，而不是SetRateLimiter设置的单个限制器。
+// 之后调用SetRateLimiter或使用WithRateLimit选项会取消按主机限速，所有请求重新共用一个限制器。
+// registry为nil时取消按主机限速。
+//
+// 参数:
+// - registry: *RateLimiterRegistry - 速率限制器注册表，如DefaultRateLimiters
+func (c *HTTPClient) SetRateLimiterRegistry(registry *RateLimiterRegistry) {
+	c.rateLimiters = registry
+}
+
+// GetRateLimiterRegistry 返回按主机限速使用的注册表，未启用时返回nil
+func (c *HTTPClient) GetRateLimiterRegistry() *RateLimiterRegistry {
+	return c.rateLimiters
+}
+
+// limiterFor 返回请求rawURL时使用的速率限制器
+func (c *HTTPClient) limiterFor(rawURL string) *HTTPRateLimiter {
+	if c.rateLimiters != nil {
+		return c.rateLimiters.Limiter(rawURL)
+	}
+	return c.rateLimiter
+}
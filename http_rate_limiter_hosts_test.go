@@ -0,0 +1,85 @@
+package cwe
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterRegistryPerHost(t *testing.T) {
+	registry := NewRateLimiterRegistry(time.Second)
+	registry.SetLoopbackInterval(0)
+
+	mitre := registry.Limiter("https://CWE-API.mitre.org:443/api/v1")
+	if registry.Limiter("cwe-api.mitre.org") != mitre {
+		t.Error("同一主机的不同写法应使用同一个限制器")
+	}
+	if registry.Limiter("http://mirror.example.com") == mitre {
+		t.Error("不同主机应使用独立的限制器")
+	}
+	if mitre.GetInterval() != time.Second {
+		t.Errorf("未登记的主机应使用默认间隔，实际为%v", mitre.GetInterval())
+	}
+	for _, host := range []string{"http://127.0.0.1:8080", "localhost:9000", "http://[::1]:80"} {
+		if interval := registry.Limiter(host).GetInterval(); interval != 0 {
+			t.Errorf("本机地址%s应使用本机间隔，实际为%v", host, interval)
+		}
+	}
+
+	registry.SetInterval("mirror.example.com", 50*time.Millisecond)
+	shared := NewHTTPRateLimiter(2 * time.Second)
+	registry.Set("a.example.com", shared)
+	registry.Set("b.example.com", shared)
+
+	intervals := registry.Intervals()
+	if intervals["mirror.example.com"] != 50*time.Millisecond || intervals["b.example.com"] != 2*time.Second {
+		t.Errorf("各主机的间隔不正确: %v", intervals)
+	}
+
+	registry.Set("b.example.com", nil)
+	expected := []string{"127.0.0.1:8080", "[::1]", "a.example.com", "cwe-api.mitre.org", "localhost:9000", "mirror.example.com"}
+	if hosts := registry.Hosts(); !reflect.DeepEqual(hosts, expected) {
+		t.Errorf("登记的主机不正确: %v", hosts)
+	}
+}
+
+func TestDefaultRateLimitersSharesOfficialLimiter(t *testing.T) {
+	if DefaultRateLimiters.Limiter(BaseURL) != DefaultRateLimiter {
+		t.Error("官方API应使用DefaultRateLimiter")
+	}
+	if NewAPIClient().GetRateLimiter() != DefaultRateLimiter {
+		t.Error("默认客户端访问官方API时应使用DefaultRateLimiter")
+	}
+}
+
+func TestHTTPClientRateLimiterRegistry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	registry := NewRateLimiterRegistry(time.Hour)
+	registry.SetInterval(server.URL, 0)
+
+	client := NewHttpClient(WithRateLimiterRegistry(registry))
+	// 全局限制器只用于未启用按主机限速的情况，这里设置为很长的间隔以确认没有使用它
+	client.rateLimiter = NewHTTPRateLimiter(time.Hour)
+	client.rateLimiter.WaitForRequest()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		resp, err := client.GetSimple(server.URL)
+		if err != nil {
+			t.Fatalf("请求失败: %v", err)
+		}
+		resp.Body.Close()
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("应使用主机对应的不限速限制器，实际耗时%v", elapsed)
+	}
+
+	client.SetRateLimiter(NewHTTPRateLimiter(0))
+	if client.GetRateLimiterRegistry() != nil {
+		t.Error("SetRateLimiter应取消按主机限速")
+	}
+}
@@ -0,0 +1,127 @@
+package cwe
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RateLimitedTransport 是在发送每个请求前等待速率限制器的http.RoundTripper
+//
+// 使任何标准的http.Client都能与APIClient共用同一套限速设施：
+// 传入client.GetHTTPClient().GetRateLimiter()时，应用中的其他请求与CWE API的请求共享同一个请求间隔。
+// RateLimitedTransport是并发安全的，可以被多个http.Client共享。
+type RateLimitedTransport struct {
+	limiter *HTTPRateLimiter
+	base    http.RoundTripper
+}
+
+// NewRateLimitedTransport 创建带速率限制的http.RoundTripper
+//
+// 方法功能:
+// 每个请求在交给base之前先等待limiter放行，等待期间请求的上下文被取消时立即返回上下文的错误，
+// 不会发送请求，也不会占用限速器的配额。
+// 与HTTPClient不同，RateLimitedTransport只负责限速，不做重试，也不处理429响应。
+//
+// 参数:
+// - limiter: *HTTPRateLimiter - 速率限制器，为nil时使用DefaultRateLimiter
+// - base: http.RoundTripper - 实际发送请求的Transport，为nil时使用http.DefaultTransport
+//
+// 返回值:
+// - *RateLimitedTransport: 带速率限制的RoundTripper
+//
+// 使用示例:
+// ```go
+// apiClient := cwe.NewAPIClient()
+//
+// // 应用中的其他HTTP请求与CWE API共享同一个限速器
+//
+//	httpClient := &http.Client{
+//	    Transport: cwe.NewRateLimitedTransport(apiClient.GetHTTPClient().GetRateLimiter(), nil),
+//	    Timeout:   30 * time.Second,
+//	}
+//
+// resp, err := httpClient.Get("https://cwe.mitre.org/data/downloads.html")
+// ```
+func NewRateLimitedTransport(limiter *HTTPRateLimiter, base http.RoundTripper) *RateLimitedTransport {
+	if limiter == nil {
+		limiter = DefaultRateLimiter
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RateLimitedTransport{
+		limiter: limiter,
+		base:    base,
+	}
+}
+
+// RoundTrip 实现http.RoundTripper接口
+func (t *RateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.WaitContext(req.Context()); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// Limiter 返回Transport使用的速率限制器
+func (t *RateLimitedTransport) Limiter() *HTTPRateLimiter {
+	return t.limiter
+}
+
+// WaitContext 与WaitForRequest相同，但ctx被取消时停止等待
+//
+// 参数:
+// - ctx: context.Context - 等待期间被取消时返回其错误
+//
+// 返回值:
+// - error: ctx在放行前被取消时返回ctx.Err()，此时不记录为一次请求
+func (r *HTTPRateLimiter) WaitContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	previous, slot := r.reserve()
+	wait := time.Until(slot)
+	if wait <= 0 {
+		return nil
+	}
+
+	// 等待期间不持有锁，排在后面的调用者可以各自预约并响应自己的ctx
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		r.cancelReservation(previous, slot)
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve 预约下一个可以发送请求的时间并记录为上一次请求的时间，返回预约前的记录和预约的时间
+func (r *HTTPRateLimiter) reserve() (previous, slot time.Time) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	previous = r.lastRequest
+	slot = time.Now()
+	if next := previous.Add(r.interval + r.slowdown); next.After(slot) {
+		slot = next
+	}
+	r.lastRequest = slot
+	return previous, slot
+}
+
+// cancelReservation 撤销reserve的预约，之后已有其他调用者预约时保持不变
+func (r *HTTPRateLimiter) cancelReservation(previous, slot time.Time) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.lastRequest.Equal(slot) {
+		r.lastRequest = previous
+	}
+}
@@ -0,0 +1,104 @@
+package cwe
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimitedTransport(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer server.Close()
+
+	limiter := NewHTTPRateLimiter(50 * time.Millisecond)
+	client := &http.Client{Transport: NewRateLimitedTransport(limiter, nil)}
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("请求失败: %v", err)
+		}
+		resp.Body.Close()
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("3个请求应至少间隔100ms，实际耗时%v", elapsed)
+	}
+	if atomic.LoadInt32(&requests) != 3 {
+		t.Errorf("服务器应收到3个请求，实际为%d", requests)
+	}
+}
+
+func TestRateLimitedTransportSharesAPIClientLimiter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	apiClient := NewAPIClientWithOptions(server.URL, DefaultTimeout, NewHTTPRateLimiter(time.Hour))
+	limiter := apiClient.GetHTTPClient().GetRateLimiter()
+	transport := NewRateLimitedTransport(limiter, nil)
+	if transport.Limiter() != limiter {
+		t.Fatal("Transport应使用传入的限速器")
+	}
+
+	// 第一个请求立即放行，之后的请求需要等待一小时，取消上下文后立即返回
+	limiter.WaitForRequest()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if _, err := (&http.Client{Transport: transport}).Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("等待限速时上下文超时应返回DeadlineExceeded，实际: %v", err)
+	}
+}
+
+func TestRateLimitedTransportDefaults(t *testing.T) {
+	transport := NewRateLimitedTransport(nil, nil)
+	if transport.Limiter() != DefaultRateLimiter || transport.base != http.DefaultTransport {
+		t.Error("limiter和base为nil时应使用默认值")
+	}
+}
+
+func TestHTTPRateLimiterWaitContextConcurrent(t *testing.T) {
+	limiter := NewHTTPRateLimiter(time.Second)
+	if err := limiter.WaitContext(context.Background()); err != nil {
+		t.Fatalf("第一次等待不应失败: %v", err)
+	}
+
+	// 第一个等待者排在下一个时间槽，ctx不会过期
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstDone := make(chan error, 1)
+	go func() { firstDone <- limiter.WaitContext(firstCtx) }()
+	time.Sleep(20 * time.Millisecond)
+
+	// 第二个等待者的ctx先过期，必须在过期后立即返回，不能等第一个等待者放行
+	secondCtx, cancelSecond := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelSecond()
+	start := time.Now()
+	err := limiter.WaitContext(secondCtx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("第二个等待者应返回context.DeadlineExceeded，实际为%v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("第二个等待者应在ctx过期后返回，实际耗时%v", elapsed)
+	}
+
+	// 等待期间其他方法不应被阻塞
+	start = time.Now()
+	if got := limiter.EffectiveInterval(); got != time.Second {
+		t.Errorf("EffectiveInterval应为1s，实际为%v", got)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("EffectiveInterval不应等待限速器，实际耗时%v", elapsed)
+	}
+
+	cancelFirst()
+	if err := <-firstDone; !errors.Is(err, context.Canceled) {
+		t.Errorf("第一个等待者应返回context.Canceled，实际为%v", err)
+	}
+}
@@ -0,0 +1,377 @@
+package cwe
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// 录制/回放相关的环境变量
+const (
+	// EnvRecordMode 指定录制模式的环境变量，取值为"record"、"replay"、"auto"或"off"
+	EnvRecordMode = "CWE_HTTP_RECORD"
+
+	// EnvRecordDir 指定录制文件目录的环境变量
+	EnvRecordDir = "CWE_HTTP_CASSETTE_DIR"
+)
+
+// RecordMode 表示RecordingTransport的工作模式
+type RecordMode int
+
+const (
+	// RecordModeOff 不录制也不回放，直接转发请求
+	RecordModeOff RecordMode = iota
+
+	// RecordModeRecord 转发请求并将响应写入磁盘，覆盖已有的录制
+	RecordModeRecord
+
+	// RecordModeReplay 只从磁盘回放响应，没有录制时返回错误，不会发出真实请求
+	RecordModeReplay
+
+	// RecordModeAuto 有录制时回放，否则转发请求并录制
+	RecordModeAuto
+)
+
+// ErrNoRecording 表示回放模式下找不到请求对应的录制
+var ErrNoRecording = errors.New("没有找到请求对应的录制")
+
+// DefaultScrubbedHeaders 默认在录制时从请求头和响应头中移除的敏感头部
+// "apiKey"是NVD API传递密钥的请求头，见NVDClient.SetAPIKey
+var DefaultScrubbedHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "apiKey"}
+
+// sensitiveHeaderMarkers 头部名称(不区分大小写)包含其中任一片段时，录制时总是移除该头部
+// 覆盖WithAPIKey等选项以自定义名称发送的密钥和令牌
+var sensitiveHeaderMarkers = []string{"key", "token", "auth"}
+
+// ParseRecordMode 解析录制模式字符串
+// 空字符串解析为RecordModeOff；无法识别的值返回错误
+func ParseRecordMode(s string) (RecordMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "off":
+		return RecordModeOff, nil
+	case "record":
+		return RecordModeRecord, nil
+	case "replay":
+		return RecordModeReplay, nil
+	case "auto":
+		return RecordModeAuto, nil
+	default:
+		return RecordModeOff, fmt.Errorf("无法识别的录制模式: %s", s)
+	}
+}
+
+// recording 是单个请求/响应录制在磁盘上的格式
+type recording struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+
+	// RequestHeader 和RequestBody 是请求头和请求体，便于在问题报告中核对发出的请求
+	// 非文本请求体以base64保存在RequestBodyBase64中
+	RequestHeader     http.Header `json:"request_header,omitempty"`
+	RequestBody       string      `json:"request_body,omitempty"`
+	RequestBodyBase64 []byte      `json:"request_body_base64,omitempty"`
+
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+
+	// Body 是UTF-8文本响应体；非文本响应体以base64保存在BodyBase64中
+	Body       string `json:"body,omitempty"`
+	BodyBase64 []byte `json:"body_base64,omitempty"`
+}
+
+// RecordingTransport 是VCR风格的http.RoundTripper
+//
+// 在录制模式下，它将请求和真实响应按请求方法、URL和请求体保存到Dir目录中的JSON文件；
+// 在回放模式下，它从这些文件确定性地返回响应而不发出网络请求。
+// 同一URL的不同请求体分别录制，没有请求体的请求只按方法和URL区分。
+// 录制时会移除ScrubHeaders中列出的请求头和响应头，以及名称中包含key、token或auth的头部，避免敏感信息写入磁盘。
+//
+// 适用于下游项目的集成测试：首次以record模式运行获取真实响应，之后以replay模式离线运行。
+type RecordingTransport struct {
+	// Dir 录制文件所在目录
+	Dir string
+
+	// Mode 工作模式
+	Mode RecordMode
+
+	// Next 实际发送请求的Transport，为nil时使用http.DefaultTransport
+	Next http.RoundTripper
+
+	// ScrubHeaders 录制时要移除的请求头和响应头，为nil时使用DefaultScrubbedHeaders
+	// 名称中包含key、token或auth的头部不论是否列出都会被移除
+	ScrubHeaders []string
+}
+
+// NewRecordingTransport 创建一个录制/回放Transport
+//
+// 参数:
+// - dir: string - 录制文件目录
+// - mode: RecordMode - 工作模式
+// - next: http.RoundTripper - 实际发送请求的Transport，可为nil
+//
+// 返回值:
+// - *RecordingTransport: 配置完成的Transport
+func NewRecordingTransport(dir string, mode RecordMode, next http.RoundTripper) *RecordingTransport {
+	return &RecordingTransport{
+		Dir:  dir,
+		Mode: mode,
+		Next: next,
+	}
+}
+
+// NewRecordingTransportFromEnv 根据环境变量创建录制/回放Transport
+//
+// 方法功能:
+// 读取CWE_HTTP_RECORD和CWE_HTTP_CASSETTE_DIR环境变量。
+// 录制模式为off或未设置时返回nil，表示不需要包装Transport。
+//
+// 返回值:
+// - *RecordingTransport: 配置完成的Transport，未启用时为nil
+// - error: 模式无法识别或启用时未指定目录时返回错误
+func NewRecordingTransportFromEnv(next http.RoundTripper) (*RecordingTransport, error) {
+	mode, err := ParseRecordMode(os.Getenv(EnvRecordMode))
+	if err != nil {
+		return nil, err
+	}
+	if mode == RecordModeOff {
+		return nil, nil
+	}
+
+	dir := os.Getenv(EnvRecordDir)
+	if dir == "" {
+		return nil, fmt.Errorf("启用录制时必须通过%s指定目录", EnvRecordDir)
+	}
+
+	return NewRecordingTransport(dir, mode, next), nil
+}
+
+// RoundTrip 实现http.RoundTripper接口
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, fmt.Errorf("读取请求体失败: %w", err)
+	}
+	if reqBody != nil {
+		// RoundTripper不能修改传入的请求，用读取的内容构造新的请求体
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	path := t.recordingPath(req, reqBody)
+
+	if t.Mode == RecordModeReplay || t.Mode == RecordModeAuto {
+		resp, err := t.replay(req, path)
+		if err == nil {
+			return resp, nil
+		}
+		if t.Mode == RecordModeReplay || !errors.Is(err, ErrNoRecording) {
+			return nil, err
+		}
+	}
+
+	resp, err := t.next().RoundTrip(req)
+	if err != nil || t.Mode == RecordModeOff {
+		return resp, err
+	}
+
+	if err := t.record(req, reqBody, resp, path); err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("保存录制失败: %w", err)
+	}
+	return resp, nil
+}
+
+// next 返回实际发送请求的Transport
+func (t *RecordingTransport) next() http.RoundTripper {
+	if t.Next != nil {
+		return t.Next
+	}
+	return http.DefaultTransport
+}
+
+// recordingPath 根据请求方法、URL和请求体计算录制文件路径
+// 没有请求体时与只按方法和URL计算的路径相同，已有的录制仍然可以回放
+func (t *RecordingTransport) recordingPath(req *http.Request, body []byte) string {
+	key := req.Method + " " + req.URL.String()
+	if len(body) > 0 {
+		bodySum := sha256.Sum256(body)
+		key += " " + hex.EncodeToString(bodySum[:])
+	}
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(t.Dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// readRequestBody 读取并关闭请求体，没有请求体时返回nil
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	return io.ReadAll(req.Body)
+}
+
+// replay 从录制文件构造响应
+func (t *RecordingTransport) replay(req *http.Request, path string) (*http.Response, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoRecording, req.Method, req.URL)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rec recording
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("解析录制文件%s失败: %w", path, err)
+	}
+
+	body := []byte(rec.Body)
+	if rec.BodyBase64 != nil {
+		body = rec.BodyBase64
+	}
+
+	header := rec.Header
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.StatusCode, http.StatusText(rec.StatusCode)),
+		StatusCode:    rec.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// record 读取响应体并与请求一起写入录制文件，然后用缓存的内容替换响应体
+func (t *RecordingTransport) record(req *http.Request, reqBody []byte, resp *http.Response, path string) error {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	rec := recording{
+		Method:        req.Method,
+		URL:           req.URL.String(),
+		RequestHeader: t.scrub(req.Header),
+		StatusCode:    resp.StatusCode,
+		Header:        t.scrub(resp.Header),
+	}
+	if utf8.Valid(reqBody) {
+		rec.RequestBody = string(reqBody)
+	} else {
+		rec.RequestBodyBase64 = reqBody
+	}
+	if utf8.Valid(body) {
+		rec.Body = string(body)
+	} else {
+		rec.BodyBase64 = body
+	}
+
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(t.Dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// scrub 返回移除了ScrubHeaders和敏感名称头部的副本，header为空时返回nil
+func (t *RecordingTransport) scrub(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+
+	header = header.Clone()
+	scrub := t.ScrubHeaders
+	if scrub == nil {
+		scrub = DefaultScrubbedHeaders
+	}
+	for _, name := range scrub {
+		header.Del(name)
+	}
+	for name := range header {
+		if isSensitiveHeader(name) {
+			delete(header, name)
+		}
+	}
+	return header
+}
+
+// isSensitiveHeader 判断头部名称是否包含sensitiveHeaderMarkers中的片段
+func isSensitiveHeader(name string) bool {
+	name = strings.ToLower(name)
+	for _, marker := range sensitiveHeaderMarkers {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithRecorder 为HTTP客户端启用录制/回放
+//
+// 方法功能:
+// 用RecordingTransport包装底层http.Client的Transport。
+// 注意选项在创建时生效，之后通过SetClient替换底层客户端会移除录制功能。
+//
+// 参数:
+// - dir: string - 录制文件目录
+// - mode: RecordMode - 工作模式，RecordModeOff时不做任何修改
+//
+// 使用示例:
+// ```go
+// client := cwe.NewHttpClient(cwe.WithRecorder("testdata/cassettes", cwe.RecordModeAuto))
+// ```
+func WithRecorder(dir string, mode RecordMode) ClientOption {
+	return func(c *HTTPClient) {
+		if mode == RecordModeOff {
+			return
+		}
+		c.wrapTransport(func(next http.RoundTripper) http.RoundTripper {
+			return NewRecordingTransport(dir, mode, next)
+		})
+	}
+}
+
+// WithRecorderFromEnv 根据CWE_HTTP_RECORD和CWE_HTTP_CASSETTE_DIR环境变量启用录制/回放
+// 环境变量未设置或配置无效时不做任何修改
+func WithRecorderFromEnv() ClientOption {
+	return func(c *HTTPClient) {
+		mode, err := ParseRecordMode(os.Getenv(EnvRecordMode))
+		if err != nil || mode == RecordModeOff || os.Getenv(EnvRecordDir) == "" {
+			return
+		}
+		WithRecorder(os.Getenv(EnvRecordDir), mode)(c)
+	}
+}
+
+// wrapTransport 用wrap返回的Transport替换底层客户端的Transport
+// 会复制底层http.Client，避免修改调用方共享的客户端
+func (c *HTTPClient) wrapTransport(wrap func(next http.RoundTripper) http.RoundTripper) {
+	clientCopy := *c.client
+	next := clientCopy.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	clientCopy.Transport = wrap(next)
+	c.client = &clientCopy
+}
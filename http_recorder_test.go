@@ -0,0 +1,207 @@
+package cwe
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordingTransportRecordAndReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Write([]byte(`{"version":"4.14"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	// 录制
+	recorder := &http.Client{Transport: NewRecordingTransport(dir, RecordModeRecord, nil)}
+	resp, err := recorder.Get(server.URL + "/cwe/version")
+	if err != nil {
+		t.Fatalf("录制请求失败: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != `{"version":"4.14"}` {
+		t.Errorf("录制模式应返回真实响应体，但得到 %s", body)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("期望1个录制文件，但得到 %d 个", len(files))
+	}
+	data, _ := os.ReadFile(files[0])
+	if strings.Contains(string(data), "secret") {
+		t.Error("录制文件中不应包含被清除的响应头")
+	}
+
+	// 回放：服务器关闭后仍能得到相同响应
+	server.Close()
+	replayer := &http.Client{Transport: NewRecordingTransport(dir, RecordModeReplay, nil)}
+	resp, err = replayer.Get(server.URL + "/cwe/version")
+	if err != nil {
+		t.Fatalf("回放请求失败: %v", err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != `{"version":"4.14"}` {
+		t.Errorf("回放响应不符合预期: %d %s", resp.StatusCode, body)
+	}
+	if resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("回放响应应保留普通响应头")
+	}
+	if calls != 1 {
+		t.Errorf("回放不应发出真实请求，服务器被调用 %d 次", calls)
+	}
+
+	// 没有录制的请求在回放模式下返回错误
+	if _, err := replayer.Get(server.URL + "/cwe/other"); err == nil || !strings.Contains(err.Error(), ErrNoRecording.Error()) {
+		t.Errorf("缺少录制时应返回ErrNoRecording，但得到 %v", err)
+	}
+}
+
+func TestRecordingTransportAuto(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := NewHttpClient(WithRecorder(t.TempDir(), RecordModeAuto))
+	client.SetRateLimiter(NewHTTPRateLimiter(0))
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(context.Background(), server.URL)
+		if err != nil {
+			t.Fatalf("请求失败: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	if calls != 1 {
+		t.Errorf("auto模式下只有首次请求应发往服务器，但调用了 %d 次", calls)
+	}
+}
+
+func TestParseRecordMode(t *testing.T) {
+	cases := map[string]RecordMode{
+		"":       RecordModeOff,
+		"off":    RecordModeOff,
+		"Record": RecordModeRecord,
+		"replay": RecordModeReplay,
+		"auto":   RecordModeAuto,
+	}
+	for input, expected := range cases {
+		mode, err := ParseRecordMode(input)
+		if err != nil || mode != expected {
+			t.Errorf("ParseRecordMode(%q) = %v, %v; 期望 %v", input, mode, err, expected)
+		}
+	}
+	if _, err := ParseRecordMode("bogus"); err == nil {
+		t.Error("无法识别的模式应返回错误")
+	}
+}
+
+func TestNewRecordingTransportFromEnv(t *testing.T) {
+	t.Setenv(EnvRecordMode, "")
+	if transport, err := NewRecordingTransportFromEnv(nil); err != nil || transport != nil {
+		t.Errorf("未启用时应返回nil, nil，但得到 %v, %v", transport, err)
+	}
+
+	t.Setenv(EnvRecordMode, "replay")
+	t.Setenv(EnvRecordDir, "")
+	if _, err := NewRecordingTransportFromEnv(nil); err == nil {
+		t.Error("未指定目录时应返回错误")
+	}
+
+	dir := t.TempDir()
+	t.Setenv(EnvRecordDir, dir)
+	transport, err := NewRecordingTransportFromEnv(nil)
+	if err != nil || transport == nil || transport.Mode != RecordModeReplay || transport.Dir != dir {
+		t.Errorf("环境变量配置未生效: %+v, %v", transport, err)
+	}
+}
+
+func TestRecordingTransportRequestBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write([]byte("echo:" + string(body)))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	post := func(client *http.Client, body string) string {
+		req, _ := http.NewRequest("POST", server.URL+"/cwe/search", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("请求失败: %v", err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return string(data)
+	}
+
+	recorder := &http.Client{Transport: NewRecordingTransport(dir, RecordModeRecord, nil)}
+	if got := post(recorder, "a"); got != "echo:a" {
+		t.Errorf("录制时请求体应原样发送，实际响应为%q", got)
+	}
+	post(recorder, "b")
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 2 {
+		t.Fatalf("不同请求体应分别录制，实际有%d个录制文件", len(files))
+	}
+	data, _ := os.ReadFile(files[0])
+	if !strings.Contains(string(data), `"request_body"`) || strings.Contains(string(data), "secret") {
+		t.Errorf("录制文件应包含请求体且不包含被清除的请求头: %s", data)
+	}
+
+	server.Close()
+	replayer := &http.Client{Transport: NewRecordingTransport(dir, RecordModeReplay, nil)}
+	if got := post(replayer, "b"); got != "echo:b" {
+		t.Errorf("应回放请求体对应的响应，实际为%q", got)
+	}
+}
+
+func TestRecordingTransportScrubsNVDAPIKey(t *testing.T) {
+	server := setupNVDServer()
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := newNVDTestClient(server.URL)
+	client.SetAPIKey("secret")
+	for _, option := range []ClientOption{
+		WithAPIKey("X-Gateway-Key", "gateway-credential"),
+		WithDefaultHeaders(map[string]string{"X-Session-Token": "session-credential"}),
+		WithRecorder(dir, RecordModeRecord),
+	} {
+		option(client.GetHTTPClient())
+	}
+
+	ids, err := client.GetCVEWeaknesses("CVE-2020-0002")
+	if err != nil || len(ids) != 1 {
+		t.Fatalf("录制NVD请求失败: %v, %v", ids, err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("期望1个录制文件，但得到 %d 个", len(files))
+	}
+	data, _ := os.ReadFile(files[0])
+	for _, secret := range []string{`"secret"`, "gateway-credential", "session-credential"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("录制文件中不应包含密钥%s: %s", secret, data)
+		}
+	}
+}
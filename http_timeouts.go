@@ -0,0 +1,186 @@
+package cwe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// WithDialTimeout 设置建立TCP连接的超时时间
+//
+// 与WithProxy相同，只能修改*http.Transport，应在WithRecorder之前使用。d<=0时不做任何修改。
+func WithDialTimeout(d time.Duration) ClientOption {
+	return func(c *HTTPClient) {
+		if d <= 0 {
+			return
+		}
+		c.configureTransport(func(transport *http.Transport) {
+			transport.DialContext = (&net.Dialer{
+				Timeout:   d,
+				KeepAlive: 30 * time.Second,
+			}).DialContext
+		})
+	}
+}
+
+// WithTLSHandshakeTimeout 设置TLS握手的超时时间
+//
+// 与WithProxy相同，只能修改*http.Transport，应在WithRecorder之前使用。d<=0时不做任何修改。
+func WithTLSHandshakeTimeout(d time.Duration) ClientOption {
+	return func(c *HTTPClient) {
+		if d <= 0 {
+			return
+		}
+		c.configureTransport(func(transport *http.Transport) {
+			transport.TLSHandshakeTimeout = d
+		})
+	}
+}
+
+// WithResponseHeaderTimeout 设置发送完请求后等待响应头的超时时间，不包括读取响应体的时间
+//
+// 与WithProxy相同，只能修改*http.Transport，应在WithRecorder之前使用。d<=0时不做任何修改。
+func WithResponseHeaderTimeout(d time.Duration) ClientOption {
+	return func(c *HTTPClient) {
+		if d <= 0 {
+			return
+		}
+		c.configureTransport(func(transport *http.Transport) {
+			transport.ResponseHeaderTimeout = d
+		})
+	}
+}
+
+// WithAttemptTimeout 设置单次尝试的超时时间，见SetAttemptTimeout
+func WithAttemptTimeout(d time.Duration) ClientOption {
+	return func(c *HTTPClient) {
+		c.SetAttemptTimeout(d)
+	}
+}
+
+// WithOverallTimeout 设置包括所有重试在内的总超时时间，见SetOverallTimeout
+func WithOverallTimeout(d time.Duration) ClientOption {
+	return func(c *HTTPClient) {
+		c.SetOverallTimeout(d)
+	}
+}
+
+// SetAttemptTimeout 设置单次尝试的超时时间
+//
+// 方法功能:
+// 每次尝试(第一次请求或一次重试)从发送请求到读完响应体都必须在d内完成，超时的尝试按网络错误重试。
+// 设置后取代底层http.Client的Timeout(默认30秒)，底层客户端会被复制并去掉Timeout，调用方共享的客户端不受影响；
+// 之后调用SetClient替换的客户端仍使用自己的Timeout。
+// 单次尝试超时不限制重试的次数和间隔，最坏情况下的总耗时约为(d + 重试间隔) × (最大重试次数 + 1)，
+// 需要确定的上限时同时使用SetOverallTimeout。
+//
+// 参数:
+// - d: time.Duration - 超时时间，<=0表示不设置单次尝试超时，底层http.Client的Timeout保持不变
+func (c *HTTPClient) SetAttemptTimeout(d time.Duration) {
+	if d <= 0 {
+		c.attemptTimeout = 0
+		return
+	}
+	c.attemptTimeout = d
+	if c.client.Timeout != 0 {
+		clientCopy := *c.client
+		clientCopy.Timeout = 0
+		c.client = &clientCopy
+	}
+}
+
+// GetAttemptTimeout 返回单次尝试的超时时间，0表示未设置
+func (c *HTTPClient) GetAttemptTimeout() time.Duration {
+	return c.attemptTimeout
+}
+
+// SetOverallTimeout 设置包括所有重试在内的总超时时间
+//
+// 方法功能:
+// 从调用Get、Do等方法开始计时，速率限制的等待、服务器限流的等待、重试间隔和每次尝试都计入总时间，
+// 超时后正在进行的请求被取消且不再重试，返回的错误包装了context.DeadlineExceeded。
+// 成功返回的响应体仍受总超时限制，需要在剩余时间内读完。
+// 与调用方通过上下文设置的截止时间同时生效，以较早者为准。
+//
+// 参数:
+// - d: time.Duration - 超时时间，<=0表示不限制
+//
+// 使用示例:
+// ```go
+// client := cwe.NewAPIClient(
+//
+//	cwe.WithDialTimeout(5*time.Second),
+//	cwe.WithTLSHandshakeTimeout(5*time.Second),
+//	cwe.WithResponseHeaderTimeout(10*time.Second),
+//	cwe.WithAttemptTimeout(20*time.Second),
+//	cwe.WithOverallTimeout(time.Minute), // 无论重试多少次，最多等待1分钟
+//
+// )
+// ```
+func (c *HTTPClient) SetOverallTimeout(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	c.overallTimeout = d
+}
+
+// GetOverallTimeout 返回包括所有重试在内的总超时时间，0表示未设置
+func (c *HTTPClient) GetOverallTimeout() time.Duration {
+	return c.overallTimeout
+}
+
+// overallContext 返回受总超时限制的上下文，未设置总超时时返回ctx本身
+func (c *HTTPClient) overallContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.overallTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.overallTimeout)
+}
+
+// attemptContext 返回受单次尝试超时限制的上下文，未设置时返回ctx本身
+func (c *HTTPClient) attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.attemptTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.attemptTimeout)
+}
+
+// deadlineError 在设置了总超时且err由截止时间引起时补充说明，其他错误原样返回
+func (c *HTTPClient) deadlineError(err error) error {
+	if c.overallTimeout > 0 && errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("请求在总超时时间%v内未完成: %w", c.overallTimeout, err)
+	}
+	return err
+}
+
+// sleepContext 等待d，ctx被取消时提前返回其错误
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// cancelOnCloseBody 在关闭响应体时释放请求使用的上下文
+// 上下文必须在响应体读完之后才能取消，否则读取会失败
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
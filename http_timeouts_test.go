@@ -0,0 +1,91 @@
+package cwe
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPClientAttemptTimeout(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 第一次请求超过单次尝试超时，重试时立即返回
+		if atomic.AddInt32(&requests, 1) == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := NewHttpClient(
+		WithAttemptTimeout(300*time.Millisecond),
+		WithMaxRetries(2),
+		WithRetryInterval(time.Millisecond),
+		WithRateLimit(1000),
+	)
+	if client.GetAttemptTimeout() != 300*time.Millisecond {
+		t.Fatalf("GetAttemptTimeout = %v", client.GetAttemptTimeout())
+	}
+	if client.client.Timeout != 0 {
+		t.Errorf("设置单次尝试超时后底层客户端不应再有Timeout，实际为%v", client.client.Timeout)
+	}
+
+	resp, err := client.Get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("超时的尝试应被重试，实际: %v", err)
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || string(data) != "ok" {
+		t.Errorf("响应体 = %q, %v", data, err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("服务器应收到2个请求，实际为%d", got)
+	}
+}
+
+func TestHTTPClientOverallTimeout(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewHttpClient(
+		WithOverallTimeout(100*time.Millisecond),
+		WithMaxRetries(10),
+		WithRetryInterval(40*time.Millisecond),
+		WithRateLimit(1000),
+	)
+
+	start := time.Now()
+	_, err := client.GetSimple(server.URL)
+	elapsed := time.Since(start)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("应返回context.DeadlineExceeded，实际: %v", err)
+	}
+	if elapsed > 500*time.Millisecond {
+		t.Errorf("总耗时应受总超时限制，实际为%v", elapsed)
+	}
+	if got := atomic.LoadInt32(&requests); got >= 11 {
+		t.Errorf("总超时后不应继续重试，服务器收到%d个请求", got)
+	}
+
+	// 调用方的上下文更早取消时以调用方为准
+	client.SetOverallTimeout(time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.Get(ctx, server.URL); !errors.Is(err, context.Canceled) {
+		t.Errorf("上下文已取消时应返回context.Canceled，实际: %v", err)
+	}
+}
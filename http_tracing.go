@@ -0,0 +1,132 @@
+package cwe
+
+import (
+	"context"
+	"net/http"
+)
+
+// TracerName 是HTTP客户端和数据获取器向TracerProvider请求Tracer时使用的名称
+const TracerName = "github.com/scagogogo/cwe"
+
+// TracerProvider 创建用于记录span的Tracer
+//
+// 接口的形状与OpenTelemetry的trace.TracerProvider一致，但不依赖OpenTelemetry，
+// 使用OpenTelemetry时只需要一个很薄的适配器：
+//
+// ```go
+// type otelProvider struct{ tp trace.TracerProvider }
+//
+// func (p otelProvider) Tracer(name string) cwe.Tracer { return otelTracer{p.tp.Tracer(name)} }
+//
+// type otelTracer struct{ t trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string) (context.Context, cwe.Span) {
+//	    ctx, span := t.t.Start(ctx, name)
+//	    return ctx, otelSpan{span}
+//	}
+//
+// type otelSpan struct{ trace.Span }
+//
+//	func (s otelSpan) SetAttributes(attrs ...cwe.SpanAttribute) {
+//	    for _, attr := range attrs {
+//	        s.Span.SetAttributes(attribute.String(attr.Key, fmt.Sprint(attr.Value)))
+//	    }
+//	}
+//
+// func (s otelSpan) RecordError(err error) { s.Span.RecordError(err); s.Span.SetStatus(codes.Error, err.Error()) }
+// func (s otelSpan) End()                  { s.Span.End() }
+// ```
+type TracerProvider interface {
+	// Tracer 返回指定名称的Tracer，name为TracerName
+	Tracer(name string) Tracer
+}
+
+// Tracer 创建span
+type Tracer interface {
+	// Start 以ctx中的span为父span创建新的span，返回携带新span的上下文
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+// Span 表示一段被追踪的操作，实现必须是并发安全的
+type Span interface {
+	// SetAttributes 设置span的属性
+	SetAttributes(attrs ...SpanAttribute)
+
+	// RecordError 记录操作失败的错误
+	RecordError(err error)
+
+	// End 结束span
+	End()
+}
+
+// SpanAttribute 是span的一个属性，Value为string、int、int64或bool
+type SpanAttribute struct {
+	Key   string
+	Value interface{}
+}
+
+// WithTracerProvider 设置HTTP客户端的TracerProvider，见SetTracerProvider
+func WithTracerProvider(provider TracerProvider) ClientOption {
+	return func(c *HTTPClient) {
+		c.SetTracerProvider(provider)
+	}
+}
+
+// SetTracerProvider 设置HTTP客户端的TracerProvider，传入nil关闭追踪
+//
+// 方法功能:
+// 设置后每一次实际发送的请求(包括每次重试)都会创建一个名为"HTTP GET"等的span，
+// 以请求上下文中的span为父span，属性包括http.method、http.url、cwe.endpoint和http.status_code，
+// 请求出错时记录错误。span覆盖从发送请求到收到响应头的时间，不包括速率限制的等待和读取响应体。
+// 拦截器在span的上下文中执行，可以从req.Context()中取出span注入追踪头部。
+//
+// 使用这个客户端的DataFetcher未单独设置TracerProvider时也使用它，见DataFetcher.SetTracerProvider。
+// 应在发送请求前调用
+//
+// 使用示例:
+// ```go
+// client := cwe.NewAPIClient(cwe.WithTracerProvider(otelProvider{otel.GetTracerProvider()}))
+// fetcher := cwe.NewDataFetcherWithClient(client)
+//
+// registry, err := fetcher.BuildCWETreeWithView("1000")
+// ```
+func (c *HTTPClient) SetTracerProvider(provider TracerProvider) {
+	c.tracerProvider = provider
+}
+
+// GetTracerProvider 获取HTTP客户端的TracerProvider，未设置时返回nil
+func (c *HTTPClient) GetTracerProvider() TracerProvider {
+	return c.tracerProvider
+}
+
+// SetTracerProvider 设置API客户端的TracerProvider，等同于GetHTTPClient().SetTracerProvider(provider)
+func (c *APIClient) SetTracerProvider(provider TracerProvider) {
+	c.client.SetTracerProvider(provider)
+}
+
+// tracingRoundTrip 为每个请求创建span，未设置TracerProvider时直接返回next
+func (c *HTTPClient) tracingRoundTrip(next RoundTripFunc) RoundTripFunc {
+	provider := c.tracerProvider
+	if provider == nil {
+		return next
+	}
+
+	return func(req *http.Request) (*http.Response, error) {
+		ctx, span := provider.Tracer(TracerName).Start(req.Context(), "HTTP "+req.Method)
+		defer span.End()
+
+		span.SetAttributes(
+			SpanAttribute{Key: "http.method", Value: req.Method},
+			SpanAttribute{Key: "http.url", Value: req.URL.String()},
+			SpanAttribute{Key: "cwe.endpoint", Value: metricsEndpoint(req.URL.Path)},
+		)
+
+		resp, err := next(req.WithContext(ctx))
+		if err != nil {
+			span.RecordError(err)
+			return resp, err
+		}
+		span.SetAttributes(SpanAttribute{Key: "http.status_code", Value: resp.StatusCode})
+		return resp, nil
+	}
+}
@@ -0,0 +1,188 @@
+package cwe
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testTracer 是记录所有span的TracerProvider，用于断言
+type testTracer struct {
+	mutex sync.Mutex
+	spans []*testSpan
+}
+
+type testSpanKey struct{}
+
+type testSpan struct {
+	tracer *testTracer
+	name   string
+	parent *testSpan
+	attrs  map[string]interface{}
+	errs   []error
+	ended  bool
+}
+
+func (t *testTracer) Tracer(name string) Tracer {
+	return t
+}
+
+func (t *testTracer) Start(ctx context.Context, spanName string) (context.Context, Span) {
+	parent, _ := ctx.Value(testSpanKey{}).(*testSpan)
+	span := &testSpan{tracer: t, name: spanName, parent: parent, attrs: make(map[string]interface{})}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, testSpanKey{}, span), span
+}
+
+func (t *testTracer) find(name string) []*testSpan {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var spans []*testSpan
+	for _, span := range t.spans {
+		if span.name == name {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+func (s *testSpan) SetAttributes(attrs ...SpanAttribute) {
+	s.tracer.mutex.Lock()
+	defer s.tracer.mutex.Unlock()
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *testSpan) RecordError(err error) {
+	s.tracer.mutex.Lock()
+	defer s.tracer.mutex.Unlock()
+	s.errs = append(s.errs, err)
+}
+
+func (s *testSpan) End() {
+	s.tracer.mutex.Lock()
+	defer s.tracer.mutex.Unlock()
+	s.ended = true
+}
+
+func TestHTTPClientTracing(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	tracer := &testTracer{}
+	client := NewHttpClient(WithTracerProvider(tracer), WithRateLimit(1000), WithRetryInterval(time.Millisecond))
+	if client.GetTracerProvider() != tracer {
+		t.Fatal("GetTracerProvider应返回设置的TracerProvider")
+	}
+
+	var sawSpan bool
+	client.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			_, sawSpan = req.Context().Value(testSpanKey{}).(*testSpan)
+			return next(req)
+		}
+	})
+
+	parentCtx, parent := tracer.Start(context.Background(), "parent")
+	resp, err := client.Get(parentCtx, server.URL+"/cwe/weakness/79")
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+
+	spans := tracer.find("HTTP GET")
+	if len(spans) != 2 {
+		t.Fatalf("每次尝试应创建一个span，实际为%d个", len(spans))
+	}
+	for i, span := range spans {
+		if !span.ended || span.parent != parent {
+			t.Errorf("span %d应已结束且以请求上下文中的span为父span", i)
+		}
+		if span.attrs["cwe.endpoint"] != "/cwe/weakness/{id}" || span.attrs["http.method"] != "GET" {
+			t.Errorf("span %d的属性不正确: %v", i, span.attrs)
+		}
+	}
+	if spans[0].attrs["http.status_code"] != http.StatusBadGateway || spans[1].attrs["http.status_code"] != http.StatusOK {
+		t.Errorf("状态码属性不正确: %v, %v", spans[0].attrs, spans[1].attrs)
+	}
+	if !sawSpan {
+		t.Error("拦截器应在span的上下文中执行")
+	}
+
+	// 请求出错时记录错误
+	server.Close()
+	client.SetMaxRetries(0)
+	if _, err := client.GetSimple(server.URL); err == nil {
+		t.Fatal("服务器关闭后请求应失败")
+	}
+	spans = tracer.find("HTTP GET")
+	if last := spans[len(spans)-1]; len(last.errs) != 1 {
+		t.Errorf("请求出错时应记录错误，实际为%v", last.errs)
+	}
+}
+
+func TestDataFetcherTracing(t *testing.T) {
+	server := setupLoggerTreeServer()
+	defer server.Close()
+
+	tracer := &testTracer{}
+	client := NewAPIClientWithOptions(server.URL, DefaultTimeout, NewHTTPRateLimiter(0))
+	client.GetHTTPClient().SetRetryDelay(time.Millisecond)
+	client.GetHTTPClient().SetMaxRetries(0)
+	client.SetTracerProvider(tracer)
+	fetcher := NewDataFetcherWithClient(client)
+
+	if _, err := fetcher.BuildCWETreeWithView("1000"); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	builds := tracer.find("cwe.BuildCWETree")
+	if len(builds) != 1 {
+		t.Fatalf("应创建1个树构建span，实际为%d个", len(builds))
+	}
+	build := builds[0]
+	if !build.ended || build.attrs["cwe.view"] != "CWE-1000" || build.attrs["cwe.resumed"] != false {
+		t.Errorf("树构建span的属性不正确: %+v", build)
+	}
+	if build.attrs["cwe.nodes"] != 3 || build.attrs["cwe.root_children"] != 1 {
+		t.Errorf("树构建span应记录节点数量，实际为%v", build.attrs)
+	}
+	if len(tracer.find("HTTP GET")) == 0 {
+		t.Error("未单独设置时数据获取器应与API客户端共用TracerProvider")
+	}
+
+	// 数据获取器可以单独设置TracerProvider
+	own := &testTracer{}
+	fetcher.SetTracerProvider(own)
+	if _, err := fetcher.BuildCWETreeResumable("1000", nil, ResumableBuildOptions{}); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if len(own.find("cwe.BuildCWETree")) != 1 || len(own.find("HTTP GET")) != 0 {
+		t.Error("树构建span应使用数据获取器自己的TracerProvider")
+	}
+
+	// 构建失败时记录错误
+	if _, err := fetcher.BuildCWETreeWithView("2000"); err == nil {
+		t.Fatal("视图不存在时构建应失败")
+	}
+	failed := own.find("cwe.BuildCWETree")
+	if last := failed[len(failed)-1]; len(last.errs) != 1 || last.attrs["cwe.nodes"] != nil {
+		t.Errorf("构建失败时应只记录错误，实际为%+v", last)
+	}
+}
@@ -0,0 +1,92 @@
+package cwe
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestWithProxy(t *testing.T) {
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"version": "4.16"}`))
+	}))
+	defer proxy.Close()
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	client := NewAPIClientWithOptions("http://cwe-api.invalid/api/v1", 0, NewHTTPRateLimiter(0))
+	client.ApplyOptions(WithProxy(proxyURL))
+
+	version, err := client.GetVersion()
+	if err != nil {
+		t.Fatalf("通过代理请求失败: %v", err)
+	}
+	if version.Version != "4.16" || proxiedHost != "cwe-api.invalid" {
+		t.Errorf("请求应发送到代理，代理收到的主机为%q", proxiedHost)
+	}
+}
+
+func TestWithTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := NewHttpClient(WithMaxRetries(1), WithRetryInterval(1))
+	client.SetRateLimiter(NewHTTPRateLimiter(0))
+	if _, err := client.GetSimple(server.URL); err == nil {
+		t.Fatal("未信任测试证书时请求应失败")
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	WithTLSConfig(&tls.Config{RootCAs: pool})(client)
+
+	resp, err := client.GetSimple(server.URL)
+	if err != nil {
+		t.Fatalf("信任测试证书后请求应成功: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestWithTransport(t *testing.T) {
+	shared := &http.Client{}
+	client := NewHttpClient()
+	client.SetClient(shared)
+	client.SetRateLimiter(NewHTTPRateLimiter(0))
+
+	WithTransport(RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader("stub")),
+			Request:    req,
+		}, nil
+	}))(client)
+
+	resp, err := client.GetSimple("http://cwe-api.invalid/")
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "stub" {
+		t.Errorf("应使用自定义Transport，实际响应为%q", body)
+	}
+	if shared.Transport != nil {
+		t.Error("不应修改调用方共享的http.Client")
+	}
+
+	// 自定义RoundTripper不是*http.Transport，WithProxy不做修改
+	WithProxy(nil)(client)
+	if _, ok := client.GetClient().Transport.(RoundTripFunc); !ok {
+		t.Error("WithProxy不应替换非*http.Transport的Transport")
+	}
+}
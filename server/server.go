@@ -0,0 +1,363 @@
+// Package server 提供由本地Registry支撑的CWE REST API服务
+//
+// 服务的路径和响应格式与MITRE CWE REST API保持一致，cwe.APIClient可以直接指向它，
+// 用于在无法访问外网的环境中部署内部镜像，或在测试中代替真实的API。
+//
+// 支持的接口(均为GET):
+//
+//	/cwe/version                 内容版本
+//	/cwe/{id[,id...]}            一个或多个条目
+//	/cwe/weakness/{id}           弱点
+//	/cwe/category/{id}           类别
+//	/cwe/view/{id}               视图
+//	/cwe/{id}/parents            父节点
+//	/cwe/{id}/children           子节点
+//	/cwe/{id}/ancestors          所有祖先节点，从近到远
+//	/cwe/{id}/descendants        所有后代节点，广度优先
+//	/search?q=&limit=&offset=&cursor=  关键词搜索(MITRE API没有此接口)
+//
+// 注册表不记录条目的类型，因此任何条目都可以通过weakness、category和view接口获取。
+// 子节点等关系接口接受view参数以兼容客户端，但结果不按视图过滤。
+//
+// 使用示例:
+//
+//	registry, _ := cwe.NewOfflineRegistry()
+//	srv := server.New(registry)
+//	log.Fatal(http.ListenAndServe(":8080", srv))
+//
+//	// 客户端
+//	client := cwe.NewAPIClientWithOptions("http://localhost:8080", cwe.DefaultTimeout)
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/scagogogo/cwe"
+)
+
+// Options 控制服务的行为
+type Options struct {
+	// Prefix 所有接口的路径前缀，如"/api/v1"，为空时接口直接位于根路径下
+	Prefix string
+
+	// Version /cwe/version返回的内容版本，为空时使用cwe.KnownCWEVersion
+	Version string
+
+	// ReleaseDate /cwe/version返回的发布日期
+	ReleaseDate string
+}
+
+// Server 是由Registry支撑的CWE REST API服务，实现了http.Handler
+//
+// Server只读取注册表，可以在多个goroutine中并发处理请求；
+// 服务期间修改注册表的层次结构需要调用方自行同步。
+type Server struct {
+	registry *cwe.Registry
+	opts     Options
+}
+
+// errorResponse 是错误响应的格式
+type errorResponse struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+// relationEntry 是parents和children接口返回的列表项，与MITRE API的格式相同
+type relationEntry struct {
+	Type          string `json:"Type"`
+	ID            string `json:"ID"`
+	ViewID        string `json:"ViewID,omitempty"`
+	PrimaryParent bool   `json:"Primary_Parent,omitempty"`
+}
+
+// SearchResponse 是/search接口的响应
+type SearchResponse struct {
+	// Total 匹配的结果总数
+	Total int `json:"total"`
+
+	// Results 本页结果
+	Results []*cwe.CWEWeakness `json:"results"`
+
+	// NextCursor 获取下一页的游标，没有更多结果时省略
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// New 创建使用默认选项的服务
+func New(registry *cwe.Registry) *Server {
+	return NewWithOptions(registry, Options{})
+}
+
+// NewWithOptions 创建服务
+//
+// 参数:
+// - registry: *cwe.Registry - 提供数据的注册表
+// - opts: Options - 服务选项
+//
+// 返回值:
+// - *Server: 可以直接传给http.ListenAndServe的服务
+func NewWithOptions(registry *cwe.Registry, opts Options) *Server {
+	opts.Prefix = strings.TrimSuffix(opts.Prefix, "/")
+	if opts.Version == "" {
+		opts.Version = cwe.KnownCWEVersion
+	}
+	return &Server{registry: registry, opts: opts}
+}
+
+// ServeHTTP 处理请求
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, "只支持GET请求")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, s.opts.Prefix)
+	if path == r.URL.Path && s.opts.Prefix != "" {
+		writeError(w, http.StatusNotFound, "未知的接口: "+r.URL.Path)
+		return
+	}
+
+	if path == "/search" {
+		s.serveSearch(w, r)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if parts[0] != "cwe" || len(parts) < 2 || len(parts) > 3 {
+		writeError(w, http.StatusNotFound, "未知的接口: "+r.URL.Path)
+		return
+	}
+
+	switch {
+	case len(parts) == 2 && parts[1] == "version":
+		writeJSON(w, cwe.VersionResponse{
+			Version:        s.opts.Version,
+			ReleaseDate:    s.opts.ReleaseDate,
+			ContentVersion: s.opts.Version,
+			ContentDate:    s.opts.ReleaseDate,
+		})
+	case len(parts) == 2:
+		s.serveEntries(w, parts[1])
+	case parts[1] == "weakness" || parts[1] == "category" || parts[1] == "view":
+		s.serveTyped(w, parts[1], parts[2])
+	default:
+		s.serveRelations(w, parts[1], parts[2])
+	}
+}
+
+// lookup 查找条目，找不到时写入错误响应并返回nil
+func (s *Server) lookup(w http.ResponseWriter, id string) *cwe.CWE {
+	normalized, err := cwe.ParseCWEID(id)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return nil
+	}
+	entry, err := s.registry.GetByID(normalized)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return nil
+	}
+	return entry
+}
+
+// serveEntries 处理/cwe/{ids}，任一ID不存在时返回404
+func (s *Server) serveEntries(w http.ResponseWriter, ids string) {
+	result := make(map[string]*cwe.CWEWeakness)
+	for _, id := range strings.Split(ids, ",") {
+		entry := s.lookup(w, strings.TrimSpace(id))
+		if entry == nil {
+			return
+		}
+		result[entry.ID] = toWeakness(entry)
+	}
+	writeJSON(w, cwe.CWEsResponse{CWEs: result})
+}
+
+// serveTyped 处理/cwe/weakness/{id}、/cwe/category/{id}和/cwe/view/{id}
+func (s *Server) serveTyped(w http.ResponseWriter, kind, id string) {
+	entry := s.lookup(w, id)
+	if entry == nil {
+		return
+	}
+
+	switch kind {
+	case "weakness":
+		writeJSON(w, cwe.WeaknessResponse{Weaknesses: []*cwe.CWEWeakness{toWeakness(entry)}})
+	case "category":
+		writeJSON(w, cwe.CategoryResponse{Categories: []*cwe.CWECategory{{
+			ID:          numericID(entry.ID),
+			Name:        entry.Name,
+			Description: entry.Description,
+			URL:         entry.URL,
+			Status:      entry.Status,
+			Members:     childIDs(entry),
+		}}})
+	default:
+		members := make([]cwe.CWEViewMember, 0, len(entry.Children))
+		for _, id := range childIDs(entry) {
+			members = append(members, cwe.CWEViewMember{CweID: id, ViewID: numericID(entry.ID)})
+		}
+		writeJSON(w, cwe.ViewResponse{Views: []*cwe.CWEView{{
+			ID:          numericID(entry.ID),
+			Name:        entry.Name,
+			Description: entry.Description,
+			URL:         entry.URL,
+			Status:      entry.Status,
+			Members:     members,
+		}}})
+	}
+}
+
+// serveRelations 处理/cwe/{id}/parents、children、ancestors和descendants
+func (s *Server) serveRelations(w http.ResponseWriter, id, relation string) {
+	entry := s.lookup(w, id)
+	if entry == nil {
+		return
+	}
+
+	switch relation {
+	case "parents":
+		parents, err := s.registry.Parents(entry.ID)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		result := make([]relationEntry, 0, len(parents))
+		for i, parent := range parents {
+			result = append(result, relationEntry{Type: "weakness", ID: numericID(parent.ID), PrimaryParent: i == 0})
+		}
+		writeJSON(w, result)
+	case "children":
+		result := make([]relationEntry, 0, len(entry.Children))
+		for _, id := range childIDs(entry) {
+			result = append(result, relationEntry{Type: "weakness", ID: id})
+		}
+		writeJSON(w, result)
+	case "ancestors":
+		ancestors, err := s.registry.GetAncestors(entry.ID)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeJSON(w, entryIDs(ancestors))
+	case "descendants":
+		descendants, err := s.registry.GetDescendants(entry.ID, 0)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeJSON(w, entryIDs(descendants))
+	default:
+		writeError(w, http.StatusNotFound, "未知的接口: "+relation)
+	}
+}
+
+// entryIDs 返回条目的ID列表
+func entryIDs(entries []*cwe.CWE) []string {
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+	}
+	return ids
+}
+
+// serveSearch 处理/search
+func (s *Server) serveSearch(w http.ResponseWriter, r *http.Request) {
+	query := cwe.SearchQuery{
+		Keyword: r.URL.Query().Get("q"),
+		Cursor:  r.URL.Query().Get("cursor"),
+	}
+	for name, target := range map[string]*int{"limit": &query.Limit, "offset": &query.Offset} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "无效的"+name+"参数: "+value)
+			return
+		}
+		*target = n
+	}
+
+	page, err := s.registry.Search(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := SearchResponse{
+		Total:      page.Total,
+		Results:    make([]*cwe.CWEWeakness, 0, len(page.Results)),
+		NextCursor: page.NextCursor,
+	}
+	for _, entry := range page.Results {
+		response.Results = append(response.Results, toWeakness(entry))
+	}
+	writeJSON(w, response)
+}
+
+// toWeakness 将条目转换为API响应中的弱点，ID与MITRE API一样不带"CWE-"前缀
+func toWeakness(entry *cwe.CWE) *cwe.CWEWeakness {
+	weakness := &cwe.CWEWeakness{
+		ID:                numericID(entry.ID),
+		Name:              entry.Name,
+		Description:       entry.Description,
+		URL:               entry.URL,
+		Severity:          entry.Severity,
+		Status:            entry.Status,
+		Abstraction:       entry.Abstraction,
+		RelatedWeaknesses: entry.Relations,
+		MappingNotes:      entry.MappingNotes,
+	}
+	weakness.Mitigations = entry.TypedMitigations()
+	weakness.ObservedExamples = entry.TypedObservedExamples()
+	for _, term := range entry.AlternateTerms {
+		weakness.AlternateTerms = append(weakness.AlternateTerms, cwe.CWEAlternateTerm{Term: term})
+	}
+	return weakness
+}
+
+// childIDs 返回子节点的数字ID，按CWE编号排序
+func childIDs(entry *cwe.CWE) []string {
+	children := make([]*cwe.CWE, len(entry.Children))
+	copy(children, entry.Children)
+	sort.SliceStable(children, func(i, j int) bool {
+		a, _ := children[i].GetNumericID()
+		b, _ := children[j].GetNumericID()
+		return a < b
+	})
+
+	ids := make([]string, 0, len(children))
+	for _, child := range children {
+		ids = append(ids, numericID(child.ID))
+	}
+	return ids
+}
+
+// numericID 去掉ID的"CWE-"前缀
+func numericID(id string) string {
+	return strings.TrimPrefix(id, "CWE-")
+}
+
+// writeJSON 以200状态码写入JSON响应
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// writeError 写入JSON格式的错误响应
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{Status: status, Message: message})
+}
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scagogogo/cwe"
+)
+
+// newTestRegistry 创建CWE-1000 -> {CWE-20 -> {CWE-79, CWE-89}, CWE-74 -> CWE-89}的多父节点注册表
+func newTestRegistry() *cwe.Registry {
+	registry := cwe.NewRegistry()
+	registry.SetHierarchyMode(cwe.HierarchyMultiParent)
+	for _, entry := range []*cwe.CWE{
+		cwe.NewCWE("CWE-1000", "Research Concepts"),
+		cwe.NewCWE("CWE-20", "Improper Input Validation"),
+		cwe.NewCWE("CWE-74", "Injection"),
+		cwe.NewCWE("CWE-79", "Cross-site Scripting"),
+		cwe.NewCWE("CWE-89", "SQL Injection"),
+	} {
+		registry.Register(entry)
+	}
+	xss := registry.Entries["CWE-79"]
+	xss.Severity = "High"
+	xss.Mitigations = []string{"对输出进行编码"}
+	xss.Relations = []cwe.CWERelation{{Nature: "ChildOf", CweID: "CWE-20", ViewID: "CWE-1000"}}
+
+	registry.AddChild("CWE-1000", "CWE-20")
+	registry.AddChild("CWE-1000", "CWE-74")
+	registry.AddChild("CWE-20", "CWE-89")
+	registry.AddChild("CWE-20", "CWE-79")
+	registry.AddChild("CWE-74", "CWE-89")
+	registry.Root = registry.Entries["CWE-1000"]
+	return registry
+}
+
+// newTestClient 创建指向测试服务的APIClient
+func newTestClient(t *testing.T, srv http.Handler) *cwe.APIClient {
+	t.Helper()
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	client := cwe.NewAPIClientWithOptions(ts.URL, cwe.DefaultTimeout, cwe.NewHTTPRateLimiter(0))
+	client.GetHTTPClient().SetMaxRetries(1)
+	client.GetHTTPClient().SetRetryDelay(time.Millisecond)
+	return client
+}
+
+func TestServerWithAPIClient(t *testing.T) {
+	client := newTestClient(t, NewWithOptions(newTestRegistry(), Options{Version: "4.16", ReleaseDate: "2024-11-19"}))
+
+	version, err := client.GetVersion()
+	if err != nil || version.Version != "4.16" || version.ReleaseDate != "2024-11-19" {
+		t.Errorf("版本不正确: %+v, %v", version, err)
+	}
+
+	weakness, err := client.GetWeakness("CWE-79")
+	if err != nil {
+		t.Fatalf("GetWeakness失败: %v", err)
+	}
+	if weakness.ID != "79" || weakness.Severity != "High" || len(weakness.Mitigations) != 1 || len(weakness.RelatedWeaknesses) != 1 {
+		t.Errorf("弱点不正确: %+v", weakness)
+	}
+
+	cwes, err := client.GetCWEs([]string{"79", "89"})
+	if err != nil || len(cwes) != 2 || cwes["CWE-89"].Name != "SQL Injection" {
+		t.Errorf("GetCWEs结果不正确: %v, %v", cwes, err)
+	}
+
+	children, err := client.GetChildren("20", "1000")
+	if err != nil || !reflect.DeepEqual(children, []string{"79", "89"}) {
+		t.Errorf("子节点不正确: %v, %v", children, err)
+	}
+	parents, err := client.GetParents("89", "")
+	if err != nil || !reflect.DeepEqual(parents, []string{"20", "74"}) {
+		t.Errorf("父节点不正确: %v, %v", parents, err)
+	}
+	ancestors, err := client.GetAncestors("89", "")
+	if err != nil || !reflect.DeepEqual(ancestors, []string{"CWE-20", "CWE-74", "CWE-1000"}) {
+		t.Errorf("祖先节点不正确: %v, %v", ancestors, err)
+	}
+	descendants, err := client.GetDescendants("1000", "")
+	if err != nil || len(descendants) != 4 {
+		t.Errorf("后代节点不正确: %v, %v", descendants, err)
+	}
+
+	if _, err := client.GetWeakness("CWE-99999"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("不存在的条目应返回404，实际为%v", err)
+	}
+}
+
+func TestServerBuildTree(t *testing.T) {
+	client := newTestClient(t, New(newTestRegistry()))
+	fetcher := cwe.NewDataFetcherWithClient(client)
+
+	registry, err := fetcher.BuildCWETreeWithView("1000")
+	if err != nil {
+		t.Fatalf("从镜像构建树失败: %v", err)
+	}
+	if len(registry.Entries) != 5 || len(registry.Root.Children) != 2 {
+		t.Errorf("构建的树不正确: %d个条目", len(registry.Entries))
+	}
+}
+
+func TestServerSearch(t *testing.T) {
+	ts := httptest.NewServer(New(newTestRegistry()))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/search?q=injection&limit=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var result SearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 2 || len(result.Results) != 1 || result.Results[0].ID != "74" || result.NextCursor == "" {
+		t.Errorf("搜索结果不正确: %+v", result)
+	}
+
+	resp, err = http.Get(ts.URL + "/search?limit=x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("无效参数应返回400，实际为%d", resp.StatusCode)
+	}
+}
+
+func TestServerSearchWithDataFetcher(t *testing.T) {
+	registry := newTestRegistry()
+	registry.Entries["CWE-79"].AlternateTerms = []string{"XSS"}
+	fetcher := cwe.NewDataFetcherWithClient(newTestClient(t, New(registry)))
+	fetcher.SetSearchRegistry(cwe.NewRegistry())
+
+	results, err := fetcher.Search("xss")
+	if err != nil {
+		t.Fatalf("Search失败: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "CWE-79" || !reflect.DeepEqual(results[0].AlternateTerms, []string{"XSS"}) {
+		t.Errorf("应使用服务器端搜索，实际为%+v", results)
+	}
+}
+
+func TestServerErrorsAndPrefix(t *testing.T) {
+	ts := httptest.NewServer(NewWithOptions(newTestRegistry(), Options{Prefix: "/api/v1/"}))
+	defer ts.Close()
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+	}{
+		{http.MethodGet, "/api/v1/cwe/weakness/79", http.StatusOK},
+		{http.MethodGet, "/cwe/weakness/79", http.StatusNotFound},
+		{http.MethodGet, "/api/v1/cwe/weakness/abc", http.StatusBadRequest},
+		{http.MethodGet, "/api/v1/cwe/79/siblings", http.StatusNotFound},
+		{http.MethodGet, "/api/v1/other", http.StatusNotFound},
+		{http.MethodPost, "/api/v1/cwe/weakness/79", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(tt.method, ts.URL+tt.path, nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s %s应返回%d，实际为%d", tt.method, tt.path, tt.status, resp.StatusCode)
+		}
+	}
+}